@@ -71,7 +71,7 @@ func action(ctx *cli.Context) error {
 	log.Info("init redis client", "addr", opts.Addr, "user name", opts.Username, "is local", cfg.Redis.Local,
 		"min idle connections", opts.MinIdleConns, "read timeout", opts.ReadTimeout)
 	redisClient := redis.NewClient(opts)
-	api.InitController(db, redisClient)
+	api.InitController(db, redisClient, cfg.Retention)
 
 	router := gin.Default()
 	registry := prometheus.DefaultRegisterer
@@ -42,12 +42,22 @@ type RedisConfig struct {
 	ReadTimeoutMs int    `json:"readTimeoutMs"`
 }
 
+// RetentionConfig is the configuration of the bridge history data retention.
+type RetentionConfig struct {
+	AdminToken      string `json:"adminToken"`      // The retention API is disabled if empty.
+	L1MessageMonths uint64 `json:"l1MessageMonths"` // Default retention of L1 sent messages, 0 means keeping forever.
+	L2MessageMonths uint64 `json:"l2MessageMonths"` // Default retention of L2 sent messages, 0 means keeping forever.
+	ExportDir       string `json:"exportDir"`       // Records are exported to this directory before deletion.
+	BatchSize       int    `json:"batchSize"`
+}
+
 // Config is the configuration of the bridge history backend
 type Config struct {
-	L1    *FetcherConfig   `json:"L1"`
-	L2    *FetcherConfig   `json:"L2"`
-	DB    *database.Config `json:"db"`
-	Redis *RedisConfig     `json:"redis"`
+	L1        *FetcherConfig   `json:"L1"`
+	L2        *FetcherConfig   `json:"L2"`
+	DB        *database.Config `json:"db"`
+	Redis     *RedisConfig     `json:"redis"`
+	Retention *RetentionConfig `json:"retention"`
 }
 
 // NewConfig returns a new instance of Config.
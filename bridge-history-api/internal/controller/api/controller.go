@@ -5,18 +5,25 @@ import (
 
 	"github.com/go-redis/redis/v8"
 	"gorm.io/gorm"
+
+	"scroll-tech/bridge-history-api/internal/config"
 )
 
 var (
 	// HistoryCtrler is controller instance
 	HistoryCtrler *HistoryController
+	// RetentionCtrler is controller instance, nil if the retention api is disabled
+	RetentionCtrler *RetentionController
 
 	initControllerOnce sync.Once
 )
 
 // InitController inits Controller with database
-func InitController(db *gorm.DB, redis *redis.Client) {
+func InitController(db *gorm.DB, redis *redis.Client, retentionCfg *config.RetentionConfig) {
 	initControllerOnce.Do(func() {
 		HistoryCtrler = NewHistoryController(db, redis)
+		if retentionCfg != nil && retentionCfg.AdminToken != "" {
+			RetentionCtrler = NewRetentionController(db, retentionCfg)
+		}
 	})
 }
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"crypto/subtle"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"scroll-tech/bridge-history-api/internal/config"
+	"scroll-tech/bridge-history-api/internal/logic"
+	"scroll-tech/bridge-history-api/internal/types"
+)
+
+// RetentionController contains the bridge history data retention service
+type RetentionController struct {
+	retentionLogic *logic.RetentionLogic
+	adminToken     string
+
+	// only one retention task runs at a time.
+	mu sync.Mutex
+}
+
+// NewRetentionController return RetentionController instance
+func NewRetentionController(db *gorm.DB, cfg *config.RetentionConfig) *RetentionController {
+	return &RetentionController{
+		retentionLogic: logic.NewRetentionLogic(db, cfg),
+		adminToken:     cfg.AdminToken,
+	}
+}
+
+// Authorize rejects the requests without the configured admin bearer token
+func (c *RetentionController) Authorize(ctx *gin.Context) {
+	token := strings.TrimPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+	if c.adminToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(c.adminToken)) != 1 {
+		types.RenderFailure(ctx, types.ErrUnauthorized, errors.New("invalid admin token"))
+		ctx.Abort()
+		return
+	}
+	ctx.Next()
+}
+
+// PostApplyRetention defines the http post method behavior
+func (c *RetentionController) PostApplyRetention(ctx *gin.Context) {
+	var req types.RetentionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		types.RenderFailure(ctx, types.ErrParameterInvalidNo, err)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result, err := c.retentionLogic.ApplyRetention(ctx, req.MessageType, req.Action, req.OlderThanMonths)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrApplyRetentionError, err)
+		return
+	}
+
+	types.RenderSuccess(ctx, result)
+}
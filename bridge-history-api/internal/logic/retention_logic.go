@@ -0,0 +1,151 @@
+package logic
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"scroll-tech/bridge-history-api/internal/config"
+	"scroll-tech/bridge-history-api/internal/orm"
+	"scroll-tech/bridge-history-api/internal/types"
+)
+
+const defaultRetentionBatchSize = 1000
+
+// RetentionLogic archives or deletes expired bridge history records.
+type RetentionLogic struct {
+	crossMessageOrm *orm.CrossMessage
+	cfg             *config.RetentionConfig
+}
+
+// NewRetentionLogic returns a new RetentionLogic instance.
+func NewRetentionLogic(db *gorm.DB, cfg *config.RetentionConfig) *RetentionLogic {
+	return &RetentionLogic{
+		crossMessageOrm: orm.NewCrossMessage(db),
+		cfg:             cfg,
+	}
+}
+
+// ApplyRetention archives or deletes the messages of the given type that are older than olderThanMonths months.
+// The configured retention of the message type is used if olderThanMonths is 0.
+// When deleting, the records are exported to a JSON lines file in the configured export dir first.
+func (r *RetentionLogic) ApplyRetention(ctx context.Context, messageType orm.MessageType, action string, olderThanMonths uint64) (*types.RetentionResult, error) {
+	if olderThanMonths == 0 {
+		olderThanMonths = r.retentionMonths(messageType)
+	}
+	if olderThanMonths == 0 {
+		return nil, fmt.Errorf("no retention configured for message type %v", messageType)
+	}
+
+	now := time.Now()
+	cutoffTimestamp := uint64(now.AddDate(0, -int(olderThanMonths), 0).Unix())
+	result := &types.RetentionResult{
+		MessageType:     messageType,
+		Action:          action,
+		CutoffTimestamp: cutoffTimestamp,
+	}
+
+	var export *os.File
+	var exportWriter *bufio.Writer
+	if action == types.RetentionActionDelete {
+		if r.cfg.ExportDir == "" {
+			return nil, errors.New("export dir is not configured, refusing to delete records without exporting them")
+		}
+		if err := os.MkdirAll(r.cfg.ExportDir, 0750); err != nil {
+			return nil, fmt.Errorf("failed to create export dir: %w", err)
+		}
+		result.ExportFile = filepath.Join(r.cfg.ExportDir, fmt.Sprintf("cross_message_type_%d_before_%d_%d.jsonl", messageType, cutoffTimestamp, now.Unix()))
+		var err error
+		export, err = os.OpenFile(filepath.Clean(result.ExportFile), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create export file: %w", err)
+		}
+		defer func() {
+			if closeErr := export.Close(); closeErr != nil {
+				log.Error("failed to close retention export file", "file", result.ExportFile, "error", closeErr)
+			}
+		}()
+		exportWriter = bufio.NewWriter(export)
+	}
+
+	var afterID uint64
+	for {
+		messages, err := r.crossMessageOrm.GetExpiredMessages(ctx, messageType, cutoffTimestamp, afterID, r.batchSize())
+		if err != nil {
+			return nil, err
+		}
+		if len(messages) == 0 {
+			break
+		}
+
+		ids := make([]uint64, len(messages))
+		for i, message := range messages {
+			ids[i] = message.ID
+		}
+		afterID = ids[len(ids)-1]
+
+		var affected int64
+		switch action {
+		case types.RetentionActionArchive:
+			affected, err = r.crossMessageOrm.ArchiveMessagesByIDs(ctx, ids)
+		case types.RetentionActionDelete:
+			if err = exportMessages(exportWriter, messages); err != nil {
+				return nil, fmt.Errorf("failed to export messages: %w", err)
+			}
+			// make sure the records are persisted in the export file before deleting them.
+			if err = exportWriter.Flush(); err != nil {
+				return nil, fmt.Errorf("failed to flush export file: %w", err)
+			}
+			if err = export.Sync(); err != nil {
+				return nil, fmt.Errorf("failed to sync export file: %w", err)
+			}
+			affected, err = r.crossMessageOrm.DeleteMessagesByIDs(ctx, ids)
+		default:
+			return nil, fmt.Errorf("invalid retention action: %v", action)
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Affected += uint64(affected)
+	}
+
+	log.Info("applied bridge history retention", "message type", messageType, "action", action, "cutoff timestamp", cutoffTimestamp,
+		"affected", result.Affected, "export file", result.ExportFile)
+	return result, nil
+}
+
+func (r *RetentionLogic) retentionMonths(messageType orm.MessageType) uint64 {
+	switch messageType {
+	case orm.MessageTypeL1SentMessage:
+		return r.cfg.L1MessageMonths
+	case orm.MessageTypeL2SentMessage:
+		return r.cfg.L2MessageMonths
+	default:
+		return 0
+	}
+}
+
+func (r *RetentionLogic) batchSize() int {
+	if r.cfg.BatchSize <= 0 {
+		return defaultRetentionBatchSize
+	}
+	return r.cfg.BatchSize
+}
+
+func exportMessages(w *bufio.Writer, messages []*orm.CrossMessage) error {
+	encoder := json.NewEncoder(w)
+	for _, message := range messages {
+		if err := encoder.Encode(message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
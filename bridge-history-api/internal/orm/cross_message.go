@@ -252,6 +252,67 @@ func (c *CrossMessage) GetTxsByAddress(ctx context.Context, sender string) ([]*C
 	return messages, nil
 }
 
+// retentionTerminalTxStatuses are the tx statuses after which a cross message is no longer updated by the fetchers,
+// only messages in these statuses are subject to data retention.
+var retentionTerminalTxStatuses = []TxStatusType{TxStatusTypeSentTxReverted, TxStatusTypeRelayed, TxStatusTypeDropped}
+
+// GetExpiredMessages retrieves at most limit messages of the given type in a terminal tx status,
+// whose block timestamp is before the cutoff timestamp and whose id is greater than afterID, ordered by id.
+func (c *CrossMessage) GetExpiredMessages(ctx context.Context, messageType MessageType, cutoffTimestamp uint64, afterID uint64, limit int) ([]*CrossMessage, error) {
+	var messages []*CrossMessage
+	db := c.db.WithContext(ctx)
+	db = db.Model(&CrossMessage{})
+	db = db.Where("message_type = ?", messageType)
+	db = db.Where("block_timestamp < ?", cutoffTimestamp)
+	db = db.Where("tx_status IN (?)", retentionTerminalTxStatuses)
+	db = db.Where("id > ?", afterID)
+	db = db.Order("id asc")
+	db = db.Limit(limit)
+	if err := db.Find(&messages).Error; err != nil {
+		return nil, fmt.Errorf("failed to get expired messages, message type: %v, cutoff timestamp: %v, after id: %v, error: %w", messageType, cutoffTimestamp, afterID, err)
+	}
+	return messages, nil
+}
+
+// DeleteMessagesByIDs permanently deletes the messages with the given ids.
+func (c *CrossMessage) DeleteMessagesByIDs(ctx context.Context, ids []uint64) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	db := c.db.WithContext(ctx)
+	db = db.Where("id IN (?)", ids)
+	result := db.Delete(&CrossMessage{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete messages by ids, error: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// ArchiveMessagesByIDs moves the messages with the given ids into the archive table atomically.
+func (c *CrossMessage) ArchiveMessagesByIDs(ctx context.Context, ids []uint64) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	var rowsAffected int64
+	err := c.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("INSERT INTO cross_message_v2_archive SELECT * FROM cross_message_v2 WHERE id IN (?) ON CONFLICT (id) DO NOTHING", ids).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id IN (?)", ids).Delete(&CrossMessage{})
+		if result.Error != nil {
+			return result.Error
+		}
+		rowsAffected = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive messages by ids, error: %w", err)
+	}
+	return rowsAffected, nil
+}
+
 // UpdateL1MessageQueueEventsInfo updates the information about L1 message queue events in the database.
 func (c *CrossMessage) UpdateL1MessageQueueEventsInfo(ctx context.Context, l1MessageQueueEvents []*MessageQueueEvent) error {
 	// update tx statuses.
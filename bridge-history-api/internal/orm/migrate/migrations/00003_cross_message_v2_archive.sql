@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE cross_message_v2_archive
+(
+    LIKE cross_message_v2 INCLUDING DEFAULTS,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_cma_message_type_block_timestamp ON cross_message_v2_archive (message_type, block_timestamp);
+CREATE INDEX IF NOT EXISTS idx_cma_message_hash ON cross_message_v2_archive (message_hash);
+
+CREATE INDEX IF NOT EXISTS idx_cm_message_type_block_timestamp ON cross_message_v2 (message_type, block_timestamp);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_cm_message_type_block_timestamp;
+DROP TABLE IF EXISTS cross_message_v2_archive;
+-- +goose StatementEnd
@@ -32,4 +32,9 @@ func Route(router *gin.Engine, conf *config.Config, reg prometheus.Registerer) {
 	r.GET("/l2/unclaimed/withdrawals", api.HistoryCtrler.GetL2UnclaimedWithdrawalsByAddress)
 
 	r.POST("/txsbyhashes", api.HistoryCtrler.PostQueryTxsByHashes)
+
+	if api.RetentionCtrler != nil {
+		admin := r.Group("admin/", api.RetentionCtrler.Authorize)
+		admin.POST("/retention", api.RetentionCtrler.PostApplyRetention)
+	}
 }
@@ -23,6 +23,17 @@ const (
 	ErrGetTxsError = 40004
 	// ErrGetTxsByHashError represents an error when trying to get transactions by hash list.
 	ErrGetTxsByHashError = 40005
+	// ErrUnauthorized represents an error when the admin token of a request is missing or invalid.
+	ErrUnauthorized = 40006
+	// ErrApplyRetentionError represents an error when trying to archive or delete expired records.
+	ErrApplyRetentionError = 40007
+)
+
+const (
+	// RetentionActionArchive moves the expired records into the archive table.
+	RetentionActionArchive = "archive"
+	// RetentionActionDelete exports the expired records to a file and then deletes them.
+	RetentionActionDelete = "delete"
 )
 
 // QueryByAddressRequest the request parameter of address api
@@ -37,6 +48,22 @@ type QueryByHashRequest struct {
 	Txs []string `json:"txs" binding:"required,min=1,max=100"`
 }
 
+// RetentionRequest the request parameter of retention api
+type RetentionRequest struct {
+	MessageType     orm.MessageType `json:"message_type" binding:"required,oneof=1 2"`
+	Action          string          `json:"action" binding:"required,oneof=archive delete"`
+	OlderThanMonths uint64          `json:"older_than_months"` // the configured retention of the message type is used if 0
+}
+
+// RetentionResult the schema of retention result
+type RetentionResult struct {
+	MessageType     orm.MessageType `json:"message_type"`
+	Action          string          `json:"action"`
+	CutoffTimestamp uint64          `json:"cutoff_timestamp"`
+	Affected        uint64          `json:"affected"`
+	ExportFile      string          `json:"export_file,omitempty"`
+}
+
 // ResultData contains return txs and total
 type ResultData struct {
 	Results []*TxHistoryInfo `json:"results"`
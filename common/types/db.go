@@ -128,6 +128,31 @@ func (r ProverTaskFailureType) String() string {
 	}
 }
 
+// ProverSLAViolationType the type of prover SLA violation
+type ProverSLAViolationType int
+
+const (
+	// ProverSLAViolationTypeUndefined indicates an unknown prover SLA violation type
+	ProverSLAViolationTypeUndefined ProverSLAViolationType = iota
+	// ProverSLAViolationTypeMissedDeadline prover SLA violation of missing the proving deadline
+	ProverSLAViolationTypeMissedDeadline
+	// ProverSLAViolationTypeInvalidProof prover SLA violation of submitting an invalid proof
+	ProverSLAViolationTypeInvalidProof
+)
+
+func (r ProverSLAViolationType) String() string {
+	switch r {
+	case ProverSLAViolationTypeUndefined:
+		return "prover sla violation undefined"
+	case ProverSLAViolationTypeMissedDeadline:
+		return "prover sla violation missed deadline"
+	case ProverSLAViolationTypeInvalidProof:
+		return "prover sla violation invalid proof"
+	default:
+		return fmt.Sprintf("illegal prover sla violation type (%d)", int32(r))
+	}
+}
+
 // ProvingStatus block_batch proving_status (unassigned, assigned, proved, verified, submitted)
 type ProvingStatus int
 
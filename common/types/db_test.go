@@ -325,3 +325,38 @@ func TestProverTaskFailureType(t *testing.T) {
 		})
 	}
 }
+
+func TestProverSLAViolationType(t *testing.T) {
+	tests := []struct {
+		name string
+		r    ProverSLAViolationType
+		want string
+	}{
+		{
+			"ProverSLAViolationTypeUndefined",
+			ProverSLAViolationTypeUndefined,
+			"prover sla violation undefined",
+		},
+		{
+			"ProverSLAViolationTypeMissedDeadline",
+			ProverSLAViolationTypeMissedDeadline,
+			"prover sla violation missed deadline",
+		},
+		{
+			"ProverSLAViolationTypeInvalidProof",
+			ProverSLAViolationTypeInvalidProof,
+			"prover sla violation invalid proof",
+		},
+		{
+			"Invalid Value",
+			ProverSLAViolationType(999),
+			"illegal prover sla violation type (999)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.r.String())
+		})
+	}
+}
@@ -383,13 +383,19 @@ func EstimateChunkL1CommitGas(c *encoding.Chunk) (uint64, error) {
 		totalL1CommitGas += blockL1CommitGas
 	}
 
-	numBlocks := uint64(len(c.Blocks))
+	totalL1CommitGas += EstimateChunkL1CommitGasOverhead(uint64(len(c.Blocks)), totalTxNum)
+	return totalL1CommitGas, nil
+}
+
+// EstimateChunkL1CommitGasOverhead calculates the L1 commit gas of a chunk on top of the L1 commit gas of its blocks approximately.
+func EstimateChunkL1CommitGasOverhead(numBlocks uint64, totalTxNum uint64) uint64 {
+	var totalL1CommitGas uint64
 	totalL1CommitGas += 100 * numBlocks                         // numBlocks times warm sload
 	totalL1CommitGas += CalldataNonZeroByteGas                  // numBlocks field of chunk encoding in calldata
 	totalL1CommitGas += CalldataNonZeroByteGas * numBlocks * 60 // numBlocks of BlockContext in chunk
 
 	totalL1CommitGas += GetKeccak256Gas(58*numBlocks + 32*totalTxNum) // chunk hash
-	return totalL1CommitGas, nil
+	return totalL1CommitGas
 }
 
 // EstimateBatchL1CommitGas calculates the total L1 commit gas for this batch approximately.
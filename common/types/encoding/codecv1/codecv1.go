@@ -291,23 +291,11 @@ func constructBlobPayload(chunks []*encoding.Chunk) (*kzg4844.Blob, common.Hash,
 	// blob metadata: num_chunks
 	binary.BigEndian.PutUint16(blobBytes[0:], uint16(len(chunks)))
 
-	// encode blob metadata and L2 transactions,
+	// encode blob metadata and L2 transactions (the chunks are encoded concurrently),
 	// and simultaneously also build challenge preimage
-	for chunkID, chunk := range chunks {
+	err := encoding.ForEachChunkL2TxPayload(chunks, func(chunkID int, payload []byte) error {
 		currentChunkStartIndex := len(blobBytes)
-
-		for _, block := range chunk.Blocks {
-			for _, tx := range block.Transactions {
-				if tx.Type != types.L1MessageTxType {
-					// encode L2 txs into blob payload
-					rlpTxData, err := encoding.ConvertTxDataToRLPEncoding(tx)
-					if err != nil {
-						return nil, common.Hash{}, nil, err
-					}
-					blobBytes = append(blobBytes, rlpTxData...)
-				}
-			}
-		}
+		blobBytes = append(blobBytes, payload...)
 
 		// blob metadata: chunki_size
 		if chunkSize := len(blobBytes) - currentChunkStartIndex; chunkSize != 0 {
@@ -317,6 +305,10 @@ func constructBlobPayload(chunks []*encoding.Chunk) (*kzg4844.Blob, common.Hash,
 		// challenge: compute chunk data hash
 		chunkDataHash = crypto.Keccak256Hash(blobBytes[currentChunkStartIndex:])
 		copy(challengePreimage[32+chunkID*32:], chunkDataHash[:])
+		return nil
+	})
+	if err != nil {
+		return nil, common.Hash{}, nil, err
 	}
 
 	// if we have fewer than MaxNumChunks chunks, the rest
@@ -467,17 +459,21 @@ func DecodeFromCalldata(data []byte) (*DABatch, []*DAChunk, error) {
 
 // EstimateChunkL1CommitBlobSize estimates the size of the L1 commit blob for a single chunk.
 func EstimateChunkL1CommitBlobSize(c *encoding.Chunk) (uint64, error) {
-	metadataSize := uint64(2 + 4*MaxNumChunks) // over-estimate: adding metadata length
 	chunkDataSize, err := chunkL1CommitBlobDataSize(c)
 	if err != nil {
 		return 0, err
 	}
-	return calculatePaddedBlobSize(metadataSize + chunkDataSize), nil
+	return EstimateL1CommitBlobSize(chunkDataSize), nil
+}
+
+// EstimateL1CommitBlobSize estimates the size of the L1 commit blob carrying dataSize bytes of chunk data.
+func EstimateL1CommitBlobSize(dataSize uint64) uint64 {
+	metadataSize := uint64(2 + 4*MaxNumChunks) // over-estimate: adding metadata length
+	return calculatePaddedBlobSize(metadataSize + dataSize)
 }
 
 // EstimateBatchL1CommitBlobSize estimates the total size of the L1 commit blob for a batch.
 func EstimateBatchL1CommitBlobSize(b *encoding.Batch) (uint64, error) {
-	metadataSize := uint64(2 + 4*MaxNumChunks)
 	var batchDataSize uint64
 	for _, c := range b.Chunks {
 		chunkDataSize, err := chunkL1CommitBlobDataSize(c)
@@ -486,20 +482,31 @@ func EstimateBatchL1CommitBlobSize(b *encoding.Batch) (uint64, error) {
 		}
 		batchDataSize += chunkDataSize
 	}
-	return calculatePaddedBlobSize(metadataSize + batchDataSize), nil
+	return EstimateL1CommitBlobSize(batchDataSize), nil
 }
 
 func chunkL1CommitBlobDataSize(c *encoding.Chunk) (uint64, error) {
 	var dataSize uint64
 	for _, block := range c.Blocks {
-		for _, tx := range block.Transactions {
-			if tx.Type != types.L1MessageTxType {
-				rlpTxData, err := encoding.ConvertTxDataToRLPEncoding(tx)
-				if err != nil {
-					return 0, err
-				}
-				dataSize += uint64(len(rlpTxData))
+		blockDataSize, err := EstimateBlockL1CommitBlobDataSize(block)
+		if err != nil {
+			return 0, err
+		}
+		dataSize += blockDataSize
+	}
+	return dataSize, nil
+}
+
+// EstimateBlockL1CommitBlobDataSize calculates the size of the L2 transactions of this block in the L1 commit blob.
+func EstimateBlockL1CommitBlobDataSize(b *encoding.Block) (uint64, error) {
+	var dataSize uint64
+	for _, tx := range b.Transactions {
+		if tx.Type != types.L1MessageTxType {
+			rlpTxData, err := encoding.ConvertTxDataToRLPEncoding(tx)
+			if err != nil {
+				return 0, err
 			}
+			dataSize += uint64(len(rlpTxData))
 		}
 	}
 	return dataSize, nil
@@ -565,7 +572,13 @@ func EstimateChunkL1CommitGas(c *encoding.Chunk) uint64 {
 		totalL1CommitGas += blockL1CommitGas
 	}
 
-	numBlocks := uint64(len(c.Blocks))
+	totalL1CommitGas += EstimateChunkL1CommitGasOverhead(uint64(len(c.Blocks)), totalNonSkippedL1Messages)
+	return totalL1CommitGas
+}
+
+// EstimateChunkL1CommitGasOverhead calculates the L1 commit gas of a chunk on top of the L1 commit gas of its blocks approximately.
+func EstimateChunkL1CommitGasOverhead(numBlocks uint64, totalNonSkippedL1Messages uint64) uint64 {
+	var totalL1CommitGas uint64
 	totalL1CommitGas += 100 * numBlocks                         // numBlocks times warm sload
 	totalL1CommitGas += CalldataNonZeroByteGas                  // numBlocks field of chunk encoding in calldata
 	totalL1CommitGas += CalldataNonZeroByteGas * numBlocks * 60 // numBlocks of BlockContext in chunk
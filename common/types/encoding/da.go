@@ -2,6 +2,7 @@ package encoding
 
 import (
 	"fmt"
+	"runtime"
 
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/common/hexutil"
@@ -162,6 +163,66 @@ func ConvertTxDataToRLPEncoding(txData *types.TransactionData) ([]byte, error) {
 	return rlpTxData, nil
 }
 
+// ForEachChunkL2TxPayload encodes the L2 transactions of the chunks concurrently and calls f with the concatenated
+// RLP encoding of the L2 transactions of each chunk, in chunk order, as soon as the chunk is encoded.
+// It stops at the first error, returned either by the encoding or by f.
+func ForEachChunkL2TxPayload(chunks []*Chunk, f func(chunkID int, payload []byte) error) error {
+	payloads := make([][]byte, len(chunks))
+	errs := make([]error, len(chunks))
+	done := make([]chan struct{}, len(chunks))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+
+	// the chunks are encoded in order, at most GOMAXPROCS at a time.
+	quit := make(chan struct{})
+	defer close(quit)
+	go func() {
+		sem := make(chan struct{}, runtime.GOMAXPROCS(0))
+		for i, chunk := range chunks {
+			select {
+			case sem <- struct{}{}:
+			case <-quit:
+				return
+			}
+			go func(i int, chunk *Chunk) {
+				defer func() { <-sem }()
+				payloads[i], errs[i] = chunk.l2TxPayload()
+				close(done[i])
+			}(i, chunk)
+		}
+	}()
+
+	for i := range chunks {
+		<-done[i]
+		if errs[i] != nil {
+			return errs[i]
+		}
+		if err := f(i, payloads[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// l2TxPayload returns the concatenated RLP encoding of the L2 transactions of the chunk.
+func (c *Chunk) l2TxPayload() ([]byte, error) {
+	var payload []byte
+	for _, block := range c.Blocks {
+		for _, tx := range block.Transactions {
+			if tx.Type == types.L1MessageTxType {
+				continue
+			}
+			rlpTxData, err := ConvertTxDataToRLPEncoding(tx)
+			if err != nil {
+				return nil, err
+			}
+			payload = append(payload, rlpTxData...)
+		}
+	}
+	return payload, nil
+}
+
 // CrcMax calculates the maximum row consumption of crc.
 func (c *Chunk) CrcMax() (uint64, error) {
 	// Map sub-circuit name to row count
@@ -2,6 +2,7 @@ package encoding
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"testing"
 
@@ -105,6 +106,44 @@ func TestConvertTxDataToRLPEncoding(t *testing.T) {
 	}
 }
 
+func TestForEachChunkL2TxPayload(t *testing.T) {
+	var chunks []*Chunk
+	for _, filename := range []string{"blockTrace_02.json", "blockTrace_03.json", "blockTrace_04.json", "blockTrace_05.json", "blockTrace_06.json", "blockTrace_07.json"} {
+		chunks = append(chunks, &Chunk{Blocks: []*Block{readBlockFromJSON(t, "../../testdata/"+filename)}})
+	}
+
+	// the payloads are reported in chunk order and match the sequential encoding.
+	var chunkIDs []int
+	err := ForEachChunkL2TxPayload(chunks, func(chunkID int, payload []byte) error {
+		chunkIDs = append(chunkIDs, chunkID)
+		var expected []byte
+		for _, txData := range chunks[chunkID].Blocks[0].Transactions {
+			if txData.Type == types.L1MessageTxType {
+				continue
+			}
+			rlpTxData, err := ConvertTxDataToRLPEncoding(txData)
+			assert.NoError(t, err)
+			expected = append(expected, rlpTxData...)
+		}
+		assert.Equal(t, expected, payload)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5}, chunkIDs)
+
+	// the iteration stops at the first error.
+	chunkIDs = nil
+	err = ForEachChunkL2TxPayload(chunks, func(chunkID int, _ []byte) error {
+		chunkIDs = append(chunkIDs, chunkID)
+		if chunkID == 2 {
+			return errors.New("stop")
+		}
+		return nil
+	})
+	assert.EqualError(t, err, "stop")
+	assert.Equal(t, []int{0, 1, 2}, chunkIDs)
+}
+
 func TestEmptyBatchRoots(t *testing.T) {
 	emptyBatch := &Batch{Chunks: []*Chunk{}}
 	assert.Equal(t, common.Hash{}, emptyBatch.StateRoot())
@@ -26,4 +26,57 @@ const (
 	ErrCoordinatorHandleZkProofFailure = 20003
 	// ErrCoordinatorEmptyProofData get empty proof data
 	ErrCoordinatorEmptyProofData = 20004
+
+	// ErrRollupAPIParameterInvalidNo is invalid params
+	ErrRollupAPIParameterInvalidNo = 30001
+	// ErrRollupAPIGetForcedInclusionFailure is getting forced inclusion status error
+	ErrRollupAPIGetForcedInclusionFailure = 30002
+	// ErrRollupAPIUnauthorized is invalid admin token
+	ErrRollupAPIUnauthorized = 30003
+	// ErrRollupAPIForceSealChunkFailure is force sealing the pending chunk error
+	ErrRollupAPIForceSealChunkFailure = 30004
+	// ErrRollupAPIGetChunkUtilizationFailure is getting the pending chunk utilization error
+	ErrRollupAPIGetChunkUtilizationFailure = 30005
+	// ErrRollupAPIGetThroughputSignalFailure is getting the throughput signal error
+	ErrRollupAPIGetThroughputSignalFailure = 30006
+	// ErrRollupAPISetFeatureFlagFailure is overriding a feature flag error
+	ErrRollupAPISetFeatureFlagFailure = 30007
+	// ErrRollupAPIResetFeatureFlagFailure is removing a feature flag override error
+	ErrRollupAPIResetFeatureFlagFailure = 30008
+	// ErrRollupAPISimulateProposeBatchFailure is simulating the next batch error
+	ErrRollupAPISimulateProposeBatchFailure = 30009
+	// ErrRollupAPISetChunkProfileFailure is switching the chunk proposer profile error
+	ErrRollupAPISetChunkProfileFailure = 30010
+	// ErrRollupAPIForceSealBatchFailure is force sealing the unbatched chunks error
+	ErrRollupAPIForceSealBatchFailure = 30011
+	// ErrRollupAPIGetBatchProposerPauseFailure is getting the batch proposer pause state error
+	ErrRollupAPIGetBatchProposerPauseFailure = 30012
+	// ErrRollupAPISetBatchProposerPauseFailure is pausing or resuming the batch proposer error
+	ErrRollupAPISetBatchProposerPauseFailure = 30013
+	// ErrRollupAPIGetForcedTxQueueFailure is getting the queued enforced transactions error
+	ErrRollupAPIGetForcedTxQueueFailure = 30014
+	// ErrRollupAPIGetL2BlockQuarantineFailure is getting the quarantined l2 blocks error
+	ErrRollupAPIGetL2BlockQuarantineFailure = 30015
+	// ErrRollupAPIUpdateL2BlockQuarantineFailure is retrying or resolving a quarantined l2 block error
+	ErrRollupAPIUpdateL2BlockQuarantineFailure = 30016
+	// ErrRollupAPIGetL2SyncHeightFailure is getting the l2 watcher sync height error
+	ErrRollupAPIGetL2SyncHeightFailure = 30017
+	// ErrRollupAPISetL2SyncHeightFailure is moving the l2 watcher sync height back error
+	ErrRollupAPISetL2SyncHeightFailure = 30018
+	// ErrRollupAPIGetL2MessageFailure is getting an indexed l2 message error
+	ErrRollupAPIGetL2MessageFailure = 30019
+	// ErrRollupAPIGetRelayerCircuitBreakerFailure is getting the relayer circuit breaker states error
+	ErrRollupAPIGetRelayerCircuitBreakerFailure = 30020
+	// ErrRollupAPISetRelayerCircuitBreakerFailure is pausing or resuming a relayer pipeline error
+	ErrRollupAPISetRelayerCircuitBreakerFailure = 30021
+	// ErrRollupAPIGetPreparedTransactionsFailure is getting the transactions prepared for the multisig error
+	ErrRollupAPIGetPreparedTransactionsFailure = 30022
+	// ErrRollupAPIGetSkippedL1MessagesFailure is getting the skipped l1 messages error
+	ErrRollupAPIGetSkippedL1MessagesFailure = 30023
+	// ErrRollupAPIGetSkippedL1MessageTxFailure is constructing the replay or drop transaction of a skipped l1 message error
+	ErrRollupAPIGetSkippedL1MessageTxFailure = 30024
+	// ErrRollupAPIGetTransactionCostsFailure is getting the aggregated costs of the sent transactions error
+	ErrRollupAPIGetTransactionCostsFailure = 30025
+	// ErrRollupAPIRevertBatchesFailure is reverting the last committed batches error
+	ErrRollupAPIRevertBatchesFailure = 30026
 )
@@ -0,0 +1,995 @@
+// Canonical protobuf schemas of the pipeline entities, mirroring the JSON schemas in schema.go.
+// Field numbers are never reused; fields are only added within a major version.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        (unknown)
+// source: common/types/schema/schema.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Chunk struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SchemaVersion                string `protobuf:"bytes,1,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
+	Index                        uint64 `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	Hash                         string `protobuf:"bytes,3,opt,name=hash,proto3" json:"hash,omitempty"`
+	StartBlockNumber             uint64 `protobuf:"varint,4,opt,name=start_block_number,json=startBlockNumber,proto3" json:"start_block_number,omitempty"`
+	StartBlockHash               string `protobuf:"bytes,5,opt,name=start_block_hash,json=startBlockHash,proto3" json:"start_block_hash,omitempty"`
+	EndBlockNumber               uint64 `protobuf:"varint,6,opt,name=end_block_number,json=endBlockNumber,proto3" json:"end_block_number,omitempty"`
+	EndBlockHash                 string `protobuf:"bytes,7,opt,name=end_block_hash,json=endBlockHash,proto3" json:"end_block_hash,omitempty"`
+	StartBlockTime               uint64 `protobuf:"varint,8,opt,name=start_block_time,json=startBlockTime,proto3" json:"start_block_time,omitempty"`
+	TotalL1MessagesPoppedBefore  uint64 `protobuf:"varint,9,opt,name=total_l1_messages_popped_before,json=totalL1MessagesPoppedBefore,proto3" json:"total_l1_messages_popped_before,omitempty"`
+	TotalL1MessagesPoppedInChunk uint64 `protobuf:"varint,10,opt,name=total_l1_messages_popped_in_chunk,json=totalL1MessagesPoppedInChunk,proto3" json:"total_l1_messages_popped_in_chunk,omitempty"`
+	ParentChunkHash              string `protobuf:"bytes,11,opt,name=parent_chunk_hash,json=parentChunkHash,proto3" json:"parent_chunk_hash,omitempty"`
+	StateRoot                    string `protobuf:"bytes,12,opt,name=state_root,json=stateRoot,proto3" json:"state_root,omitempty"`
+	ParentChunkStateRoot         string `protobuf:"bytes,13,opt,name=parent_chunk_state_root,json=parentChunkStateRoot,proto3" json:"parent_chunk_state_root,omitempty"`
+	WithdrawRoot                 string `protobuf:"bytes,14,opt,name=withdraw_root,json=withdrawRoot,proto3" json:"withdraw_root,omitempty"`
+	TotalL2TxGas                 uint64 `protobuf:"varint,15,opt,name=total_l2_tx_gas,json=totalL2TxGas,proto3" json:"total_l2_tx_gas,omitempty"`
+	TotalL2TxNum                 uint64 `protobuf:"varint,16,opt,name=total_l2_tx_num,json=totalL2TxNum,proto3" json:"total_l2_tx_num,omitempty"`
+	TotalL1CommitCalldataSize    uint64 `protobuf:"varint,17,opt,name=total_l1_commit_calldata_size,json=totalL1CommitCalldataSize,proto3" json:"total_l1_commit_calldata_size,omitempty"`
+	TotalL1CommitGas             uint64 `protobuf:"varint,18,opt,name=total_l1_commit_gas,json=totalL1CommitGas,proto3" json:"total_l1_commit_gas,omitempty"`
+	CrcMax                       uint64 `protobuf:"varint,19,opt,name=crc_max,json=crcMax,proto3" json:"crc_max,omitempty"`
+	BlobSize                     uint64 `protobuf:"varint,20,opt,name=blob_size,json=blobSize,proto3" json:"blob_size,omitempty"`
+	// types.ProvingStatus value.
+	ProvingStatus int32  `protobuf:"varint,21,opt,name=proving_status,json=provingStatus,proto3" json:"proving_status,omitempty"`
+	BatchHash     string `protobuf:"bytes,22,opt,name=batch_hash,json=batchHash,proto3" json:"batch_hash,omitempty"`
+	// unix timestamp in seconds.
+	CreatedAt int64 `protobuf:"varint,23,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+}
+
+func (x *Chunk) Reset() {
+	*x = Chunk{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_common_types_schema_schema_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Chunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Chunk) ProtoMessage() {}
+
+func (x *Chunk) ProtoReflect() protoreflect.Message {
+	mi := &file_common_types_schema_schema_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Chunk.ProtoReflect.Descriptor instead.
+func (*Chunk) Descriptor() ([]byte, []int) {
+	return file_common_types_schema_schema_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Chunk) GetSchemaVersion() string {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return ""
+}
+
+func (x *Chunk) GetIndex() uint64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *Chunk) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *Chunk) GetStartBlockNumber() uint64 {
+	if x != nil {
+		return x.StartBlockNumber
+	}
+	return 0
+}
+
+func (x *Chunk) GetStartBlockHash() string {
+	if x != nil {
+		return x.StartBlockHash
+	}
+	return ""
+}
+
+func (x *Chunk) GetEndBlockNumber() uint64 {
+	if x != nil {
+		return x.EndBlockNumber
+	}
+	return 0
+}
+
+func (x *Chunk) GetEndBlockHash() string {
+	if x != nil {
+		return x.EndBlockHash
+	}
+	return ""
+}
+
+func (x *Chunk) GetStartBlockTime() uint64 {
+	if x != nil {
+		return x.StartBlockTime
+	}
+	return 0
+}
+
+func (x *Chunk) GetTotalL1MessagesPoppedBefore() uint64 {
+	if x != nil {
+		return x.TotalL1MessagesPoppedBefore
+	}
+	return 0
+}
+
+func (x *Chunk) GetTotalL1MessagesPoppedInChunk() uint64 {
+	if x != nil {
+		return x.TotalL1MessagesPoppedInChunk
+	}
+	return 0
+}
+
+func (x *Chunk) GetParentChunkHash() string {
+	if x != nil {
+		return x.ParentChunkHash
+	}
+	return ""
+}
+
+func (x *Chunk) GetStateRoot() string {
+	if x != nil {
+		return x.StateRoot
+	}
+	return ""
+}
+
+func (x *Chunk) GetParentChunkStateRoot() string {
+	if x != nil {
+		return x.ParentChunkStateRoot
+	}
+	return ""
+}
+
+func (x *Chunk) GetWithdrawRoot() string {
+	if x != nil {
+		return x.WithdrawRoot
+	}
+	return ""
+}
+
+func (x *Chunk) GetTotalL2TxGas() uint64 {
+	if x != nil {
+		return x.TotalL2TxGas
+	}
+	return 0
+}
+
+func (x *Chunk) GetTotalL2TxNum() uint64 {
+	if x != nil {
+		return x.TotalL2TxNum
+	}
+	return 0
+}
+
+func (x *Chunk) GetTotalL1CommitCalldataSize() uint64 {
+	if x != nil {
+		return x.TotalL1CommitCalldataSize
+	}
+	return 0
+}
+
+func (x *Chunk) GetTotalL1CommitGas() uint64 {
+	if x != nil {
+		return x.TotalL1CommitGas
+	}
+	return 0
+}
+
+func (x *Chunk) GetCrcMax() uint64 {
+	if x != nil {
+		return x.CrcMax
+	}
+	return 0
+}
+
+func (x *Chunk) GetBlobSize() uint64 {
+	if x != nil {
+		return x.BlobSize
+	}
+	return 0
+}
+
+func (x *Chunk) GetProvingStatus() int32 {
+	if x != nil {
+		return x.ProvingStatus
+	}
+	return 0
+}
+
+func (x *Chunk) GetBatchHash() string {
+	if x != nil {
+		return x.BatchHash
+	}
+	return ""
+}
+
+func (x *Chunk) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+type Batch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SchemaVersion             string `protobuf:"bytes,1,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
+	Index                     uint64 `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	Hash                      string `protobuf:"bytes,3,opt,name=hash,proto3" json:"hash,omitempty"`
+	DataHash                  string `protobuf:"bytes,4,opt,name=data_hash,json=dataHash,proto3" json:"data_hash,omitempty"`
+	StartChunkIndex           uint64 `protobuf:"varint,5,opt,name=start_chunk_index,json=startChunkIndex,proto3" json:"start_chunk_index,omitempty"`
+	StartChunkHash            string `protobuf:"bytes,6,opt,name=start_chunk_hash,json=startChunkHash,proto3" json:"start_chunk_hash,omitempty"`
+	EndChunkIndex             uint64 `protobuf:"varint,7,opt,name=end_chunk_index,json=endChunkIndex,proto3" json:"end_chunk_index,omitempty"`
+	EndChunkHash              string `protobuf:"bytes,8,opt,name=end_chunk_hash,json=endChunkHash,proto3" json:"end_chunk_hash,omitempty"`
+	StateRoot                 string `protobuf:"bytes,9,opt,name=state_root,json=stateRoot,proto3" json:"state_root,omitempty"`
+	WithdrawRoot              string `protobuf:"bytes,10,opt,name=withdraw_root,json=withdrawRoot,proto3" json:"withdraw_root,omitempty"`
+	ParentBatchHash           string `protobuf:"bytes,11,opt,name=parent_batch_hash,json=parentBatchHash,proto3" json:"parent_batch_hash,omitempty"`
+	BatchHeader               []byte `protobuf:"bytes,12,opt,name=batch_header,json=batchHeader,proto3" json:"batch_header,omitempty"`
+	TotalL1CommitCalldataSize uint64 `protobuf:"varint,13,opt,name=total_l1_commit_calldata_size,json=totalL1CommitCalldataSize,proto3" json:"total_l1_commit_calldata_size,omitempty"`
+	TotalL1CommitGas          uint64 `protobuf:"varint,14,opt,name=total_l1_commit_gas,json=totalL1CommitGas,proto3" json:"total_l1_commit_gas,omitempty"`
+	BlobSize                  uint64 `protobuf:"varint,15,opt,name=blob_size,json=blobSize,proto3" json:"blob_size,omitempty"`
+	// types.ChunkProofsStatus value.
+	ChunkProofsStatus int32 `protobuf:"varint,16,opt,name=chunk_proofs_status,json=chunkProofsStatus,proto3" json:"chunk_proofs_status,omitempty"`
+	// types.ProvingStatus value.
+	ProvingStatus int32 `protobuf:"varint,17,opt,name=proving_status,json=provingStatus,proto3" json:"proving_status,omitempty"`
+	// types.RollupStatus value.
+	RollupStatus   int32  `protobuf:"varint,18,opt,name=rollup_status,json=rollupStatus,proto3" json:"rollup_status,omitempty"`
+	CommitTxHash   string `protobuf:"bytes,19,opt,name=commit_tx_hash,json=commitTxHash,proto3" json:"commit_tx_hash,omitempty"`
+	FinalizeTxHash string `protobuf:"bytes,20,opt,name=finalize_tx_hash,json=finalizeTxHash,proto3" json:"finalize_tx_hash,omitempty"`
+	// unix timestamp in seconds.
+	CreatedAt int64 `protobuf:"varint,21,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+}
+
+func (x *Batch) Reset() {
+	*x = Batch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_common_types_schema_schema_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Batch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Batch) ProtoMessage() {}
+
+func (x *Batch) ProtoReflect() protoreflect.Message {
+	mi := &file_common_types_schema_schema_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Batch.ProtoReflect.Descriptor instead.
+func (*Batch) Descriptor() ([]byte, []int) {
+	return file_common_types_schema_schema_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Batch) GetSchemaVersion() string {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return ""
+}
+
+func (x *Batch) GetIndex() uint64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *Batch) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *Batch) GetDataHash() string {
+	if x != nil {
+		return x.DataHash
+	}
+	return ""
+}
+
+func (x *Batch) GetStartChunkIndex() uint64 {
+	if x != nil {
+		return x.StartChunkIndex
+	}
+	return 0
+}
+
+func (x *Batch) GetStartChunkHash() string {
+	if x != nil {
+		return x.StartChunkHash
+	}
+	return ""
+}
+
+func (x *Batch) GetEndChunkIndex() uint64 {
+	if x != nil {
+		return x.EndChunkIndex
+	}
+	return 0
+}
+
+func (x *Batch) GetEndChunkHash() string {
+	if x != nil {
+		return x.EndChunkHash
+	}
+	return ""
+}
+
+func (x *Batch) GetStateRoot() string {
+	if x != nil {
+		return x.StateRoot
+	}
+	return ""
+}
+
+func (x *Batch) GetWithdrawRoot() string {
+	if x != nil {
+		return x.WithdrawRoot
+	}
+	return ""
+}
+
+func (x *Batch) GetParentBatchHash() string {
+	if x != nil {
+		return x.ParentBatchHash
+	}
+	return ""
+}
+
+func (x *Batch) GetBatchHeader() []byte {
+	if x != nil {
+		return x.BatchHeader
+	}
+	return nil
+}
+
+func (x *Batch) GetTotalL1CommitCalldataSize() uint64 {
+	if x != nil {
+		return x.TotalL1CommitCalldataSize
+	}
+	return 0
+}
+
+func (x *Batch) GetTotalL1CommitGas() uint64 {
+	if x != nil {
+		return x.TotalL1CommitGas
+	}
+	return 0
+}
+
+func (x *Batch) GetBlobSize() uint64 {
+	if x != nil {
+		return x.BlobSize
+	}
+	return 0
+}
+
+func (x *Batch) GetChunkProofsStatus() int32 {
+	if x != nil {
+		return x.ChunkProofsStatus
+	}
+	return 0
+}
+
+func (x *Batch) GetProvingStatus() int32 {
+	if x != nil {
+		return x.ProvingStatus
+	}
+	return 0
+}
+
+func (x *Batch) GetRollupStatus() int32 {
+	if x != nil {
+		return x.RollupStatus
+	}
+	return 0
+}
+
+func (x *Batch) GetCommitTxHash() string {
+	if x != nil {
+		return x.CommitTxHash
+	}
+	return ""
+}
+
+func (x *Batch) GetFinalizeTxHash() string {
+	if x != nil {
+		return x.FinalizeTxHash
+	}
+	return ""
+}
+
+func (x *Batch) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+type Bundle struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SchemaVersion   string `protobuf:"bytes,1,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
+	Index           uint64 `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	Hash            string `protobuf:"bytes,3,opt,name=hash,proto3" json:"hash,omitempty"`
+	StartBatchIndex uint64 `protobuf:"varint,4,opt,name=start_batch_index,json=startBatchIndex,proto3" json:"start_batch_index,omitempty"`
+	StartBatchHash  string `protobuf:"bytes,5,opt,name=start_batch_hash,json=startBatchHash,proto3" json:"start_batch_hash,omitempty"`
+	EndBatchIndex   uint64 `protobuf:"varint,6,opt,name=end_batch_index,json=endBatchIndex,proto3" json:"end_batch_index,omitempty"`
+	EndBatchHash    string `protobuf:"bytes,7,opt,name=end_batch_hash,json=endBatchHash,proto3" json:"end_batch_hash,omitempty"`
+	// types.ProvingStatus value.
+	ProvingStatus int32 `protobuf:"varint,8,opt,name=proving_status,json=provingStatus,proto3" json:"proving_status,omitempty"`
+	// types.RollupStatus value.
+	RollupStatus   int32  `protobuf:"varint,9,opt,name=rollup_status,json=rollupStatus,proto3" json:"rollup_status,omitempty"`
+	FinalizeTxHash string `protobuf:"bytes,10,opt,name=finalize_tx_hash,json=finalizeTxHash,proto3" json:"finalize_tx_hash,omitempty"`
+	// unix timestamp in seconds.
+	CreatedAt int64 `protobuf:"varint,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+}
+
+func (x *Bundle) Reset() {
+	*x = Bundle{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_common_types_schema_schema_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Bundle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Bundle) ProtoMessage() {}
+
+func (x *Bundle) ProtoReflect() protoreflect.Message {
+	mi := &file_common_types_schema_schema_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Bundle.ProtoReflect.Descriptor instead.
+func (*Bundle) Descriptor() ([]byte, []int) {
+	return file_common_types_schema_schema_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Bundle) GetSchemaVersion() string {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return ""
+}
+
+func (x *Bundle) GetIndex() uint64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *Bundle) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *Bundle) GetStartBatchIndex() uint64 {
+	if x != nil {
+		return x.StartBatchIndex
+	}
+	return 0
+}
+
+func (x *Bundle) GetStartBatchHash() string {
+	if x != nil {
+		return x.StartBatchHash
+	}
+	return ""
+}
+
+func (x *Bundle) GetEndBatchIndex() uint64 {
+	if x != nil {
+		return x.EndBatchIndex
+	}
+	return 0
+}
+
+func (x *Bundle) GetEndBatchHash() string {
+	if x != nil {
+		return x.EndBatchHash
+	}
+	return ""
+}
+
+func (x *Bundle) GetProvingStatus() int32 {
+	if x != nil {
+		return x.ProvingStatus
+	}
+	return 0
+}
+
+func (x *Bundle) GetRollupStatus() int32 {
+	if x != nil {
+		return x.RollupStatus
+	}
+	return 0
+}
+
+func (x *Bundle) GetFinalizeTxHash() string {
+	if x != nil {
+		return x.FinalizeTxHash
+	}
+	return ""
+}
+
+func (x *Bundle) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+type ProvingTask struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SchemaVersion string `protobuf:"bytes,1,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
+	Uuid          string `protobuf:"bytes,2,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	TaskId        string `protobuf:"bytes,3,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	// message.ProofType value.
+	TaskType        int32  `protobuf:"varint,4,opt,name=task_type,json=taskType,proto3" json:"task_type,omitempty"`
+	ProverPublicKey string `protobuf:"bytes,5,opt,name=prover_public_key,json=proverPublicKey,proto3" json:"prover_public_key,omitempty"`
+	ProverName      string `protobuf:"bytes,6,opt,name=prover_name,json=proverName,proto3" json:"prover_name,omitempty"`
+	ProverVersion   string `protobuf:"bytes,7,opt,name=prover_version,json=proverVersion,proto3" json:"prover_version,omitempty"`
+	// types.ProverProveStatus value.
+	ProvingStatus int32 `protobuf:"varint,8,opt,name=proving_status,json=provingStatus,proto3" json:"proving_status,omitempty"`
+	// types.ProverTaskFailureType value.
+	FailureType int32 `protobuf:"varint,9,opt,name=failure_type,json=failureType,proto3" json:"failure_type,omitempty"`
+	// decimal string.
+	Reward string `protobuf:"bytes,10,opt,name=reward,proto3" json:"reward,omitempty"`
+	// unix timestamp in seconds.
+	AssignedAt int64 `protobuf:"varint,11,opt,name=assigned_at,json=assignedAt,proto3" json:"assigned_at,omitempty"`
+}
+
+func (x *ProvingTask) Reset() {
+	*x = ProvingTask{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_common_types_schema_schema_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProvingTask) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProvingTask) ProtoMessage() {}
+
+func (x *ProvingTask) ProtoReflect() protoreflect.Message {
+	mi := &file_common_types_schema_schema_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProvingTask.ProtoReflect.Descriptor instead.
+func (*ProvingTask) Descriptor() ([]byte, []int) {
+	return file_common_types_schema_schema_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ProvingTask) GetSchemaVersion() string {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return ""
+}
+
+func (x *ProvingTask) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *ProvingTask) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *ProvingTask) GetTaskType() int32 {
+	if x != nil {
+		return x.TaskType
+	}
+	return 0
+}
+
+func (x *ProvingTask) GetProverPublicKey() string {
+	if x != nil {
+		return x.ProverPublicKey
+	}
+	return ""
+}
+
+func (x *ProvingTask) GetProverName() string {
+	if x != nil {
+		return x.ProverName
+	}
+	return ""
+}
+
+func (x *ProvingTask) GetProverVersion() string {
+	if x != nil {
+		return x.ProverVersion
+	}
+	return ""
+}
+
+func (x *ProvingTask) GetProvingStatus() int32 {
+	if x != nil {
+		return x.ProvingStatus
+	}
+	return 0
+}
+
+func (x *ProvingTask) GetFailureType() int32 {
+	if x != nil {
+		return x.FailureType
+	}
+	return 0
+}
+
+func (x *ProvingTask) GetReward() string {
+	if x != nil {
+		return x.Reward
+	}
+	return ""
+}
+
+func (x *ProvingTask) GetAssignedAt() int64 {
+	if x != nil {
+		return x.AssignedAt
+	}
+	return 0
+}
+
+var File_common_types_schema_schema_proto protoreflect.FileDescriptor
+
+var file_common_types_schema_schema_proto_rawDesc = []byte{
+	0x0a, 0x20, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2f, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2f, 0x73,
+	0x63, 0x68, 0x65, 0x6d, 0x61, 0x2f, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x10, 0x73, 0x63, 0x72, 0x6f, 0x6c, 0x6c, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x6d,
+	0x61, 0x2e, 0x76, 0x31, 0x22, 0xba, 0x07, 0x0a, 0x05, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x25,
+	0x0a, 0x0e, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x56, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x12, 0x0a, 0x04, 0x68,
+	0x61, 0x73, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x12,
+	0x2c, 0x0a, 0x12, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x6e,
+	0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x28, 0x0a,
+	0x10, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x68, 0x61, 0x73,
+	0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x73, 0x74, 0x61, 0x72, 0x74, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x48, 0x61, 0x73, 0x68, 0x12, 0x28, 0x0a, 0x10, 0x65, 0x6e, 0x64, 0x5f, 0x62,
+	0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x0e, 0x65, 0x6e, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x4e, 0x75, 0x6d, 0x62, 0x65,
+	0x72, 0x12, 0x24, 0x0a, 0x0e, 0x65, 0x6e, 0x64, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x68,
+	0x61, 0x73, 0x68, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x6e, 0x64, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x48, 0x61, 0x73, 0x68, 0x12, 0x28, 0x0a, 0x10, 0x73, 0x74, 0x61, 0x72, 0x74,
+	0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x0e, 0x73, 0x74, 0x61, 0x72, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x54, 0x69, 0x6d,
+	0x65, 0x12, 0x44, 0x0a, 0x1f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x6c, 0x31, 0x5f, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x5f, 0x70, 0x6f, 0x70, 0x70, 0x65, 0x64, 0x5f, 0x62, 0x65,
+	0x66, 0x6f, 0x72, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x04, 0x52, 0x1b, 0x74, 0x6f, 0x74, 0x61,
+	0x6c, 0x4c, 0x31, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x50, 0x6f, 0x70, 0x70, 0x65,
+	0x64, 0x42, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x12, 0x47, 0x0a, 0x21, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x5f, 0x6c, 0x31, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x5f, 0x70, 0x6f, 0x70,
+	0x70, 0x65, 0x64, 0x5f, 0x69, 0x6e, 0x5f, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x1c, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x4c, 0x31, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x73, 0x50, 0x6f, 0x70, 0x70, 0x65, 0x64, 0x49, 0x6e, 0x43, 0x68, 0x75, 0x6e, 0x6b,
+	0x12, 0x2a, 0x0a, 0x11, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x63, 0x68, 0x75, 0x6e, 0x6b,
+	0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x70, 0x61, 0x72,
+	0x65, 0x6e, 0x74, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x48, 0x61, 0x73, 0x68, 0x12, 0x1d, 0x0a, 0x0a,
+	0x73, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x73, 0x74, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x35, 0x0a, 0x17, 0x70,
+	0x61, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x5f, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x14, 0x70, 0x61,
+	0x72, 0x65, 0x6e, 0x74, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x6f,
+	0x6f, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x77, 0x69, 0x74, 0x68, 0x64, 0x72, 0x61, 0x77, 0x5f, 0x72,
+	0x6f, 0x6f, 0x74, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x77, 0x69, 0x74, 0x68, 0x64,
+	0x72, 0x61, 0x77, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x25, 0x0a, 0x0f, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x5f, 0x6c, 0x32, 0x5f, 0x74, 0x78, 0x5f, 0x67, 0x61, 0x73, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x0c, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x4c, 0x32, 0x54, 0x78, 0x47, 0x61, 0x73, 0x12, 0x25,
+	0x0a, 0x0f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x6c, 0x32, 0x5f, 0x74, 0x78, 0x5f, 0x6e, 0x75,
+	0x6d, 0x18, 0x10, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x4c, 0x32,
+	0x54, 0x78, 0x4e, 0x75, 0x6d, 0x12, 0x40, 0x0a, 0x1d, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x6c,
+	0x31, 0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x5f, 0x63, 0x61, 0x6c, 0x6c, 0x64, 0x61, 0x74,
+	0x61, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x11, 0x20, 0x01, 0x28, 0x04, 0x52, 0x19, 0x74, 0x6f,
+	0x74, 0x61, 0x6c, 0x4c, 0x31, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x43, 0x61, 0x6c, 0x6c, 0x64,
+	0x61, 0x74, 0x61, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x2d, 0x0a, 0x13, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x5f, 0x6c, 0x31, 0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x5f, 0x67, 0x61, 0x73, 0x18, 0x12,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x4c, 0x31, 0x43, 0x6f, 0x6d,
+	0x6d, 0x69, 0x74, 0x47, 0x61, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x63, 0x72, 0x63, 0x5f, 0x6d, 0x61,
+	0x78, 0x18, 0x13, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x63, 0x72, 0x63, 0x4d, 0x61, 0x78, 0x12,
+	0x1b, 0x0a, 0x09, 0x62, 0x6c, 0x6f, 0x62, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x14, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x08, 0x62, 0x6c, 0x6f, 0x62, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x25, 0x0a, 0x0e,
+	0x70, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x67, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x15,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x68, 0x61, 0x73,
+	0x68, 0x18, 0x16, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x62, 0x61, 0x74, 0x63, 0x68, 0x48, 0x61,
+	0x73, 0x68, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74,
+	0x18, 0x17, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41,
+	0x74, 0x22, 0xa5, 0x06, 0x0a, 0x05, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x25, 0x0a, 0x0e, 0x73,
+	0x63, 0x68, 0x65, 0x6d, 0x61, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x56, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x12, 0x1b, 0x0a, 0x09,
+	0x64, 0x61, 0x74, 0x61, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x64, 0x61, 0x74, 0x61, 0x48, 0x61, 0x73, 0x68, 0x12, 0x2a, 0x0a, 0x11, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x5f, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x43, 0x68, 0x75, 0x6e, 0x6b,
+	0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x28, 0x0a, 0x10, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x63,
+	0x68, 0x75, 0x6e, 0x6b, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0e, 0x73, 0x74, 0x61, 0x72, 0x74, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x48, 0x61, 0x73, 0x68, 0x12,
+	0x26, 0x0a, 0x0f, 0x65, 0x6e, 0x64, 0x5f, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x5f, 0x69, 0x6e, 0x64,
+	0x65, 0x78, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x65, 0x6e, 0x64, 0x43, 0x68, 0x75,
+	0x6e, 0x6b, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x24, 0x0a, 0x0e, 0x65, 0x6e, 0x64, 0x5f, 0x63,
+	0x68, 0x75, 0x6e, 0x6b, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0c, 0x65, 0x6e, 0x64, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x48, 0x61, 0x73, 0x68, 0x12, 0x1d, 0x0a,
+	0x0a, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x73, 0x74, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x23, 0x0a, 0x0d,
+	0x77, 0x69, 0x74, 0x68, 0x64, 0x72, 0x61, 0x77, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x0a, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0c, 0x77, 0x69, 0x74, 0x68, 0x64, 0x72, 0x61, 0x77, 0x52, 0x6f, 0x6f,
+	0x74, 0x12, 0x2a, 0x0a, 0x11, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x62, 0x61, 0x74, 0x63,
+	0x68, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x70, 0x61,
+	0x72, 0x65, 0x6e, 0x74, 0x42, 0x61, 0x74, 0x63, 0x68, 0x48, 0x61, 0x73, 0x68, 0x12, 0x21, 0x0a,
+	0x0c, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x0c, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x0b, 0x62, 0x61, 0x74, 0x63, 0x68, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72,
+	0x12, 0x40, 0x0a, 0x1d, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x6c, 0x31, 0x5f, 0x63, 0x6f, 0x6d,
+	0x6d, 0x69, 0x74, 0x5f, 0x63, 0x61, 0x6c, 0x6c, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x73, 0x69, 0x7a,
+	0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x04, 0x52, 0x19, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x4c, 0x31,
+	0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x43, 0x61, 0x6c, 0x6c, 0x64, 0x61, 0x74, 0x61, 0x53, 0x69,
+	0x7a, 0x65, 0x12, 0x2d, 0x0a, 0x13, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x6c, 0x31, 0x5f, 0x63,
+	0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x5f, 0x67, 0x61, 0x73, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x10, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x4c, 0x31, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x47, 0x61,
+	0x73, 0x12, 0x1b, 0x0a, 0x09, 0x62, 0x6c, 0x6f, 0x62, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x0f,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x62, 0x6c, 0x6f, 0x62, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x2e,
+	0x0a, 0x13, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x73, 0x5f, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x10, 0x20, 0x01, 0x28, 0x05, 0x52, 0x11, 0x63, 0x68, 0x75,
+	0x6e, 0x6b, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x73, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x25,
+	0x0a, 0x0e, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x67, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x18, 0x11, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x67, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x72, 0x6f, 0x6c, 0x6c, 0x75, 0x70, 0x5f,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x12, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x72, 0x6f,
+	0x6c, 0x6c, 0x75, 0x70, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x24, 0x0a, 0x0e, 0x63, 0x6f,
+	0x6d, 0x6d, 0x69, 0x74, 0x5f, 0x74, 0x78, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x13, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0c, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x54, 0x78, 0x48, 0x61, 0x73, 0x68,
+	0x12, 0x28, 0x0a, 0x10, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x5f, 0x74, 0x78, 0x5f,
+	0x68, 0x61, 0x73, 0x68, 0x18, 0x14, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x66, 0x69, 0x6e, 0x61,
+	0x6c, 0x69, 0x7a, 0x65, 0x54, 0x78, 0x48, 0x61, 0x73, 0x68, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x15, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09,
+	0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0x92, 0x03, 0x0a, 0x06, 0x42, 0x75,
+	0x6e, 0x64, 0x6c, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x5f, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x63,
+	0x68, 0x65, 0x6d, 0x61, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x69,
+	0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65,
+	0x78, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x68, 0x61, 0x73, 0x68, 0x12, 0x2a, 0x0a, 0x11, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x62,
+	0x61, 0x74, 0x63, 0x68, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x0f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x42, 0x61, 0x74, 0x63, 0x68, 0x49, 0x6e, 0x64, 0x65,
+	0x78, 0x12, 0x28, 0x0a, 0x10, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x62, 0x61, 0x74, 0x63, 0x68,
+	0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x42, 0x61, 0x74, 0x63, 0x68, 0x48, 0x61, 0x73, 0x68, 0x12, 0x26, 0x0a, 0x0f, 0x65,
+	0x6e, 0x64, 0x5f, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x65, 0x6e, 0x64, 0x42, 0x61, 0x74, 0x63, 0x68, 0x49, 0x6e,
+	0x64, 0x65, 0x78, 0x12, 0x24, 0x0a, 0x0e, 0x65, 0x6e, 0x64, 0x5f, 0x62, 0x61, 0x74, 0x63, 0x68,
+	0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x6e, 0x64,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x48, 0x61, 0x73, 0x68, 0x12, 0x25, 0x0a, 0x0e, 0x70, 0x72, 0x6f,
+	0x76, 0x69, 0x6e, 0x67, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x0d, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x23, 0x0a, 0x0d, 0x72, 0x6f, 0x6c, 0x6c, 0x75, 0x70, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x72, 0x6f, 0x6c, 0x6c, 0x75, 0x70, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x28, 0x0a, 0x10, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a,
+	0x65, 0x5f, 0x74, 0x78, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0e, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x54, 0x78, 0x48, 0x61, 0x73, 0x68, 0x12,
+	0x1d, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x0b, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0xf5,
+	0x02, 0x0a, 0x0b, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x67, 0x54, 0x61, 0x73, 0x6b, 0x12, 0x25,
+	0x0a, 0x0e, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x56, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x74, 0x61, 0x73,
+	0x6b, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x61, 0x73, 0x6b,
+	0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x61, 0x73, 0x6b, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x74, 0x61, 0x73, 0x6b, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x2a, 0x0a, 0x11, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x72, 0x5f, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63,
+	0x5f, 0x6b, 0x65, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x70, 0x72, 0x6f, 0x76,
+	0x65, 0x72, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x12, 0x1f, 0x0a, 0x0b, 0x70,
+	0x72, 0x6f, 0x76, 0x65, 0x72, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x25, 0x0a, 0x0e,
+	0x70, 0x72, 0x6f, 0x76, 0x65, 0x72, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x72, 0x56, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x12, 0x25, 0x0a, 0x0e, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x67, 0x5f, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x70, 0x72, 0x6f,
+	0x76, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x66, 0x61,
+	0x69, 0x6c, 0x75, 0x72, 0x65, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0b, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x16, 0x0a,
+	0x06, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72,
+	0x65, 0x77, 0x61, 0x72, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x65,
+	0x64, 0x5f, 0x61, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x61, 0x73, 0x73, 0x69,
+	0x67, 0x6e, 0x65, 0x64, 0x41, 0x74, 0x42, 0x27, 0x5a, 0x25, 0x73, 0x63, 0x72, 0x6f, 0x6c, 0x6c,
+	0x2d, 0x74, 0x65, 0x63, 0x68, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2f, 0x74, 0x79, 0x70,
+	0x65, 0x73, 0x2f, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x2f, 0x70, 0x62, 0x3b, 0x70, 0x62, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_common_types_schema_schema_proto_rawDescOnce sync.Once
+	file_common_types_schema_schema_proto_rawDescData = file_common_types_schema_schema_proto_rawDesc
+)
+
+func file_common_types_schema_schema_proto_rawDescGZIP() []byte {
+	file_common_types_schema_schema_proto_rawDescOnce.Do(func() {
+		file_common_types_schema_schema_proto_rawDescData = protoimpl.X.CompressGZIP(file_common_types_schema_schema_proto_rawDescData)
+	})
+	return file_common_types_schema_schema_proto_rawDescData
+}
+
+var file_common_types_schema_schema_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_common_types_schema_schema_proto_goTypes = []interface{}{
+	(*Chunk)(nil),       // 0: scroll.schema.v1.Chunk
+	(*Batch)(nil),       // 1: scroll.schema.v1.Batch
+	(*Bundle)(nil),      // 2: scroll.schema.v1.Bundle
+	(*ProvingTask)(nil), // 3: scroll.schema.v1.ProvingTask
+}
+var file_common_types_schema_schema_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_common_types_schema_schema_proto_init() }
+func file_common_types_schema_schema_proto_init() {
+	if File_common_types_schema_schema_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_common_types_schema_schema_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Chunk); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_common_types_schema_schema_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Batch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_common_types_schema_schema_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Bundle); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_common_types_schema_schema_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProvingTask); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_common_types_schema_schema_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_common_types_schema_schema_proto_goTypes,
+		DependencyIndexes: file_common_types_schema_schema_proto_depIdxs,
+		MessageInfos:      file_common_types_schema_schema_proto_msgTypes,
+	}.Build()
+	File_common_types_schema_schema_proto = out.File
+	file_common_types_schema_schema_proto_rawDesc = nil
+	file_common_types_schema_schema_proto_goTypes = nil
+	file_common_types_schema_schema_proto_depIdxs = nil
+}
@@ -0,0 +1,144 @@
+// Package schema defines the canonical, versioned serialization of the pipeline entities
+// (chunks, batches, bundles and proving tasks) exposed to external consumers, such as the inspect
+// and export tools of the rollup. Unlike the ORM structs, these schemas only change in a backward
+// compatible way within a major version. schema.proto holds the protobuf counterpart, generated
+// into the pb package.
+package schema
+
+//go:generate protoc -I ../../.. --go_out=../.. --go_opt=module=scroll-tech/common common/types/schema/schema.proto
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Version is the current version of the schemas.
+// The major version is bumped on breaking changes, the minor version on additions.
+const Version = "1.0"
+
+// majorVersion is the major part of Version.
+const majorVersion = "1"
+
+// Chunk is the canonical schema of a chunk of L2 blocks.
+type Chunk struct {
+	SchemaVersion string `json:"schema_version"`
+
+	Index                        uint64 `json:"index"`
+	Hash                         string `json:"hash"`
+	StartBlockNumber             uint64 `json:"start_block_number"`
+	StartBlockHash               string `json:"start_block_hash"`
+	EndBlockNumber               uint64 `json:"end_block_number"`
+	EndBlockHash                 string `json:"end_block_hash"`
+	StartBlockTime               uint64 `json:"start_block_time"`
+	TotalL1MessagesPoppedBefore  uint64 `json:"total_l1_messages_popped_before"`
+	TotalL1MessagesPoppedInChunk uint64 `json:"total_l1_messages_popped_in_chunk"`
+	ParentChunkHash              string `json:"parent_chunk_hash"`
+	StateRoot                    string `json:"state_root"`
+	ParentChunkStateRoot         string `json:"parent_chunk_state_root"`
+	WithdrawRoot                 string `json:"withdraw_root"`
+	TotalL2TxGas                 uint64 `json:"total_l2_tx_gas"`
+	TotalL2TxNum                 uint64 `json:"total_l2_tx_num"`
+	TotalL1CommitCalldataSize    uint64 `json:"total_l1_commit_calldata_size"`
+	TotalL1CommitGas             uint64 `json:"total_l1_commit_gas"`
+	CrcMax                       uint64 `json:"crc_max"`
+	BlobSize                     uint64 `json:"blob_size"`
+	// ProvingStatus is a types.ProvingStatus value.
+	ProvingStatus int32  `json:"proving_status"`
+	BatchHash     string `json:"batch_hash,omitempty"`
+	// CreatedAt is a unix timestamp in seconds.
+	CreatedAt int64 `json:"created_at"`
+}
+
+// Batch is the canonical schema of a batch of chunks.
+type Batch struct {
+	SchemaVersion string `json:"schema_version"`
+
+	Index                     uint64 `json:"index"`
+	Hash                      string `json:"hash"`
+	DataHash                  string `json:"data_hash"`
+	StartChunkIndex           uint64 `json:"start_chunk_index"`
+	StartChunkHash            string `json:"start_chunk_hash"`
+	EndChunkIndex             uint64 `json:"end_chunk_index"`
+	EndChunkHash              string `json:"end_chunk_hash"`
+	StateRoot                 string `json:"state_root"`
+	WithdrawRoot              string `json:"withdraw_root"`
+	ParentBatchHash           string `json:"parent_batch_hash"`
+	BatchHeader               []byte `json:"batch_header"`
+	TotalL1CommitCalldataSize uint64 `json:"total_l1_commit_calldata_size"`
+	TotalL1CommitGas          uint64 `json:"total_l1_commit_gas"`
+	BlobSize                  uint64 `json:"blob_size"`
+	// ChunkProofsStatus is a types.ChunkProofsStatus value.
+	ChunkProofsStatus int32 `json:"chunk_proofs_status"`
+	// ProvingStatus is a types.ProvingStatus value.
+	ProvingStatus int32 `json:"proving_status"`
+	// RollupStatus is a types.RollupStatus value.
+	RollupStatus   int32  `json:"rollup_status"`
+	CommitTxHash   string `json:"commit_tx_hash,omitempty"`
+	FinalizeTxHash string `json:"finalize_tx_hash,omitempty"`
+	// CreatedAt is a unix timestamp in seconds.
+	CreatedAt int64 `json:"created_at"`
+}
+
+// Bundle is the canonical schema of a bundle of batches finalized together on L1.
+type Bundle struct {
+	SchemaVersion string `json:"schema_version"`
+
+	Index           uint64 `json:"index"`
+	Hash            string `json:"hash"`
+	StartBatchIndex uint64 `json:"start_batch_index"`
+	StartBatchHash  string `json:"start_batch_hash"`
+	EndBatchIndex   uint64 `json:"end_batch_index"`
+	EndBatchHash    string `json:"end_batch_hash"`
+	// ProvingStatus is a types.ProvingStatus value.
+	ProvingStatus int32 `json:"proving_status"`
+	// RollupStatus is a types.RollupStatus value.
+	RollupStatus   int32  `json:"rollup_status"`
+	FinalizeTxHash string `json:"finalize_tx_hash,omitempty"`
+	// CreatedAt is a unix timestamp in seconds.
+	CreatedAt int64 `json:"created_at"`
+}
+
+// ProvingTask is the canonical schema of a proving task assigned to a prover.
+type ProvingTask struct {
+	SchemaVersion string `json:"schema_version"`
+
+	UUID   string `json:"uuid"`
+	TaskID string `json:"task_id"`
+	// TaskType is a message.ProofType value.
+	TaskType        int32  `json:"task_type"`
+	ProverPublicKey string `json:"prover_public_key"`
+	ProverName      string `json:"prover_name"`
+	ProverVersion   string `json:"prover_version"`
+	// ProvingStatus is a types.ProverProveStatus value.
+	ProvingStatus int32 `json:"proving_status"`
+	// FailureType is a types.ProverTaskFailureType value.
+	FailureType int32 `json:"failure_type"`
+	// Reward is a decimal string.
+	Reward string `json:"reward"`
+	// AssignedAt is a unix timestamp in seconds.
+	AssignedAt int64 `json:"assigned_at"`
+}
+
+// CheckVersion returns an error if the schema version is not compatible with Version.
+func CheckVersion(version string) error {
+	major, _, _ := strings.Cut(version, ".")
+	if major != majorVersion {
+		return fmt.Errorf("incompatible schema version: %q, expected major version %s", version, majorVersion)
+	}
+	return nil
+}
+
+// Unmarshal decodes a JSON encoded schema into v and checks its schema version.
+func Unmarshal(data []byte, v interface{}) error {
+	var header struct {
+		SchemaVersion string `json:"schema_version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return err
+	}
+	if err := CheckVersion(header.SchemaVersion); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
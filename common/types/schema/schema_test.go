@@ -0,0 +1,100 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+
+	"scroll-tech/common/types/schema/pb"
+)
+
+func TestCheckVersion(t *testing.T) {
+	assert.NoError(t, CheckVersion(Version))
+	assert.NoError(t, CheckVersion("1.5"))
+	assert.Error(t, CheckVersion("2.0"))
+	assert.Error(t, CheckVersion(""))
+}
+
+func TestUnmarshal(t *testing.T) {
+	chunk := &Chunk{SchemaVersion: Version, Index: 1, Hash: "0x01", StartBlockNumber: 1, EndBlockNumber: 2}
+	data, err := json.Marshal(chunk)
+	assert.NoError(t, err)
+
+	var decoded Chunk
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, chunk, &decoded)
+
+	assert.Error(t, Unmarshal([]byte(`{"schema_version":"2.0","index":1}`), &decoded))
+	assert.Error(t, Unmarshal([]byte(`{"index":1}`), &decoded))
+}
+
+func TestBatchJSON(t *testing.T) {
+	batch := &Batch{
+		SchemaVersion:   Version,
+		Index:           1,
+		Hash:            "0x01",
+		StartChunkIndex: 1,
+		EndChunkIndex:   2,
+		ParentBatchHash: "0x00",
+		BatchHeader:     []byte{0x01},
+		RollupStatus:    3,
+		CommitTxHash:    "0x02",
+		CreatedAt:       1700000000,
+	}
+	data, err := json.Marshal(batch)
+	assert.NoError(t, err)
+	// the field names are part of the schema and must not change within a major version.
+	assert.JSONEq(t, `{"schema_version":"1.0","index":1,"hash":"0x01","data_hash":"","start_chunk_index":1,"start_chunk_hash":"",
+		"end_chunk_index":2,"end_chunk_hash":"","state_root":"","withdraw_root":"","parent_batch_hash":"0x00","batch_header":"AQ==",
+		"total_l1_commit_calldata_size":0,"total_l1_commit_gas":0,"blob_size":0,"chunk_proofs_status":0,"proving_status":0,
+		"rollup_status":3,"commit_tx_hash":"0x02","created_at":1700000000}`, string(data))
+}
+
+func TestProvingTaskJSON(t *testing.T) {
+	task := &ProvingTask{
+		SchemaVersion:   Version,
+		UUID:            "00000000-0000-0000-0000-000000000001",
+		TaskID:          "0x01",
+		TaskType:        1,
+		ProverPublicKey: "0x02",
+		ProverName:      "prover-0",
+		ProverVersion:   "v4.0.0",
+		ProvingStatus:   2,
+		Reward:          "0",
+		AssignedAt:      1700000000,
+	}
+	data, err := json.Marshal(task)
+	assert.NoError(t, err)
+	// the field names are part of the schema and must not change within a major version.
+	assert.JSONEq(t, `{"schema_version":"1.0","uuid":"00000000-0000-0000-0000-000000000001","task_id":"0x01","task_type":1,
+		"prover_public_key":"0x02","prover_name":"prover-0","prover_version":"v4.0.0","proving_status":2,"failure_type":0,
+		"reward":"0","assigned_at":1700000000}`, string(data))
+}
+
+func TestProtoSchemas(t *testing.T) {
+	schemas := []struct {
+		json  interface{}
+		proto proto.Message
+	}{
+		{Chunk{}, &pb.Chunk{}},
+		{Batch{}, &pb.Batch{}},
+		{Bundle{}, &pb.Bundle{}},
+		{ProvingTask{}, &pb.ProvingTask{}},
+	}
+	// the protobuf messages declare the fields of the JSON schemas, with the same names and in the same order.
+	for _, s := range schemas {
+		jsonType := reflect.TypeOf(s.json)
+		fields := s.proto.ProtoReflect().Descriptor().Fields()
+		if !assert.Equal(t, jsonType.NumField(), fields.Len(), jsonType.Name()) {
+			continue
+		}
+		for i := 0; i < jsonType.NumField(); i++ {
+			name, _, _ := strings.Cut(jsonType.Field(i).Tag.Get("json"), ",")
+			assert.Equal(t, name, string(fields.Get(i).Name()), jsonType.Name())
+		}
+	}
+}
@@ -54,6 +54,59 @@ func Loop(ctx context.Context, period time.Duration, f func()) {
 	}
 }
 
+// LoopWithAdaptiveInterval runs the f func periodically with an adaptive period:
+// the period is reset to minPeriod whenever f returns true (i.e. it made progress and there may be more work),
+// and doubles up to maxPeriod while f returns false. A random delay in [0, jitter) is added to every period.
+func LoopWithAdaptiveInterval(ctx context.Context, minPeriod, maxPeriod, jitter time.Duration, f func() bool) {
+	if maxPeriod < minPeriod {
+		maxPeriod = minPeriod
+	}
+	period := minPeriod
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		if f() {
+			period = minPeriod
+		} else {
+			period = nextAdaptivePeriod(period, minPeriod, maxPeriod)
+		}
+
+		timer := time.NewTimer(period + randomDuration(jitter))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+func nextAdaptivePeriod(period, minPeriod, maxPeriod time.Duration) time.Duration {
+	if period < minPeriod {
+		period = minPeriod
+	}
+	if period == 0 || period > maxPeriod/2 {
+		return maxPeriod
+	}
+	return period * 2
+}
+
+// randomDuration returns a random duration in [0, upperBound).
+func randomDuration(upperBound time.Duration) time.Duration {
+	if upperBound <= 0 {
+		return 0
+	}
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(upperBound)))
+	if err != nil {
+		return 0
+	}
+	return time.Duration(n.Int64())
+}
+
 // IsNil Check if the interface is empty.
 func IsNil(i interface{}) bool {
 	return i == nil || reflect2.IsNil(i)
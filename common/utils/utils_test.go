@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextAdaptivePeriod(t *testing.T) {
+	minPeriod, maxPeriod := time.Second, 5*time.Second
+	assert.Equal(t, 2*time.Second, nextAdaptivePeriod(time.Second, minPeriod, maxPeriod))
+	assert.Equal(t, 4*time.Second, nextAdaptivePeriod(2*time.Second, minPeriod, maxPeriod))
+	assert.Equal(t, 5*time.Second, nextAdaptivePeriod(4*time.Second, minPeriod, maxPeriod))
+	assert.Equal(t, 5*time.Second, nextAdaptivePeriod(5*time.Second, minPeriod, maxPeriod))
+	assert.Equal(t, 2*time.Second, nextAdaptivePeriod(0, minPeriod, maxPeriod))
+	assert.Equal(t, time.Duration(0), nextAdaptivePeriod(0, 0, 0))
+}
+
+func TestRandomDuration(t *testing.T) {
+	assert.Equal(t, time.Duration(0), randomDuration(0))
+	for i := 0; i < 100; i++ {
+		d := randomDuration(time.Millisecond)
+		assert.GreaterOrEqual(t, d, time.Duration(0))
+		assert.Less(t, d, time.Millisecond)
+	}
+}
+
+func TestLoopWithAdaptiveInterval(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var calls int
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		LoopWithAdaptiveInterval(ctx, time.Millisecond, 10*time.Millisecond, time.Millisecond, func() bool {
+			calls++
+			if calls == 5 {
+				cancel()
+			}
+			// pretend there is a backlog for the first few calls
+			return calls < 3
+		})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("LoopWithAdaptiveInterval did not stop after context cancellation")
+	}
+	assert.Equal(t, 5, calls)
+}
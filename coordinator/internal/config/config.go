@@ -40,12 +40,26 @@ type Auth struct {
 	LoginExpireDurationSec     int    `json:"login_expire_duration_sec"`
 }
 
+// ProverSLA configures the bookkeeping of missed deadlines and invalid proofs of external prover operators.
+type ProverSLA struct {
+	// Enabled turns on the periodic violation reports.
+	Enabled bool `json:"enabled"`
+	// ReportIntervalSec is the period covered by each report (in seconds). A report covering the downtime of the
+	// coordinator is sent once it is restarted.
+	ReportIntervalSec int `json:"report_interval_sec"`
+	// ReportDir is the directory the JSON reports are exported to, no export if empty.
+	ReportDir string `json:"report_dir"`
+	// WebhookURL receives each report as a JSON POST request, no notification if empty.
+	WebhookURL string `json:"webhook_url"`
+}
+
 // Config load configuration items.
 type Config struct {
 	ProverManager *ProverManager   `json:"prover_manager"`
 	DB            *database.Config `json:"db"`
 	L2            *L2              `json:"l2"`
 	Auth          *Auth            `json:"auth"`
+	ProverSLA     *ProverSLA       `json:"prover_sla,omitempty"`
 }
 
 // VerifierConfig load zk verifier config.
@@ -27,11 +27,14 @@ type Collector struct {
 	stopBatchTimeoutChan       chan struct{}
 	stopBatchAllChunkReadyChan chan struct{}
 	stopCleanChallengeChan     chan struct{}
+	stopProverSLAReportChan    chan struct{}
 
-	proverTaskOrm *orm.ProverTask
-	chunkOrm      *orm.Chunk
-	batchOrm      *orm.Batch
-	challenge     *orm.Challenge
+	proverTaskOrm         *orm.ProverTask
+	chunkOrm              *orm.Chunk
+	batchOrm              *orm.Batch
+	challenge             *orm.Challenge
+	proverSLAViolationOrm *orm.ProverSLAViolation
+	proverSLAReportOrm    *orm.ProverSLAReport
 
 	timeoutBatchCheckerRunTotal     prometheus.Counter
 	batchProverTaskTimeoutTotal     prometheus.Counter
@@ -50,10 +53,13 @@ func NewCollector(ctx context.Context, db *gorm.DB, cfg *config.Config, reg prom
 		stopBatchTimeoutChan:       make(chan struct{}),
 		stopBatchAllChunkReadyChan: make(chan struct{}),
 		stopCleanChallengeChan:     make(chan struct{}),
+		stopProverSLAReportChan:    make(chan struct{}),
 		proverTaskOrm:              orm.NewProverTask(db),
 		chunkOrm:                   orm.NewChunk(db),
 		batchOrm:                   orm.NewBatch(db),
 		challenge:                  orm.NewChallenge(db),
+		proverSLAViolationOrm:      orm.NewProverSLAViolation(db),
+		proverSLAReportOrm:         orm.NewProverSLAReport(db),
 
 		timeoutBatchCheckerRunTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
 			Name: "coordinator_batch_timeout_checker_run_total",
@@ -81,6 +87,9 @@ func NewCollector(ctx context.Context, db *gorm.DB, cfg *config.Config, reg prom
 	go c.timeoutChunkProofTask()
 	go c.checkBatchAllChunkReady()
 	go c.cleanupChallenge()
+	if cfg.ProverSLA != nil && cfg.ProverSLA.Enabled {
+		go c.reportProverSLAViolations()
+	}
 
 	log.Info("Start coordinator cron successfully.")
 
@@ -93,6 +102,9 @@ func (c *Collector) Stop() {
 	c.stopBatchTimeoutChan <- struct{}{}
 	c.stopBatchAllChunkReadyChan <- struct{}{}
 	c.stopCleanChallengeChan <- struct{}{}
+	if c.cfg.ProverSLA != nil && c.cfg.ProverSLA.Enabled {
+		c.stopProverSLAReportChan <- struct{}{}
+	}
 }
 
 // timeoutBatchProofTask cron check the send task is timeout. if timeout reached, restore the
@@ -181,6 +193,11 @@ func (c *Collector) check(assignedProverTasks []orm.ProverTask, timeout promethe
 				return err
 			}
 
+			if err := c.proverSLAViolationOrm.InsertProverSLAViolation(c.ctx, &assignedProverTask, types.ProverSLAViolationTypeMissedDeadline, tx); err != nil {
+				log.Error("insert prover sla violation failure", "uuid", assignedProverTask.UUID, "hash", assignedProverTask.TaskID, "pubKey", assignedProverTask.ProverPublicKey, "err", err)
+				return err
+			}
+
 			switch message.ProofType(assignedProverTask.TaskType) {
 			case message.ProofTypeChunk:
 				if err := c.chunkOrm.DecreaseActiveAttemptsByHash(c.ctx, assignedProverTask.TaskID, tx); err != nil {
@@ -0,0 +1,158 @@
+package cron
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/common/types"
+	"scroll-tech/common/utils"
+)
+
+const defaultProverSLAReportIntervalSec = 24 * 60 * 60
+
+// proverSLAReportRetryInterval is the delay before retrying a failed report.
+const proverSLAReportRetryInterval = time.Minute
+
+// ProverSLAReport is the per operator summary of the prover SLA violations in a period.
+type ProverSLAReport struct {
+	Since     time.Time                  `json:"since"`
+	Until     time.Time                  `json:"until"`
+	Operators []*ProverSLAOperatorReport `json:"operators"`
+}
+
+// ProverSLAOperatorReport is the number of prover SLA violations of an operator.
+type ProverSLAOperatorReport struct {
+	ProverPublicKey string `json:"prover_public_key"`
+	ProverName      string `json:"prover_name"`
+	MissedDeadlines uint64 `json:"missed_deadlines"`
+	InvalidProofs   uint64 `json:"invalid_proofs"`
+}
+
+// reportProverSLAViolations reports the violations every interval. The reports are recorded in the database, and
+// each report starts where the latest one ended, so that the violations recorded while the coordinator was down
+// or before a restart are reported too.
+func (c *Collector) reportProverSLAViolations() {
+	defer func() {
+		if err := recover(); err != nil {
+			nerr := fmt.Errorf("report prover sla violations panic error: %v", err)
+			log.Warn(nerr.Error())
+		}
+	}()
+
+	interval := time.Duration(c.cfg.ProverSLA.ReportIntervalSec) * time.Second
+	if interval <= 0 {
+		interval = defaultProverSLAReportIntervalSec * time.Second
+	}
+
+	// since is the start of the next report, unknown until the latest report is loaded.
+	var since time.Time
+	// until is the end of the report being delivered, kept across its retries so that the window is delivered under
+	// a stable name.
+	var until time.Time
+	next := utils.NowUTC()
+	for {
+		select {
+		case <-time.After(time.Until(next)):
+		case <-c.ctx.Done():
+			if c.ctx.Err() != nil {
+				log.Error("manager context canceled with error", "error", c.ctx.Err())
+			}
+			return
+		case <-c.stopProverSLAReportChan:
+			log.Info("the coordinator reportProverSLAViolations run loop exit")
+			return
+		}
+
+		if since.IsZero() {
+			latest, err := c.proverSLAReportOrm.GetLatestProverSLAReportUntil(c.ctx)
+			if err != nil {
+				log.Error("failed to get the latest prover sla report", "error", err)
+				next = utils.NowUTC().Add(proverSLAReportRetryInterval)
+				continue
+			}
+			since = latest
+			if since.IsZero() {
+				since = utils.NowUTC()
+			}
+			next = since.Add(interval)
+			continue
+		}
+
+		if until.IsZero() {
+			until = utils.NowUTC()
+		}
+		if err := c.reportProverSLAViolationsInRange(since, until); err != nil {
+			log.Error("report prover sla violations failure", "since", since, "until", until, "error", err)
+			next = utils.NowUTC().Add(proverSLAReportRetryInterval)
+			continue
+		}
+		since, until = until, time.Time{}
+		next = since.Add(interval)
+	}
+}
+
+func (c *Collector) reportProverSLAViolationsInRange(since, until time.Time) error {
+	summaries, err := c.proverSLAViolationOrm.GetProverSLAViolationSummaries(c.ctx, since, until)
+	if err != nil {
+		return err
+	}
+
+	report := &ProverSLAReport{Since: since, Until: until, Operators: []*ProverSLAOperatorReport{}}
+	operators := make(map[string]*ProverSLAOperatorReport)
+	for _, summary := range summaries {
+		operator, ok := operators[summary.ProverPublicKey]
+		if !ok {
+			operator = &ProverSLAOperatorReport{ProverPublicKey: summary.ProverPublicKey, ProverName: summary.ProverName}
+			operators[summary.ProverPublicKey] = operator
+			report.Operators = append(report.Operators, operator)
+		}
+		switch types.ProverSLAViolationType(summary.ViolationType) {
+		case types.ProverSLAViolationTypeMissedDeadline:
+			operator.MissedDeadlines += summary.Count
+		case types.ProverSLAViolationTypeInvalidProof:
+			operator.InvalidProofs += summary.Count
+		}
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal prover sla report: %w", err)
+	}
+
+	if c.cfg.ProverSLA.ReportDir != "" {
+		if err := os.MkdirAll(c.cfg.ProverSLA.ReportDir, 0750); err != nil {
+			return fmt.Errorf("failed to create prover sla report dir: %w", err)
+		}
+		file := filepath.Join(c.cfg.ProverSLA.ReportDir, fmt.Sprintf("prover_sla_report_%d_%d.json", since.Unix(), until.Unix()))
+		if err := os.WriteFile(file, data, 0600); err != nil {
+			return fmt.Errorf("failed to write prover sla report: %w", err)
+		}
+	}
+
+	if c.cfg.ProverSLA.WebhookURL != "" {
+		resp, err := resty.New().SetTimeout(30*time.Second).R().
+			SetContext(c.ctx).
+			SetHeader("Content-Type", "application/json").
+			SetBody(data).
+			Post(c.cfg.ProverSLA.WebhookURL)
+		if err != nil {
+			return fmt.Errorf("failed to notify prover sla report webhook: %w", err)
+		}
+		if resp.IsError() {
+			return fmt.Errorf("prover sla report webhook returned status %d", resp.StatusCode())
+		}
+	}
+
+	if err := c.proverSLAReportOrm.InsertProverSLAReport(c.ctx, since, until, len(report.Operators)); err != nil {
+		return err
+	}
+
+	log.Info("reported prover sla violations", "since", since, "until", until, "operators", len(report.Operators))
+	return nil
+}
@@ -44,9 +44,10 @@ var (
 
 // ProofReceiverLogic the proof receiver logic
 type ProofReceiverLogic struct {
-	chunkOrm      *orm.Chunk
-	batchOrm      *orm.Batch
-	proverTaskOrm *orm.ProverTask
+	chunkOrm              *orm.Chunk
+	batchOrm              *orm.Batch
+	proverTaskOrm         *orm.ProverTask
+	proverSLAViolationOrm *orm.ProverSLAViolation
 
 	db  *gorm.DB
 	cfg *config.ProverManager
@@ -68,9 +69,10 @@ type ProofReceiverLogic struct {
 // NewSubmitProofReceiverLogic create a proof receiver logic
 func NewSubmitProofReceiverLogic(cfg *config.ProverManager, db *gorm.DB, vf *verifier.Verifier, reg prometheus.Registerer) *ProofReceiverLogic {
 	return &ProofReceiverLogic{
-		chunkOrm:      orm.NewChunk(db),
-		batchOrm:      orm.NewBatch(db),
-		proverTaskOrm: orm.NewProverTask(db),
+		chunkOrm:              orm.NewChunk(db),
+		batchOrm:              orm.NewBatch(db),
+		proverTaskOrm:         orm.NewProverTask(db),
+		proverSLAViolationOrm: orm.NewProverSLAViolation(db),
 
 		cfg: cfg,
 		db:  db,
@@ -177,6 +179,10 @@ func (m *ProofReceiverLogic) HandleZkProof(ctx *gin.Context, proofMsg *message.P
 
 		m.proofRecover(ctx.Copy(), proverTask, types.ProverTaskFailureTypeVerifiedFailed, proofMsg)
 
+		if err := m.proverSLAViolationOrm.InsertProverSLAViolation(ctx.Copy(), proverTask, types.ProverSLAViolationTypeInvalidProof); err != nil {
+			log.Error("insert prover sla violation failure", "uuid", proverTask.UUID, "taskID", proofMsg.ID, "pubKey", pk, "error", err)
+		}
+
 		log.Info("proof verified by coordinator failed", "proof id", proofMsg.ID, "prover name", proverTask.ProverName,
 			"prover pk", pk, "forkName", hardForkName, "prove type", proofMsg.Type, "proof time", proofTimeSec, "error", verifyErr)
 
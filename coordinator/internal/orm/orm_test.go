@@ -4,6 +4,7 @@ import (
 	"context"
 	"math/big"
 	"testing"
+	"time"
 
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
@@ -17,9 +18,11 @@ import (
 )
 
 var (
-	testApps      *testcontainers.TestcontainerApps
-	db            *gorm.DB
-	proverTaskOrm *ProverTask
+	testApps              *testcontainers.TestcontainerApps
+	db                    *gorm.DB
+	proverTaskOrm         *ProverTask
+	proverSLAViolationOrm *ProverSLAViolation
+	proverSLAReportOrm    *ProverSLAReport
 )
 
 func TestMain(m *testing.M) {
@@ -45,6 +48,8 @@ func setupEnv(t *testing.T) {
 	assert.NoError(t, migrate.ResetDB(sqlDB))
 
 	proverTaskOrm = NewProverTask(db)
+	proverSLAViolationOrm = NewProverSLAViolation(db)
+	proverSLAReportOrm = NewProverSLAReport(db)
 }
 
 func tearDownEnv(t *testing.T) {
@@ -120,3 +125,63 @@ func TestProverTaskOrmUint256(t *testing.T) {
 	assert.Equal(t, resultRewardUint256, rewardUint256)
 	assert.Equal(t, resultRewardUint256.String(), "115792089237316195423570985008687907853269984665640564039457584007913129639935")
 }
+
+func TestProverSLAViolationOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	proverTask := ProverTask{
+		TaskType:        int16(message.ProofTypeBatch),
+		TaskID:          "test-hash",
+		ProverName:      "prover-0",
+		ProverPublicKey: "0",
+		ProvingStatus:   int16(types.ProverAssigned),
+		AssignedAt:      utils.NowUTC(),
+	}
+	err = proverTaskOrm.InsertProverTask(context.Background(), &proverTask)
+	assert.NoError(t, err)
+
+	since := time.Now().Add(-time.Hour)
+	err = proverSLAViolationOrm.InsertProverSLAViolation(context.Background(), &proverTask, types.ProverSLAViolationTypeInvalidProof)
+	assert.NoError(t, err)
+	// the same violation of a prover task is only recorded once.
+	err = proverSLAViolationOrm.InsertProverSLAViolation(context.Background(), &proverTask, types.ProverSLAViolationTypeInvalidProof)
+	assert.NoError(t, err)
+	err = proverSLAViolationOrm.InsertProverSLAViolation(context.Background(), &proverTask, types.ProverSLAViolationTypeMissedDeadline)
+	assert.NoError(t, err)
+
+	summaries, err := proverSLAViolationOrm.GetProverSLAViolationSummaries(context.Background(), since, time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, summaries, 2)
+	assert.Equal(t, "0", summaries[0].ProverPublicKey)
+	assert.Equal(t, "prover-0", summaries[0].ProverName)
+	assert.Equal(t, int16(types.ProverSLAViolationTypeMissedDeadline), summaries[0].ViolationType)
+	assert.Equal(t, uint64(1), summaries[0].Count)
+	assert.Equal(t, int16(types.ProverSLAViolationTypeInvalidProof), summaries[1].ViolationType)
+	assert.Equal(t, uint64(1), summaries[1].Count)
+
+	summaries, err = proverSLAViolationOrm.GetProverSLAViolationSummaries(context.Background(), since.Add(-time.Hour), since)
+	assert.NoError(t, err)
+	assert.Len(t, summaries, 0)
+}
+
+func TestProverSLAReportOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	until, err := proverSLAReportOrm.GetLatestProverSLAReportUntil(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, until.IsZero())
+
+	since := utils.NowUTC().Add(-2 * time.Hour).Truncate(time.Second)
+	err = proverSLAReportOrm.InsertProverSLAReport(context.Background(), since, since.Add(time.Hour), 1)
+	assert.NoError(t, err)
+	err = proverSLAReportOrm.InsertProverSLAReport(context.Background(), since.Add(time.Hour), since.Add(2*time.Hour), 0)
+	assert.NoError(t, err)
+
+	until, err = proverSLAReportOrm.GetLatestProverSLAReportUntil(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, since.Add(2*time.Hour).Equal(until))
+}
@@ -0,0 +1,69 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ProverSLAReport records a sent prover SLA report, so that the next report starts where it ended.
+type ProverSLAReport struct {
+	db *gorm.DB `gorm:"-"`
+
+	ID int64 `json:"id" gorm:"column:id;primaryKey"`
+
+	// report
+	Since     time.Time `json:"since" gorm:"column:since"`
+	Until     time.Time `json:"until" gorm:"column:until"`
+	Operators int       `json:"operators" gorm:"column:operators"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewProverSLAReport creates a new ProverSLAReport instance.
+func NewProverSLAReport(db *gorm.DB) *ProverSLAReport {
+	return &ProverSLAReport{db: db}
+}
+
+// TableName returns the name of the "prover_sla_report" table.
+func (*ProverSLAReport) TableName() string {
+	return "prover_sla_report"
+}
+
+// InsertProverSLAReport records a report of the time range [since, until).
+func (o *ProverSLAReport) InsertProverSLAReport(ctx context.Context, since, until time.Time, operators int) error {
+	report := ProverSLAReport{
+		Since:     since,
+		Until:     until,
+		Operators: operators,
+	}
+
+	db := o.db.WithContext(ctx)
+	db = db.Model(&ProverSLAReport{})
+	if err := db.Create(&report).Error; err != nil {
+		return fmt.Errorf("ProverSLAReport.InsertProverSLAReport error: %w, since: %v, until: %v", err, since, until)
+	}
+	return nil
+}
+
+// GetLatestProverSLAReportUntil returns the end of the time range of the latest report, or the zero time if there is none.
+func (o *ProverSLAReport) GetLatestProverSLAReportUntil(ctx context.Context) (time.Time, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&ProverSLAReport{})
+	db = db.Order("until desc")
+
+	var report ProverSLAReport
+	if err := db.First(&report).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("ProverSLAReport.GetLatestProverSLAReportUntil error: %w", err)
+	}
+	return report.Until, nil
+}
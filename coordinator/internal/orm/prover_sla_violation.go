@@ -0,0 +1,101 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"scroll-tech/common/types"
+)
+
+// ProverSLAViolation records a missed deadline or an invalid proof of a prover task,
+// as input to the penalty processes of external prover operators.
+type ProverSLAViolation struct {
+	db *gorm.DB `gorm:"-"`
+
+	ID int64 `json:"id" gorm:"column:id;primaryKey"`
+
+	// prover
+	ProverPublicKey string `json:"prover_public_key" gorm:"column:prover_public_key"`
+	ProverName      string `json:"prover_name" gorm:"column:prover_name"`
+	ProverVersion   string `json:"prover_version" gorm:"column:prover_version"`
+
+	// task
+	TaskID         string    `json:"task_id" gorm:"column:task_id"`
+	TaskType       int16     `json:"task_type" gorm:"column:task_type"`
+	ProverTaskUUID uuid.UUID `json:"prover_task_uuid" gorm:"column:prover_task_uuid;type:uuid"`
+	ViolationType  int16     `json:"violation_type" gorm:"column:violation_type"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// ProverSLAViolationSummary is the number of violations of a prover per violation type.
+type ProverSLAViolationSummary struct {
+	ProverPublicKey string `json:"prover_public_key" gorm:"column:prover_public_key"`
+	ProverName      string `json:"prover_name" gorm:"column:prover_name"`
+	ViolationType   int16  `json:"violation_type" gorm:"column:violation_type"`
+	Count           uint64 `json:"count" gorm:"column:count"`
+}
+
+// NewProverSLAViolation creates a new ProverSLAViolation instance.
+func NewProverSLAViolation(db *gorm.DB) *ProverSLAViolation {
+	return &ProverSLAViolation{db: db}
+}
+
+// TableName returns the name of the "prover_sla_violation" table.
+func (*ProverSLAViolation) TableName() string {
+	return "prover_sla_violation"
+}
+
+// InsertProverSLAViolation records a violation of the given prover task.
+// A prover task is recorded at most once per violation type.
+func (o *ProverSLAViolation) InsertProverSLAViolation(ctx context.Context, proverTask *ProverTask, violationType types.ProverSLAViolationType, dbTX ...*gorm.DB) error {
+	violation := ProverSLAViolation{
+		ProverPublicKey: proverTask.ProverPublicKey,
+		ProverName:      proverTask.ProverName,
+		ProverVersion:   proverTask.ProverVersion,
+		TaskID:          proverTask.TaskID,
+		TaskType:        proverTask.TaskType,
+		ProverTaskUUID:  proverTask.UUID,
+		ViolationType:   int16(violationType),
+	}
+
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&ProverSLAViolation{})
+	db = db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "prover_task_uuid"}, {Name: "violation_type"}},
+		Where:     clause.Where{Exprs: []clause.Expression{clause.Eq{Column: "deleted_at", Value: nil}}},
+		DoNothing: true,
+	})
+	if err := db.Create(&violation).Error; err != nil {
+		return fmt.Errorf("ProverSLAViolation.InsertProverSLAViolation error: %w, prover task uuid: %v, violation type: %v", err, proverTask.UUID, violationType.String())
+	}
+	return nil
+}
+
+// GetProverSLAViolationSummaries returns the number of violations per prover and violation type
+// recorded in the time range [since, until).
+func (o *ProverSLAViolation) GetProverSLAViolationSummaries(ctx context.Context, since, until time.Time) ([]*ProverSLAViolationSummary, error) {
+	var summaries []*ProverSLAViolationSummary
+	db := o.db.WithContext(ctx)
+	db = db.Model(&ProverSLAViolation{})
+	db = db.Select("prover_public_key, MAX(prover_name) AS prover_name, violation_type, COUNT(*) AS count")
+	db = db.Where("created_at >= ? AND created_at < ?", since, until)
+	db = db.Group("prover_public_key, violation_type")
+	db = db.Order("prover_public_key ASC, violation_type ASC")
+	if err := db.Scan(&summaries).Error; err != nil {
+		return nil, fmt.Errorf("ProverSLAViolation.GetProverSLAViolationSummaries error: %w, since: %v, until: %v", err, since, until)
+	}
+	return summaries, nil
+}
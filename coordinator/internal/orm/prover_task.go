@@ -12,6 +12,7 @@ import (
 
 	"scroll-tech/common/types"
 	"scroll-tech/common/types/message"
+	"scroll-tech/common/types/schema"
 	"scroll-tech/common/utils"
 )
 
@@ -49,6 +50,23 @@ func NewProverTask(db *gorm.DB) *ProverTask {
 	return &ProverTask{db: db}
 }
 
+// ToSchema converts the prover task to its canonical schema.
+func (o *ProverTask) ToSchema() *schema.ProvingTask {
+	return &schema.ProvingTask{
+		SchemaVersion:   schema.Version,
+		UUID:            o.UUID.String(),
+		TaskID:          o.TaskID,
+		TaskType:        int32(o.TaskType),
+		ProverPublicKey: o.ProverPublicKey,
+		ProverName:      o.ProverName,
+		ProverVersion:   o.ProverVersion,
+		ProvingStatus:   int32(o.ProvingStatus),
+		FailureType:     int32(o.FailureType),
+		Reward:          o.Reward.String(),
+		AssignedAt:      o.AssignedAt.Unix(),
+	}
+}
+
 // TableName returns the name of the "prover_task" table.
 func (*ProverTask) TableName() string {
 	return "prover_task"
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE l1_message
+ADD COLUMN is_enforced BOOLEAN NOT NULL DEFAULT FALSE;
+
+create index if not exists l1_message_is_enforced_index
+on l1_message (is_enforced) where deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+drop index if exists l1_message_is_enforced_index;
+
+ALTER TABLE IF EXISTS l1_message
+DROP COLUMN is_enforced;
+
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE prover_sla_violation
+(
+    id                 BIGSERIAL    PRIMARY KEY,
+
+    prover_public_key  VARCHAR      NOT NULL,
+    prover_name        VARCHAR      NOT NULL,
+    prover_version     VARCHAR      NOT NULL,
+
+    task_id            VARCHAR      NOT NULL,
+    task_type          SMALLINT     NOT NULL,
+    prover_task_uuid   UUID         NOT NULL,
+    violation_type     SMALLINT     NOT NULL,
+
+    created_at         TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at         TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at         TIMESTAMP(0) DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX if not exists uniq_prover_sla_violation_prover_task_uuid_violation_type ON prover_sla_violation(prover_task_uuid, violation_type) where deleted_at IS NULL;
+CREATE INDEX if not exists idx_prover_sla_violation_created_at ON prover_sla_violation(created_at) where deleted_at IS NULL;
+CREATE INDEX if not exists idx_prover_sla_violation_prover_public_key ON prover_sla_violation(prover_public_key) where deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS prover_sla_violation;
+-- +goose StatementEnd
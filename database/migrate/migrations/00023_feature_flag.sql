@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE feature_flag
+(
+    name               VARCHAR      NOT NULL,
+    enabled            BOOLEAN      NOT NULL DEFAULT FALSE,
+    rollout_percentage SMALLINT     NOT NULL DEFAULT 100,
+
+    created_at         TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at         TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at         TIMESTAMP(0) DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX if not exists uniq_feature_flag_name ON feature_flag(name) where deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS feature_flag;
+-- +goose StatementEnd
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE chunk
+ADD COLUMN codec_version SMALLINT NOT NULL DEFAULT -1;
+
+comment
+on column chunk.codec_version is 'codec version of the chunk, -1 for the chunks proposed before the column is added';
+
+CREATE TABLE stale_chunk
+(
+    id                 BIGSERIAL    PRIMARY KEY,
+
+    chunk_index        BIGINT       NOT NULL,
+    chunk_hash         VARCHAR      NOT NULL,
+    start_block_number BIGINT       NOT NULL,
+    end_block_number   BIGINT       NOT NULL,
+    reason             VARCHAR      NOT NULL,
+
+    created_at         TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at         TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at         TIMESTAMP(0) DEFAULT NULL
+);
+
+CREATE INDEX if not exists idx_stale_chunk_chunk_index ON stale_chunk(chunk_index) where deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS stale_chunk;
+
+ALTER TABLE IF EXISTS chunk
+DROP COLUMN codec_version;
+
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE proposer_pause
+(
+    proposer           VARCHAR      NOT NULL,
+    paused             BOOLEAN      NOT NULL DEFAULT FALSE,
+    reason             VARCHAR      NOT NULL DEFAULT '',
+
+    created_at         TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at         TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at         TIMESTAMP(0) DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX if not exists uniq_proposer_pause_proposer ON proposer_pause(proposer) where deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS proposer_pause;
+-- +goose StatementEnd
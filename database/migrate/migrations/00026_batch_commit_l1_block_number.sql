@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE batch
+ADD COLUMN commit_l1_block_number BIGINT NOT NULL DEFAULT 0;
+
+comment
+on column batch.commit_l1_block_number is 'number of the L1 block including the commit transaction of the batch, 0 if unknown';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE IF EXISTS batch
+DROP COLUMN commit_l1_block_number;
+
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE proposer_state
+(
+    proposer           VARCHAR      NOT NULL,
+    state              TEXT         NOT NULL DEFAULT '',
+
+    created_at         TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at         TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at         TIMESTAMP(0) DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX if not exists uniq_proposer_state_proposer ON proposer_state(proposer) where deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS proposer_state;
+-- +goose StatementEnd
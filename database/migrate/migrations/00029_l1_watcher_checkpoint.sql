@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE l1_watcher_checkpoint
+(
+    event_filter       VARCHAR      NOT NULL,
+    processed_height   BIGINT       NOT NULL DEFAULT 0,
+    block_hash         VARCHAR      NOT NULL DEFAULT '',
+
+    created_at         TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at         TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at         TIMESTAMP(0) DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX if not exists uniq_l1_watcher_checkpoint_event_filter ON l1_watcher_checkpoint(event_filter) where deleted_at IS NULL;
+
+comment
+on column l1_watcher_checkpoint.block_hash is 'hash of the block at processed_height, empty if unknown';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS l1_watcher_checkpoint;
+-- +goose StatementEnd
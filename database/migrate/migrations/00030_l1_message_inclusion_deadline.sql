@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE l1_message
+ADD COLUMN inclusion_deadline BIGINT NOT NULL DEFAULT 0;
+
+comment
+on column l1_message.inclusion_deadline is 'unix time before which an enforced transaction must be included, 0 if none';
+
+create index if not exists l1_message_enforced_inclusion_deadline_index
+on l1_message (inclusion_deadline) where is_enforced = TRUE and deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS l1_message_enforced_inclusion_deadline_index;
+
+ALTER TABLE IF EXISTS l1_message
+DROP COLUMN inclusion_deadline;
+
+-- +goose StatementEnd
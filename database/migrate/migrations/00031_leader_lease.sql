@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE leader_lease
+(
+    name               VARCHAR      NOT NULL,
+    holder             VARCHAR      NOT NULL,
+    expires_at         TIMESTAMP(0) NOT NULL,
+
+    created_at         TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at         TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at         TIMESTAMP(0) DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX if not exists uniq_leader_lease_name ON leader_lease(name) where deleted_at IS NULL;
+
+comment
+on column leader_lease.holder is 'instance id of the current leader';
+
+comment
+on column leader_lease.expires_at is 'the lease can be taken over by another instance after this time';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS leader_lease;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE l2_block_quarantine
+(
+    number             BIGINT       NOT NULL,
+    status             VARCHAR      NOT NULL,
+    attempts           BIGINT       NOT NULL DEFAULT 0,
+    last_error         TEXT         NOT NULL DEFAULT '',
+
+    created_at         TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at         TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at         TIMESTAMP(0) DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX if not exists uniq_l2_block_quarantine_number ON l2_block_quarantine(number) where deleted_at IS NULL;
+
+comment
+on column l2_block_quarantine.status is 'quarantined, retry, resolved';
+
+comment
+on column l2_block_quarantine.attempts is 'number of consecutive failures to fetch or validate the block';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS l2_block_quarantine;
+-- +goose StatementEnd
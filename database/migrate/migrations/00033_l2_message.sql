@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+
+create table l2_message
+(
+    message_index    BIGINT           NOT NULL,
+    msg_hash         VARCHAR          NOT NULL,
+    height           BIGINT           NOT NULL,
+    layer2_hash      VARCHAR          NOT NULL,
+    sender           VARCHAR          NOT NULL DEFAULT '',
+    target           VARCHAR          NOT NULL DEFAULT '',
+    value            VARCHAR          NOT NULL DEFAULT '',
+    gas_limit        BIGINT           NOT NULL DEFAULT 0,
+    calldata         TEXT             NOT NULL DEFAULT '',
+
+-- metadata
+    created_at       TIMESTAMP(0)     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at       TIMESTAMP(0)     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at       TIMESTAMP(0)     DEFAULT NULL
+);
+
+comment
+on column l2_message.message_index is 'index of the message in the withdraw trie of the L2MessageQueue, also the message nonce';
+
+comment
+on column l2_message.height is 'number of the l2 block which sent the message';
+
+create unique index l2_message_index_uindex
+on l2_message (message_index) where deleted_at IS NULL;
+
+create index l2_message_hash_index
+on l2_message (msg_hash) where deleted_at IS NULL;
+
+create index l2_message_height_index
+on l2_message (height) where deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table if exists l2_message;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+
+alter table l2_block
+add column transactions_pruned BOOLEAN NOT NULL DEFAULT FALSE;
+
+comment
+on column l2_block.transactions_pruned is 'whether the transactions of the block are discarded by the light storage mode, once the batch of the block is finalized';
+
+create index l2_block_unpruned_number_index
+on l2_block (number) where transactions_pruned = FALSE AND deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop index if exists l2_block_unpruned_number_index;
+
+alter table l2_block
+drop column if exists transactions_pruned;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE batch
+ADD COLUMN blob_versioned_hashes TEXT DEFAULT NULL;
+
+comment
+on column batch.blob_versioned_hashes is 'json array of the versioned hashes of the blobs carried by the commit transaction, NULL for the batches committed without blobs';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE IF EXISTS batch
+DROP COLUMN blob_versioned_hashes;
+
+-- +goose StatementEnd
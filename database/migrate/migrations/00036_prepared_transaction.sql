@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE prepared_transaction
+(
+    batch_hash         VARCHAR      NOT NULL,
+    batch_index        BIGINT       NOT NULL,
+    kind               VARCHAR      NOT NULL,
+    status             VARCHAR      NOT NULL,
+    payload            TEXT         NOT NULL,
+    tx_hash            VARCHAR      NOT NULL DEFAULT '',
+
+    created_at         TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at         TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at         TIMESTAMP(0) DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX if not exists uniq_prepared_transaction_batch_hash_kind ON prepared_transaction(batch_hash, kind) where deleted_at IS NULL;
+
+CREATE INDEX if not exists idx_prepared_transaction_status_batch_index ON prepared_transaction(status, batch_index) where deleted_at IS NULL;
+
+comment
+on column prepared_transaction.kind is 'commit_batch, finalize_batch';
+
+comment
+on column prepared_transaction.status is 'prepared, executed';
+
+comment
+on column prepared_transaction.payload is 'Safe transaction builder batch or timelock calls, in json';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS prepared_transaction;
+-- +goose StatementEnd
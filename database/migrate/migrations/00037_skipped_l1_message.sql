@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE skipped_l1_message
+(
+    queue_index        BIGINT       NOT NULL,
+    msg_hash           VARCHAR      NOT NULL DEFAULT '',
+    batch_index        BIGINT       NOT NULL,
+    batch_hash         VARCHAR      NOT NULL,
+    skip_reason        TEXT         NOT NULL DEFAULT '',
+    skip_block_number  BIGINT       NOT NULL DEFAULT 0,
+
+    created_at         TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at         TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at         TIMESTAMP(0) DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX if not exists uniq_skipped_l1_message_queue_index ON skipped_l1_message(queue_index) where deleted_at IS NULL;
+
+comment
+on column skipped_l1_message.skip_reason is 'reason reported by l2geth, empty if unknown';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS skipped_l1_message;
+-- +goose StatementEnd
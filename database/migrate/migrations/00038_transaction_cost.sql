@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE transaction_cost
+(
+    tx_hash              VARCHAR         NOT NULL,
+    context_id           VARCHAR         NOT NULL,
+    sender_name          VARCHAR         NOT NULL,
+    sender_service       VARCHAR         NOT NULL,
+    sender_address       VARCHAR         NOT NULL,
+    sender_type          SMALLINT        NOT NULL,
+    is_successful        BOOLEAN         NOT NULL,
+    block_number         BIGINT          NOT NULL,
+    block_timestamp      BIGINT          NOT NULL,
+    gas_used             BIGINT          NOT NULL,
+    effective_gas_price  DECIMAL(78, 0)  NOT NULL DEFAULT 0,
+    blob_gas_used        BIGINT          NOT NULL DEFAULT 0,
+    blob_gas_price       DECIMAL(78, 0)  NOT NULL DEFAULT 0,
+    l1_fee               DECIMAL(78, 0)  NOT NULL DEFAULT 0,
+    fee                  DECIMAL(78, 0)  NOT NULL,
+
+    created_at           TIMESTAMP(0)    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at           TIMESTAMP(0)    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at           TIMESTAMP(0)    DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX if not exists uniq_transaction_cost_tx_hash ON transaction_cost(tx_hash) where deleted_at IS NULL;
+
+CREATE INDEX if not exists idx_transaction_cost_context_id ON transaction_cost(context_id) where deleted_at IS NULL;
+
+CREATE INDEX if not exists idx_transaction_cost_block_timestamp ON transaction_cost(block_timestamp) where deleted_at IS NULL;
+
+comment
+on column transaction_cost.context_id is 'batch hash of commit, finalize and l2 gas oracle transactions, l1 block hash of l1 gas oracle transactions';
+
+comment
+on column transaction_cost.fee is 'wei paid by the sender: execution fee, blob fee and l1 data fee';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS transaction_cost;
+-- +goose StatementEnd
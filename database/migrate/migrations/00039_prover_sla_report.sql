@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE prover_sla_report
+(
+    id                 BIGSERIAL    PRIMARY KEY,
+
+    since              TIMESTAMP(0) NOT NULL,
+    until              TIMESTAMP(0) NOT NULL,
+    operators          INTEGER      NOT NULL,
+
+    created_at         TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at         TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at         TIMESTAMP(0) DEFAULT NULL
+);
+
+CREATE INDEX if not exists idx_prover_sla_report_until ON prover_sla_report(until) where deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS prover_sla_report;
+-- +goose StatementEnd
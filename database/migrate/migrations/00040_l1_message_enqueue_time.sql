@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE l1_message
+ADD COLUMN enqueue_time BIGINT NOT NULL DEFAULT 0;
+
+comment
+on column l1_message.enqueue_time is 'unix time of the L1 block queueing an enforced transaction, 0 if unknown';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE IF EXISTS l1_message
+DROP COLUMN enqueue_time;
+
+-- +goose StatementEnd
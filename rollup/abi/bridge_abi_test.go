@@ -13,6 +13,12 @@ func TestEventSignature(t *testing.T) {
 
 	assert.Equal(L1CommitBatchEventSignature, common.HexToHash("2c32d4ae151744d0bf0b9464a3e897a1d17ed2f1af71f7c9a75f12ce0d28238f"))
 	assert.Equal(L1FinalizeBatchEventSignature, common.HexToHash("26ba82f907317eedc97d0cbef23de76a43dd6edb563bdb6e9407645b950a7a2d"))
+	assert.Equal(L1RevertBatchEventSignature, common.HexToHash("00cae2739091badfd91c373f0a16cede691e0cd25bb80cff77dd5caeb4710146"))
+
+	assert.Equal(L1UpdateSequencerEventSignature, common.HexToHash("631cb110fbe6a87fba5414d6b2cff02264480535cd1f5abdbc4fa638bc0b5692"))
+	assert.Equal(L1UpdateMaxNumTxInChunkEventSignature, common.HexToHash("6d0f49971e462a2f78a25906f145cb29cd5e7bd01ebf681ac8f58cb814e5877a"))
+	assert.Equal(L1UpdateMaxGasLimitEventSignature, common.HexToHash("a030881e03ff723954dd0d35500564afab9603555d09d4456a32436f2b2373c5"))
+	assert.Equal(L1L2BaseFeeUpdatedEventSignature, common.HexToHash("230bc8094d790356a078817d156f95cc1068e9ff6485359f6a986170f567b63b"))
 
 	assert.Equal(L2SentMessageEventSignature, common.HexToHash("104371f3b442861a2a7b82a070afbbaab748bb13757bf47769e170e37809ec1e"))
 	assert.Equal(L2RelayedMessageEventSignature, common.HexToHash("4641df4a962071e12719d8c8c8e5ac7fc4d97b927346a3d7a335b1f7517e133c"))
@@ -0,0 +1,129 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/scroll-tech/go-ethereum/ethclient"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/urfave/cli/v2"
+
+	"scroll-tech/common/database"
+	"scroll-tech/common/utils"
+	"scroll-tech/common/version"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/controller/relayer"
+	"scroll-tech/rollup/internal/orm"
+)
+
+var app *cli.App
+
+func init() {
+	// Set up batch-reencoder app info.
+	app = cli.NewApp()
+	app.Action = action
+	app.Name = "batch-reencoder"
+	app.Usage = "The Scroll Batch Re-encoder"
+	app.Description = "Re-encodes historical batches with the current codecs and reports the hashes that differ from the database or L1."
+	app.Version = version.Version
+	app.Flags = append(app.Flags, utils.CommonFlags...)
+	app.Flags = append(app.Flags, reencodeFlags...)
+	app.Commands = []*cli.Command{}
+	app.Before = func(ctx *cli.Context) error {
+		return utils.LogSetup(ctx)
+	}
+}
+
+func action(ctx *cli.Context) error {
+	// Load config file.
+	cfgFile := ctx.String(utils.ConfigFileFlag.Name)
+	cfg, err := config.NewConfig(cfgFile)
+	if err != nil {
+		log.Crit("failed to load config file", "config file", cfgFile, "error", err)
+	}
+
+	// Init db connection
+	db, err := database.InitDB(cfg.DBConfig)
+	if err != nil {
+		log.Crit("failed to init db connection", "err", err)
+	}
+	defer func() {
+		if err = database.CloseDB(db); err != nil {
+			log.Crit("failed to close db connection", "error", err)
+		}
+	}()
+
+	genesisPath := ctx.String(utils.Genesis.Name)
+	genesis, err := utils.ReadGenesis(genesisPath)
+	if err != nil {
+		log.Crit("failed to read genesis", "genesis file", genesisPath, "error", err)
+	}
+
+	var reencoder *relayer.BatchReencoder
+	if ctx.Bool(skipL1Flag.Name) {
+		reencoder = relayer.NewBatchReencoder(ctx.Context, db, nil, cfg.L2Config.RelayerConfig.RollupContractAddress, genesis.Config)
+	} else {
+		l1client, dialErr := ethclient.Dial(cfg.L1Config.Endpoint)
+		if dialErr != nil {
+			log.Crit("failed to connect l1 geth", "config file", cfgFile, "error", dialErr)
+		}
+		reencoder = relayer.NewBatchReencoder(ctx.Context, db, l1client, cfg.L2Config.RelayerConfig.RollupContractAddress, genesis.Config)
+	}
+
+	endIndex := ctx.Uint64(endBatchIndexFlag.Name)
+	if endIndex == 0 {
+		latestBatch, getErr := orm.NewBatch(db).GetLatestBatch(ctx.Context)
+		if getErr != nil {
+			log.Crit("failed to get latest batch", "error", getErr)
+		}
+		endIndex = latestBatch.Index
+	}
+
+	report, err := reencoder.Reencode(ctx.Uint64(startBatchIndexFlag.Name), endIndex)
+	if err != nil {
+		return fmt.Errorf("failed to re-encode batches: %w", err)
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal discrepancy report: %w", err)
+	}
+	if reportFile := ctx.String(reportFileFlag.Name); reportFile != "" {
+		if err = os.WriteFile(reportFile, data, 0600); err != nil {
+			return fmt.Errorf("failed to write discrepancy report: %w", err)
+		}
+	} else {
+		_, _ = fmt.Fprintln(os.Stdout, string(data))
+	}
+
+	log.Info("re-encoded historical batches", "start index", report.StartBatchIndex, "end index", report.EndBatchIndex,
+		"checked batches", report.CheckedBatches, "checked on l1", report.CheckedOnL1, "discrepancies", len(report.Discrepancies))
+
+	// Fail the release gate if any historical output changed.
+	if len(report.Discrepancies) > 0 {
+		return fmt.Errorf("found %d discrepancies in re-encoded batches", len(report.Discrepancies))
+	}
+	return nil
+}
+
+// Command returns the batch-reencoder as the `reencode` subcommand of the scroll-rollup cli.
+func Command() *cli.Command {
+	return &cli.Command{
+		Name:        "reencode",
+		Usage:       app.Usage,
+		Description: app.Description,
+		Flags:       app.Flags,
+		Before:      app.Before,
+		Action:      action,
+	}
+}
+
+// Run batch_reencoder cmd instance.
+func Run() {
+	if err := app.Run(os.Args); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
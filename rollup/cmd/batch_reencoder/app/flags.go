@@ -0,0 +1,36 @@
+package app
+
+import "github.com/urfave/cli/v2"
+
+var (
+	reencodeFlags = []cli.Flag{
+		&startBatchIndexFlag,
+		&endBatchIndexFlag,
+		&reportFileFlag,
+		&skipL1Flag,
+	}
+	// startBatchIndexFlag sets the first batch to re-encode.
+	startBatchIndexFlag = cli.Uint64Flag{
+		Name:  "start-index",
+		Usage: "Index of the first batch to re-encode",
+		Value: 1,
+	}
+	// endBatchIndexFlag sets the last batch to re-encode, 0 means the latest batch.
+	endBatchIndexFlag = cli.Uint64Flag{
+		Name:  "end-index",
+		Usage: "Index of the last batch to re-encode, 0 means the latest batch",
+		Value: 0,
+	}
+	// reportFileFlag sets the file the discrepancy report is written to.
+	reportFileFlag = cli.StringFlag{
+		Name:  "report",
+		Usage: "File to write the discrepancy report to, the report is printed to stdout if empty",
+		Value: "",
+	}
+	// skipL1Flag skips the comparison with the batch hashes committed on L1.
+	skipL1Flag = cli.BoolFlag{
+		Name:  "skip-l1",
+		Usage: "Skip the comparison with the batch hashes committed on L1",
+		Value: false,
+	}
+)
@@ -8,7 +8,6 @@ import (
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/scroll-tech/go-ethereum/ethclient"
 	"github.com/scroll-tech/go-ethereum/log"
 	"github.com/urfave/cli/v2"
 
@@ -19,6 +18,7 @@ import (
 
 	"scroll-tech/rollup/internal/config"
 	"scroll-tech/rollup/internal/controller/watcher"
+	butils "scroll-tech/rollup/internal/utils"
 )
 
 var app *cli.App
@@ -62,19 +62,40 @@ func action(ctx *cli.Context) error {
 
 	registry := prometheus.DefaultRegisterer
 	observability.Server(ctx, db)
-	l1client, err := ethclient.Dial(cfg.L1Config.Endpoint)
+	l1client, err := butils.DialWithFailover(subCtx, cfg.L1Config.RPCEndpoints(), cfg.L1Config.RPCProbeInterval(), registry)
 	if err != nil {
 		log.Crit("failed to connect l1 geth", "config file", cfgFile, "error", err)
 	}
 
 	l1watcher := watcher.NewL1WatcherClient(ctx.Context, l1client, cfg.L1Config.StartHeight, cfg.L1Config.Confirmations,
-		cfg.L1Config.L1MessageQueueAddress, cfg.L1Config.ScrollChainContractAddress, db, registry)
+		cfg.L1Config.L1MessageQueueAddress, cfg.L1Config.ScrollChainContractAddress, cfg.L1Config.L1ScrollMessengerAddress, db, registry)
+	l1watcher.SetEventConfirmations(cfg.L1Config.EventConfirmations)
+	l1watcher.SetLogFetchConfig(cfg.L1Config.LogFetchConfig, cfg.L1Config.RPCEndpoints())
+	l1watcher.SetSyncLagAlertConfig(cfg.L1Config.SyncLagAlertConfig)
+	l1watcher.SetForcedTxQueueConfig(cfg.L1Config.ForcedTxQueueConfig)
+	l1watcher.SetBeaconCheckpoints(cfg.L1Config.BeaconCheckpointsConfig)
+	if err = l1watcher.SetContractVersions(cfg.L1Config.ContractVersions); err != nil {
+		log.Crit("failed to set l1 contract versions", "config file", cfgFile, "error", err)
+	}
+
+	if standbyCfg := cfg.L1Config.StandbyConfig; standbyCfg != nil {
+		standby := watcher.NewL1WatcherStandby(subCtx, db, standbyCfg, registry)
+		l1watcher.SetStandby(standby)
+		defer standby.Release()
+		go utils.Loop(subCtx, time.Duration(standbyCfg.HeartbeatIntervalSec)*time.Second, standby.Heartbeat)
+	}
 
-	go utils.Loop(subCtx, 10*time.Second, func() {
+	fetchContractEvent := func(context.Context) {
 		if loopErr := l1watcher.FetchContractEvent(); loopErr != nil {
 			log.Error("Failed to fetch bridge contract", "err", loopErr)
 		}
-	})
+	}
+	if subCfg := cfg.L1Config.SubscriptionConfig; subCfg != nil {
+		headSubscriber := watcher.NewHeadSubscriber(subCtx, subCfg, 10*time.Second, "l1", "event_watcher", registry)
+		go headSubscriber.Run(fetchContractEvent)
+	} else {
+		go utils.LoopWithContext(subCtx, 10*time.Second, fetchContractEvent)
+	}
 
 	log.Info("Start event-watcher successfully", "version", version.Version)
 
@@ -88,6 +109,18 @@ func action(ctx *cli.Context) error {
 	return nil
 }
 
+// Command returns the event-watcher as the `watcher` subcommand of the scroll-rollup cli.
+func Command() *cli.Command {
+	return &cli.Command{
+		Name:        "watcher",
+		Usage:       app.Usage,
+		Description: app.Description,
+		Flags:       app.Flags,
+		Before:      app.Before,
+		Action:      action,
+	}
+}
+
 // Run event watcher cmd instance.
 func Run() {
 	if err := app.Run(os.Args); err != nil {
@@ -67,7 +67,7 @@ func action(ctx *cli.Context) error {
 	registry := prometheus.DefaultRegisterer
 	observability.Server(ctx, db)
 
-	l1client, err := ethclient.Dial(cfg.L1Config.Endpoint)
+	l1client, err := butils.DialWithFailover(subCtx, cfg.L1Config.RPCEndpoints(), cfg.L1Config.RPCProbeInterval(), registry)
 	if err != nil {
 		log.Crit("failed to connect l1 geth", "config file", cfgFile, "error", err)
 	}
@@ -84,7 +84,7 @@ func action(ctx *cli.Context) error {
 		log.Crit("failed to read genesis", "genesis file", genesisPath, "error", err)
 	}
 
-	l1watcher := watcher.NewL1WatcherClient(ctx.Context, l1client, cfg.L1Config.StartHeight, cfg.L1Config.Confirmations, cfg.L1Config.L1MessageQueueAddress, cfg.L1Config.ScrollChainContractAddress, db, registry)
+	l1watcher := watcher.NewL1WatcherClient(ctx.Context, l1client, cfg.L1Config.StartHeight, cfg.L1Config.Confirmations, cfg.L1Config.L1MessageQueueAddress, cfg.L1Config.ScrollChainContractAddress, cfg.L1Config.L1ScrollMessengerAddress, db, registry)
 
 	l1relayer, err := relayer.NewLayer1Relayer(ctx.Context, db, cfg.L1Config.RelayerConfig, genesis.Config, relayer.ServiceTypeL1GasOracle, registry)
 	if err != nil {
@@ -95,20 +95,45 @@ func action(ctx *cli.Context) error {
 		log.Crit("failed to create new l2 relayer", "config file", cfgFile, "error", err)
 	}
 	// Start l1 watcher process
-	go utils.LoopWithContext(subCtx, 10*time.Second, func(ctx context.Context) {
+	fetchBlockHeader := func(ctx context.Context) {
 		// Fetch the latest block number to decrease the delay when fetching gas prices
-		// Use latest block number - 1 to prevent frequent reorg
-		number, loopErr := butils.GetLatestConfirmedBlockNumber(ctx, l1client, rpc.LatestBlockNumber)
+		// Use latest block number - 1 to prevent frequent reorg, unless the gas oracle confirmations are configured
+		confirmations, offset := rpc.LatestBlockNumber, uint64(1)
+		if eventCfg := cfg.L1Config.EventConfirmations; eventCfg != nil && eventCfg.GasOracle != nil {
+			confirmations, offset = *eventCfg.GasOracle, 0
+		}
+		number, loopErr := butils.GetLatestConfirmedBlockNumber(ctx, l1client, confirmations)
 		if loopErr != nil {
 			log.Error("failed to get block number", "err", loopErr)
 			return
 		}
 
-		if loopErr = l1watcher.FetchBlockHeader(number - 1); loopErr != nil {
-			log.Error("Failed to fetch L1 block header", "lastest", number-1, "err", loopErr)
+		if loopErr = l1watcher.FetchBlockHeader(number - offset); loopErr != nil {
+			log.Error("Failed to fetch L1 block header", "lastest", number-offset, "err", loopErr)
 			return
 		}
-	})
+	}
+	if subCfg := cfg.L1Config.SubscriptionConfig; subCfg != nil {
+		headSubscriber := watcher.NewHeadSubscriber(subCtx, subCfg, 10*time.Second, "l1", "gas_oracle", registry)
+		go headSubscriber.Run(fetchBlockHeader)
+	} else {
+		go utils.LoopWithContext(subCtx, 10*time.Second, fetchBlockHeader)
+	}
+
+	if systemCfg := cfg.L1Config.SystemConfigWatcherConfig; systemCfg != nil {
+		startHeight := systemCfg.StartHeight
+		if startHeight == 0 {
+			startHeight = cfg.L1Config.StartHeight
+		}
+		systemConfigWatcher := watcher.NewSystemConfigWatcher(subCtx, l1client, startHeight, cfg.L1Config.Confirmations,
+			watcher.SystemConfigAddresses{L2GasOracle: cfg.L2Config.RelayerConfig.GasPriceOracleContractAddress},
+			watcher.SystemConfigHandlers{OnL2BaseFeeUpdate: l2relayer.SetOnChainL2BaseFee}, registry)
+		checkInterval := time.Duration(systemCfg.CheckIntervalSec) * time.Second
+		if checkInterval == 0 {
+			checkInterval = time.Minute
+		}
+		go utils.Loop(subCtx, checkInterval, systemConfigWatcher.FetchUpdates)
+	}
 
 	// Start l1relayer process
 	go utils.Loop(subCtx, 10*time.Second, l1relayer.ProcessGasPriceOracle)
@@ -127,6 +152,18 @@ func action(ctx *cli.Context) error {
 	return nil
 }
 
+// Command returns the gas-oracle as the `gas-oracle` subcommand of the scroll-rollup cli.
+func Command() *cli.Command {
+	return &cli.Command{
+		Name:        "gas-oracle",
+		Usage:       app.Usage,
+		Description: app.Description,
+		Flags:       app.Flags,
+		Before:      app.Before,
+		Action:      action,
+	}
+}
+
 // Run message_relayer cmd instance.
 func Run() {
 	if err := app.Run(os.Args); err != nil {
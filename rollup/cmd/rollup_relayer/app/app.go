@@ -2,15 +2,20 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
+	"syscall"
 	"time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/scroll-tech/go-ethereum/ethclient"
 	"github.com/scroll-tech/go-ethereum/log"
 	"github.com/urfave/cli/v2"
+	"gorm.io/gorm"
 
 	"scroll-tech/common/database"
 	"scroll-tech/common/observability"
@@ -18,8 +23,11 @@ import (
 	"scroll-tech/common/version"
 
 	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/controller/api"
 	"scroll-tech/rollup/internal/controller/relayer"
 	"scroll-tech/rollup/internal/controller/watcher"
+	"scroll-tech/rollup/internal/featureflag"
+	"scroll-tech/rollup/internal/route"
 	butils "scroll-tech/rollup/internal/utils"
 )
 
@@ -34,6 +42,8 @@ func init() {
 	app.Version = version.Version
 	app.Flags = append(app.Flags, utils.CommonFlags...)
 	app.Flags = append(app.Flags, utils.RollupRelayerFlags...)
+	app.Flags = append(app.Flags, apiFlags...)
+	app.Flags = append(app.Flags, &l2SyncHeightFlag)
 	app.Commands = []*cli.Command{}
 	app.Before = func(ctx *cli.Context) error {
 		return utils.LogSetup(ctx)
@@ -67,22 +77,52 @@ func action(ctx *cli.Context) error {
 	observability.Server(ctx, db)
 
 	// Init l2geth connection
-	l2client, err := ethclient.Dial(cfg.L2Config.Endpoint)
+	l2client, l2Failover, err := butils.DialL2WithFailover(subCtx, cfg.L2Config.RPCEndpoints(), cfg.L2Config.RPCProbeInterval(), registry)
 	if err != nil {
 		log.Crit("failed to connect l2 geth", "config file", cfgFile, "error", err)
 	}
 
+	// Init l1geth connection, shared by the components reading L1
+	var l1client *ethclient.Client
+	if cfg.L2Config.RelayerConfig.StrictSequencing || cfg.L2Config.RelayerConfig.OperatorFallbackConfig != nil ||
+		cfg.L1Config.ParamDriftConfig != nil || cfg.L1Config.SystemConfigWatcherConfig != nil || cfg.L1Config.BlobVerificationConfig != nil {
+		l1client, err = butils.DialWithFailover(subCtx, cfg.L1Config.RPCEndpoints(), cfg.L1Config.RPCProbeInterval(), registry)
+		if err != nil {
+			log.Crit("failed to connect l1 geth", "config file", cfgFile, "error", err)
+		}
+	}
+
 	genesisPath := ctx.String(utils.Genesis.Name)
 	genesis, err := utils.ReadGenesis(genesisPath)
 	if err != nil {
 		log.Crit("failed to read genesis", "genesis file", genesisPath, "error", err)
 	}
+	if relayerCfg := cfg.L2Config.RelayerConfig; relayerCfg.MultisigConfig != nil && relayerCfg.DABackendConfig == nil && genesis.Config.BernoulliBlock != nil {
+		log.Crit("blob batches can't be committed through a multisig, multisig_config requires da_backend_config once Bernoulli is enabled", "config file", cfgFile)
+	}
+
+	featureFlags, err := featureflag.NewFlags(subCtx, cfg.FeatureFlags, db, registry)
+	if err != nil {
+		log.Crit("failed to load feature flags", "config file", cfgFile, "error", err)
+	}
+	go utils.Loop(subCtx, 30*time.Second, featureFlags.Refresh)
 
 	initGenesis := ctx.Bool(utils.ImportGenesisFlag.Name)
 	l2relayer, err := relayer.NewLayer2Relayer(ctx.Context, l2client, db, cfg.L2Config.RelayerConfig, genesis.Config, initGenesis, relayer.ServiceTypeL2RollupRelayer, registry)
 	if err != nil {
 		log.Crit("failed to create l2 relayer", "config file", cfgFile, "error", err)
 	}
+	l2relayer.SetFeatureFlags(featureFlags)
+	if crossCheckCfg := cfg.L2Config.RelayerConfig.FinalizeCrossCheckConfig; crossCheckCfg != nil {
+		crossCheckClient, dialErr := ethclient.Dial(crossCheckCfg.Endpoint)
+		if dialErr != nil {
+			log.Crit("failed to connect the finalize cross-check node", "config file", cfgFile, "error", dialErr)
+		}
+		l2relayer.SetFinalizeCrossCheck(crossCheckClient, cfg.L2Config.L2MessageQueueAddress, cfg.L2Config.WithdrawTrieRootSlot)
+	}
+	if cfg.L2Config.RelayerConfig.StrictSequencing || cfg.L2Config.RelayerConfig.OperatorFallbackConfig != nil {
+		l2relayer.SetSequencingGuard(l1client)
+	}
 
 	chunkProposer := watcher.NewChunkProposer(subCtx, cfg.L2Config.ChunkProposerConfig, genesis.Config, db, registry)
 	if err != nil {
@@ -95,38 +135,238 @@ func action(ctx *cli.Context) error {
 	}
 
 	l2watcher := watcher.NewL2WatcherClient(subCtx, l2client, cfg.L2Config.Confirmations, cfg.L2Config.L2MessageQueueAddress, cfg.L2Config.WithdrawTrieRootSlot, db, registry)
+	l2watcher.SetBlockFetchConfig(cfg.L2Config.BlockFetchConfig)
+	l2watcher.SetBlockQuarantineConfig(cfg.L2Config.BlockQuarantineConfig)
+	l2watcher.SetMessageIndexConfig(cfg.L2Config.MessageIndexConfig)
+	if crossCheckCfg := cfg.L2Config.CrossCheckConfig; crossCheckCfg != nil {
+		crossCheckClient, dialErr := ethclient.Dial(crossCheckCfg.Endpoint)
+		if dialErr != nil {
+			log.Crit("failed to connect the l2 cross-check node", "config file", cfgFile, "error", dialErr)
+		}
+		var onDivergence func()
+		if l2Failover != nil {
+			onDivergence = l2Failover.FailOver
+		}
+		l2watcher.SetCrossCheck(crossCheckClient, onDivergence)
+	}
+	if ctx.IsSet(l2SyncHeightFlag.Name) {
+		if err = l2watcher.SetSyncHeight(subCtx, ctx.Uint64(l2SyncHeightFlag.Name)); err != nil {
+			log.Crit("failed to set the l2 sync height", "height", ctx.Uint64(l2SyncHeightFlag.Name), "error", err)
+		}
+	}
 
 	// Watcher loop to fetch missing blocks
-	go utils.LoopWithContext(subCtx, 2*time.Second, func(ctx context.Context) {
+	fetchMissingBlocks := func(ctx context.Context) {
 		number, loopErr := butils.GetLatestConfirmedBlockNumber(ctx, l2client, cfg.L2Config.Confirmations)
 		if loopErr != nil {
 			log.Error("failed to get block number", "err", loopErr)
 			return
 		}
 		l2watcher.TryFetchRunningMissingBlocks(number)
-	})
+	}
+	if subCfg := cfg.L2Config.SubscriptionConfig; subCfg != nil {
+		headSubscriber := watcher.NewHeadSubscriber(subCtx, subCfg, 2*time.Second, "l2", "rollup_relayer", registry)
+		go headSubscriber.Run(fetchMissingBlocks)
+	} else {
+		go utils.LoopWithContext(subCtx, 2*time.Second, fetchMissingBlocks)
+	}
+
+	if backfillCfg := cfg.L2Config.RowConsumptionBackfillConfig; backfillCfg != nil {
+		backfiller, backfillErr := watcher.NewRowConsumptionBackfiller(subCtx, backfillCfg, l2client, db, registry)
+		if backfillErr != nil {
+			log.Crit("failed to create row consumption backfiller", "config file", cfgFile, "error", backfillErr)
+		}
+		checkInterval := time.Duration(backfillCfg.CheckIntervalSec) * time.Second
+		if checkInterval == 0 {
+			checkInterval = 10 * time.Second
+		}
+		go utils.Loop(subCtx, checkInterval, backfiller.Backfill)
+	}
+
+	if lightStorageCfg := cfg.L2Config.LightStorageConfig; lightStorageCfg != nil {
+		pruner := watcher.NewL2BlockPruner(subCtx, lightStorageCfg, db, registry)
+		pruneInterval := time.Duration(lightStorageCfg.PruneIntervalSec) * time.Second
+		if pruneInterval == 0 {
+			pruneInterval = 10 * time.Minute
+		}
+		go utils.Loop(subCtx, pruneInterval, pruner.Prune)
+	}
+
+	snapshotCfg := cfg.L2Config.ProposerStateSnapshotConfig
+	if snapshotCfg != nil {
+		if restoreErr := chunkProposer.RestoreState(); restoreErr != nil {
+			log.Warn("failed to restore chunk proposer state, re-estimating the pending blocks", "error", restoreErr)
+		}
+		snapshotInterval := time.Duration(snapshotCfg.SnapshotIntervalSec) * time.Second
+		if snapshotInterval == 0 {
+			snapshotInterval = 60 * time.Second
+		}
+		go utils.Loop(subCtx, snapshotInterval, chunkProposer.SnapshotState)
+	}
 
-	go utils.Loop(subCtx, 2*time.Second, chunkProposer.TryProposeChunk)
+	chunkMinInterval, chunkMaxInterval, chunkJitter := cfg.L2Config.ChunkProposerConfig.ProposeInterval.Intervals(2 * time.Second)
+	go utils.LoopWithAdaptiveInterval(subCtx, chunkMinInterval, chunkMaxInterval, chunkJitter, chunkProposer.TryProposeChunk)
 
-	go utils.Loop(subCtx, 10*time.Second, batchProposer.TryProposeBatch)
+	if gcCfg := cfg.L2Config.ChunkProposerConfig.StaleChunkGC; gcCfg != nil {
+		checkInterval := time.Duration(gcCfg.CheckIntervalSec) * time.Second
+		if checkInterval == 0 {
+			checkInterval = 60 * time.Second
+		}
+		go utils.Loop(subCtx, checkInterval, chunkProposer.CollectStaleChunks)
+	}
 
-	go utils.Loop(subCtx, 2*time.Second, l2relayer.ProcessPendingBatches)
+	batchMinInterval, batchMaxInterval, batchJitter := cfg.L2Config.BatchProposerConfig.ProposeInterval.Intervals(10 * time.Second)
+	go utils.LoopWithAdaptiveInterval(subCtx, batchMinInterval, batchMaxInterval, batchJitter, batchProposer.TryProposeBatch)
 
-	go utils.Loop(subCtx, 15*time.Second, l2relayer.ProcessCommittedBatches)
+	if driftCfg := cfg.L1Config.ParamDriftConfig; driftCfg != nil {
+		driftDetector, driftErr := watcher.NewL1ParamDriftDetector(subCtx, l1client, cfg, registry)
+		if driftErr != nil {
+			log.Crit("failed to create l1 param drift detector", "config file", cfgFile, "error", driftErr)
+		}
+		checkInterval := time.Duration(driftCfg.CheckIntervalSec) * time.Second
+		if checkInterval == 0 {
+			checkInterval = 10 * time.Minute
+		}
+		go utils.Loop(subCtx, checkInterval, driftDetector.CheckDrift)
+	}
+
+	if systemCfg := cfg.L1Config.SystemConfigWatcherConfig; systemCfg != nil {
+		startHeight := systemCfg.StartHeight
+		if startHeight == 0 {
+			startHeight = cfg.L1Config.StartHeight
+		}
+		systemConfigWatcher := watcher.NewSystemConfigWatcher(subCtx, l1client, startHeight, cfg.L1Config.Confirmations,
+			watcher.SystemConfigAddresses{
+				ScrollChain:    cfg.L2Config.RelayerConfig.RollupContractAddress,
+				L1MessageQueue: cfg.L1Config.L1MessageQueueAddress,
+			},
+			watcher.SystemConfigHandlers{
+				OnSequencerUpdate:       l2relayer.SetSequencerStatus,
+				OnMaxNumTxInChunkUpdate: chunkProposer.SetOnChainMaxTxNumPerChunk,
+			}, registry)
+		checkInterval := time.Duration(systemCfg.CheckIntervalSec) * time.Second
+		if checkInterval == 0 {
+			checkInterval = time.Minute
+		}
+		go utils.Loop(subCtx, checkInterval, systemConfigWatcher.FetchUpdates)
+	}
+
+	if blobCfg := cfg.L1Config.BlobVerificationConfig; blobCfg != nil {
+		blobVerifier, verifierErr := relayer.NewBlobVerifier(subCtx, db, l1client, blobCfg, genesis.Config, registry)
+		if verifierErr != nil {
+			log.Crit("failed to create blob verifier", "config file", cfgFile, "error", verifierErr)
+		}
+		checkInterval := time.Duration(blobCfg.CheckIntervalSec) * time.Second
+		if checkInterval == 0 {
+			checkInterval = time.Minute
+		}
+		go utils.Loop(subCtx, checkInterval, blobVerifier.VerifyCommittedBatches)
+	}
+
+	var governor *watcher.ThroughputGovernor
+	if governorCfg := cfg.L2Config.ThroughputGovernorConfig; governorCfg != nil {
+		governor, err = watcher.NewThroughputGovernor(subCtx, governorCfg, db, registry)
+		if err != nil {
+			log.Crit("failed to create throughput governor", "config file", cfgFile, "error", err)
+		}
+		checkInterval := time.Duration(governorCfg.CheckIntervalSec) * time.Second
+		if checkInterval == 0 {
+			checkInterval = 30 * time.Second
+		}
+		go utils.Loop(subCtx, checkInterval, governor.Update)
+	}
+
+	// The relaying loops are stopped first on shutdown, while the pending transactions are drained.
+	relayCtx, cancelRelay := context.WithCancel(subCtx)
+	defer cancelRelay()
+
+	go utils.Loop(relayCtx, 2*time.Second, l2relayer.ProcessPendingBatches)
+
+	go utils.Loop(relayCtx, 15*time.Second, l2relayer.ProcessCommittedBatches)
+
+	if cfg.L2Config.RelayerConfig.MultisigConfig != nil {
+		go utils.Loop(subCtx, 15*time.Second, l2relayer.ProcessPreparedTransactions)
+	}
+
+	apiSrv := apiServer(ctx, cfg, db, chunkProposer, batchProposer, l2watcher, l2relayer, governor, featureFlags, registry)
 
 	// Finish start all rollup relayer functions.
 	log.Info("Start rollup-relayer successfully", "version", version.Version)
 
 	// Catch CTRL-C to ensure a graceful shutdown.
 	interrupt := make(chan os.Signal, 1)
-	signal.Notify(interrupt, os.Interrupt)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
 
 	// Wait until the interrupt signal is received from an OS signal.
 	<-interrupt
 
+	// Stop sending new L1 transactions and wait for the pending ones, a second signal aborts the wait.
+	cancelRelay()
+	drainTimeout := time.Duration(cfg.L2Config.RelayerConfig.DrainTimeoutSec) * time.Second
+	if drainTimeout == 0 {
+		drainTimeout = 5 * time.Minute
+	}
+	drainCtx, cancelDrain := context.WithTimeout(subCtx, drainTimeout)
+	go func() {
+		select {
+		case <-interrupt:
+			cancelDrain()
+		case <-drainCtx.Done():
+		}
+	}()
+	log.Info("draining the pending L1 transactions", "timeout", drainTimeout)
+	l2relayer.Drain(drainCtx)
+	cancelDrain()
+
+	if snapshotCfg != nil {
+		chunkProposer.SnapshotState()
+	}
+
+	if apiSrv != nil {
+		closeCtx, cancelExit := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancelExit()
+		if err = apiSrv.Shutdown(closeCtx); err != nil {
+			log.Warn("shutdown rollup-relayer api server failure", "error", err)
+		}
+	}
+
 	return nil
 }
 
+func apiServer(ctx *cli.Context, cfg *config.Config, db *gorm.DB, chunkProposer *watcher.ChunkProposer, batchProposer *watcher.BatchProposer, l2Watcher *watcher.L2WatcherClient, l2Relayer *relayer.Layer2Relayer, governor *watcher.ThroughputGovernor, featureFlags *featureflag.Flags, reg prometheus.Registerer) *http.Server {
+	if !ctx.Bool(httpEnabledFlag.Name) {
+		return nil
+	}
+
+	router := gin.New()
+	api.InitController(db, chunkProposer, batchProposer, l2Watcher, l2Relayer, governor, featureFlags, cfg.AdminAPIConfig, cfg.L1Config.L1ScrollMessengerAddress)
+	route.Route(router, reg)
+	srv := &http.Server{
+		Addr:              fmt.Sprintf("%s:%d", ctx.String(httpListenAddrFlag.Name), ctx.Int(httpPortFlag.Name)),
+		Handler:           router,
+		ReadHeaderTimeout: time.Minute,
+	}
+
+	go func() {
+		if runServerErr := srv.ListenAndServe(); runServerErr != nil && !errors.Is(runServerErr, http.ErrServerClosed) {
+			log.Crit("run rollup-relayer http server failure", "error", runServerErr)
+		}
+	}()
+	return srv
+}
+
+// Command returns the rollup-relayer as the `relayer` subcommand of the scroll-rollup cli.
+func Command() *cli.Command {
+	return &cli.Command{
+		Name:        "relayer",
+		Usage:       app.Usage,
+		Description: app.Description,
+		Flags:       app.Flags,
+		Before:      app.Before,
+		Action:      action,
+	}
+}
+
 // Run rollup relayer cmd instance.
 func Run() {
 	if err := app.Run(os.Args); err != nil {
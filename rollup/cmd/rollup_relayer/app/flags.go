@@ -0,0 +1,35 @@
+package app
+
+import "github.com/urfave/cli/v2"
+
+var (
+	apiFlags = []cli.Flag{
+		// http flags
+		&httpEnabledFlag,
+		&httpListenAddrFlag,
+		&httpPortFlag,
+	}
+	// httpEnabledFlag enable the rollup relayer api server.
+	httpEnabledFlag = cli.BoolFlag{
+		Name:  "http",
+		Usage: "Enable the HTTP API server",
+		Value: false,
+	}
+	// httpListenAddrFlag set the http address.
+	httpListenAddrFlag = cli.StringFlag{
+		Name:  "http.addr",
+		Usage: "HTTP API server listening interface",
+		Value: "localhost",
+	}
+	// httpPortFlag set http.port.
+	httpPortFlag = cli.IntFlag{
+		Name:  "http.port",
+		Usage: "HTTP API server listening port",
+		Value: 8290,
+	}
+	// l2SyncHeightFlag moves the l2 watcher sync cursor back at startup.
+	l2SyncHeightFlag = cli.Uint64Flag{
+		Name:  "l2.sync-height",
+		Usage: "Delete the stored L2 blocks above the given height at startup, so that the L2 watcher imports them again",
+	}
+)
@@ -0,0 +1,80 @@
+package app
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v2"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/database"
+	"scroll-tech/common/utils"
+	"scroll-tech/common/version"
+
+	reencoder "scroll-tech/rollup/cmd/batch_reencoder/app"
+	watcher "scroll-tech/rollup/cmd/event_watcher/app"
+	oracle "scroll-tech/rollup/cmd/gas_oracle/app"
+	relayer "scroll-tech/rollup/cmd/rollup_relayer/app"
+	"scroll-tech/rollup/internal/config"
+)
+
+var app *cli.App
+
+func init() {
+	// Set up scroll-rollup app info.
+	app = cli.NewApp()
+	app.Name = "scroll-rollup"
+	app.Usage = "The Scroll Rollup CLI"
+	app.Description = "Runs the rollup services and maintenance tools sharing the same configuration file and logging flags."
+	app.Version = version.Version
+	app.Commands = []*cli.Command{
+		watcher.Command(),
+		relayer.Command(),
+		oracle.Command(),
+		reencoder.Command(),
+		inspectCommand(),
+		rollbackCommand(),
+		exportCommand(),
+		migrateCommand(),
+		backfillCommand(),
+		backfillRowConsumptionCommand(),
+		dryRunCommitCommand(),
+	}
+}
+
+// maintenanceCommand builds a maintenance subcommand taking the common flags.
+func maintenanceCommand(name, usage string, flags []cli.Flag, action cli.ActionFunc) *cli.Command {
+	return &cli.Command{
+		Name:   name,
+		Usage:  usage,
+		Flags:  append(append([]cli.Flag{}, utils.CommonFlags...), flags...),
+		Before: utils.LogSetup,
+		Action: action,
+	}
+}
+
+// withDB loads the config file and runs fn with a connection to the rollup database.
+func withDB(ctx *cli.Context, fn func(cfg *config.Config, db *gorm.DB) error) error {
+	cfgFile := ctx.String(utils.ConfigFileFlag.Name)
+	cfg, err := config.NewConfig(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load config file %s: %w", cfgFile, err)
+	}
+
+	db, err := database.InitDB(cfg.DBConfig)
+	if err != nil {
+		return fmt.Errorf("failed to init db connection: %w", err)
+	}
+	defer func() {
+		_ = database.CloseDB(db)
+	}()
+	return fn(cfg, db)
+}
+
+// Run scroll-rollup cmd instance.
+func Run() {
+	if err := app.Run(os.Args); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
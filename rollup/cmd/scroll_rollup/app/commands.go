@@ -0,0 +1,392 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scroll-tech/go-ethereum/ethclient"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/urfave/cli/v2"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+	"scroll-tech/common/utils"
+	"scroll-tech/database/migrate"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/controller/relayer"
+	"scroll-tech/rollup/internal/controller/watcher"
+	"scroll-tech/rollup/internal/orm"
+)
+
+const exportPageSize = 1000
+
+var (
+	indexFlag = &cli.Uint64Flag{
+		Name:     "index",
+		Usage:    "Index of the batch or chunk",
+		Required: true,
+	}
+	batchIndexFlag = &cli.Uint64Flag{
+		Name:     "batch-index",
+		Usage:    "Index of the last batch to keep",
+		Required: true,
+	}
+	confirmFlag = &cli.BoolFlag{
+		Name:  "confirm",
+		Usage: "Apply the rollback instead of printing what would be rolled back",
+	}
+	startIndexFlag = &cli.Uint64Flag{
+		Name:  "start-index",
+		Usage: "Index of the first exported row",
+	}
+	endIndexFlag = &cli.Uint64Flag{
+		Name:  "end-index",
+		Usage: "Index of the last exported row, the latest row if not set",
+		Value: math.MaxUint64,
+	}
+	outputFlag = &cli.StringFlag{
+		Name:  "output",
+		Usage: "File the JSON lines are written to, stdout if not set",
+	}
+	startL1BlockFlag = &cli.Uint64Flag{
+		Name:     "start-l1-block",
+		Usage:    "First L1 block whose committed batches are backfilled",
+		Required: true,
+	}
+	endL1BlockFlag = &cli.Uint64Flag{
+		Name:     "end-l1-block",
+		Usage:    "Last L1 block whose committed batches are backfilled, it must be finalized",
+		Required: true,
+	}
+	capacityCheckerEndpointFlag = &cli.StringFlag{
+		Name:  "capacity-checker-endpoint",
+		Usage: "Capacity checker RPC endpoint queried when l2geth returns no row consumption, the row_consumption_backfill_config one if not set",
+	}
+	capacityCheckerMethodFlag = &cli.StringFlag{
+		Name:  "capacity-checker-method",
+		Usage: "RPC method of the capacity checker returning the row consumption of a block by number, the row_consumption_backfill_config one if not set",
+	}
+	backfillBatchSizeFlag = &cli.Uint64Flag{
+		Name:  "batch-size",
+		Usage: "Number of blocks loaded from the database at a time",
+		Value: 100,
+	}
+)
+
+func inspectCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "inspect",
+		Usage: "Print a batch or a chunk stored in the database",
+		Subcommands: []*cli.Command{
+			maintenanceCommand("batch", "Print the batch with the given index", []cli.Flag{indexFlag}, inspectBatch),
+			maintenanceCommand("chunk", "Print the chunk with the given index", []cli.Flag{indexFlag}, inspectChunk),
+		},
+	}
+}
+
+func inspectBatch(ctx *cli.Context) error {
+	return withDB(ctx, func(_ *config.Config, db *gorm.DB) error {
+		batch, err := orm.NewBatch(db).GetBatchByIndex(ctx.Context, ctx.Uint64(indexFlag.Name))
+		if err != nil {
+			return err
+		}
+		if batch == nil {
+			return fmt.Errorf("batch %d not found", ctx.Uint64(indexFlag.Name))
+		}
+		return printJSON(batch.ToSchema())
+	})
+}
+
+func inspectChunk(ctx *cli.Context) error {
+	return withDB(ctx, func(_ *config.Config, db *gorm.DB) error {
+		index := ctx.Uint64(indexFlag.Name)
+		chunks, err := orm.NewChunk(db).GetChunksInRange(ctx.Context, index, index)
+		if err != nil {
+			return err
+		}
+		return printJSON(chunks[0].ToSchema())
+	})
+}
+
+func printJSON(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(os.Stdout, string(data))
+	return err
+}
+
+func rollbackCommand() *cli.Command {
+	return maintenanceCommand("rollback", "Delete the uncommitted batches after the given batch and release their chunks", []cli.Flag{batchIndexFlag, confirmFlag}, rollback)
+}
+
+func rollback(ctx *cli.Context) error {
+	return withDB(ctx, func(_ *config.Config, db *gorm.DB) error {
+		batchIndex := ctx.Uint64(batchIndexFlag.Name)
+		batchOrm := orm.NewBatch(db)
+		chunkOrm := orm.NewChunk(db)
+
+		keptBatch, err := batchOrm.GetBatchByIndex(ctx.Context, batchIndex)
+		if err != nil {
+			return err
+		}
+		if keptBatch == nil {
+			return fmt.Errorf("batch %d not found", batchIndex)
+		}
+
+		// Only the batches which have not been committed on L1 can be rolled back.
+		committed, err := batchOrm.GetBatches(ctx.Context, map[string]interface{}{
+			"index > ?":              batchIndex,
+			"rollup_status NOT IN ?": []types.RollupStatus{types.RollupPending, types.RollupCommitFailed},
+		}, nil, 1)
+		if err != nil {
+			return err
+		}
+		if len(committed) > 0 {
+			return fmt.Errorf("batch %d has rollup status %s, only uncommitted batches can be rolled back", committed[0].Index, types.RollupStatus(committed[0].RollupStatus))
+		}
+
+		latestBatch, err := batchOrm.GetLatestBatch(ctx.Context)
+		if err != nil {
+			return err
+		}
+		if latestBatch.Index == batchIndex {
+			log.Info("nothing to roll back", "latest batch index", latestBatch.Index)
+			return nil
+		}
+
+		if !ctx.Bool(confirmFlag.Name) {
+			log.Info("dry run, rerun with --confirm to roll back", "deleted batches", fmt.Sprintf("%d-%d", batchIndex+1, latestBatch.Index), "released chunks after index", keptBatch.EndChunkIndex)
+			return nil
+		}
+
+		err = db.Transaction(func(dbTX *gorm.DB) error {
+			if err := batchOrm.DeleteBatchesGtIndex(ctx.Context, batchIndex, dbTX); err != nil {
+				return err
+			}
+			return chunkOrm.ResetBatchHashGtIndex(ctx.Context, keptBatch.EndChunkIndex, dbTX)
+		})
+		if err != nil {
+			return err
+		}
+		log.Info("rolled back batches", "deleted batches", fmt.Sprintf("%d-%d", batchIndex+1, latestBatch.Index), "released chunks after index", keptBatch.EndChunkIndex)
+		return nil
+	})
+}
+
+func exportCommand() *cli.Command {
+	flags := []cli.Flag{startIndexFlag, endIndexFlag, outputFlag}
+	return &cli.Command{
+		Name:  "export",
+		Usage: "Export batches or chunks as JSON lines",
+		Subcommands: []*cli.Command{
+			maintenanceCommand("batches", "Export the batches in the index range", flags, exportBatches),
+			maintenanceCommand("chunks", "Export the chunks in the index range", flags, exportChunks),
+		},
+	}
+}
+
+func exportBatches(ctx *cli.Context) error {
+	return withDB(ctx, func(_ *config.Config, db *gorm.DB) error {
+		batchOrm := orm.NewBatch(db)
+		return exportRows(ctx, func(start, end uint64) ([]interface{}, uint64, error) {
+			batches, err := batchOrm.GetBatches(ctx.Context, map[string]interface{}{"index >= ?": start, "index <= ?": end}, nil, exportPageSize)
+			if err != nil || len(batches) == 0 {
+				return nil, 0, err
+			}
+			rows := make([]interface{}, len(batches))
+			for i, batch := range batches {
+				rows[i] = batch.ToSchema()
+			}
+			return rows, batches[len(batches)-1].Index, nil
+		})
+	})
+}
+
+func exportChunks(ctx *cli.Context) error {
+	return withDB(ctx, func(_ *config.Config, db *gorm.DB) error {
+		chunkOrm := orm.NewChunk(db)
+		return exportRows(ctx, func(start, end uint64) ([]interface{}, uint64, error) {
+			chunks, err := chunkOrm.GetChunksGEIndex(ctx.Context, start, exportPageSize)
+			if err != nil {
+				return nil, 0, err
+			}
+			var rows []interface{}
+			for _, chunk := range chunks {
+				if chunk.Index > end {
+					break
+				}
+				rows = append(rows, chunk.ToSchema())
+			}
+			if len(rows) == 0 {
+				return nil, 0, nil
+			}
+			return rows, chunks[len(rows)-1].Index, nil
+		})
+	})
+}
+
+// exportRows writes the rows in the index range as JSON lines, fetching a page of rows starting at
+// the given index at a time. fetchPage returns the rows of the page and the index of its last row.
+func exportRows(ctx *cli.Context, fetchPage func(start, end uint64) ([]interface{}, uint64, error)) error {
+	start, end := ctx.Uint64(startIndexFlag.Name), ctx.Uint64(endIndexFlag.Name)
+	if start > end {
+		return errors.New("start index should be less than or equal to end index")
+	}
+
+	var w io.Writer = os.Stdout
+	if output := ctx.String(outputFlag.Name); output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+		w = f
+	}
+
+	encoder := json.NewEncoder(w)
+	var exported int
+	for start <= end {
+		rows, lastIndex, err := fetchPage(start, end)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			if err := encoder.Encode(row); err != nil {
+				return err
+			}
+		}
+		exported += len(rows)
+		if len(rows) < exportPageSize || lastIndex == math.MaxUint64 {
+			break
+		}
+		start = lastIndex + 1
+	}
+	log.Info("export completed", "rows", exported)
+	return nil
+}
+
+func backfillCommand() *cli.Command {
+	return maintenanceCommand("backfill", "Rebuild the batches committed in a finalized L1 block range from the commit calldata", []cli.Flag{startL1BlockFlag, endL1BlockFlag}, backfill)
+}
+
+func backfill(ctx *cli.Context) error {
+	return withDB(ctx, func(cfg *config.Config, db *gorm.DB) error {
+		genesis, err := utils.ReadGenesis(ctx.String(utils.Genesis.Name))
+		if err != nil {
+			return fmt.Errorf("failed to read genesis: %w", err)
+		}
+		l1client, err := ethclient.Dial(cfg.L1Config.Endpoint)
+		if err != nil {
+			return fmt.Errorf("failed to connect l1 geth: %w", err)
+		}
+
+		backfiller := watcher.NewBatchBackfiller(ctx.Context, db, l1client, cfg.L1Config.ScrollChainContractAddress, genesis.Config)
+		result, err := backfiller.Backfill(ctx.Uint64(startL1BlockFlag.Name), ctx.Uint64(endL1BlockFlag.Name))
+		if err != nil {
+			return fmt.Errorf("failed to backfill batches: %w", err)
+		}
+		return printJSON(result)
+	})
+}
+
+func backfillRowConsumptionCommand() *cli.Command {
+	flags := []cli.Flag{capacityCheckerEndpointFlag, capacityCheckerMethodFlag, backfillBatchSizeFlag}
+	return maintenanceCommand("backfill-row-consumption", "Backfill the row consumption of the L2 blocks stored without it", flags, backfillRowConsumption)
+}
+
+func backfillRowConsumption(ctx *cli.Context) error {
+	return withDB(ctx, func(cfg *config.Config, db *gorm.DB) error {
+		backfillCfg := config.RowConsumptionBackfillConfig{BatchSize: ctx.Uint64(backfillBatchSizeFlag.Name)}
+		if configured := cfg.L2Config.RowConsumptionBackfillConfig; configured != nil {
+			backfillCfg.CapacityCheckerEndpoint = configured.CapacityCheckerEndpoint
+			backfillCfg.CapacityCheckerMethod = configured.CapacityCheckerMethod
+		}
+		if ctx.IsSet(capacityCheckerEndpointFlag.Name) {
+			backfillCfg.CapacityCheckerEndpoint = ctx.String(capacityCheckerEndpointFlag.Name)
+		}
+		if ctx.IsSet(capacityCheckerMethodFlag.Name) {
+			backfillCfg.CapacityCheckerMethod = ctx.String(capacityCheckerMethodFlag.Name)
+		}
+		if backfillCfg.BatchSize == 0 {
+			return errors.New("batch size should be positive")
+		}
+		if backfillCfg.CapacityCheckerEndpoint != "" && backfillCfg.CapacityCheckerMethod == "" {
+			return errors.New("capacity checker method is required with a capacity checker endpoint")
+		}
+
+		l2client, err := ethclient.Dial(cfg.L2Config.Endpoint)
+		if err != nil {
+			return fmt.Errorf("failed to connect l2 geth: %w", err)
+		}
+
+		backfiller, err := watcher.NewRowConsumptionBackfiller(ctx.Context, &backfillCfg, l2client, db, prometheus.NewRegistry())
+		if err != nil {
+			return err
+		}
+		backfilled, err := backfiller.BackfillAll()
+		if err != nil {
+			return fmt.Errorf("failed to backfill row consumption after %d blocks: %w", backfilled, err)
+		}
+		log.Info("row consumption backfill completed", "blocks", backfilled)
+		return nil
+	})
+}
+
+func dryRunCommitCommand() *cli.Command {
+	return maintenanceCommand("dry-run-commit", "Print the commitBatch transaction of the next pending batch and its estimated gas without sending it", nil, dryRunCommit)
+}
+
+func dryRunCommit(ctx *cli.Context) error {
+	return withDB(ctx, func(cfg *config.Config, db *gorm.DB) error {
+		genesis, err := utils.ReadGenesis(ctx.String(utils.Genesis.Name))
+		if err != nil {
+			return fmt.Errorf("failed to read genesis: %w", err)
+		}
+		l1client, err := ethclient.Dial(cfg.L2Config.RelayerConfig.SenderConfig.Endpoint)
+		if err != nil {
+			return fmt.Errorf("failed to connect l1 geth: %w", err)
+		}
+
+		dryRun, err := relayer.DryRunCommit(ctx.Context, l1client, db, cfg.L2Config.RelayerConfig, genesis.Config)
+		if err != nil {
+			return err
+		}
+		if dryRun == nil {
+			log.Info("no pending batch to commit")
+			return nil
+		}
+		return printJSON(dryRun)
+	})
+}
+
+func migrateCommand() *cli.Command {
+	return maintenanceCommand("migrate", "Apply the pending database migrations", nil, runMigrate)
+}
+
+func runMigrate(ctx *cli.Context) error {
+	return withDB(ctx, func(_ *config.Config, db *gorm.DB) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		if err := migrate.Migrate(sqlDB); err != nil {
+			return fmt.Errorf("failed to migrate: %w", err)
+		}
+		version, err := migrate.Current(sqlDB)
+		if err != nil {
+			return err
+		}
+		log.Info("database migrated", "version", version)
+		return nil
+	})
+}
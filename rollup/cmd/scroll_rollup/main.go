@@ -0,0 +1,7 @@
+package main
+
+import "scroll-tech/rollup/cmd/scroll_rollup/app"
+
+func main() {
+	app.Run()
+}
@@ -6,20 +6,263 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/scroll-tech/go-ethereum/common"
+
 	"scroll-tech/common/database"
 )
 
 // Config load configuration items.
 type Config struct {
-	L1Config *L1Config        `json:"l1_config"`
-	L2Config *L2Config        `json:"l2_config"`
-	DBConfig *database.Config `json:"db_config"`
+	L1Config       *L1Config        `json:"l1_config"`
+	L2Config       *L2Config        `json:"l2_config"`
+	DBConfig       *database.Config `json:"db_config"`
+	AdminAPIConfig *AdminAPIConfig  `json:"admin_api_config,omitempty"`
+	// FeatureFlags gates the experimental behaviors of the deployment, overridable in the database.
+	FeatureFlags map[string]*FeatureFlagConfig `json:"feature_flags,omitempty"`
+}
+
+// FeatureFlagConfig loads the configured state of a feature flag.
+type FeatureFlagConfig struct {
+	Enabled bool `json:"enabled"`
+	// RolloutPercentage is the percentage of the subjects (e.g. batches) the flag is enabled for, 100 if not set.
+	RolloutPercentage *uint64 `json:"rollout_percentage,omitempty"`
+}
+
+// AdminAPIConfig loads the admin api configuration items.
+type AdminAPIConfig struct {
+	// AdminToken is the bearer token required by the admin api, the admin api is disabled if empty.
+	AdminToken string `json:"admin_token"`
 }
 
 func (c *Config) validate() error {
 	if maxChunkPerBatch := c.L2Config.BatchProposerConfig.MaxChunkNumPerBatch; maxChunkPerBatch <= 0 {
 		return fmt.Errorf("Invalid max_chunk_num_per_batch configuration: %v", maxChunkPerBatch)
 	}
+	if subCfg := c.L1Config.SubscriptionConfig; subCfg != nil && subCfg.WSEndpoint == "" {
+		return fmt.Errorf("Invalid l1_config.subscription_config.ws_endpoint configuration: missing")
+	}
+	if subCfg := c.L2Config.SubscriptionConfig; subCfg != nil && subCfg.WSEndpoint == "" {
+		return fmt.Errorf("Invalid l2_config.subscription_config.ws_endpoint configuration: missing")
+	}
+	if fetchCfg := c.L2Config.BlockFetchConfig; fetchCfg != nil && fetchCfg.Parallelism <= 0 {
+		return fmt.Errorf("Invalid l2_config.block_fetch_config.parallelism configuration: %v", fetchCfg.Parallelism)
+	}
+	if fetchCfg := c.L2Config.BlockFetchConfig; fetchCfg != nil && fetchCfg.PrefetchDepth < 0 {
+		return fmt.Errorf("Invalid l2_config.block_fetch_config.prefetch_depth configuration: %v", fetchCfg.PrefetchDepth)
+	}
+	if quarantineCfg := c.L2Config.BlockQuarantineConfig; quarantineCfg != nil && quarantineCfg.MaxAttempts == 0 {
+		return fmt.Errorf("Invalid l2_config.block_quarantine_config.max_attempts configuration: %v", quarantineCfg.MaxAttempts)
+	}
+	if indexCfg := c.L2Config.MessageIndexConfig; indexCfg != nil && indexCfg.L2ScrollMessengerAddress == (common.Address{}) {
+		return fmt.Errorf("Invalid l2_config.message_index_config.l2_scroll_messenger_address configuration: missing")
+	}
+	if failoverCfg := c.L2Config.RPCFailoverConfig; failoverCfg != nil && len(failoverCfg.Endpoints) == 0 {
+		return fmt.Errorf("Invalid l2_config.rpc_failover_config.endpoints configuration: missing")
+	}
+	if crossCheckCfg := c.L2Config.CrossCheckConfig; crossCheckCfg != nil && crossCheckCfg.Endpoint == "" {
+		return fmt.Errorf("Invalid l2_config.cross_check_config.endpoint configuration: missing")
+	}
+	if failoverCfg := c.L1Config.RPCFailoverConfig; failoverCfg != nil && len(failoverCfg.Endpoints) == 0 {
+		return fmt.Errorf("Invalid l1_config.rpc_failover_config.endpoints configuration: missing")
+	}
+	if blobCfg := c.L1Config.BlobVerificationConfig; blobCfg != nil && blobCfg.BeaconEndpoint == "" {
+		return fmt.Errorf("Invalid l1_config.blob_verification_config.beacon_endpoint configuration: missing")
+	}
+	if beaconCfg := c.L1Config.BeaconCheckpointsConfig; beaconCfg != nil && beaconCfg.BeaconEndpoint == "" {
+		return fmt.Errorf("Invalid l1_config.beacon_checkpoints_config.beacon_endpoint configuration: missing")
+	}
+	if queueCfg := c.L1Config.ForcedTxQueueConfig; queueCfg != nil && queueCfg.MaxInclusionDelaySec == 0 {
+		return fmt.Errorf("Invalid l1_config.forced_tx_queue_config.max_inclusion_delay_sec configuration: missing")
+	}
+	if standbyCfg := c.L1Config.StandbyConfig; standbyCfg != nil {
+		if standbyCfg.HeartbeatIntervalSec == 0 || standbyCfg.HeartbeatIntervalSec >= standbyCfg.LeaseDurationSec {
+			return fmt.Errorf("Invalid l1_config.standby_config.heartbeat_interval_sec configuration: %v, must be in (0, lease_duration_sec)", standbyCfg.HeartbeatIntervalSec)
+		}
+	}
+	versionNames := make(map[string]bool)
+	for _, versionCfg := range c.L1Config.ContractVersions {
+		if versionCfg.Name == "" || versionNames[versionCfg.Name] {
+			return fmt.Errorf("Invalid l1_config.contract_versions configuration: missing or duplicated name %q", versionCfg.Name)
+		}
+		versionNames[versionCfg.Name] = true
+		if versionCfg.EndHeight != 0 && versionCfg.EndHeight < versionCfg.StartHeight {
+			return fmt.Errorf("Invalid l1_config.contract_versions configuration: end_height of %v before start_height", versionCfg.Name)
+		}
+		if versionCfg.L1MessageQueueAddress == (common.Address{}) || versionCfg.ScrollChainContractAddress == (common.Address{}) {
+			return fmt.Errorf("Invalid l1_config.contract_versions configuration: missing contract address of %v", versionCfg.Name)
+		}
+	}
+	if fetchCfg := c.L1Config.LogFetchConfig; fetchCfg != nil {
+		if fetchCfg.MaxBlockRange != 0 && fetchCfg.MinBlockRange > fetchCfg.MaxBlockRange {
+			return fmt.Errorf("Invalid l1_config.log_fetch_config.min_block_range configuration: greater than max_block_range")
+		}
+		if fetchCfg.InitialBlockRange != 0 && (fetchCfg.InitialBlockRange < fetchCfg.MinBlockRange || (fetchCfg.MaxBlockRange != 0 && fetchCfg.InitialBlockRange > fetchCfg.MaxBlockRange)) {
+			return fmt.Errorf("Invalid l1_config.log_fetch_config.initial_block_range configuration: out of [min_block_range, max_block_range]")
+		}
+	}
+	if err := c.L2Config.ChunkProposerConfig.ProposeInterval.validate(); err != nil {
+		return fmt.Errorf("Invalid chunk_proposer_config: %w", err)
+	}
+	if err := c.L2Config.ChunkProposerConfig.validateProfiles(); err != nil {
+		return err
+	}
+	if err := c.L2Config.BatchProposerConfig.ProposeInterval.validate(); err != nil {
+		return fmt.Errorf("Invalid batch_proposer_config: %w", err)
+	}
+	if dynamicCfg := c.L2Config.BatchProposerConfig.DynamicMaxChunks; dynamicCfg != nil {
+		if dynamicCfg.MinChunkNumPerBatch == 0 || dynamicCfg.MinChunkNumPerBatch > c.L2Config.BatchProposerConfig.MaxChunkNumPerBatch {
+			return fmt.Errorf("Invalid batch_proposer_config.dynamic_max_chunks.min_chunk_num_per_batch configuration: %v", dynamicCfg.MinChunkNumPerBatch)
+		}
+		if dynamicCfg.LowBlobBaseFee >= dynamicCfg.HighBlobBaseFee {
+			return fmt.Errorf("Invalid batch_proposer_config.dynamic_max_chunks.high_blob_base_fee configuration: %v, not greater than low_blob_base_fee: %v", dynamicCfg.HighBlobBaseFee, dynamicCfg.LowBlobBaseFee)
+		}
+	}
+	if daModeCfg := c.L2Config.BatchProposerConfig.DAModeSelection; daModeCfg != nil && daModeCfg.MinSavingPercent >= 100 {
+		return fmt.Errorf("Invalid batch_proposer_config.da_mode_selection.min_saving_percent configuration: %v", daModeCfg.MinSavingPercent)
+	}
+	if daModeCfg := c.L2Config.BatchProposerConfig.DAModeSelection; daModeCfg != nil && daModeCfg.BlobFeeSpikeMultiplier != 0 && daModeCfg.BlobFeeSpikeMultiplier <= 1 {
+		return fmt.Errorf("Invalid batch_proposer_config.da_mode_selection.blob_fee_spike_multiplier configuration: %v", daModeCfg.BlobFeeSpikeMultiplier)
+	}
+	if congestionCfg := c.L2Config.BatchProposerConfig.CongestionTimeout; congestionCfg != nil {
+		if congestionCfg.BaseFeeCeiling == 0 && congestionCfg.BlobBaseFeeCeiling == 0 {
+			return fmt.Errorf("Invalid batch_proposer_config.congestion_timeout configuration: neither base_fee_ceiling nor blob_base_fee_ceiling is set")
+		}
+		if congestionCfg.MaxDelaySec == 0 {
+			return fmt.Errorf("Invalid batch_proposer_config.congestion_timeout.max_delay_sec configuration: %v", congestionCfg.MaxDelaySec)
+		}
+	}
+	for name, flagCfg := range c.FeatureFlags {
+		if flagCfg == nil {
+			return fmt.Errorf("Invalid feature_flags.%s configuration: missing", name)
+		}
+		if flagCfg.RolloutPercentage != nil && *flagCfg.RolloutPercentage > 100 {
+			return fmt.Errorf("Invalid feature_flags.%s.rollout_percentage configuration: %v", name, *flagCfg.RolloutPercentage)
+		}
+	}
+	if governorCfg := c.L2Config.ThroughputGovernorConfig; governorCfg != nil {
+		if governorCfg.BaseGasLimit == 0 {
+			return fmt.Errorf("Invalid throughput_governor_config.base_gas_limit configuration: %v", governorCfg.BaseGasLimit)
+		}
+		if governorCfg.MinGasLimitRatio <= 0 || governorCfg.MinGasLimitRatio > 1 {
+			return fmt.Errorf("Invalid throughput_governor_config.min_gas_limit_ratio configuration: %v", governorCfg.MinGasLimitRatio)
+		}
+	}
+	if backfillCfg := c.L2Config.RowConsumptionBackfillConfig; backfillCfg != nil {
+		if backfillCfg.BatchSize == 0 {
+			return fmt.Errorf("Invalid row_consumption_backfill_config.batch_size configuration: %v", backfillCfg.BatchSize)
+		}
+		if backfillCfg.CapacityCheckerEndpoint != "" && backfillCfg.CapacityCheckerMethod == "" {
+			return fmt.Errorf("Invalid row_consumption_backfill_config.capacity_checker_method configuration: missing")
+		}
+	}
+	if committeeCfg := c.L2Config.RelayerConfig.CommitteeConfig; committeeCfg != nil {
+		if committeeCfg.Threshold == 0 || committeeCfg.Threshold > uint64(len(committeeCfg.Members)) {
+			return fmt.Errorf("Invalid relayer_config.committee_config.threshold configuration: %v, members: %v", committeeCfg.Threshold, len(committeeCfg.Members))
+		}
+		members := make(map[common.Address]struct{}, len(committeeCfg.Members))
+		for _, member := range committeeCfg.Members {
+			if _, ok := members[member.Address]; ok {
+				return fmt.Errorf("Invalid relayer_config.committee_config.members configuration: duplicate member %v", member.Address.Hex())
+			}
+			members[member.Address] = struct{}{}
+		}
+	}
+	for _, relayerCfg := range []*RelayerConfig{c.L1Config.RelayerConfig, c.L2Config.RelayerConfig} {
+		if relayerCfg == nil || relayerCfg.SenderConfig == nil {
+			continue
+		}
+		if multiplier := relayerCfg.SenderConfig.GasLimitMultiplier; multiplier != 0 && multiplier < 1 {
+			return fmt.Errorf("Invalid relayer_config.sender_config.gas_limit_multiplier configuration: %v", multiplier)
+		}
+	}
+	if crossCheckCfg := c.L2Config.RelayerConfig.FinalizeCrossCheckConfig; crossCheckCfg != nil && crossCheckCfg.Endpoint == "" {
+		return fmt.Errorf("Invalid relayer_config.finalize_cross_check_config.endpoint configuration: missing")
+	}
+	if multisigCfg := c.L2Config.RelayerConfig.MultisigConfig; multisigCfg != nil {
+		if multisigCfg.Mode != MultisigModeSafe && multisigCfg.Mode != MultisigModeTimelock {
+			return fmt.Errorf("Invalid relayer_config.multisig_config.mode configuration: %v", multisigCfg.Mode)
+		}
+		if multisigCfg.Address == (common.Address{}) {
+			return fmt.Errorf("Invalid relayer_config.multisig_config.address configuration: missing")
+		}
+		if c.L2Config.RelayerConfig.CommitteeConfig != nil {
+			return fmt.Errorf("Invalid relayer_config.multisig_config configuration: exclusive with committee_config")
+		}
+	}
+	if daCfg := c.L2Config.RelayerConfig.DABackendConfig; daCfg != nil {
+		switch {
+		case daCfg.Type == DABackendTypeObjectStore && daCfg.Endpoint == "":
+			return fmt.Errorf("Invalid relayer_config.da_backend_config.endpoint configuration: missing")
+		case daCfg.Type == DABackendTypeFilesystem && daCfg.Directory == "":
+			return fmt.Errorf("Invalid relayer_config.da_backend_config.directory configuration: missing")
+		case daCfg.Type != DABackendTypeObjectStore && daCfg.Type != DABackendTypeFilesystem:
+			return fmt.Errorf("Invalid relayer_config.da_backend_config.type configuration: %v", daCfg.Type)
+		}
+		if c.L1Config.BlobVerificationConfig != nil {
+			return fmt.Errorf("Invalid relayer_config.da_backend_config configuration: exclusive with blob_verification_config")
+		}
+	}
+	proofVersions := make(map[[2]uint64]bool)
+	for _, proofVersion := range c.L2Config.RelayerConfig.ProofVersions {
+		if proofVersion.Name == "" || proofVersion.VkHash == (common.Hash{}) {
+			return fmt.Errorf("Invalid relayer_config.proof_versions configuration: name and vk_hash are required")
+		}
+		route := [2]uint64{uint64(proofVersion.VerifierVersion), proofVersion.StartBatchIndex}
+		if proofVersions[route] {
+			return fmt.Errorf("Invalid relayer_config.proof_versions configuration: duplicated verifier_version %v and start_batch_index %v", proofVersion.VerifierVersion, proofVersion.StartBatchIndex)
+		}
+		proofVersions[route] = true
+	}
+	if breakerCfg := c.L2Config.RelayerConfig.CircuitBreakerConfig; breakerCfg != nil && breakerCfg.MaxConsecutiveFailures == 0 {
+		return fmt.Errorf("Invalid relayer_config.circuit_breaker_config.max_consecutive_failures configuration: %v", breakerCfg.MaxConsecutiveFailures)
+	}
+	return nil
+}
+
+func (c *ProposeIntervalConfig) validate() error {
+	if c == nil {
+		return nil
+	}
+	switch c.Policy {
+	case "", ProposePolicyAdaptive, ProposePolicyFixed:
+	default:
+		return fmt.Errorf("Invalid propose_interval.policy configuration: %v", c.Policy)
+	}
+	if c.MinIntervalMs == 0 {
+		return fmt.Errorf("Invalid propose_interval.min_interval_ms configuration: %v", c.MinIntervalMs)
+	}
+	if c.Policy != ProposePolicyFixed && c.MaxIntervalMs < c.MinIntervalMs {
+		return fmt.Errorf("Invalid propose_interval.max_interval_ms configuration: %v, less than min_interval_ms: %v", c.MaxIntervalMs, c.MinIntervalMs)
+	}
+	return nil
+}
+
+func (c *ChunkProposerConfig) validateProfiles() error {
+	for name, profileCfg := range c.Profiles {
+		if profileCfg == nil {
+			return fmt.Errorf("Invalid chunk_proposer_config.profiles.%s configuration: missing", name)
+		}
+		maxBlockNum := profileCfg.MaxBlockNumPerChunk
+		if maxBlockNum == 0 {
+			maxBlockNum = c.MaxBlockNumPerChunk
+		}
+		if profileCfg.MinBlockNumPerChunk > maxBlockNum {
+			return fmt.Errorf("Invalid chunk_proposer_config.profiles.%s.min_block_num_per_chunk configuration: %v, greater than max_block_num_per_chunk: %v", name, profileCfg.MinBlockNumPerChunk, maxBlockNum)
+		}
+		if profileCfg.MaxChunkDelaySec != 0 && profileCfg.MaxChunkDelaySec < profileCfg.ChunkTimeoutSec {
+			return fmt.Errorf("Invalid chunk_proposer_config.profiles.%s.max_chunk_delay_sec configuration: %v, less than chunk_timeout_sec: %v", name, profileCfg.MaxChunkDelaySec, profileCfg.ChunkTimeoutSec)
+		}
+	}
+	if c.Profile == "" {
+		return nil
+	}
+	switch c.Profile {
+	case ChunkProfileLowLatency, ChunkProfileBalanced, ChunkProfileCostOptimized:
+		return nil
+	}
+	if _, ok := c.Profiles[c.Profile]; !ok {
+		return fmt.Errorf("Invalid chunk_proposer_config.profile configuration: unknown profile %v", c.Profile)
+	}
 	return nil
 }
 
@@ -55,4 +55,53 @@ func TestConfig(t *testing.T) {
 		_, err = NewConfig(tmpFile.Name())
 		assert.Error(t, err)
 	})
+
+	t.Run("Propose Interval", func(t *testing.T) {
+		var nilCfg *ProposeIntervalConfig
+		assert.NoError(t, nilCfg.validate())
+		minInterval, maxInterval, jitter := nilCfg.Intervals(2 * time.Second)
+		assert.Equal(t, 2*time.Second, minInterval)
+		assert.Equal(t, 2*time.Second, maxInterval)
+		assert.Equal(t, time.Duration(0), jitter)
+
+		cfg := &ProposeIntervalConfig{MinIntervalMs: 500, MaxIntervalMs: 4000, JitterMs: 200}
+		assert.NoError(t, cfg.validate())
+		minInterval, maxInterval, jitter = cfg.Intervals(2 * time.Second)
+		assert.Equal(t, 500*time.Millisecond, minInterval)
+		assert.Equal(t, 4*time.Second, maxInterval)
+		assert.Equal(t, 200*time.Millisecond, jitter)
+
+		assert.Error(t, (&ProposeIntervalConfig{MinIntervalMs: 0, MaxIntervalMs: 4000}).validate())
+		assert.Error(t, (&ProposeIntervalConfig{MinIntervalMs: 500, MaxIntervalMs: 400}).validate())
+
+		fixedCfg := &ProposeIntervalConfig{Policy: ProposePolicyFixed, MinIntervalMs: 60000, JitterMs: 1000}
+		assert.NoError(t, fixedCfg.validate())
+		minInterval, maxInterval, jitter = fixedCfg.Intervals(2 * time.Second)
+		assert.Equal(t, time.Minute, minInterval)
+		assert.Equal(t, time.Minute, maxInterval)
+		assert.Equal(t, time.Second, jitter)
+
+		assert.NoError(t, (&ProposeIntervalConfig{Policy: ProposePolicyAdaptive, MinIntervalMs: 500, MaxIntervalMs: 4000}).validate())
+		assert.Error(t, (&ProposeIntervalConfig{Policy: "cron", MinIntervalMs: 500, MaxIntervalMs: 4000}).validate())
+	})
+
+	t.Run("Chunk Proposer Profiles", func(t *testing.T) {
+		cfg := &ChunkProposerConfig{MaxBlockNumPerChunk: 100, Profile: ChunkProfileCostOptimized}
+		assert.NoError(t, cfg.validateProfiles())
+
+		cfg.Profile = "night"
+		assert.Error(t, cfg.validateProfiles())
+		cfg.Profiles = map[string]*ChunkProposerProfileConfig{"night": {ChunkTimeoutSec: 600, MinBlockNumPerChunk: 50}}
+		assert.NoError(t, cfg.validateProfiles())
+
+		cfg.Profiles["night"].MinBlockNumPerChunk = 101
+		assert.Error(t, cfg.validateProfiles())
+		cfg.Profiles["night"].MaxBlockNumPerChunk = 200
+		assert.NoError(t, cfg.validateProfiles())
+
+		cfg.Profiles["night"].MaxChunkDelaySec = 300
+		assert.Error(t, cfg.validateProfiles())
+		cfg.Profiles["night"].MaxChunkDelaySec = 1800
+		assert.NoError(t, cfg.validateProfiles())
+	})
 }
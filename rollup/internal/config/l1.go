@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/rpc"
 )
@@ -9,6 +11,8 @@ import (
 type L1Config struct {
 	// Confirmations block height confirmations number.
 	Confirmations rpc.BlockNumber `json:"confirmations"`
+	// The per event type confirmations, overriding Confirmations for the configured event types.
+	EventConfirmations *EventConfirmationsConfig `json:"event_confirmations,omitempty"`
 	// l1 eth node url.
 	Endpoint string `json:"endpoint"`
 	// The start height to sync event from layer 1
@@ -17,6 +21,180 @@ type L1Config struct {
 	L1MessageQueueAddress common.Address `json:"l1_message_queue_address"`
 	// The ScrollChain contract address deployed on layer 1 chain.
 	ScrollChainContractAddress common.Address `json:"scroll_chain_address"`
+	// The L1ScrollMessenger contract address deployed on layer 1 chain, used to tell
+	// bridge messages apart from enforced (forced-inclusion) transactions.
+	L1ScrollMessengerAddress common.Address `json:"l1_scroll_messenger_address"`
 	// The relayer config
 	RelayerConfig *RelayerConfig `json:"relayer_config"`
+	// The on-chain rollup parameter drift detection config, the detection is disabled if not set.
+	ParamDriftConfig *ParamDriftConfig `json:"param_drift_config,omitempty"`
+	// The eth_subscribe based head subscription config, the l1 watcher only polls if not set.
+	SubscriptionConfig *HeadSubscriptionConfig `json:"subscription_config,omitempty"`
+	// The multi-endpoint rpc failover config, only Endpoint is used if not set.
+	RPCFailoverConfig *RPCFailoverConfig `json:"rpc_failover_config,omitempty"`
+	// The blob verification config, the blobs of the commit transactions are not verified if not set.
+	BlobVerificationConfig *BlobVerificationConfig `json:"blob_verification_config,omitempty"`
+	// The adaptive block range config of the log queries, the defaults are used if not set.
+	LogFetchConfig *LogFetchConfig `json:"log_fetch_config,omitempty"`
+	// The on-chain system config watcher config, the on-chain config updates are not applied if not set.
+	SystemConfigWatcherConfig *SystemConfigWatcherConfig `json:"system_config_watcher_config,omitempty"`
+	// The l1 watcher sync lag alert config, the sync lag is only exported as metrics if not set.
+	SyncLagAlertConfig *SyncLagAlertConfig `json:"sync_lag_alert_config,omitempty"`
+	// The forced transaction queue config, the enforced transactions are stored without deadline if not set.
+	ForcedTxQueueConfig *ForcedTxQueueConfig `json:"forced_tx_queue_config,omitempty"`
+	// The deployed versions of the rollup contracts watched by the l1 watcher, the contracts at
+	// l1_message_queue_address and scroll_chain_address are watched at all heights if not set.
+	ContractVersions []*ContractVersionConfig `json:"contract_versions,omitempty"`
+	// The hot-standby config of the l1 watcher, the l1 watcher imports the events unconditionally if not set.
+	StandbyConfig *L1WatcherStandbyConfig `json:"standby_config,omitempty"`
+	// The beacon checkpoints config, the safe and finalized heads are the ones of the l1 node if not set.
+	BeaconCheckpointsConfig *BeaconCheckpointsConfig `json:"beacon_checkpoints_config,omitempty"`
+}
+
+// BeaconCheckpointsConfig loads the configuration items of the safe and finalized heads taken from the justified
+// and finalized checkpoints of the beacon chain, for the event types confirmed by "safe" or "finalized".
+type BeaconCheckpointsConfig struct {
+	// The beacon node api endpoint.
+	BeaconEndpoint string `json:"beacon_endpoint"`
+	// The event filters (l1_message, commit_batch, finalize_batch) using the beacon checkpoints, all if not set.
+	EventFilters []string `json:"event_filters,omitempty"`
+}
+
+// L1WatcherStandbyConfig loads the configuration items of the hot-standby mode of the l1 watcher. The instances
+// sharing the database elect a leader through a lease in the database, the others follow its progress read-only
+// and take over when its lease expires.
+type L1WatcherStandbyConfig struct {
+	// The name of the lease, l1_watcher if not set. The instances sharing a lease replace each other.
+	LeaseName string `json:"lease_name,omitempty"`
+	// The id of the instance in the lease, the host name and the process id if not set.
+	InstanceID string `json:"instance_id,omitempty"`
+	// The duration of the lease, after which a standby instance takes over if the leader did not renew it, in seconds.
+	LeaseDurationSec uint64 `json:"lease_duration_sec"`
+	// The interval of the heartbeats renewing the lease, in seconds. Must be below lease_duration_sec.
+	HeartbeatIntervalSec uint64 `json:"heartbeat_interval_sec"`
+}
+
+// ContractVersionConfig loads a deployed version of the L1 rollup contracts, watched in a range of L1 blocks.
+// The ranges of the versions before and after an upgrade may overlap, so that the cutover needs no restart.
+type ContractVersionConfig struct {
+	// The name of the version, used in the logs.
+	Name string `json:"name"`
+	// The first L1 block of the version.
+	StartHeight uint64 `json:"start_height"`
+	// The last L1 block of the version, open-ended if not set.
+	EndHeight uint64 `json:"end_height,omitempty"`
+	// The L1MessageQueue contract address of the version.
+	L1MessageQueueAddress common.Address `json:"l1_message_queue_address"`
+	// The ScrollChain contract address of the version.
+	ScrollChainContractAddress common.Address `json:"scroll_chain_address"`
+	// The L1ScrollMessenger contract address of the version, l1_scroll_messenger_address if not set.
+	L1ScrollMessengerAddress common.Address `json:"l1_scroll_messenger_address,omitempty"`
+	// The path of the JSON ABI of the L1MessageQueue contract of the version, the built-in ABI if not set.
+	L1MessageQueueABIPath string `json:"l1_message_queue_abi_path,omitempty"`
+	// The path of the JSON ABI of the ScrollChain contract of the version, the built-in ABI if not set.
+	ScrollChainABIPath string `json:"scroll_chain_abi_path,omitempty"`
+}
+
+// ForcedTxQueueConfig loads the configuration items of the forced transaction queue.
+type ForcedTxQueueConfig struct {
+	// The max delay between the L1 block queueing an enforced transaction and its inclusion, in seconds.
+	MaxInclusionDelaySec uint64 `json:"max_inclusion_delay_sec"`
+}
+
+// SyncLagAlertConfig loads the alert thresholds of the gap between the L1 head and the heights processed by the l1 watcher.
+// The gap includes the confirmations of the event filters, the thresholds must be above them.
+type SyncLagAlertConfig struct {
+	// The max gap in blocks of every event filter, not alerted if not set.
+	MaxLagBlocks uint64 `json:"max_lag_blocks,omitempty"`
+	// The max gaps in blocks by event filter (l1_message, commit_batch, finalize_batch), overriding MaxLagBlocks.
+	EventFilterMaxLagBlocks map[string]uint64 `json:"event_filter_max_lag_blocks,omitempty"`
+}
+
+// SystemConfigWatcherConfig loads the configuration items of the watcher applying the configuration updates
+// of the L1 rollup contracts to the running components.
+type SystemConfigWatcherConfig struct {
+	// The height to replay the configuration updates from at startup, start_height if not set.
+	StartHeight uint64 `json:"start_height,omitempty"`
+	// The interval of the checks for new configuration updates, in seconds.
+	CheckIntervalSec uint64 `json:"check_interval_sec"`
+}
+
+// LogFetchConfig loads the configuration items of the adaptive block range of the l1 log queries.
+type LogFetchConfig struct {
+	// The block range of the first log query, 10 if not set.
+	InitialBlockRange uint64 `json:"initial_block_range,omitempty"`
+	// The block range the log queries never shrink below, 1 if not set.
+	MinBlockRange uint64 `json:"min_block_range,omitempty"`
+	// The block range the log queries never grow above, 1000 if not set.
+	MaxBlockRange uint64 `json:"max_block_range,omitempty"`
+	// The number of logs under which a response grows the block range, 100 if not set.
+	SmallResponseLogs uint64 `json:"small_response_logs,omitempty"`
+	// The max block ranges accepted by the rpc providers, by endpoint. Any query may be served by any
+	// of the endpoints in use, so the block range never grows above the lowest of their caps.
+	ProviderMaxBlockRanges map[string]uint64 `json:"provider_max_block_ranges,omitempty"`
+}
+
+// BlobVerificationConfig loads the configuration items of the verification of the committed blobs against the beacon node.
+type BlobVerificationConfig struct {
+	// The beacon node api url.
+	BeaconEndpoint string `json:"beacon_endpoint"`
+	// The duration of a beacon slot, in seconds, 12 if not set.
+	SecondsPerSlot uint64 `json:"seconds_per_slot,omitempty"`
+	// The index of the first batch to verify, the latest batch at startup if not set.
+	StartBatchIndex uint64 `json:"start_batch_index,omitempty"`
+	// The interval of the verification, in seconds.
+	CheckIntervalSec uint64 `json:"check_interval_sec"`
+}
+
+// RPCFailoverConfig loads the rpc failover configuration items, of the l1 or the l2 eth nodes.
+type RPCFailoverConfig struct {
+	// The eth node urls failed over to, in addition to Endpoint, only http(s) urls are supported.
+	Endpoints []string `json:"endpoints"`
+	// The interval to re-probe the health of the endpoints, in seconds.
+	ProbeIntervalSec uint64 `json:"probe_interval_sec"`
+}
+
+// RPCEndpoints returns the l1 eth node urls, Endpoint first.
+func (c *L1Config) RPCEndpoints() []string {
+	endpoints := []string{c.Endpoint}
+	if c.RPCFailoverConfig != nil {
+		endpoints = append(endpoints, c.RPCFailoverConfig.Endpoints...)
+	}
+	return endpoints
+}
+
+// RPCProbeInterval returns the interval to re-probe the health of the l1 eth nodes, 0 for the default.
+func (c *L1Config) RPCProbeInterval() time.Duration {
+	if c.RPCFailoverConfig == nil {
+		return 0
+	}
+	return time.Duration(c.RPCFailoverConfig.ProbeIntervalSec) * time.Second
+}
+
+// EventConfirmationsConfig loads the per event type confirmations, the unset ones default to Confirmations.
+type EventConfirmationsConfig struct {
+	// The confirmations of the L1 messages (QueueTransaction events).
+	L1Message *rpc.BlockNumber `json:"l1_message,omitempty"`
+	// The confirmations of the CommitBatch events.
+	CommitBatch *rpc.BlockNumber `json:"commit_batch,omitempty"`
+	// The confirmations of the FinalizeBatch events.
+	FinalizeBatch *rpc.BlockNumber `json:"finalize_batch,omitempty"`
+	// The confirmations of the L1 blocks imported for the gas oracle, the gas oracle follows the latest block but one if not set.
+	GasOracle *rpc.BlockNumber `json:"gas_oracle,omitempty"`
+}
+
+// HeadSubscriptionConfig loads the head subscription configuration items of the l1 or l2 node.
+type HeadSubscriptionConfig struct {
+	// The websocket endpoint of the eth node.
+	WSEndpoint string `json:"ws_endpoint"`
+	// The interval to retry the subscription after a disconnect, in seconds, polling is used meanwhile.
+	ReconnectIntervalSec uint64 `json:"reconnect_interval_sec"`
+}
+
+// ParamDriftConfig loads the on-chain rollup parameter drift detection configuration items.
+type ParamDriftConfig struct {
+	// The interval of the drift checks, in seconds.
+	CheckIntervalSec uint64 `json:"check_interval_sec"`
+	// The expected verifier address of the ScrollChain contract, not checked if not set.
+	VerifierAddress common.Address `json:"verifier_address,omitempty"`
 }
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/scroll-tech/go-ethereum/rpc"
 
 	"github.com/scroll-tech/go-ethereum/common"
@@ -8,7 +10,8 @@ import (
 
 // L2Config loads l2geth configuration items.
 type L2Config struct {
-	// Confirmations block height confirmations number.
+	// Confirmations block height confirmations number. A number N makes the l2 watcher follow N blocks
+	// behind the l2 head, so that a tip block re-emitted by the sequencer is not imported before it's replaced.
 	Confirmations rpc.BlockNumber `json:"confirmations"`
 	// l2geth node url.
 	Endpoint string `json:"endpoint"`
@@ -22,6 +25,123 @@ type L2Config struct {
 	ChunkProposerConfig *ChunkProposerConfig `json:"chunk_proposer_config"`
 	// The batch_proposer config
 	BatchProposerConfig *BatchProposerConfig `json:"batch_proposer_config"`
+	// The throughput governor config, the governor is disabled if not set.
+	ThroughputGovernorConfig *ThroughputGovernorConfig `json:"throughput_governor_config,omitempty"`
+	// The backfill of the row consumption of the blocks stored without it, disabled if not set.
+	RowConsumptionBackfillConfig *RowConsumptionBackfillConfig `json:"row_consumption_backfill_config,omitempty"`
+	// The snapshots of the in-memory state of the proposers, disabled if not set.
+	ProposerStateSnapshotConfig *ProposerStateSnapshotConfig `json:"proposer_state_snapshot_config,omitempty"`
+	// The eth_subscribe based head subscription config, the l2 watcher only polls if not set.
+	SubscriptionConfig *HeadSubscriptionConfig `json:"subscription_config,omitempty"`
+	// The concurrent block fetching config of the l2 watcher, the blocks are fetched one at a time if not set.
+	BlockFetchConfig *L2BlockFetchConfig `json:"block_fetch_config,omitempty"`
+	// The quarantine of the blocks which repeatedly fail to be imported, disabled if not set.
+	BlockQuarantineConfig *L2BlockQuarantineConfig `json:"block_quarantine_config,omitempty"`
+	// The indexing of the L2 to L1 messages sent by the imported blocks, disabled if not set.
+	MessageIndexConfig *L2MessageIndexConfig `json:"message_index_config,omitempty"`
+	// The light storage mode pruning the transactions of the finalized blocks, the blocks are fully kept if not set.
+	LightStorageConfig *L2LightStorageConfig `json:"light_storage_config,omitempty"`
+	// The multi-endpoint rpc failover config, only Endpoint is used if not set.
+	RPCFailoverConfig *RPCFailoverConfig `json:"rpc_failover_config,omitempty"`
+	// The cross-checking of the fetched blocks against another l2geth node, disabled if not set.
+	CrossCheckConfig *L2CrossCheckConfig `json:"cross_check_config,omitempty"`
+}
+
+// RPCEndpoints returns the l2geth node urls, Endpoint first.
+func (c *L2Config) RPCEndpoints() []string {
+	endpoints := []string{c.Endpoint}
+	if c.RPCFailoverConfig != nil {
+		endpoints = append(endpoints, c.RPCFailoverConfig.Endpoints...)
+	}
+	return endpoints
+}
+
+// RPCProbeInterval returns the interval to re-probe the health of the l2geth nodes, 0 for the default.
+func (c *L2Config) RPCProbeInterval() time.Duration {
+	if c.RPCFailoverConfig == nil {
+		return 0
+	}
+	return time.Duration(c.RPCFailoverConfig.ProbeIntervalSec) * time.Second
+}
+
+// L2BlockFetchConfig loads the configuration items of the concurrent fetching of the l2 blocks. The blocks are
+// still inserted in height order, the blocks fetched before a failed one are inserted and the others fetched again.
+type L2BlockFetchConfig struct {
+	// The max number of concurrent block requests.
+	Parallelism int `json:"parallelism"`
+	// The number of blocks fetched and inserted at once in a single transaction, 10 if not set.
+	// Larger values reduce the database round trips while catching up.
+	BlocksPerInsert uint64 `json:"blocks_per_insert,omitempty"`
+	// The number of fetched ranges of blocks buffered ahead of their insertion, so that fetching the next ranges
+	// overlaps with inserting the current one. The blocks are fetched and inserted sequentially if not set.
+	PrefetchDepth int `json:"prefetch_depth,omitempty"`
+}
+
+// L2BlockQuarantineConfig loads the l2 block quarantine configuration items. A block failing to be fetched or
+// validated MaxAttempts times in a row is quarantined: it is only retried every RetryIntervalSec, until an operator
+// requests a retry or resolves it through the admin api.
+type L2BlockQuarantineConfig struct {
+	MaxAttempts      uint64 `json:"max_attempts"`
+	RetryIntervalSec uint64 `json:"retry_interval_sec"`
+}
+
+// L2MessageIndexConfig loads the l2 message indexing configuration items. The AppendMessage events of the
+// L2MessageQueue and the SentMessage events of the L2ScrollMessenger are stored with the blocks emitting them.
+type L2MessageIndexConfig struct {
+	L2ScrollMessengerAddress common.Address `json:"l2_scroll_messenger_address"`
+}
+
+// L2CrossCheckConfig loads the l2 cross-check configuration items. The hash and the withdraw root of the last block
+// of every fetched range are compared against another l2geth node before the blocks are stored. On divergence the
+// blocks are not stored and the l2 watcher fails over to another endpoint of the rpc failover config, if any.
+type L2CrossCheckConfig struct {
+	// The l2geth node url the blocks are checked against, preferably not one of the fetched endpoints.
+	Endpoint string `json:"endpoint"`
+}
+
+// L2LightStorageConfig loads the light storage mode configuration items. Once the batch containing a block is
+// finalized, the transactions of the block are discarded, only the header fields and the per-block metrics needed
+// by the chunk proposer are kept. The finalized batches can't be re-encoded from the database afterwards.
+type L2LightStorageConfig struct {
+	PruneIntervalSec uint64 `json:"prune_interval_sec"`
+	// The max number of blocks pruned per database statement, 1000 if not set.
+	PruneBatchSize uint64 `json:"prune_batch_size,omitempty"`
+}
+
+// ProposerStateSnapshotConfig loads the proposer state snapshot configuration items.
+// The in-memory state of the proposers (e.g. the cached L1 commit estimations of the pending blocks) is persisted
+// periodically and on shutdown, and restored on startup, so that a restarted proposer doesn't re-estimate its backlog.
+type ProposerStateSnapshotConfig struct {
+	SnapshotIntervalSec uint64 `json:"snapshot_interval_sec"`
+}
+
+// RowConsumptionBackfillConfig loads the row consumption backfill configuration items.
+// The row consumption of the blocks stored without it is re-queried from l2geth, and from the
+// capacity checker if l2geth doesn't return it, so that chunks can be proposed over those blocks.
+type RowConsumptionBackfillConfig struct {
+	CheckIntervalSec uint64 `json:"check_interval_sec"`
+	// The max number of blocks backfilled per check.
+	BatchSize uint64 `json:"batch_size"`
+	// The capacity checker RPC endpoint, only l2geth is queried if empty.
+	CapacityCheckerEndpoint string `json:"capacity_checker_endpoint,omitempty"`
+	// The RPC method of the capacity checker returning the row consumption of a block by number.
+	CapacityCheckerMethod string `json:"capacity_checker_method,omitempty"`
+}
+
+// ThroughputGovernorConfig loads the throughput governor configuration items.
+// The governor recommends an L2 block gas limit, lowered from BaseGasLimit down to BaseGasLimit*MinGasLimitRatio
+// as the DA backlog or the blob base fee approaches the configured maximums. A maximum of 0 ignores the signal.
+type ThroughputGovernorConfig struct {
+	CheckIntervalSec      uint64  `json:"check_interval_sec"`
+	BaseGasLimit          uint64  `json:"base_gas_limit"`
+	MinGasLimitRatio      float64 `json:"min_gas_limit_ratio"`
+	MaxUnchunkedBlocks    uint64  `json:"max_unchunked_blocks"`
+	MaxUncommittedBatches uint64  `json:"max_uncommitted_batches"`
+	MaxBlobBaseFee        uint64  `json:"max_blob_base_fee"`
+	// The sequencer admin RPC endpoint the recommended gas limit is pushed to, not pushed if empty.
+	SequencerAdminEndpoint string `json:"sequencer_admin_endpoint,omitempty"`
+	// The RPC method called with the recommended gas limit, miner_setGasLimit if empty.
+	SequencerAdminMethod string `json:"sequencer_admin_method,omitempty"`
 }
 
 // ChunkProposerConfig loads chunk_proposer configuration items.
@@ -33,6 +153,53 @@ type ChunkProposerConfig struct {
 	ChunkTimeoutSec                 uint64  `json:"chunk_timeout_sec"`
 	MaxRowConsumptionPerChunk       uint64  `json:"max_row_consumption_per_chunk"`
 	GasCostIncreaseMultiplier       float64 `json:"gas_cost_increase_multiplier"`
+	ForcedInclusionTimeoutSec       uint64  `json:"forced_inclusion_timeout_sec"`
+	// The chunk timeout applied when the pending blocks contain L1 messages, so that deposits are committed
+	// faster than ordinary traffic. Only chunk_timeout_sec applies if not set.
+	L1MessageChunkTimeoutSec uint64 `json:"l1_message_chunk_timeout_sec,omitempty"`
+	// The max total size of the uncompressed L2 transaction payloads of a chunk, unlimited if not set.
+	MaxUncompressedPayloadSizePerChunk uint64 `json:"max_uncompressed_payload_size_per_chunk,omitempty"`
+	// The max number of chunks sealed in one TryProposeChunk invocation, at most one chunk is sealed if not set.
+	MaxChunksPerTick uint64 `json:"max_chunks_per_tick,omitempty"`
+	// The scheduling of TryProposeChunk, a fixed 2s interval is used if not set.
+	ProposeInterval *ProposeIntervalConfig `json:"propose_interval,omitempty"`
+	// The profile applied at startup, one of the built-in profiles or a key of Profiles, "balanced" if not set.
+	// The built-in "balanced" profile uses the settings above as is. The profile can be switched at runtime via the admin api.
+	Profile string `json:"profile,omitempty"`
+	// Overrides of the built-in profiles, and additional named profiles.
+	Profiles map[string]*ChunkProposerProfileConfig `json:"profiles,omitempty"`
+	// The periodic release of the unbatched chunks invalidated by a fork, disabled if not set.
+	StaleChunkGC *StaleChunkGCConfig `json:"stale_chunk_gc,omitempty"`
+}
+
+// StaleChunkGCConfig loads the stale chunk garbage collection configuration items.
+// An unbatched chunk crossing a fork height or proposed with another codec than the one of its fork,
+// e.g. after a fork height is moved, is released along with the chunks after it, and its blocks are chunked again.
+type StaleChunkGCConfig struct {
+	// The interval of the stale chunk checks, 60s if not set.
+	CheckIntervalSec uint64 `json:"check_interval_sec,omitempty"`
+}
+
+// Names of the built-in chunk proposer profiles.
+const (
+	ChunkProfileLowLatency    = "low-latency"
+	ChunkProfileBalanced      = "balanced"
+	ChunkProfileCostOptimized = "cost-optimized"
+)
+
+// ChunkProposerProfileConfig bundles the chunk proposer settings trading commit latency for L1 cost.
+type ChunkProposerProfileConfig struct {
+	ChunkTimeoutSec          uint64 `json:"chunk_timeout_sec"`
+	L1MessageChunkTimeoutSec uint64 `json:"l1_message_chunk_timeout_sec,omitempty"`
+	// The min number of blocks of a chunk sealed by the chunk timeout, the chunk timeout always seals if not set.
+	// The other timeouts and the capacity limits still seal smaller chunks.
+	MinBlockNumPerChunk uint64 `json:"min_block_num_per_chunk,omitempty"`
+	// The max delay of the first block of a chunk smaller than min_block_num_per_chunk, after which the chunk
+	// timeout seals it anyway. Twice chunk_timeout_sec is used if not set.
+	MaxChunkDelaySec uint64 `json:"max_chunk_delay_sec,omitempty"`
+	// The max number of blocks packed into a chunk, max_block_num_per_chunk is used if not set.
+	MaxBlockNumPerChunk uint64 `json:"max_block_num_per_chunk,omitempty"`
+	MaxChunksPerTick    uint64 `json:"max_chunks_per_tick,omitempty"`
 }
 
 // BatchProposerConfig loads batch_proposer configuration items.
@@ -42,4 +209,94 @@ type BatchProposerConfig struct {
 	MaxL1CommitCalldataSizePerBatch uint64  `json:"max_l1_commit_calldata_size_per_batch"`
 	BatchTimeoutSec                 uint64  `json:"batch_timeout_sec"`
 	GasCostIncreaseMultiplier       float64 `json:"gas_cost_increase_multiplier"`
+	// The scheduling of TryProposeBatch, a fixed 10s interval is used if not set.
+	ProposeInterval *ProposeIntervalConfig `json:"propose_interval,omitempty"`
+	// The scaling of the max chunk number per batch with the L1 blob base fee, MaxChunkNumPerBatch is used if not set.
+	DynamicMaxChunks *DynamicMaxChunksConfig `json:"dynamic_max_chunks,omitempty"`
+	// The max row consumption of a batch, summed over its chunks per sub-circuit, so that a batch never exceeds
+	// what a batch-prover task can handle. The row consumption per batch isn't limited if not set.
+	MaxRowConsumptionPerBatch uint64 `json:"max_row_consumption_per_batch,omitempty"`
+	// The batch timeout applied when the unbatched chunks contain an enforced transaction, counted from the L1 block
+	// queueing it, so that the forced inclusions are committed faster than ordinary traffic. Only batch_timeout_sec
+	// applies if not set.
+	ForcedInclusionTimeoutSec uint64 `json:"forced_inclusion_timeout_sec,omitempty"`
+	// The extension of the batch timeout during L1 fee spikes, batch_timeout_sec always applies if not set.
+	CongestionTimeout *CongestionTimeoutConfig `json:"congestion_timeout,omitempty"`
+	// The per-batch comparison of the calldata and blob commit costs at the latest L1 fees, not compared if not set.
+	// The cheaper DA mode is only reported: the batches after the Bernoulli fork are always committed in blobs.
+	DAModeSelection *DAModeSelectionConfig `json:"da_mode_selection,omitempty"`
+}
+
+// DAModeSelectionConfig loads the DA mode selection configuration items of the batch proposer.
+// The L1 cost of committing a sealed batch in calldata (codecv0) is compared with its cost in blobs (codecv1)
+// at the latest L1 base fee and blob base fee, and the cheaper one is reported. The batch is still encoded with
+// codecv1, the only codec the provers and the verifier accept after the Bernoulli fork.
+type DAModeSelectionConfig struct {
+	// The calldata mode is only reported as the cheaper if it saves at least this percentage of the blob mode cost,
+	// so that the mode doesn't flap around the break-even fees.
+	MinSavingPercent uint64 `json:"min_saving_percent,omitempty"`
+	// The blob fee spike detection: the latest blob base fee spikes while it exceeds this multiple of its trailing
+	// average. The spikes are only reported, the batches after the Bernoulli fork don't fall back to calldata.
+	// Disabled if not set.
+	BlobFeeSpikeMultiplier float64 `json:"blob_fee_spike_multiplier,omitempty"`
+	// The number of latest L1 blocks the trailing average of the blob base fee is computed over, 300 if not set.
+	BlobFeeSpikeWindow uint64 `json:"blob_fee_spike_window,omitempty"`
+}
+
+// CongestionTimeoutConfig loads the congestion-aware batch timeout configuration items of the batch proposer.
+// While the latest L1 base fee or blob base fee exceeds its ceiling, the batch timeout is extended by MaxDelaySec,
+// so that the commits are deferred to cheaper L1 blocks. The capacity limits still seal full batches.
+type CongestionTimeoutConfig struct {
+	// The L1 base fee ceiling, the base fee is ignored if not set.
+	BaseFeeCeiling uint64 `json:"base_fee_ceiling,omitempty"`
+	// The L1 blob base fee ceiling, the blob base fee is ignored if not set.
+	BlobBaseFeeCeiling uint64 `json:"blob_base_fee_ceiling,omitempty"`
+	// The max delay added to batch_timeout_sec while a ceiling is exceeded.
+	MaxDelaySec uint64 `json:"max_delay_sec"`
+}
+
+// DynamicMaxChunksConfig loads the dynamic max chunk number configuration items of the batch proposer.
+// The max chunk number per batch is interpolated linearly between MinChunkNumPerBatch at LowBlobBaseFee and
+// MaxChunkNumPerBatch at HighBlobBaseFee, so that batches are bigger when blobs are expensive and faster when cheap.
+type DynamicMaxChunksConfig struct {
+	MinChunkNumPerBatch uint64 `json:"min_chunk_num_per_batch"`
+	LowBlobBaseFee      uint64 `json:"low_blob_base_fee"`
+	HighBlobBaseFee     uint64 `json:"high_blob_base_fee"`
+	// The max chunk number is only changed once the target moves at least HysteresisChunkNum away from it,
+	// or reaches one of the bounds.
+	HysteresisChunkNum uint64 `json:"hysteresis_chunk_num"`
+}
+
+// Scheduling policies of a proposer loop.
+const (
+	// ProposePolicyAdaptive ticks the proposer every MinIntervalMs while it keeps proposing (i.e. there is a backlog),
+	// and backs off exponentially up to MaxIntervalMs while idle.
+	ProposePolicyAdaptive = "adaptive"
+	// ProposePolicyFixed ticks the proposer every MinIntervalMs whether it proposed or not, MaxIntervalMs is ignored.
+	ProposePolicyFixed = "fixed"
+)
+
+// ProposeIntervalConfig loads the scheduling configuration items of a proposer.
+// Each proposer has its own loop, so e.g. the batch proposer can run less frequently than the chunk proposer
+// on low-traffic networks. A random delay in [0, JitterMs) is added to every interval.
+type ProposeIntervalConfig struct {
+	// Policy is the scheduling policy of the proposer loop, ProposePolicyAdaptive if empty.
+	Policy        string `json:"policy,omitempty"`
+	MinIntervalMs uint64 `json:"min_interval_ms"`
+	MaxIntervalMs uint64 `json:"max_interval_ms"`
+	JitterMs      uint64 `json:"jitter_ms"`
+}
+
+// Intervals returns the min interval, max interval and jitter of the proposer loop according to the policy,
+// falling back to a fixed defaultInterval without jitter if the config is not set.
+func (c *ProposeIntervalConfig) Intervals(defaultInterval time.Duration) (time.Duration, time.Duration, time.Duration) {
+	if c == nil {
+		return defaultInterval, defaultInterval, 0
+	}
+	minInterval := time.Duration(c.MinIntervalMs) * time.Millisecond
+	maxInterval := time.Duration(c.MaxIntervalMs) * time.Millisecond
+	if c.Policy == ProposePolicyFixed {
+		maxInterval = minInterval
+	}
+	return minInterval, maxInterval, time.Duration(c.JitterMs) * time.Millisecond
 }
@@ -14,6 +14,10 @@ import (
 type SenderConfig struct {
 	// The RPC endpoint of the ethereum or scroll public node.
 	Endpoint string `json:"endpoint"`
+	// SecondaryEndpoint is the RPC endpoint of another provider every signed transaction is also broadcast to, and
+	// the receipts are looked up from if the primary endpoint has none. Disabled if empty. The broadcast is gated by
+	// the secondary_broadcast feature flag.
+	SecondaryEndpoint string `json:"secondary_endpoint,omitempty"`
 	// The time to trigger check pending txs in sender.
 	CheckPendingTime uint64 `json:"check_pending_time"`
 	// The number of blocks to wait to escalate increase gas price of the transaction.
@@ -32,6 +36,10 @@ type SenderConfig struct {
 	MaxBlobGasPrice uint64 `json:"max_blob_gas_price"`
 	// The transaction type to use: LegacyTx, DynamicFeeTx, BlobTx
 	TxType string `json:"tx_type"`
+	// GasLimitMultiplier is the margin applied to the gas limit estimated by eth_estimateGas, 1.2 if not set.
+	GasLimitMultiplier float64 `json:"gas_limit_multiplier,omitempty"`
+	// MaxGasLimit caps the gas limit of a transaction, whose estimation must not exceed it. Not capped if 0.
+	MaxGasLimit uint64 `json:"max_gas_limit,omitempty"`
 }
 
 // ChainMonitor this config is used to get batch status from chain_monitor API.
@@ -57,6 +65,47 @@ type RelayerConfig struct {
 	ChainMonitor *ChainMonitor `json:"chain_monitor"`
 	// L1CommitGasLimitMultiplier multiplier for fallback gas limit in commitBatch txs
 	L1CommitGasLimitMultiplier float64 `json:"l1_commit_gas_limit_multiplier,omitempty"`
+	// MaxInFlightCommits is the max number of commitBatch txs sent but not confirmed yet. They are sent in batch
+	// index order with sequential nonces, and each is tracked on its own. Not bounded if 0.
+	MaxInFlightCommits uint64 `json:"max_in_flight_commits,omitempty"`
+	// CommitteeConfig requires committee approvals for commit and finalize transactions, disabled if nil.
+	CommitteeConfig *CommitteeConfig `json:"committee_config,omitempty"`
+	// AutoRebatchConfig dissolves the batches whose commit transaction reverted with a known error, disabled if nil.
+	AutoRebatchConfig *AutoRebatchConfig `json:"auto_rebatch_config,omitempty"`
+	// FinalizeHoldingL1Blocks is the number of L1 blocks a batch is held after the L1 block including its commit
+	// transaction before it can be finalized, e.g. to leave room for an external DA challenge window. Disabled if 0.
+	// The L1 blocks are the ones imported by the L1 watcher, so it must run along with the relayer.
+	FinalizeHoldingL1Blocks uint64 `json:"finalize_holding_l1_blocks,omitempty"`
+	// CircuitBreakerConfig pauses the commit or finalize pipeline after repeated failed transactions. If nil, the
+	// pipelines are only paused by the reverts aborting them, e.g. while the rollup contract is paused.
+	CircuitBreakerConfig *CircuitBreakerConfig `json:"circuit_breaker_config,omitempty"`
+	// FinalizeCrossCheckConfig checks the roots of a batch against an independent l2geth node before finalizing it,
+	// disabled if nil.
+	FinalizeCrossCheckConfig *FinalizeCrossCheckConfig `json:"finalize_cross_check_config,omitempty"`
+	// ProofFreshnessConfig requests a new proof from the coordinator instead of finalizing a batch with a stale or
+	// invalid one, disabled if nil.
+	ProofFreshnessConfig *ProofFreshnessConfig `json:"proof_freshness_config,omitempty"`
+	// MultisigConfig prepares the commit and finalize transactions for a multisig or a timelock instead of sending
+	// them, disabled if nil.
+	MultisigConfig *MultisigConfig `json:"multisig_config,omitempty"`
+	// DABackendConfig posts the blobs of the batches to an alternative DA layer instead of attaching them to the commit
+	// transactions, for validium deployments, disabled if nil.
+	DABackendConfig *DABackendConfig `json:"da_backend_config,omitempty"`
+	// OperatorFallbackConfig leaves the commit and finalize transactions to the other operators of a decentralized
+	// deployment for a grace period, and only sends them if the batch is still not committed or finalized on L1 after
+	// it, disabled if nil.
+	OperatorFallbackConfig *OperatorFallbackConfig `json:"operator_fallback_config,omitempty"`
+	// ProofVersions are the versions of the proofs accepted by the rollup verifier. The proof finalizing a batch must be
+	// of the version the verifier routes the batch to, and the finalize call is chosen by the version of the proof.
+	// The proofs aren't checked if empty.
+	ProofVersions []*ProofVersionConfig `json:"proof_versions,omitempty"`
+	// StrictSequencing checks on the rollup contract that a batch is the next one to commit or finalize before its
+	// transaction is sent, and refuses to send it otherwise.
+	StrictSequencing bool `json:"strict_sequencing,omitempty"`
+	// DrainTimeoutSec is how long the rollup-relayer waits on shutdown for its pending commit and finalize transactions
+	// to be confirmed, after it stops sending new ones. The transactions still pending are tracked again on restart.
+	// 300 if not set.
+	DrainTimeoutSec uint64 `json:"drain_timeout_sec,omitempty"`
 	// The private key of the relayer
 	GasOracleSenderPrivateKey *ecdsa.PrivateKey `json:"-"`
 	CommitSenderPrivateKey    *ecdsa.PrivateKey `json:"-"`
@@ -68,6 +117,142 @@ type RelayerConfig struct {
 	FinalizeBatchWithoutProofTimeoutSec uint64 `json:"finalize_batch_without_proof_timeout_sec"`
 }
 
+// CommitteeConfig loads the committee signing configuration items.
+// The commit and finalize transactions are sent to the relay contract, which executes them
+// on the rollup contract once Threshold committee members have signed them.
+type CommitteeConfig struct {
+	// RelayContractAddress is the address of the relay contract verifying the committee signatures.
+	RelayContractAddress common.Address `json:"relay_contract_address"`
+	// Threshold is the number of member signatures required to execute a transaction.
+	Threshold uint64 `json:"threshold"`
+	// Members are the committee members asked for approval.
+	Members []*CommitteeMemberConfig `json:"members"`
+	// ApprovalTimeoutSec is the timeout of collecting the approvals of a transaction, shared by the requests sent
+	// concurrently to the members, 30 if not set.
+	ApprovalTimeoutSec uint64 `json:"approval_timeout_sec,omitempty"`
+	// SignatureValiditySec is the time after which the relay contract rejects the member signatures, 3600 if not set.
+	SignatureValiditySec uint64 `json:"signature_validity_sec,omitempty"`
+}
+
+// CommitteeMemberConfig loads the configuration items of a committee member.
+type CommitteeMemberConfig struct {
+	// Address is the address of the member signing key.
+	Address common.Address `json:"address"`
+	// Endpoint is the url of the member approval service.
+	Endpoint string `json:"endpoint"`
+}
+
+// AutoRebatchConfig loads the automatic re-batching configuration items.
+// A batch whose commit transaction reverted with one of the RevertErrors is deleted along with the
+// uncommitted batches after it, and its chunks are re-proposed by the batch proposer.
+type AutoRebatchConfig struct {
+	// RevertErrors are the names of the rollup contract errors dissolving the batch, e.g. "ErrorIncorrectBitmapLength".
+	// The errors caused by the grouping of the chunks into a batch are used if empty. The errors caused by the content
+	// of a chunk aren't fixed by re-batching, since the same chunks are re-proposed.
+	RevertErrors []string `json:"revert_errors,omitempty"`
+	// MaxRebatches is the max number of times a batch with the same hash is dissolved, 1 if not set.
+	// The commit of a batch dissolved MaxRebatches times is retried as is.
+	MaxRebatches uint64 `json:"max_rebatches,omitempty"`
+}
+
+// CircuitBreakerConfig loads the relayer circuit breaker configuration items.
+// The commit or finalize pipeline is paused once MaxConsecutiveFailures of its transactions in a row
+// are confirmed but failed on L1, and stays paused until an operator resumes it through the admin API.
+type CircuitBreakerConfig struct {
+	// MaxConsecutiveFailures is the number of failed transactions in a row pausing the pipeline.
+	MaxConsecutiveFailures uint64 `json:"max_consecutive_failures"`
+}
+
+// FinalizeCrossCheckConfig loads the pre-finalize cross-check configuration items. The state root and the withdraw
+// root of a batch are compared with the ones of its last block on the l2geth node before the batch is finalized,
+// and the batch is not finalized on mismatch.
+type FinalizeCrossCheckConfig struct {
+	// The l2geth node url the batches are checked against, preferably independent from the l2 watcher endpoints.
+	Endpoint string `json:"endpoint"`
+}
+
+// ProofFreshnessConfig loads the proof freshness configuration items. A batch whose verified proof is older than
+// ValidityWindowSec when it's about to be finalized, or fails the sanity check before its submission, is moved back
+// to unassigned with its proof dropped, so that the coordinator assigns it to a prover again.
+type ProofFreshnessConfig struct {
+	// ValidityWindowSec is the max age of the proof finalizing a batch, counted from its verification.
+	// The age isn't checked if 0.
+	ValidityWindowSec uint64 `json:"validity_window_sec,omitempty"`
+}
+
+// OperatorFallbackConfig loads the operator fallback configuration items, for the deployments where several operators
+// commit and finalize the same batches. The batches committed or finalized by the other operators are tracked through
+// the rollup events imported by the L1 watcher, which must run along with the relayer, and the rollup contract is
+// read before each transaction, as with strict_sequencing, so that a batch is only committed or finalized in sequence.
+type OperatorFallbackConfig struct {
+	// CommitGracePeriodSec is how long a batch is left to the other operators to commit after it's proposed.
+	CommitGracePeriodSec uint64 `json:"commit_grace_period_sec"`
+	// FinalizeGracePeriodSec is how long a batch is left to the other operators to finalize after it's committed and
+	// proven.
+	FinalizeGracePeriodSec uint64 `json:"finalize_grace_period_sec"`
+}
+
+// ProofVersionConfig loads a version of the proofs, i.e. of the circuits and of the verifier contract checking them.
+// As in the MultipleVersionRollupVerifier, a batch is routed to the verifier of its batch version with the greatest
+// start batch index not after its index.
+type ProofVersionConfig struct {
+	// Name labels the version in the logs and the metrics, e.g. the fork name of the circuits.
+	Name string `json:"name"`
+	// VkHash is the keccak256 hash of the verifying key carried by the proofs of the version.
+	VkHash common.Hash `json:"vk_hash"`
+	// VerifierVersion is the batch version the verifier of the proofs is registered with.
+	VerifierVersion uint8 `json:"verifier_version"`
+	// StartBatchIndex is the index of the first batch routed to the verifier of the proofs.
+	StartBatchIndex uint64 `json:"start_batch_index"`
+}
+
+// Formats of the transactions prepared for a multisig.
+const (
+	// MultisigModeSafe prepares a Safe transaction builder batch, proposed to and executed by the Safe.
+	MultisigModeSafe = "safe"
+	// MultisigModeTimelock prepares the schedule and execute calls of a timelock controller, sent by its proposer
+	// and executor, e.g. a Safe.
+	MultisigModeTimelock = "timelock"
+)
+
+// MultisigConfig loads the multisig relaying configuration items, for the deployments where the sequencer and prover
+// roles of the rollup contract are held by a multisig or a timelock. The commit and finalize calls are persisted as
+// prepared transactions, fetched by the signers through the admin API, and tracked through the rollup events imported
+// by the L1 watcher, which must run along with the relayer. A contract can't attach blobs to its calls, so only the
+// batches committed with calldata are supported.
+type MultisigConfig struct {
+	// Mode is the format of the prepared transactions, "safe" or "timelock".
+	Mode string `json:"mode"`
+	// Address is the address of the Safe in the safe mode, or of the timelock controller in the timelock mode.
+	Address common.Address `json:"address"`
+	// TimelockDelaySec is the delay of the scheduled calls in the timelock mode, at least the min delay of the timelock.
+	TimelockDelaySec uint64 `json:"timelock_delay_sec,omitempty"`
+}
+
+// Types of the alternative DA backends.
+const (
+	// DABackendTypeObjectStore PUTs the blobs to an HTTP object store, under their versioned hashes.
+	DABackendTypeObjectStore = "object_store"
+	// DABackendTypeFilesystem writes the blobs to a directory, e.g. a mounted bucket or a devnet volume.
+	DABackendTypeFilesystem = "filesystem"
+)
+
+// DABackendConfig loads the alternative DA backend configuration items. The blobs of a batch are posted to the backend
+// before its commit transaction, which carries their versioned hashes in its calldata instead of the blobs, so the
+// rollup contract of the deployment must accept such commits. Only the batches committed with blobs are concerned.
+type DABackendConfig struct {
+	// Type is the type of the backend, "object_store" or "filesystem".
+	Type string `json:"type"`
+	// Endpoint is the base URL of the object store in the object_store type.
+	Endpoint string `json:"endpoint,omitempty"`
+	// AuthToken is sent as a bearer token to the object store if set.
+	AuthToken string `json:"auth_token,omitempty"`
+	// Directory is the directory the blobs are written to in the filesystem type.
+	Directory string `json:"directory,omitempty"`
+	// TimeoutSec bounds the post of a blob, 30 if not set.
+	TimeoutSec uint64 `json:"timeout_sec,omitempty"`
+}
+
 // GasOracleConfig The config for updating gas price oracle.
 type GasOracleConfig struct {
 	// MinGasPrice store the minimum gas price to set.
@@ -0,0 +1,463 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/controller/relayer"
+	"scroll-tech/rollup/internal/controller/watcher"
+	"scroll-tech/rollup/internal/featureflag"
+	"scroll-tech/rollup/internal/orm"
+	rollupTypes "scroll-tech/rollup/internal/types"
+)
+
+// defaultPreparedTransactionsLimit is the number of prepared transactions returned if the request sets no limit
+const defaultPreparedTransactionsLimit = 100
+
+// defaultBatchTransactionCostsLimit is the number of batches whose transaction costs are returned if the request sets no limit
+const defaultBatchTransactionCostsLimit = 100
+
+// defaultDailyTransactionCostsDays is the number of days whose transaction costs are returned if the request sets none
+const defaultDailyTransactionCostsDays = 30
+
+// manualPauseReason is the reason recorded with a relayer pipeline paused by an operator without a reason
+const manualPauseReason = "paused by an operator"
+
+// AdminController the admin api controller for operators
+type AdminController struct {
+	chunkProposer *watcher.ChunkProposer
+	batchProposer *watcher.BatchProposer
+	l2Watcher     *watcher.L2WatcherClient
+	l2Relayer     *relayer.Layer2Relayer
+	featureFlags  *featureflag.Flags
+	adminToken    string
+
+	l2BlockQuarantineOrm   *orm.L2BlockQuarantine
+	proposerPauseOrm       *orm.ProposerPause
+	preparedTransactionOrm *orm.PreparedTransaction
+	transactionCostOrm     *orm.TransactionCost
+}
+
+// NewAdminController create an admin controller
+func NewAdminController(db *gorm.DB, chunkProposer *watcher.ChunkProposer, batchProposer *watcher.BatchProposer, l2Watcher *watcher.L2WatcherClient, l2Relayer *relayer.Layer2Relayer, featureFlags *featureflag.Flags, adminToken string) *AdminController {
+	return &AdminController{
+		chunkProposer:          chunkProposer,
+		batchProposer:          batchProposer,
+		l2Watcher:              l2Watcher,
+		l2Relayer:              l2Relayer,
+		featureFlags:           featureFlags,
+		adminToken:             adminToken,
+		l2BlockQuarantineOrm:   orm.NewL2BlockQuarantine(db),
+		proposerPauseOrm:       orm.NewProposerPause(db),
+		preparedTransactionOrm: orm.NewPreparedTransaction(db),
+		transactionCostOrm:     orm.NewTransactionCost(db),
+	}
+}
+
+// Authorize rejects the requests without the configured admin bearer token
+func (c *AdminController) Authorize(ctx *gin.Context) {
+	token := strings.TrimPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+	if c.adminToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(c.adminToken)) != 1 {
+		types.RenderFailure(ctx, types.ErrRollupAPIUnauthorized, errors.New("invalid admin token"))
+		ctx.Abort()
+		return
+	}
+	ctx.Next()
+}
+
+// ForceSealChunk seals all the pending blocks into chunks right now, regardless of the chunk timeout
+func (c *AdminController) ForceSealChunk(ctx *gin.Context) {
+	result, err := c.chunkProposer.ForceSealChunks(ctx)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIForceSealChunkFailure, err)
+		return
+	}
+	types.RenderSuccess(ctx, result)
+}
+
+// ForceSealBatch seals all the unbatched chunks into batches right now, regardless of the batch timeout
+func (c *AdminController) ForceSealBatch(ctx *gin.Context) {
+	result, err := c.batchProposer.ForceSealBatches(ctx)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIForceSealBatchFailure, err)
+		return
+	}
+	types.RenderSuccess(ctx, result)
+}
+
+// GetChunkUtilization returns the constraint utilization of the pending blocks of the next chunk
+func (c *AdminController) GetChunkUtilization(ctx *gin.Context) {
+	report, err := c.chunkProposer.GetChunkUtilizationReport(ctx)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIGetChunkUtilizationFailure, err)
+		return
+	}
+	types.RenderSuccess(ctx, report)
+}
+
+// GetChunkProfile returns the chunk proposer profile in use and the available ones
+func (c *AdminController) GetChunkProfile(ctx *gin.Context) {
+	types.RenderSuccess(ctx, c.chunkProposer.Profile())
+}
+
+// SetChunkProfile switches the chunk proposer profile until the next switch or restart
+func (c *AdminController) SetChunkProfile(ctx *gin.Context) {
+	var req rollupTypes.SetChunkProfileParameter
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIParameterInvalidNo, err)
+		return
+	}
+
+	if err := c.chunkProposer.SetProfile(req.Name); err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPISetChunkProfileFailure, err)
+		return
+	}
+	types.RenderSuccess(ctx, c.chunkProposer.Profile())
+}
+
+// SimulateProposeBatch returns the batch that would be proposed from the unbatched chunks without persisting it.
+// The batch proposer configuration in the request body, if any, is used instead of the current one
+func (c *AdminController) SimulateProposeBatch(ctx *gin.Context) {
+	var cfg *config.BatchProposerConfig
+	if ctx.Request.ContentLength > 0 {
+		cfg = &config.BatchProposerConfig{}
+		if err := ctx.ShouldBindJSON(cfg); err != nil {
+			types.RenderFailure(ctx, types.ErrRollupAPIParameterInvalidNo, err)
+			return
+		}
+		if cfg.MaxChunkNumPerBatch == 0 {
+			types.RenderFailure(ctx, types.ErrRollupAPIParameterInvalidNo, errors.New("invalid max_chunk_num_per_batch"))
+			return
+		}
+	}
+
+	result, err := c.batchProposer.SimulateProposeBatch(ctx, cfg)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPISimulateProposeBatchFailure, err)
+		return
+	}
+	types.RenderSuccess(ctx, result)
+}
+
+// GetBatchProposerPause returns the pause state of the batch proposer
+func (c *AdminController) GetBatchProposerPause(ctx *gin.Context) {
+	state, err := c.batchProposer.PauseState(ctx)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIGetBatchProposerPauseFailure, err)
+		return
+	}
+	types.RenderSuccess(ctx, state)
+}
+
+// SetBatchProposerPause pauses or resumes the batch proposal, the pause survives restarts
+func (c *AdminController) SetBatchProposerPause(ctx *gin.Context) {
+	var req rollupTypes.SetBatchProposerPauseParameter
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIParameterInvalidNo, err)
+		return
+	}
+
+	var err error
+	if *req.Paused {
+		err = c.batchProposer.Pause(ctx, req.Reason)
+	} else {
+		err = c.batchProposer.Resume(ctx)
+	}
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPISetBatchProposerPauseFailure, err)
+		return
+	}
+	c.GetBatchProposerPause(ctx)
+}
+
+// GetRelayerCircuitBreaker returns the circuit breaker states of the commit and finalize relayer pipelines
+func (c *AdminController) GetRelayerCircuitBreaker(ctx *gin.Context) {
+	pipelines := []string{relayer.CommitCircuitBreakerName, relayer.FinalizeCircuitBreakerName}
+	results := make([]*rollupTypes.RelayerCircuitBreakerSchema, len(pipelines))
+	for i, pipeline := range pipelines {
+		pause, err := c.proposerPauseOrm.GetProposerPause(ctx, pipeline)
+		if err != nil {
+			types.RenderFailure(ctx, types.ErrRollupAPIGetRelayerCircuitBreakerFailure, err)
+			return
+		}
+		results[i] = &rollupTypes.RelayerCircuitBreakerSchema{Pipeline: pipeline}
+		if pause != nil {
+			results[i].Paused = pause.Paused
+			results[i].Reason = pause.Reason
+			results[i].UpdatedAt = pause.UpdatedAt.Unix()
+		}
+	}
+	types.RenderSuccess(ctx, results)
+}
+
+// SetRelayerCircuitBreaker pauses or resumes the commit or the finalize relayer pipeline independently of the other.
+// The pause is persisted, whether set here or by the circuit breaker, and holds until resumed here
+func (c *AdminController) SetRelayerCircuitBreaker(ctx *gin.Context) {
+	var req rollupTypes.SetRelayerCircuitBreakerParameter
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIParameterInvalidNo, err)
+		return
+	}
+
+	reason := req.Reason
+	if !*req.Paused {
+		reason = ""
+	} else if reason == "" {
+		// tells a manual pause apart from a tripped circuit breaker.
+		reason = manualPauseReason
+	}
+	if err := c.proposerPauseOrm.UpsertProposerPause(ctx, req.Pipeline, *req.Paused, reason); err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPISetRelayerCircuitBreakerFailure, err)
+		return
+	}
+	c.GetRelayerCircuitBreaker(ctx)
+}
+
+// GetBatchRevert returns the last batch revert sent since the relayer started, null if none was sent
+func (c *AdminController) GetBatchRevert(ctx *gin.Context) {
+	types.RenderSuccess(ctx, c.l2Relayer.BatchRevert())
+}
+
+// RevertBatches reverts the last committed batches of the range on L1, then deletes them along with the pending
+// batches after them, releasing their chunks. The revertBatch call is only checked and returned unless confirmed
+func (c *AdminController) RevertBatches(ctx *gin.Context) {
+	var req rollupTypes.RevertBatchesParameter
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIParameterInvalidNo, err)
+		return
+	}
+
+	revert, err := c.l2Relayer.RevertBatches(req.StartIndex, req.EndIndex, req.Confirm)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIRevertBatchesFailure, err)
+		return
+	}
+	types.RenderSuccess(ctx, revert)
+}
+
+// GetPreparedTransactions returns the commit and finalize transactions prepared for the multisig, in batch index order
+func (c *AdminController) GetPreparedTransactions(ctx *gin.Context) {
+	var req rollupTypes.PreparedTransactionsParameter
+	if err := ctx.ShouldBind(&req); err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIParameterInvalidNo, err)
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultPreparedTransactionsLimit
+	}
+
+	preparedTxs, err := c.preparedTransactionOrm.GetPreparedTransactions(ctx, req.Status, req.Limit)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIGetPreparedTransactionsFailure, err)
+		return
+	}
+
+	results := make([]*rollupTypes.PreparedTransactionSchema, len(preparedTxs))
+	for i, preparedTx := range preparedTxs {
+		results[i] = &rollupTypes.PreparedTransactionSchema{
+			BatchIndex: preparedTx.BatchIndex,
+			BatchHash:  preparedTx.BatchHash,
+			Kind:       preparedTx.Kind,
+			Status:     preparedTx.Status,
+			Payload:    json.RawMessage(preparedTx.Payload),
+			TxHash:     preparedTx.TxHash,
+			CreatedAt:  preparedTx.CreatedAt.Unix(),
+		}
+	}
+	types.RenderSuccess(ctx, results)
+}
+
+// GetBatchTransactionCosts returns the fees paid for the commit, finalize and l2 gas oracle transactions of the
+// batches from the given index on, per sender type, in batch index order
+func (c *AdminController) GetBatchTransactionCosts(ctx *gin.Context) {
+	var req rollupTypes.BatchTransactionCostsParameter
+	if err := ctx.ShouldBind(&req); err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIParameterInvalidNo, err)
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultBatchTransactionCostsLimit
+	}
+
+	summaries, err := c.transactionCostOrm.GetBatchTransactionCosts(ctx, req.StartIndex, req.Limit)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIGetTransactionCostsFailure, err)
+		return
+	}
+
+	results := make([]*rollupTypes.BatchTransactionCostSchema, 0, len(summaries))
+	totalFee := new(big.Int)
+	for i, summary := range summaries {
+		if i == 0 || summary.BatchIndex != summaries[i-1].BatchIndex {
+			totalFee = new(big.Int)
+			results = append(results, &rollupTypes.BatchTransactionCostSchema{BatchIndex: summary.BatchIndex, BatchHash: summary.BatchHash})
+		}
+		result := results[len(results)-1]
+		result.Costs = append(result.Costs, transactionCostSchema(summary, totalFee))
+		result.TotalFee = totalFee.String()
+	}
+	types.RenderSuccess(ctx, results)
+}
+
+// GetDailyTransactionCosts returns the fees paid for the transactions confirmed on each of the last UTC days, per
+// sender type, in day order
+func (c *AdminController) GetDailyTransactionCosts(ctx *gin.Context) {
+	var req rollupTypes.DailyTransactionCostsParameter
+	if err := ctx.ShouldBind(&req); err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIParameterInvalidNo, err)
+		return
+	}
+	if req.Days == 0 {
+		req.Days = defaultDailyTransactionCostsDays
+	}
+
+	since := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1-req.Days)
+	summaries, err := c.transactionCostOrm.GetDailyTransactionCosts(ctx, since)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIGetTransactionCostsFailure, err)
+		return
+	}
+
+	results := make([]*rollupTypes.DailyTransactionCostSchema, 0, req.Days)
+	totalFee := new(big.Int)
+	for i, summary := range summaries {
+		if i == 0 || summary.Day != summaries[i-1].Day {
+			totalFee = new(big.Int)
+			results = append(results, &rollupTypes.DailyTransactionCostSchema{Day: summary.Day})
+		}
+		result := results[len(results)-1]
+		result.Costs = append(result.Costs, transactionCostSchema(summary, totalFee))
+		result.TotalFee = totalFee.String()
+	}
+	types.RenderSuccess(ctx, results)
+}
+
+// transactionCostSchema converts the aggregated costs of a sender type, adding its fee to totalFee
+func transactionCostSchema(summary *orm.TransactionCostSummary, totalFee *big.Int) *rollupTypes.TransactionCostSchema {
+	if fee, ok := new(big.Int).SetString(summary.Fee, 10); ok {
+		totalFee.Add(totalFee, fee)
+	}
+	return &rollupTypes.TransactionCostSchema{
+		SenderType:  summary.SenderType.String(),
+		TxCount:     summary.TxCount,
+		FailedCount: summary.FailedCount,
+		GasUsed:     summary.GasUsed,
+		BlobGasUsed: summary.BlobGasUsed,
+		Fee:         summary.Fee,
+	}
+}
+
+// GetFeatureFlags returns the effective states of the feature flags
+func (c *AdminController) GetFeatureFlags(ctx *gin.Context) {
+	types.RenderSuccess(ctx, c.featureFlags.States())
+}
+
+// SetFeatureFlag overrides the state of a feature flag in the database
+func (c *AdminController) SetFeatureFlag(ctx *gin.Context) {
+	var req rollupTypes.SetFeatureFlagParameter
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIParameterInvalidNo, err)
+		return
+	}
+
+	rolloutPercentage := uint64(100)
+	if req.RolloutPercentage != nil {
+		rolloutPercentage = *req.RolloutPercentage
+	}
+	if err := c.featureFlags.Set(ctx, req.Name, req.Enabled, rolloutPercentage); err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPISetFeatureFlagFailure, err)
+		return
+	}
+	types.RenderSuccess(ctx, c.featureFlags.States())
+}
+
+// ResetFeatureFlag removes the database override of a feature flag, falling back to the configured state
+func (c *AdminController) ResetFeatureFlag(ctx *gin.Context) {
+	var req rollupTypes.FeatureFlagNameParameter
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIParameterInvalidNo, err)
+		return
+	}
+
+	if err := c.featureFlags.Reset(ctx, req.Name); err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIResetFeatureFlagFailure, err)
+		return
+	}
+	types.RenderSuccess(ctx, c.featureFlags.States())
+}
+
+// GetL2BlockQuarantine returns the l2 blocks quarantined after repeatedly failing to be imported
+func (c *AdminController) GetL2BlockQuarantine(ctx *gin.Context) {
+	quarantines, err := c.l2BlockQuarantineOrm.GetL2BlockQuarantines(ctx)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIGetL2BlockQuarantineFailure, err)
+		return
+	}
+
+	results := make([]*rollupTypes.L2BlockQuarantineSchema, len(quarantines))
+	for i, quarantine := range quarantines {
+		results[i] = &rollupTypes.L2BlockQuarantineSchema{
+			Number:    quarantine.Number,
+			Status:    quarantine.Status,
+			Attempts:  quarantine.Attempts,
+			LastError: quarantine.LastError,
+			UpdatedAt: quarantine.UpdatedAt.Unix(),
+		}
+	}
+	types.RenderSuccess(ctx, results)
+}
+
+// UpdateL2BlockQuarantine retries a quarantined l2 block on the next fetch of the l2 watcher,
+// or resolves it so that it is imported without its row consumption
+func (c *AdminController) UpdateL2BlockQuarantine(ctx *gin.Context) {
+	var req rollupTypes.UpdateL2BlockQuarantineParameter
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIParameterInvalidNo, err)
+		return
+	}
+
+	status := orm.L2BlockQuarantineStatusRetry
+	if req.Action == rollupTypes.L2BlockQuarantineActionResolve {
+		status = orm.L2BlockQuarantineStatusResolved
+	}
+	if err := c.l2BlockQuarantineOrm.UpdateL2BlockQuarantineStatus(ctx, req.Number, status); err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIUpdateL2BlockQuarantineFailure, err)
+		return
+	}
+	c.GetL2BlockQuarantine(ctx)
+}
+
+// GetL2SyncHeight returns the height of the latest l2 block stored by the l2 watcher
+func (c *AdminController) GetL2SyncHeight(ctx *gin.Context) {
+	height, err := c.l2Watcher.SyncHeight(ctx)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIGetL2SyncHeightFailure, err)
+		return
+	}
+	types.RenderSuccess(ctx, &rollupTypes.L2SyncHeightState{Height: height})
+}
+
+// SetL2SyncHeight moves the l2 watcher sync cursor back, the blocks above the height are deleted and imported again
+func (c *AdminController) SetL2SyncHeight(ctx *gin.Context) {
+	var req rollupTypes.SetL2SyncHeightParameter
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIParameterInvalidNo, err)
+		return
+	}
+
+	if err := c.l2Watcher.SetSyncHeight(ctx, *req.Height); err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPISetL2SyncHeightFailure, err)
+		return
+	}
+	c.GetL2SyncHeight(ctx)
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"sync"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"gorm.io/gorm"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/controller/relayer"
+	"scroll-tech/rollup/internal/controller/watcher"
+	"scroll-tech/rollup/internal/featureflag"
+)
+
+var (
+	// ForcedInclusion the forced inclusion status controller
+	ForcedInclusion *ForcedInclusionController
+	// L2Message the l2 to l1 message controller
+	L2Message *L2MessageController
+	// SkippedL1Message the skipped l1 message controller
+	SkippedL1Message *SkippedL1MessageController
+	// Admin the admin controller, nil if the admin api is disabled
+	Admin *AdminController
+	// Throughput the throughput governor controller, nil if the throughput governor is disabled
+	Throughput *ThroughputController
+
+	initControllerOnce sync.Once
+)
+
+// InitController inits Controller with database
+func InitController(db *gorm.DB, chunkProposer *watcher.ChunkProposer, batchProposer *watcher.BatchProposer, l2Watcher *watcher.L2WatcherClient, l2Relayer *relayer.Layer2Relayer, governor *watcher.ThroughputGovernor, featureFlags *featureflag.Flags, adminCfg *config.AdminAPIConfig, l1MessengerAddress common.Address) {
+	initControllerOnce.Do(func() {
+		ForcedInclusion = NewForcedInclusionController(db)
+		L2Message = NewL2MessageController(db)
+		SkippedL1Message = NewSkippedL1MessageController(db, l1MessengerAddress)
+		if governor != nil {
+			Throughput = NewThroughputController(governor)
+		}
+		if adminCfg != nil && adminCfg.AdminToken != "" {
+			Admin = NewAdminController(db, chunkProposer, batchProposer, l2Watcher, l2Relayer, featureFlags, adminCfg.AdminToken)
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+
+	"scroll-tech/rollup/internal/orm"
+	rollupTypes "scroll-tech/rollup/internal/types"
+)
+
+// defaultForcedTxQueueLimit is the number of queued enforced transactions returned if the request sets no limit
+const defaultForcedTxQueueLimit = 100
+
+// ForcedInclusionController the forced inclusion status api controller
+type ForcedInclusionController struct {
+	l1MessageOrm *orm.L1Message
+	chunkOrm     *orm.Chunk
+	batchOrm     *orm.Batch
+}
+
+// NewForcedInclusionController create a forced inclusion status controller
+func NewForcedInclusionController(db *gorm.DB) *ForcedInclusionController {
+	return &ForcedInclusionController{
+		l1MessageOrm: orm.NewL1Message(db),
+		chunkOrm:     orm.NewChunk(db),
+		batchOrm:     orm.NewBatch(db),
+	}
+}
+
+// GetForcedInclusionStatus returns the pipeline status of the enforced transactions submitted by an L1 transaction
+func (c *ForcedInclusionController) GetForcedInclusionStatus(ctx *gin.Context) {
+	var req rollupTypes.ForcedInclusionStatusParameter
+	if err := ctx.ShouldBind(&req); err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIParameterInvalidNo, err)
+		return
+	}
+
+	messages, err := c.l1MessageOrm.GetEnforcedL1MessagesByLayer1Hash(ctx, req.L1TxHash)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIGetForcedInclusionFailure, err)
+		return
+	}
+	if len(messages) == 0 {
+		types.RenderFailure(ctx, types.ErrRollupAPIGetForcedInclusionFailure, errors.New("enforced transaction not found"))
+		return
+	}
+
+	var results []*rollupTypes.ForcedInclusionStatusSchema
+	for _, msg := range messages {
+		status, err := c.getStatus(ctx, msg)
+		if err != nil {
+			types.RenderFailure(ctx, types.ErrRollupAPIGetForcedInclusionFailure, err)
+			return
+		}
+		results = append(results, status)
+	}
+	types.RenderSuccess(ctx, results)
+}
+
+// GetForcedTxQueue returns the enforced transactions not included in any chunk yet, with their inclusion deadlines
+func (c *ForcedInclusionController) GetForcedTxQueue(ctx *gin.Context) {
+	var req rollupTypes.ForcedTxQueueParameter
+	if err := ctx.ShouldBind(&req); err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIParameterInvalidNo, err)
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultForcedTxQueueLimit
+	}
+
+	queueIndex, err := c.chunkOrm.GetUnchunkedL1MessageQueueIndex(ctx)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIGetForcedTxQueueFailure, err)
+		return
+	}
+	messages, err := c.l1MessageOrm.GetEnforcedL1MessagesGEQueueIndex(ctx, queueIndex, req.Limit)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIGetForcedTxQueueFailure, err)
+		return
+	}
+
+	results := make([]*rollupTypes.ForcedTxQueueSchema, 0, len(messages))
+	for _, msg := range messages {
+		results = append(results, &rollupTypes.ForcedTxQueueSchema{
+			QueueIndex:        msg.QueueIndex,
+			L1TxHash:          msg.Layer1Hash,
+			L1BlockNumber:     msg.Height,
+			Sender:            msg.Sender,
+			Target:            msg.Target,
+			InclusionDeadline: msg.InclusionDeadline,
+		})
+	}
+	types.RenderSuccess(ctx, results)
+}
+
+func (c *ForcedInclusionController) getStatus(ctx context.Context, msg *orm.L1Message) (*rollupTypes.ForcedInclusionStatusSchema, error) {
+	status := &rollupTypes.ForcedInclusionStatusSchema{
+		QueueIndex:    msg.QueueIndex,
+		L1TxHash:      msg.Layer1Hash,
+		L1BlockNumber: msg.Height,
+		Sender:        msg.Sender,
+		Target:        msg.Target,
+		Status:        rollupTypes.ForcedInclusionStatusQueued,
+
+		InclusionDeadline: msg.InclusionDeadline,
+	}
+
+	chunk, err := c.chunkOrm.GetChunkByL1MessageQueueIndex(ctx, msg.QueueIndex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chunk of queue index %v: %w", msg.QueueIndex, err)
+	}
+	if chunk == nil {
+		return status, nil
+	}
+	status.Status = rollupTypes.ForcedInclusionStatusChunked
+	status.ChunkHash = chunk.Hash
+	if chunk.BatchHash == "" {
+		return status, nil
+	}
+
+	batch, err := c.batchOrm.GetBatchByHash(ctx, chunk.BatchHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get batch of queue index %v: %w", msg.QueueIndex, err)
+	}
+	status.BatchHash = batch.Hash
+	status.BatchIndex = batch.Index
+	status.CommitTxHash = batch.CommitTxHash
+	status.FinalizeTxHash = batch.FinalizeTxHash
+	switch types.RollupStatus(batch.RollupStatus) {
+	case types.RollupCommitted, types.RollupFinalizing, types.RollupFinalizeFailed:
+		status.Status = rollupTypes.ForcedInclusionStatusCommitted
+	case types.RollupFinalized:
+		status.Status = rollupTypes.ForcedInclusionStatusFinalized
+	default:
+		status.Status = rollupTypes.ForcedInclusionStatusBatched
+	}
+	return status, nil
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+
+	"scroll-tech/rollup/internal/orm"
+	rollupTypes "scroll-tech/rollup/internal/types"
+)
+
+// L2MessageController the l2 to l1 message api controller
+type L2MessageController struct {
+	l2MessageOrm *orm.L2Message
+}
+
+// NewL2MessageController create an l2 to l1 message controller
+func NewL2MessageController(db *gorm.DB) *L2MessageController {
+	return &L2MessageController{
+		l2MessageOrm: orm.NewL2Message(db),
+	}
+}
+
+// GetL2Message returns the l2 to l1 message with the given hash, indexed with the l2 block sending it
+func (c *L2MessageController) GetL2Message(ctx *gin.Context) {
+	var req rollupTypes.L2MessageParameter
+	if err := ctx.ShouldBind(&req); err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIParameterInvalidNo, err)
+		return
+	}
+
+	message, err := c.l2MessageOrm.GetL2MessageByHash(ctx, req.MsgHash)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIGetL2MessageFailure, err)
+		return
+	}
+	if message == nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIGetL2MessageFailure, errors.New("l2 message not found"))
+		return
+	}
+
+	types.RenderSuccess(ctx, &rollupTypes.L2MessageSchema{
+		MessageIndex:  message.MessageIndex,
+		MsgHash:       message.MsgHash,
+		L2BlockNumber: message.Height,
+		L2TxHash:      message.Layer2Hash,
+		Sender:        message.Sender,
+		Target:        message.Target,
+		Value:         message.Value,
+		GasLimit:      message.GasLimit,
+		Calldata:      message.Calldata,
+	})
+}
@@ -0,0 +1,176 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/scroll-tech/go-ethereum/accounts/abi"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+
+	bridgeAbi "scroll-tech/rollup/abi"
+	"scroll-tech/rollup/internal/orm"
+	rollupTypes "scroll-tech/rollup/internal/types"
+	"scroll-tech/rollup/internal/utils"
+)
+
+// defaultSkippedL1MessagesLimit is the number of skipped l1 messages returned if the request sets no limit
+const defaultSkippedL1MessagesLimit = 100
+
+// l1MessengerReplayABI holds the calls of the L1ScrollMessenger replaying or dropping an l1 message.
+const l1MessengerReplayABI = `[
+	{"type":"function","name":"replayMessage","stateMutability":"payable","inputs":[{"name":"_from","type":"address"},{"name":"_to","type":"address"},{"name":"_value","type":"uint256"},{"name":"_messageNonce","type":"uint256"},{"name":"_message","type":"bytes"},{"name":"_newGasLimit","type":"uint32"},{"name":"_refundAddress","type":"address"}],"outputs":[]},
+	{"type":"function","name":"dropMessage","stateMutability":"nonpayable","inputs":[{"name":"_from","type":"address"},{"name":"_to","type":"address"},{"name":"_value","type":"uint256"},{"name":"_messageNonce","type":"uint256"},{"name":"_message","type":"bytes"}],"outputs":[]}
+]`
+
+// SkippedL1MessageController the skipped l1 message api controller
+type SkippedL1MessageController struct {
+	l1MessageOrm        *orm.L1Message
+	skippedL1MessageOrm *orm.SkippedL1Message
+
+	l1MessengerAddress common.Address
+	l1MessengerABI     abi.ABI
+}
+
+// NewSkippedL1MessageController create a skipped l1 message controller, the replay and drop transactions target
+// the given L1ScrollMessenger
+func NewSkippedL1MessageController(db *gorm.DB, l1MessengerAddress common.Address) *SkippedL1MessageController {
+	// the abi is a constant, it can't fail to parse.
+	parsed, err := abi.JSON(strings.NewReader(l1MessengerReplayABI))
+	if err != nil {
+		panic(fmt.Sprintf("failed to parse L1ScrollMessenger replay abi: %v", err))
+	}
+
+	return &SkippedL1MessageController{
+		l1MessageOrm:        orm.NewL1Message(db),
+		skippedL1MessageOrm: orm.NewSkippedL1Message(db),
+		l1MessengerAddress:  l1MessengerAddress,
+		l1MessengerABI:      parsed,
+	}
+}
+
+// GetSkippedL1Messages returns the l1 messages skipped by the committed batches along with their skip reasons,
+// in queue index order
+func (c *SkippedL1MessageController) GetSkippedL1Messages(ctx *gin.Context) {
+	var req rollupTypes.SkippedL1MessagesParameter
+	if err := ctx.ShouldBind(&req); err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIParameterInvalidNo, err)
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultSkippedL1MessagesLimit
+	}
+
+	messages, err := c.skippedL1MessageOrm.GetSkippedL1Messages(ctx, req.StartQueueIndex, req.Limit)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIGetSkippedL1MessagesFailure, err)
+		return
+	}
+
+	results := make([]*rollupTypes.SkippedL1MessageSchema, len(messages))
+	for i, message := range messages {
+		results[i] = &rollupTypes.SkippedL1MessageSchema{
+			QueueIndex:      message.QueueIndex,
+			MsgHash:         message.MsgHash,
+			BatchIndex:      message.BatchIndex,
+			BatchHash:       message.BatchHash,
+			SkipReason:      message.SkipReason,
+			SkipBlockNumber: message.SkipBlockNumber,
+			CreatedAt:       message.CreatedAt.Unix(),
+		}
+	}
+	types.RenderSuccess(ctx, results)
+}
+
+// GetSkippedL1MessageTx returns the L1ScrollMessenger transaction replaying or dropping a skipped l1 message
+func (c *SkippedL1MessageController) GetSkippedL1MessageTx(ctx *gin.Context) {
+	var req rollupTypes.SkippedL1MessageTxParameter
+	if err := ctx.ShouldBind(&req); err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIParameterInvalidNo, err)
+		return
+	}
+	if req.Action == "replay" && req.NewGasLimit == 0 {
+		types.RenderFailure(ctx, types.ErrRollupAPIParameterInvalidNo, errors.New("new_gas_limit is required to replay the message"))
+		return
+	}
+	if req.RefundAddress != "" && !common.IsHexAddress(req.RefundAddress) {
+		types.RenderFailure(ctx, types.ErrRollupAPIParameterInvalidNo, fmt.Errorf("invalid refund_address %v", req.RefundAddress))
+		return
+	}
+
+	skippedMessage, err := c.skippedL1MessageOrm.GetSkippedL1MessageByQueueIndex(ctx, *req.QueueIndex)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIGetSkippedL1MessageTxFailure, err)
+		return
+	}
+	if skippedMessage == nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIGetSkippedL1MessageTxFailure, fmt.Errorf("l1 message %v is not skipped", *req.QueueIndex))
+		return
+	}
+	messages, err := c.l1MessageOrm.GetL1MessagesByQueueIndices(ctx, []uint64{*req.QueueIndex})
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIGetSkippedL1MessageTxFailure, err)
+		return
+	}
+	if len(messages) == 0 {
+		types.RenderFailure(ctx, types.ErrRollupAPIGetSkippedL1MessageTxFailure, fmt.Errorf("l1 message %v not found", *req.QueueIndex))
+		return
+	}
+
+	tx, err := c.skippedL1MessageTx(messages[0], &req)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIGetSkippedL1MessageTxFailure, err)
+		return
+	}
+	types.RenderSuccess(ctx, tx)
+}
+
+// skippedL1MessageTx decodes the relayMessage call of the l1 message and packs it in the replay or drop call.
+// Only the messages sent through the L1ScrollMessenger can be replayed or dropped, not the enforced transactions.
+func (c *SkippedL1MessageController) skippedL1MessageTx(message *orm.L1Message, req *rollupTypes.SkippedL1MessageTxParameter) (*rollupTypes.SkippedL1MessageTxSchema, error) {
+	if message.IsEnforced || common.HexToAddress(message.Sender) != utils.ApplyL1ToL2Alias(c.l1MessengerAddress) {
+		return nil, fmt.Errorf("l1 message %v is not sent by the L1ScrollMessenger", message.QueueIndex)
+	}
+
+	calldata := common.FromHex(message.Calldata)
+	if len(calldata) < 4 {
+		return nil, fmt.Errorf("l1 message %v has no relayMessage call", message.QueueIndex)
+	}
+	method, err := bridgeAbi.L2ScrollMessengerABI.MethodById(calldata[:4])
+	if err != nil || method.Name != "relayMessage" {
+		return nil, fmt.Errorf("l1 message %v has no relayMessage call", message.QueueIndex)
+	}
+	args, err := method.Inputs.Unpack(calldata[4:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the relayMessage call of l1 message %v: %w", message.QueueIndex, err)
+	}
+	from, to, value, nonce, payload := args[0].(common.Address), args[1].(common.Address), args[2].(*big.Int), args[3].(*big.Int), args[4].([]byte)
+
+	var data []byte
+	if req.Action == "replay" {
+		refundAddress := from
+		if req.RefundAddress != "" {
+			refundAddress = common.HexToAddress(req.RefundAddress)
+		}
+		data, err = c.l1MessengerABI.Pack("replayMessage", from, to, value, nonce, payload, req.NewGasLimit, refundAddress)
+	} else {
+		data, err = c.l1MessengerABI.Pack("dropMessage", from, to, value, nonce, payload)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack the %v call of l1 message %v: %w", req.Action, message.QueueIndex, err)
+	}
+
+	return &rollupTypes.SkippedL1MessageTxSchema{
+		QueueIndex: message.QueueIndex,
+		Action:     req.Action,
+		Sender:     from.String(),
+		To:         c.l1MessengerAddress.String(),
+		Data:       hexutil.Encode(data),
+	}, nil
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"scroll-tech/common/types"
+
+	"scroll-tech/rollup/internal/controller/watcher"
+)
+
+// ThroughputController the throughput governor api controller
+type ThroughputController struct {
+	governor *watcher.ThroughputGovernor
+}
+
+// NewThroughputController create a throughput governor controller
+func NewThroughputController(governor *watcher.ThroughputGovernor) *ThroughputController {
+	return &ThroughputController{governor: governor}
+}
+
+// GetThroughputSignal returns the latest throughput signal, including the recommended L2 block gas limit
+func (c *ThroughputController) GetThroughputSignal(ctx *gin.Context) {
+	signal := c.governor.Signal()
+	if signal == nil {
+		types.RenderFailure(ctx, types.ErrRollupAPIGetThroughputSignalFailure, errors.New("throughput signal is not computed yet"))
+		return
+	}
+	types.RenderSuccess(ctx, signal)
+}
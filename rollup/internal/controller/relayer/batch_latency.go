@@ -0,0 +1,91 @@
+package relayer
+
+import (
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/log"
+)
+
+// batchLifecycleStage is a lifecycle stage of a batch along with the time it was reached, nil if it wasn't.
+type batchLifecycleStage struct {
+	name string
+	at   *time.Time
+}
+
+// observeBatchLatency exports the latencies between the lifecycle stages of a finalized batch, from the import of its
+// first block to its finalization. A stage not reached, e.g. the proof of a batch finalized without proof, is skipped,
+// and a stage reached before the previous one, e.g. a proof verified before the commit, has a zero latency.
+func (r *Layer2Relayer) observeBatchLatency(batchHash string) {
+	dbBatch, err := r.batchOrm.GetBatchByHash(r.ctx, batchHash)
+	if err != nil {
+		log.Warn("failed to get the finalized batch for its latency", "hash", batchHash, "err", err)
+		return
+	}
+	if dbBatch.FinalizedAt == nil {
+		return
+	}
+	dbChunks, err := r.chunkOrm.GetChunksInRange(r.ctx, dbBatch.StartChunkIndex, dbBatch.StartChunkIndex)
+	if err != nil || len(dbChunks) == 0 {
+		log.Warn("failed to get the first chunk of the finalized batch for its latency", "index", dbBatch.Index, "hash", batchHash, "err", err)
+		return
+	}
+	importedAt, err := r.l2BlockOrm.GetL2BlockImportedAt(r.ctx, dbChunks[0].StartBlockNumber)
+	if err != nil {
+		log.Warn("failed to get the first block of the finalized batch for its latency", "index", dbBatch.Index, "hash", batchHash, "err", err)
+		return
+	}
+
+	stages := []batchLifecycleStage{
+		{name: "block_imported", at: importedAt},
+		{name: "chunk_proposed", at: &dbChunks[0].CreatedAt},
+		{name: "batch_proposed", at: &dbBatch.CreatedAt},
+		{name: "batch_committed", at: dbBatch.CommittedAt},
+		{name: "proof_verified", at: dbBatch.ProvedAt},
+		{name: "batch_finalized", at: dbBatch.FinalizedAt},
+	}
+
+	latencies, total := stageLatencies(stages)
+	for _, latency := range latencies {
+		r.metrics.rollupL2BatchStageLatencySec.WithLabelValues(latency.from, latency.to).Observe(latency.sec)
+	}
+	if len(latencies) > 0 {
+		r.metrics.rollupL2BatchTotalLatencySec.Observe(total)
+	}
+}
+
+// batchStageLatency is the latency between two consecutive lifecycle stages reached by a batch.
+type batchStageLatency struct {
+	from, to string
+	sec      float64
+}
+
+// stageLatencies returns the latencies between the consecutive stages reached, and the total latency from the
+// first stage reached to the last one.
+func stageLatencies(stages []batchLifecycleStage) ([]batchStageLatency, float64) {
+	var latencies []batchStageLatency
+	var first, last *batchLifecycleStage
+	for i := range stages {
+		stage := &stages[i]
+		if stage.at == nil {
+			continue
+		}
+		if last != nil {
+			latencies = append(latencies, batchStageLatency{from: last.name, to: stage.name, sec: latencySec(*last.at, *stage.at)})
+		} else {
+			first = stage
+		}
+		last = stage
+	}
+	if first == nil {
+		return nil, 0
+	}
+	return latencies, latencySec(*first.at, *last.at)
+}
+
+// latencySec returns the seconds from start to end, 0 if end is before start.
+func latencySec(start, end time.Time) float64 {
+	if end.Before(start) {
+		return 0
+	}
+	return end.Sub(start).Seconds()
+}
@@ -0,0 +1,48 @@
+package relayer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testStageLatencies(t *testing.T) {
+	start := time.Now()
+	at := func(sec int) *time.Time {
+		stageAt := start.Add(time.Duration(sec) * time.Second)
+		return &stageAt
+	}
+
+	latencies, total := stageLatencies([]batchLifecycleStage{
+		{name: "block_imported", at: at(0)},
+		{name: "chunk_proposed", at: at(10)},
+		{name: "batch_proposed", at: at(30)},
+		{name: "batch_committed", at: at(100)},
+		{name: "proof_verified", at: at(90)},
+		{name: "batch_finalized", at: at(400)},
+	})
+	assert.Equal(t, []batchStageLatency{
+		{from: "block_imported", to: "chunk_proposed", sec: 10},
+		{from: "chunk_proposed", to: "batch_proposed", sec: 20},
+		{from: "batch_proposed", to: "batch_committed", sec: 70},
+		// the proof verified before the commit has no latency of its own.
+		{from: "batch_committed", to: "proof_verified", sec: 0},
+		{from: "proof_verified", to: "batch_finalized", sec: 310},
+	}, latencies)
+	assert.Equal(t, float64(400), total)
+
+	// the stages not reached are skipped.
+	latencies, total = stageLatencies([]batchLifecycleStage{
+		{name: "block_imported", at: nil},
+		{name: "chunk_proposed", at: at(10)},
+		{name: "proof_verified", at: nil},
+		{name: "batch_finalized", at: at(60)},
+	})
+	assert.Equal(t, []batchStageLatency{{from: "chunk_proposed", to: "batch_finalized", sec: 50}}, latencies)
+	assert.Equal(t, float64(50), total)
+
+	latencies, total = stageLatencies([]batchLifecycleStage{{name: "block_imported", at: nil}})
+	assert.Empty(t, latencies)
+	assert.Equal(t, float64(0), total)
+}
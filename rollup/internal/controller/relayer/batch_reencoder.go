@@ -0,0 +1,234 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/accounts/abi"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/params"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types/encoding"
+
+	bridgeAbi "scroll-tech/rollup/abi"
+	"scroll-tech/rollup/internal/orm"
+	"scroll-tech/rollup/internal/utils"
+)
+
+// Sources of the values a re-encoded batch is compared against.
+const (
+	ReencodeSourceDB = "db"
+	ReencodeSourceL1 = "l1"
+)
+
+// BatchDiscrepancy is a value of a historical batch that changed when re-encoded under the current code.
+type BatchDiscrepancy struct {
+	BatchIndex uint64 `json:"batch_index"`
+	// ChunkIndex is only set for chunk hash discrepancies.
+	ChunkIndex *uint64 `json:"chunk_index,omitempty"`
+	Source     string  `json:"source"`
+	Field      string  `json:"field"`
+	Recorded   string  `json:"recorded"`
+	Reencoded  string  `json:"reencoded"`
+}
+
+// BatchReencodeReport is the result of re-encoding a range of historical batches.
+type BatchReencodeReport struct {
+	StartBatchIndex uint64              `json:"start_batch_index"`
+	EndBatchIndex   uint64              `json:"end_batch_index"`
+	CheckedBatches  uint64              `json:"checked_batches"`
+	CheckedOnL1     uint64              `json:"checked_on_l1"`
+	Discrepancies   []*BatchDiscrepancy `json:"discrepancies"`
+}
+
+// BatchReencoder re-encodes historical batches with the current codecs and compares the results
+// against the hashes recorded in the database and committed on L1, so that codec refactors can be
+// checked not to change historical outputs.
+type BatchReencoder struct {
+	ctx context.Context
+
+	batchOrm   *orm.Batch
+	chunkOrm   *orm.Chunk
+	l2BlockOrm *orm.L2Block
+
+	// l1Client is optional, the L1 comparison is skipped if it's nil.
+	l1Client              ethereum.ContractCaller
+	rollupContractAddress common.Address
+	l1RollupABI           *abi.ABI
+
+	chainCfg *params.ChainConfig
+}
+
+// NewBatchReencoder creates a new BatchReencoder instance.
+func NewBatchReencoder(ctx context.Context, db *gorm.DB, l1Client ethereum.ContractCaller, rollupContractAddress common.Address, chainCfg *params.ChainConfig) *BatchReencoder {
+	return &BatchReencoder{
+		ctx:                   ctx,
+		batchOrm:              orm.NewBatch(db),
+		chunkOrm:              orm.NewChunk(db),
+		l2BlockOrm:            orm.NewL2Block(db),
+		l1Client:              l1Client,
+		rollupContractAddress: rollupContractAddress,
+		l1RollupABI:           bridgeAbi.ScrollChainABI,
+		chainCfg:              chainCfg,
+	}
+}
+
+// Reencode re-encodes the batches in [startIndex, endIndex] and reports every discrepancy.
+// The genesis batch is skipped since it's not built by the batch proposer.
+func (r *BatchReencoder) Reencode(startIndex, endIndex uint64) (*BatchReencodeReport, error) {
+	if startIndex == 0 {
+		startIndex = 1
+	}
+	report := &BatchReencodeReport{StartBatchIndex: startIndex, EndBatchIndex: endIndex, Discrepancies: []*BatchDiscrepancy{}}
+	if startIndex > endIndex {
+		return report, nil
+	}
+
+	dbParentBatch, err := r.batchOrm.GetBatchByIndex(r.ctx, startIndex-1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get parent batch, index: %d, err: %w", startIndex-1, err)
+	}
+
+	for index := startIndex; index <= endIndex; index++ {
+		dbBatch, err := r.batchOrm.GetBatchByIndex(r.ctx, index)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get batch, index: %d, err: %w", index, err)
+		}
+
+		reencodedHash, discrepancies, err := r.reencodeBatch(dbBatch, dbParentBatch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to re-encode batch, index: %d, err: %w", index, err)
+		}
+		report.Discrepancies = append(report.Discrepancies, discrepancies...)
+		report.CheckedBatches++
+
+		if r.l1Client != nil {
+			discrepancy, committed, err := r.compareWithL1(dbBatch.Index, reencodedHash)
+			if err != nil {
+				return nil, fmt.Errorf("failed to compare batch with l1, index: %d, err: %w", index, err)
+			}
+			if committed {
+				report.CheckedOnL1++
+			}
+			if discrepancy != nil {
+				report.Discrepancies = append(report.Discrepancies, discrepancy)
+			}
+		}
+
+		if len(discrepancies) > 0 {
+			log.Warn("re-encoded batch differs from its recorded values", "index", index, "discrepancies", len(discrepancies))
+		}
+		dbParentBatch = dbBatch
+	}
+	return report, nil
+}
+
+// reencodeBatch rebuilds the batch and its chunks from the stored blocks and compares them to the recorded ones.
+// It returns the re-encoded batch hash.
+func (r *BatchReencoder) reencodeBatch(dbBatch *orm.Batch, dbParentBatch *orm.Batch) (common.Hash, []*BatchDiscrepancy, error) {
+	dbChunks, err := r.chunkOrm.GetChunksInRange(r.ctx, dbBatch.StartChunkIndex, dbBatch.EndChunkIndex)
+	if err != nil {
+		return common.Hash{}, nil, err
+	}
+	if len(dbChunks) == 0 {
+		return common.Hash{}, nil, fmt.Errorf("no chunks in range [%d, %d]", dbBatch.StartChunkIndex, dbBatch.EndChunkIndex)
+	}
+
+	codecVersion := utils.GetBatchCodecVersion(r.chainCfg, dbBatch.Index, dbChunks[0].StartBlockNumber)
+	parentBatchCodecVersion := utils.GetBatchCodecVersion(r.chainCfg, dbParentBatch.Index, dbChunks[0].StartBlockNumber-1)
+
+	var discrepancies []*BatchDiscrepancy
+	batch := &encoding.Batch{
+		Index:           dbBatch.Index,
+		ParentBatchHash: common.HexToHash(dbParentBatch.Hash),
+	}
+	batch.TotalL1MessagePoppedBefore, err = utils.GetTotalL1MessagePoppedBeforeBatch(dbParentBatch.BatchHeader, parentBatchCodecVersion)
+	if err != nil {
+		return common.Hash{}, nil, err
+	}
+
+	for _, dbChunk := range dbChunks {
+		blocks, err := r.l2BlockOrm.GetL2BlocksInRange(r.ctx, dbChunk.StartBlockNumber, dbChunk.EndBlockNumber)
+		if err != nil {
+			return common.Hash{}, nil, err
+		}
+		chunk := &encoding.Chunk{Blocks: blocks}
+		batch.Chunks = append(batch.Chunks, chunk)
+
+		chunkHash, err := utils.GetChunkHash(chunk, dbChunk.TotalL1MessagesPoppedBefore, codecVersion)
+		if err != nil {
+			return common.Hash{}, nil, err
+		}
+		if chunkHash.Hex() != dbChunk.Hash {
+			chunkIndex := dbChunk.Index
+			discrepancies = append(discrepancies, &BatchDiscrepancy{
+				BatchIndex: dbBatch.Index,
+				ChunkIndex: &chunkIndex,
+				Source:     ReencodeSourceDB,
+				Field:      "chunk_hash",
+				Recorded:   dbChunk.Hash,
+				Reencoded:  chunkHash.Hex(),
+			})
+		}
+	}
+
+	batchMeta, err := utils.GetBatchMetadata(batch, codecVersion)
+	if err != nil {
+		return common.Hash{}, nil, err
+	}
+
+	compare := func(field, recorded, reencoded string) {
+		if recorded != reencoded {
+			discrepancies = append(discrepancies, &BatchDiscrepancy{
+				BatchIndex: dbBatch.Index,
+				Source:     ReencodeSourceDB,
+				Field:      field,
+				Recorded:   recorded,
+				Reencoded:  reencoded,
+			})
+		}
+	}
+	compare("batch_hash", dbBatch.Hash, batchMeta.BatchHash.Hex())
+	compare("data_hash", dbBatch.DataHash, batchMeta.BatchDataHash.Hex())
+	compare("start_chunk_hash", dbBatch.StartChunkHash, batchMeta.StartChunkHash.Hex())
+	compare("end_chunk_hash", dbBatch.EndChunkHash, batchMeta.EndChunkHash.Hex())
+	compare("batch_header", common.Bytes2Hex(dbBatch.BatchHeader), common.Bytes2Hex(batchMeta.BatchBytes))
+	compare("blob_data_proof", common.Bytes2Hex(dbBatch.BlobDataProof), common.Bytes2Hex(batchMeta.BatchBlobDataProof))
+	return batchMeta.BatchHash, discrepancies, nil
+}
+
+// compareWithL1 compares the re-encoded batch hash to the one committed on L1.
+// It returns false if the batch is not committed on L1 yet.
+func (r *BatchReencoder) compareWithL1(index uint64, reencodedHash common.Hash) (*BatchDiscrepancy, bool, error) {
+	input, err := r.l1RollupABI.Pack("committedBatches", new(big.Int).SetUint64(index))
+	if err != nil {
+		return nil, false, err
+	}
+	output, err := r.l1Client.CallContract(r.ctx, ethereum.CallMsg{To: &r.rollupContractAddress, Data: input}, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	unpacked, err := r.l1RollupABI.Unpack("committedBatches", output)
+	if err != nil {
+		return nil, false, err
+	}
+	committedBatchHash := common.Hash(*abi.ConvertType(unpacked[0], new([32]byte)).(*[32]byte))
+
+	if committedBatchHash == (common.Hash{}) {
+		return nil, false, nil
+	}
+	if committedBatchHash != reencodedHash {
+		return &BatchDiscrepancy{
+			BatchIndex: index,
+			Source:     ReencodeSourceL1,
+			Field:      "batch_hash",
+			Recorded:   committedBatchHash.Hex(),
+			Reencoded:  reencodedHash.Hex(),
+		}, true, nil
+	}
+	return nil, true, nil
+}
@@ -0,0 +1,64 @@
+package relayer
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/database"
+	"scroll-tech/common/types/encoding"
+
+	"scroll-tech/rollup/internal/orm"
+)
+
+func testBatchReencoderReencode(t *testing.T) {
+	db := setupL2RelayerDB(t)
+	defer database.CloseDB(db)
+
+	chainConfig := &params.ChainConfig{BernoulliBlock: big.NewInt(0)}
+	relayer, err := NewLayer2Relayer(context.Background(), l2Cli, db, cfg.L2Config.RelayerConfig, chainConfig, true, ServiceTypeL2RollupRelayer, nil)
+	assert.NoError(t, err)
+	defer relayer.StopSenders()
+
+	l2BlockOrm := orm.NewL2Block(db)
+	err = l2BlockOrm.InsertL2Blocks(context.Background(), []*encoding.Block{block1, block2})
+	assert.NoError(t, err)
+	chunkOrm := orm.NewChunk(db)
+	_, err = chunkOrm.InsertChunk(context.Background(), chunk1, encoding.CodecV1)
+	assert.NoError(t, err)
+	_, err = chunkOrm.InsertChunk(context.Background(), chunk2, encoding.CodecV1)
+	assert.NoError(t, err)
+
+	batchOrm := orm.NewBatch(db)
+	genesisBatch, err := batchOrm.GetBatchByIndex(context.Background(), 0)
+	assert.NoError(t, err)
+	batch := &encoding.Batch{
+		Index:                      1,
+		TotalL1MessagePoppedBefore: 0,
+		ParentBatchHash:            common.HexToHash(genesisBatch.Hash),
+		Chunks:                     []*encoding.Chunk{chunk1, chunk2},
+	}
+	_, err = batchOrm.InsertBatch(context.Background(), batch, encoding.CodecV1)
+	assert.NoError(t, err)
+
+	reencoder := NewBatchReencoder(context.Background(), db, nil, cfg.L2Config.RelayerConfig.RollupContractAddress, chainConfig)
+	report, err := reencoder.Reencode(0, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), report.CheckedBatches)
+	assert.Equal(t, uint64(0), report.CheckedOnL1)
+	assert.Empty(t, report.Discrepancies)
+
+	// a changed historical output is reported.
+	err = db.Model(&orm.Batch{}).Where("index = ?", 1).Update("data_hash", common.Hash{}.Hex()).Error
+	assert.NoError(t, err)
+	report, err = reencoder.Reencode(1, 1)
+	assert.NoError(t, err)
+	assert.Len(t, report.Discrepancies, 1)
+	assert.Equal(t, ReencodeSourceDB, report.Discrepancies[0].Source)
+	assert.Equal(t, "data_hash", report.Discrepancies[0].Field)
+	assert.Equal(t, common.Hash{}.Hex(), report.Discrepancies[0].Recorded)
+}
@@ -0,0 +1,287 @@
+package relayer
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+
+	"scroll-tech/rollup/internal/controller/sender"
+	"scroll-tech/rollup/internal/orm"
+)
+
+// batchRevertContextPrefix prefixes the context id of the revertBatch transactions, sent by the commit sender.
+const batchRevertContextPrefix = "revert_batches:"
+
+// The statuses of a batch revert.
+const (
+	// BatchRevertStatusPlanned is a revert checked and packed, not sent.
+	BatchRevertStatusPlanned = "planned"
+	// BatchRevertStatusSent is a revert whose revertBatch transaction is in flight.
+	BatchRevertStatusSent = "sent"
+	// BatchRevertStatusReverted is a revert confirmed on L1, waiting for the L1 watcher to import it.
+	BatchRevertStatusReverted = "reverted"
+	// BatchRevertStatusRolledBack is a revert whose batches are deleted and their chunks released.
+	BatchRevertStatusRolledBack = "rolled_back"
+	// BatchRevertStatusFailed is a revert whose revertBatch transaction failed on L1.
+	BatchRevertStatusFailed = "failed"
+)
+
+// BatchRevert is the revert of the last committed batches of an index range: the revertBatch call of the rollup
+// contract, then the rollback of the batches from the DB, so that their chunks are proposed in new batches.
+type BatchRevert struct {
+	StartIndex     uint64         `json:"start_index"`
+	EndIndex       uint64         `json:"end_index"`
+	StartBatchHash string         `json:"start_batch_hash"`
+	EndBatchHash   string         `json:"end_batch_hash"`
+	To             common.Address `json:"to"`
+	Calldata       hexutil.Bytes  `json:"calldata"`
+	Status         string         `json:"status"`
+	TxHash         string         `json:"tx_hash,omitempty"`
+
+	startChunkIndex uint64
+}
+
+func (b *BatchRevert) contextID() string {
+	return fmt.Sprintf("%s%d-%d", batchRevertContextPrefix, b.StartIndex, b.EndIndex)
+}
+
+// RevertBatches reverts the committed batches from startIndex to endIndex, which must be the last committed ones.
+// The revertBatch call is only checked and returned if send is false. Else it's sent by the commit sender, which
+// must be the owner of the rollup contract, and the commit and finalize pipelines hold until the L1 watcher imported
+// the revert and the batches, along with the pending batches after them, are deleted and their chunks released.
+// A revert in flight when the relayer stops isn't rolled back, its batches are rolled back with the rollback command.
+func (r *Layer2Relayer) RevertBatches(startIndex, endIndex uint64, send bool) (*BatchRevert, error) {
+	r.batchRevertMu.Lock()
+	defer r.batchRevertMu.Unlock()
+
+	if r.batchRevert != nil && (r.batchRevert.Status == BatchRevertStatusSent || r.batchRevert.Status == BatchRevertStatusReverted) {
+		return nil, fmt.Errorf("the revert of batches %d-%d is in progress, status: %s", r.batchRevert.StartIndex, r.batchRevert.EndIndex, r.batchRevert.Status)
+	}
+
+	revert, err := r.planBatchRevert(startIndex, endIndex)
+	if err != nil {
+		return nil, err
+	}
+	if !send {
+		return revert, nil
+	}
+	if r.commitSender == nil {
+		return nil, errors.New("the batches are only reverted by the rollup relayer")
+	}
+	if r.multisig != nil {
+		return nil, errors.New("the rollup contract calls are prepared for the multisig, the revertBatch call must be sent by the owner")
+	}
+
+	txHash, err := r.commitSender.SendTransaction(revert.contextID(), &revert.To, revert.Calldata, nil, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send revertBatch tx to L1, start index: %d, end index: %d, err: %w", startIndex, endIndex, err)
+	}
+	revert.Status = BatchRevertStatusSent
+	revert.TxHash = txHash.String()
+	r.batchRevert = revert
+	r.metrics.rollupL2RelayerBatchRevertTotal.WithLabelValues(BatchRevertStatusSent).Inc()
+	log.Warn("Sent revertBatch transaction", "start index", startIndex, "end index", endIndex, "tx hash", revert.TxHash)
+	return revert, nil
+}
+
+// BatchRevert returns the last revert sent by RevertBatches, nil if none was sent since the relayer started.
+func (r *Layer2Relayer) BatchRevert() *BatchRevert {
+	r.batchRevertMu.Lock()
+	defer r.batchRevertMu.Unlock()
+
+	if r.batchRevert == nil {
+		return nil
+	}
+	revert := *r.batchRevert
+	return &revert
+}
+
+// planBatchRevert checks that the batches of the range are the last committed ones, and not finalized, and packs
+// their revertBatch call.
+func (r *Layer2Relayer) planBatchRevert(startIndex, endIndex uint64) (*BatchRevert, error) {
+	if startIndex == 0 {
+		return nil, errors.New("the genesis batch can't be reverted")
+	}
+	if startIndex > endIndex {
+		return nil, fmt.Errorf("start index %d is greater than end index %d", startIndex, endIndex)
+	}
+
+	dbBatches, err := r.batchOrm.GetBatches(r.ctx, map[string]interface{}{
+		"index >= ?": startIndex,
+		"index <= ?": endIndex,
+	}, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(dbBatches)) != endIndex-startIndex+1 {
+		return nil, fmt.Errorf("found %d batches in the range %d-%d", len(dbBatches), startIndex, endIndex)
+	}
+	for _, dbBatch := range dbBatches {
+		if status := types.RollupStatus(dbBatch.RollupStatus); status != types.RollupCommitted {
+			return nil, fmt.Errorf("batch %d has rollup status %s, only committed batches can be reverted", dbBatch.Index, status)
+		}
+	}
+
+	// the rollup contract only reverts the last committed batches.
+	committed, err := r.batchOrm.GetBatches(r.ctx, map[string]interface{}{
+		"index > ?":          endIndex,
+		"rollup_status IN ?": []types.RollupStatus{types.RollupCommitting, types.RollupCommitted, types.RollupFinalizing, types.RollupFinalized},
+	}, nil, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(committed) > 0 {
+		return nil, fmt.Errorf("batch %d has rollup status %s, only the last committed batches can be reverted", committed[0].Index, types.RollupStatus(committed[0].RollupStatus))
+	}
+
+	startBatch, endBatch := dbBatches[0], dbBatches[len(dbBatches)-1]
+	if r.sequencingGuardClient != nil {
+		if err = r.checkRevertOnL1(startBatch, endBatch); err != nil {
+			return nil, err
+		}
+	}
+
+	calldata, err := r.l1RollupABI.Pack("revertBatch", startBatch.BatchHeader, new(big.Int).SetUint64(endIndex-startIndex+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack revertBatch, start index: %d, end index: %d, err: %w", startIndex, endIndex, err)
+	}
+	return &BatchRevert{
+		StartIndex:      startIndex,
+		EndIndex:        endIndex,
+		StartBatchHash:  startBatch.Hash,
+		EndBatchHash:    endBatch.Hash,
+		To:              r.cfg.RollupContractAddress,
+		Calldata:        calldata,
+		Status:          BatchRevertStatusPlanned,
+		startChunkIndex: startBatch.StartChunkIndex,
+	}, nil
+}
+
+// checkRevertOnL1 checks the range against the rollup contract: its first and last batches are committed with
+// their hashes, the batch after it isn't committed, and its first batch isn't finalized.
+func (r *Layer2Relayer) checkRevertOnL1(startBatch *orm.Batch, endBatch *orm.Batch) error {
+	for _, dbBatch := range []*orm.Batch{startBatch, endBatch} {
+		committedHash, err := r.committedBatchHash(dbBatch.Index)
+		if err != nil {
+			return err
+		}
+		if committedHash != common.HexToHash(dbBatch.Hash) {
+			return fmt.Errorf("batch %d is committed on L1 with hash %v, expected %v", dbBatch.Index, committedHash, dbBatch.Hash)
+		}
+	}
+
+	nextHash, err := r.committedBatchHash(endBatch.Index + 1)
+	if err != nil {
+		return err
+	}
+	if nextHash != (common.Hash{}) {
+		return fmt.Errorf("batch %d is committed on L1 with hash %v, only the last committed batches can be reverted", endBatch.Index+1, nextHash)
+	}
+
+	lastFinalizedIndex, err := r.lastFinalizedBatchIndex()
+	if err != nil {
+		return err
+	}
+	if lastFinalizedIndex >= startBatch.Index {
+		return fmt.Errorf("batch %d is finalized on L1, last finalized batch: %d", startBatch.Index, lastFinalizedIndex)
+	}
+	return nil
+}
+
+// isBatchRevertContext returns true if the confirmation is of a revertBatch transaction.
+func isBatchRevertContext(contextID string) bool {
+	return strings.HasPrefix(contextID, batchRevertContextPrefix)
+}
+
+// handleBatchRevertConfirmation records the outcome of the revertBatch transaction, the batches are rolled back
+// once the L1 watcher imported the revert.
+func (r *Layer2Relayer) handleBatchRevertConfirmation(cfm *sender.Confirmation) {
+	r.batchRevertMu.Lock()
+	defer r.batchRevertMu.Unlock()
+
+	revert := r.batchRevert
+	if revert == nil || revert.contextID() != cfm.ContextID {
+		log.Warn("revertBatch transaction confirmed after a restart, roll its batches back with the rollback command", "confirmation", cfm)
+		return
+	}
+
+	revert.TxHash = cfm.TxHash.String()
+	if !cfm.IsSuccessful {
+		revert.Status = BatchRevertStatusFailed
+		r.metrics.rollupL2RelayerBatchRevertTotal.WithLabelValues(BatchRevertStatusFailed).Inc()
+		log.Error("revertBatch transaction confirmed but failed in layer1, the batches are kept", "start index", revert.StartIndex, "end index", revert.EndIndex, "confirmation", cfm)
+		return
+	}
+	revert.Status = BatchRevertStatusReverted
+	r.metrics.rollupL2RelayerBatchRevertTotal.WithLabelValues(BatchRevertStatusReverted).Inc()
+	log.Warn("Batches reverted on L1", "start index", revert.StartIndex, "end index", revert.EndIndex, "tx hash", revert.TxHash)
+}
+
+// isHeldForBatchRevert returns true while a batch revert is in progress, rolling the reverted batches back once the
+// L1 watcher moved them back to pending.
+func (r *Layer2Relayer) isHeldForBatchRevert() bool {
+	r.batchRevertMu.Lock()
+	defer r.batchRevertMu.Unlock()
+
+	revert := r.batchRevert
+	if revert == nil {
+		return false
+	}
+	switch revert.Status {
+	case BatchRevertStatusSent:
+		log.Debug("revertBatch transaction in flight, holding the commit and finalize pipelines", "start index", revert.StartIndex, "end index", revert.EndIndex)
+		return true
+	case BatchRevertStatusReverted:
+		rolledBack, err := r.rollbackRevertedBatches(revert)
+		if err != nil {
+			log.Error("failed to roll back the reverted batches", "start index", revert.StartIndex, "end index", revert.EndIndex, "err", err)
+			return true
+		}
+		if !rolledBack {
+			log.Debug("waiting for the L1 watcher to import the batch revert", "start index", revert.StartIndex, "end index", revert.EndIndex)
+			return true
+		}
+		revert.Status = BatchRevertStatusRolledBack
+		r.metrics.rollupL2RelayerBatchRevertTotal.WithLabelValues(BatchRevertStatusRolledBack).Inc()
+		log.Warn("Rolled back the reverted batches, their chunks are re-proposed", "start index", revert.StartIndex, "end index", revert.EndIndex)
+		return false
+	default:
+		return false
+	}
+}
+
+// rollbackRevertedBatches deletes the reverted batches along with the batches after them and releases their chunks
+// to the batch proposer. It returns false if the L1 watcher hasn't moved the reverted batches back to pending yet,
+// since it fails on the revert events of deleted batches.
+func (r *Layer2Relayer) rollbackRevertedBatches(revert *BatchRevert) (bool, error) {
+	notImported, err := r.batchOrm.GetBatches(r.ctx, map[string]interface{}{
+		"index >= ?":         revert.StartIndex,
+		"index <= ?":         revert.EndIndex,
+		"rollup_status <> ?": types.RollupPending,
+	}, nil, 1)
+	if err != nil {
+		return false, err
+	}
+	if len(notImported) > 0 {
+		return false, nil
+	}
+
+	err = r.db.Transaction(func(dbTX *gorm.DB) error {
+		if err := r.batchOrm.DeleteBatchesGtIndex(r.ctx, revert.StartIndex-1, dbTX); err != nil {
+			return err
+		}
+		return r.chunkOrm.ResetBatchHashGtIndex(r.ctx, revert.startChunkIndex-1, dbTX)
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,70 @@
+package relayer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/types"
+
+	bridgeAbi "scroll-tech/rollup/abi"
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/controller/sender"
+	"scroll-tech/rollup/internal/orm"
+)
+
+func testBatchRevert(t *testing.T) {
+	startBatch := &orm.Batch{Index: 2, Hash: common.HexToHash("0x02").Hex()}
+	endBatch := &orm.Batch{Index: 3, Hash: common.HexToHash("0x03").Hex()}
+	client := &mockSequencingGuardClient{committedBatches: map[uint64]common.Hash{1: common.HexToHash("0x01"), 2: common.HexToHash("0x02"), 3: common.HexToHash("0x03")}, lastFinalizedIndex: 1}
+	r := &Layer2Relayer{
+		ctx:         context.Background(),
+		cfg:         &config.RelayerConfig{},
+		l1RollupABI: bridgeAbi.ScrollChainABI,
+		metrics:     initL2RelayerMetrics(prometheus.NewRegistry()),
+	}
+	r.SetSequencingGuard(client)
+	assert.NoError(t, r.checkRevertOnL1(startBatch, endBatch))
+
+	// only the last committed batches can be reverted.
+	client.committedBatches[4] = common.HexToHash("0x04")
+	assert.Error(t, r.checkRevertOnL1(startBatch, endBatch))
+	delete(client.committedBatches, 4)
+
+	// the batches committed on L1 with another hash aren't reverted.
+	client.committedBatches[3] = common.HexToHash("0x0f")
+	assert.Error(t, r.checkRevertOnL1(startBatch, endBatch))
+	client.committedBatches[3] = common.HexToHash("0x03")
+
+	// the finalized batches can't be reverted.
+	client.lastFinalizedIndex = 2
+	assert.Error(t, r.checkRevertOnL1(startBatch, endBatch))
+
+	// the pipelines hold while the revert is in flight, and no other revert is sent.
+	revert := &BatchRevert{StartIndex: 2, EndIndex: 3, Status: BatchRevertStatusSent}
+	r.batchRevert = revert
+	assert.True(t, r.isHeldForBatchRevert())
+	_, err := r.RevertBatches(2, 3, true)
+	assert.Error(t, err)
+
+	// the confirmations of the other transactions aren't taken for the revert.
+	assert.False(t, isBatchRevertContext(startBatch.Hash))
+	assert.True(t, isBatchRevertContext(revert.contextID()))
+
+	// the pipelines resume once the revert failed.
+	r.handleConfirmation(&sender.Confirmation{ContextID: revert.contextID(), IsSuccessful: false, TxHash: common.HexToHash("0x0a"), SenderType: types.SenderTypeCommitBatch})
+	assert.Equal(t, BatchRevertStatusFailed, r.BatchRevert().Status)
+	assert.Equal(t, common.HexToHash("0x0a").String(), r.BatchRevert().TxHash)
+	assert.False(t, r.isHeldForBatchRevert())
+
+	// the revert of a previous relayer run is ignored.
+	r.batchRevert = &BatchRevert{StartIndex: 2, EndIndex: 3, Status: BatchRevertStatusSent}
+	r.handleConfirmation(&sender.Confirmation{ContextID: batchRevertContextPrefix + "2-4", IsSuccessful: true, SenderType: types.SenderTypeCommitBatch})
+	assert.Equal(t, BatchRevertStatusSent, r.BatchRevert().Status)
+
+	r.handleConfirmation(&sender.Confirmation{ContextID: revert.contextID(), IsSuccessful: true, TxHash: common.HexToHash("0x0b"), SenderType: types.SenderTypeCommitBatch})
+	assert.Equal(t, BatchRevertStatusReverted, r.BatchRevert().Status)
+}
@@ -0,0 +1,264 @@
+package relayer
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto/kzg4844"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/params"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+	"scroll-tech/common/types/encoding"
+	"scroll-tech/common/types/encoding/codecv1"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+	"scroll-tech/rollup/internal/utils"
+)
+
+// BlobVerificationSourceBeacon is the source of the blob discrepancies, the blob sidecars served by the beacon node.
+const BlobVerificationSourceBeacon = "beacon"
+
+// maxBlobVerificationsPerTick is the number of batches verified by a VerifyCommittedBatches call at most.
+const maxBlobVerificationsPerTick = 10
+
+// blobVerificationL1Client is the part of the l1 client used by the BlobVerifier.
+type blobVerificationL1Client interface {
+	TransactionByHash(ctx context.Context, hash common.Hash) (*gethTypes.Transaction, bool, error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*gethTypes.Receipt, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*gethTypes.Header, error)
+}
+
+// BlobVerifier fetches the blob sidecars of the commit transactions from the beacon node and checks that
+// the data available on L1 matches the batch payload rebuilt from the local database.
+type BlobVerifier struct {
+	ctx context.Context
+
+	batchOrm   *orm.Batch
+	chunkOrm   *orm.Chunk
+	l2BlockOrm *orm.L2Block
+
+	l1Client blobVerificationL1Client
+	beacon   *utils.BeaconClient
+	chainCfg *params.ChainConfig
+
+	// nextBatchIndex is the index of the next batch to verify.
+	nextBatchIndex uint64
+
+	blobVerificationTotal         prometheus.Counter
+	blobVerificationFailureTotal  prometheus.Counter
+	blobVerificationMismatchTotal *prometheus.CounterVec
+	blobVerificationBatchIndex    prometheus.Gauge
+}
+
+// NewBlobVerifier creates a new BlobVerifier, verifying the batches from the configured start index on,
+// or from the latest batch if not set.
+func NewBlobVerifier(ctx context.Context, db *gorm.DB, l1Client blobVerificationL1Client, cfg *config.BlobVerificationConfig, chainCfg *params.ChainConfig, reg prometheus.Registerer) (*BlobVerifier, error) {
+	v := &BlobVerifier{
+		ctx:            ctx,
+		batchOrm:       orm.NewBatch(db),
+		chunkOrm:       orm.NewChunk(db),
+		l2BlockOrm:     orm.NewL2Block(db),
+		l1Client:       l1Client,
+		beacon:         utils.NewBeaconClient(cfg.BeaconEndpoint, cfg.SecondsPerSlot),
+		chainCfg:       chainCfg,
+		nextBatchIndex: cfg.StartBatchIndex,
+
+		blobVerificationTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_blob_verification_total",
+			Help: "The total number of committed batches whose blobs were verified against the beacon node.",
+		}),
+		blobVerificationFailureTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_blob_verification_failure_total",
+			Help: "The total number of failed blob verification attempts.",
+		}),
+		blobVerificationMismatchTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "rollup_blob_verification_mismatch_total",
+			Help: "The total number of mismatches between the blobs available on L1 and the local batch payloads.",
+		}, []string{"field"}),
+		blobVerificationBatchIndex: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_blob_verification_batch_index",
+			Help: "The index of the latest batch whose blobs were verified.",
+		}),
+	}
+
+	if v.nextBatchIndex == 0 {
+		latestBatch, err := v.batchOrm.GetLatestBatch(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get latest batch: %w", err)
+		}
+		if latestBatch != nil {
+			v.nextBatchIndex = latestBatch.Index
+		}
+	}
+	return v, nil
+}
+
+// VerifyCommittedBatches verifies the blobs of the batches committed since the last call, in index order.
+func (v *BlobVerifier) VerifyCommittedBatches() {
+	for i := 0; i < maxBlobVerificationsPerTick; i++ {
+		dbBatches, err := v.batchOrm.GetBatches(v.ctx, map[string]interface{}{"index = ?": v.nextBatchIndex}, nil, 1)
+		if err != nil {
+			log.Error("failed to get batch to verify blobs", "index", v.nextBatchIndex, "err", err)
+			return
+		}
+		if len(dbBatches) == 0 {
+			return
+		}
+		dbBatch := dbBatches[0]
+
+		switch types.RollupStatus(dbBatch.RollupStatus) {
+		case types.RollupPending, types.RollupCommitting:
+			// wait for the commit transaction to be confirmed.
+			return
+		case types.RollupCommitFailed:
+			// the blobs of a failed commit transaction are not part of the rollup.
+			v.nextBatchIndex++
+			continue
+		}
+
+		discrepancies, err := v.verifyBatch(dbBatch)
+		if err != nil {
+			v.blobVerificationFailureTotal.Inc()
+			log.Error("failed to verify batch blobs", "index", dbBatch.Index, "hash", dbBatch.Hash, "commitTxHash", dbBatch.CommitTxHash, "err", err)
+			return
+		}
+		for _, discrepancy := range discrepancies {
+			v.blobVerificationMismatchTotal.WithLabelValues(discrepancy.Field).Inc()
+			log.Error("blob available on L1 does not match the local batch payload", "index", dbBatch.Index, "hash", dbBatch.Hash,
+				"commitTxHash", dbBatch.CommitTxHash, "field", discrepancy.Field, "onChain", discrepancy.Recorded, "local", discrepancy.Reencoded)
+		}
+
+		v.blobVerificationTotal.Inc()
+		v.blobVerificationBatchIndex.Set(float64(dbBatch.Index))
+		v.nextBatchIndex++
+	}
+}
+
+// verifyBatch compares the blobs of the commit transaction of the batch with the ones rebuilt from the database.
+func (v *BlobVerifier) verifyBatch(dbBatch *orm.Batch) ([]*BatchDiscrepancy, error) {
+	localBlobs, localHashes, err := v.localBlobs(dbBatch)
+	if err != nil {
+		return nil, err
+	}
+	if localBlobs == nil {
+		// the batch is committed without blobs.
+		return nil, nil
+	}
+
+	commitTxHash := common.HexToHash(dbBatch.CommitTxHash)
+	tx, _, err := v.l1Client.TransactionByHash(v.ctx, commitTxHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit transaction: %w", err)
+	}
+
+	discrepancy := func(field, onChain, local string) *BatchDiscrepancy {
+		return &BatchDiscrepancy{BatchIndex: dbBatch.Index, Source: BlobVerificationSourceBeacon, Field: field, Recorded: onChain, Reencoded: local}
+	}
+
+	onChainHashes := tx.BlobHashes()
+	recordedHashes, err := dbBatch.GetBlobVersionedHashes()
+	if err != nil {
+		return nil, err
+	}
+	if recordedHashes != nil && !slices.Equal(recordedHashes, onChainHashes) {
+		return []*BatchDiscrepancy{discrepancy("recorded_blob_versioned_hashes", fmt.Sprint(onChainHashes), fmt.Sprint(recordedHashes))}, nil
+	}
+	if len(onChainHashes) != len(localHashes) {
+		return []*BatchDiscrepancy{discrepancy("blob_count", fmt.Sprint(len(onChainHashes)), fmt.Sprint(len(localHashes)))}, nil
+	}
+
+	sidecars, err := v.blobSidecars(dbBatch, commitTxHash)
+	if err != nil {
+		return nil, err
+	}
+
+	var discrepancies []*BatchDiscrepancy
+	for i, onChainHash := range onChainHashes {
+		if onChainHash != localHashes[i] {
+			discrepancies = append(discrepancies, discrepancy("blob_versioned_hash", onChainHash.Hex(), localHashes[i].Hex()))
+			continue
+		}
+		blob, ok := sidecars[onChainHash]
+		if !ok {
+			discrepancies = append(discrepancies, discrepancy("blob_sidecar", "missing", onChainHash.Hex()))
+			continue
+		}
+		if *blob != *localBlobs[i] {
+			discrepancies = append(discrepancies, discrepancy("blob_data", onChainHash.Hex(), "differs"))
+		}
+	}
+	return discrepancies, nil
+}
+
+// localBlobs rebuilds the blobs of the batch from the database, nil for the batches committed without blobs.
+func (v *BlobVerifier) localBlobs(dbBatch *orm.Batch) ([]*kzg4844.Blob, []common.Hash, error) {
+	dbChunks, err := v.chunkOrm.GetChunksInRange(v.ctx, dbBatch.StartChunkIndex, dbBatch.EndChunkIndex)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(dbChunks) == 0 {
+		return nil, nil, fmt.Errorf("no chunks in range [%d, %d]", dbBatch.StartChunkIndex, dbBatch.EndChunkIndex)
+	}
+	if utils.GetBatchCodecVersion(v.chainCfg, dbBatch.Index, dbChunks[0].StartBlockNumber) == encoding.CodecV0 {
+		return nil, nil, nil
+	}
+
+	batch := &encoding.Batch{
+		Index:                      dbBatch.Index,
+		TotalL1MessagePoppedBefore: dbChunks[0].TotalL1MessagesPoppedBefore,
+		ParentBatchHash:            common.HexToHash(dbBatch.ParentBatchHash),
+	}
+	for _, dbChunk := range dbChunks {
+		blocks, err := v.l2BlockOrm.GetL2BlocksInRange(v.ctx, dbChunk.StartBlockNumber, dbChunk.EndBlockNumber)
+		if err != nil {
+			return nil, nil, err
+		}
+		batch.Chunks = append(batch.Chunks, &encoding.Chunk{Blocks: blocks})
+	}
+
+	daBatch, err := codecv1.NewDABatch(batch)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create DA batch: %w", err)
+	}
+	return []*kzg4844.Blob{daBatch.Blob()}, []common.Hash{daBatch.BlobVersionedHash}, nil
+}
+
+// blobSidecars returns the blobs of the L1 block including the commit transaction, by versioned hash.
+func (v *BlobVerifier) blobSidecars(dbBatch *orm.Batch, commitTxHash common.Hash) (map[common.Hash]*kzg4844.Blob, error) {
+	l1BlockNumber := dbBatch.CommitL1BlockNumber
+	if l1BlockNumber == 0 {
+		receipt, err := v.l1Client.TransactionReceipt(v.ctx, commitTxHash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get commit transaction receipt: %w", err)
+		}
+		l1BlockNumber = receipt.BlockNumber.Uint64()
+	}
+	header, err := v.l1Client.HeaderByNumber(v.ctx, new(big.Int).SetUint64(l1BlockNumber))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit l1 block header: %w", err)
+	}
+	slot, err := v.beacon.Slot(v.ctx, header.Time)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get beacon slot: %w", err)
+	}
+	sidecars, err := v.beacon.BlobSidecars(v.ctx, slot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blob sidecars: %w", err)
+	}
+
+	blobs := make(map[common.Hash]*kzg4844.Blob, len(sidecars))
+	for _, sidecar := range sidecars {
+		blobs[kzg4844.CalcBlobHashV1(sha256.New(), &sidecar.KZGCommitment)] = sidecar.Blob
+	}
+	return blobs, nil
+}
@@ -0,0 +1,87 @@
+package relayer
+
+import (
+	"fmt"
+
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+// The names of the relayer pipelines guarded by a circuit breaker, identifying their pause state in the
+// proposer pause table shared with the proposers.
+const (
+	// CommitCircuitBreakerName identifies the pipeline sending the commitBatch transactions.
+	CommitCircuitBreakerName = "commit_relayer"
+	// FinalizeCircuitBreakerName identifies the pipeline sending the finalizeBatch transactions.
+	FinalizeCircuitBreakerName = "finalize_relayer"
+)
+
+// circuitBreaker counts the consecutive failed transactions of a relayer pipeline.
+type circuitBreaker struct {
+	name string
+	// maxConsecutiveFailures is 0 if the pipeline is only paused by the reverts aborting it.
+	maxConsecutiveFailures uint64
+	// consecutiveFailures is only accessed by the confirmation loop.
+	consecutiveFailures uint64
+}
+
+func newCircuitBreaker(name string, cfg *config.CircuitBreakerConfig) *circuitBreaker {
+	b := &circuitBreaker{name: name}
+	if cfg != nil {
+		b.maxConsecutiveFailures = cfg.MaxConsecutiveFailures
+	}
+	return b
+}
+
+// record records the outcome of a confirmed transaction, it returns true if the failures in a row reach the threshold.
+func (b *circuitBreaker) record(successful bool) bool {
+	if successful {
+		b.consecutiveFailures = 0
+		return false
+	}
+	b.consecutiveFailures++
+	return b.maxConsecutiveFailures > 0 && b.consecutiveFailures >= b.maxConsecutiveFailures
+}
+
+// recordConfirmation records the outcome of a confirmed transaction of the pipeline, and pauses the pipeline
+// once its transactions failed too many times in a row.
+func (r *Layer2Relayer) recordConfirmation(b *circuitBreaker, successful bool) {
+	if !b.record(successful) {
+		return
+	}
+	if r.tripCircuitBreaker(b, fmt.Sprintf("%v transactions failed in a row", b.consecutiveFailures)) {
+		b.consecutiveFailures = 0
+	}
+}
+
+// tripCircuitBreaker pauses the pipeline, it returns false if the pause couldn't be persisted. The pause is
+// persisted, so that it survives restarts and is only lifted by an operator.
+func (r *Layer2Relayer) tripCircuitBreaker(b *circuitBreaker, reason string) bool {
+	if err := r.proposerPauseOrm.UpsertProposerPause(r.ctx, b.name, true, reason); err != nil {
+		log.Error("failed to trip the relayer circuit breaker", "pipeline", b.name, "reason", reason, "err", err)
+		return false
+	}
+	r.metrics.rollupL2RelayerCircuitBreakerTrippedTotal.WithLabelValues(b.name).Inc()
+	r.metrics.rollupL2RelayerCircuitBreakerOpen.WithLabelValues(b.name).Set(1)
+	log.Error("relayer circuit breaker tripped, the pipeline is paused until resumed by an operator", "pipeline", b.name, "reason", reason)
+	return true
+}
+
+// isCircuitOpen returns whether the pipeline is paused by its circuit breaker, reading the persisted pause state so
+// that a resume through the admin API is observed. The pipeline is held if the pause state can't be read.
+func (r *Layer2Relayer) isCircuitOpen(b *circuitBreaker) bool {
+	pause, err := r.proposerPauseOrm.GetProposerPause(r.ctx, b.name)
+	if err != nil {
+		log.Error("failed to get the relayer circuit breaker state", "pipeline", b.name, "err", err)
+		return true
+	}
+	open := pause != nil && pause.Paused
+	if open {
+		r.metrics.rollupL2RelayerCircuitBreakerOpen.WithLabelValues(b.name).Set(1)
+		log.Warn("relayer pipeline is paused, skipping", "pipeline", b.name, "reason", pause.Reason)
+	} else {
+		r.metrics.rollupL2RelayerCircuitBreakerOpen.WithLabelValues(b.name).Set(0)
+	}
+	return open
+}
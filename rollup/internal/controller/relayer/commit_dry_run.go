@@ -0,0 +1,156 @@
+package relayer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/consensus/misc"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/params"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+	"scroll-tech/common/types/encoding"
+
+	bridgeAbi "scroll-tech/rollup/abi"
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+)
+
+// commitDryRunClient is the L1 node the gas of the dry-run commit transaction is estimated with.
+type commitDryRunClient interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*gethTypes.Header, error)
+	EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error)
+}
+
+// CommitDryRun is the commitBatch transaction of the next pending batch, constructed without being sent.
+type CommitDryRun struct {
+	BatchIndex   uint64                `json:"batch_index"`
+	BatchHash    string                `json:"batch_hash"`
+	RollupStatus string                `json:"rollup_status"`
+	CodecVersion encoding.CodecVersion `json:"codec_version"`
+
+	ParentBatchIndex   uint64 `json:"parent_batch_index"`
+	ParentBatchHash    string `json:"parent_batch_hash"`
+	ParentRollupStatus string `json:"parent_rollup_status"`
+	// ParentLinked is whether the parent batch hash recorded by the batch is the hash of its parent batch.
+	ParentLinked bool `json:"parent_linked"`
+
+	// From is the caller of the rollup contract: the commit sender, the multisig or the committee relay contract.
+	From     common.Address      `json:"from"`
+	To       common.Address      `json:"to"`
+	Calldata hexutil.Bytes       `json:"calldata"`
+	Blobs    []*CommitDryRunBlob `json:"blobs,omitempty"`
+
+	EstimatedGas       uint64 `json:"estimated_gas,omitempty"`
+	GasEstimationError string `json:"gas_estimation_error,omitempty"`
+}
+
+// CommitDryRunBlob summarizes a blob carried by the dry-run commit transaction.
+type CommitDryRunBlob struct {
+	VersionedHash common.Hash `json:"versioned_hash"`
+	// PayloadSize is the size of the blob without its zero padding.
+	PayloadSize int `json:"payload_size"`
+}
+
+// DryRunCommit constructs the commitBatch transaction of the next pending batch as ProcessPendingBatches would, and
+// estimates its gas on L1, without sending it nor updating the batch. The call isn't wrapped with the committee
+// approvals, it's estimated as sent by the relay contract. It returns nil if no batch is pending.
+func DryRunCommit(ctx context.Context, client commitDryRunClient, db *gorm.DB, cfg *config.RelayerConfig, chainCfg *params.ChainConfig) (*CommitDryRun, error) {
+	r := &Layer2Relayer{
+		ctx:         ctx,
+		batchOrm:    orm.NewBatch(db),
+		chunkOrm:    orm.NewChunk(db),
+		l2BlockOrm:  orm.NewL2Block(db),
+		l1RollupABI: bridgeAbi.ScrollChainABI,
+		cfg:         cfg,
+		chainCfg:    chainCfg,
+	}
+
+	dbBatches, err := r.batchOrm.GetFailedAndPendingBatches(ctx, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch pending L2 batches: %w", err)
+	}
+	if len(dbBatches) == 0 {
+		return nil, nil
+	}
+	dbBatch := dbBatches[0]
+
+	payload, err := r.constructCommitBatchPayload(dbBatch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct commitBatch payload, index: %v, err: %w", dbBatch.Index, err)
+	}
+
+	dryRun := &CommitDryRun{
+		BatchIndex:         dbBatch.Index,
+		BatchHash:          dbBatch.Hash,
+		RollupStatus:       types.RollupStatus(dbBatch.RollupStatus).String(),
+		CodecVersion:       payload.codecVersion,
+		ParentBatchIndex:   payload.dbParentBatch.Index,
+		ParentBatchHash:    payload.dbParentBatch.Hash,
+		ParentRollupStatus: types.RollupStatus(payload.dbParentBatch.RollupStatus).String(),
+		ParentLinked:       dbBatch.ParentBatchHash == payload.dbParentBatch.Hash,
+		From:               crypto.PubkeyToAddress(cfg.CommitSenderPrivateKey.PublicKey),
+		To:                 cfg.RollupContractAddress,
+		Calldata:           payload.calldata,
+	}
+	if cfg.MultisigConfig != nil {
+		dryRun.From = cfg.MultisigConfig.Address
+	} else if cfg.CommitteeConfig != nil {
+		dryRun.From = cfg.CommitteeConfig.RelayContractAddress
+	}
+	if payload.blob != nil {
+		dryRun.Blobs = append(dryRun.Blobs, &CommitDryRunBlob{
+			VersionedHash: payload.blobVersionedHash,
+			PayloadSize:   len(bytes.TrimRight(payload.blob[:], "\x00")),
+		})
+	}
+
+	// with a DA backend, the blobs aren't attached nor posted, the call carries their versioned hashes as it would once
+	// they are.
+	if cfg.DABackendConfig != nil && payload.blob != nil {
+		daCommit, err := newDABackendCommit(nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		if payload.calldata, err = daCommit.commitCalldata(r.l1RollupABI, payload.calldata, []common.Hash{payload.blobVersionedHash}); err != nil {
+			return nil, fmt.Errorf("failed to construct DA commit payload, index: %v, err: %w", dbBatch.Index, err)
+		}
+		payload.blob = nil
+		dryRun.Calldata = payload.calldata
+	}
+
+	// a failed estimation is reported along with the transaction, e.g. to inspect a reverting commit.
+	estimatedGas, err := estimateDryRunGas(ctx, client, dryRun.From, dryRun.To, payload)
+	if err != nil {
+		dryRun.GasEstimationError = err.Error()
+	} else {
+		dryRun.EstimatedGas = estimatedGas
+	}
+	return dryRun, nil
+}
+
+// estimateDryRunGas estimates the gas of the commitBatch call, with a blob gas fee cap covering the current blob base
+// fee if it carries blobs.
+func estimateDryRunGas(ctx context.Context, client commitDryRunClient, from, to common.Address, payload *commitBatchPayload) (uint64, error) {
+	msg := ethereum.CallMsg{From: from, To: &to, Data: payload.calldata}
+	if payload.blob != nil {
+		header, err := client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get the latest L1 header: %w", err)
+		}
+		blobBaseFee := big.NewInt(1)
+		if header.ExcessBlobGas != nil && header.BlobGasUsed != nil {
+			blobBaseFee = misc.CalcBlobFee(misc.CalcExcessBlobGas(*header.ExcessBlobGas, *header.BlobGasUsed))
+		}
+		msg.BlobHashes = []common.Hash{payload.blobVersionedHash}
+		msg.BlobGasFeeCap = new(big.Int).Mul(blobBaseFee, big.NewInt(2))
+	}
+	return client.EstimateGas(ctx, msg)
+}
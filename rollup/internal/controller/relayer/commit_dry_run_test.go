@@ -0,0 +1,88 @@
+package relayer
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/database"
+	"scroll-tech/common/types"
+	"scroll-tech/common/types/encoding"
+
+	"scroll-tech/rollup/internal/orm"
+)
+
+type mockCommitDryRunClient struct {
+	msg ethereum.CallMsg
+}
+
+func (c *mockCommitDryRunClient) HeaderByNumber(context.Context, *big.Int) (*gethTypes.Header, error) {
+	return &gethTypes.Header{Number: big.NewInt(1)}, nil
+}
+
+func (c *mockCommitDryRunClient) EstimateGas(_ context.Context, msg ethereum.CallMsg) (uint64, error) {
+	c.msg = msg
+	return 123456, nil
+}
+
+func testL2RelayerCommitDryRun(t *testing.T) {
+	db := setupL2RelayerDB(t)
+	defer database.CloseDB(db)
+
+	relayerCfg := cfg.L2Config.RelayerConfig
+	relayer, err := NewLayer2Relayer(context.Background(), l2Cli, db, relayerCfg, &params.ChainConfig{}, true, ServiceTypeL2RollupRelayer, nil)
+	assert.NoError(t, err)
+	defer relayer.StopSenders()
+
+	client := &mockCommitDryRunClient{}
+	dryRun, err := DryRunCommit(context.Background(), client, db, relayerCfg, &params.ChainConfig{})
+	assert.NoError(t, err)
+	assert.Nil(t, dryRun)
+
+	l2BlockOrm := orm.NewL2Block(db)
+	assert.NoError(t, l2BlockOrm.InsertL2Blocks(context.Background(), []*encoding.Block{block1, block2}))
+	chunkOrm := orm.NewChunk(db)
+	_, err = chunkOrm.InsertChunk(context.Background(), chunk1, encoding.CodecV0)
+	assert.NoError(t, err)
+	_, err = chunkOrm.InsertChunk(context.Background(), chunk2, encoding.CodecV0)
+	assert.NoError(t, err)
+
+	batchOrm := orm.NewBatch(db)
+	genesisBatch, err := batchOrm.GetBatchByIndex(context.Background(), 0)
+	assert.NoError(t, err)
+	batch := &encoding.Batch{
+		Index:                      1,
+		TotalL1MessagePoppedBefore: 0,
+		ParentBatchHash:            common.HexToHash(genesisBatch.Hash),
+		Chunks:                     []*encoding.Chunk{chunk1, chunk2},
+	}
+	dbBatch, err := batchOrm.InsertBatch(context.Background(), batch, encoding.CodecV0)
+	assert.NoError(t, err)
+
+	dryRun, err = DryRunCommit(context.Background(), client, db, relayerCfg, &params.ChainConfig{})
+	assert.NoError(t, err)
+	assert.Equal(t, dbBatch.Hash, dryRun.BatchHash)
+	assert.Equal(t, genesisBatch.Hash, dryRun.ParentBatchHash)
+	assert.True(t, dryRun.ParentLinked)
+	assert.Equal(t, encoding.CodecV0, dryRun.CodecVersion)
+	assert.Empty(t, dryRun.Blobs)
+	assert.Equal(t, uint64(123456), dryRun.EstimatedGas)
+	assert.Equal(t, crypto.PubkeyToAddress(relayerCfg.CommitSenderPrivateKey.PublicKey), client.msg.From)
+	assert.Equal(t, relayerCfg.RollupContractAddress, *client.msg.To)
+
+	method, err := relayer.l1RollupABI.MethodById(dryRun.Calldata)
+	assert.NoError(t, err)
+	assert.Equal(t, "commitBatch", method.Name)
+
+	// the dry run leaves the batch pending.
+	statuses, err := batchOrm.GetRollupStatusByHashList(context.Background(), []string{dbBatch.Hash})
+	assert.NoError(t, err)
+	assert.Equal(t, []types.RollupStatus{types.RollupPending}, statuses)
+}
@@ -0,0 +1,138 @@
+package relayer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/controller/sender"
+)
+
+// defaultRebatchRevertErrors are the rollup contract errors reverting a commitBatch because of how its chunks are
+// grouped into a batch, which re-batching the same chunks can fix.
+var defaultRebatchRevertErrors = []string{
+	"ErrorBatchIsEmpty",
+	"ErrorIncorrectBitmapLength",
+	"ErrorLastL1MessageSkipped",
+}
+
+// chunkContentRevertErrors are the rollup contract errors reverting a commitBatch because of the content of one of its
+// chunks. Re-batching re-proposes the same chunks, so these are left to a manual fix.
+var chunkContentRevertErrors = []string{
+	"ErrorNoBlockInChunk",
+	"ErrorIncorrectChunkLength",
+	"ErrorNumTxsLessThanNumL1Msgs",
+	"ErrorTooManyTxsInOneChunk",
+	"ErrorIncompleteL2TransactionData",
+}
+
+// rebatcher decides whether a batch whose commit transaction failed is dissolved, so that its chunks are re-proposed.
+type rebatcher struct {
+	maxRebatches uint64
+	// revertErrors maps the selectors of the errors dissolving a batch to their names.
+	revertErrors map[[4]byte]string
+}
+
+func newRebatcher(cfg *config.AutoRebatchConfig) *rebatcher {
+	names := cfg.RevertErrors
+	if len(names) == 0 {
+		names = defaultRebatchRevertErrors
+	}
+
+	b := &rebatcher{
+		maxRebatches: cfg.MaxRebatches,
+		revertErrors: make(map[[4]byte]string, len(names)),
+	}
+	if b.maxRebatches == 0 {
+		b.maxRebatches = 1
+	}
+	for _, name := range names {
+		var selector [4]byte
+		copy(selector[:], crypto.Keccak256([]byte(name + "()"))[:4])
+		b.revertErrors[selector] = name
+	}
+	return b
+}
+
+// revertError returns the name of the error dissolving a batch matching the revert data, empty if none matches.
+func (b *rebatcher) revertError(revertData []byte) string {
+	if len(revertData) < 4 {
+		return ""
+	}
+	var selector [4]byte
+	copy(selector[:], revertData[:4])
+	return b.revertErrors[selector]
+}
+
+// dissolveFailedBatch dissolves the batch of the failed commit transaction if it reverted with a known error.
+// It returns true if the batch is dissolved.
+func (r *Layer2Relayer) dissolveFailedBatch(cfm *sender.Confirmation) bool {
+	if r.rebatcher == nil {
+		return false
+	}
+	revertError := r.rebatcher.revertError(cfm.RevertData)
+	if revertError == "" {
+		return false
+	}
+
+	dissolved, err := r.dissolveBatch(cfm.ContextID)
+	if err != nil {
+		log.Error("failed to dissolve batch with failed commit", "hash", cfm.ContextID, "revert error", revertError, "err", err)
+		return false
+	}
+	if dissolved {
+		r.metrics.rollupL2BatchesDissolvedTotal.Inc()
+		log.Warn("dissolved batch with failed commit, its chunks are re-proposed", "hash", cfm.ContextID, "tx hash", cfm.TxHash.String(), "revert error", revertError)
+	}
+	return dissolved
+}
+
+// dissolveBatch deletes the batch along with the batches after it and releases their chunks to the batch proposer.
+// The batches after it can't be committed before it, so their pending commits fail anyway.
+// It returns false if the batch has already been dissolved maxRebatches times.
+func (r *Layer2Relayer) dissolveBatch(batchHash string) (bool, error) {
+	dbBatch, err := r.batchOrm.GetBatchByHash(r.ctx, batchHash)
+	if err != nil {
+		return false, err
+	}
+	if dbBatch.Index == 0 {
+		return false, errors.New("the genesis batch can't be dissolved")
+	}
+
+	numDissolved, err := r.batchOrm.GetDeletedBatchCountByHash(r.ctx, batchHash)
+	if err != nil {
+		return false, err
+	}
+	if numDissolved >= r.rebatcher.maxRebatches {
+		log.Warn("batch dissolved too many times, retrying its commit", "hash", batchHash, "index", dbBatch.Index, "dissolved", numDissolved)
+		return false, nil
+	}
+
+	committed, err := r.batchOrm.GetBatches(r.ctx, map[string]interface{}{
+		"index >= ?":             dbBatch.Index,
+		"rollup_status NOT IN ?": []types.RollupStatus{types.RollupPending, types.RollupCommitting, types.RollupCommitFailed},
+	}, nil, 1)
+	if err != nil {
+		return false, err
+	}
+	if len(committed) > 0 {
+		return false, fmt.Errorf("batch %d has rollup status %s, only uncommitted batches can be dissolved", committed[0].Index, types.RollupStatus(committed[0].RollupStatus))
+	}
+
+	err = r.db.Transaction(func(dbTX *gorm.DB) error {
+		if err := r.batchOrm.DeleteBatchesGtIndex(r.ctx, dbBatch.Index-1, dbTX); err != nil {
+			return err
+		}
+		return r.chunkOrm.ResetBatchHashGtIndex(r.ctx, dbBatch.StartChunkIndex-1, dbTX)
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
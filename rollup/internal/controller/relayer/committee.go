@@ -0,0 +1,203 @@
+package relayer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/scroll-tech/go-ethereum/accounts/abi"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+const (
+	defaultCommitteeApprovalTimeoutSec   = 30
+	defaultCommitteeSignatureValiditySec = 3600
+)
+
+// committeeRelayABI holds the entrypoint of the relay contract executing a call approved by the committee.
+const committeeRelayABI = `[
+	{"type":"function","name":"execute","stateMutability":"nonpayable","inputs":[{"name":"target","type":"address"},{"name":"data","type":"bytes"},{"name":"deadline","type":"uint256"},{"name":"signatures","type":"bytes[]"}],"outputs":[]}
+]`
+
+// Kinds of the calls approved by the committee.
+const (
+	committeeCallKindCommit   = "commit_batch"
+	committeeCallKindFinalize = "finalize_batch"
+)
+
+// committeeApprovalRequest is the request sent to a committee member to approve a call.
+type committeeApprovalRequest struct {
+	Kind      string         `json:"kind"`
+	ContextID string         `json:"context_id"`
+	Target    common.Address `json:"target"`
+	Calldata  hexutil.Bytes  `json:"calldata"`
+	ChainID   *hexutil.Big   `json:"chain_id"`
+	Deadline  uint64         `json:"deadline"`
+	Digest    common.Hash    `json:"digest"`
+}
+
+// committeeApprovalResponse is the signature of the digest returned by a committee member.
+type committeeApprovalResponse struct {
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// committee collects the approvals of the committee members for the commit and finalize calls and
+// wraps the approved calls into the relay contract, so that no single key is able to submit batches.
+type committee struct {
+	chainID           *big.Int
+	relayAddress      common.Address
+	threshold         uint64
+	members           []*config.CommitteeMemberConfig
+	relayABI          abi.ABI
+	client            *resty.Client
+	approvalTimeout   time.Duration
+	signatureValidity time.Duration
+}
+
+// newCommittee creates a committee signing the calls relayed on the L1 chain with the given chain id.
+func newCommittee(cfg *config.CommitteeConfig, chainID *big.Int) (*committee, error) {
+	parsed, err := abi.JSON(strings.NewReader(committeeRelayABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse committee relay abi: %w", err)
+	}
+
+	timeout := time.Duration(cfg.ApprovalTimeoutSec) * time.Second
+	if timeout == 0 {
+		timeout = defaultCommitteeApprovalTimeoutSec * time.Second
+	}
+	validity := time.Duration(cfg.SignatureValiditySec) * time.Second
+	if validity == 0 {
+		validity = defaultCommitteeSignatureValiditySec * time.Second
+	}
+
+	return &committee{
+		chainID:           chainID,
+		relayAddress:      cfg.RelayContractAddress,
+		threshold:         cfg.Threshold,
+		members:           cfg.Members,
+		relayABI:          parsed,
+		client:            resty.New(),
+		approvalTimeout:   timeout,
+		signatureValidity: validity,
+	}, nil
+}
+
+// digest returns the hash signed by the committee members, binding the call to the chain, the relay contract and
+// its target. The deadline bounds the time the signatures can be replayed, the relay contract rejects them after it.
+func (c *committee) digest(target common.Address, calldata []byte, deadline uint64) common.Hash {
+	return crypto.Keccak256Hash(
+		common.BigToHash(c.chainID).Bytes(),
+		c.relayAddress.Bytes(),
+		target.Bytes(),
+		crypto.Keccak256(calldata),
+		common.BigToHash(new(big.Int).SetUint64(deadline)).Bytes(),
+	)
+}
+
+// wrap collects the approvals of the call and returns the relay contract address and the calldata executing the call.
+// A resubmission of the relay call reverts once the deadline has passed, and the call is approved again when retried.
+func (c *committee) wrap(ctx context.Context, kind string, contextID string, target common.Address, calldata []byte) (*common.Address, []byte, error) {
+	deadline := uint64(time.Now().Add(c.signatureValidity).Unix())
+	signatures, err := c.collectApprovals(ctx, kind, contextID, target, calldata, deadline)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	relayCalldata, err := c.relayABI.Pack("execute", target, calldata, new(big.Int).SetUint64(deadline), signatures)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to pack committee relay execute: %w", err)
+	}
+	return &c.relayAddress, relayCalldata, nil
+}
+
+// collectApprovals requests the approval of all members concurrently, under a deadline shared by the requests, and
+// returns once the threshold is reached. The signatures are sorted by the signer address, as expected by the relay contract.
+func (c *committee) collectApprovals(ctx context.Context, kind string, contextID string, target common.Address, calldata []byte, deadline uint64) ([][]byte, error) {
+	req := &committeeApprovalRequest{
+		Kind:      kind,
+		ContextID: contextID,
+		Target:    target,
+		Calldata:  calldata,
+		ChainID:   (*hexutil.Big)(c.chainID),
+		Deadline:  deadline,
+		Digest:    c.digest(target, calldata, deadline),
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.approvalTimeout)
+	defer cancel()
+
+	type approval struct {
+		signer    common.Address
+		signature []byte
+		err       error
+	}
+	// buffered so that the requests still running after the threshold is reached don't block.
+	results := make(chan approval, len(c.members))
+	for _, member := range c.members {
+		go func(member *config.CommitteeMemberConfig) {
+			signature, err := c.requestApproval(ctx, member, req)
+			results <- approval{signer: member.Address, signature: signature, err: err}
+		}(member)
+	}
+
+	var approvals []approval
+	for range c.members {
+		result := <-results
+		if result.err != nil {
+			log.Warn("failed to get committee member approval", "member", result.signer, "kind", kind, "context id", contextID, "err", result.err)
+			continue
+		}
+		approvals = append(approvals, result)
+		if uint64(len(approvals)) >= c.threshold {
+			break
+		}
+	}
+
+	if uint64(len(approvals)) < c.threshold {
+		return nil, fmt.Errorf("not enough committee approvals, kind: %s, context id: %s, approvals: %d, threshold: %d", kind, contextID, len(approvals), c.threshold)
+	}
+
+	sort.Slice(approvals, func(i, j int) bool {
+		return bytes.Compare(approvals[i].signer.Bytes(), approvals[j].signer.Bytes()) < 0
+	})
+	signatures := make([][]byte, len(approvals))
+	for i, a := range approvals {
+		signatures[i] = a.signature
+	}
+	return signatures, nil
+}
+
+// requestApproval requests the signature of the digest from a member and checks it's signed by the member.
+func (c *committee) requestApproval(ctx context.Context, member *config.CommitteeMemberConfig, req *committeeApprovalRequest) ([]byte, error) {
+	var result committeeApprovalResponse
+	resp, err := c.client.R().
+		SetContext(ctx).
+		SetBody(req).
+		SetResult(&result).
+		Post(member.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("committee member returned status %d", resp.StatusCode())
+	}
+
+	pubKey, err := crypto.SigToPub(req.Digest.Bytes(), result.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature: %w", err)
+	}
+	if signer := crypto.PubkeyToAddress(*pubKey); signer != member.Address {
+		return nil, fmt.Errorf("signature signed by %s instead of the member", signer.Hex())
+	}
+	return result.Signature, nil
+}
@@ -0,0 +1,110 @@
+package relayer
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+func mockCommitteeMemberServer(t *testing.T, key *ecdsa.PrivateKey) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req committeeApprovalRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		signature, err := crypto.Sign(req.Digest.Bytes(), key)
+		assert.NoError(t, err)
+		w.Header().Set("Content-Type", "application/json")
+		assert.NoError(t, json.NewEncoder(w).Encode(&committeeApprovalResponse{Signature: signature}))
+	}))
+}
+
+func testCommitteeWrap(t *testing.T) {
+	var members []*config.CommitteeMemberConfig
+	for i := 0; i < 3; i++ {
+		key, err := crypto.GenerateKey()
+		assert.NoError(t, err)
+		signingKey := key
+		if i == 0 {
+			// the first member signs with a key other than its registered one.
+			signingKey, err = crypto.GenerateKey()
+			assert.NoError(t, err)
+		}
+		srv := mockCommitteeMemberServer(t, signingKey)
+		defer srv.Close()
+		members = append(members, &config.CommitteeMemberConfig{Address: crypto.PubkeyToAddress(key.PublicKey), Endpoint: srv.URL})
+	}
+	// a member never answering doesn't hold the approvals once the threshold is reached.
+	hangingSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the request body is read so that the server notices the request being canceled.
+		_, err := io.Copy(io.Discard, r.Body)
+		assert.NoError(t, err)
+		<-r.Context().Done()
+	}))
+	defer hangingSrv.Close()
+	members = append(members, &config.CommitteeMemberConfig{Address: common.HexToAddress("0x9999"), Endpoint: hangingSrv.URL})
+
+	committeeCfg := &config.CommitteeConfig{
+		RelayContractAddress: common.HexToAddress("0x1234"),
+		Threshold:            2,
+		Members:              members,
+		ApprovalTimeoutSec:   5,
+	}
+	chainID := big.NewInt(534351)
+	c, err := newCommittee(committeeCfg, chainID)
+	assert.NoError(t, err)
+
+	target := common.HexToAddress("0x5678")
+	calldata := []byte{0x1, 0x2, 0x3}
+	start := time.Now()
+	to, relayCalldata, err := c.wrap(context.Background(), committeeCallKindCommit, "0xabcd", target, calldata)
+	assert.NoError(t, err)
+	assert.Less(t, time.Since(start), time.Duration(committeeCfg.ApprovalTimeoutSec)*time.Second)
+	assert.Equal(t, committeeCfg.RelayContractAddress, *to)
+
+	args, err := c.relayABI.Methods["execute"].Inputs.Unpack(relayCalldata[4:])
+	assert.NoError(t, err)
+	assert.Equal(t, target, args[0].(common.Address))
+	assert.Equal(t, calldata, args[1].([]byte))
+	deadline := args[2].(*big.Int).Uint64()
+	assert.Greater(t, deadline, uint64(time.Now().Unix()))
+	signatures := args[3].([][]byte)
+	assert.Len(t, signatures, 2)
+
+	// the digest is bound to the chain and the deadline.
+	digest := c.digest(target, calldata, deadline)
+	assert.NotEqual(t, digest, c.digest(target, calldata, deadline+1))
+	otherChain, err := newCommittee(committeeCfg, big.NewInt(1))
+	assert.NoError(t, err)
+	assert.NotEqual(t, digest, otherChain.digest(target, calldata, deadline))
+	var signers []common.Address
+	for _, signature := range signatures {
+		pubKey, err := crypto.SigToPub(digest.Bytes(), signature)
+		assert.NoError(t, err)
+		signers = append(signers, crypto.PubkeyToAddress(*pubKey))
+	}
+	assert.ElementsMatch(t, []common.Address{members[1].Address, members[2].Address}, signers)
+	assert.True(t, bytes.Compare(signers[0].Bytes(), signers[1].Bytes()) < 0)
+
+	// not enough valid approvals, the hanging member is given up at the approval timeout.
+	committeeCfg.Threshold = 3
+	committeeCfg.ApprovalTimeoutSec = 1
+	c, err = newCommittee(committeeCfg, chainID)
+	assert.NoError(t, err)
+	_, _, err = c.wrap(context.Background(), committeeCallKindFinalize, "0xabcd", target, calldata)
+	assert.Error(t, err)
+}
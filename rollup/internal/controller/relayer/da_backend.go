@@ -0,0 +1,195 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/scroll-tech/go-ethereum/accounts/abi"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/crypto/kzg4844"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+)
+
+// defaultDAPostTimeout bounds the post of a blob to the DA backend if the configuration sets no timeout.
+const defaultDAPostTimeout = 30 * time.Second
+
+// daCommitABI holds the commit call of the rollup contract of a validium deployment. It carries the versioned hashes
+// of the blobs posted to the DA backend in place of the blobs of the transaction, so the batch headers, the batch
+// hashes and the proofs are the same as in the commits with blobs.
+const daCommitABI = `[
+	{"type":"function","name":"commitBatchWithDACommitment","stateMutability":"nonpayable","inputs":[{"name":"version","type":"uint8"},{"name":"parentBatchHeader","type":"bytes"},{"name":"chunks","type":"bytes[]"},{"name":"skippedL1MessageBitmap","type":"bytes"},{"name":"blobVersionedHashes","type":"bytes32[]"}],"outputs":[]}
+]`
+
+// DABackend is an alternative DA layer the blobs of the batches are posted to instead of L1.
+type DABackend interface {
+	// Name is the name of the backend, as labeled in the metrics.
+	Name() string
+	// PostBlob stores a blob of a batch under its versioned hash and returns where it's stored. A blob is posted
+	// again if its commit is retried, so posting it must be idempotent.
+	PostBlob(ctx context.Context, batchHash common.Hash, versionedHash common.Hash, blob *kzg4844.Blob) (string, error)
+}
+
+// daBackendCommit posts the blobs of the batches to the DA backend and packs their commitments in the commit calls.
+type daBackendCommit struct {
+	backend DABackend
+	timeout time.Duration
+	abi     abi.ABI
+}
+
+func newDABackendCommit(backend DABackend, timeoutSec uint64) (*daBackendCommit, error) {
+	parsed, err := abi.JSON(strings.NewReader(daCommitABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse DA commit abi: %w", err)
+	}
+
+	timeout := defaultDAPostTimeout
+	if timeoutSec > 0 {
+		timeout = time.Duration(timeoutSec) * time.Second
+	}
+	return &daBackendCommit{backend: backend, timeout: timeout, abi: parsed}, nil
+}
+
+// newDABackend creates the DA backend of the configured type.
+func newDABackend(cfg *config.DABackendConfig) (DABackend, error) {
+	switch cfg.Type {
+	case config.DABackendTypeObjectStore:
+		client := resty.New()
+		if cfg.AuthToken != "" {
+			client.SetAuthToken(cfg.AuthToken)
+		}
+		return &objectStoreDABackend{client: client, endpoint: strings.TrimRight(cfg.Endpoint, "/")}, nil
+	case config.DABackendTypeFilesystem:
+		if err := os.MkdirAll(cfg.Directory, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create DA directory %v: %w", cfg.Directory, err)
+		}
+		return &filesystemDABackend{directory: cfg.Directory}, nil
+	default:
+		return nil, fmt.Errorf("unknown DA backend type: %v", cfg.Type)
+	}
+}
+
+// SetDABackend posts the blobs of the batches to the given DA backend instead of attaching them to the commit
+// transactions, replacing the configured one. It's disabled if backend is nil.
+func (r *Layer2Relayer) SetDABackend(backend DABackend) error {
+	if backend == nil {
+		r.daCommit = nil
+		return nil
+	}
+
+	var timeoutSec uint64
+	if r.cfg.DABackendConfig != nil {
+		timeoutSec = r.cfg.DABackendConfig.TimeoutSec
+	}
+	daCommit, err := newDABackendCommit(backend, timeoutSec)
+	if err != nil {
+		return err
+	}
+	r.daCommit = daCommit
+	return nil
+}
+
+// postBlobsToDABackend posts the blob of the commit payload to the DA backend, and replaces the commitBatch call
+// with the call carrying the versioned hash of the blob, which is still recorded with the batch. The payload is
+// left as is if it carries no blob.
+func (r *Layer2Relayer) postBlobsToDABackend(dbBatch *orm.Batch, payload *commitBatchPayload) error {
+	if r.daCommit == nil || payload.blob == nil {
+		return nil
+	}
+	backendName := r.daCommit.backend.Name()
+
+	versionedHash := payload.blobVersionedHash
+	ctx, cancel := context.WithTimeout(r.ctx, r.daCommit.timeout)
+	location, err := r.daCommit.backend.PostBlob(ctx, common.HexToHash(dbBatch.Hash), versionedHash, payload.blob)
+	cancel()
+	if err != nil {
+		r.metrics.rollupL2RelayerDABackendPostFailureTotal.WithLabelValues(backendName).Inc()
+		return fmt.Errorf("failed to post blob %v to the %v DA backend: %w", versionedHash, backendName, err)
+	}
+	r.metrics.rollupL2RelayerDABackendPostTotal.WithLabelValues(backendName).Inc()
+	log.Info("posted blob to the DA backend", "backend", backendName, "batch index", dbBatch.Index, "batch hash", dbBatch.Hash, "versioned hash", versionedHash, "location", location)
+
+	calldata, err := r.daCommit.commitCalldata(r.l1RollupABI, payload.calldata, []common.Hash{versionedHash})
+	if err != nil {
+		return err
+	}
+	payload.calldata, payload.blob = calldata, nil
+	return nil
+}
+
+// commitCalldata repacks the arguments of the commitBatch call in the commit call carrying the blob versioned hashes.
+func (c *daBackendCommit) commitCalldata(rollupABI *abi.ABI, commitBatchCalldata []byte, blobVersionedHashes []common.Hash) ([]byte, error) {
+	if len(commitBatchCalldata) < 4 {
+		return nil, fmt.Errorf("invalid commitBatch calldata length: %v", len(commitBatchCalldata))
+	}
+	args, err := rollupABI.Methods["commitBatch"].Inputs.Unpack(commitBatchCalldata[4:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack commitBatch: %w", err)
+	}
+
+	hashes := make([][32]byte, len(blobVersionedHashes))
+	for i, hash := range blobVersionedHashes {
+		hashes[i] = hash
+	}
+	calldata, err := c.abi.Pack("commitBatchWithDACommitment", append(args, hashes)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack commitBatchWithDACommitment: %w", err)
+	}
+	return calldata, nil
+}
+
+// objectStoreDABackend PUTs the blobs to an HTTP object store, at <endpoint>/<versioned hash>.
+type objectStoreDABackend struct {
+	client   *resty.Client
+	endpoint string
+}
+
+func (b *objectStoreDABackend) Name() string {
+	return config.DABackendTypeObjectStore
+}
+
+func (b *objectStoreDABackend) PostBlob(ctx context.Context, batchHash common.Hash, versionedHash common.Hash, blob *kzg4844.Blob) (string, error) {
+	url := fmt.Sprintf("%s/%s", b.endpoint, versionedHash.Hex())
+	resp, err := b.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/octet-stream").
+		SetHeader("X-Batch-Hash", batchHash.Hex()).
+		SetBody(blob[:]).
+		Put(url)
+	if err != nil {
+		return "", err
+	}
+	if resp.IsError() {
+		return "", fmt.Errorf("object store responded %v", resp.Status())
+	}
+	return url, nil
+}
+
+// filesystemDABackend writes the blobs to a directory, at <directory>/<versioned hash>.blob.
+type filesystemDABackend struct {
+	directory string
+}
+
+func (b *filesystemDABackend) Name() string {
+	return config.DABackendTypeFilesystem
+}
+
+func (b *filesystemDABackend) PostBlob(_ context.Context, _ common.Hash, versionedHash common.Hash, blob *kzg4844.Blob) (string, error) {
+	path := filepath.Join(b.directory, versionedHash.Hex()+".blob")
+	// the blob is renamed into place so that a blob file is never partially written.
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, blob[:], 0o644); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,80 @@
+package relayer
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/crypto/kzg4844"
+	"github.com/stretchr/testify/assert"
+
+	bridgeAbi "scroll-tech/rollup/abi"
+	"scroll-tech/rollup/internal/config"
+)
+
+func testDABackend(t *testing.T) {
+	blob := &kzg4844.Blob{1, 2, 3}
+	versionedHash := common.HexToHash("0x01aa")
+
+	backend, err := newDABackend(&config.DABackendConfig{Type: config.DABackendTypeFilesystem, Directory: t.TempDir()})
+	assert.NoError(t, err)
+	path, err := backend.PostBlob(context.Background(), common.Hash{}, versionedHash, blob)
+	assert.NoError(t, err)
+	stored, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, blob[:], stored)
+
+	var requests []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, blob[:], body)
+		requests = append(requests, r)
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	backend, err = newDABackend(&config.DABackendConfig{Type: config.DABackendTypeObjectStore, Endpoint: server.URL + "/blobs/", AuthToken: "token"})
+	assert.NoError(t, err)
+	url, err := backend.PostBlob(context.Background(), common.HexToHash("0x02"), versionedHash, blob)
+	assert.NoError(t, err)
+	assert.Equal(t, server.URL+"/blobs/"+versionedHash.Hex(), url)
+	assert.Len(t, requests, 1)
+	assert.Equal(t, http.MethodPut, requests[0].Method)
+	assert.Equal(t, common.HexToHash("0x02").Hex(), requests[0].Header.Get("X-Batch-Hash"))
+
+	backend, err = newDABackend(&config.DABackendConfig{Type: config.DABackendTypeObjectStore, Endpoint: server.URL})
+	assert.NoError(t, err)
+	_, err = backend.PostBlob(context.Background(), common.Hash{}, versionedHash, blob)
+	assert.Error(t, err)
+}
+
+func testDABackendCommitCalldata(t *testing.T) {
+	daCommit, err := newDABackendCommit(&filesystemDABackend{}, 0)
+	assert.NoError(t, err)
+
+	chunks := [][]byte{{1}, {2, 3}}
+	commitBatchCalldata, err := bridgeAbi.ScrollChainABI.Pack("commitBatch", uint8(1), []byte{4}, chunks, []byte{5})
+	assert.NoError(t, err)
+	hashes := []common.Hash{common.HexToHash("0x01aa"), common.HexToHash("0x01bb")}
+	calldata, err := daCommit.commitCalldata(bridgeAbi.ScrollChainABI, commitBatchCalldata, hashes)
+	assert.NoError(t, err)
+
+	method := daCommit.abi.Methods["commitBatchWithDACommitment"]
+	assert.Equal(t, method.ID, calldata[:4])
+	args, err := method.Inputs.Unpack(calldata[4:])
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(1), args[0])
+	assert.Equal(t, []byte{4}, args[1])
+	assert.Equal(t, chunks, args[2])
+	assert.Equal(t, []byte{5}, args[3])
+	assert.Equal(t, [][32]byte{hashes[0], hashes[1]}, args[4])
+
+	_, err = daCommit.commitCalldata(bridgeAbi.ScrollChainABI, []byte{1}, hashes)
+	assert.Error(t, err)
+}
@@ -0,0 +1,61 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/rollup/internal/orm"
+)
+
+// finalizeCrossCheckClient is the independent l2geth node the batches are checked against before their finalization.
+type finalizeCrossCheckClient interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*gethTypes.Header, error)
+	StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error)
+}
+
+// SetFinalizeCrossCheck sets the l2geth node the state root and the withdraw root of a batch are checked against
+// before its finalize transaction is sent, along with the location of the withdraw root in the L2MessageQueue
+// contract. It's disabled if client is nil.
+func (r *Layer2Relayer) SetFinalizeCrossCheck(client finalizeCrossCheckClient, messageQueueAddress common.Address, withdrawTrieRootSlot common.Hash) {
+	r.finalizeCrossCheckClient = client
+	r.messageQueueAddress = messageQueueAddress
+	r.withdrawTrieRootSlot = withdrawTrieRootSlot
+}
+
+// crossCheckFinalize compares the state root and the withdraw root claimed by the batch with the ones of its last
+// block on the cross-check node. The batch is not finalized if they mismatch, nor if the cross-check node is
+// unavailable, as the finalization can't be undone; it is retried by the next ProcessCommittedBatches.
+func (r *Layer2Relayer) crossCheckFinalize(dbBatch *orm.Batch, dbChunks []*orm.Chunk) error {
+	if r.finalizeCrossCheckClient == nil {
+		return nil
+	}
+
+	number := new(big.Int).SetUint64(dbChunks[len(dbChunks)-1].EndBlockNumber)
+	header, err := r.finalizeCrossCheckClient.HeaderByNumber(r.ctx, number)
+	if err != nil {
+		return fmt.Errorf("failed to get block %v from the cross-check node, index: %v, err: %w", number, dbBatch.Index, err)
+	}
+	withdrawRoot, err := r.finalizeCrossCheckClient.StorageAt(r.ctx, r.messageQueueAddress, r.withdrawTrieRootSlot, number)
+	if err != nil {
+		return fmt.Errorf("failed to get the withdraw root of block %v from the cross-check node, index: %v, err: %w", number, dbBatch.Index, err)
+	}
+
+	var mismatch error
+	if header.Root != common.HexToHash(dbBatch.StateRoot) {
+		mismatch = fmt.Errorf("state root of batch %v mismatches the cross-check node, batch: %v, cross-check: %v", dbBatch.Index, dbBatch.StateRoot, header.Root)
+	} else if common.BytesToHash(withdrawRoot) != common.HexToHash(dbBatch.WithdrawRoot) {
+		mismatch = fmt.Errorf("withdraw root of batch %v mismatches the cross-check node, batch: %v, cross-check: %v", dbBatch.Index, dbBatch.WithdrawRoot, common.BytesToHash(withdrawRoot))
+	}
+	if mismatch == nil {
+		return nil
+	}
+
+	r.metrics.rollupL2RelayerFinalizeCrossCheckMismatchTotal.Inc()
+	log.Error("refusing to finalize the batch, pre-finalize cross-check failed", "index", dbBatch.Index, "hash", dbBatch.Hash, "block", number, "err", mismatch)
+	return mismatch
+}
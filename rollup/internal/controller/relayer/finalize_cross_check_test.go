@@ -0,0 +1,66 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/rollup/internal/orm"
+)
+
+type mockFinalizeCrossCheckClient struct {
+	header       *gethTypes.Header
+	withdrawRoot common.Hash
+	err          error
+}
+
+func (c *mockFinalizeCrossCheckClient) HeaderByNumber(_ context.Context, number *big.Int) (*gethTypes.Header, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	header := gethTypes.CopyHeader(c.header)
+	header.Number = number
+	return header, nil
+}
+
+func (c *mockFinalizeCrossCheckClient) StorageAt(context.Context, common.Address, common.Hash, *big.Int) ([]byte, error) {
+	return c.withdrawRoot.Bytes(), c.err
+}
+
+func testL2RelayerFinalizeCrossCheck(t *testing.T) {
+	dbBatch := &orm.Batch{
+		Index:        1,
+		StateRoot:    common.HexToHash("0x01").Hex(),
+		WithdrawRoot: common.HexToHash("0x02").Hex(),
+	}
+	dbChunks := []*orm.Chunk{{EndBlockNumber: 5}, {EndBlockNumber: 10}}
+	client := &mockFinalizeCrossCheckClient{
+		header:       &gethTypes.Header{Root: common.HexToHash("0x01")},
+		withdrawRoot: common.HexToHash("0x02"),
+	}
+	r := &Layer2Relayer{ctx: context.Background(), metrics: initL2RelayerMetrics(prometheus.NewRegistry())}
+	assert.NoError(t, r.crossCheckFinalize(dbBatch, dbChunks))
+
+	r.SetFinalizeCrossCheck(client, common.Address{}, common.Hash{})
+	assert.NoError(t, r.crossCheckFinalize(dbBatch, dbChunks))
+
+	// A mismatching state root refuses the finalization.
+	client.header.Root = common.HexToHash("0x03")
+	assert.Error(t, r.crossCheckFinalize(dbBatch, dbChunks))
+
+	// A mismatching withdraw root too.
+	client.header.Root = common.HexToHash("0x01")
+	client.withdrawRoot = common.HexToHash("0x03")
+	assert.Error(t, r.crossCheckFinalize(dbBatch, dbChunks))
+
+	// An unavailable cross-check node holds the finalization.
+	client.withdrawRoot = common.HexToHash("0x02")
+	client.err = errors.New("connection refused")
+	assert.Error(t, r.crossCheckFinalize(dbBatch, dbChunks))
+}
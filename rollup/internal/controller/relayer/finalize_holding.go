@@ -0,0 +1,31 @@
+package relayer
+
+import (
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/rollup/internal/orm"
+)
+
+// isFinalizeHeld returns true if the batch is still in the finalize holding window, i.e. fewer than
+// FinalizeHoldingL1Blocks L1 blocks have been imported after the L1 block including its commit transaction.
+// The batches whose commit L1 block is unknown, e.g. committed before it was tracked, are not held.
+func (r *Layer2Relayer) isFinalizeHeld(batch *orm.Batch) (bool, error) {
+	if r.cfg.FinalizeHoldingL1Blocks == 0 {
+		return false, nil
+	}
+	if batch.CommitL1BlockNumber == 0 {
+		log.Warn("commit l1 block of the batch is unknown, skipping the finalize holding window", "index", batch.Index, "hash", batch.Hash)
+		return false, nil
+	}
+
+	latestL1BlockHeight, err := r.l1BlockOrm.GetLatestL1BlockHeight(r.ctx)
+	if err != nil {
+		return false, err
+	}
+	if latestL1BlockHeight < batch.CommitL1BlockNumber+r.cfg.FinalizeHoldingL1Blocks {
+		log.Debug("batch is in the finalize holding window", "index", batch.Index, "hash", batch.Hash,
+			"commit l1 block", batch.CommitL1BlockNumber, "latest l1 block", latestL1BlockHeight, "holding l1 blocks", r.cfg.FinalizeHoldingL1Blocks)
+		return true, nil
+	}
+	return false, nil
+}
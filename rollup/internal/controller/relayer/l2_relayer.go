@@ -2,9 +2,12 @@ package relayer
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"sort"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-resty/resty/v2"
@@ -29,7 +32,9 @@ import (
 	bridgeAbi "scroll-tech/rollup/abi"
 	"scroll-tech/rollup/internal/config"
 	"scroll-tech/rollup/internal/controller/sender"
+	"scroll-tech/rollup/internal/featureflag"
 	"scroll-tech/rollup/internal/orm"
+	rutils "scroll-tech/rollup/internal/utils"
 )
 
 // Layer2Relayer is responsible for
@@ -47,12 +52,20 @@ type Layer2Relayer struct {
 	batchOrm   *orm.Batch
 	chunkOrm   *orm.Chunk
 	l2BlockOrm *orm.L2Block
+	l1BlockOrm *orm.L1Block
+
+	proposerPauseOrm       *orm.ProposerPause
+	preparedTransactionOrm *orm.PreparedTransaction
+	l1MessageOrm           *orm.L1Message
+	skippedL1MessageOrm    *orm.SkippedL1Message
 
 	cfg *config.RelayerConfig
 
 	commitSender   *sender.Sender
 	finalizeSender *sender.Sender
 	l1RollupABI    *abi.ABI
+	// confirmMu is held while handling a confirmation, so that Drain waits for the one being handled.
+	confirmMu sync.Mutex
 
 	gasOracleSender *sender.Sender
 	l2GasOracleABI  *abi.ABI
@@ -61,9 +74,41 @@ type Layer2Relayer struct {
 	minGasPrice  uint64
 	gasPriceDiff uint64
 
+	// The L2 base fee set on L1 since the last gas price oracle run, 0 if none.
+	onChainL2BaseFee atomic.Uint64
+	// Whether the commit sender was removed from the sequencers of the rollup contract.
+	commitSenderRevoked atomic.Bool
+
 	// Used to get batch status from chain_monitor api.
 	chainMonitorClient *resty.Client
 
+	// Used to collect committee approvals of commit and finalize transactions, nil if disabled.
+	committee *committee
+
+	// Used to prepare the commit and finalize transactions for a multisig instead of sending them, nil if disabled.
+	multisig *multisig
+	// Used to post the blobs of the batches to an alternative DA layer instead of L1, nil if disabled.
+	daCommit *daBackendCommit
+
+	// Used to dissolve the batches whose commit failed with a known error, nil if disabled.
+	rebatcher *rebatcher
+
+	// The last batch revert sent by the operators, nil if none was sent since the relayer started.
+	batchRevert   *BatchRevert
+	batchRevertMu sync.Mutex
+
+	// Used to check the roots of the batches before their finalization, nil if disabled.
+	finalizeCrossCheckClient finalizeCrossCheckClient
+	messageQueueAddress      common.Address
+	withdrawTrieRootSlot     common.Hash
+
+	// Used to check the batches are the next ones expected by the rollup contract before sending them, nil if disabled.
+	sequencingGuardClient sequencingGuardClient
+
+	// Used to pause the commit and finalize pipelines after repeated or aborting failed transactions.
+	commitCircuitBreaker   *circuitBreaker
+	finalizeCircuitBreaker *circuitBreaker
+
 	metrics *l2RelayerMetrics
 
 	chainCfg *params.ChainConfig
@@ -126,6 +171,12 @@ func NewLayer2Relayer(ctx context.Context, l2Client *ethclient.Client, db *gorm.
 		batchOrm:   orm.NewBatch(db),
 		l2BlockOrm: orm.NewL2Block(db),
 		chunkOrm:   orm.NewChunk(db),
+		l1BlockOrm: orm.NewL1Block(db),
+
+		proposerPauseOrm:       orm.NewProposerPause(db),
+		preparedTransactionOrm: orm.NewPreparedTransaction(db),
+		l1MessageOrm:           orm.NewL1Message(db),
+		skippedL1MessageOrm:    orm.NewSkippedL1Message(db),
 
 		l2Client: l2Client,
 
@@ -150,6 +201,37 @@ func NewLayer2Relayer(ctx context.Context, l2Client *ethclient.Client, db *gorm.
 		layer2Relayer.chainMonitorClient.SetTimeout(time.Duration(cfg.ChainMonitor.TimeOut) * time.Second)
 	}
 
+	if cfg.CommitteeConfig != nil && commitSender != nil {
+		layer2Relayer.committee, err = newCommittee(cfg.CommitteeConfig, commitSender.GetChainID())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create committee, err: %w", err)
+		}
+	}
+
+	if cfg.MultisigConfig != nil {
+		layer2Relayer.multisig, err = newMultisig(cfg.MultisigConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create multisig, err: %w", err)
+		}
+	}
+
+	if cfg.DABackendConfig != nil {
+		backend, err := newDABackend(cfg.DABackendConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create DA backend, err: %w", err)
+		}
+		if err = layer2Relayer.SetDABackend(backend); err != nil {
+			return nil, fmt.Errorf("failed to set DA backend, err: %w", err)
+		}
+	}
+
+	if cfg.AutoRebatchConfig != nil {
+		layer2Relayer.rebatcher = newRebatcher(cfg.AutoRebatchConfig)
+	}
+
+	layer2Relayer.commitCircuitBreaker = newCircuitBreaker(CommitCircuitBreakerName, cfg.CircuitBreakerConfig)
+	layer2Relayer.finalizeCircuitBreaker = newCircuitBreaker(FinalizeCircuitBreakerName, cfg.CircuitBreakerConfig)
+
 	// Initialize genesis before we do anything else
 	if initGenesis {
 		if err := layer2Relayer.initializeGenesis(); err != nil {
@@ -290,6 +372,10 @@ func (r *Layer2Relayer) commitGenesisBatch(batchHash string, batchHeader []byte,
 // ProcessGasPriceOracle imports gas price to layer1
 func (r *Layer2Relayer) ProcessGasPriceOracle() {
 	r.metrics.rollupL2RelayerGasPriceOraclerRunTotal.Inc()
+	if l2BaseFee := r.onChainL2BaseFee.Swap(0); l2BaseFee != 0 {
+		r.lastGasPrice = l2BaseFee
+		r.metrics.rollupL2RelayerLastGasPrice.Set(float64(r.lastGasPrice))
+	}
 	batch, err := r.batchOrm.GetLatestBatch(r.ctx)
 	if err != nil {
 		log.Error("Failed to GetLatestBatch", "err", err)
@@ -336,8 +422,32 @@ func (r *Layer2Relayer) ProcessGasPriceOracle() {
 
 // ProcessPendingBatches processes the pending batches by sending commitBatch transactions to layer 1.
 func (r *Layer2Relayer) ProcessPendingBatches() {
+	if r.commitSenderRevoked.Load() {
+		log.Warn("commit sender is not a sequencer of the rollup contract, skipping the pending batches")
+		return
+	}
+	if r.isCircuitOpen(r.commitCircuitBreaker) || r.isHeldForBatchRevert() {
+		return
+	}
+
+	limit := uint64(5)
+	if maxInFlight := r.cfg.MaxInFlightCommits; maxInFlight > 0 {
+		inFlight, err := r.batchOrm.GetCommittingBatchCount(r.ctx)
+		if err != nil {
+			log.Error("Failed to count the committing L2 batches", "err", err)
+			return
+		}
+		if inFlight >= maxInFlight {
+			log.Debug("Too many commitBatch txs in flight, skipping the pending batches", "inFlight", inFlight, "maxInFlight", maxInFlight)
+			return
+		}
+		if maxInFlight-inFlight < limit {
+			limit = maxInFlight - inFlight
+		}
+	}
+
 	// get pending batches from database in ascending order by their index.
-	dbBatches, err := r.batchOrm.GetFailedAndPendingBatches(r.ctx, 5)
+	dbBatches, err := r.batchOrm.GetFailedAndPendingBatches(r.ctx, int(limit))
 	if err != nil {
 		log.Error("Failed to fetch pending L2 batches", "err", err)
 		return
@@ -345,48 +455,35 @@ func (r *Layer2Relayer) ProcessPendingBatches() {
 	for _, dbBatch := range dbBatches {
 		r.metrics.rollupL2RelayerProcessPendingBatchTotal.Inc()
 
-		dbChunks, err := r.chunkOrm.GetChunksInRange(r.ctx, dbBatch.StartChunkIndex, dbBatch.EndChunkIndex)
-		if err != nil {
-			log.Error("failed to get chunks in range", "err", err)
+		if left, err := r.isCommitLeftToOtherOperators(dbBatch); err != nil {
+			log.Error("failed to check the commit of the batch by the other operators", "index", dbBatch.Index, "hash", dbBatch.Hash, "err", err)
+			return
+		} else if left {
 			return
 		}
 
-		chunks := make([]*encoding.Chunk, len(dbChunks))
-		for i, c := range dbChunks {
-			blocks, getErr := r.l2BlockOrm.GetL2BlocksInRange(r.ctx, c.StartBlockNumber, c.EndBlockNumber)
-			if getErr != nil {
-				log.Error("failed to get blocks in range", "err", getErr)
-				return
-			}
-			chunks[i] = &encoding.Chunk{Blocks: blocks}
+		payload, err := r.constructCommitBatchPayload(dbBatch)
+		if err != nil {
+			log.Error("failed to construct commitBatch payload", "index", dbBatch.Index, "hash", dbBatch.Hash, "err", err)
+			return
 		}
 
-		if dbBatch.Index == 0 {
-			log.Error("invalid args: batch index is 0, should only happen in committing genesis batch")
+		if err = r.checkCommitSequencing(dbBatch, payload.dbParentBatch); err != nil {
 			return
 		}
 
-		dbParentBatch, getErr := r.batchOrm.GetBatchByIndex(r.ctx, dbBatch.Index-1)
-		if getErr != nil {
-			log.Error("failed to get parent batch header", "err", getErr)
+		// the blobs can only be committed through a multisig once they're posted to a DA backend.
+		if r.multisig != nil && r.daCommit == nil && payload.blob != nil {
+			log.Error("blob batches can't be committed through a multisig, skipping the pending batches", "index", dbBatch.Index, "hash", dbBatch.Hash)
 			return
 		}
 
-		var calldata []byte
-		var blob *kzg4844.Blob
-		if !r.chainCfg.IsBernoulli(new(big.Int).SetUint64(dbChunks[0].StartBlockNumber)) { // codecv0
-			calldata, err = r.constructCommitBatchPayloadCodecV0(dbBatch, dbParentBatch, dbChunks, chunks)
-			if err != nil {
-				log.Error("failed to construct commitBatch payload codecv0", "index", dbBatch.Index, "err", err)
-				return
-			}
-		} else { // codecv1
-			calldata, blob, err = r.constructCommitBatchPayloadCodecV1(dbBatch, dbParentBatch, dbChunks, chunks)
-			if err != nil {
-				log.Error("failed to construct commitBatch payload codecv1", "index", dbBatch.Index, "err", err)
-				return
-			}
+		// the blobs are posted to the DA backend before the commit is sent, and again if it's retried.
+		if err = r.postBlobsToDABackend(dbBatch, payload); err != nil {
+			log.Error("failed to post the batch blobs to the DA backend", "index", dbBatch.Index, "hash", dbBatch.Hash, "err", err)
+			return
 		}
+		calldata, blob := payload.calldata, payload.blob
 
 		// fallbackGasLimit is non-zero only in sending non-blob transactions.
 		fallbackGasLimit := uint64(float64(dbBatch.TotalL1CommitGas) * r.cfg.L1CommitGasLimitMultiplier)
@@ -396,13 +493,34 @@ func (r *Layer2Relayer) ProcessPendingBatches() {
 			log.Warn("Batch commit previously failed, using eth_estimateGas for the re-submission", "hash", dbBatch.Hash)
 		}
 
-		txHash, err := r.commitSender.SendTransaction(dbBatch.Hash, &r.cfg.RollupContractAddress, calldata, blob, fallbackGasLimit)
+		// the skipped messages are recorded before the commit is sent, and again if it's retried.
+		r.recordSkippedL1Messages(dbBatch, payload.dbChunks, payload.chunks)
+
+		to, calldata, err := r.rollupContractCall(committeeCallKindCommit, dbBatch.Hash, calldata)
+		if err != nil {
+			log.Error("failed to get committee approval of commitBatch", "index", dbBatch.Index, "hash", dbBatch.Hash, "err", err)
+			return
+		}
+
+		if r.multisig != nil {
+			if err = r.prepareMultisigTransaction(committeeCallKindCommit, dbBatch, *to, calldata); err != nil {
+				log.Error("failed to prepare the commitBatch multisig transaction", "index", dbBatch.Index, "hash", dbBatch.Hash, "err", err)
+				return
+			}
+			r.metrics.rollupL2RelayerProcessPendingBatchSuccessTotal.Inc()
+			continue
+		}
+
+		txHash, err := r.commitSender.SendTransaction(dbBatch.Hash, to, calldata, blob, fallbackGasLimit)
 		if err != nil {
+			revertReason, revertClass := estimationRevert(err)
 			log.Error(
 				"Failed to send commitBatch tx to layer1",
 				"index", dbBatch.Index,
 				"hash", dbBatch.Hash,
 				"RollupContractAddress", r.cfg.RollupContractAddress,
+				"revert reason", revertReason,
+				"revert class", revertClass,
 				"err", err,
 			)
 			log.Debug(
@@ -416,6 +534,13 @@ func (r *Layer2Relayer) ProcessPendingBatches() {
 			return
 		}
 
+		// the blob versioned hash is recorded for the later verification of the committed blob.
+		if payload.blobVersionedHash != (common.Hash{}) {
+			if err = r.batchOrm.UpdateBlobVersionedHashes(r.ctx, dbBatch.Hash, []common.Hash{payload.blobVersionedHash}); err != nil {
+				log.Error("UpdateBlobVersionedHashes failed", "hash", dbBatch.Hash, "index", dbBatch.Index, "err", err)
+			}
+		}
+
 		err = r.batchOrm.UpdateCommitTxHashAndRollupStatus(r.ctx, dbBatch.Hash, txHash.String(), types.RollupCommitting)
 		if err != nil {
 			log.Error("UpdateCommitTxHashAndRollupStatus failed", "hash", dbBatch.Hash, "index", dbBatch.Index, "err", err)
@@ -426,8 +551,69 @@ func (r *Layer2Relayer) ProcessPendingBatches() {
 	}
 }
 
+// commitBatchPayload is the commitBatch call of a batch, along with the blob it carries.
+type commitBatchPayload struct {
+	codecVersion      encoding.CodecVersion
+	dbParentBatch     *orm.Batch
+	dbChunks          []*orm.Chunk
+	chunks            []*encoding.Chunk
+	calldata          []byte
+	blob              *kzg4844.Blob
+	blobVersionedHash common.Hash
+}
+
+// constructCommitBatchPayload loads the chunks, the blocks and the parent of the batch, and encodes its commitBatch
+// call with the codec of the batch.
+func (r *Layer2Relayer) constructCommitBatchPayload(dbBatch *orm.Batch) (*commitBatchPayload, error) {
+	if dbBatch.Index == 0 {
+		return nil, errors.New("invalid args: batch index is 0, should only happen in committing genesis batch")
+	}
+
+	dbChunks, err := r.chunkOrm.GetChunksInRange(r.ctx, dbBatch.StartChunkIndex, dbBatch.EndChunkIndex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chunks in range: %w", err)
+	}
+
+	chunks := make([]*encoding.Chunk, len(dbChunks))
+	for i, c := range dbChunks {
+		blocks, getErr := r.l2BlockOrm.GetL2BlocksInRange(r.ctx, c.StartBlockNumber, c.EndBlockNumber)
+		if getErr != nil {
+			return nil, fmt.Errorf("failed to get blocks in range: %w", getErr)
+		}
+		chunks[i] = &encoding.Chunk{Blocks: blocks}
+	}
+
+	dbParentBatch, err := r.batchOrm.GetBatchByIndex(r.ctx, dbBatch.Index-1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get parent batch header: %w", err)
+	}
+
+	payload := &commitBatchPayload{
+		codecVersion:  rutils.GetBatchCodecVersion(r.chainCfg, dbBatch.Index, dbChunks[0].StartBlockNumber),
+		dbParentBatch: dbParentBatch,
+		dbChunks:      dbChunks,
+		chunks:        chunks,
+	}
+	if payload.codecVersion == encoding.CodecV0 {
+		payload.calldata, err = r.constructCommitBatchPayloadCodecV0(dbBatch, dbParentBatch, dbChunks, chunks)
+		if err != nil {
+			return nil, fmt.Errorf("failed to construct commitBatch payload codecv0: %w", err)
+		}
+	} else { // codecv1
+		payload.calldata, payload.blob, payload.blobVersionedHash, err = r.constructCommitBatchPayloadCodecV1(dbBatch, dbParentBatch, dbChunks, chunks)
+		if err != nil {
+			return nil, fmt.Errorf("failed to construct commitBatch payload codecv1: %w", err)
+		}
+	}
+	return payload, nil
+}
+
 // ProcessCommittedBatches submit proof to layer 1 rollup contract
 func (r *Layer2Relayer) ProcessCommittedBatches() {
+	if r.isCircuitOpen(r.finalizeCircuitBreaker) || r.isHeldForBatchRevert() {
+		return
+	}
+
 	// retrieves the earliest batch whose rollup status is 'committed'
 	fields := map[string]interface{}{
 		"rollup_status": types.RollupCommitted,
@@ -447,6 +633,14 @@ func (r *Layer2Relayer) ProcessCommittedBatches() {
 	r.metrics.rollupL2RelayerProcessCommittedBatchesTotal.Inc()
 
 	batch := batches[0]
+	if held, err := r.isFinalizeHeld(batch); err != nil {
+		log.Error("Failed to check the finalize holding window", "index", batch.Index, "hash", batch.Hash, "err", err)
+		return
+	} else if held {
+		r.metrics.rollupL2RelayerProcessCommittedBatchesHeldTotal.Inc()
+		return
+	}
+
 	status := types.ProvingStatus(batch.ProvingStatus)
 	switch status {
 	case types.ProvingTaskUnassigned, types.ProvingTaskAssigned:
@@ -462,6 +656,15 @@ func (r *Layer2Relayer) ProcessCommittedBatches() {
 		}
 
 	case types.ProvingTaskVerified:
+		if r.isProofStale(batch) {
+			return
+		}
+		if left, err := r.isFinalizeLeftToOtherOperators(batch); err != nil {
+			log.Error("failed to check the finalization of the batch by the other operators", "index", batch.Index, "hash", batch.Hash, "err", err)
+			return
+		} else if left {
+			return
+		}
 		log.Info("Start to roll up zk proof", "hash", batch.Hash)
 		r.metrics.rollupL2RelayerProcessCommittedBatchesFinalizedTotal.Inc()
 		if err := r.finalizeBatch(batch, true); err != nil {
@@ -522,6 +725,10 @@ func (r *Layer2Relayer) finalizeBatch(dbBatch *orm.Batch, withProof bool) error
 		return fmt.Errorf("failed to fetch chunks: %w", err)
 	}
 
+	if err = r.crossCheckFinalize(dbBatch, dbChunks); err != nil {
+		return err
+	}
+
 	var aggProof *message.BatchProof
 	if withProof {
 		aggProof, getErr = r.batchOrm.GetVerifiedProofByHash(r.ctx, dbBatch.Hash)
@@ -530,12 +737,19 @@ func (r *Layer2Relayer) finalizeBatch(dbBatch *orm.Batch, withProof bool) error
 		}
 
 		if err = aggProof.SanityCheck(); err != nil {
+			r.rerequestProof(dbBatch, proofRerequestReasonInvalid, err)
 			return fmt.Errorf("failed to check agg_proof sanity, index: %d, err: %w", dbBatch.Index, err)
 		}
 	}
 
+	// the finalize call is the one of the verifier the proof is for, a proof of another version isn't broadcast.
+	codecVersion, err := r.finalizeCodecVersion(dbBatch, rutils.GetBatchCodecVersion(r.chainCfg, dbBatch.Index, dbChunks[0].StartBlockNumber), aggProof)
+	if err != nil {
+		return err
+	}
+
 	var calldata []byte
-	if !r.chainCfg.IsBernoulli(new(big.Int).SetUint64(dbChunks[0].StartBlockNumber)) { // codecv0
+	if codecVersion == encoding.CodecV0 {
 		calldata, err = r.constructFinalizeBatchPayloadCodecV0(dbBatch, dbParentBatch, aggProof)
 		if err != nil {
 			return fmt.Errorf("failed to construct commitBatch payload codecv0, index: %v, err: %w", dbBatch.Index, err)
@@ -556,14 +770,30 @@ func (r *Layer2Relayer) finalizeBatch(dbBatch *orm.Batch, withProof bool) error
 		}
 	}
 
-	txHash, err := r.finalizeSender.SendTransaction(dbBatch.Hash, &r.cfg.RollupContractAddress, calldata, nil, 0)
+	if err = r.checkFinalizeSequencing(dbBatch, dbParentBatch); err != nil {
+		return err
+	}
+
+	to, calldata, err := r.rollupContractCall(committeeCallKindFinalize, dbBatch.Hash, calldata)
+	if err != nil {
+		return fmt.Errorf("failed to get committee approval of finalizeBatch, index: %v, err: %w", dbBatch.Index, err)
+	}
+
+	if r.multisig != nil {
+		return r.prepareMultisigTransaction(committeeCallKindFinalize, dbBatch, *to, calldata)
+	}
+
+	txHash, err := r.finalizeSender.SendTransaction(dbBatch.Hash, to, calldata, nil, 0)
 	if err != nil {
+		revertReason, revertClass := estimationRevert(err)
 		log.Error(
 			"finalizeBatch in layer1 failed",
 			"with proof", withProof,
 			"index", dbBatch.Index,
 			"hash", dbBatch.Hash,
 			"RollupContractAddress", r.cfg.RollupContractAddress,
+			"revert reason", revertReason,
+			"revert class", revertClass,
 			"err", err,
 		)
 		log.Debug(
@@ -654,33 +884,60 @@ func (r *Layer2Relayer) getBatchStatusByIndex(batch *orm.Batch) (bool, error) {
 }
 
 func (r *Layer2Relayer) handleConfirmation(cfm *sender.Confirmation) {
+	if isBatchRevertContext(cfm.ContextID) {
+		r.handleBatchRevertConfirmation(cfm)
+		return
+	}
+
 	switch cfm.SenderType {
 	case types.SenderTypeCommitBatch:
+		r.recordConfirmation(r.commitCircuitBreaker, cfm.IsSuccessful)
 		var status types.RollupStatus
 		if cfm.IsSuccessful {
 			status = types.RollupCommitted
 			r.metrics.rollupL2BatchesCommittedConfirmedTotal.Inc()
 		} else {
-			status = types.RollupCommitFailed
 			r.metrics.rollupL2BatchesCommittedConfirmedFailedTotal.Inc()
 			log.Warn("CommitBatchTxType transaction confirmed but failed in layer1", "confirmation", cfm)
+			status = r.handleRevert(r.commitCircuitBreaker, cfm, types.RollupCommitFailed)
+			if status == types.RollupUndefined || r.dissolveFailedBatch(cfm) {
+				break
+			}
 		}
 
+		if status == types.RollupCommitted {
+			if err := r.batchOrm.UpdateCommittedBatch(r.ctx, cfm.ContextID, cfm.TxHash.String(), cfm.BlockNumber); err != nil {
+				log.Warn("UpdateCommittedBatch failed", "confirmation", cfm, "err", err)
+			}
+			break
+		}
 		err := r.batchOrm.UpdateCommitTxHashAndRollupStatus(r.ctx, cfm.ContextID, cfm.TxHash.String(), status)
 		if err != nil {
 			log.Warn("UpdateCommitTxHashAndRollupStatus failed", "confirmation", cfm, "err", err)
 		}
 	case types.SenderTypeFinalizeBatch:
+		r.recordConfirmation(r.finalizeCircuitBreaker, cfm.IsSuccessful)
 		var status types.RollupStatus
 		if cfm.IsSuccessful {
 			status = types.RollupFinalized
 			r.metrics.rollupL2BatchesFinalizedConfirmedTotal.Inc()
 		} else {
-			status = types.RollupFinalizeFailed
 			r.metrics.rollupL2BatchesFinalizedConfirmedFailedTotal.Inc()
 			log.Warn("FinalizeBatchTxType transaction confirmed but failed in layer1", "confirmation", cfm)
+			status = r.handleRevert(r.finalizeCircuitBreaker, cfm, types.RollupFinalizeFailed)
+			if status == types.RollupUndefined {
+				break
+			}
 		}
 
+		if status == types.RollupFinalized {
+			if err := r.batchOrm.UpdateFinalizedBatch(r.ctx, cfm.ContextID, cfm.TxHash.String(), cfm.BlockNumber); err != nil {
+				log.Warn("UpdateFinalizedBatch failed", "confirmation", cfm, "err", err)
+			} else {
+				r.observeBatchLatency(cfm.ContextID)
+			}
+			break
+		}
 		err := r.batchOrm.UpdateFinalizeTxHashAndRollupStatus(r.ctx, cfm.ContextID, cfm.TxHash.String(), status)
 		if err != nil {
 			log.Warn("UpdateFinalizeTxHashAndRollupStatus failed", "confirmation", cfm, "err", err)
@@ -725,13 +982,26 @@ func (r *Layer2Relayer) handleL2RollupRelayerConfirmLoop(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case cfm := <-r.commitSender.ConfirmChan():
+			r.confirmMu.Lock()
 			r.handleConfirmation(cfm)
+			r.confirmMu.Unlock()
 		case cfm := <-r.finalizeSender.ConfirmChan():
+			r.confirmMu.Lock()
 			r.handleConfirmation(cfm)
+			r.confirmMu.Unlock()
 		}
 	}
 }
 
+// rollupContractCall returns the destination and calldata of a rollup contract call. If committee signing
+// is enabled, the call is approved by the committee and wrapped into a relay contract call.
+func (r *Layer2Relayer) rollupContractCall(kind string, batchHash string, calldata []byte) (*common.Address, []byte, error) {
+	if r.committee == nil {
+		return &r.cfg.RollupContractAddress, calldata, nil
+	}
+	return r.committee.wrap(r.ctx, kind, batchHash, r.cfg.RollupContractAddress, calldata)
+}
+
 func (r *Layer2Relayer) constructCommitBatchPayloadCodecV0(dbBatch *orm.Batch, dbParentBatch *orm.Batch, dbChunks []*orm.Chunk, chunks []*encoding.Chunk) ([]byte, error) {
 	daBatch, err := codecv0.NewDABatchFromBytes(dbBatch.BatchHeader)
 	if err != nil {
@@ -758,7 +1028,7 @@ func (r *Layer2Relayer) constructCommitBatchPayloadCodecV0(dbBatch *orm.Batch, d
 	return calldata, nil
 }
 
-func (r *Layer2Relayer) constructCommitBatchPayloadCodecV1(dbBatch *orm.Batch, dbParentBatch *orm.Batch, dbChunks []*orm.Chunk, chunks []*encoding.Chunk) ([]byte, *kzg4844.Blob, error) {
+func (r *Layer2Relayer) constructCommitBatchPayloadCodecV1(dbBatch *orm.Batch, dbParentBatch *orm.Batch, dbChunks []*orm.Chunk, chunks []*encoding.Chunk) ([]byte, *kzg4844.Blob, common.Hash, error) {
 	batch := &encoding.Batch{
 		Index:                      dbBatch.Index,
 		TotalL1MessagePoppedBefore: dbChunks[0].TotalL1MessagesPoppedBefore,
@@ -768,23 +1038,23 @@ func (r *Layer2Relayer) constructCommitBatchPayloadCodecV1(dbBatch *orm.Batch, d
 
 	daBatch, createErr := codecv1.NewDABatch(batch)
 	if createErr != nil {
-		return nil, nil, fmt.Errorf("failed to create DA batch: %w", createErr)
+		return nil, nil, common.Hash{}, fmt.Errorf("failed to create DA batch: %w", createErr)
 	}
 
 	encodedChunks := make([][]byte, len(dbChunks))
 	for i, c := range dbChunks {
 		daChunk, createErr := codecv1.NewDAChunk(chunks[i], c.TotalL1MessagesPoppedBefore)
 		if createErr != nil {
-			return nil, nil, fmt.Errorf("failed to create DA chunk: %w", createErr)
+			return nil, nil, common.Hash{}, fmt.Errorf("failed to create DA chunk: %w", createErr)
 		}
 		encodedChunks[i] = daChunk.Encode()
 	}
 
 	calldata, packErr := r.l1RollupABI.Pack("commitBatch", daBatch.Version, dbParentBatch.BatchHeader, encodedChunks, daBatch.SkippedL1MessageBitmap)
 	if packErr != nil {
-		return nil, nil, fmt.Errorf("failed to pack commitBatch: %w", packErr)
+		return nil, nil, common.Hash{}, fmt.Errorf("failed to pack commitBatch: %w", packErr)
 	}
-	return calldata, daBatch.Blob(), nil
+	return calldata, daBatch.Blob(), daBatch.BlobVersionedHash, nil
 }
 
 func (r *Layer2Relayer) constructFinalizeBatchPayloadCodecV0(dbBatch *orm.Batch, dbParentBatch *orm.Batch, aggProof *message.BatchProof) ([]byte, error) {
@@ -866,6 +1136,44 @@ func (r *Layer2Relayer) constructFinalizeBatchPayloadCodecV1(dbBatch *orm.Batch,
 	return calldata, nil
 }
 
+// Drain stops sending new commit and finalize transactions, and waits until the pending ones are confirmed and
+// their confirmations handled, or ctx is done. The transactions still pending are kept in the database, and are
+// tracked again by the senders on the next start, so that no nonce is abandoned.
+func (r *Layer2Relayer) Drain(ctx context.Context) {
+	for _, s := range []*sender.Sender{r.commitSender, r.finalizeSender} {
+		if s != nil {
+			s.StopSending()
+		}
+	}
+
+	for name, s := range map[string]*sender.Sender{"commit_sender": r.commitSender, "finalize_sender": r.finalizeSender} {
+		if s == nil {
+			continue
+		}
+		pending, err := s.Drain(ctx)
+		if err != nil {
+			log.Error("failed to drain the pending transactions", "sender", name, "err", err)
+			continue
+		}
+		if pending > 0 {
+			log.Warn("drain timed out, the pending transactions are tracked again on restart", "sender", name, "pending", pending)
+		}
+	}
+
+	r.confirmMu.Lock()
+	defer r.confirmMu.Unlock()
+	log.Info("layer2 relayer drained")
+}
+
+// SetFeatureFlags sets the feature flags gating the experimental behaviors of the senders.
+func (r *Layer2Relayer) SetFeatureFlags(flags *featureflag.Flags) {
+	for _, s := range []*sender.Sender{r.gasOracleSender, r.commitSender, r.finalizeSender} {
+		if s != nil {
+			s.SetFeatureFlags(flags)
+		}
+	}
+}
+
 // StopSenders stops the senders of the rollup-relayer to prevent querying the removed pending_transaction table in unit tests.
 // for unit test
 func (r *Layer2Relayer) StopSenders() {
@@ -15,14 +15,33 @@ type l2RelayerMetrics struct {
 	rollupL2RelayerProcessCommittedBatchesTotal                 prometheus.Counter
 	rollupL2RelayerProcessCommittedBatchesFinalizedTotal        prometheus.Counter
 	rollupL2RelayerProcessCommittedBatchesFinalizedSuccessTotal prometheus.Counter
+	rollupL2RelayerProcessCommittedBatchesHeldTotal             prometheus.Counter
 	rollupL2BatchesCommittedConfirmedTotal                      prometheus.Counter
 	rollupL2BatchesCommittedConfirmedFailedTotal                prometheus.Counter
+	rollupL2BatchesDissolvedTotal                               prometheus.Counter
 	rollupL2BatchesFinalizedConfirmedTotal                      prometheus.Counter
 	rollupL2BatchesFinalizedConfirmedFailedTotal                prometheus.Counter
 	rollupL2UpdateGasOracleConfirmedTotal                       prometheus.Counter
 	rollupL2UpdateGasOracleConfirmedFailedTotal                 prometheus.Counter
 	rollupL2ChainMonitorLatestFailedCall                        prometheus.Counter
 	rollupL2ChainMonitorLatestFailedBatchStatus                 prometheus.Counter
+	rollupL2RelayerCircuitBreakerTrippedTotal                   *prometheus.CounterVec
+	rollupL2RelayerCircuitBreakerOpen                           *prometheus.GaugeVec
+	rollupL2RelayerFinalizeCrossCheckMismatchTotal              prometheus.Counter
+	rollupL2RelayerRevertTotal                                  *prometheus.CounterVec
+	rollupL2RelayerPreparedTransactionTotal                     *prometheus.CounterVec
+	rollupL2RelayerPreparedTransactionExecutedTotal             *prometheus.CounterVec
+	rollupL2RelayerVerifiedProofAgeSec                          prometheus.Gauge
+	rollupL2RelayerProofRerequestedTotal                        *prometheus.CounterVec
+	rollupL2BatchStageLatencySec                                *prometheus.HistogramVec
+	rollupL2BatchTotalLatencySec                                prometheus.Histogram
+	rollupL2RelayerSkippedL1MessagesTotal                       prometheus.Counter
+	rollupL2RelayerSequencingRefusedTotal                       *prometheus.CounterVec
+	rollupL2RelayerDABackendPostTotal                           *prometheus.CounterVec
+	rollupL2RelayerDABackendPostFailureTotal                    *prometheus.CounterVec
+	rollupL2RelayerOperatorFallbackHeldBatchIndex               *prometheus.GaugeVec
+	rollupL2RelayerProofVersionMismatchTotal                    *prometheus.CounterVec
+	rollupL2RelayerBatchRevertTotal                             *prometheus.CounterVec
 }
 
 var (
@@ -61,6 +80,10 @@ func initL2RelayerMetrics(reg prometheus.Registerer) *l2RelayerMetrics {
 				Name: "rollup_layer2_process_committed_batches_finalized_success_total",
 				Help: "The total number of layer2 process committed batches finalized success total",
 			}),
+			rollupL2RelayerProcessCommittedBatchesHeldTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_layer2_process_committed_batches_held_total",
+				Help: "The total number of times a committed batch was not finalized because of the finalize holding window",
+			}),
 			rollupL2BatchesCommittedConfirmedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
 				Name: "rollup_layer2_process_committed_batches_confirmed_total",
 				Help: "The total number of layer2 process committed batches confirmed total",
@@ -69,6 +92,10 @@ func initL2RelayerMetrics(reg prometheus.Registerer) *l2RelayerMetrics {
 				Name: "rollup_layer2_process_committed_batches_confirmed_failed_total",
 				Help: "The total number of layer2 process committed batches confirmed failed total",
 			}),
+			rollupL2BatchesDissolvedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_layer2_batches_dissolved_total",
+				Help: "The total number of layer2 batches dissolved after their commit failed with a known error",
+			}),
 			rollupL2BatchesFinalizedConfirmedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
 				Name: "rollup_layer2_process_finalized_batches_confirmed_total",
 				Help: "The total number of layer2 process finalized batches confirmed total",
@@ -93,6 +120,76 @@ func initL2RelayerMetrics(reg prometheus.Registerer) *l2RelayerMetrics {
 				Name: "rollup_layer2_chain_monitor_latest_failed_batch_status",
 				Help: "The total number of failed batch status get from chain_monitor",
 			}),
+			rollupL2RelayerCircuitBreakerTrippedTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_layer2_relayer_circuit_breaker_tripped_total",
+				Help: "The total number of times a relayer pipeline is paused after repeated failed transactions",
+			}, []string{"pipeline"}),
+			rollupL2RelayerCircuitBreakerOpen: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+				Name: "rollup_layer2_relayer_circuit_breaker_open",
+				Help: "Whether a relayer pipeline is paused by its circuit breaker, 1 if paused and 0 otherwise",
+			}, []string{"pipeline"}),
+			rollupL2RelayerFinalizeCrossCheckMismatchTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_layer2_finalize_cross_check_mismatch_total",
+				Help: "The total number of batches not finalized as their roots mismatch the cross-check node",
+			}),
+			rollupL2RelayerRevertTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_layer2_relayer_revert_total",
+				Help: "The total number of reverted commit and finalize transactions by revert class",
+			}, []string{"pipeline", "class"}),
+			rollupL2RelayerPreparedTransactionTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_layer2_relayer_prepared_transaction_total",
+				Help: "The total number of commit and finalize transactions prepared for the multisig",
+			}, []string{"kind"}),
+			rollupL2RelayerPreparedTransactionExecutedTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_layer2_relayer_prepared_transaction_executed_total",
+				Help: "The total number of prepared transactions observed executed on the rollup contract",
+			}, []string{"kind"}),
+			rollupL2RelayerVerifiedProofAgeSec: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+				Name: "rollup_layer2_relayer_verified_proof_age_sec",
+				Help: "The age of the verified proof of the next batch to finalize, in seconds since its verification",
+			}),
+			rollupL2RelayerProofRerequestedTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_layer2_relayer_proof_rerequested_total",
+				Help: "The total number of batch proofs requested again from the coordinator instead of being finalized, by reason",
+			}, []string{"reason"}),
+			rollupL2BatchStageLatencySec: promauto.With(reg).NewHistogramVec(prometheus.HistogramOpts{
+				Name:    "rollup_layer2_batch_stage_latency_sec",
+				Help:    "The latency between two consecutive lifecycle stages of the finalized batches, in seconds",
+				Buckets: prometheus.ExponentialBuckets(1, 2, 18),
+			}, []string{"from", "to"}),
+			rollupL2BatchTotalLatencySec: promauto.With(reg).NewHistogram(prometheus.HistogramOpts{
+				Name:    "rollup_layer2_batch_total_latency_sec",
+				Help:    "The latency from the import of the first block of a batch to its finalization, in seconds",
+				Buckets: prometheus.ExponentialBuckets(1, 2, 18),
+			}),
+			rollupL2RelayerSkippedL1MessagesTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_layer2_relayer_skipped_l1_messages_total",
+				Help: "The total number of skipped L1 messages recorded from the committed batches",
+			}),
+			rollupL2RelayerSequencingRefusedTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_layer2_relayer_sequencing_refused_total",
+				Help: "The total number of commit and finalize transactions not sent as their batch isn't the next one expected by the rollup contract",
+			}, []string{"pipeline"}),
+			rollupL2RelayerDABackendPostTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_layer2_relayer_da_backend_post_total",
+				Help: "The total number of blobs posted to the alternative DA backend",
+			}, []string{"backend"}),
+			rollupL2RelayerDABackendPostFailureTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_layer2_relayer_da_backend_post_failure_total",
+				Help: "The total number of failed posts of blobs to the alternative DA backend",
+			}, []string{"backend"}),
+			rollupL2RelayerOperatorFallbackHeldBatchIndex: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+				Name: "rollup_layer2_relayer_operator_fallback_held_batch_index",
+				Help: "The index of the batch whose commit or finalize transaction is left to the other operators, 0 if none",
+			}, []string{"pipeline"}),
+			rollupL2RelayerProofVersionMismatchTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_layer2_relayer_proof_version_mismatch_total",
+				Help: "The total number of proofs not of the version expected by the verifier of their batch, by expected version",
+			}, []string{"expected_version"}),
+			rollupL2RelayerBatchRevertTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_layer2_relayer_batch_revert_total",
+				Help: "The total number of batch reverts sent by the operators, by the status they reached",
+			}, []string{"status"}),
 		}
 	})
 	return l2RelayerMetric
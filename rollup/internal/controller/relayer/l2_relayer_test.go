@@ -13,6 +13,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/scroll-tech/go-ethereum/common"
 	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto"
 	"github.com/scroll-tech/go-ethereum/crypto/kzg4844"
 	"github.com/scroll-tech/go-ethereum/params"
 	"github.com/smartystreets/goconvey/convey"
@@ -27,6 +28,7 @@ import (
 
 	"scroll-tech/database/migrate"
 
+	"scroll-tech/rollup/internal/config"
 	"scroll-tech/rollup/internal/controller/sender"
 	"scroll-tech/rollup/internal/orm"
 )
@@ -99,6 +101,57 @@ func testL2RelayerProcessPendingBatches(t *testing.T) {
 	}
 }
 
+func testL2RelayerProcessPendingBatchesInFlightLimit(t *testing.T) {
+	db := setupL2RelayerDB(t)
+	defer database.CloseDB(db)
+
+	relayerCfg := *cfg.L2Config.RelayerConfig
+	relayerCfg.MaxInFlightCommits = 1
+	relayer, err := NewLayer2Relayer(context.Background(), l2Cli, db, &relayerCfg, &params.ChainConfig{BernoulliBlock: big.NewInt(0)}, true, ServiceTypeL2RollupRelayer, nil)
+	assert.NoError(t, err)
+	defer relayer.StopSenders()
+
+	patchGuard := gomonkey.ApplyMethodFunc(l2Cli, "SendTransaction", func(_ context.Context, _ *gethTypes.Transaction) error {
+		return nil
+	})
+	defer patchGuard.Reset()
+
+	l2BlockOrm := orm.NewL2Block(db)
+	assert.NoError(t, l2BlockOrm.InsertL2Blocks(context.Background(), []*encoding.Block{block1, block2}))
+	chunkOrm := orm.NewChunk(db)
+	_, err = chunkOrm.InsertChunk(context.Background(), chunk1, encoding.CodecV0)
+	assert.NoError(t, err)
+	_, err = chunkOrm.InsertChunk(context.Background(), chunk2, encoding.CodecV0)
+	assert.NoError(t, err)
+
+	batchOrm := orm.NewBatch(db)
+	var batchHashes []string
+	for index := uint64(1); index <= 2; index++ {
+		batch := &encoding.Batch{
+			Index:                      index,
+			TotalL1MessagePoppedBefore: 0,
+			ParentBatchHash:            common.Hash{},
+			Chunks:                     []*encoding.Chunk{chunk1, chunk2},
+		}
+		dbBatch, err := batchOrm.InsertBatch(context.Background(), batch, encoding.CodecV0)
+		assert.NoError(t, err)
+		batchHashes = append(batchHashes, dbBatch.Hash)
+	}
+
+	// only the first batch is sent while its commit is in flight.
+	relayer.ProcessPendingBatches()
+	relayer.ProcessPendingBatches()
+	statuses, err := batchOrm.GetRollupStatusByHashList(context.Background(), batchHashes)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.RollupStatus{types.RollupCommitting, types.RollupPending}, statuses)
+
+	assert.NoError(t, batchOrm.UpdateRollupStatus(context.Background(), batchHashes[0], types.RollupCommitted))
+	relayer.ProcessPendingBatches()
+	statuses, err = batchOrm.GetRollupStatusByHashList(context.Background(), batchHashes)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.RollupStatus{types.RollupCommitted, types.RollupCommitting}, statuses)
+}
+
 func testL2RelayerProcessCommittedBatches(t *testing.T) {
 	codecVersions := []encoding.CodecVersion{encoding.CodecV0, encoding.CodecV1}
 	for _, codecVersion := range codecVersions {
@@ -287,6 +340,122 @@ func testL2RelayerCommitConfirm(t *testing.T) {
 	assert.True(t, ok)
 }
 
+func testL2RelayerCommitConfirmRebatch(t *testing.T) {
+	db := setupL2RelayerDB(t)
+	defer database.CloseDB(db)
+
+	relayerCfg := *cfg.L2Config.RelayerConfig
+	relayerCfg.AutoRebatchConfig = &config.AutoRebatchConfig{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	l2Relayer, err := NewLayer2Relayer(ctx, l2Cli, db, &relayerCfg, &params.ChainConfig{}, true, ServiceTypeL2RollupRelayer, nil)
+	assert.NoError(t, err)
+	defer l2Relayer.StopSenders()
+
+	knownRevertData := append(crypto.Keccak256([]byte("ErrorIncorrectBitmapLength()"))[:4], make([]byte, 28)...)
+	assert.Equal(t, "ErrorIncorrectBitmapLength", l2Relayer.rebatcher.revertError(knownRevertData))
+	// the same chunks would be re-proposed, so the chunk content errors don't dissolve the batch.
+	assert.Equal(t, "", l2Relayer.rebatcher.revertError(crypto.Keccak256([]byte("ErrorTooManyTxsInOneChunk()"))[:4]))
+	assert.Equal(t, "", l2Relayer.rebatcher.revertError(crypto.Keccak256([]byte("ErrorIncorrectBatchHash()"))[:4]))
+	assert.Equal(t, "", l2Relayer.rebatcher.revertError(nil))
+
+	batchOrm := orm.NewBatch(db)
+	insertBatch := func(index uint64) string {
+		batch := &encoding.Batch{
+			Index:                      index,
+			TotalL1MessagePoppedBefore: 0,
+			ParentBatchHash:            common.Hash{},
+			Chunks:                     []*encoding.Chunk{chunk1, chunk2},
+		}
+		dbBatch, err := batchOrm.InsertBatch(context.Background(), batch, encoding.CodecV0)
+		assert.NoError(t, err)
+		return dbBatch.Hash
+	}
+	getRollupStatus := func(batchHash string) (types.RollupStatus, bool) {
+		batchInDB, err := batchOrm.GetBatches(context.Background(), map[string]interface{}{"hash": batchHash}, nil, 0)
+		if err != nil || len(batchInDB) != 1 {
+			return types.RollupUndefined, false
+		}
+		return types.RollupStatus(batchInDB[0].RollupStatus), true
+	}
+	batchHash1 := insertBatch(1)
+	batchHash2 := insertBatch(2)
+
+	// the failed batch is dissolved along with the batch after it.
+	l2Relayer.commitSender.SendConfirmation(&sender.Confirmation{
+		ContextID:    batchHash1,
+		IsSuccessful: false,
+		TxHash:       common.HexToHash("0x123456789abcdef"),
+		SenderType:   types.SenderTypeCommitBatch,
+		RevertData:   knownRevertData,
+	})
+	ok := utils.TryTimes(5, func() bool {
+		_, found1 := getRollupStatus(batchHash1)
+		_, found2 := getRollupStatus(batchHash2)
+		return !found1 && !found2
+	})
+	assert.True(t, ok)
+
+	// the same batch is proposed again and fails again, it's not dissolved more than max rebatches times.
+	assert.Equal(t, batchHash1, insertBatch(1))
+	l2Relayer.commitSender.SendConfirmation(&sender.Confirmation{
+		ContextID:    batchHash1,
+		IsSuccessful: false,
+		TxHash:       common.HexToHash("0x123456789abcdef"),
+		SenderType:   types.SenderTypeCommitBatch,
+		RevertData:   knownRevertData,
+	})
+	ok = utils.TryTimes(5, func() bool {
+		status, found := getRollupStatus(batchHash1)
+		return found && status == types.RollupCommitFailed
+	})
+	assert.True(t, ok)
+}
+
+func testL2RelayerCommitConfirmCircuitBreaker(t *testing.T) {
+	db := setupL2RelayerDB(t)
+	defer database.CloseDB(db)
+
+	relayerCfg := *cfg.L2Config.RelayerConfig
+	relayerCfg.CircuitBreakerConfig = &config.CircuitBreakerConfig{MaxConsecutiveFailures: 2}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	l2Relayer, err := NewLayer2Relayer(ctx, l2Cli, db, &relayerCfg, &params.ChainConfig{}, true, ServiceTypeL2RollupRelayer, nil)
+	assert.NoError(t, err)
+	defer l2Relayer.StopSenders()
+
+	proposerPauseOrm := orm.NewProposerPause(db)
+	isPaused := func() bool {
+		pause, err := proposerPauseOrm.GetProposerPause(context.Background(), CommitCircuitBreakerName)
+		return err == nil && pause != nil && pause.Paused
+	}
+	confirm := func(isSuccessful bool) {
+		l2Relayer.commitSender.SendConfirmation(&sender.Confirmation{
+			ContextID:    "0x0",
+			IsSuccessful: isSuccessful,
+			TxHash:       common.HexToHash("0x123456789abcdef"),
+			SenderType:   types.SenderTypeCommitBatch,
+		})
+	}
+
+	// a success in between resets the failures in a row.
+	confirm(false)
+	confirm(true)
+	confirm(false)
+	time.Sleep(time.Second)
+	assert.False(t, isPaused())
+	assert.False(t, l2Relayer.isCircuitOpen(l2Relayer.commitCircuitBreaker))
+
+	confirm(false)
+	assert.True(t, utils.TryTimes(5, isPaused))
+	assert.True(t, l2Relayer.isCircuitOpen(l2Relayer.commitCircuitBreaker))
+	assert.False(t, l2Relayer.isCircuitOpen(l2Relayer.finalizeCircuitBreaker))
+
+	// the pipeline is resumed by an operator.
+	assert.NoError(t, proposerPauseOrm.UpsertProposerPause(context.Background(), CommitCircuitBreakerName, false, ""))
+	assert.False(t, l2Relayer.isCircuitOpen(l2Relayer.commitCircuitBreaker))
+}
+
 func testL2RelayerFinalizeConfirm(t *testing.T) {
 	db := setupL2RelayerDB(t)
 	defer database.CloseDB(db)
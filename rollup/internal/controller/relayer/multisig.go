@@ -0,0 +1,190 @@
+package relayer
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/accounts/abi"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/common/types"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+)
+
+// timelockControllerABI holds the calls of a TimelockController scheduling and executing an operation.
+const timelockControllerABI = `[
+	{"type":"function","name":"schedule","stateMutability":"nonpayable","inputs":[{"name":"target","type":"address"},{"name":"value","type":"uint256"},{"name":"data","type":"bytes"},{"name":"predecessor","type":"bytes32"},{"name":"salt","type":"bytes32"},{"name":"delay","type":"uint256"}],"outputs":[]},
+	{"type":"function","name":"execute","stateMutability":"payable","inputs":[{"name":"target","type":"address"},{"name":"value","type":"uint256"},{"name":"payload","type":"bytes"},{"name":"predecessor","type":"bytes32"},{"name":"salt","type":"bytes32"}],"outputs":[]}
+]`
+
+// preparedCallsLimit is the max number of prepared transactions tracked by a ProcessPreparedTransactions run.
+const preparedCallsLimit = 100
+
+// preparedCall is a call of a prepared transaction.
+type preparedCall struct {
+	To    common.Address `json:"to"`
+	Value string         `json:"value"`
+	Data  hexutil.Bytes  `json:"data"`
+}
+
+// safeTransactionBatch is a batch of the Safe transaction builder, imported in the Safe interface.
+type safeTransactionBatch struct {
+	Version      string                   `json:"version"`
+	ChainID      string                   `json:"chainId"`
+	CreatedAt    int64                    `json:"createdAt"`
+	Meta         safeTransactionBatchMeta `json:"meta"`
+	Transactions []*preparedCall          `json:"transactions"`
+}
+
+// safeTransactionBatchMeta describes a batch of the Safe transaction builder.
+type safeTransactionBatchMeta struct {
+	Name                   string         `json:"name"`
+	Description            string         `json:"description"`
+	CreatedFromSafeAddress common.Address `json:"createdFromSafeAddress"`
+}
+
+// timelockOperation is the schedule and execute calls of a timelock controller operation, the execute call is
+// only valid once the delay passed after the schedule call.
+type timelockOperation struct {
+	ChainID  string        `json:"chainId"`
+	ID       common.Hash   `json:"id"`
+	Delay    uint64        `json:"delay"`
+	Schedule *preparedCall `json:"schedule"`
+	Execute  *preparedCall `json:"execute"`
+}
+
+// multisig prepares the commit and finalize calls for the multisig or the timelock holding the rollup contract roles.
+type multisig struct {
+	mode          string
+	address       common.Address
+	timelockDelay uint64
+	timelockABI   abi.ABI
+}
+
+func newMultisig(cfg *config.MultisigConfig) (*multisig, error) {
+	parsed, err := abi.JSON(strings.NewReader(timelockControllerABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse timelock controller abi: %w", err)
+	}
+
+	return &multisig{
+		mode:          cfg.Mode,
+		address:       cfg.Address,
+		timelockDelay: cfg.TimelockDelaySec,
+		timelockABI:   parsed,
+	}, nil
+}
+
+// payload returns the prepared transaction of the call of the batch, in the json format of the mode.
+func (m *multisig) payload(chainID *big.Int, kind string, dbBatch *orm.Batch, target common.Address, calldata []byte) ([]byte, error) {
+	if m.mode == config.MultisigModeSafe {
+		return json.Marshal(&safeTransactionBatch{
+			Version:   "1.0",
+			ChainID:   chainID.String(),
+			CreatedAt: time.Now().UnixMilli(),
+			Meta: safeTransactionBatchMeta{
+				Name:                   fmt.Sprintf("%s %d", kind, dbBatch.Index),
+				Description:            fmt.Sprintf("%s of batch %d, hash %s", kind, dbBatch.Index, dbBatch.Hash),
+				CreatedFromSafeAddress: m.address,
+			},
+			Transactions: []*preparedCall{{To: target, Value: "0", Data: calldata}},
+		})
+	}
+
+	// the batch hash salts the operation, so that the calls of different batches are distinct operations.
+	value := big.NewInt(0)
+	predecessor := common.Hash{}
+	salt := common.HexToHash(dbBatch.Hash)
+	encoded, err := m.timelockABI.Methods["execute"].Inputs.Pack(target, value, calldata, predecessor, salt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode timelock operation: %w", err)
+	}
+	schedule, err := m.timelockABI.Pack("schedule", target, value, calldata, predecessor, salt, new(big.Int).SetUint64(m.timelockDelay))
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack timelock schedule: %w", err)
+	}
+	execute, err := m.timelockABI.Pack("execute", target, value, calldata, predecessor, salt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack timelock execute: %w", err)
+	}
+	return json.Marshal(&timelockOperation{
+		ChainID:  chainID.String(),
+		ID:       crypto.Keccak256Hash(encoded),
+		Delay:    m.timelockDelay,
+		Schedule: &preparedCall{To: m.address, Value: "0", Data: schedule},
+		Execute:  &preparedCall{To: m.address, Value: "0", Data: execute},
+	})
+}
+
+// prepareMultisigTransaction stores the call of the batch as a transaction prepared for the multisig instead of
+// sending it, and marks the batch as committing or finalizing until the call is observed on the rollup contract.
+func (r *Layer2Relayer) prepareMultisigTransaction(kind string, dbBatch *orm.Batch, target common.Address, calldata []byte) error {
+	payload, err := r.multisig.payload(r.commitSender.GetChainID(), kind, dbBatch, target, calldata)
+	if err != nil {
+		return fmt.Errorf("failed to prepare %s transaction, index: %v, err: %w", kind, dbBatch.Index, err)
+	}
+	if err = r.preparedTransactionOrm.UpsertPreparedTransaction(r.ctx, dbBatch.Hash, dbBatch.Index, kind, string(payload)); err != nil {
+		return err
+	}
+
+	// the transaction hash is recorded by the L1 watcher along with the rollup event.
+	if kind == committeeCallKindCommit {
+		err = r.batchOrm.UpdateCommitTxHashAndRollupStatus(r.ctx, dbBatch.Hash, "", types.RollupCommitting)
+	} else {
+		err = r.batchOrm.UpdateFinalizeTxHashAndRollupStatus(r.ctx, dbBatch.Hash, "", types.RollupFinalizing)
+	}
+	if err != nil {
+		return err
+	}
+
+	r.metrics.rollupL2RelayerPreparedTransactionTotal.WithLabelValues(kind).Inc()
+	log.Info("Prepared the multisig transaction", "kind", kind, "mode", r.multisig.mode, "batch index", dbBatch.Index, "batch hash", dbBatch.Hash)
+	return nil
+}
+
+// ProcessPreparedTransactions marks the prepared transactions as executed once the L1 watcher imported the rollup
+// events of their batches, recording the L1 transactions executing them.
+func (r *Layer2Relayer) ProcessPreparedTransactions() {
+	preparedTxs, err := r.preparedTransactionOrm.GetPreparedTransactions(r.ctx, orm.PreparedTransactionStatusPrepared, preparedCallsLimit)
+	if err != nil {
+		log.Error("Failed to fetch the prepared transactions", "err", err)
+		return
+	}
+
+	for _, preparedTx := range preparedTxs {
+		dbBatch, err := r.batchOrm.GetBatchByHash(r.ctx, preparedTx.BatchHash)
+		if err != nil {
+			log.Error("Failed to get the batch of the prepared transaction", "kind", preparedTx.Kind, "batch hash", preparedTx.BatchHash, "err", err)
+			continue
+		}
+
+		status := types.RollupStatus(dbBatch.RollupStatus)
+		var txHash string
+		switch {
+		case preparedTx.Kind == committeeCallKindCommit && status >= types.RollupCommitted && status != types.RollupCommitFailed:
+			txHash = dbBatch.CommitTxHash
+		case preparedTx.Kind == committeeCallKindFinalize && status == types.RollupFinalized:
+			txHash = dbBatch.FinalizeTxHash
+		default:
+			continue
+		}
+
+		if err = r.preparedTransactionOrm.UpdatePreparedTransactionExecuted(r.ctx, preparedTx.BatchHash, preparedTx.Kind, txHash); err != nil {
+			log.Error("Failed to mark the prepared transaction as executed", "kind", preparedTx.Kind, "batch hash", preparedTx.BatchHash, "err", err)
+			continue
+		}
+		r.metrics.rollupL2RelayerPreparedTransactionExecutedTotal.WithLabelValues(preparedTx.Kind).Inc()
+		if preparedTx.Kind == committeeCallKindFinalize {
+			r.observeBatchLatency(preparedTx.BatchHash)
+		}
+		log.Info("The prepared multisig transaction is executed", "kind", preparedTx.Kind, "batch index", preparedTx.BatchIndex, "batch hash", preparedTx.BatchHash, "tx hash", txHash)
+	}
+}
@@ -0,0 +1,114 @@
+package relayer
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/database"
+	"scroll-tech/common/types"
+	"scroll-tech/common/types/encoding"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+)
+
+func testL2RelayerMultisig(t *testing.T) {
+	db := setupL2RelayerDB(t)
+	defer database.CloseDB(db)
+
+	safeAddress := common.HexToAddress("0x5afe")
+	relayerCfg := *cfg.L2Config.RelayerConfig
+	relayerCfg.MultisigConfig = &config.MultisigConfig{Mode: config.MultisigModeSafe, Address: safeAddress}
+	relayer, err := NewLayer2Relayer(context.Background(), l2Cli, db, &relayerCfg, &params.ChainConfig{}, true, ServiceTypeL2RollupRelayer, nil)
+	assert.NoError(t, err)
+	defer relayer.StopSenders()
+
+	l2BlockOrm := orm.NewL2Block(db)
+	assert.NoError(t, l2BlockOrm.InsertL2Blocks(context.Background(), []*encoding.Block{block1, block2}))
+	chunkOrm := orm.NewChunk(db)
+	_, err = chunkOrm.InsertChunk(context.Background(), chunk1, encoding.CodecV0)
+	assert.NoError(t, err)
+	_, err = chunkOrm.InsertChunk(context.Background(), chunk2, encoding.CodecV0)
+	assert.NoError(t, err)
+
+	batchOrm := orm.NewBatch(db)
+	batch := &encoding.Batch{
+		Index:                      1,
+		TotalL1MessagePoppedBefore: 0,
+		ParentBatchHash:            common.Hash{},
+		Chunks:                     []*encoding.Chunk{chunk1, chunk2},
+	}
+	dbBatch, err := batchOrm.InsertBatch(context.Background(), batch, encoding.CodecV0)
+	assert.NoError(t, err)
+
+	// the commit is prepared for the Safe instead of being sent.
+	relayer.ProcessPendingBatches()
+	statuses, err := batchOrm.GetRollupStatusByHashList(context.Background(), []string{dbBatch.Hash})
+	assert.NoError(t, err)
+	assert.Equal(t, []types.RollupStatus{types.RollupCommitting}, statuses)
+
+	preparedTxOrm := orm.NewPreparedTransaction(db)
+	preparedTxs, err := preparedTxOrm.GetPreparedTransactions(context.Background(), orm.PreparedTransactionStatusPrepared, 0)
+	assert.NoError(t, err)
+	assert.Len(t, preparedTxs, 1)
+	assert.Equal(t, committeeCallKindCommit, preparedTxs[0].Kind)
+
+	var safeBatch safeTransactionBatch
+	assert.NoError(t, json.Unmarshal([]byte(preparedTxs[0].Payload), &safeBatch))
+	assert.Equal(t, safeAddress, safeBatch.Meta.CreatedFromSafeAddress)
+	assert.Len(t, safeBatch.Transactions, 1)
+	assert.Equal(t, relayerCfg.RollupContractAddress, safeBatch.Transactions[0].To)
+	method, err := relayer.l1RollupABI.MethodById(safeBatch.Transactions[0].Data)
+	assert.NoError(t, err)
+	assert.Equal(t, "commitBatch", method.Name)
+
+	// the prepared transaction is executed once the L1 watcher imports the commit event.
+	relayer.ProcessPreparedTransactions()
+	preparedTxs, err = preparedTxOrm.GetPreparedTransactions(context.Background(), orm.PreparedTransactionStatusPrepared, 0)
+	assert.NoError(t, err)
+	assert.Len(t, preparedTxs, 1)
+
+	commitTxHash := common.HexToHash("0x01").String()
+	assert.NoError(t, batchOrm.UpdateCommitTxHashAndRollupStatus(context.Background(), dbBatch.Hash, commitTxHash, types.RollupCommitted))
+	relayer.ProcessPreparedTransactions()
+	preparedTxs, err = preparedTxOrm.GetPreparedTransactions(context.Background(), orm.PreparedTransactionStatusExecuted, 0)
+	assert.NoError(t, err)
+	assert.Len(t, preparedTxs, 1)
+	assert.Equal(t, commitTxHash, preparedTxs[0].TxHash)
+}
+
+func testMultisigTimelockPayload(t *testing.T) {
+	timelockAddress := common.HexToAddress("0x7153")
+	m, err := newMultisig(&config.MultisigConfig{Mode: config.MultisigModeTimelock, Address: timelockAddress, TimelockDelaySec: 3600})
+	assert.NoError(t, err)
+
+	target := common.HexToAddress("0x1234")
+	calldata := []byte{0xde, 0xad, 0xbe, 0xef}
+	dbBatch := &orm.Batch{Index: 7, Hash: common.HexToHash("0x07").String()}
+	payload, err := m.payload(big.NewInt(1), committeeCallKindFinalize, dbBatch, target, calldata)
+	assert.NoError(t, err)
+
+	var operation timelockOperation
+	assert.NoError(t, json.Unmarshal(payload, &operation))
+	assert.Equal(t, "1", operation.ChainID)
+	assert.Equal(t, uint64(3600), operation.Delay)
+	assert.Equal(t, timelockAddress, operation.Schedule.To)
+	assert.Equal(t, timelockAddress, operation.Execute.To)
+
+	// both calls target the same operation, salted by the batch hash.
+	schedule, err := m.timelockABI.Methods["schedule"].Inputs.Unpack(operation.Schedule.Data[4:])
+	assert.NoError(t, err)
+	execute, err := m.timelockABI.Methods["execute"].Inputs.Unpack(operation.Execute.Data[4:])
+	assert.NoError(t, err)
+	assert.Equal(t, schedule[:5], execute)
+	assert.Equal(t, target, execute[0])
+	assert.Equal(t, calldata, execute[2])
+	assert.Equal(t, [32]byte(common.HexToHash(dbBatch.Hash)), execute[4])
+	assert.Equal(t, big.NewInt(3600), schedule[5])
+}
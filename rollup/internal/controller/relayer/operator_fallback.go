@@ -0,0 +1,114 @@
+package relayer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/common/utils"
+
+	"scroll-tech/rollup/internal/orm"
+)
+
+// isCommitLeftToOtherOperators returns true if the commit of the batch is left to the other operators: it's still in
+// its commit grace period, or it's already committed on L1 and the L1 watcher hasn't imported its commit yet.
+// A batch committed by another operator with a different hash trips the commit circuit breaker, since the chains of
+// the operators diverged.
+func (r *Layer2Relayer) isCommitLeftToOtherOperators(dbBatch *orm.Batch) (bool, error) {
+	held, err := r.commitHeldForOtherOperators(dbBatch)
+	if err == nil {
+		r.setOperatorFallbackHeld(CommitCircuitBreakerName, dbBatch, held)
+	}
+	return held, err
+}
+
+func (r *Layer2Relayer) commitHeldForOtherOperators(dbBatch *orm.Batch) (bool, error) {
+	fallbackCfg := r.cfg.OperatorFallbackConfig
+	if fallbackCfg == nil {
+		return false, nil
+	}
+	if r.isInGracePeriod(CommitCircuitBreakerName, dbBatch, dbBatch.CreatedAt, fallbackCfg.CommitGracePeriodSec) {
+		return true, nil
+	}
+	if r.sequencingGuardClient == nil {
+		return false, nil
+	}
+
+	committedHash, err := r.committedBatchHash(dbBatch.Index)
+	if err != nil {
+		return false, err
+	}
+	switch committedHash {
+	case common.Hash{}:
+		log.Info("batch not committed by the other operators after its grace period, committing it", "index", dbBatch.Index, "hash", dbBatch.Hash)
+		return false, nil
+	case common.HexToHash(dbBatch.Hash):
+		log.Info("batch committed by another operator, waiting for the L1 watcher to import its commit", "index", dbBatch.Index, "hash", dbBatch.Hash)
+	default:
+		r.tripCircuitBreaker(r.commitCircuitBreaker, fmt.Sprintf("batch %d committed by another operator with hash %s instead of %s", dbBatch.Index, committedHash.Hex(), dbBatch.Hash))
+	}
+	return true, nil
+}
+
+// isFinalizeLeftToOtherOperators returns true if the finalization of the batch is left to the other operators: it's
+// still in its finalize grace period, counted from when it was both committed and proven, or it's already finalized
+// on L1 and the L1 watcher hasn't imported its finalization yet.
+func (r *Layer2Relayer) isFinalizeLeftToOtherOperators(dbBatch *orm.Batch) (bool, error) {
+	held, err := r.finalizeHeldForOtherOperators(dbBatch)
+	if err == nil {
+		r.setOperatorFallbackHeld(FinalizeCircuitBreakerName, dbBatch, held)
+	}
+	return held, err
+}
+
+func (r *Layer2Relayer) finalizeHeldForOtherOperators(dbBatch *orm.Batch) (bool, error) {
+	fallbackCfg := r.cfg.OperatorFallbackConfig
+	if fallbackCfg == nil {
+		return false, nil
+	}
+	if dbBatch.CommittedAt == nil || dbBatch.ProvedAt == nil {
+		return false, nil
+	}
+	finalizableAt := *dbBatch.CommittedAt
+	if dbBatch.ProvedAt.After(finalizableAt) {
+		finalizableAt = *dbBatch.ProvedAt
+	}
+	if r.isInGracePeriod(FinalizeCircuitBreakerName, dbBatch, finalizableAt, fallbackCfg.FinalizeGracePeriodSec) {
+		return true, nil
+	}
+	if r.sequencingGuardClient == nil {
+		return false, nil
+	}
+
+	lastFinalizedIndex, err := r.lastFinalizedBatchIndex()
+	if err != nil {
+		return false, err
+	}
+	if lastFinalizedIndex < dbBatch.Index {
+		log.Info("batch not finalized by the other operators after its grace period, finalizing it", "index", dbBatch.Index, "hash", dbBatch.Hash)
+		return false, nil
+	}
+	log.Info("batch finalized by another operator, waiting for the L1 watcher to import its finalization", "index", dbBatch.Index, "hash", dbBatch.Hash, "last finalized batch", lastFinalizedIndex)
+	return true, nil
+}
+
+// setOperatorFallbackHeld exports the index of the batch the pipeline leaves to the other operators, 0 if none,
+// so that a batch held across many polls is observed once.
+func (r *Layer2Relayer) setOperatorFallbackHeld(pipeline string, dbBatch *orm.Batch, held bool) {
+	var index uint64
+	if held {
+		index = dbBatch.Index
+	}
+	r.metrics.rollupL2RelayerOperatorFallbackHeldBatchIndex.WithLabelValues(pipeline).Set(float64(index))
+}
+
+// isInGracePeriod returns true if fewer than gracePeriodSec seconds passed since the given time.
+func (r *Layer2Relayer) isInGracePeriod(pipeline string, dbBatch *orm.Batch, since time.Time, gracePeriodSec uint64) bool {
+	if utils.NowUTC().Sub(since) >= time.Duration(gracePeriodSec)*time.Second {
+		return false
+	}
+	log.Debug("batch is in the grace period of the other operators", "pipeline", pipeline, "index", dbBatch.Index, "hash", dbBatch.Hash, "since", since, "grace period sec", gracePeriodSec)
+	return true
+}
@@ -0,0 +1,92 @@
+package relayer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/database"
+	"scroll-tech/common/utils"
+
+	bridgeAbi "scroll-tech/rollup/abi"
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+)
+
+func testOperatorFallback(t *testing.T) {
+	db := setupL2RelayerDB(t)
+	defer database.CloseDB(db)
+
+	now := utils.NowUTC()
+	batch := &orm.Batch{Index: 2, Hash: common.HexToHash("0x02").Hex(), CreatedAt: now.Add(-time.Minute)}
+	client := &mockSequencingGuardClient{committedBatches: map[uint64]common.Hash{1: common.HexToHash("0x01")}, lastFinalizedIndex: 1}
+	r := &Layer2Relayer{
+		ctx:         context.Background(),
+		cfg:         &config.RelayerConfig{},
+		l1RollupABI: bridgeAbi.ScrollChainABI,
+		metrics:     initL2RelayerMetrics(prometheus.NewRegistry()),
+
+		proposerPauseOrm:     orm.NewProposerPause(db),
+		commitCircuitBreaker: newCircuitBreaker(CommitCircuitBreakerName, nil),
+	}
+	heldBatchIndex := func(pipeline string) float64 {
+		return testutil.ToFloat64(r.metrics.rollupL2RelayerOperatorFallbackHeldBatchIndex.WithLabelValues(pipeline))
+	}
+
+	// the transactions aren't left to other operators without the fallback mode.
+	left, err := r.isCommitLeftToOtherOperators(batch)
+	assert.NoError(t, err)
+	assert.False(t, left)
+
+	r.cfg.OperatorFallbackConfig = &config.OperatorFallbackConfig{CommitGracePeriodSec: 120, FinalizeGracePeriodSec: 120}
+	r.SetSequencingGuard(client)
+	left, err = r.isCommitLeftToOtherOperators(batch)
+	assert.NoError(t, err)
+	assert.True(t, left)
+	assert.Equal(t, float64(2), heldBatchIndex(CommitCircuitBreakerName))
+
+	// the batch not committed by the other operators after the grace period is committed.
+	batch.CreatedAt = now.Add(-3 * time.Minute)
+	left, err = r.isCommitLeftToOtherOperators(batch)
+	assert.NoError(t, err)
+	assert.False(t, left)
+	assert.Equal(t, float64(0), heldBatchIndex(CommitCircuitBreakerName))
+
+	// the batch committed by another operator is left to the L1 watcher.
+	client.committedBatches[2] = common.HexToHash("0x02")
+	left, err = r.isCommitLeftToOtherOperators(batch)
+	assert.NoError(t, err)
+	assert.True(t, left)
+	assert.False(t, r.isCircuitOpen(r.commitCircuitBreaker))
+
+	// the batch committed by another operator with a different hash trips the commit circuit breaker.
+	client.committedBatches[2] = common.HexToHash("0x2a")
+	left, err = r.isCommitLeftToOtherOperators(batch)
+	assert.NoError(t, err)
+	assert.True(t, left)
+	assert.True(t, r.isCircuitOpen(r.commitCircuitBreaker))
+
+	// the finalize grace period starts once the batch is both committed and proven.
+	committedAt, provedAt := now.Add(-3*time.Minute), now.Add(-time.Minute)
+	batch.CommittedAt, batch.ProvedAt = &committedAt, &provedAt
+	left, err = r.isFinalizeLeftToOtherOperators(batch)
+	assert.NoError(t, err)
+	assert.True(t, left)
+
+	provedAt = now.Add(-3 * time.Minute)
+	left, err = r.isFinalizeLeftToOtherOperators(batch)
+	assert.NoError(t, err)
+	assert.False(t, left)
+
+	// the batch finalized by another operator is left to the L1 watcher.
+	client.lastFinalizedIndex = 2
+	left, err = r.isFinalizeLeftToOtherOperators(batch)
+	assert.NoError(t, err)
+	assert.True(t, left)
+	assert.Equal(t, float64(2), heldBatchIndex(FinalizeCircuitBreakerName))
+}
@@ -0,0 +1,54 @@
+package relayer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/common/utils"
+
+	"scroll-tech/rollup/internal/orm"
+)
+
+// Reasons of requesting a new proof of a batch.
+const (
+	proofRerequestReasonStale           = "stale"
+	proofRerequestReasonInvalid         = "invalid"
+	proofRerequestReasonVersionMismatch = "version_mismatch"
+)
+
+// isProofStale records the age of the verified proof of the batch about to be finalized, and requests a new proof
+// if it's older than the validity window. It returns true if the proof is stale.
+func (r *Layer2Relayer) isProofStale(dbBatch *orm.Batch) bool {
+	if dbBatch.ProvedAt == nil {
+		return false
+	}
+	age := utils.NowUTC().Sub(*dbBatch.ProvedAt)
+	r.metrics.rollupL2RelayerVerifiedProofAgeSec.Set(age.Seconds())
+
+	freshnessCfg := r.cfg.ProofFreshnessConfig
+	if freshnessCfg == nil || freshnessCfg.ValidityWindowSec == 0 || age <= time.Duration(freshnessCfg.ValidityWindowSec)*time.Second {
+		return false
+	}
+	r.rerequestProof(dbBatch, proofRerequestReasonStale, fmt.Errorf("proof verified %v ago, validity window: %vs", age.Round(time.Second), freshnessCfg.ValidityWindowSec))
+	return true
+}
+
+// rerequestProof drops the proof of the batch and moves it back to unassigned, so that the coordinator assigns it
+// to a prover again and the batch is finalized with the new proof. It's a no-op if the freshness check is disabled.
+func (r *Layer2Relayer) rerequestProof(dbBatch *orm.Batch, reason string, cause error) {
+	if r.cfg.ProofFreshnessConfig == nil {
+		return
+	}
+	reset, err := r.batchOrm.ResetBatchProof(r.ctx, dbBatch.Hash)
+	if err != nil {
+		log.Error("failed to request a new proof of the batch", "index", dbBatch.Index, "hash", dbBatch.Hash, "reason", reason, "err", err)
+		return
+	}
+	if !reset {
+		return
+	}
+	r.metrics.rollupL2RelayerProofRerequestedTotal.WithLabelValues(reason).Inc()
+	log.Warn("requested a new proof of the batch instead of finalizing it", "index", dbBatch.Index, "hash", dbBatch.Hash, "reason", reason, "cause", cause)
+}
@@ -0,0 +1,77 @@
+package relayer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/database"
+	"scroll-tech/common/types"
+	"scroll-tech/common/types/encoding"
+	"scroll-tech/common/types/message"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+)
+
+func testL2RelayerProofFreshness(t *testing.T) {
+	db := setupL2RelayerDB(t)
+	defer database.CloseDB(db)
+
+	relayerCfg := *cfg.L2Config.RelayerConfig
+	relayerCfg.ProofFreshnessConfig = &config.ProofFreshnessConfig{ValidityWindowSec: 3600}
+	relayer, err := NewLayer2Relayer(context.Background(), l2Cli, db, &relayerCfg, &params.ChainConfig{}, true, ServiceTypeL2RollupRelayer, nil)
+	assert.NoError(t, err)
+	defer relayer.StopSenders()
+
+	l2BlockOrm := orm.NewL2Block(db)
+	assert.NoError(t, l2BlockOrm.InsertL2Blocks(context.Background(), []*encoding.Block{block1, block2}))
+	chunkOrm := orm.NewChunk(db)
+	_, err = chunkOrm.InsertChunk(context.Background(), chunk1, encoding.CodecV0)
+	assert.NoError(t, err)
+	_, err = chunkOrm.InsertChunk(context.Background(), chunk2, encoding.CodecV0)
+	assert.NoError(t, err)
+
+	batchOrm := orm.NewBatch(db)
+	batch := &encoding.Batch{
+		Index:                      1,
+		TotalL1MessagePoppedBefore: 0,
+		ParentBatchHash:            common.Hash{},
+		Chunks:                     []*encoding.Chunk{chunk1, chunk2},
+	}
+	dbBatch, err := batchOrm.InsertBatch(context.Background(), batch, encoding.CodecV0)
+	assert.NoError(t, err)
+	assert.NoError(t, batchOrm.UpdateRollupStatus(context.Background(), dbBatch.Hash, types.RollupCommitted))
+
+	provingStatus := func() types.ProvingStatus {
+		status, getErr := batchOrm.GetBatchByHash(context.Background(), dbBatch.Hash)
+		assert.NoError(t, getErr)
+		return types.ProvingStatus(status.ProvingStatus)
+	}
+
+	// a proof failing the pre-submit check is requested again.
+	assert.NoError(t, batchOrm.UpdateProvingStatus(context.Background(), dbBatch.Hash, types.ProvingTaskVerified))
+	assert.NoError(t, batchOrm.UpdateProofByHash(context.Background(), dbBatch.Hash, &message.BatchProof{Proof: []byte{0, 1, 2}}, 100))
+	relayer.ProcessCommittedBatches()
+	assert.Equal(t, types.ProvingTaskUnassigned, provingStatus())
+
+	// so is a proof verified before the validity window.
+	assert.NoError(t, batchOrm.UpdateProvingStatus(context.Background(), dbBatch.Hash, types.ProvingTaskVerified))
+	assert.NoError(t, batchOrm.UpdateProofByHash(context.Background(), dbBatch.Hash, &message.BatchProof{Proof: make([]byte, 32)}, 100))
+	assert.NoError(t, db.Model(&orm.Batch{}).Where("hash = ?", dbBatch.Hash).Update("proved_at", time.Now().Add(-2*time.Hour)).Error)
+	relayer.ProcessCommittedBatches()
+	assert.Equal(t, types.ProvingTaskUnassigned, provingStatus())
+
+	// a fresh proof finalizes the batch.
+	assert.NoError(t, batchOrm.UpdateProvingStatus(context.Background(), dbBatch.Hash, types.ProvingTaskVerified))
+	assert.NoError(t, batchOrm.UpdateProofByHash(context.Background(), dbBatch.Hash, &message.BatchProof{Proof: make([]byte, 32)}, 100))
+	relayer.ProcessCommittedBatches()
+	assert.Equal(t, types.ProvingTaskVerified, provingStatus())
+	statuses, err := batchOrm.GetRollupStatusByHashList(context.Background(), []string{dbBatch.Hash})
+	assert.NoError(t, err)
+	assert.Equal(t, []types.RollupStatus{types.RollupFinalizing}, statuses)
+}
@@ -0,0 +1,67 @@
+package relayer
+
+import (
+	"fmt"
+
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/common/types/encoding"
+	"scroll-tech/common/types/message"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+)
+
+// expectedProofVersion returns the proof version the rollup verifier routes the batch to, nil if no version is
+// registered for its batch version.
+func expectedProofVersion(proofVersions []*config.ProofVersionConfig, batchVersion uint8, batchIndex uint64) *config.ProofVersionConfig {
+	var expected *config.ProofVersionConfig
+	for _, proofVersion := range proofVersions {
+		if proofVersion.VerifierVersion != batchVersion || proofVersion.StartBatchIndex > batchIndex {
+			continue
+		}
+		if expected == nil || proofVersion.StartBatchIndex > expected.StartBatchIndex {
+			expected = proofVersion
+		}
+	}
+	return expected
+}
+
+// proofVersionOf returns the version of the proof from its verifying key, nil if the key isn't of a known version.
+func proofVersionOf(proofVersions []*config.ProofVersionConfig, proof *message.BatchProof) *config.ProofVersionConfig {
+	vkHash := crypto.Keccak256Hash(proof.Vk)
+	for _, proofVersion := range proofVersions {
+		if proofVersion.VkHash == vkHash {
+			return proofVersion
+		}
+	}
+	return nil
+}
+
+// finalizeCodecVersion returns the codec version whose finalize call carries the proof of the batch, the one of the
+// verifier of the proof version if the versions are configured. The proof is rejected, and a new one requested, if
+// it isn't of the version the verifier of the batch expects.
+func (r *Layer2Relayer) finalizeCodecVersion(dbBatch *orm.Batch, codecVersion encoding.CodecVersion, proof *message.BatchProof) (encoding.CodecVersion, error) {
+	if len(r.cfg.ProofVersions) == 0 || proof == nil {
+		return codecVersion, nil
+	}
+
+	expected := expectedProofVersion(r.cfg.ProofVersions, uint8(codecVersion), dbBatch.Index)
+	if expected == nil {
+		return 0, fmt.Errorf("no proof version registered for batch version %v and batch %v", codecVersion, dbBatch.Index)
+	}
+	actual := proofVersionOf(r.cfg.ProofVersions, proof)
+	if actual != expected {
+		actualName := "unknown"
+		if actual != nil {
+			actualName = actual.Name
+		}
+		err := fmt.Errorf("proof of batch %v is of version %v, its verifier expects version %v", dbBatch.Index, actualName, expected.Name)
+		r.metrics.rollupL2RelayerProofVersionMismatchTotal.WithLabelValues(expected.Name).Inc()
+		log.Error("refusing to finalize the batch with a proof of another version", "index", dbBatch.Index, "hash", dbBatch.Hash, "proof version", actualName, "expected version", expected.Name)
+		r.rerequestProof(dbBatch, proofRerequestReasonVersionMismatch, err)
+		return 0, err
+	}
+	return encoding.CodecVersion(actual.VerifierVersion), nil
+}
@@ -0,0 +1,48 @@
+package relayer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/types/encoding"
+	"scroll-tech/common/types/message"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+)
+
+func testProofVersion(t *testing.T) {
+	legacy := &config.ProofVersionConfig{Name: "curie-legacy", VkHash: crypto.Keccak256Hash([]byte("vk1")), VerifierVersion: 1, StartBatchIndex: 0}
+	upgraded := &config.ProofVersionConfig{Name: "curie", VkHash: crypto.Keccak256Hash([]byte("vk2")), VerifierVersion: 1, StartBatchIndex: 100}
+	r := &Layer2Relayer{
+		ctx:     context.Background(),
+		cfg:     &config.RelayerConfig{},
+		metrics: initL2RelayerMetrics(prometheus.NewRegistry()),
+	}
+
+	// the proofs aren't checked without versions.
+	codecVersion, err := r.finalizeCodecVersion(&orm.Batch{Index: 100}, encoding.CodecV1, &message.BatchProof{Vk: []byte("vk1")})
+	assert.NoError(t, err)
+	assert.Equal(t, encoding.CodecV1, codecVersion)
+
+	r.cfg.ProofVersions = []*config.ProofVersionConfig{upgraded, legacy}
+	assert.Equal(t, legacy, expectedProofVersion(r.cfg.ProofVersions, 1, 99))
+	assert.Equal(t, upgraded, expectedProofVersion(r.cfg.ProofVersions, 1, 100))
+	assert.Nil(t, expectedProofVersion(r.cfg.ProofVersions, 0, 100))
+
+	codecVersion, err = r.finalizeCodecVersion(&orm.Batch{Index: 100}, encoding.CodecV1, &message.BatchProof{Vk: []byte("vk2")})
+	assert.NoError(t, err)
+	assert.Equal(t, encoding.CodecV1, codecVersion)
+
+	// a proof of the legacy verifier, of an unknown version or of a batch version without verifier is rejected.
+	_, err = r.finalizeCodecVersion(&orm.Batch{Index: 100}, encoding.CodecV1, &message.BatchProof{Vk: []byte("vk1")})
+	assert.Error(t, err)
+	_, err = r.finalizeCodecVersion(&orm.Batch{Index: 100}, encoding.CodecV1, &message.BatchProof{Vk: []byte("vk3")})
+	assert.Error(t, err)
+	_, err = r.finalizeCodecVersion(&orm.Batch{Index: 100}, encoding.CodecV0, &message.BatchProof{Vk: []byte("vk2")})
+	assert.Error(t, err)
+}
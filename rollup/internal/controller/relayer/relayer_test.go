@@ -123,12 +123,33 @@ func TestFunctions(t *testing.T) {
 	// Run l2 relayer test cases.
 	t.Run("TestCreateNewRelayer", testCreateNewRelayer)
 	t.Run("TestL2RelayerProcessPendingBatches", testL2RelayerProcessPendingBatches)
+	t.Run("TestL2RelayerProcessPendingBatchesInFlightLimit", testL2RelayerProcessPendingBatchesInFlightLimit)
 	t.Run("TestL2RelayerProcessCommittedBatches", testL2RelayerProcessCommittedBatches)
 	t.Run("TestL2RelayerFinalizeTimeoutBatches", testL2RelayerFinalizeTimeoutBatches)
 	t.Run("TestL2RelayerCommitConfirm", testL2RelayerCommitConfirm)
+	t.Run("TestL2RelayerCommitConfirmRebatch", testL2RelayerCommitConfirmRebatch)
+	t.Run("TestL2RelayerCommitConfirmCircuitBreaker", testL2RelayerCommitConfirmCircuitBreaker)
+	t.Run("TestClassifyRevert", testClassifyRevert)
 	t.Run("TestL2RelayerFinalizeConfirm", testL2RelayerFinalizeConfirm)
+	t.Run("TestL2RelayerFinalizeCrossCheck", testL2RelayerFinalizeCrossCheck)
+	t.Run("TestL2RelayerProofFreshness", testL2RelayerProofFreshness)
+	t.Run("TestStageLatencies", testStageLatencies)
 	t.Run("TestL2RelayerGasOracleConfirm", testL2RelayerGasOracleConfirm)
 	t.Run("TestLayer2RelayerProcessGasPriceOracle", testLayer2RelayerProcessGasPriceOracle)
 	// test getBatchStatusByIndex
 	t.Run("TestGetBatchStatusByIndex", testGetBatchStatusByIndex)
+	t.Run("TestCommitteeWrap", testCommitteeWrap)
+	t.Run("TestL2RelayerMultisig", testL2RelayerMultisig)
+	t.Run("TestMultisigTimelockPayload", testMultisigTimelockPayload)
+	t.Run("TestSkippedQueueIndices", testSkippedQueueIndices)
+	t.Run("TestL2RelayerCommitDryRun", testL2RelayerCommitDryRun)
+	t.Run("TestSequencingGuard", testSequencingGuard)
+	t.Run("TestDABackend", testDABackend)
+	t.Run("TestDABackendCommitCalldata", testDABackendCommitCalldata)
+	t.Run("TestOperatorFallback", testOperatorFallback)
+	t.Run("TestProofVersion", testProofVersion)
+	t.Run("TestBatchRevert", testBatchRevert)
+
+	// Run batch re-encoder test cases.
+	t.Run("TestBatchReencoderReencode", testBatchReencoderReencode)
 }
@@ -0,0 +1,117 @@
+package relayer
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/scroll-tech/go-ethereum/accounts/abi"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/common/types"
+
+	"scroll-tech/rollup/internal/controller/sender"
+)
+
+// revertClass is the class of the revert reason of a failed commit or finalize transaction, deciding how it's handled.
+type revertClass string
+
+const (
+	// revertClassUnknown is a revert reason without a specific policy, the batch is retried as before.
+	revertClassUnknown revertClass = "unknown"
+	// revertClassBatchContent is a batch rejected for its content, dissolved by the auto re-batching if it matches one
+	// of its revert errors.
+	revertClassBatchContent revertClass = "batch_content"
+	// revertClassParentMismatch is a batch sent before its parent landed, retried after it.
+	revertClassParentMismatch revertClass = "parent_mismatch"
+	// revertClassAlreadyDone is a batch already committed or finalized, e.g. by a replaced transaction.
+	// Its status is left as is and reconciled from the rollup events by the L1 watcher.
+	revertClassAlreadyDone revertClass = "already_done"
+	// revertClassUnauthorized is a sender not allowed by the rollup contract, the pipeline is aborted.
+	revertClassUnauthorized revertClass = "unauthorized"
+	// revertClassPaused is a paused rollup contract, the pipeline is aborted.
+	revertClassPaused revertClass = "paused"
+)
+
+// classifiedRevertError is a rollup contract error along with its class.
+type classifiedRevertError struct {
+	name  string
+	class revertClass
+}
+
+// revertErrors maps the selectors of the classified rollup contract errors to them.
+var revertErrors = func() map[[4]byte]classifiedRevertError {
+	classes := map[string]revertClass{
+		"ErrorIncorrectBatchHash":         revertClassParentMismatch,
+		"ErrorIncorrectBatchIndex":        revertClassParentMismatch,
+		"ErrorIncorrectPreviousStateRoot": revertClassParentMismatch,
+		"ErrorBatchIsAlreadyCommitted":    revertClassAlreadyDone,
+		"ErrorBatchIsAlreadyVerified":     revertClassAlreadyDone,
+		"ErrorCallerIsNotSequencer":       revertClassUnauthorized,
+		"ErrorCallerIsNotProver":          revertClassUnauthorized,
+	}
+	for _, name := range append(defaultRebatchRevertErrors, chunkContentRevertErrors...) {
+		classes[name] = revertClassBatchContent
+	}
+
+	errs := make(map[[4]byte]classifiedRevertError, len(classes))
+	for name, class := range classes {
+		var selector [4]byte
+		copy(selector[:], crypto.Keccak256([]byte(name + "()"))[:4])
+		errs[selector] = classifiedRevertError{name: name, class: class}
+	}
+	return errs
+}()
+
+// classifyRevert decodes the revert data of a failed transaction, either a rollup contract error or an
+// Error(string) like the one of a paused contract, and returns its reason and class.
+func classifyRevert(revertData []byte) (string, revertClass) {
+	if reason, err := abi.UnpackRevert(revertData); err == nil {
+		if strings.Contains(reason, "paused") {
+			return reason, revertClassPaused
+		}
+		return reason, revertClassUnknown
+	}
+	if len(revertData) < 4 {
+		return "", revertClassUnknown
+	}
+	var selector [4]byte
+	copy(selector[:], revertData[:4])
+	if revertErr, ok := revertErrors[selector]; ok {
+		return revertErr.name, revertErr.class
+	}
+	return "", revertClassUnknown
+}
+
+// estimationRevert returns the revert reason and class of a transaction not sent as its gas estimation reverted,
+// empty if the error isn't a reverted estimation.
+func estimationRevert(err error) (string, revertClass) {
+	var estimationErr *sender.GasEstimationError
+	if !errors.As(err, &estimationErr) || !estimationErr.Reverted {
+		return "", ""
+	}
+	return classifyRevert(estimationErr.RevertData)
+}
+
+// handleRevert applies the policy of the revert class of a failed commit or finalize transaction of the pipeline,
+// and returns the rollup status recorded for its batch, RollupUndefined if the status is left as is.
+func (r *Layer2Relayer) handleRevert(b *circuitBreaker, cfm *sender.Confirmation, failedStatus types.RollupStatus) types.RollupStatus {
+	reason, class := classifyRevert(cfm.RevertData)
+	r.metrics.rollupL2RelayerRevertTotal.WithLabelValues(b.name, string(class)).Inc()
+	log.Warn("transaction reverted", "pipeline", b.name, "hash", cfm.ContextID, "tx hash", cfm.TxHash.String(), "revert reason", reason, "revert class", class)
+
+	switch class {
+	case revertClassAlreadyDone:
+		return types.RollupUndefined
+	case revertClassUnauthorized, revertClassPaused:
+		if r.tripCircuitBreaker(b, "transaction reverted with "+reason) {
+			b.consecutiveFailures = 0
+		}
+	case revertClassParentMismatch:
+		// the failed commits are retried in batch index order, the failed finalizations aren't.
+		if failedStatus == types.RollupFinalizeFailed {
+			return types.RollupCommitted
+		}
+	}
+	return failedStatus
+}
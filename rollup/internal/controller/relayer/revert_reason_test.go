@@ -0,0 +1,36 @@
+package relayer
+
+import (
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/accounts/abi"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func testClassifyRevert(t *testing.T) {
+	stringType, err := abi.NewType("string", "", nil)
+	assert.NoError(t, err)
+	packed, err := abi.Arguments{{Type: stringType}}.Pack("Pausable: paused")
+	assert.NoError(t, err)
+	pausedRevertData := append(crypto.Keccak256([]byte("Error(string)"))[:4], packed...)
+
+	tests := []struct {
+		revertData []byte
+		reason     string
+		class      revertClass
+	}{
+		{nil, "", revertClassUnknown},
+		{[]byte{0x01, 0x02, 0x03, 0x04}, "", revertClassUnknown},
+		{pausedRevertData, "Pausable: paused", revertClassPaused},
+		{crypto.Keccak256([]byte("ErrorTooManyTxsInOneChunk()"))[:4], "ErrorTooManyTxsInOneChunk", revertClassBatchContent},
+		{crypto.Keccak256([]byte("ErrorIncorrectBatchHash()"))[:4], "ErrorIncorrectBatchHash", revertClassParentMismatch},
+		{crypto.Keccak256([]byte("ErrorBatchIsAlreadyVerified()"))[:4], "ErrorBatchIsAlreadyVerified", revertClassAlreadyDone},
+		{crypto.Keccak256([]byte("ErrorCallerIsNotSequencer()"))[:4], "ErrorCallerIsNotSequencer", revertClassUnauthorized},
+	}
+	for _, test := range tests {
+		reason, class := classifyRevert(test.revertData)
+		assert.Equal(t, test.reason, reason)
+		assert.Equal(t, test.class, class)
+	}
+}
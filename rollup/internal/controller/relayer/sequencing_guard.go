@@ -0,0 +1,129 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/accounts/abi"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/common/types"
+
+	"scroll-tech/rollup/internal/orm"
+)
+
+// sequencingGuardClient is the L1 node the rollup contract state is read from before sending a transaction.
+type sequencingGuardClient interface {
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+}
+
+// SetSequencingGuard sets the L1 node the committed and finalized batches of the rollup contract are read from, so
+// that a commit or finalize transaction is only sent for the next batch expected by the contract. It's disabled if
+// client is nil.
+func (r *Layer2Relayer) SetSequencingGuard(client sequencingGuardClient) {
+	r.sequencingGuardClient = client
+}
+
+// checkCommitSequencing checks that the batch is the next one to commit: it isn't committed on L1 yet, and its
+// parent either is, with the same hash, or has its commit transaction in flight.
+func (r *Layer2Relayer) checkCommitSequencing(dbBatch *orm.Batch, dbParentBatch *orm.Batch) error {
+	if r.sequencingGuardClient == nil {
+		return nil
+	}
+
+	committedHash, err := r.committedBatchHash(dbBatch.Index)
+	if err != nil {
+		return r.refuseOutOfSequence(CommitCircuitBreakerName, dbBatch, err)
+	}
+	if committedHash != (common.Hash{}) {
+		return r.refuseOutOfSequence(CommitCircuitBreakerName, dbBatch, fmt.Errorf("batch %v is already committed on L1 with hash %v", dbBatch.Index, committedHash))
+	}
+
+	parentHash, err := r.committedBatchHash(dbParentBatch.Index)
+	if err != nil {
+		return r.refuseOutOfSequence(CommitCircuitBreakerName, dbBatch, err)
+	}
+	switch {
+	case parentHash == (common.Hash{}) && types.RollupStatus(dbParentBatch.RollupStatus) != types.RollupCommitting:
+		err = fmt.Errorf("parent batch %v is neither committed on L1 nor committing, rollup status: %v", dbParentBatch.Index, types.RollupStatus(dbParentBatch.RollupStatus))
+	case parentHash != (common.Hash{}) && parentHash != common.HexToHash(dbParentBatch.Hash):
+		err = fmt.Errorf("parent batch %v is committed on L1 with hash %v, expected %v", dbParentBatch.Index, parentHash, dbParentBatch.Hash)
+	default:
+		return nil
+	}
+	return r.refuseOutOfSequence(CommitCircuitBreakerName, dbBatch, err)
+}
+
+// checkFinalizeSequencing checks that the batch is the next one to finalize: it's committed on L1 with the same
+// hash, and its parent either is the last finalized batch or has its finalize transaction in flight.
+func (r *Layer2Relayer) checkFinalizeSequencing(dbBatch *orm.Batch, dbParentBatch *orm.Batch) error {
+	if r.sequencingGuardClient == nil {
+		return nil
+	}
+
+	committedHash, err := r.committedBatchHash(dbBatch.Index)
+	if err != nil {
+		return r.refuseOutOfSequence(FinalizeCircuitBreakerName, dbBatch, err)
+	}
+	if committedHash != common.HexToHash(dbBatch.Hash) {
+		return r.refuseOutOfSequence(FinalizeCircuitBreakerName, dbBatch, fmt.Errorf("batch %v is committed on L1 with hash %v, expected %v", dbBatch.Index, committedHash, dbBatch.Hash))
+	}
+
+	lastFinalizedIndex, err := r.lastFinalizedBatchIndex()
+	if err != nil {
+		return r.refuseOutOfSequence(FinalizeCircuitBreakerName, dbBatch, err)
+	}
+	switch {
+	case lastFinalizedIndex >= dbBatch.Index:
+		err = fmt.Errorf("batch %v is already finalized on L1, last finalized batch: %v", dbBatch.Index, lastFinalizedIndex)
+	case lastFinalizedIndex+1 < dbBatch.Index && types.RollupStatus(dbParentBatch.RollupStatus) != types.RollupFinalizing:
+		err = fmt.Errorf("batch %v is expected to be finalized next, parent batch %v isn't finalizing, rollup status: %v", lastFinalizedIndex+1, dbParentBatch.Index, types.RollupStatus(dbParentBatch.RollupStatus))
+	default:
+		return nil
+	}
+	return r.refuseOutOfSequence(FinalizeCircuitBreakerName, dbBatch, err)
+}
+
+// refuseOutOfSequence reports a transaction not sent by the sequencing guard, and returns the reason.
+func (r *Layer2Relayer) refuseOutOfSequence(pipeline string, dbBatch *orm.Batch, reason error) error {
+	r.metrics.rollupL2RelayerSequencingRefusedTotal.WithLabelValues(pipeline).Inc()
+	log.Error("refusing to send the transaction, the batch isn't the next one expected by the rollup contract", "pipeline", pipeline, "index", dbBatch.Index, "hash", dbBatch.Hash, "err", reason)
+	return fmt.Errorf("sequencing guard refused batch %v: %w", dbBatch.Index, reason)
+}
+
+// committedBatchHash returns the hash of the batch committed on L1, zero if the batch isn't committed.
+func (r *Layer2Relayer) committedBatchHash(index uint64) (common.Hash, error) {
+	output, err := r.callRollupContract("committedBatches", new(big.Int).SetUint64(index))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.Hash(*abi.ConvertType(output[0], new([32]byte)).(*[32]byte)), nil
+}
+
+// lastFinalizedBatchIndex returns the index of the last batch finalized on L1.
+func (r *Layer2Relayer) lastFinalizedBatchIndex() (uint64, error) {
+	output, err := r.callRollupContract("lastFinalizedBatchIndex")
+	if err != nil {
+		return 0, err
+	}
+	return abi.ConvertType(output[0], new(big.Int)).(*big.Int).Uint64(), nil
+}
+
+func (r *Layer2Relayer) callRollupContract(method string, args ...interface{}) ([]interface{}, error) {
+	input, err := r.l1RollupABI.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack %v: %w", method, err)
+	}
+	output, err := r.sequencingGuardClient.CallContract(r.ctx, ethereum.CallMsg{To: &r.cfg.RollupContractAddress, Data: input}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %v on the rollup contract: %w", method, err)
+	}
+	unpacked, err := r.l1RollupABI.Unpack(method, output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack %v: %w", method, err)
+	}
+	return unpacked, nil
+}
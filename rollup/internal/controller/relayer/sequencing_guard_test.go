@@ -0,0 +1,86 @@
+package relayer
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/types"
+
+	bridgeAbi "scroll-tech/rollup/abi"
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+)
+
+type mockSequencingGuardClient struct {
+	committedBatches   map[uint64]common.Hash
+	lastFinalizedIndex uint64
+}
+
+func (c *mockSequencingGuardClient) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	method, err := bridgeAbi.ScrollChainABI.MethodById(msg.Data)
+	if err != nil {
+		return nil, err
+	}
+	if method.Name == "lastFinalizedBatchIndex" {
+		return method.Outputs.Pack(new(big.Int).SetUint64(c.lastFinalizedIndex))
+	}
+	args, err := method.Inputs.Unpack(msg.Data[4:])
+	if err != nil {
+		return nil, err
+	}
+	return method.Outputs.Pack([32]byte(c.committedBatches[args[0].(*big.Int).Uint64()]))
+}
+
+func testSequencingGuard(t *testing.T) {
+	parent := &orm.Batch{Index: 1, Hash: common.HexToHash("0x01").Hex(), RollupStatus: int16(types.RollupCommitted)}
+	batch := &orm.Batch{Index: 2, Hash: common.HexToHash("0x02").Hex(), RollupStatus: int16(types.RollupPending)}
+	client := &mockSequencingGuardClient{committedBatches: map[uint64]common.Hash{0: common.HexToHash("0x00ff"), 1: common.HexToHash("0x01")}}
+	r := &Layer2Relayer{
+		ctx:         context.Background(),
+		cfg:         &config.RelayerConfig{},
+		l1RollupABI: bridgeAbi.ScrollChainABI,
+		metrics:     initL2RelayerMetrics(prometheus.NewRegistry()),
+	}
+
+	// the guard is disabled without an L1 node.
+	assert.NoError(t, r.checkCommitSequencing(batch, &orm.Batch{Index: 1}))
+
+	r.SetSequencingGuard(client)
+	assert.NoError(t, r.checkCommitSequencing(batch, parent))
+
+	// the parent committed on L1 with another hash, e.g. after a reorg of the database.
+	client.committedBatches[1] = common.HexToHash("0x0f")
+	assert.Error(t, r.checkCommitSequencing(batch, parent))
+
+	// the parent isn't committed on L1, only its commit in flight is followed by the batch.
+	delete(client.committedBatches, 1)
+	assert.Error(t, r.checkCommitSequencing(batch, parent))
+	parent.RollupStatus = int16(types.RollupCommitting)
+	assert.NoError(t, r.checkCommitSequencing(batch, parent))
+
+	// the batch is already committed.
+	client.committedBatches[1] = common.HexToHash("0x01")
+	client.committedBatches[2] = common.HexToHash("0x02")
+	assert.Error(t, r.checkCommitSequencing(batch, parent))
+
+	// the batch is finalized after its parent.
+	parent.RollupStatus = int16(types.RollupCommitted)
+	client.lastFinalizedIndex = 1
+	assert.NoError(t, r.checkFinalizeSequencing(batch, parent))
+	client.lastFinalizedIndex = 2
+	assert.Error(t, r.checkFinalizeSequencing(batch, parent))
+	client.lastFinalizedIndex = 0
+	assert.Error(t, r.checkFinalizeSequencing(batch, parent))
+	parent.RollupStatus = int16(types.RollupFinalizing)
+	assert.NoError(t, r.checkFinalizeSequencing(batch, parent))
+
+	// the batch committed on L1 with another hash isn't finalized.
+	client.committedBatches[2] = common.HexToHash("0x0e")
+	assert.Error(t, r.checkFinalizeSequencing(batch, parent))
+}
@@ -0,0 +1,81 @@
+package relayer
+
+import (
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/common/types/encoding"
+
+	"scroll-tech/rollup/internal/orm"
+)
+
+// skippedQueueIndices returns the queue indices of the L1 messages skipped by the chunks of a batch, i.e. the
+// ones marked in its skipped bitmap, the gaps between the L1 messages included from totalL1MessagePoppedBefore on.
+func skippedQueueIndices(chunks []*encoding.Chunk, totalL1MessagePoppedBefore uint64) []uint64 {
+	var skipped []uint64
+	nextIndex := totalL1MessagePoppedBefore
+	for _, chunk := range chunks {
+		for _, block := range chunk.Blocks {
+			for _, tx := range block.Transactions {
+				if tx.Type != gethTypes.L1MessageTxType || tx.Nonce < nextIndex {
+					continue
+				}
+				for queueIndex := nextIndex; queueIndex < tx.Nonce; queueIndex++ {
+					skipped = append(skipped, queueIndex)
+				}
+				nextIndex = tx.Nonce + 1
+			}
+		}
+	}
+	return skipped
+}
+
+// recordSkippedL1Messages records the L1 messages skipped by the batch, along with the skip reasons reported by
+// l2geth. The messages are recorded on a best effort basis, failures are logged and don't hold the commit.
+func (r *Layer2Relayer) recordSkippedL1Messages(dbBatch *orm.Batch, dbChunks []*orm.Chunk, chunks []*encoding.Chunk) {
+	queueIndices := skippedQueueIndices(chunks, dbChunks[0].TotalL1MessagesPoppedBefore)
+	if len(queueIndices) == 0 {
+		return
+	}
+
+	l1Messages, err := r.l1MessageOrm.GetL1MessagesByQueueIndices(r.ctx, queueIndices)
+	if err != nil {
+		log.Error("failed to get the skipped L1 messages", "batch index", dbBatch.Index, "batch hash", dbBatch.Hash, "err", err)
+		return
+	}
+	msgHashes := make(map[uint64]string, len(l1Messages))
+	for _, l1Message := range l1Messages {
+		msgHashes[l1Message.QueueIndex] = l1Message.MsgHash
+	}
+
+	skippedMessages := make([]*orm.SkippedL1Message, 0, len(queueIndices))
+	for _, queueIndex := range queueIndices {
+		skippedMessage := &orm.SkippedL1Message{
+			QueueIndex: queueIndex,
+			MsgHash:    msgHashes[queueIndex],
+			BatchIndex: dbBatch.Index,
+			BatchHash:  dbBatch.Hash,
+		}
+		// the skipped L1 message transaction has the hash of the message.
+		if skippedMessage.MsgHash != "" {
+			skippedTx, err := r.l2Client.GetSkippedTransaction(r.ctx, common.HexToHash(skippedMessage.MsgHash))
+			if err != nil {
+				log.Warn("failed to get the skip reason of the L1 message", "queue index", queueIndex, "msg hash", skippedMessage.MsgHash, "err", err)
+			} else if skippedTx != nil {
+				skippedMessage.SkipReason = skippedTx.SkipReason
+				if skippedTx.SkipBlockNumber != nil {
+					skippedMessage.SkipBlockNumber = skippedTx.SkipBlockNumber.ToInt().Uint64()
+				}
+			}
+		}
+		skippedMessages = append(skippedMessages, skippedMessage)
+	}
+
+	if err = r.skippedL1MessageOrm.UpsertSkippedL1Messages(r.ctx, skippedMessages); err != nil {
+		log.Error("failed to record the skipped L1 messages", "batch index", dbBatch.Index, "batch hash", dbBatch.Hash, "err", err)
+		return
+	}
+	r.metrics.rollupL2RelayerSkippedL1MessagesTotal.Add(float64(len(skippedMessages)))
+	log.Warn("the batch skips L1 messages", "batch index", dbBatch.Index, "batch hash", dbBatch.Hash, "queue indices", queueIndices)
+}
@@ -0,0 +1,28 @@
+package relayer
+
+import (
+	"testing"
+
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/types/encoding"
+)
+
+func testSkippedQueueIndices(t *testing.T) {
+	block := func(queueIndices ...uint64) *encoding.Block {
+		b := &encoding.Block{Transactions: []*gethTypes.TransactionData{{Type: gethTypes.LegacyTxType, Nonce: 100}}}
+		for _, queueIndex := range queueIndices {
+			b.Transactions = append(b.Transactions, &gethTypes.TransactionData{Type: gethTypes.L1MessageTxType, Nonce: queueIndex})
+		}
+		return b
+	}
+
+	chunks := []*encoding.Chunk{
+		{Blocks: []*encoding.Block{block(5, 7), block()}},
+		{Blocks: []*encoding.Block{block(8, 11)}},
+	}
+	assert.Equal(t, []uint64{3, 4, 6, 9, 10}, skippedQueueIndices(chunks, 3))
+	assert.Equal(t, []uint64{9, 10}, skippedQueueIndices(chunks[1:], 8))
+	assert.Empty(t, skippedQueueIndices([]*encoding.Chunk{{Blocks: []*encoding.Block{block(3, 4), block()}}}, 3))
+}
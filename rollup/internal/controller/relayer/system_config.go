@@ -0,0 +1,24 @@
+package relayer
+
+import (
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/log"
+)
+
+// SetOnChainL2BaseFee records the L2 base fee set on L1, so that the gas price oracle compares the suggested
+// gas price with the on-chain one, e.g. after another updater changed it.
+func (r *Layer2Relayer) SetOnChainL2BaseFee(l2BaseFee uint64) {
+	r.onChainL2BaseFee.Store(l2BaseFee)
+}
+
+// SetSequencerStatus pauses the commits while the commit sender is not a sequencer of the rollup contract,
+// as the commit transactions would revert, and resumes them once it is allowed again.
+func (r *Layer2Relayer) SetSequencerStatus(account common.Address, allowed bool) {
+	if r.cfg.CommitSenderPrivateKey == nil || account != crypto.PubkeyToAddress(r.cfg.CommitSenderPrivateKey.PublicKey) {
+		return
+	}
+	if r.commitSenderRevoked.Swap(!allowed) != !allowed {
+		log.Warn("commit sender sequencer status updated on L1", "account", account, "allowed", allowed)
+	}
+}
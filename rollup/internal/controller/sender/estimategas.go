@@ -3,6 +3,7 @@ package sender
 import (
 	"fmt"
 	"math/big"
+	"strings"
 
 	"github.com/scroll-tech/go-ethereum"
 	"github.com/scroll-tech/go-ethereum/common"
@@ -11,6 +12,51 @@ import (
 	"github.com/scroll-tech/go-ethereum/log"
 )
 
+// defaultGasLimitMultiplier is the margin applied to the estimated gas limit if not configured.
+const defaultGasLimitMultiplier = 1.2
+
+// GasEstimationError is a failed gas estimation of a transaction, which is not sent.
+type GasEstimationError struct {
+	// Reverted is whether the simulated transaction reverted.
+	Reverted bool
+	// RevertData is the revert data of the simulated transaction, nil if unavailable.
+	RevertData []byte
+	// GasLimit is the estimated gas limit if it exceeds the configured cap, 0 otherwise.
+	GasLimit uint64
+	Err      error
+}
+
+func (e *GasEstimationError) Error() string {
+	if e.GasLimit > 0 {
+		return fmt.Sprintf("gas estimation failed, gas limit: %v, err: %v", e.GasLimit, e.Err)
+	}
+	return fmt.Sprintf("gas estimation failed, reverted: %v, err: %v", e.Reverted, e.Err)
+}
+
+func (e *GasEstimationError) Unwrap() error {
+	return e.Err
+}
+
+// applyGasLimitMargin returns the gas limit of a transaction from its estimation: the estimation times the
+// configured multiplier, capped by the configured max gas limit. It fails if the estimation exceeds the cap.
+func (s *Sender) applyGasLimitMargin(estimated uint64) (uint64, error) {
+	multiplier := s.config.GasLimitMultiplier
+	if multiplier == 0 {
+		multiplier = defaultGasLimitMultiplier
+	}
+	gasLimit := uint64(float64(estimated) * multiplier)
+
+	if maxGasLimit := s.config.MaxGasLimit; maxGasLimit > 0 {
+		if estimated > maxGasLimit {
+			return 0, &GasEstimationError{GasLimit: estimated, Err: fmt.Errorf("estimated gas limit exceeds the max gas limit %v", maxGasLimit)}
+		}
+		if gasLimit > maxGasLimit {
+			gasLimit = maxGasLimit
+		}
+	}
+	return gasLimit, nil
+}
+
 func (s *Sender) estimateLegacyGas(to *common.Address, data []byte, fallbackGasLimit uint64) (*FeeData, error) {
 	gasPrice, err := s.client.SuggestGasPrice(s.ctx)
 	if err != nil {
@@ -27,12 +73,14 @@ func (s *Sender) estimateLegacyGas(to *common.Address, data []byte, fallbackGasL
 	if err != nil {
 		log.Error("estimateLegacyGas estimateGasLimit failure", "gas price", gasPrice, "from", s.auth.From.String(),
 			"nonce", s.auth.Nonce.Uint64(), "to address", to.String(), "fallback gas limit", fallbackGasLimit, "error", err)
+		// the estimation runs at the latest block, so the fallback gas limit also covers the transactions reverting
+		// there, e.g. the commit of a batch whose parent commit is in flight.
 		if fallbackGasLimit == 0 {
 			return nil, err
 		}
 		gasLimit = fallbackGasLimit
-	} else {
-		gasLimit = gasLimit * 12 / 10 // 20% extra gas to avoid out of gas error
+	} else if gasLimit, err = s.applyGasLimitMargin(gasLimit); err != nil {
+		return nil, err
 	}
 	return &FeeData{
 		gasPrice: gasPrice,
@@ -62,8 +110,8 @@ func (s *Sender) estimateDynamicGas(to *common.Address, data []byte, baseFee uin
 			return nil, err
 		}
 		gasLimit = fallbackGasLimit
-	} else {
-		gasLimit = gasLimit * 12 / 10 // 20% extra gas to avoid out of gas error
+	} else if gasLimit, err = s.applyGasLimitMargin(gasLimit); err != nil {
+		return nil, err
 	}
 	feeData := &FeeData{
 		gasLimit:  gasLimit,
@@ -99,8 +147,8 @@ func (s *Sender) estimateBlobGas(to *common.Address, data []byte, sidecar *gethT
 			return nil, err
 		}
 		gasLimit = fallbackGasLimit
-	} else {
-		gasLimit = gasLimit * 12 / 10 // 20% extra gas to avoid out of gas error
+	} else if gasLimit, err = s.applyGasLimitMargin(gasLimit); err != nil {
+		return nil, err
 	}
 	feeData := &FeeData{
 		gasLimit:      gasLimit,
@@ -133,7 +181,12 @@ func (s *Sender) estimateGasLimit(to *common.Address, data []byte, sidecar *geth
 	gasLimitWithoutAccessList, err := s.client.EstimateGas(s.ctx, msg)
 	if err != nil {
 		log.Error("estimateGasLimit EstimateGas failure without access list", "error", err)
-		return 0, nil, err
+		revertData := revertDataFromError(err)
+		return 0, nil, &GasEstimationError{
+			Reverted:   revertData != nil || strings.Contains(err.Error(), "execution reverted"),
+			RevertData: revertData,
+			Err:        err,
+		}
 	}
 
 	if s.config.TxType == LegacyTxType {
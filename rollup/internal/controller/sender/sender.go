@@ -8,18 +8,23 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/holiman/uint256"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scroll-tech/go-ethereum"
 	"github.com/scroll-tech/go-ethereum/accounts/abi/bind"
 	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
 	"github.com/scroll-tech/go-ethereum/consensus/misc"
 	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
 	"github.com/scroll-tech/go-ethereum/crypto/kzg4844"
 	"github.com/scroll-tech/go-ethereum/ethclient"
 	"github.com/scroll-tech/go-ethereum/ethclient/gethclient"
 	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/params"
 	"github.com/scroll-tech/go-ethereum/rlp"
 	"github.com/scroll-tech/go-ethereum/rpc"
 	"gorm.io/gorm"
@@ -27,10 +32,17 @@ import (
 	"scroll-tech/common/types"
 
 	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/featureflag"
 	"scroll-tech/rollup/internal/orm"
 	"scroll-tech/rollup/internal/utils"
 )
 
+// ErrSenderStopped indicates a transaction requested from a sender whose sending is stopped by StopSending.
+var ErrSenderStopped = errors.New("sender stopped sending new transactions")
+
+// drainCheckInterval is the interval at which Drain checks the pending transactions.
+const drainCheckInterval = time.Second
+
 const (
 	// LegacyTxType type for LegacyTx
 	LegacyTxType = "LegacyTx"
@@ -45,6 +57,10 @@ type Confirmation struct {
 	IsSuccessful bool
 	TxHash       common.Hash
 	SenderType   types.SenderType
+	// BlockNumber is the number of the L1 block including the transaction.
+	BlockNumber uint64
+	// RevertData is the revert data of a failed transaction, nil if unavailable.
+	RevertData []byte
 }
 
 // FeeData fee struct used to estimate gas price
@@ -71,14 +87,26 @@ type Sender struct {
 	name       string
 	senderType types.SenderType
 
+	// The client of the secondary endpoint the transactions are also broadcast to, nil if not configured.
+	secondaryClient *ethclient.Client
+	// rpcClients are the rpc clients of the endpoints by their client, to trace the failed transactions.
+	rpcClients map[*ethclient.Client]*rpc.Client
+	// flags gate the broadcast to the secondary endpoint, every flag is disabled until set.
+	flags atomic.Pointer[featureflag.Flags]
+
 	auth *bind.TransactOpts
 
 	db                    *gorm.DB
 	pendingTransactionOrm *orm.PendingTransaction
+	transactionCostOrm    *orm.TransactionCost
 
 	confirmCh chan *Confirmation
 	stopCh    chan struct{}
 
+	// sendMu is held while sending a new transaction, so that no transaction is sent once StopSending returns.
+	sendMu         sync.Mutex
+	sendingStopped bool
+
 	metrics *senderMetrics
 }
 
@@ -98,6 +126,24 @@ func NewSender(ctx context.Context, config *config.SenderConfig, priv *ecdsa.Pri
 	if err != nil {
 		return nil, fmt.Errorf("failed to get chain ID, err: %w", err)
 	}
+	rpcClients := map[*ethclient.Client]*rpc.Client{client: rpcClient}
+
+	var secondaryClient *ethclient.Client
+	if config.SecondaryEndpoint != "" {
+		secondaryRPCClient, err := rpc.Dial(config.SecondaryEndpoint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to dial secondary eth client, err: %w", err)
+		}
+		secondaryClient = ethclient.NewClient(secondaryRPCClient)
+		rpcClients[secondaryClient] = secondaryRPCClient
+		secondaryChainID, err := secondaryClient.ChainID(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get chain ID of the secondary endpoint, err: %w", err)
+		}
+		if secondaryChainID.Cmp(chainID) != 0 {
+			return nil, fmt.Errorf("secondary endpoint chain ID %v differs from chain ID %v", secondaryChainID, chainID)
+		}
+	}
 
 	auth, err := bind.NewKeyedTransactorWithChainID(priv, chainID)
 	if err != nil {
@@ -116,10 +162,13 @@ func NewSender(ctx context.Context, config *config.SenderConfig, priv *ecdsa.Pri
 		config:                config,
 		gethClient:            gethclient.New(rpcClient),
 		client:                client,
+		secondaryClient:       secondaryClient,
+		rpcClients:            rpcClients,
 		chainID:               chainID,
 		auth:                  auth,
 		db:                    db,
 		pendingTransactionOrm: orm.NewPendingTransaction(db),
+		transactionCostOrm:    orm.NewTransactionCost(db),
 		confirmCh:             make(chan *Confirmation, 128),
 		stopCh:                make(chan struct{}),
 		name:                  name,
@@ -144,6 +193,38 @@ func (s *Sender) Stop() {
 	log.Info("sender stopped", "name", s.name, "service", s.service, "address", s.auth.From.String())
 }
 
+// StopSending rejects the new transactions with ErrSenderStopped, while the pending ones are still tracked,
+// resubmitted and confirmed. It waits for the transaction being sent, if any.
+func (s *Sender) StopSending() {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	s.sendingStopped = true
+}
+
+// Drain waits until the pending transactions of the sender are confirmed and their confirmations are consumed,
+// or ctx is done. It returns the number of transactions still pending, which are kept in the database and
+// tracked again once the sender restarts. It is meant to be called after StopSending.
+func (s *Sender) Drain(ctx context.Context) (int, error) {
+	ticker := time.NewTicker(drainCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		pending, err := s.pendingTransactionOrm.GetPendingOrReplacedTransactionsBySenderType(ctx, s.senderType, 100)
+		if err != nil {
+			return 0, fmt.Errorf("failed to load pending transactions, err: %w", err)
+		}
+		if len(pending) == 0 && len(s.confirmCh) == 0 {
+			return 0, nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return len(pending), nil
+		}
+	}
+}
+
 // ConfirmChan channel used to communicate with transaction sender
 func (s *Sender) ConfirmChan() <-chan *Confirmation {
 	return s.confirmCh
@@ -171,6 +252,12 @@ func (s *Sender) getFeeData(target *common.Address, data []byte, sidecar *gethTy
 
 // SendTransaction send a signed L2tL1 transaction.
 func (s *Sender) SendTransaction(contextID string, target *common.Address, data []byte, blob *kzg4844.Blob, fallbackGasLimit uint64) (common.Hash, error) {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	if s.sendingStopped {
+		return common.Hash{}, ErrSenderStopped
+	}
+
 	s.metrics.sendTransactionTotal.WithLabelValues(s.service, s.name).Inc()
 	var (
 		feeData *FeeData
@@ -273,7 +360,7 @@ func (s *Sender) createAndSendTx(feeData *FeeData, target *common.Address, data
 		return nil, err
 	}
 
-	if err = s.client.SendTransaction(s.ctx, signedTx); err != nil {
+	if err = s.broadcastTransaction(signedTx); err != nil {
 		log.Error("failed to send tx", "tx hash", signedTx.Hash().String(), "from", s.auth.From.String(), "nonce", signedTx.Nonce(), "err", err)
 		// Check if contain nonce, and reset nonce
 		// only reset nonce when it is not from resubmit
@@ -308,6 +395,36 @@ func (s *Sender) createAndSendTx(feeData *FeeData, target *common.Address, data
 	return signedTx, nil
 }
 
+// SetFeatureFlags sets the feature flags gating the experimental behaviors of the sender.
+func (s *Sender) SetFeatureFlags(flags *featureflag.Flags) {
+	s.flags.Store(flags)
+}
+
+// broadcastTransaction sends the signed transaction to the endpoint, and to the secondary endpoint if configured and
+// the secondary_broadcast feature flag is enabled for the sender. The transaction is sent if either endpoint accepts
+// it, the error of the endpoint is returned otherwise.
+func (s *Sender) broadcastTransaction(signedTx *gethTypes.Transaction) error {
+	err := s.client.SendTransaction(s.ctx, signedTx)
+	if s.secondaryClient == nil || !s.flags.Load().Enabled(featureflag.SecondaryBroadcast, s.name) {
+		return err
+	}
+
+	secondaryErr := s.secondaryClient.SendTransaction(s.ctx, signedTx)
+	// the transaction may reach the secondary endpoint through the p2p network first.
+	if secondaryErr != nil && strings.Contains(secondaryErr.Error(), "already known") {
+		secondaryErr = nil
+	}
+	if secondaryErr != nil {
+		s.metrics.secondaryBroadcastFailureTotal.WithLabelValues(s.service, s.name).Inc()
+		log.Warn("secondary endpoint failed to accept tx", "tx hash", signedTx.Hash().String(), "from", s.auth.From.String(), "nonce", signedTx.Nonce(), "err", secondaryErr)
+	}
+	if err != nil && secondaryErr == nil {
+		log.Warn("tx only accepted by the secondary endpoint", "tx hash", signedTx.Hash().String(), "from", s.auth.From.String(), "nonce", signedTx.Nonce(), "err", err)
+		return nil
+	}
+	return err
+}
+
 // resetNonce reset nonce if send signed tx failed.
 func (s *Sender) resetNonce(ctx context.Context) {
 	nonce, err := s.client.PendingNonceAt(ctx, s.auth.From)
@@ -479,6 +596,16 @@ func (s *Sender) checkPendingTransaction() {
 		log.Error("failed to get latest confirmed block number", "confirmations", s.config.Confirmations, "err", err)
 		return
 	}
+	// a receipt is confirmed by the chain of the endpoint it's found on.
+	confirmedByClient := map[*ethclient.Client]uint64{s.client: confirmed}
+	if s.secondaryClient != nil {
+		secondaryConfirmed, err := utils.GetLatestConfirmedBlockNumber(s.ctx, s.secondaryClient, s.config.Confirmations)
+		if err != nil {
+			log.Warn("failed to get latest confirmed block number of the secondary endpoint", "confirmations", s.config.Confirmations, "err", err)
+		} else {
+			confirmedByClient[s.secondaryClient] = secondaryConfirmed
+		}
+	}
 
 	for _, txnToCheck := range transactionsToCheck {
 		tx := new(gethTypes.Transaction)
@@ -487,10 +614,16 @@ func (s *Sender) checkPendingTransaction() {
 			continue
 		}
 
-		receipt, err := s.client.TransactionReceipt(s.ctx, tx.Hash())
+		receipt, client, err := s.transactionReceipt(tx.Hash())
 		if err == nil { // tx confirmed.
-			if receipt.BlockNumber.Uint64() <= confirmed {
-				err := s.db.Transaction(func(dbTX *gorm.DB) error {
+			if receipt.BlockNumber.Uint64() <= confirmedByClient[client] {
+				header, err := client.HeaderByNumber(s.ctx, receipt.BlockNumber)
+				if err != nil {
+					log.Error("failed to get the header of the block including the transaction", "hash", tx.Hash().String(), "block number", receipt.BlockNumber, "err", err)
+					return
+				}
+
+				err = s.db.Transaction(func(dbTX *gorm.DB) error {
 					// Update the status of the transaction to TxStatusConfirmed.
 					if err := s.pendingTransactionOrm.UpdatePendingTransactionStatusByTxHash(s.ctx, tx.Hash(), types.TxStatusConfirmed, dbTX); err != nil {
 						log.Error("failed to update transaction status by tx hash", "hash", tx.Hash().String(), "sender meta", s.getSenderMeta(), "from", s.auth.From.String(), "nonce", tx.Nonce(), "err", err)
@@ -501,20 +634,31 @@ func (s *Sender) checkPendingTransaction() {
 						log.Error("failed to update other transactions as failed by nonce", "senderAddress", txnToCheck.SenderAddress, "nonce", tx.Nonce(), "excludedTxHash", tx.Hash(), "err", err)
 						return err
 					}
+					// Record the cost paid for the transaction, reverted or not.
+					if err := s.transactionCostOrm.InsertTransactionCost(s.ctx, txnToCheck.ContextID, s.getSenderMeta(), receipt, header.Time, dbTX); err != nil {
+						log.Error("failed to record transaction cost", "hash", tx.Hash().String(), "context ID", txnToCheck.ContextID, "err", err)
+						return err
+					}
 					return nil
 				})
 				if err != nil {
 					log.Error("db transaction failed after receiving confirmation", "err", err)
 					return
 				}
+				s.recordTransactionCostMetrics(receipt)
 
 				// send confirm message
-				s.confirmCh <- &Confirmation{
+				cfm := &Confirmation{
 					ContextID:    txnToCheck.ContextID,
 					IsSuccessful: receipt.Status == gethTypes.ReceiptStatusSuccessful,
 					TxHash:       tx.Hash(),
 					SenderType:   s.senderType,
+					BlockNumber:  receipt.BlockNumber.Uint64(),
+				}
+				if !cfm.IsSuccessful {
+					cfm.RevertData = s.getRevertData(client, tx, receipt)
 				}
+				s.confirmCh <- cfm
 			}
 		} else if txnToCheck.Status == types.TxStatusPending && // Only try resubmitting a new transaction based on gas price of the last transaction (status pending) with same ContextID.
 			s.config.EscalateBlocks+txnToCheck.SubmitBlockNumber <= blockNumber {
@@ -564,6 +708,88 @@ func (s *Sender) checkPendingTransaction() {
 	}
 }
 
+// transactionReceipt returns the receipt of the transaction along with the client it's found on, looking it up on
+// the secondary endpoint if the endpoint has none.
+func (s *Sender) transactionReceipt(hash common.Hash) (*gethTypes.Receipt, *ethclient.Client, error) {
+	receipt, err := s.client.TransactionReceipt(s.ctx, hash)
+	if err == nil || s.secondaryClient == nil {
+		return receipt, s.client, err
+	}
+
+	secondaryReceipt, secondaryErr := s.secondaryClient.TransactionReceipt(s.ctx, hash)
+	if secondaryErr != nil {
+		return nil, s.client, err
+	}
+	s.metrics.secondaryReceiptTotal.WithLabelValues(s.service, s.name).Inc()
+	return secondaryReceipt, s.secondaryClient, nil
+}
+
+// recordTransactionCostMetrics accounts the gas used and the fee paid for a confirmed transaction.
+func (s *Sender) recordTransactionCostMetrics(receipt *gethTypes.Receipt) {
+	feeGwei, _ := new(big.Float).Quo(new(big.Float).SetInt(orm.TransactionFee(receipt)), big.NewFloat(params.GWei)).Float64()
+	s.metrics.confirmedTransactionGasUsedTotal.WithLabelValues(s.service, s.name).Add(float64(receipt.GasUsed))
+	s.metrics.confirmedTransactionFeeTotal.WithLabelValues(s.service, s.name).Add(feeGwei)
+}
+
+// callTrace is the top level call of a transaction traced by the callTracer.
+type callTrace struct {
+	Output hexutil.Bytes `json:"output"`
+	Error  string        `json:"error"`
+}
+
+// getRevertData gets the revert data of the failed transaction from the endpoint its receipt is found on. The
+// transaction is traced, so that it runs on the state left by the earlier transactions of its block. If the endpoint
+// doesn't serve debug_traceTransaction, the transaction is replayed on top of the parent of its block instead, which
+// ignores the earlier transactions of the block: the replay may then succeed or revert for another reason.
+// It returns nil if the transaction doesn't revert with data, e.g. if it ran out of gas.
+func (s *Sender) getRevertData(client *ethclient.Client, tx *gethTypes.Transaction, receipt *gethTypes.Receipt) []byte {
+	var trace callTrace
+	err := s.rpcClients[client].CallContext(s.ctx, &trace, "debug_traceTransaction", tx.Hash(), map[string]string{"tracer": "callTracer"})
+	if err == nil {
+		if trace.Error == "" || len(trace.Output) == 0 {
+			log.Warn("failed transaction reverted without data", "hash", tx.Hash().String(), "err", trace.Error)
+			return nil
+		}
+		return trace.Output
+	}
+	log.Debug("failed to trace failed transaction, replaying it on top of the parent block", "hash", tx.Hash().String(), "err", err)
+
+	msg := ethereum.CallMsg{
+		From:       s.auth.From,
+		To:         tx.To(),
+		Gas:        tx.Gas(),
+		Value:      tx.Value(),
+		Data:       tx.Data(),
+		AccessList: tx.AccessList(),
+		BlobHashes: tx.BlobHashes(),
+	}
+	parentNumber := new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1))
+	_, err = client.CallContract(s.ctx, msg, parentNumber)
+	revertData := revertDataFromError(err)
+	if revertData == nil {
+		log.Warn("failed to replay failed transaction for revert data", "hash", tx.Hash().String(), "err", err)
+	}
+	return revertData
+}
+
+// revertDataFromError returns the revert data carried by the rpc error of a reverted call, nil if none.
+func revertDataFromError(err error) []byte {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return nil
+	}
+	hexData, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return nil
+	}
+	data, err := hexutil.Decode(hexData)
+	if err != nil {
+		log.Warn("failed to decode revert data", "data", hexData, "err", err)
+		return nil
+	}
+	return data
+}
+
 // Loop is the main event loop
 func (s *Sender) loop(ctx context.Context) {
 	checkTick := time.NewTicker(time.Duration(s.config.CheckPendingTime) * time.Second)
@@ -19,6 +19,10 @@ type senderMetrics struct {
 	currentGasPrice                    *prometheus.GaugeVec
 	currentBlobGasFeeCap               *prometheus.GaugeVec
 	currentGasLimit                    *prometheus.GaugeVec
+	confirmedTransactionGasUsedTotal   *prometheus.CounterVec
+	confirmedTransactionFeeTotal       *prometheus.CounterVec
+	secondaryBroadcastFailureTotal     *prometheus.CounterVec
+	secondaryReceiptTotal              *prometheus.CounterVec
 }
 
 var (
@@ -73,6 +77,22 @@ func initSenderMetrics(reg prometheus.Registerer) *senderMetrics {
 				Name: "rollup_sender_check_pending_transaction_total",
 				Help: "The total number of check pending transaction.",
 			}, []string{"service", "name"}),
+			confirmedTransactionGasUsedTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_sender_confirmed_transaction_gas_used_total",
+				Help: "The total gas used by the confirmed transactions.",
+			}, []string{"service", "name"}),
+			confirmedTransactionFeeTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_sender_confirmed_transaction_fee_gwei_total",
+				Help: "The total fee paid for the confirmed transactions in gwei, including the blob fee and the L1 data fee.",
+			}, []string{"service", "name"}),
+			secondaryBroadcastFailureTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_sender_secondary_broadcast_failure_total",
+				Help: "The total number of transactions the secondary endpoint failed to accept.",
+			}, []string{"service", "name"}),
+			secondaryReceiptTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_sender_secondary_receipt_total",
+				Help: "The total number of receipts found on the secondary endpoint only.",
+			}, []string{"service", "name"}),
 		}
 	})
 
@@ -1,12 +1,17 @@
 package sender
 
 import (
+	"bytes"
 	"context"
 	"crypto/ecdsa"
 	"crypto/rand"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 	"time"
@@ -15,6 +20,7 @@ import (
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
 	"github.com/holiman/uint256"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/scroll-tech/go-ethereum/accounts/abi/bind"
 	"github.com/scroll-tech/go-ethereum/common"
 	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
@@ -32,6 +38,7 @@ import (
 
 	bridgeAbi "scroll-tech/rollup/abi"
 	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/featureflag"
 	"scroll-tech/rollup/internal/orm"
 	"scroll-tech/rollup/mock_bridge"
 )
@@ -124,6 +131,7 @@ func TestSender(t *testing.T) {
 	t.Run("test new sender", testNewSender)
 	t.Run("test send and retrieve transaction", testSendAndRetrieveTransaction)
 	t.Run("test fallback gas limit", testFallbackGasLimit)
+	t.Run("test apply gas limit margin", testApplyGasLimitMargin)
 	t.Run("test access list transaction gas limit", testAccessListTransactionGasLimit)
 	t.Run("test resubmit zero gas price transaction", testResubmitZeroGasPriceTransaction)
 	t.Run("test resubmit non-zero gas price transaction", testResubmitNonZeroGasPriceTransaction)
@@ -135,6 +143,8 @@ func TestSender(t *testing.T) {
 	t.Run("test check pending transaction replaced tx confirmed", testCheckPendingTransactionReplacedTxConfirmed)
 	t.Run("test check pending transaction multiple times with only one transaction pending", testCheckPendingTransactionTxMultipleTimesWithOnlyOneTxPending)
 	t.Run("test blob transaction with blobhash op contract call", testBlobTransactionWithBlobhashOpContractCall)
+	t.Run("test stop sending and drain", testStopSendingAndDrain)
+	t.Run("test secondary endpoint", testSecondaryEndpoint)
 }
 
 func testNewSender(t *testing.T) {
@@ -243,11 +253,62 @@ func testFallbackGasLimit(t *testing.T) {
 			return len(txs) == 0
 		}, 30*time.Second, time.Second)
 
+		patchGuard.Reset()
+
+		// the estimation reverts while the transactions it depends on are in flight.
+		patchGuard = gomonkey.ApplyPrivateMethod(s, "estimateGasLimit",
+			func(contract *common.Address, data []byte, sidecar *gethTypes.BlobTxSidecar, gasPrice, gasTipCap, gasFeeCap, blobGasFeeCap *big.Int) (uint64, *gethTypes.AccessList, error) {
+				return 0, nil, &GasEstimationError{Reverted: true, Err: errors.New("execution reverted")}
+			},
+		)
+
+		_, err = s.SendTransaction("2", &common.Address{}, nil, txBlob[i], 0)
+		var estimationErr *GasEstimationError
+		assert.ErrorAs(t, err, &estimationErr)
+		assert.True(t, estimationErr.Reverted)
+
+		txHash2, err := s.SendTransaction("2", &common.Address{}, nil, txBlob[i], 100000)
+		assert.NoError(t, err)
+		tx2, _, err := client.TransactionByHash(context.Background(), txHash2)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(100000), tx2.Gas())
+
+		assert.Eventually(t, func() bool {
+			txs, err := s.pendingTransactionOrm.GetPendingOrReplacedTransactionsBySenderType(context.Background(), s.senderType, 100)
+			assert.NoError(t, err)
+			return len(txs) == 0
+		}, 30*time.Second, time.Second)
+
 		s.Stop()
 		patchGuard.Reset()
 	}
 }
 
+func testApplyGasLimitMargin(t *testing.T) {
+	s := &Sender{config: &config.SenderConfig{}}
+	gasLimit, err := s.applyGasLimitMargin(100000)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(120000), gasLimit)
+
+	s.config.GasLimitMultiplier = 1.5
+	s.config.MaxGasLimit = 140000
+	gasLimit, err = s.applyGasLimitMargin(80000)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(120000), gasLimit)
+
+	// the margin is capped.
+	gasLimit, err = s.applyGasLimitMargin(100000)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(140000), gasLimit)
+
+	// an estimation exceeding the cap fails.
+	_, err = s.applyGasLimitMargin(150000)
+	var estimationErr *GasEstimationError
+	assert.ErrorAs(t, err, &estimationErr)
+	assert.Equal(t, uint64(150000), estimationErr.GasLimit)
+	assert.False(t, estimationErr.Reverted)
+}
+
 func testResubmitZeroGasPriceTransaction(t *testing.T) {
 	for i, txType := range txTypes {
 		if txBlob[i] != nil {
@@ -795,6 +856,158 @@ func testBlobTransactionWithBlobhashOpContractCall(t *testing.T) {
 	}, 30*time.Second, time.Second)
 }
 
+func testStopSendingAndDrain(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	cfgCopy := *cfg.L2Config.RelayerConfig.SenderConfig
+	cfgCopy.TxType = DynamicFeeTxType
+	s, err := NewSender(context.Background(), &cfgCopy, privateKey, "test", "test", types.SenderTypeUnknown, db, nil)
+	assert.NoError(t, err)
+	defer s.Stop()
+
+	_, err = s.SendTransaction("0", &common.Address{}, nil, nil, 0)
+	assert.NoError(t, err)
+
+	s.StopSending()
+	_, err = s.SendTransaction("1", &common.Address{}, nil, nil, 0)
+	assert.ErrorIs(t, err, ErrSenderStopped)
+
+	// the pending transaction is still confirmed.
+	go func() {
+		<-s.ConfirmChan()
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	pending, err := s.Drain(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, pending)
+
+	txs, err := s.pendingTransactionOrm.GetConfirmedTransactionsBySenderType(context.Background(), s.senderType, 100)
+	assert.NoError(t, err)
+	assert.Len(t, txs, 1)
+}
+
+func testSecondaryEndpoint(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	// the secondary endpoint is another connection to the L1 node, which already knows the broadcast transactions.
+	cfgCopy := *cfg.L2Config.RelayerConfig.SenderConfig
+	cfgCopy.TxType = DynamicFeeTxType
+	cfgCopy.SecondaryEndpoint = cfgCopy.Endpoint
+	s, err := NewSender(context.Background(), &cfgCopy, privateKey, "test", "test", types.SenderTypeUnknown, db, nil)
+	assert.NoError(t, err)
+	defer s.Stop()
+	assert.NotNil(t, s.secondaryClient)
+
+	hash, err := s.SendTransaction("0", &common.Address{}, nil, nil, 0)
+	assert.NoError(t, err)
+
+	var cfm *Confirmation
+	assert.Eventually(t, func() bool {
+		select {
+		case cfm = <-s.ConfirmChan():
+			return true
+		default:
+			return false
+		}
+	}, 30*time.Second, time.Second)
+	assert.Equal(t, hash, cfm.TxHash)
+	assert.True(t, cfm.IsSuccessful)
+
+	// the receipt is looked up on the endpoint first.
+	receipt, client, err := s.transactionReceipt(hash)
+	assert.NoError(t, err)
+	assert.Equal(t, s.client, client)
+	assert.Equal(t, hash, receipt.TxHash)
+
+	// the secondary endpoint must be of the same chain.
+	cfgCopy.SecondaryEndpoint, err = testApps.GetL2GethEndPoint()
+	assert.NoError(t, err)
+	_, err = NewSender(context.Background(), &cfgCopy, privateKey, "test", "test", types.SenderTypeUnknown, db, nil)
+	assert.Error(t, err)
+
+	// the endpoint rejects the transactions and has no receipts, so they are only accepted and confirmed by the secondary endpoint.
+	endpoint, err := testApps.GetPoSL1EndPoint()
+	assert.NoError(t, err)
+	proxy := newRejectingProxy(t, endpoint)
+	defer proxy.Close()
+
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+	cfgCopy.Endpoint = proxy.URL
+	cfgCopy.SecondaryEndpoint = endpoint
+	s2, err := NewSender(context.Background(), &cfgCopy, privateKey, "test", "test", types.SenderTypeUnknown, db, nil)
+	assert.NoError(t, err)
+	defer s2.Stop()
+
+	// nothing is broadcast to the secondary endpoint until the feature flag is enabled.
+	_, err = s2.SendTransaction("1", &common.Address{}, nil, nil, 0)
+	assert.Error(t, err)
+	flags, err := featureflag.NewFlags(context.Background(), map[string]*config.FeatureFlagConfig{featureflag.SecondaryBroadcast: {Enabled: true}}, db, nil)
+	assert.NoError(t, err)
+	s2.SetFeatureFlags(flags)
+
+	secondaryReceipts := testutil.ToFloat64(s2.metrics.secondaryReceiptTotal.WithLabelValues("test", "test"))
+	hash, err = s2.SendTransaction("1", &common.Address{}, nil, nil, 0)
+	assert.NoError(t, err)
+
+	cfm = nil
+	assert.Eventually(t, func() bool {
+		select {
+		case cfm = <-s2.ConfirmChan():
+			return true
+		default:
+			return false
+		}
+	}, 30*time.Second, time.Second)
+	assert.Equal(t, hash, cfm.TxHash)
+	assert.True(t, cfm.IsSuccessful)
+	assert.Greater(t, testutil.ToFloat64(s2.metrics.secondaryReceiptTotal.WithLabelValues("test", "test")), secondaryReceipts)
+
+	receipt, client, err = s2.transactionReceipt(hash)
+	assert.NoError(t, err)
+	assert.Equal(t, s2.secondaryClient, client)
+	assert.Equal(t, hash, receipt.TxHash)
+}
+
+// newRejectingProxy returns a JSON-RPC proxy of the endpoint, which rejects the raw transactions and returns no receipts.
+func newRejectingProxy(t *testing.T, endpoint string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		if json.Unmarshal(body, &req) == nil {
+			switch req.Method {
+			case "eth_sendRawTransaction":
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":{"code":-32000,"message":"txpool is full"}}`, req.ID)
+				return
+			case "eth_getTransactionReceipt":
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":null}`, req.ID)
+				return
+			}
+		}
+
+		resp, err := http.Post(endpoint, "application/json", bytes.NewReader(body))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(resp.StatusCode)
+		_, _ = io.Copy(w, resp.Body)
+	}))
+}
+
 func randBlob() *kzg4844.Blob {
 	var blob kzg4844.Blob
 	for i := 0; i < len(blob); i += gokzg4844.SerializedScalarSize {
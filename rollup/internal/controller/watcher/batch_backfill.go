@@ -0,0 +1,370 @@
+package watcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	geth "github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/accounts/abi"
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/scroll-tech/go-ethereum/rpc"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+	"scroll-tech/common/types/encoding"
+
+	bridgeAbi "scroll-tech/rollup/abi"
+	"scroll-tech/rollup/internal/orm"
+	"scroll-tech/rollup/internal/utils"
+)
+
+// backfillBlocksFetchLimit is the number of L1 blocks whose ScrollChain events are fetched at a time.
+const backfillBlocksFetchLimit = uint64(1000)
+
+// blockContextSize is the size of an encoded block context in the chunks of the commit calldata.
+const blockContextSize = 60
+
+// BackfillL1Client is the L1 client the BatchBackfiller reads the ScrollChain events and commit transactions with.
+type BackfillL1Client interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*gethTypes.Header, error)
+	geth.LogFilterer
+	geth.TransactionReader
+}
+
+// BatchBackfillResult is the result of backfilling the batches committed in a range of L1 blocks.
+type BatchBackfillResult struct {
+	StartL1BlockNumber uint64 `json:"start_l1_block_number"`
+	EndL1BlockNumber   uint64 `json:"end_l1_block_number"`
+	// NumBatches is the number of batches inserted, the batches already in the database are skipped.
+	NumBatches      uint64 `json:"num_batches"`
+	StartBatchIndex uint64 `json:"start_batch_index,omitempty"`
+	EndBatchIndex   uint64 `json:"end_batch_index,omitempty"`
+	NumFinalized    uint64 `json:"num_finalized"`
+	NumReverted     uint64 `json:"num_reverted"`
+}
+
+// BatchBackfiller reconstructs the chunk and batch rows of the batches committed on L1 from the commit calldata
+// and the L2 blocks, so that a database lost without backup can be rebuilt. The genesis batch and the L2 blocks
+// of the backfilled batches must already be in the database, e.g. imported by the relayer and the L2 watcher.
+type BatchBackfiller struct {
+	ctx context.Context
+	db  *gorm.DB
+
+	batchOrm   *orm.Batch
+	chunkOrm   *orm.Chunk
+	l2BlockOrm *orm.L2Block
+
+	l1Client           BackfillL1Client
+	scrollChainAddress common.Address
+	scrollChainABI     *abi.ABI
+
+	chainCfg *params.ChainConfig
+}
+
+// NewBatchBackfiller creates a new BatchBackfiller instance.
+func NewBatchBackfiller(ctx context.Context, db *gorm.DB, l1Client BackfillL1Client, scrollChainAddress common.Address, chainCfg *params.ChainConfig) *BatchBackfiller {
+	return &BatchBackfiller{
+		ctx:                ctx,
+		db:                 db,
+		batchOrm:           orm.NewBatch(db),
+		chunkOrm:           orm.NewChunk(db),
+		l2BlockOrm:         orm.NewL2Block(db),
+		l1Client:           l1Client,
+		scrollChainAddress: scrollChainAddress,
+		scrollChainABI:     bridgeAbi.ScrollChainABI,
+		chainCfg:           chainCfg,
+	}
+}
+
+// Backfill replays the ScrollChain events of the L1 blocks in [startBlock, endBlock]: the committed batches are
+// rebuilt and inserted as committed, the finalized ones are marked as finalized, and the reverted ones are removed.
+// The range must be finalized on L1, and the first batch committed in it must follow the latest batch in the database.
+// The batches already in the database are checked against L1 and skipped, so that an interrupted backfill can be rerun.
+func (b *BatchBackfiller) Backfill(startBlock, endBlock uint64) (*BatchBackfillResult, error) {
+	if startBlock > endBlock {
+		return nil, fmt.Errorf("start block %d is after end block %d", startBlock, endBlock)
+	}
+	finalizedHeader, err := b.l1Client.HeaderByNumber(b.ctx, big.NewInt(int64(rpc.FinalizedBlockNumber)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get finalized l1 block: %w", err)
+	}
+	if endBlock > finalizedHeader.Number.Uint64() {
+		return nil, fmt.Errorf("end block %d is not finalized on l1, finalized block: %d", endBlock, finalizedHeader.Number.Uint64())
+	}
+
+	result := &BatchBackfillResult{StartL1BlockNumber: startBlock, EndL1BlockNumber: endBlock}
+	for from := startBlock; from <= endBlock; from += backfillBlocksFetchLimit {
+		to := min(from+backfillBlocksFetchLimit-1, endBlock)
+		query := geth.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(from), // inclusive
+			ToBlock:   new(big.Int).SetUint64(to),   // inclusive
+			Addresses: []common.Address{b.scrollChainAddress},
+			Topics: [][]common.Hash{{
+				bridgeAbi.L1CommitBatchEventSignature,
+				bridgeAbi.L1FinalizeBatchEventSignature,
+				bridgeAbi.L1RevertBatchEventSignature,
+			}},
+		}
+		logs, err := b.l1Client.FilterLogs(b.ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get event logs, from: %d, to: %d, err: %w", from, to, err)
+		}
+
+		for _, vLog := range logs {
+			if err := b.processLog(vLog, result); err != nil {
+				return nil, fmt.Errorf("failed to process %v event in l1 tx %s: %w", vLog.Topics[0].Hex(), vLog.TxHash.Hex(), err)
+			}
+		}
+		log.Info("backfilled batches from l1 blocks", "from", from, "to", to, "events", len(logs), "batches", result.NumBatches)
+	}
+	return result, nil
+}
+
+func (b *BatchBackfiller) processLog(vLog gethTypes.Log, result *BatchBackfillResult) error {
+	switch vLog.Topics[0] {
+	case bridgeAbi.L1CommitBatchEventSignature:
+		event := bridgeAbi.L1CommitBatchEvent{}
+		if err := utils.UnpackLog(b.scrollChainABI, &event, "CommitBatch", vLog); err != nil {
+			return err
+		}
+		inserted, err := b.backfillBatch(event.BatchIndex.Uint64(), event.BatchHash, vLog.TxHash, vLog.BlockNumber)
+		if err != nil || !inserted {
+			return err
+		}
+		if result.NumBatches == 0 {
+			result.StartBatchIndex = event.BatchIndex.Uint64()
+		}
+		result.EndBatchIndex = event.BatchIndex.Uint64()
+		result.NumBatches++
+	case bridgeAbi.L1FinalizeBatchEventSignature:
+		event := bridgeAbi.L1FinalizeBatchEvent{}
+		if err := utils.UnpackLog(b.scrollChainABI, &event, "FinalizeBatch", vLog); err != nil {
+			return err
+		}
+		if err := b.finalizeBatch(event.BatchIndex.Uint64(), event.BatchHash, vLog.TxHash); err != nil {
+			return err
+		}
+		result.NumFinalized++
+	case bridgeAbi.L1RevertBatchEventSignature:
+		event := bridgeAbi.L1RevertBatchEvent{}
+		if err := utils.UnpackLog(b.scrollChainABI, &event, "RevertBatch", vLog); err != nil {
+			return err
+		}
+		if err := b.revertBatch(event.BatchIndex.Uint64(), event.BatchHash); err != nil {
+			return err
+		}
+		result.NumReverted++
+	}
+	return nil
+}
+
+// backfillBatch rebuilds the batch committed by the given L1 transaction and inserts it along with its chunks.
+// It returns false if the batch is already in the database.
+func (b *BatchBackfiller) backfillBatch(index uint64, batchHash common.Hash, commitTxHash common.Hash, commitL1BlockNumber uint64) (bool, error) {
+	latestBatch, err := b.batchOrm.GetLatestBatch(b.ctx)
+	if err != nil {
+		return false, err
+	}
+	if index <= latestBatch.Index {
+		dbBatch, err := b.batchOrm.GetBatchByIndex(b.ctx, index)
+		if err != nil {
+			return false, err
+		}
+		if dbBatch.Hash != batchHash.Hex() {
+			return false, fmt.Errorf("batch %d in the database has hash %s, committed hash: %s", index, dbBatch.Hash, batchHash.Hex())
+		}
+		log.Info("batch already in the database, skipping it", "index", index, "hash", dbBatch.Hash)
+		return false, nil
+	}
+	if index != latestBatch.Index+1 {
+		return false, fmt.Errorf("batch %d doesn't follow the latest batch %d in the database", index, latestBatch.Index)
+	}
+
+	version, parentBatchHeader, encodedChunks, err := b.getCommitBatchArgs(commitTxHash)
+	if err != nil {
+		return false, err
+	}
+	if !bytes.Equal(parentBatchHeader, latestBatch.BatchHeader) {
+		return false, fmt.Errorf("parent batch header of batch %d doesn't match the latest batch in the database", index)
+	}
+
+	// the chunks of the batch follow the last batched chunk, there must be no unbatched chunks in the database.
+	latestChunks, err := b.chunkOrm.GetChunksGEIndex(b.ctx, latestBatch.EndChunkIndex+1, 1)
+	if err != nil {
+		return false, err
+	}
+	if len(latestChunks) > 0 {
+		return false, fmt.Errorf("the database has unbatched chunks from index %d, backfill into a database without unbatched chunks", latestChunks[0].Index)
+	}
+
+	batch := &encoding.Batch{
+		Index:           index,
+		ParentBatchHash: common.HexToHash(latestBatch.Hash),
+	}
+	for i, encodedChunk := range encodedChunks {
+		startBlockNumber, endBlockNumber, err := decodeChunkBlockRange(encodedChunk)
+		if err != nil {
+			return false, fmt.Errorf("failed to decode chunk %d: %w", i, err)
+		}
+		blocks, err := b.l2BlockOrm.GetL2BlocksInRange(b.ctx, startBlockNumber, endBlockNumber)
+		if err != nil {
+			return false, err
+		}
+		if uint64(len(blocks)) != endBlockNumber-startBlockNumber+1 {
+			return false, fmt.Errorf("l2 blocks [%d, %d] of chunk %d are missing in the database, got %d blocks", startBlockNumber, endBlockNumber, i, len(blocks))
+		}
+		batch.Chunks = append(batch.Chunks, &encoding.Chunk{Blocks: blocks})
+	}
+
+	firstBlockNumber := batch.Chunks[0].Blocks[0].Header.Number.Uint64()
+	parentCodecVersion := utils.GetBatchCodecVersion(b.chainCfg, latestBatch.Index, firstBlockNumber-1)
+	batch.TotalL1MessagePoppedBefore, err = utils.GetTotalL1MessagePoppedBeforeBatch(latestBatch.BatchHeader, parentCodecVersion)
+	if err != nil {
+		return false, err
+	}
+
+	// the batch is rebuilt from the L2 blocks in the database, it must hash to the committed one.
+	codecVersion := encoding.CodecVersion(version)
+	batchMeta, err := utils.GetBatchMetadata(batch, codecVersion)
+	if err != nil {
+		return false, err
+	}
+	if batchMeta.BatchHash != batchHash {
+		return false, fmt.Errorf("rebuilt batch %d has hash %s, committed hash: %s", index, batchMeta.BatchHash.Hex(), batchHash.Hex())
+	}
+
+	err = b.db.Transaction(func(dbTX *gorm.DB) error {
+		// the orms are bound to the transaction since the chunk indexes follow the chunks inserted before.
+		chunkOrm, l2BlockOrm, batchOrm := orm.NewChunk(dbTX), orm.NewL2Block(dbTX), orm.NewBatch(dbTX)
+		for _, chunk := range batch.Chunks {
+			dbChunk, err := chunkOrm.InsertChunk(b.ctx, chunk, codecVersion)
+			if err != nil {
+				return err
+			}
+			if err := l2BlockOrm.UpdateChunkHashInRange(b.ctx, dbChunk.StartBlockNumber, dbChunk.EndBlockNumber, dbChunk.Hash); err != nil {
+				return err
+			}
+		}
+		dbBatch, err := batchOrm.InsertBatch(b.ctx, batch, codecVersion)
+		if err != nil {
+			return err
+		}
+		if err := chunkOrm.UpdateBatchHashInRange(b.ctx, dbBatch.StartChunkIndex, dbBatch.EndChunkIndex, dbBatch.Hash); err != nil {
+			return err
+		}
+		if err := batchOrm.UpdateCommitTxHashAndRollupStatus(b.ctx, dbBatch.Hash, commitTxHash.Hex(), types.RollupCommitted); err != nil {
+			return err
+		}
+		return batchOrm.UpdateCommitL1BlockNumber(b.ctx, dbBatch.Hash, commitL1BlockNumber)
+	})
+	if err != nil {
+		return false, err
+	}
+	log.Info("backfilled batch", "index", index, "hash", batchHash.Hex(), "chunks", len(batch.Chunks), "codec version", codecVersion)
+	return true, nil
+}
+
+// getCommitBatchArgs returns the arguments of the commitBatch call of the given L1 transaction.
+func (b *BatchBackfiller) getCommitBatchArgs(txHash common.Hash) (uint8, []byte, [][]byte, error) {
+	tx, _, err := b.l1Client.TransactionByHash(b.ctx, txHash)
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("failed to get commit tx: %w", err)
+	}
+	if len(tx.Data()) < 4 {
+		return 0, nil, nil, errors.New("commit tx has no calldata")
+	}
+	method, err := b.scrollChainABI.MethodById(tx.Data()[:4])
+	if err != nil || method.Name != "commitBatch" {
+		return 0, nil, nil, errors.New("commit tx doesn't call commitBatch")
+	}
+	args, err := method.Inputs.Unpack(tx.Data()[4:])
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("failed to unpack commitBatch calldata: %w", err)
+	}
+	return args[0].(uint8), args[1].([]byte), args[2].([][]byte), nil
+}
+
+// decodeChunkBlockRange returns the range of the L2 blocks of an encoded chunk of the commit calldata,
+// i.e. the number of blocks followed by the block contexts starting with the block numbers.
+func decodeChunkBlockRange(encodedChunk []byte) (uint64, uint64, error) {
+	if len(encodedChunk) == 0 || encodedChunk[0] == 0 {
+		return 0, 0, errors.New("empty chunk")
+	}
+	numBlocks := int(encodedChunk[0])
+	if len(encodedChunk) < 1+numBlocks*blockContextSize {
+		return 0, 0, fmt.Errorf("chunk of %d blocks is too short, length: %d", numBlocks, len(encodedChunk))
+	}
+	startBlockNumber := binary.BigEndian.Uint64(encodedChunk[1:])
+	endBlockNumber := binary.BigEndian.Uint64(encodedChunk[1+(numBlocks-1)*blockContextSize:])
+	if endBlockNumber-startBlockNumber+1 != uint64(numBlocks) {
+		return 0, 0, fmt.Errorf("chunk of %d blocks has block range [%d, %d]", numBlocks, startBlockNumber, endBlockNumber)
+	}
+	return startBlockNumber, endBlockNumber, nil
+}
+
+// finalizeBatch marks the backfilled batch as finalized.
+func (b *BatchBackfiller) finalizeBatch(index uint64, batchHash common.Hash, finalizeTxHash common.Hash) error {
+	latestBatch, err := b.batchOrm.GetLatestBatch(b.ctx)
+	if err != nil {
+		return err
+	}
+	if index > latestBatch.Index {
+		return fmt.Errorf("finalized batch %d is not in the database", index)
+	}
+	dbBatch, err := b.batchOrm.GetBatchByIndex(b.ctx, index)
+	if err != nil {
+		return err
+	}
+	if dbBatch.Hash != batchHash.Hex() {
+		return fmt.Errorf("batch %d in the database has hash %s, finalized hash: %s", index, dbBatch.Hash, batchHash.Hex())
+	}
+
+	// the proofs of the finalized batches are verified on L1, they don't need to be proven again.
+	return b.db.Transaction(func(dbTX *gorm.DB) error {
+		batchOrm := orm.NewBatch(dbTX)
+		if err := batchOrm.UpdateProvingStatus(b.ctx, dbBatch.Hash, types.ProvingTaskVerified); err != nil {
+			return err
+		}
+		if err := orm.NewChunk(dbTX).UpdateProvingStatusByBatchHash(b.ctx, dbBatch.Hash, types.ProvingTaskVerified); err != nil {
+			return err
+		}
+		return batchOrm.UpdateFinalizeTxHashAndRollupStatus(b.ctx, dbBatch.Hash, finalizeTxHash.Hex(), types.RollupFinalized)
+	})
+}
+
+// revertBatch removes the reverted batch and its chunks, so that the batch committed next with the same index
+// can be backfilled.
+func (b *BatchBackfiller) revertBatch(index uint64, batchHash common.Hash) error {
+	dbBatch, err := b.batchOrm.GetBatchByIndex(b.ctx, index)
+	if err != nil {
+		return err
+	}
+	if dbBatch.Hash != batchHash.Hex() {
+		return fmt.Errorf("batch %d in the database has hash %s, reverted hash: %s", index, dbBatch.Hash, batchHash.Hex())
+	}
+	dbChunks, err := b.chunkOrm.GetChunksInRange(b.ctx, dbBatch.StartChunkIndex, dbBatch.StartChunkIndex)
+	if err != nil {
+		return err
+	}
+
+	return b.db.Transaction(func(dbTX *gorm.DB) error {
+		chunkOrm := orm.NewChunk(dbTX)
+		if err := orm.NewBatch(dbTX).DeleteBatchesGtIndex(b.ctx, index-1); err != nil {
+			return err
+		}
+		if err := chunkOrm.ResetBatchHashGtIndex(b.ctx, dbBatch.StartChunkIndex-1); err != nil {
+			return err
+		}
+		if _, err := chunkOrm.DeleteUnbatchedChunksGEIndex(b.ctx, dbBatch.StartChunkIndex); err != nil {
+			return err
+		}
+		return orm.NewL2Block(dbTX).ResetChunkHashGENumber(b.ctx, dbChunks[0].StartBlockNumber)
+	})
+}
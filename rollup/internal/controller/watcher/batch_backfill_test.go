@@ -0,0 +1,44 @@
+package watcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/types/encoding"
+	"scroll-tech/common/types/encoding/codecv0"
+	"scroll-tech/common/types/encoding/codecv1"
+)
+
+func TestDecodeChunkBlockRange(t *testing.T) {
+	block2 := readBlockFromJSON(t, "../../../testdata/blockTrace_02.json")
+	block3 := readBlockFromJSON(t, "../../../testdata/blockTrace_03.json")
+	chunk := &encoding.Chunk{Blocks: []*encoding.Block{block2, block3}}
+
+	daChunkV0, err := codecv0.NewDAChunk(chunk, 0)
+	assert.NoError(t, err)
+	encodedV0, err := daChunkV0.Encode()
+	assert.NoError(t, err)
+	startBlockNumber, endBlockNumber, err := decodeChunkBlockRange(encodedV0)
+	assert.NoError(t, err)
+	assert.Equal(t, block2.Header.Number.Uint64(), startBlockNumber)
+	assert.Equal(t, block3.Header.Number.Uint64(), endBlockNumber)
+
+	daChunkV1, err := codecv1.NewDAChunk(chunk, 0)
+	assert.NoError(t, err)
+	encodedV1 := daChunkV1.Encode()
+	startBlockNumber, endBlockNumber, err = decodeChunkBlockRange(encodedV1)
+	assert.NoError(t, err)
+	assert.Equal(t, block2.Header.Number.Uint64(), startBlockNumber)
+	assert.Equal(t, block3.Header.Number.Uint64(), endBlockNumber)
+
+	_, _, err = decodeChunkBlockRange(nil)
+	assert.Error(t, err)
+	_, _, err = decodeChunkBlockRange(encodedV1[:len(encodedV1)-1])
+	assert.Error(t, err)
+	// the blocks of a chunk are consecutive.
+	daChunkV1, err = codecv1.NewDAChunk(&encoding.Chunk{Blocks: []*encoding.Block{block2, block2}}, 0)
+	assert.NoError(t, err)
+	_, _, err = decodeChunkBlockRange(daChunkV1.Encode())
+	assert.Error(t, err)
+}
@@ -0,0 +1,81 @@
+package watcher
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+// congestionTimeout extends the batch timeout while the L1 fees exceed the configured ceilings,
+// trading batch latency for commit cost during fee spikes.
+type congestionTimeout struct {
+	cfg *config.CongestionTimeoutConfig
+	// baseFeeSource and blobBaseFeeSource are nil if the fee is not checked.
+	baseFeeSource     BaseFeeSource
+	blobBaseFeeSource BlobBaseFeeSource
+
+	congested bool
+
+	batchCongested prometheus.Gauge
+}
+
+func newCongestionTimeout(cfg *config.CongestionTimeoutConfig, baseFeeSource BaseFeeSource, blobBaseFeeSource BlobBaseFeeSource, reg prometheus.Registerer) *congestionTimeout {
+	return &congestionTimeout{
+		cfg:               cfg,
+		baseFeeSource:     baseFeeSource,
+		blobBaseFeeSource: blobBaseFeeSource,
+
+		batchCongested: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_propose_batch_congested",
+			Help: "Whether the batch timeout is extended because the L1 fees exceed the ceilings, 1 if extended",
+		}),
+	}
+}
+
+// batchTimeoutSec returns the batch timeout for the latest L1 fees.
+// The last congestion state is kept if the fees can't be fetched.
+func (c *congestionTimeout) batchTimeoutSec(ctx context.Context, baseTimeoutSec uint64) uint64 {
+	congested, err := c.checkCongested(ctx)
+	if err != nil {
+		log.Warn("failed to get latest L1 fees, keeping congestion state", "congested", c.congested, "err", err)
+	} else if congested != c.congested {
+		log.Info("updating batch timeout congestion state", "congested", congested, "max delay sec", c.cfg.MaxDelaySec)
+		c.congested = congested
+		if congested {
+			c.batchCongested.Set(1)
+		} else {
+			c.batchCongested.Set(0)
+		}
+	}
+
+	if c.congested {
+		return baseTimeoutSec + c.cfg.MaxDelaySec
+	}
+	return baseTimeoutSec
+}
+
+func (c *congestionTimeout) checkCongested(ctx context.Context) (bool, error) {
+	if c.baseFeeSource != nil && c.cfg.BaseFeeCeiling > 0 {
+		baseFee, err := c.baseFeeSource.GetLatestBaseFee(ctx)
+		if err != nil {
+			return false, err
+		}
+		if baseFee > c.cfg.BaseFeeCeiling {
+			return true, nil
+		}
+	}
+	if c.blobBaseFeeSource != nil && c.cfg.BlobBaseFeeCeiling > 0 {
+		blobBaseFee, err := c.blobBaseFeeSource.GetLatestBlobBaseFee(ctx)
+		if err != nil {
+			return false, err
+		}
+		if blobBaseFee > c.cfg.BlobBaseFeeCeiling {
+			return true, nil
+		}
+	}
+	return false, nil
+}
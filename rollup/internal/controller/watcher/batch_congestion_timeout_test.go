@@ -0,0 +1,51 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+type mockBaseFeeSource struct {
+	baseFee uint64
+	err     error
+}
+
+func (s *mockBaseFeeSource) GetLatestBaseFee(_ context.Context) (uint64, error) {
+	return s.baseFee, s.err
+}
+
+func TestCongestionTimeout(t *testing.T) {
+	cfg := &config.CongestionTimeoutConfig{
+		BaseFeeCeiling:     100,
+		BlobBaseFeeCeiling: 10,
+		MaxDelaySec:        600,
+	}
+	baseFeeSource := &mockBaseFeeSource{baseFee: 50}
+	blobBaseFeeSource := &mockBlobBaseFeeSource{blobBaseFee: 5}
+	c := newCongestionTimeout(cfg, baseFeeSource, blobBaseFeeSource, nil)
+
+	// both fees are below the ceilings.
+	assert.Equal(t, uint64(300), c.batchTimeoutSec(context.Background(), 300))
+	// either fee exceeding its ceiling extends the timeout.
+	baseFeeSource.baseFee = 101
+	assert.Equal(t, uint64(900), c.batchTimeoutSec(context.Background(), 300))
+	baseFeeSource.baseFee = 50
+	blobBaseFeeSource.blobBaseFee = 11
+	assert.Equal(t, uint64(900), c.batchTimeoutSec(context.Background(), 300))
+	// the last state is kept on failures.
+	blobBaseFeeSource.err = errors.New("l1 block not found")
+	assert.Equal(t, uint64(900), c.batchTimeoutSec(context.Background(), 300))
+	blobBaseFeeSource.err = nil
+	blobBaseFeeSource.blobBaseFee = 10
+	assert.Equal(t, uint64(300), c.batchTimeoutSec(context.Background(), 300))
+
+	// the blob base fee is ignored without its ceiling.
+	cfg.BlobBaseFeeCeiling = 0
+	blobBaseFeeSource.blobBaseFee = 1000
+	assert.Equal(t, uint64(300), c.batchTimeoutSec(context.Background(), 300))
+}
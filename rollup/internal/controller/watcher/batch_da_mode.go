@@ -0,0 +1,167 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/params"
+
+	"scroll-tech/common/types/encoding"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/utils"
+)
+
+// DA modes of a batch, as labeled in the metrics.
+const (
+	daModeCalldata = "calldata"
+	daModeBlob     = "blob"
+)
+
+// defaultBlobFeeSpikeWindow is the number of L1 blocks of the trailing average blob base fee if not configured.
+const defaultBlobFeeSpikeWindow = 300
+
+// daModeSelector compares the L1 costs of committing the sealed batches in calldata and in blobs at the latest L1 fees.
+// The batches after the Bernoulli fork are always committed in blobs (codecv1): the coordinator, the provers and the
+// verifier only prove codecv1 batches after the fork, so a codecv0 batch there could never be finalized. The cheaper
+// DA mode is only reported, to size the savings of a codec-aware proving pipeline.
+type daModeSelector struct {
+	cfg               *config.DAModeSelectionConfig
+	baseFeeSource     BaseFeeSource
+	blobBaseFeeSource BlobBaseFeeSource
+	// blobFeeHistorySource is nil if the blob fee spikes aren't detected.
+	blobFeeHistorySource BlobBaseFeeHistorySource
+
+	// blobFeeSpike is whether the last blob base fee exceeded the spike threshold.
+	blobFeeSpike bool
+
+	cheaperDAModeTotal *prometheus.CounterVec
+	calldataCommitCost prometheus.Gauge
+	blobCommitCost     prometheus.Gauge
+	blobFeeSpikeGauge  prometheus.Gauge
+}
+
+func newDAModeSelector(cfg *config.DAModeSelectionConfig, baseFeeSource BaseFeeSource, blobBaseFeeSource BlobBaseFeeSource, blobFeeHistorySource BlobBaseFeeHistorySource, reg prometheus.Registerer) *daModeSelector {
+	if cfg.BlobFeeSpikeMultiplier == 0 {
+		blobFeeHistorySource = nil
+	}
+	return &daModeSelector{
+		cfg:                  cfg,
+		baseFeeSource:        baseFeeSource,
+		blobBaseFeeSource:    blobBaseFeeSource,
+		blobFeeHistorySource: blobFeeHistorySource,
+
+		cheaperDAModeTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "rollup_propose_batch_cheaper_da_mode_total",
+			Help: "Total number of batches by the DA mode which is the cheaper to commit them at the latest L1 fees",
+		}, []string{"mode"}),
+		calldataCommitCost: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_propose_batch_calldata_commit_cost_wei",
+			Help: "The estimated L1 cost of committing the last batch in calldata",
+		}),
+		blobCommitCost: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_propose_batch_blob_commit_cost_wei",
+			Help: "The estimated L1 cost of committing the last batch in blobs",
+		}),
+		blobFeeSpikeGauge: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_propose_batch_blob_fee_spike",
+			Help: "Whether the blob base fee spikes above its trailing average (1) or not (0)",
+		}),
+	}
+}
+
+// checkBlobFeeSpike returns whether the blob base fee exceeds the spike multiple of its trailing average.
+// The last state is kept if the trailing average can't be fetched.
+func (s *daModeSelector) checkBlobFeeSpike(ctx context.Context, blobBaseFee uint64) bool {
+	if s.blobFeeHistorySource == nil {
+		return false
+	}
+
+	window := s.cfg.BlobFeeSpikeWindow
+	if window == 0 {
+		window = defaultBlobFeeSpikeWindow
+	}
+	average, err := s.blobFeeHistorySource.GetAverageBlobBaseFee(ctx, window)
+	if err != nil {
+		log.Warn("failed to get average blob base fee, keeping blob fee spike state", "spike", s.blobFeeSpike, "err", err)
+		return s.blobFeeSpike
+	}
+
+	spike := average > 0 && float64(blobBaseFee) > s.cfg.BlobFeeSpikeMultiplier*float64(average)
+	if spike != s.blobFeeSpike {
+		log.Info("blob fee spike state changed", "spike", spike, "blob base fee", blobBaseFee, "average blob base fee", average,
+			"multiplier", s.cfg.BlobFeeSpikeMultiplier)
+		s.blobFeeSpike = spike
+		if spike {
+			s.blobFeeSpikeGauge.Set(1)
+		} else {
+			s.blobFeeSpikeGauge.Set(0)
+		}
+	}
+	return spike
+}
+
+// calldataCommitCost estimates the L1 cost of committing a batch in calldata, in wei.
+func calldataCommitCost(calldataMetrics *utils.BatchMetrics, baseFee uint64) float64 {
+	return float64(calldataMetrics.L1CommitGas) * float64(baseFee)
+}
+
+// blobCommitCost estimates the L1 cost of committing a batch in blobs, in wei.
+func blobCommitCost(blobMetrics *utils.BatchMetrics, baseFee uint64, blobBaseFee uint64) float64 {
+	return float64(blobMetrics.L1CommitGas)*float64(baseFee) + float64(params.BlobTxBlobGasPerBlob)*float64(blobBaseFee)
+}
+
+// compareDAModeCosts records in proposal.cheaperDAMode whether committing the sealed codecv1 batch in calldata would be
+// cheaper at the latest L1 fees, and the batch fits the calldata limits of a batch. The batch itself is always committed
+// in blobs, see daModeSelector. A blob base fee spike is only reported: falling back to calldata would emit codecv0
+// batches after the Bernoulli fork.
+func (p *BatchProposer) compareDAModeCosts(proposal *batchProposal) error {
+	if p.daModeSelector == nil || proposal.codecVersion != encoding.CodecV1 {
+		return nil
+	}
+	s := p.daModeSelector
+
+	calldataMetrics, err := utils.CalculateBatchMetrics(proposal.batch, encoding.CodecV0)
+	if err != nil {
+		return fmt.Errorf("failed to calculate batch metrics: %w", err)
+	}
+	if calldataMetrics.L1CommitCalldataSize > p.maxL1CommitCalldataSizePerBatch ||
+		uint64(p.gasCostIncreaseMultiplier*float64(calldataMetrics.L1CommitGas)) > p.maxL1CommitGasPerBatch {
+		log.Debug("batch doesn't fit the calldata limits, blobs are its only DA mode", "index", proposal.batch.Index,
+			"calldata size", calldataMetrics.L1CommitCalldataSize, "l1 commit gas", calldataMetrics.L1CommitGas)
+		proposal.cheaperDAMode = daModeBlob
+		s.cheaperDAModeTotal.WithLabelValues(daModeBlob).Inc()
+		return nil
+	}
+
+	baseFee, err := s.baseFeeSource.GetLatestBaseFee(p.ctx)
+	if err != nil {
+		log.Warn("failed to get latest L1 base fee, skipping the DA mode cost comparison", "index", proposal.batch.Index, "err", err)
+		return nil
+	}
+	blobBaseFee, err := s.blobBaseFeeSource.GetLatestBlobBaseFee(p.ctx)
+	if err != nil {
+		log.Warn("failed to get latest L1 blob base fee, skipping the DA mode cost comparison", "index", proposal.batch.Index, "err", err)
+		return nil
+	}
+
+	calldataCost := calldataCommitCost(calldataMetrics, baseFee)
+	blobCost := blobCommitCost(proposal.metrics, baseFee, blobBaseFee)
+	s.calldataCommitCost.Set(calldataCost)
+	s.blobCommitCost.Set(blobCost)
+
+	spike := s.checkBlobFeeSpike(p.ctx, blobBaseFee)
+
+	mode := daModeBlob
+	if calldataCost <= blobCost*float64(100-s.cfg.MinSavingPercent)/100 {
+		mode = daModeCalldata
+	}
+	proposal.cheaperDAMode = mode
+	s.cheaperDAModeTotal.WithLabelValues(mode).Inc()
+	log.Info("compared batch DA mode costs", "index", proposal.batch.Index, "mode", mode, "calldata cost", calldataCost, "blob cost", blobCost,
+		"base fee", baseFee, "blob base fee", blobBaseFee, "blob fee spike", spike)
+	return nil
+}
@@ -0,0 +1,76 @@
+package watcher
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+// dynamicMaxChunks scales the max chunk number per batch with the observed L1 blob base fee,
+// trading batch latency for amortized blob cost.
+type dynamicMaxChunks struct {
+	cfg        *config.DynamicMaxChunksConfig
+	upperBound uint64
+	feeSource  BlobBaseFeeSource
+
+	current uint64
+
+	dynamicMaxChunkNum prometheus.Gauge
+}
+
+func newDynamicMaxChunks(cfg *config.DynamicMaxChunksConfig, upperBound uint64, feeSource BlobBaseFeeSource, reg prometheus.Registerer) *dynamicMaxChunks {
+	d := &dynamicMaxChunks{
+		cfg:        cfg,
+		upperBound: upperBound,
+		feeSource:  feeSource,
+		current:    upperBound,
+
+		dynamicMaxChunkNum: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_propose_batch_dynamic_max_chunk_num",
+			Help: "The max chunk number per batch derived from the L1 blob base fee",
+		}),
+	}
+	d.dynamicMaxChunkNum.Set(float64(d.current))
+	return d
+}
+
+// maxChunkNum returns the max chunk number per batch for the latest blob base fee.
+// The last value is kept if the blob base fee can't be fetched.
+func (d *dynamicMaxChunks) maxChunkNum(ctx context.Context) uint64 {
+	blobBaseFee, err := d.feeSource.GetLatestBlobBaseFee(ctx)
+	if err != nil {
+		log.Warn("failed to get latest blob base fee, keeping max chunk number per batch", "max chunk num", d.current, "err", err)
+		return d.current
+	}
+
+	target := d.target(blobBaseFee)
+	if target != d.current && (absDiff(target, d.current) >= d.cfg.HysteresisChunkNum || target == d.cfg.MinChunkNumPerBatch || target == d.upperBound) {
+		log.Info("updating max chunk number per batch", "blob base fee", blobBaseFee, "old", d.current, "new", target)
+		d.current = target
+		d.dynamicMaxChunkNum.Set(float64(d.current))
+	}
+	return d.current
+}
+
+// target interpolates the max chunk number linearly between the bounds by the blob base fee.
+func (d *dynamicMaxChunks) target(blobBaseFee uint64) uint64 {
+	if blobBaseFee <= d.cfg.LowBlobBaseFee {
+		return d.cfg.MinChunkNumPerBatch
+	}
+	if blobBaseFee >= d.cfg.HighBlobBaseFee {
+		return d.upperBound
+	}
+	ratio := float64(blobBaseFee-d.cfg.LowBlobBaseFee) / float64(d.cfg.HighBlobBaseFee-d.cfg.LowBlobBaseFee)
+	return d.cfg.MinChunkNumPerBatch + uint64(ratio*float64(d.upperBound-d.cfg.MinChunkNumPerBatch))
+}
+
+func absDiff(a, b uint64) uint64 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
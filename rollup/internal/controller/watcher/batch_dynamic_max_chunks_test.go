@@ -0,0 +1,52 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+type mockBlobBaseFeeSource struct {
+	blobBaseFee uint64
+	err         error
+}
+
+func (s *mockBlobBaseFeeSource) GetLatestBlobBaseFee(_ context.Context) (uint64, error) {
+	return s.blobBaseFee, s.err
+}
+
+func TestDynamicMaxChunks(t *testing.T) {
+	cfg := &config.DynamicMaxChunksConfig{
+		MinChunkNumPerBatch: 5,
+		LowBlobBaseFee:      100,
+		HighBlobBaseFee:     1100,
+		HysteresisChunkNum:  3,
+	}
+	feeSource := &mockBlobBaseFeeSource{}
+	d := newDynamicMaxChunks(cfg, 45, feeSource, nil)
+
+	assert.Equal(t, uint64(5), d.target(0))
+	assert.Equal(t, uint64(5), d.target(100))
+	assert.Equal(t, uint64(25), d.target(600))
+	assert.Equal(t, uint64(45), d.target(1100))
+	assert.Equal(t, uint64(45), d.target(5000))
+
+	// starts from the upper bound.
+	feeSource.blobBaseFee = 1050
+	assert.Equal(t, uint64(45), d.maxChunkNum(context.Background()))
+	// cheap blobs, the lower bound is always reached.
+	feeSource.blobBaseFee = 50
+	assert.Equal(t, uint64(5), d.maxChunkNum(context.Background()))
+	// the target moves less than the hysteresis.
+	feeSource.blobBaseFee = 150
+	assert.Equal(t, uint64(5), d.maxChunkNum(context.Background()))
+	feeSource.blobBaseFee = 250
+	assert.Equal(t, uint64(11), d.maxChunkNum(context.Background()))
+	// the last value is kept on failures.
+	feeSource.err = errors.New("l1 block not found")
+	assert.Equal(t, uint64(11), d.maxChunkNum(context.Background()))
+}
@@ -0,0 +1,74 @@
+package watcher
+
+import (
+	"context"
+
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/common/types/encoding"
+)
+
+// forcedInclusionTimeoutReached checks whether an enforced transaction in the blocks has been waiting
+// for longer than timeoutSec since it was queued on L1, or is due to reach its inclusion deadline within
+// timeoutSec. The wait of the enforced transactions queued before their enqueue time was recorded is
+// counted from the L2 block including them.
+func forcedInclusionTimeoutReached(ctx context.Context, source EnforcedL1MessageSource, blocks []*encoding.Block, timeoutSec uint64, currentTimeSec uint64) (bool, error) {
+	var queueIndices []uint64
+	blockTimestamps := make(map[uint64]uint64) // queue index -> timestamp of the L2 block including it
+	for _, block := range blocks {
+		for _, tx := range block.Transactions {
+			if tx.Type != gethTypes.L1MessageTxType {
+				continue
+			}
+			queueIndices = append(queueIndices, tx.Nonce)
+			blockTimestamps[tx.Nonce] = block.Header.Time
+		}
+	}
+	if len(queueIndices) == 0 {
+		return false, nil
+	}
+
+	enforcedMessages, err := source.GetEnforcedL1MessagesByQueueIndices(ctx, queueIndices)
+	if err != nil {
+		return false, err
+	}
+
+	for _, msg := range enforcedMessages {
+		enqueueTime := msg.EnqueueTime
+		if enqueueTime == 0 {
+			enqueueTime = blockTimestamps[msg.QueueIndex]
+		}
+		if enqueueTime+timeoutSec < currentTimeSec {
+			log.Info("enforced transaction reached forced inclusion timeout",
+				"queue index", msg.QueueIndex,
+				"l1 tx hash", msg.Layer1Hash,
+				"enqueue time", enqueueTime,
+				"forced inclusion timeout sec", timeoutSec)
+			return true, nil
+		}
+		if msg.InclusionDeadline != 0 && currentTimeSec+timeoutSec >= msg.InclusionDeadline {
+			log.Info("enforced transaction is close to its inclusion deadline",
+				"queue index", msg.QueueIndex,
+				"l1 tx hash", msg.Layer1Hash,
+				"inclusion deadline", msg.InclusionDeadline,
+				"forced inclusion timeout sec", timeoutSec)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// forcedInclusionTimeoutReached checks whether an enforced transaction in the batch has been waiting
+// for longer than the forced inclusion timeout of the batch proposer since it was queued on L1.
+func (p *BatchProposer) forcedInclusionTimeoutReached(batch *encoding.Batch, currentTimeSec uint64) (bool, error) {
+	if p.cfg.ForcedInclusionTimeoutSec == 0 || p.enforcedL1MessageSource == nil {
+		return false, nil
+	}
+
+	var blocks []*encoding.Block
+	for _, chunk := range batch.Chunks {
+		blocks = append(blocks, chunk.Blocks...)
+	}
+	return forcedInclusionTimeoutReached(p.ctx, p.enforcedL1MessageSource, blocks, p.cfg.ForcedInclusionTimeoutSec, currentTimeSec)
+}
@@ -0,0 +1,49 @@
+package watcher
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/scroll-tech/go-ethereum/core/types"
+)
+
+// ErrL1MessageQueueGap indicates unbatched chunks whose L1 messages don't continue the message queue of the previous
+// batch, or of the previous chunk, which the L1 contract would reject. Manual fix is needed.
+var ErrL1MessageQueueGap = errors.New("L1 message queue discontinuity")
+
+// validateL1MessageContinuity checks that the chunks of the batch pop the L1 message queue right after the parent
+// batch and after each other, i.e. each chunk starts where the previous one ends and its L1 messages, skipped ones
+// included, are in ascending queue order and add up to the number recorded for the chunk.
+func (p *BatchProposer) validateL1MessageContinuity(proposal *batchProposal) error {
+	totalL1MessagePopped := proposal.batch.TotalL1MessagePoppedBefore
+	for i, chunk := range proposal.batch.Chunks {
+		dbChunk := proposal.dbChunks[i]
+		if dbChunk.TotalL1MessagesPoppedBefore != totalL1MessagePopped {
+			p.l1MessageQueueGapTotal.Inc()
+			return fmt.Errorf("%w: chunk index: %v, total l1 messages popped before: %v, expected: %v",
+				ErrL1MessageQueueGap, dbChunk.Index, dbChunk.TotalL1MessagesPoppedBefore, totalL1MessagePopped)
+		}
+
+		nextQueueIndex := totalL1MessagePopped
+		for _, block := range chunk.Blocks {
+			for _, tx := range block.Transactions {
+				if tx.Type != types.L1MessageTxType {
+					continue
+				}
+				if tx.Nonce < nextQueueIndex {
+					p.l1MessageQueueGapTotal.Inc()
+					return fmt.Errorf("%w: chunk index: %v, block number: %v, l1 message queue index: %v, expected at least: %v",
+						ErrL1MessageQueueGap, dbChunk.Index, block.Header.Number, tx.Nonce, nextQueueIndex)
+				}
+				nextQueueIndex = tx.Nonce + 1
+			}
+		}
+		if numL1Messages := nextQueueIndex - totalL1MessagePopped; numL1Messages != dbChunk.TotalL1MessagesPoppedInChunk {
+			p.l1MessageQueueGapTotal.Inc()
+			return fmt.Errorf("%w: chunk index: %v, l1 messages popped in chunk: %v, recorded: %v",
+				ErrL1MessageQueueGap, dbChunk.Index, numL1Messages, dbChunk.TotalL1MessagesPoppedInChunk)
+		}
+		totalL1MessagePopped = nextQueueIndex
+	}
+	return nil
+}
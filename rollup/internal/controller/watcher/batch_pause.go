@@ -0,0 +1,88 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/log"
+)
+
+// batchProposerName identifies the batch proposer in the PauseStore.
+const batchProposerName = "batch_proposer"
+
+// ErrBatchProposerPaused indicates a batch requested from a paused BatchProposer.
+var ErrBatchProposerPaused = errors.New("batch proposer is paused")
+
+// ProposerPauseState is the pause state of a proposer.
+type ProposerPauseState struct {
+	Paused bool   `json:"paused"`
+	Reason string `json:"reason,omitempty"`
+	// UpdatedAt is the time of the last pause or resume, nil if the proposer was never paused.
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// Pause halts the batch proposal until Resume is called, e.g. during an L1 contract migration, while the chunk
+// proposal continues. The pause is persisted so that it survives restarts. It waits for the batch being proposed,
+// if any, so that no batch is created once it returns. It fails if the batch store doesn't persist pause states.
+func (p *BatchProposer) Pause(ctx context.Context, reason string) error {
+	return p.setPaused(ctx, true, reason)
+}
+
+// Resume resumes the batch proposal halted by Pause.
+func (p *BatchProposer) Resume(ctx context.Context) error {
+	return p.setPaused(ctx, false, "")
+}
+
+// PauseState returns the pause state of the batch proposer.
+func (p *BatchProposer) PauseState(ctx context.Context) (*ProposerPauseState, error) {
+	if p.pauseStore == nil {
+		return &ProposerPauseState{}, nil
+	}
+	pause, err := p.pauseStore.GetProposerPause(ctx, batchProposerName)
+	if err != nil {
+		return nil, err
+	}
+	if pause == nil {
+		return &ProposerPauseState{}, nil
+	}
+	return &ProposerPauseState{Paused: pause.Paused, Reason: pause.Reason, UpdatedAt: &pause.UpdatedAt}, nil
+}
+
+func (p *BatchProposer) setPaused(ctx context.Context, paused bool, reason string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.pauseStore == nil {
+		return errors.New("batch store doesn't support pausing the batch proposer")
+	}
+	if err := p.pauseStore.SetProposerPause(ctx, batchProposerName, paused, reason); err != nil {
+		return err
+	}
+	p.setPausedGauge(paused)
+	log.Info("batch proposer pause state updated", "paused", paused, "reason", reason)
+	return nil
+}
+
+// isPaused returns whether the batch proposal is paused, reading the persisted pause state so that a pause set
+// by another instance is observed. It must be called with p.mu held.
+func (p *BatchProposer) isPaused() (bool, error) {
+	if p.pauseStore == nil {
+		return false, nil
+	}
+	pause, err := p.pauseStore.GetProposerPause(p.ctx, batchProposerName)
+	if err != nil {
+		return false, err
+	}
+	paused := pause != nil && pause.Paused
+	p.setPausedGauge(paused)
+	return paused, nil
+}
+
+func (p *BatchProposer) setPausedGauge(paused bool) {
+	if paused {
+		p.batchProposerPaused.Set(1)
+	} else {
+		p.batchProposerPaused.Set(0)
+	}
+}
@@ -2,9 +2,10 @@ package watcher
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
-	"time"
+	"sync"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -21,24 +22,47 @@ import (
 	"scroll-tech/rollup/internal/utils"
 )
 
+// ErrChunkCrossesForkBoundary indicates an unbatched chunk containing blocks of different fork regimes,
+// which can't be encoded by a single codec version. Manual fix is needed.
+var ErrChunkCrossesForkBoundary = errors.New("chunk crosses a fork boundary")
+
 // BatchProposer proposes batches based on available unbatched chunks.
 type BatchProposer struct {
 	ctx context.Context
-	db  *gorm.DB
+	cfg *config.BatchProposerConfig
 
-	batchOrm   *orm.Batch
-	chunkOrm   *orm.Chunk
-	l2BlockOrm *orm.L2Block
+	blockSource L2BlockSource
+	batchStore  BatchStore
+	clock       Clock
 
 	maxChunkNumPerBatch             uint64
 	maxL1CommitGasPerBatch          uint64
 	maxL1CommitCalldataSizePerBatch uint64
 	batchTimeoutSec                 uint64
 	gasCostIncreaseMultiplier       float64
-	forkMap                         map[uint64]bool
+	maxRowConsumptionPerBatch       uint64
+	forkHeights                     []uint64
 
 	chainCfg *params.ChainConfig
 
+	// dynamicMaxChunks is nil if the max chunk number per batch is static.
+	dynamicMaxChunks *dynamicMaxChunks
+	// congestionTimeout is nil if the batch timeout is static.
+	congestionTimeout *congestionTimeout
+	// pauseStore is nil if the batch store doesn't persist pause states.
+	pauseStore PauseStore
+	// stateStore is nil if the batch store doesn't persist proposer states.
+	stateStore StateStore
+	// daModeSelector is nil if the DA mode costs of the batches aren't compared.
+	daModeSelector *daModeSelector
+	// enforcedL1MessageSource is nil if the batch store doesn't provide the enforced L1 messages.
+	enforcedL1MessageSource EnforcedL1MessageSource
+
+	utilizationMetrics *batchUtilizationMetrics
+
+	// serializes the proposal loop and the force seals requested by operators.
+	mu sync.Mutex
+
 	batchProposerCircleTotal           prometheus.Counter
 	proposeBatchFailureTotal           prometheus.Counter
 	proposeBatchUpdateInfoTotal        prometheus.Counter
@@ -49,31 +73,54 @@ type BatchProposer struct {
 	batchChunksNum                     prometheus.Gauge
 	batchFirstBlockTimeoutReached      prometheus.Counter
 	batchChunksProposeNotEnoughTotal   prometheus.Counter
+	batchForceSealedTotal              prometheus.Counter
+	batchProposerPaused                prometheus.Gauge
+	l1MessageQueueGapTotal             prometheus.Counter
+	batchRowConsumption                prometheus.Gauge
+	batchForcedInclusionTimeoutReached prometheus.Counter
+}
+
+// ForceSealBatchResult is the outcome of a force seal of the unbatched chunks.
+type ForceSealBatchResult struct {
+	NumBatches      uint64 `json:"num_batches"`
+	StartBatchIndex uint64 `json:"start_batch_index,omitempty"`
+	EndBatchIndex   uint64 `json:"end_batch_index,omitempty"`
+	StartChunkIndex uint64 `json:"start_chunk_index,omitempty"`
+	EndChunkIndex   uint64 `json:"end_chunk_index,omitempty"`
 }
 
-// NewBatchProposer creates a new BatchProposer instance.
+// NewBatchProposer creates a new BatchProposer instance backed by the rollup database.
 func NewBatchProposer(ctx context.Context, cfg *config.BatchProposerConfig, chainCfg *params.ChainConfig, db *gorm.DB, reg prometheus.Registerer) *BatchProposer {
-	forkHeights, forkMap, _ := forks.CollectSortedForkHeights(chainCfg)
+	return NewBatchProposerWithBackend(ctx, cfg, chainCfg, orm.NewL2Block(db), newDBBatchStore(db), systemClock{}, reg)
+}
+
+// NewBatchProposerWithBackend creates a new BatchProposer instance reading chunk blocks from blockSource,
+// persisting batches to batchStore and using clock as the time source.
+func NewBatchProposerWithBackend(ctx context.Context, cfg *config.BatchProposerConfig, chainCfg *params.ChainConfig, blockSource L2BlockSource, batchStore BatchStore, clock Clock, reg prometheus.Registerer) *BatchProposer {
+	forkHeights, _, _ := forks.CollectSortedForkHeights(chainCfg)
 	log.Debug("new batch proposer",
 		"maxChunkNumPerBatch", cfg.MaxChunkNumPerBatch,
 		"maxL1CommitGasPerBatch", cfg.MaxL1CommitGasPerBatch,
 		"maxL1CommitCalldataSizePerBatch", cfg.MaxL1CommitCalldataSizePerBatch,
 		"batchTimeoutSec", cfg.BatchTimeoutSec,
 		"gasCostIncreaseMultiplier", cfg.GasCostIncreaseMultiplier,
+		"maxRowConsumptionPerBatch", cfg.MaxRowConsumptionPerBatch,
+		"forcedInclusionTimeoutSec", cfg.ForcedInclusionTimeoutSec,
 		"forkHeights", forkHeights)
 
 	p := &BatchProposer{
 		ctx:                             ctx,
-		db:                              db,
-		batchOrm:                        orm.NewBatch(db),
-		chunkOrm:                        orm.NewChunk(db),
-		l2BlockOrm:                      orm.NewL2Block(db),
+		cfg:                             cfg,
+		blockSource:                     blockSource,
+		batchStore:                      batchStore,
+		clock:                           clock,
 		maxChunkNumPerBatch:             cfg.MaxChunkNumPerBatch,
 		maxL1CommitGasPerBatch:          cfg.MaxL1CommitGasPerBatch,
 		maxL1CommitCalldataSizePerBatch: cfg.MaxL1CommitCalldataSizePerBatch,
 		batchTimeoutSec:                 cfg.BatchTimeoutSec,
 		gasCostIncreaseMultiplier:       cfg.GasCostIncreaseMultiplier,
-		forkMap:                         forkMap,
+		maxRowConsumptionPerBatch:       cfg.MaxRowConsumptionPerBatch,
+		forkHeights:                     forkHeights,
 		chainCfg:                        chainCfg,
 
 		batchProposerCircleTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
@@ -116,67 +163,248 @@ func NewBatchProposer(ctx context.Context, cfg *config.BatchProposerConfig, chai
 			Name: "rollup_propose_batch_chunks_propose_not_enough_total",
 			Help: "Total number of batch chunk propose not enough",
 		}),
+		batchForceSealedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_propose_batch_force_sealed_total",
+			Help: "Total number of batches sealed by force seal requests",
+		}),
+		batchProposerPaused: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_propose_batch_paused",
+			Help: "Whether the batch proposal is paused by the operators, 1 if paused and 0 otherwise",
+		}),
+		l1MessageQueueGapTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_propose_batch_l1_message_queue_gap_total",
+			Help: "Total number of batches not sealed because their L1 messages don't continue the message queue",
+		}),
+		batchRowConsumption: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_propose_batch_row_consumption",
+			Help: "The max row consumption over the sub-circuits of the latest proposed batch, only set if the row consumption per batch is limited",
+		}),
+		batchForcedInclusionTimeoutReached: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_propose_batch_forced_inclusion_timeout_reached_total",
+			Help: "Total times of batch sealed because an enforced transaction reached its inclusion deadline",
+		}),
+	}
+
+	p.utilizationMetrics = newBatchUtilizationMetrics(reg)
+
+	if cfg.DynamicMaxChunks != nil {
+		if feeSource, ok := batchStore.(BlobBaseFeeSource); ok {
+			p.dynamicMaxChunks = newDynamicMaxChunks(cfg.DynamicMaxChunks, cfg.MaxChunkNumPerBatch, feeSource, reg)
+		} else {
+			log.Warn("batch store doesn't provide the blob base fee, using static max chunk number per batch")
+		}
+	}
+
+	p.pauseStore, _ = batchStore.(PauseStore)
+	p.stateStore, _ = batchStore.(StateStore)
+
+	if cfg.ForcedInclusionTimeoutSec > 0 {
+		p.enforcedL1MessageSource, _ = batchStore.(EnforcedL1MessageSource)
+		if p.enforcedL1MessageSource == nil {
+			log.Warn("batch store doesn't provide the enforced L1 messages, disabling the batch forced inclusion timeout")
+		}
+	}
+
+	if cfg.DAModeSelection != nil {
+		baseFeeSource, _ := batchStore.(BaseFeeSource)
+		blobBaseFeeSource, _ := batchStore.(BlobBaseFeeSource)
+		if baseFeeSource == nil || blobBaseFeeSource == nil {
+			log.Warn("batch store doesn't provide the L1 fees, disabling the DA mode cost comparison")
+		} else {
+			blobFeeHistorySource, _ := batchStore.(BlobBaseFeeHistorySource)
+			if cfg.DAModeSelection.BlobFeeSpikeMultiplier > 0 && blobFeeHistorySource == nil {
+				log.Warn("batch store doesn't provide the average blob base fee, disabling the blob fee spike detection")
+			}
+			p.daModeSelector = newDAModeSelector(cfg.DAModeSelection, baseFeeSource, blobBaseFeeSource, blobFeeHistorySource, reg)
+		}
+	}
+
+	if cfg.CongestionTimeout != nil {
+		baseFeeSource, _ := batchStore.(BaseFeeSource)
+		blobBaseFeeSource, _ := batchStore.(BlobBaseFeeSource)
+		if baseFeeSource == nil && blobBaseFeeSource == nil {
+			log.Warn("batch store doesn't provide the L1 fees, using static batch timeout")
+		} else {
+			p.congestionTimeout = newCongestionTimeout(cfg.CongestionTimeout, baseFeeSource, blobBaseFeeSource, reg)
+		}
 	}
 
 	return p
 }
 
 // TryProposeBatch tries to propose a new batches.
-func (p *BatchProposer) TryProposeBatch() {
+// It returns true if a batch is proposed, which indicates that more pending chunks may be available.
+func (p *BatchProposer) TryProposeBatch() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	p.batchProposerCircleTotal.Inc()
-	if err := p.proposeBatch(); err != nil {
+	paused, err := p.isPaused()
+	if err != nil {
+		p.proposeBatchFailureTotal.Inc()
+		log.Error("failed to get batch proposer pause state", "err", err)
+		return false
+	}
+	if paused {
+		log.Debug("batch proposer is paused")
+		return false
+	}
+
+	proposed, err := p.proposeBatch()
+	if err != nil {
 		p.proposeBatchFailureTotal.Inc()
 		log.Error("proposeBatchChunks failed", "err", err)
-		return
+		return false
 	}
+	return proposed
 }
 
-func (p *BatchProposer) updateDBBatchInfo(batch *encoding.Batch, codecVersion encoding.CodecVersion) error {
-	err := p.db.Transaction(func(dbTX *gorm.DB) error {
-		dbBatch, dbErr := p.batchOrm.InsertBatch(p.ctx, batch, codecVersion, dbTX)
-		if dbErr != nil {
-			log.Warn("BatchProposer.updateBatchInfoInDB insert batch failure", "index", batch.Index, "parent hash", batch.ParentBatchHash.Hex(), "error", dbErr)
-			return dbErr
+// ForceSealBatches seals all the unbatched chunks into batches right now, without waiting for the batch timeout
+// or for a batch to be full, e.g. before a planned L1 contract upgrade. The dynamic max chunk number per batch is
+// bypassed too, but the L1 limits of a batch and the fork boundaries still apply, so the unbatched chunks may be
+// split into several batches. It fails if the batch proposer is paused.
+func (p *BatchProposer) ForceSealBatches(ctx context.Context) (*ForceSealBatchResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	result := &ForceSealBatchResult{}
+	paused, err := p.isPaused()
+	if err != nil {
+		return result, fmt.Errorf("failed to get batch proposer pause state: %w", err)
+	}
+	if paused {
+		return result, ErrBatchProposerPaused
+	}
+	for ctx.Err() == nil {
+		proposal, err := p.buildBatch(true)
+		if err != nil {
+			return result, fmt.Errorf("failed to force seal batch: %w", err)
 		}
-		if dbErr = p.chunkOrm.UpdateBatchHashInRange(p.ctx, dbBatch.StartChunkIndex, dbBatch.EndChunkIndex, dbBatch.Hash, dbTX); dbErr != nil {
-			log.Warn("BatchProposer.UpdateBatchHashInRange update the chunk's batch hash failure", "hash", dbBatch.Hash, "error", dbErr)
-			return dbErr
+		if proposal == nil {
+			break
 		}
-		return nil
-	})
-	if err != nil {
+		if err := p.validateL1MessageContinuity(proposal); err != nil {
+			return result, fmt.Errorf("failed to validate force sealed batch: %w", err)
+		}
+		if err := p.compareDAModeCosts(proposal); err != nil {
+			return result, fmt.Errorf("failed to compare DA mode costs of force sealed batch: %w", err)
+		}
+
+		p.recordBatchMetrics(proposal.metrics)
+		p.recordBatchUtilization(proposal.batch, proposal.codecVersion, proposal.metrics)
+		p.recordBatchRowConsumption(proposal.batch)
+		p.proposeBatchUpdateInfoTotal.Inc()
+		if err := p.batchStore.InsertBatch(p.ctx, proposal.batch, proposal.codecVersion); err != nil {
+			p.proposeBatchUpdateInfoFailureTotal.Inc()
+			return result, fmt.Errorf("failed to insert force sealed batch: %w", err)
+		}
+
+		numChunks := len(proposal.batch.Chunks)
+		if result.NumBatches == 0 {
+			result.StartBatchIndex = proposal.batch.Index
+			result.StartChunkIndex = proposal.dbChunks[0].Index
+		}
+		result.EndBatchIndex = proposal.batch.Index
+		result.EndChunkIndex = proposal.dbChunks[numChunks-1].Index
+		result.NumBatches++
+		p.batchForceSealedTotal.Inc()
+	}
+
+	log.Info("force sealed unbatched chunks", "batches", result.NumBatches, "start batch index", result.StartBatchIndex, "end batch index", result.EndBatchIndex,
+		"start chunk index", result.StartChunkIndex, "end chunk index", result.EndChunkIndex)
+	return result, ctx.Err()
+}
+
+func (p *BatchProposer) updateDBBatchInfo(batch *encoding.Batch, codecVersion encoding.CodecVersion) error {
+	p.proposeBatchUpdateInfoTotal.Inc()
+	if err := p.batchStore.InsertBatch(p.ctx, batch, codecVersion); err != nil {
 		p.proposeBatchUpdateInfoFailureTotal.Inc()
 		log.Error("update batch info in db failed", "err", err)
+		return err
 	}
 	return nil
 }
 
-func (p *BatchProposer) proposeBatch() error {
-	unbatchedChunkIndex, err := p.batchOrm.GetFirstUnbatchedChunkIndex(p.ctx)
+func (p *BatchProposer) proposeBatch() (bool, error) {
+	proposal, err := p.buildBatch(false)
 	if err != nil {
-		return err
+		return false, err
+	}
+	if proposal == nil || proposal.constraint == "" {
+		return false, nil
+	}
+	if err := p.validateL1MessageContinuity(proposal); err != nil {
+		return false, err
+	}
+	if err := p.compareDAModeCosts(proposal); err != nil {
+		return false, err
 	}
 
-	// select at most p.maxChunkNumPerBatch chunks
-	dbChunks, err := p.chunkOrm.GetChunksGEIndex(p.ctx, unbatchedChunkIndex, int(p.maxChunkNumPerBatch))
+	p.recordBatchMetrics(proposal.metrics)
+	p.recordBatchUtilization(proposal.batch, proposal.codecVersion, proposal.metrics)
+	p.recordBatchRowConsumption(proposal.batch)
+	if err := p.updateDBBatchInfo(proposal.batch, proposal.codecVersion); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// batchProposal is the next batch built from the unbatched chunks.
+type batchProposal struct {
+	batch        *encoding.Batch
+	codecVersion encoding.CodecVersion
+	metrics      *utils.BatchMetrics
+	// cheaperDAMode is the DA mode which is the cheaper to commit the batch at the latest L1 fees, empty if not compared.
+	// The batch is committed in the DA mode of its codec version regardless.
+	cheaperDAMode string
+	// dbChunks are the unbatched chunks considered for the batch, batch.Chunks is a prefix of them.
+	dbChunks []*orm.Chunk
+	// constraint is the constraint sealing the batch, empty if the batch is not ready to be sealed.
+	constraint string
+}
+
+// buildBatch builds the next batch from the unbatched chunks without persisting it.
+// It returns nil if there is no unbatched chunk. If force is true, the batch is sealed even if neither
+// the batch is full nor the batch timeout is reached.
+func (p *BatchProposer) buildBatch(force bool) (*batchProposal, error) {
+	unbatchedChunkIndex, err := p.batchStore.GetFirstUnbatchedChunkIndex(p.ctx)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	maxChunkNumPerBatch := p.maxChunkNumPerBatch
+	if p.dynamicMaxChunks != nil && !force {
+		maxChunkNumPerBatch = p.dynamicMaxChunks.maxChunkNum(p.ctx)
+	}
+
+	// select at most maxChunkNumPerBatch chunks
+	dbChunks, err := p.batchStore.GetChunksGEIndex(p.ctx, unbatchedChunkIndex, int(maxChunkNumPerBatch))
+	if err != nil {
+		return nil, err
 	}
 
 	if len(dbChunks) == 0 {
-		return nil
+		return nil, nil
 	}
 
-	maxChunksThisBatch := p.maxChunkNumPerBatch
+	maxChunksThisBatch := maxChunkNumPerBatch
+	maxChunksConstraint := batchConstraintMaxChunkNum
+	batchForkHeight, _ := forks.BlockRange(dbChunks[0].StartBlockNumber, p.forkHeights)
 	for i, chunk := range dbChunks {
-		// if a chunk is starting at a fork boundary, only consider earlier chunks
-		if i != 0 && p.forkMap[chunk.StartBlockNumber] {
+		// a batch never groups chunks of different fork regimes, only consider the chunks before the first one of a later regime
+		chunkForkHeight, nextForkHeight := forks.BlockRange(chunk.StartBlockNumber, p.forkHeights)
+		if chunkForkHeight != batchForkHeight {
 			dbChunks = dbChunks[:i]
 			if uint64(len(dbChunks)) < maxChunksThisBatch {
 				maxChunksThisBatch = uint64(len(dbChunks))
+				maxChunksConstraint = batchConstraintForkBoundary
 			}
 			break
 		}
+		if chunk.EndBlockNumber >= nextForkHeight {
+			return nil, fmt.Errorf("%w: chunk index: %v, start block number: %v, end block number: %v, fork height: %v",
+				ErrChunkCrossesForkBoundary, chunk.Index, chunk.StartBlockNumber, chunk.EndBlockNumber, nextForkHeight)
+		}
 	}
 
 	codecVersion := encoding.CodecV0
@@ -186,88 +414,119 @@ func (p *BatchProposer) proposeBatch() error {
 
 	daChunks, err := p.getDAChunks(dbChunks)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	dbParentBatch, err := p.batchOrm.GetLatestBatch(p.ctx)
+	dbParentBatch, err := p.batchStore.GetLatestBatch(p.ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var batch encoding.Batch
 	batch.Index = dbParentBatch.Index + 1
 	batch.ParentBatchHash = common.HexToHash(dbParentBatch.Hash)
 	parentBatchEndBlockNumber := daChunks[0].Blocks[0].Header.Number.Uint64() - 1
-	parentBatchCodecVersion := encoding.CodecV0
-	// Genesis batch uses codecv0 encoding, otherwise using bernoulli fork to choose codec version.
-	if dbParentBatch.Index > 0 && p.chainCfg.IsBernoulli(new(big.Int).SetUint64(parentBatchEndBlockNumber)) {
-		parentBatchCodecVersion = encoding.CodecV1
-	}
+	parentBatchCodecVersion := utils.GetBatchCodecVersion(p.chainCfg, dbParentBatch.Index, parentBatchEndBlockNumber)
 	batch.TotalL1MessagePoppedBefore, err = utils.GetTotalL1MessagePoppedBeforeBatch(dbParentBatch.BatchHeader, parentBatchCodecVersion)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	proposal := &batchProposal{batch: &batch, codecVersion: codecVersion, dbChunks: dbChunks}
+	rowConsumption := batchRowConsumption{}
 	for i, chunk := range daChunks {
 		batch.Chunks = append(batch.Chunks, chunk)
 		metrics, calcErr := utils.CalculateBatchMetrics(&batch, codecVersion)
 		if calcErr != nil {
-			return fmt.Errorf("failed to calculate batch metrics: %w", calcErr)
+			return nil, fmt.Errorf("failed to calculate batch metrics: %w", calcErr)
+		}
+		var crcMax uint64
+		if p.maxRowConsumptionPerBatch > 0 {
+			if crcMax, calcErr = rowConsumption.addChunk(chunk); calcErr != nil {
+				return nil, fmt.Errorf("failed to calculate batch row consumption: %w", calcErr)
+			}
 		}
 		totalOverEstimateL1CommitGas := uint64(p.gasCostIncreaseMultiplier * float64(metrics.L1CommitGas))
-		if metrics.L1CommitCalldataSize > p.maxL1CommitCalldataSizePerBatch ||
-			totalOverEstimateL1CommitGas > p.maxL1CommitGasPerBatch ||
-			metrics.L1CommitBlobSize > maxBlobSize {
+		switch {
+		case metrics.L1CommitCalldataSize > p.maxL1CommitCalldataSizePerBatch:
+			proposal.constraint = batchConstraintL1CommitCalldataSize
+		case totalOverEstimateL1CommitGas > p.maxL1CommitGasPerBatch:
+			proposal.constraint = batchConstraintL1CommitGas
+		case metrics.L1CommitBlobSize > maxBlobSize:
+			proposal.constraint = batchConstraintBlobSize
+		case p.maxRowConsumptionPerBatch > 0 && crcMax > p.maxRowConsumptionPerBatch:
+			proposal.constraint = batchConstraintRowConsumption
+		}
+		if proposal.constraint != "" {
 			if i == 0 {
 				// The first chunk exceeds hard limits, which indicates a bug in the chunk-proposer, manual fix is needed.
-				return fmt.Errorf("the first chunk exceeds limits; start block number: %v, end block number: %v, limits: %+v, maxChunkNum: %v, maxL1CommitCalldataSize: %v, maxL1CommitGas: %v, maxBlobSize: %v",
-					dbChunks[0].StartBlockNumber, dbChunks[0].EndBlockNumber, metrics, p.maxChunkNumPerBatch, p.maxL1CommitCalldataSizePerBatch, p.maxL1CommitGasPerBatch, maxBlobSize)
+				return nil, fmt.Errorf("the first chunk exceeds limits; start block number: %v, end block number: %v, limits: %+v, rowConsumption: %v, maxChunkNum: %v, maxL1CommitCalldataSize: %v, maxL1CommitGas: %v, maxBlobSize: %v, maxRowConsumption: %v",
+					dbChunks[0].StartBlockNumber, dbChunks[0].EndBlockNumber, metrics, crcMax, maxChunkNumPerBatch, p.maxL1CommitCalldataSizePerBatch, p.maxL1CommitGasPerBatch, maxBlobSize, p.maxRowConsumptionPerBatch)
 			}
 
 			log.Debug("breaking limit condition in batching",
 				"currentL1CommitCalldataSize", metrics.L1CommitCalldataSize,
 				"maxL1CommitCalldataSizePerBatch", p.maxL1CommitCalldataSizePerBatch,
 				"currentOverEstimateL1CommitGas", totalOverEstimateL1CommitGas,
-				"maxL1CommitGasPerBatch", p.maxL1CommitGasPerBatch)
+				"maxL1CommitGasPerBatch", p.maxL1CommitGasPerBatch,
+				"currentRowConsumption", crcMax,
+				"maxRowConsumptionPerBatch", p.maxRowConsumptionPerBatch)
 
 			batch.Chunks = batch.Chunks[:len(batch.Chunks)-1]
 
-			metrics, err := utils.CalculateBatchMetrics(&batch, codecVersion)
+			proposal.metrics, err = utils.CalculateBatchMetrics(&batch, codecVersion)
 			if err != nil {
-				return fmt.Errorf("failed to calculate batch metrics: %w", err)
+				return nil, fmt.Errorf("failed to calculate batch metrics: %w", err)
 			}
-
-			p.recordBatchMetrics(metrics)
-			return p.updateDBBatchInfo(&batch, codecVersion)
+			return proposal, nil
 		}
 	}
 
 	metrics, calcErr := utils.CalculateBatchMetrics(&batch, codecVersion)
 	if calcErr != nil {
-		return fmt.Errorf("failed to calculate batch metrics: %w", calcErr)
+		return nil, fmt.Errorf("failed to calculate batch metrics: %w", calcErr)
 	}
-	currentTimeSec := uint64(time.Now().Unix())
-	if metrics.FirstBlockTimestamp+p.batchTimeoutSec < currentTimeSec || metrics.NumChunks == maxChunksThisBatch {
-		log.Info("reached maximum number of chunks in batch or first block timeout",
-			"chunk count", metrics.NumChunks,
-			"start block number", dbChunks[0].StartBlockNumber,
-			"start block timestamp", dbChunks[0].StartBlockTime,
-			"current time", currentTimeSec)
-
-		p.batchFirstBlockTimeoutReached.Inc()
-		p.recordBatchMetrics(metrics)
-		return p.updateDBBatchInfo(&batch, codecVersion)
+	proposal.metrics = metrics
+	currentTimeSec := uint64(p.clock.Now().Unix())
+	batchTimeoutSec := p.batchTimeoutSec
+	if p.congestionTimeout != nil {
+		batchTimeoutSec = p.congestionTimeout.batchTimeoutSec(p.ctx, p.batchTimeoutSec)
+	}
+	timeoutReached := metrics.FirstBlockTimestamp+batchTimeoutSec < currentTimeSec
+	forcedInclusionTimeoutReached, err := p.forcedInclusionTimeoutReached(&batch, currentTimeSec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check forced inclusion timeout: %w", err)
+	}
+	if timeoutReached || forcedInclusionTimeoutReached || metrics.NumChunks == maxChunksThisBatch || force {
+		proposal.constraint = maxChunksConstraint
+		if timeoutReached {
+			proposal.constraint = batchConstraintTimeout
+		} else if forcedInclusionTimeoutReached {
+			proposal.constraint = batchConstraintForcedInclusionTimeout
+			p.batchForcedInclusionTimeoutReached.Inc()
+		} else if metrics.NumChunks < maxChunksThisBatch {
+			proposal.constraint = batchConstraintForced
+		}
+		if proposal.constraint != batchConstraintForced {
+			log.Info("reached maximum number of chunks in batch or first block timeout",
+				"chunk count", metrics.NumChunks,
+				"start block number", dbChunks[0].StartBlockNumber,
+				"start block timestamp", dbChunks[0].StartBlockTime,
+				"current time", currentTimeSec)
+			p.batchFirstBlockTimeoutReached.Inc()
+		}
+		return proposal, nil
 	}
 
 	log.Debug("pending chunks do not reach one of the constraints or contain a timeout block")
 	p.batchChunksProposeNotEnoughTotal.Inc()
-	return nil
+	return proposal, nil
 }
 
 func (p *BatchProposer) getDAChunks(dbChunks []*orm.Chunk) ([]*encoding.Chunk, error) {
 	chunks := make([]*encoding.Chunk, len(dbChunks))
 	for i, c := range dbChunks {
-		blocks, err := p.l2BlockOrm.GetL2BlocksInRange(p.ctx, c.StartBlockNumber, c.EndBlockNumber)
+		blocks, err := p.blockSource.GetL2BlocksInRange(p.ctx, c.StartBlockNumber, c.EndBlockNumber)
 		if err != nil {
 			log.Error("Failed to fetch blocks", "start number", c.StartBlockNumber, "end number", c.EndBlockNumber, "error", err)
 			return nil, err
@@ -5,6 +5,7 @@ import (
 	"math"
 	"math/big"
 	"testing"
+	"time"
 
 	"github.com/scroll-tech/go-ethereum/common"
 	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
@@ -591,3 +592,322 @@ func testBatchProposerBlobSizeLimit(t *testing.T) {
 		assert.Equal(t, expected, batch.EndChunkIndex)
 	}
 }
+
+func TestBatchProposerForkBoundary(t *testing.T) {
+	store := newMemoryStore(t, 4)
+	bp := newTestBatchProposer(store, newMemoryClock(store), &params.ChainConfig{CurieBlock: big.NewInt(3)}, nil)
+
+	// the batch stops before the first chunk of the next fork regime.
+	store.chunks = []*encoding.Chunk{{Blocks: store.blocks[0:2]}, {Blocks: store.blocks[2:4]}}
+	result, err := bp.SimulateProposeBatch(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.True(t, result.Sealed)
+	assert.Equal(t, "fork_boundary", result.BindingConstraint)
+	assert.Equal(t, uint64(1), result.EndChunkIndex)
+	assert.Equal(t, uint64(2), result.EndBlockNumber)
+
+	// a chunk crossing the fork height can't be batched.
+	store.chunks = []*encoding.Chunk{{Blocks: store.blocks[0:1]}, {Blocks: store.blocks[1:3]}, {Blocks: store.blocks[3:4]}}
+	_, err = bp.SimulateProposeBatch(context.Background(), nil)
+	assert.ErrorIs(t, err, ErrChunkCrossesForkBoundary)
+	assert.False(t, bp.TryProposeBatch())
+	assert.Empty(t, store.batches)
+}
+
+func TestBatchProposerForceSealBatches(t *testing.T) {
+	store := newMemoryStore(t, 5)
+	store.chunkEach()
+	bp := newTestBatchProposer(store, newMemoryClock(store), nil, func(cfg *config.BatchProposerConfig) {
+		cfg.MaxChunkNumPerBatch = 2
+		cfg.BatchTimeoutSec = math.MaxUint32
+	})
+
+	// the unbatched chunks are sealed without waiting for the batch timeout, respecting the max number of chunks.
+	result, err := bp.ForceSealBatches(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, &ForceSealBatchResult{NumBatches: 3, StartBatchIndex: 1, EndBatchIndex: 3, StartChunkIndex: 1, EndChunkIndex: 5}, result)
+	assert.Len(t, store.batches, 3)
+	assert.Len(t, store.batches[2].Chunks, 1)
+
+	// nothing is left to seal.
+	result, err = bp.ForceSealBatches(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), result.NumBatches)
+	assert.False(t, bp.TryProposeBatch())
+}
+
+func TestBatchProposerSimulateProposeBatch(t *testing.T) {
+	store := newMemoryStore(t, 3)
+	store.chunkEach()
+	clock := newMemoryClock(store)
+	bp := newTestBatchProposer(store, clock, nil, nil)
+
+	// no constraint is reached with the current configuration.
+	result, err := bp.SimulateProposeBatch(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.False(t, result.Sealed)
+	assert.Empty(t, result.BindingConstraint)
+	assert.Equal(t, uint64(1), result.Index)
+	assert.Equal(t, uint64(1), result.StartChunkIndex)
+	assert.Equal(t, uint64(3), result.EndChunkIndex)
+	assert.Equal(t, []string{"chunk-1", "chunk-2", "chunk-3"}, result.ChunkHashes)
+	assert.Equal(t, uint64(3), result.EndBlockNumber)
+	assert.NotZero(t, result.L1CommitGas)
+
+	// preview a lower max chunk number.
+	previewCfg := *bp.cfg
+	previewCfg.MaxChunkNumPerBatch = 2
+	result, err = bp.SimulateProposeBatch(context.Background(), &previewCfg)
+	assert.NoError(t, err)
+	assert.True(t, result.Sealed)
+	assert.Equal(t, "max_chunk_num_per_batch", result.BindingConstraint)
+	assert.Equal(t, uint64(2), result.EndChunkIndex)
+
+	// preview a lower l1 commit calldata size limit, only fitting the first chunk.
+	previewCfg = *bp.cfg
+	previewCfg.MaxL1CommitCalldataSizePerBatch = result.L1CommitCalldataSize / 2
+	result, err = bp.SimulateProposeBatch(context.Background(), &previewCfg)
+	assert.NoError(t, err)
+	assert.True(t, result.Sealed)
+	assert.Equal(t, "max_l1_commit_calldata_size_per_batch", result.BindingConstraint)
+	assert.Equal(t, uint64(1), result.EndChunkIndex)
+
+	// the current configuration reaches the batch timeout once the clock advances.
+	clock.now = clock.now.Add(301 * time.Second)
+	result, err = bp.SimulateProposeBatch(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.True(t, result.Sealed)
+	assert.Equal(t, "batch_timeout_sec", result.BindingConstraint)
+	assert.Equal(t, uint64(3), result.EndChunkIndex)
+
+	// the simulations don't persist any batch.
+	assert.Empty(t, store.batches)
+	assert.True(t, bp.TryProposeBatch())
+	assert.Len(t, store.batches, 1)
+	assert.Len(t, store.batches[0].Chunks, 3)
+
+	result, err = bp.SimulateProposeBatch(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+}
+
+func TestBatchProposerSimulateDynamicMaxChunks(t *testing.T) {
+	store := newMemoryStore(t, 6)
+	store.chunkEach()
+	fees := &feeStore{memoryStore: store}
+	bp := newTestBatchProposer(fees, newMemoryClock(store), nil, func(cfg *config.BatchProposerConfig) {
+		cfg.BatchTimeoutSec = math.MaxUint32
+		cfg.DynamicMaxChunks = &config.DynamicMaxChunksConfig{MinChunkNumPerBatch: 2, LowBlobBaseFee: 100, HighBlobBaseFee: 1100, HysteresisChunkNum: 3}
+	})
+
+	// cheap blobs lower the max chunk number to its lower bound.
+	fees.blobBaseFee = 50
+	assert.True(t, bp.TryProposeBatch())
+	assert.Len(t, store.batches[0].Chunks, 2)
+
+	// the target moves less than the hysteresis, the running configuration is simulated from the current max chunk number.
+	fees.blobBaseFee = 300
+	result, err := bp.SimulateProposeBatch(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "max_chunk_num_per_batch", result.BindingConstraint)
+	assert.Equal(t, uint64(4), result.EndChunkIndex)
+
+	// a previewed configuration starts from the upper bound.
+	previewCfg := *bp.cfg
+	result, err = bp.SimulateProposeBatch(context.Background(), &previewCfg)
+	assert.NoError(t, err)
+	assert.Equal(t, "max_chunk_num_per_batch", result.BindingConstraint)
+	assert.Equal(t, uint64(5), result.EndChunkIndex)
+}
+
+func TestBatchProposerL1MessageContinuity(t *testing.T) {
+	store := newMemoryStore(t, 3)
+	// the first and the last chunks pop the same L1 message.
+	store.blocks[0].Transactions[0].Type = gethTypes.L1MessageTxType
+	store.blocks[0].Transactions[0].Nonce = 0
+	store.blocks[2].Transactions[0].Type = gethTypes.L1MessageTxType
+	store.blocks[2].Transactions[0].Nonce = 0
+	store.chunkEach()
+	bp := newTestBatchProposer(store, newMemoryClock(store), nil, func(cfg *config.BatchProposerConfig) {
+		cfg.MaxChunkNumPerBatch = 2
+		cfg.BatchTimeoutSec = math.MaxUint32
+	})
+
+	// the first two chunks continue the message queue.
+	assert.True(t, bp.TryProposeBatch())
+	assert.Len(t, store.batches, 1)
+
+	// the last chunk pops an L1 message already popped, the batch isn't sealed.
+	_, err := bp.ForceSealBatches(context.Background())
+	assert.ErrorIs(t, err, ErrL1MessageQueueGap)
+	_, err = bp.SimulateProposeBatch(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Len(t, store.batches, 1)
+}
+
+func TestBatchProposerMaxRowConsumptionPerBatch(t *testing.T) {
+	// every block of the trace consumes a single row.
+	store := newMemoryStore(t, 30)
+	for i := 0; i < 30; i += 5 {
+		store.chunks = append(store.chunks, &encoding.Chunk{Blocks: store.blocks[i : i+5]})
+	}
+	bp := newTestBatchProposer(store, newMemoryClock(store), nil, func(cfg *config.BatchProposerConfig) {
+		cfg.MaxChunkNumPerBatch = 6
+		cfg.BatchTimeoutSec = math.MaxUint32
+		cfg.MaxRowConsumptionPerBatch = 12
+	})
+
+	result, err := bp.SimulateProposeBatch(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.True(t, result.Sealed)
+	assert.Equal(t, "max_row_consumption_per_batch", result.BindingConstraint)
+	assert.Equal(t, uint64(2), result.EndChunkIndex)
+	assert.Equal(t, uint64(10), result.RowConsumption)
+
+	// the row consumption per batch isn't limited if not set.
+	previewCfg := *bp.cfg
+	previewCfg.MaxRowConsumptionPerBatch = 0
+	result, err = bp.SimulateProposeBatch(context.Background(), &previewCfg)
+	assert.NoError(t, err)
+	assert.Equal(t, "max_chunk_num_per_batch", result.BindingConstraint)
+	assert.Equal(t, uint64(6), result.EndChunkIndex)
+	assert.Zero(t, result.RowConsumption)
+
+	// the last two chunks reach neither the row consumption limit nor the max chunk number.
+	for bp.TryProposeBatch() {
+	}
+	assert.Len(t, store.batches, 2)
+	for _, batch := range store.batches {
+		assert.Len(t, batch.Chunks, 2)
+	}
+}
+
+func TestBatchProposerForcedInclusionTimeout(t *testing.T) {
+	store := newMemoryStore(t, 3)
+	store.blocks[1].Transactions[0].Type = gethTypes.L1MessageTxType
+	store.blocks[1].Transactions[0].Nonce = 0
+	store.chunkEach()
+	clock := newMemoryClock(store)
+	bp := newTestBatchProposer(store, clock, nil, func(cfg *config.BatchProposerConfig) {
+		cfg.BatchTimeoutSec = 3600
+		cfg.ForcedInclusionTimeoutSec = 60
+	})
+
+	// the L1 message isn't enforced, only the batch timeout applies.
+	clock.now = clock.now.Add(61 * time.Second)
+	assert.False(t, bp.TryProposeBatch())
+
+	store.enforcedQueueIndices = map[uint64]bool{0: true}
+	result, err := bp.SimulateProposeBatch(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.True(t, result.Sealed)
+	assert.Equal(t, "forced_inclusion_timeout_sec", result.BindingConstraint)
+	assert.Equal(t, uint64(3), result.EndChunkIndex)
+
+	assert.True(t, bp.TryProposeBatch())
+	assert.Len(t, store.batches, 1)
+	assert.Len(t, store.batches[0].Chunks, 3)
+}
+
+func TestBatchProposerForcedInclusionTimeoutFromEnqueueTime(t *testing.T) {
+	store := newMemoryStore(t, 3)
+	store.blocks[1].Transactions[0].Type = gethTypes.L1MessageTxType
+	store.blocks[1].Transactions[0].Nonce = 0
+	store.chunkEach()
+	store.enforcedQueueIndices = map[uint64]bool{0: true}
+	clock := &memoryClock{now: time.Unix(int64(store.blocks[1].Header.Time), 0)}
+	bp := newTestBatchProposer(store, clock, nil, func(cfg *config.BatchProposerConfig) {
+		cfg.BatchTimeoutSec = 3600
+		cfg.ForcedInclusionTimeoutSec = 60
+	})
+
+	// without enqueue time, the wait is counted from the L2 block including the enforced transaction.
+	assert.False(t, bp.TryProposeBatch())
+
+	// the enforced transaction was queued on L1 long before the L2 block including it.
+	store.enqueueTimes = map[uint64]uint64{0: store.blocks[1].Header.Time - 61}
+	assert.True(t, bp.TryProposeBatch())
+	assert.Len(t, store.batches, 1)
+}
+
+// feeStore is a memoryStore providing the latest L1 fees.
+type feeStore struct {
+	*memoryStore
+	baseFee     uint64
+	blobBaseFee uint64
+}
+
+func (s *feeStore) GetLatestBaseFee(_ context.Context) (uint64, error) {
+	return s.baseFee, nil
+}
+
+func (s *feeStore) GetLatestBlobBaseFee(_ context.Context) (uint64, error) {
+	return s.blobBaseFee, nil
+}
+
+func TestBatchProposerDAModeSelection(t *testing.T) {
+	store := newMemoryStore(t, 4)
+	store.chunkEach()
+	fees := &feeStore{memoryStore: store}
+	bp := newTestBatchProposer(fees, newMemoryClock(store), &params.ChainConfig{BernoulliBlock: big.NewInt(0)}, func(cfg *config.BatchProposerConfig) {
+		cfg.MaxChunkNumPerBatch = 2
+		cfg.DAModeSelection = &config.DAModeSelectionConfig{MinSavingPercent: 10}
+	})
+
+	// blobs are cheaper while the blob base fee is low.
+	fees.baseFee, fees.blobBaseFee = 1_000_000_000, 1
+	result, err := bp.SimulateProposeBatch(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "blob", result.CheaperDAMode)
+	assert.Equal(t, encoding.CodecV1, result.CodecVersion)
+	assert.NotZero(t, result.L1CommitBlobSize)
+
+	// calldata is cheaper once a blob costs more than the calldata of the batch, but the batch is still
+	// committed in blobs after the Bernoulli fork.
+	fees.baseFee, fees.blobBaseFee = 1, 1_000_000_000
+	result, err = bp.SimulateProposeBatch(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "calldata", result.CheaperDAMode)
+	assert.Equal(t, encoding.CodecV1, result.CodecVersion)
+	assert.NotZero(t, result.L1CommitBlobSize)
+
+	assert.True(t, bp.TryProposeBatch())
+	fees.baseFee, fees.blobBaseFee = 1_000_000_000, 1
+	assert.True(t, bp.TryProposeBatch())
+	assert.Equal(t, []encoding.CodecVersion{encoding.CodecV1, encoding.CodecV1}, store.batchCodecVersions)
+}
+
+type feeHistoryStore struct {
+	*feeStore
+	averageBlobBaseFee uint64
+}
+
+func (s *feeHistoryStore) GetAverageBlobBaseFee(_ context.Context, _ uint64) (uint64, error) {
+	return s.averageBlobBaseFee, nil
+}
+
+func TestBatchProposerBlobFeeSpike(t *testing.T) {
+	store := newMemoryStore(t, 4)
+	store.chunkEach()
+	fees := &feeHistoryStore{feeStore: &feeStore{memoryStore: store}}
+	bp := newTestBatchProposer(fees, newMemoryClock(store), &params.ChainConfig{BernoulliBlock: big.NewInt(0)}, func(cfg *config.BatchProposerConfig) {
+		cfg.MaxChunkNumPerBatch = 2
+		cfg.DAModeSelection = &config.DAModeSelectionConfig{BlobFeeSpikeMultiplier: 3}
+	})
+
+	// blobs are cheaper, and the blob base fee is in line with its trailing average.
+	fees.baseFee, fees.blobBaseFee, fees.averageBlobBaseFee = 1_000_000_000, 2, 1
+	result, err := bp.SimulateProposeBatch(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "blob", result.CheaperDAMode)
+
+	// the batches don't fall back to calldata while the blob base fee spikes.
+	fees.blobBaseFee = 4
+	result, err = bp.SimulateProposeBatch(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "blob", result.CheaperDAMode)
+	assert.Equal(t, encoding.CodecV1, result.CodecVersion)
+
+	assert.True(t, bp.TryProposeBatch())
+	assert.Equal(t, []encoding.CodecVersion{encoding.CodecV1}, store.batchCodecVersions)
+}
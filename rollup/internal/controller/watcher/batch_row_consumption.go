@@ -0,0 +1,58 @@
+package watcher
+
+import (
+	"fmt"
+
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/common/types/encoding"
+)
+
+// batchRowConsumption accumulates the row consumption of the chunks of a batch per sub-circuit,
+// since a batch-prover task proves the rows of all the chunks of the batch.
+type batchRowConsumption map[string]uint64
+
+// addChunk adds the row consumption of the chunk and returns the max row consumption of the batch over the sub-circuits.
+func (rc batchRowConsumption) addChunk(chunk *encoding.Chunk) (uint64, error) {
+	for _, block := range chunk.Blocks {
+		if block.RowConsumption == nil {
+			return 0, fmt.Errorf("block (%d, %v) has nil RowConsumption", block.Header.Number, block.Header.Hash().Hex())
+		}
+		for _, subCircuit := range *block.RowConsumption {
+			rc[subCircuit.Name] += subCircuit.RowNumber
+		}
+	}
+
+	var crcMax uint64
+	for _, rows := range rc {
+		crcMax = max(crcMax, rows)
+	}
+	return crcMax, nil
+}
+
+// calculateBatchCrcMax returns the max row consumption of the batch over the sub-circuits.
+func calculateBatchCrcMax(batch *encoding.Batch) (uint64, error) {
+	rc := batchRowConsumption{}
+	var crcMax uint64
+	for _, chunk := range batch.Chunks {
+		var err error
+		if crcMax, err = rc.addChunk(chunk); err != nil {
+			return 0, err
+		}
+	}
+	return crcMax, nil
+}
+
+// recordBatchRowConsumption exports the row consumption of the batch about to be persisted,
+// only tracked if the row consumption per batch is limited.
+func (p *BatchProposer) recordBatchRowConsumption(batch *encoding.Batch) {
+	if p.maxRowConsumptionPerBatch == 0 {
+		return
+	}
+	crcMax, err := calculateBatchCrcMax(batch)
+	if err != nil {
+		log.Warn("failed to calculate the row consumption of the batch", "index", batch.Index, "err", err)
+		return
+	}
+	p.batchRowConsumption.Set(float64(crcMax))
+}
@@ -0,0 +1,107 @@
+package watcher
+
+import (
+	"context"
+
+	"scroll-tech/common/types/encoding"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+// Names of the constraints sealing a batch, as reported by BatchProposer.SimulateProposeBatch.
+const (
+	batchConstraintMaxChunkNum            = "max_chunk_num_per_batch"
+	batchConstraintL1CommitCalldataSize   = "max_l1_commit_calldata_size_per_batch"
+	batchConstraintL1CommitGas            = "max_l1_commit_gas_per_batch"
+	batchConstraintBlobSize               = "max_blob_size"
+	batchConstraintRowConsumption         = "max_row_consumption_per_batch"
+	batchConstraintTimeout                = "batch_timeout_sec"
+	batchConstraintForcedInclusionTimeout = "forced_inclusion_timeout_sec"
+	batchConstraintForkBoundary           = "fork_boundary"
+	batchConstraintForced                 = "forced"
+)
+
+// BatchSimulationResult is the batch the BatchProposer would propose from the current unbatched chunks.
+type BatchSimulationResult struct {
+	CodecVersion encoding.CodecVersion `json:"codec_version"`
+	// Sealed is false if the chunks don't reach any constraint yet, i.e. no batch would be proposed now.
+	Sealed bool `json:"sealed"`
+	// BindingConstraint is the constraint sealing the batch, empty if not sealed.
+	BindingConstraint string `json:"binding_constraint,omitempty"`
+	// CheaperDAMode is the DA mode which would be the cheaper to commit the batch at the latest L1 fees,
+	// only set if the DA mode costs are compared. The batch is committed in the DA mode of its codec version.
+	CheaperDAMode string `json:"cheaper_da_mode,omitempty"`
+
+	Index            uint64   `json:"index"`
+	StartChunkIndex  uint64   `json:"start_chunk_index"`
+	EndChunkIndex    uint64   `json:"end_chunk_index"`
+	ChunkHashes      []string `json:"chunk_hashes"`
+	StartBlockNumber uint64   `json:"start_block_number"`
+	EndBlockNumber   uint64   `json:"end_block_number"`
+
+	L1CommitGas          uint64 `json:"l1_commit_gas"`
+	L1CommitCalldataSize uint64 `json:"l1_commit_calldata_size"`
+	L1CommitBlobSize     uint64 `json:"l1_commit_blob_size"`
+	// RowConsumption is the max row consumption over the sub-circuits, only set if the row consumption per batch is limited.
+	RowConsumption uint64 `json:"row_consumption,omitempty"`
+}
+
+// SimulateProposeBatch reports the batch that would be proposed from the current unbatched chunks without
+// persisting it. The simulation uses cfg if not nil, so that new limits can be previewed before enabling them,
+// and the proposer's configuration otherwise. It returns nil if there is no unbatched chunk.
+func (p *BatchProposer) SimulateProposeBatch(ctx context.Context, cfg *config.BatchProposerConfig) (*BatchSimulationResult, error) {
+	runningCfg := cfg == nil
+	if runningCfg {
+		cfg = p.cfg
+	}
+	// A separate proposer keeps the simulation from updating the dynamic max chunk number and the congestion state
+	// of the running one.
+	simulator := NewBatchProposerWithBackend(ctx, cfg, p.chainCfg, p.blockSource, p.batchStore, p.clock, nil)
+	// the running configuration is simulated from the max chunk number the running proposer reached.
+	if runningCfg && p.dynamicMaxChunks != nil && simulator.dynamicMaxChunks != nil {
+		p.mu.Lock()
+		simulator.dynamicMaxChunks.current = p.dynamicMaxChunks.current
+		p.mu.Unlock()
+	}
+
+	proposal, err := simulator.buildBatch(false)
+	if err != nil {
+		return nil, err
+	}
+	if proposal == nil {
+		return nil, nil
+	}
+	if proposal.constraint != "" {
+		if err := simulator.validateL1MessageContinuity(proposal); err != nil {
+			return nil, err
+		}
+		if err := simulator.compareDAModeCosts(proposal); err != nil {
+			return nil, err
+		}
+	}
+
+	numChunks := len(proposal.batch.Chunks)
+	result := &BatchSimulationResult{
+		CodecVersion:         proposal.codecVersion,
+		Sealed:               proposal.constraint != "",
+		BindingConstraint:    proposal.constraint,
+		CheaperDAMode:        proposal.cheaperDAMode,
+		Index:                proposal.batch.Index,
+		StartChunkIndex:      proposal.dbChunks[0].Index,
+		EndChunkIndex:        proposal.dbChunks[numChunks-1].Index,
+		StartBlockNumber:     proposal.dbChunks[0].StartBlockNumber,
+		EndBlockNumber:       proposal.dbChunks[numChunks-1].EndBlockNumber,
+		L1CommitGas:          proposal.metrics.L1CommitGas,
+		L1CommitCalldataSize: proposal.metrics.L1CommitCalldataSize,
+		L1CommitBlobSize:     proposal.metrics.L1CommitBlobSize,
+	}
+	for _, dbChunk := range proposal.dbChunks[:numChunks] {
+		result.ChunkHashes = append(result.ChunkHashes, dbChunk.Hash)
+	}
+	if simulator.maxRowConsumptionPerBatch > 0 {
+		if result.RowConsumption, err = calculateBatchCrcMax(proposal.batch); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,96 @@
+package watcher
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/common/types/encoding"
+
+	"scroll-tech/rollup/internal/utils"
+)
+
+// batchUtilization is the data availability efficiency of a proposed batch.
+type batchUtilization struct {
+	// blobBytes is the size of the blob carrying the batch.
+	blobBytes uint64
+	// blobCapacity is the capacity of the blob carrying the batch, i.e. maxBlobSize.
+	blobCapacity       uint64
+	numChunks          uint64
+	numL2Transactions  uint64
+	l1CommitGasPerL2Tx float64
+}
+
+// calculateBatchUtilization calculates the utilization of the batch.
+// The blob metrics are 0 for codecv0, which commits the calldata.
+func calculateBatchUtilization(batch *encoding.Batch, codecVersion encoding.CodecVersion, metrics *utils.BatchMetrics) *batchUtilization {
+	u := &batchUtilization{numChunks: uint64(len(batch.Chunks))}
+	for _, chunk := range batch.Chunks {
+		u.numL2Transactions += chunk.NumL2Transactions()
+	}
+	if u.numL2Transactions > 0 {
+		u.l1CommitGasPerL2Tx = float64(metrics.L1CommitGas) / float64(u.numL2Transactions)
+	}
+
+	if codecVersion == encoding.CodecV0 {
+		return u
+	}
+	u.blobBytes = metrics.L1CommitBlobSize
+	u.blobCapacity = maxBlobSize
+	return u
+}
+
+// batchUtilizationMetrics exports the utilization of the proposed batches. The totals give the blob utilization
+// over any time range, since the gauges only reflect the latest batch.
+type batchUtilizationMetrics struct {
+	blobBytesUsed          prometheus.Gauge
+	blobUtilizationRatio   prometheus.Gauge
+	l1CommitGasPerL2Tx     prometheus.Gauge
+	blobBytesUsedTotal     prometheus.Counter
+	blobCapacityBytesTotal prometheus.Counter
+	l2TransactionsTotal    prometheus.Counter
+}
+
+func newBatchUtilizationMetrics(reg prometheus.Registerer) *batchUtilizationMetrics {
+	return &batchUtilizationMetrics{
+		blobBytesUsed: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_propose_batch_blob_bytes_used",
+			Help: "The blob bytes used by the latest proposed batch",
+		}),
+		blobUtilizationRatio: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_propose_batch_blob_utilization_ratio",
+			Help: "The blob bytes used by the latest proposed batch over the capacity of its blobs",
+		}),
+		l1CommitGasPerL2Tx: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_propose_batch_l1_commit_gas_per_l2_tx",
+			Help: "The estimated l1 commit gas of the latest proposed batch per L2 transaction",
+		}),
+		blobBytesUsedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_propose_batch_blob_bytes_used_total",
+			Help: "Total blob bytes used by the proposed batches",
+		}),
+		blobCapacityBytesTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_propose_batch_blob_capacity_bytes_total",
+			Help: "Total capacity of the blobs carrying the proposed batches",
+		}),
+		l2TransactionsTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_propose_batch_l2_transactions_total",
+			Help: "Total number of L2 transactions in the proposed batches",
+		}),
+	}
+}
+
+// recordBatchUtilization exports the utilization of the batch about to be persisted.
+func (p *BatchProposer) recordBatchUtilization(batch *encoding.Batch, codecVersion encoding.CodecVersion, metrics *utils.BatchMetrics) {
+	u := calculateBatchUtilization(batch, codecVersion, metrics)
+	p.utilizationMetrics.l1CommitGasPerL2Tx.Set(u.l1CommitGasPerL2Tx)
+	p.utilizationMetrics.l2TransactionsTotal.Add(float64(u.numL2Transactions))
+	if u.blobCapacity > 0 {
+		p.utilizationMetrics.blobBytesUsed.Set(float64(u.blobBytes))
+		p.utilizationMetrics.blobUtilizationRatio.Set(float64(u.blobBytes) / float64(u.blobCapacity))
+		p.utilizationMetrics.blobBytesUsedTotal.Add(float64(u.blobBytes))
+		p.utilizationMetrics.blobCapacityBytesTotal.Add(float64(u.blobCapacity))
+	}
+	log.Debug("batch utilization", "index", batch.Index, "chunks", u.numChunks, "l2 txs", u.numL2Transactions,
+		"blob bytes", u.blobBytes, "blob capacity", u.blobCapacity, "l1 commit gas per l2 tx", u.l1CommitGasPerL2Tx)
+}
@@ -0,0 +1,34 @@
+package watcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/types/encoding"
+
+	"scroll-tech/rollup/internal/utils"
+)
+
+func TestCalculateBatchUtilization(t *testing.T) {
+	block := readBlockFromJSON(t, "../../../testdata/blockTrace_02.json")
+	batch := &encoding.Batch{Chunks: []*encoding.Chunk{{Blocks: []*encoding.Block{block}}, {Blocks: []*encoding.Block{block}}}}
+	numL2Transactions := 2 * block.NumL2Transactions()
+	assert.NotZero(t, numL2Transactions)
+
+	metrics, err := utils.CalculateBatchMetrics(batch, encoding.CodecV0)
+	assert.NoError(t, err)
+	u := calculateBatchUtilization(batch, encoding.CodecV0, metrics)
+	assert.Equal(t, uint64(2), u.numChunks)
+	assert.Equal(t, numL2Transactions, u.numL2Transactions)
+	assert.Equal(t, float64(metrics.L1CommitGas)/float64(numL2Transactions), u.l1CommitGasPerL2Tx)
+	// codecv0 commits the calldata.
+	assert.Zero(t, u.blobBytes)
+	assert.Zero(t, u.blobCapacity)
+
+	metrics, err = utils.CalculateBatchMetrics(batch, encoding.CodecV1)
+	assert.NoError(t, err)
+	u = calculateBatchUtilization(batch, encoding.CodecV1, metrics)
+	assert.Equal(t, metrics.L1CommitBlobSize, u.blobBytes)
+	assert.Equal(t, maxBlobSize, u.blobCapacity)
+}
@@ -0,0 +1,114 @@
+package watcher
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+// ChunkProfileState is the chunk proposer profile in use, along with the profiles it can be switched to.
+type ChunkProfileState struct {
+	Name      string                            `json:"name"`
+	Settings  config.ChunkProposerProfileConfig `json:"settings"`
+	Available []string                          `json:"available"`
+}
+
+// chunkProfiles returns the built-in profiles derived from the configured settings, overridden and
+// extended by the configured profiles.
+func chunkProfiles(cfg *config.ChunkProposerConfig) map[string]*config.ChunkProposerProfileConfig {
+	profiles := map[string]*config.ChunkProposerProfileConfig{
+		// seals smaller chunks sooner, and drains a backlog faster.
+		config.ChunkProfileLowLatency: {
+			ChunkTimeoutSec:          max(cfg.ChunkTimeoutSec/4, 1),
+			L1MessageChunkTimeoutSec: (cfg.L1MessageChunkTimeoutSec + 3) / 4,
+			MaxBlockNumPerChunk:      max(cfg.MaxBlockNumPerChunk/2, 1),
+			MaxChunksPerTick:         2 * max(cfg.MaxChunksPerTick, 1),
+		},
+		config.ChunkProfileBalanced: {
+			ChunkTimeoutSec:          cfg.ChunkTimeoutSec,
+			L1MessageChunkTimeoutSec: cfg.L1MessageChunkTimeoutSec,
+			MaxBlockNumPerChunk:      cfg.MaxBlockNumPerChunk,
+			MaxChunksPerTick:         cfg.MaxChunksPerTick,
+		},
+		// waits longer for chunks to fill up, so that the per-chunk L1 overhead is amortized over more blocks.
+		// Deposits keep the configured L1 message timeout.
+		config.ChunkProfileCostOptimized: {
+			ChunkTimeoutSec:          2 * cfg.ChunkTimeoutSec,
+			L1MessageChunkTimeoutSec: cfg.L1MessageChunkTimeoutSec,
+			MinBlockNumPerChunk:      cfg.MaxBlockNumPerChunk / 2,
+			MaxBlockNumPerChunk:      cfg.MaxBlockNumPerChunk,
+			MaxChunksPerTick:         cfg.MaxChunksPerTick,
+		},
+	}
+	for name, profileCfg := range cfg.Profiles {
+		profile := *profileCfg
+		if profile.MaxBlockNumPerChunk == 0 {
+			profile.MaxBlockNumPerChunk = cfg.MaxBlockNumPerChunk
+		}
+		profiles[name] = &profile
+	}
+	return profiles
+}
+
+// SetProfile switches the chunk proposer to the named profile. It takes effect from the next proposal attempt,
+// and lasts until the next switch or restart.
+func (p *ChunkProposer) SetProfile(name string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.applyProfile(name)
+}
+
+// Profile returns the chunk proposer profile in use.
+func (p *ChunkProposer) Profile() *ChunkProfileState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	state := &ChunkProfileState{
+		Name:     p.profile,
+		Settings: *p.profiles[p.profile],
+	}
+	for name := range p.profiles {
+		state.Available = append(state.Available, name)
+	}
+	sort.Strings(state.Available)
+	return state
+}
+
+// applyProfile must be called with p.mu held, or before the proposer is in use.
+func (p *ChunkProposer) applyProfile(name string) error {
+	profile, ok := p.profiles[name]
+	if !ok {
+		return fmt.Errorf("unknown chunk proposer profile: %v", name)
+	}
+
+	p.chunkTimeoutSec = profile.ChunkTimeoutSec
+	p.l1MessageChunkTimeoutSec = profile.L1MessageChunkTimeoutSec
+	p.minBlockNumPerChunk = profile.MinBlockNumPerChunk
+	p.maxChunkDelaySec = profile.MaxChunkDelaySec
+	if p.maxChunkDelaySec == 0 {
+		p.maxChunkDelaySec = 2 * profile.ChunkTimeoutSec
+	}
+	p.maxBlockNumPerChunk = profile.MaxBlockNumPerChunk
+	p.maxChunksPerTick = profile.MaxChunksPerTick
+
+	if p.profile != "" {
+		p.chunkProfile.With(prometheus.Labels{"profile": p.profile}).Set(0)
+	}
+	p.chunkProfile.With(prometheus.Labels{"profile": name}).Set(1)
+	p.profile = name
+
+	log.Info("applied chunk proposer profile",
+		"profile", name,
+		"chunkTimeoutSec", profile.ChunkTimeoutSec,
+		"l1MessageChunkTimeoutSec", profile.L1MessageChunkTimeoutSec,
+		"minBlockNumPerChunk", profile.MinBlockNumPerChunk,
+		"maxChunkDelaySec", p.maxChunkDelaySec,
+		"maxBlockNumPerChunk", profile.MaxBlockNumPerChunk,
+		"maxChunksPerTick", profile.MaxChunksPerTick)
+	return nil
+}
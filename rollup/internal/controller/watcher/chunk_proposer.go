@@ -3,10 +3,11 @@ package watcher
 import (
 	"context"
 	"fmt"
-	"time"
+	"sync"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
 	"github.com/scroll-tech/go-ethereum/log"
 	"github.com/scroll-tech/go-ethereum/params"
 	"gorm.io/gorm"
@@ -24,22 +25,42 @@ const maxBlobSize = uint64(131072)
 // ChunkProposer proposes chunks based on available unchunked blocks.
 type ChunkProposer struct {
 	ctx context.Context
-	db  *gorm.DB
 
-	chunkOrm   *orm.Chunk
-	l2BlockOrm *orm.L2Block
+	blockSource L2BlockSource
+	chunkStore  ChunkStore
+	clock       Clock
 
 	maxBlockNumPerChunk             uint64
 	maxTxNumPerChunk                uint64
 	maxL1CommitGasPerChunk          uint64
 	maxL1CommitCalldataSizePerChunk uint64
 	maxRowConsumptionPerChunk       uint64
+	maxUncompressedPayloadSize      uint64
 	chunkTimeoutSec                 uint64
+	l1MessageChunkTimeoutSec        uint64
+	forcedInclusionTimeoutSec       uint64
+	maxChunksPerTick                uint64
+	minBlockNumPerChunk             uint64
+	maxChunkDelaySec                uint64
 	gasCostIncreaseMultiplier       float64
 	forkHeights                     []uint64
+	// the max number of transactions per chunk of the configuration, capped by the on-chain limit.
+	configMaxTxNumPerChunk uint64
+
+	// the named bundles of the chunk timeouts and sizes, switchable at runtime.
+	profiles map[string]*config.ChunkProposerProfileConfig
+	profile  string
 
 	chainCfg *params.ChainConfig
 
+	// caches the per-block L1 commit estimations across proposal attempts.
+	estimationCache *utils.EstimationCache
+	// stateStore is nil if the chunk store doesn't persist proposer states.
+	stateStore StateStore
+
+	// serializes the proposal loop and the force seals requested by operators.
+	mu sync.Mutex
+
 	chunkProposerCircleTotal           prometheus.Counter
 	proposeChunkFailureTotal           prometheus.Counter
 	proposeChunkUpdateInfoTotal        prometheus.Counter
@@ -48,38 +69,69 @@ type ChunkProposer struct {
 	chunkEstimateL1CommitGas           prometheus.Gauge
 	totalL1CommitCalldataSize          prometheus.Gauge
 	totalL1CommitBlobSize              prometheus.Gauge
+	uncompressedPayloadSize            prometheus.Gauge
 	maxTxConsumption                   prometheus.Gauge
 	chunkBlocksNum                     prometheus.Gauge
 	chunkFirstBlockTimeoutReached      prometheus.Counter
+	chunkL1MessageTimeoutReached       prometheus.Counter
+	chunkForcedInclusionTimeoutReached prometheus.Counter
 	chunkBlocksProposeNotEnoughTotal   prometheus.Counter
+	chunkForceSealedTotal              prometheus.Counter
+	chunkProfile                       *prometheus.GaugeVec
+	staleChunkReleasedTotal            *prometheus.CounterVec
+}
+
+// ForceSealResult is the outcome of a force seal of the pending blocks.
+type ForceSealResult struct {
+	NumChunks        uint64 `json:"num_chunks"`
+	StartBlockNumber uint64 `json:"start_block_number,omitempty"`
+	EndBlockNumber   uint64 `json:"end_block_number,omitempty"`
 }
 
-// NewChunkProposer creates a new ChunkProposer instance.
+// NewChunkProposer creates a new ChunkProposer instance backed by the rollup database.
 func NewChunkProposer(ctx context.Context, cfg *config.ChunkProposerConfig, chainCfg *params.ChainConfig, db *gorm.DB, reg prometheus.Registerer) *ChunkProposer {
+	return NewChunkProposerWithBackend(ctx, cfg, chainCfg, orm.NewL2Block(db), newDBChunkStore(db), systemClock{}, reg)
+}
+
+// NewChunkProposerWithBackend creates a new ChunkProposer instance reading blocks from blockSource,
+// persisting chunks to chunkStore and using clock as the time source.
+func NewChunkProposerWithBackend(ctx context.Context, cfg *config.ChunkProposerConfig, chainCfg *params.ChainConfig, blockSource L2BlockSource, chunkStore ChunkStore, clock Clock, reg prometheus.Registerer) *ChunkProposer {
 	forkHeights, _, _ := forks.CollectSortedForkHeights(chainCfg)
 	log.Debug("new chunk proposer",
 		"maxTxNumPerChunk", cfg.MaxTxNumPerChunk,
 		"maxL1CommitGasPerChunk", cfg.MaxL1CommitGasPerChunk,
 		"maxL1CommitCalldataSizePerChunk", cfg.MaxL1CommitCalldataSizePerChunk,
 		"maxRowConsumptionPerChunk", cfg.MaxRowConsumptionPerChunk,
+		"maxUncompressedPayloadSizePerChunk", cfg.MaxUncompressedPayloadSizePerChunk,
 		"chunkTimeoutSec", cfg.ChunkTimeoutSec,
+		"l1MessageChunkTimeoutSec", cfg.L1MessageChunkTimeoutSec,
+		"forcedInclusionTimeoutSec", cfg.ForcedInclusionTimeoutSec,
+		"maxChunksPerTick", cfg.MaxChunksPerTick,
 		"gasCostIncreaseMultiplier", cfg.GasCostIncreaseMultiplier,
+		"profile", cfg.Profile,
 		"forkHeights", forkHeights)
 
 	p := &ChunkProposer{
 		ctx:                             ctx,
-		db:                              db,
-		chunkOrm:                        orm.NewChunk(db),
-		l2BlockOrm:                      orm.NewL2Block(db),
+		blockSource:                     blockSource,
+		chunkStore:                      chunkStore,
+		clock:                           clock,
 		maxBlockNumPerChunk:             cfg.MaxBlockNumPerChunk,
 		maxTxNumPerChunk:                cfg.MaxTxNumPerChunk,
+		configMaxTxNumPerChunk:          cfg.MaxTxNumPerChunk,
 		maxL1CommitGasPerChunk:          cfg.MaxL1CommitGasPerChunk,
 		maxL1CommitCalldataSizePerChunk: cfg.MaxL1CommitCalldataSizePerChunk,
 		maxRowConsumptionPerChunk:       cfg.MaxRowConsumptionPerChunk,
+		maxUncompressedPayloadSize:      cfg.MaxUncompressedPayloadSizePerChunk,
 		chunkTimeoutSec:                 cfg.ChunkTimeoutSec,
+		l1MessageChunkTimeoutSec:        cfg.L1MessageChunkTimeoutSec,
+		forcedInclusionTimeoutSec:       cfg.ForcedInclusionTimeoutSec,
+		maxChunksPerTick:                cfg.MaxChunksPerTick,
 		gasCostIncreaseMultiplier:       cfg.GasCostIncreaseMultiplier,
 		forkHeights:                     forkHeights,
 		chainCfg:                        chainCfg,
+		estimationCache:                 utils.NewEstimationCache(utils.DefaultEstimationCacheSize),
+		profiles:                        chunkProfiles(cfg),
 
 		chunkProposerCircleTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
 			Name: "rollup_propose_chunk_circle_total",
@@ -113,6 +165,10 @@ func NewChunkProposer(ctx context.Context, cfg *config.ChunkProposerConfig, chai
 			Name: "rollup_propose_chunk_total_l1_commit_blob_size",
 			Help: "The total l1 commit blob size",
 		}),
+		uncompressedPayloadSize: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_propose_chunk_uncompressed_payload_size",
+			Help: "The total uncompressed L2 transaction payload size of the chunk",
+		}),
 		maxTxConsumption: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
 			Name: "rollup_propose_chunk_max_tx_consumption",
 			Help: "The max tx consumption",
@@ -125,23 +181,114 @@ func NewChunkProposer(ctx context.Context, cfg *config.ChunkProposerConfig, chai
 			Name: "rollup_propose_chunk_first_block_timeout_reached_total",
 			Help: "Total times of chunk's first block timeout reached",
 		}),
+		chunkL1MessageTimeoutReached: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_propose_chunk_l1_message_timeout_reached_total",
+			Help: "Total times of chunk sealed because a block containing L1 messages reached the L1 message chunk timeout",
+		}),
+		chunkForcedInclusionTimeoutReached: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_propose_chunk_forced_inclusion_timeout_reached_total",
+			Help: "Total times of chunk sealed because an enforced transaction reached its inclusion deadline",
+		}),
 		chunkBlocksProposeNotEnoughTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
 			Name: "rollup_propose_chunk_blocks_propose_not_enough_total",
 			Help: "Total number of chunk block propose not enough",
 		}),
+		chunkForceSealedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_propose_chunk_force_sealed_total",
+			Help: "Total number of chunks sealed by force seal requests",
+		}),
+		chunkProfile: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Name: "rollup_propose_chunk_profile",
+			Help: "The chunk proposer profile in use, 1 for the active profile",
+		}, []string{"profile"}),
+		staleChunkReleasedTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "rollup_propose_chunk_stale_released_total",
+			Help: "Total number of unbatched chunks released by the stale chunk garbage collection",
+		}, []string{"reason"}),
 	}
 
+	profile := cfg.Profile
+	if profile == "" {
+		profile = config.ChunkProfileBalanced
+	}
+	if err := p.applyProfile(profile); err != nil {
+		log.Crit("failed to apply chunk proposer profile", "err", err)
+	}
+	p.stateStore, _ = chunkStore.(StateStore)
 	return p
 }
 
-// TryProposeChunk tries to propose a new chunk.
-func (p *ChunkProposer) TryProposeChunk() {
-	p.chunkProposerCircleTotal.Inc()
-	if err := p.proposeChunk(); err != nil {
-		p.proposeChunkFailureTotal.Inc()
-		log.Error("propose new chunk failed", "err", err)
-		return
+// TryProposeChunk tries to propose new chunks, sealing at most maxChunksPerTick chunks in one invocation.
+// It returns true if any chunk is proposed, which indicates that more pending blocks may be available.
+func (p *ChunkProposer) TryProposeChunk() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	maxChunks := p.maxChunksPerTick
+	if maxChunks == 0 {
+		maxChunks = 1
+	}
+
+	var numProposed uint64
+	for numProposed < maxChunks && p.ctx.Err() == nil {
+		p.chunkProposerCircleTotal.Inc()
+		proposed, err := p.proposeChunk(false)
+		if err != nil {
+			p.proposeChunkFailureTotal.Inc()
+			log.Error("propose new chunk failed", "err", err)
+			break
+		}
+		if !proposed {
+			break
+		}
+		numProposed++
+	}
+
+	if numProposed > 1 {
+		log.Info("proposed multiple chunks in one tick", "count", numProposed)
+	}
+	return numProposed > 0
+}
+
+// ForceSealChunks seals all the pending blocks into chunks right now, without waiting for the chunk timeout
+// or for a chunk to be full, e.g. during fork cutovers and incident response.
+// The capacity limits of a chunk still apply, so the pending blocks may be split into several chunks.
+func (p *ChunkProposer) ForceSealChunks(ctx context.Context) (*ForceSealResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	result := &ForceSealResult{}
+	for ctx.Err() == nil {
+		chunk, err := p.proposeChunkWithForce(true)
+		if err != nil {
+			return result, fmt.Errorf("failed to force seal chunk: %w", err)
+		}
+		if chunk == nil {
+			break
+		}
+		if result.NumChunks == 0 {
+			result.StartBlockNumber = chunk.Blocks[0].Header.Number.Uint64()
+		}
+		result.EndBlockNumber = chunk.Blocks[len(chunk.Blocks)-1].Header.Number.Uint64()
+		result.NumChunks++
+		p.chunkForceSealedTotal.Inc()
 	}
+
+	log.Info("force sealed pending blocks", "chunks", result.NumChunks, "start block number", result.StartBlockNumber, "end block number", result.EndBlockNumber)
+	return result, ctx.Err()
+}
+
+// SetOnChainMaxTxNumPerChunk caps the max number of transactions per chunk by the limit of the ScrollChain contract,
+// so that no chunk is rejected on commit after the limit is lowered on L1. It takes effect from the next proposal attempt.
+func (p *ChunkProposer) SetOnChainMaxTxNumPerChunk(maxTxNum uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.maxTxNumPerChunk = p.configMaxTxNumPerChunk
+	if maxTxNum != 0 && maxTxNum < p.maxTxNumPerChunk {
+		p.maxTxNumPerChunk = maxTxNum
+	}
+	log.Info("applied on-chain max number of transactions per chunk", "onChain", maxTxNum, "maxTxNumPerChunk", p.maxTxNumPerChunk)
 }
 
 func (p *ChunkProposer) updateDBChunkInfo(chunk *encoding.Chunk, codecVersion encoding.CodecVersion) error {
@@ -150,19 +297,7 @@ func (p *ChunkProposer) updateDBChunkInfo(chunk *encoding.Chunk, codecVersion en
 	}
 
 	p.proposeChunkUpdateInfoTotal.Inc()
-	err := p.db.Transaction(func(dbTX *gorm.DB) error {
-		dbChunk, err := p.chunkOrm.InsertChunk(p.ctx, chunk, codecVersion, dbTX)
-		if err != nil {
-			log.Warn("ChunkProposer.InsertChunk failed", "err", err)
-			return err
-		}
-		if err := p.l2BlockOrm.UpdateChunkHashInRange(p.ctx, dbChunk.StartBlockNumber, dbChunk.EndBlockNumber, dbChunk.Hash, dbTX); err != nil {
-			log.Error("failed to update chunk_hash for l2_blocks", "chunk hash", dbChunk.Hash, "start block", dbChunk.StartBlockNumber, "end block", dbChunk.EndBlockNumber, "err", err)
-			return err
-		}
-		return nil
-	})
-	if err != nil {
+	if err := p.chunkStore.InsertChunk(p.ctx, chunk, codecVersion); err != nil {
 		p.proposeChunkUpdateInfoFailureTotal.Inc()
 		log.Error("update chunk info in orm failed", "err", err)
 		return err
@@ -170,10 +305,17 @@ func (p *ChunkProposer) updateDBChunkInfo(chunk *encoding.Chunk, codecVersion en
 	return nil
 }
 
-func (p *ChunkProposer) proposeChunk() error {
-	unchunkedBlockHeight, err := p.chunkOrm.GetUnchunkedBlockHeight(p.ctx)
+func (p *ChunkProposer) proposeChunk(force bool) (bool, error) {
+	chunk, err := p.proposeChunkWithForce(force)
+	return chunk != nil, err
+}
+
+// proposeChunkWithForce proposes a chunk and returns it, or nil if no chunk is proposed.
+// If force is true, the pending blocks are sealed even if neither the chunk is full nor a timeout is reached.
+func (p *ChunkProposer) proposeChunkWithForce(force bool) (*encoding.Chunk, error) {
+	unchunkedBlockHeight, err := p.chunkStore.GetUnchunkedBlockHeight(p.ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	maxBlocksThisChunk := p.maxBlockNumPerChunk
@@ -183,13 +325,13 @@ func (p *ChunkProposer) proposeChunk() error {
 	}
 
 	// select at most maxBlocksThisChunk blocks
-	blocks, err := p.l2BlockOrm.GetL2BlocksGEHeight(p.ctx, unchunkedBlockHeight, int(maxBlocksThisChunk))
+	blocks, err := p.blockSource.GetL2BlocksGEHeight(p.ctx, unchunkedBlockHeight, int(maxBlocksThisChunk))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if len(blocks) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	codecVersion := encoding.CodecV0
@@ -201,21 +343,17 @@ func (p *ChunkProposer) proposeChunk() error {
 	for i, block := range blocks {
 		chunk.Blocks = append(chunk.Blocks, block)
 
-		metrics, calcErr := utils.CalculateChunkMetrics(&chunk, codecVersion)
+		metrics, calcErr := p.estimationCache.CalculateChunkMetrics(&chunk, codecVersion)
 		if calcErr != nil {
-			return fmt.Errorf("failed to calculate chunk metrics: %w", calcErr)
+			return nil, fmt.Errorf("failed to calculate chunk metrics: %w", calcErr)
 		}
 
 		overEstimatedL1CommitGas := uint64(p.gasCostIncreaseMultiplier * float64(metrics.L1CommitGas))
-		if metrics.TxNum > p.maxTxNumPerChunk ||
-			metrics.L1CommitCalldataSize > p.maxL1CommitCalldataSizePerChunk ||
-			overEstimatedL1CommitGas > p.maxL1CommitGasPerChunk ||
-			metrics.CrcMax > p.maxRowConsumptionPerChunk ||
-			metrics.L1CommitBlobSize > maxBlobSize {
+		if len(p.exceededLimits(metrics)) > 0 {
 			if i == 0 {
 				// The first block exceeds hard limits, which indicates a bug in the sequencer, manual fix is needed.
-				return fmt.Errorf("the first block exceeds limits; block number: %v, limits: %+v, maxTxNum: %v, maxL1CommitCalldataSize: %v, maxL1CommitGas: %v, maxRowConsumption: %v, maxBlobSize: %v",
-					block.Header.Number, metrics, p.maxTxNumPerChunk, p.maxL1CommitCalldataSizePerChunk, p.maxL1CommitGasPerChunk, p.maxRowConsumptionPerChunk, maxBlobSize)
+				return nil, fmt.Errorf("the first block exceeds limits; block number: %v, limits: %+v, maxTxNum: %v, maxL1CommitCalldataSize: %v, maxL1CommitGas: %v, maxRowConsumption: %v, maxBlobSize: %v, maxUncompressedPayloadSize: %v",
+					block.Header.Number, metrics, p.maxTxNumPerChunk, p.maxL1CommitCalldataSizePerChunk, p.maxL1CommitGasPerChunk, p.maxRowConsumptionPerChunk, maxBlobSize, p.maxUncompressedPayloadSize)
 			}
 
 			log.Debug("breaking limit condition in chunking",
@@ -227,42 +365,97 @@ func (p *ChunkProposer) proposeChunk() error {
 				"maxL1CommitGas", p.maxL1CommitGasPerChunk,
 				"rowConsumption", metrics.CrcMax,
 				"maxRowConsumption", p.maxRowConsumptionPerChunk,
-				"maxBlobSize", maxBlobSize)
+				"maxBlobSize", maxBlobSize,
+				"uncompressedPayloadSize", metrics.UncompressedPayloadSize,
+				"maxUncompressedPayloadSize", p.maxUncompressedPayloadSize)
 
 			chunk.Blocks = chunk.Blocks[:len(chunk.Blocks)-1]
 
-			metrics, calcErr := utils.CalculateChunkMetrics(&chunk, codecVersion)
+			metrics, calcErr := p.estimationCache.CalculateChunkMetrics(&chunk, codecVersion)
 			if calcErr != nil {
-				return fmt.Errorf("failed to calculate chunk metrics: %w", calcErr)
+				return nil, fmt.Errorf("failed to calculate chunk metrics: %w", calcErr)
 			}
 
 			p.recordChunkMetrics(metrics)
-			return p.updateDBChunkInfo(&chunk, codecVersion)
+			if err := p.updateDBChunkInfo(&chunk, codecVersion); err != nil {
+				return nil, err
+			}
+			return &chunk, nil
 		}
 	}
 
-	metrics, calcErr := utils.CalculateChunkMetrics(&chunk, codecVersion)
+	metrics, calcErr := p.estimationCache.CalculateChunkMetrics(&chunk, codecVersion)
 	if calcErr != nil {
-		return fmt.Errorf("failed to calculate chunk metrics: %w", calcErr)
+		return nil, fmt.Errorf("failed to calculate chunk metrics: %w", calcErr)
+	}
+
+	currentTimeSec := uint64(p.clock.Now().Unix())
+	forcedInclusionTimeoutReached, err := p.forcedInclusionTimeoutReached(&chunk, currentTimeSec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check forced inclusion timeout: %w", err)
 	}
 
-	currentTimeSec := uint64(time.Now().Unix())
-	if metrics.FirstBlockTimestamp+p.chunkTimeoutSec < currentTimeSec || metrics.NumBlocks == maxBlocksThisChunk {
-		log.Info("reached maximum number of blocks in chunk or first block timeout",
+	l1MessageTimeoutReached := p.l1MessageTimeoutReached(&chunk, currentTimeSec)
+
+	// the min block number only holds the chunk until its first block waited for the max chunk delay.
+	chunkTimeoutReached := metrics.FirstBlockTimestamp+p.chunkTimeoutSec < currentTimeSec &&
+		(metrics.NumBlocks >= p.minBlockNumPerChunk || metrics.FirstBlockTimestamp+p.maxChunkDelaySec < currentTimeSec)
+	if force || chunkTimeoutReached || metrics.NumBlocks == maxBlocksThisChunk || l1MessageTimeoutReached || forcedInclusionTimeoutReached {
+		log.Info("reached maximum number of blocks in chunk or first block timeout or l1 message timeout or forced inclusion timeout or force sealed",
 			"start block number", chunk.Blocks[0].Header.Number,
 			"block count", len(chunk.Blocks),
 			"block number", chunk.Blocks[0].Header.Number,
 			"block timestamp", metrics.FirstBlockTimestamp,
+			"l1 message timeout reached", l1MessageTimeoutReached,
+			"forced inclusion timeout reached", forcedInclusionTimeoutReached,
+			"force", force,
 			"current time", currentTimeSec)
 
+		if l1MessageTimeoutReached {
+			p.chunkL1MessageTimeoutReached.Inc()
+		}
+		if forcedInclusionTimeoutReached {
+			p.chunkForcedInclusionTimeoutReached.Inc()
+		}
 		p.chunkFirstBlockTimeoutReached.Inc()
 		p.recordChunkMetrics(metrics)
-		return p.updateDBChunkInfo(&chunk, codecVersion)
+		if err := p.updateDBChunkInfo(&chunk, codecVersion); err != nil {
+			return nil, err
+		}
+		return &chunk, nil
 	}
 
 	log.Debug("pending blocks do not reach one of the constraints or contain a timeout block")
 	p.chunkBlocksProposeNotEnoughTotal.Inc()
-	return nil
+	return nil, nil
+}
+
+// l1MessageTimeoutReached checks whether the first block containing L1 messages in the chunk has been
+// waiting for longer than l1MessageChunkTimeoutSec.
+func (p *ChunkProposer) l1MessageTimeoutReached(chunk *encoding.Chunk, currentTimeSec uint64) bool {
+	if p.l1MessageChunkTimeoutSec == 0 {
+		return false
+	}
+
+	for _, block := range chunk.Blocks {
+		for _, tx := range block.Transactions {
+			if tx.Type != gethTypes.L1MessageTxType {
+				continue
+			}
+			// blocks are in ascending order, so the first block containing L1 messages waits the longest.
+			return block.Header.Time+p.l1MessageChunkTimeoutSec < currentTimeSec
+		}
+	}
+	return false
+}
+
+// forcedInclusionTimeoutReached checks whether an enforced transaction in the chunk has been waiting
+// for longer than forcedInclusionTimeoutSec since it was queued on L1.
+func (p *ChunkProposer) forcedInclusionTimeoutReached(chunk *encoding.Chunk, currentTimeSec uint64) (bool, error) {
+	if p.forcedInclusionTimeoutSec == 0 {
+		return false, nil
+	}
+	return forcedInclusionTimeoutReached(p.ctx, p.chunkStore, chunk.Blocks, p.forcedInclusionTimeoutSec, currentTimeSec)
 }
 
 func (p *ChunkProposer) recordChunkMetrics(metrics *utils.ChunkMetrics) {
@@ -272,4 +465,5 @@ func (p *ChunkProposer) recordChunkMetrics(metrics *utils.ChunkMetrics) {
 	p.totalL1CommitCalldataSize.Set(float64(metrics.L1CommitCalldataSize))
 	p.chunkEstimateL1CommitGas.Set(float64(metrics.L1CommitGas))
 	p.totalL1CommitBlobSize.Set(float64(metrics.L1CommitBlobSize))
+	p.uncompressedPayloadSize.Set(float64(metrics.UncompressedPayloadSize))
 }
@@ -4,8 +4,10 @@ import (
 	"context"
 	"math/big"
 	"testing"
+	"time"
 
 	"github.com/scroll-tech/go-ethereum/common/math"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
 	"github.com/scroll-tech/go-ethereum/params"
 	"github.com/stretchr/testify/assert"
 
@@ -422,3 +424,215 @@ func testChunkProposerCodecv1BlobSizeLimit(t *testing.T) {
 		assert.Equal(t, expected, chunk.EndBlockNumber)
 	}
 }
+
+func TestChunkProposerMultipleChunksPerTick(t *testing.T) {
+	store := newMemoryStore(t, 5)
+	cp := newTestChunkProposer(store, newMemoryClock(store), nil, func(cfg *config.ChunkProposerConfig) {
+		cfg.MaxBlockNumPerChunk = 1
+		cfg.ChunkTimeoutSec = math.MaxUint32
+		cfg.MaxChunksPerTick = 3
+	})
+
+	assert.True(t, cp.TryProposeChunk())
+	assert.Len(t, store.chunks, 3)
+
+	assert.True(t, cp.TryProposeChunk())
+	assert.Len(t, store.chunks, 5)
+
+	assert.False(t, cp.TryProposeChunk())
+	assert.Len(t, store.chunks, 5)
+}
+
+func TestChunkProposerForceSealChunks(t *testing.T) {
+	store := newMemoryStore(t, 5)
+	cp := newTestChunkProposer(store, newMemoryClock(store), nil, func(cfg *config.ChunkProposerConfig) {
+		cfg.MaxBlockNumPerChunk = 2
+		cfg.ChunkTimeoutSec = math.MaxUint32
+	})
+
+	// the pending blocks are sealed without waiting for the chunk timeout, respecting the max number of blocks.
+	result, err := cp.ForceSealChunks(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, &ForceSealResult{NumChunks: 3, StartBlockNumber: 1, EndBlockNumber: 5}, result)
+	assert.Len(t, store.chunks, 3)
+	assert.Len(t, store.chunks[2].Blocks, 1)
+
+	// nothing is left to seal.
+	result, err = cp.ForceSealChunks(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), result.NumChunks)
+}
+
+func TestChunkProposerChunkUtilizationReport(t *testing.T) {
+	store := newMemoryStore(t, 3)
+	clock := newMemoryClock(store)
+
+	report, err := newTestChunkProposer(store, clock, nil, nil).GetChunkUtilizationReport(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, report.Blocks, 3)
+	for i, block := range report.Blocks {
+		assert.Equal(t, uint64(i+1), block.Number)
+		assert.Equal(t, uint64(i+1)*block.TxNum, block.CumulativeTxNum)
+		assert.Empty(t, block.ExceededLimits)
+	}
+	assert.Greater(t, report.Blocks[2].CumulativeL1CommitGas, report.Blocks[1].CumulativeL1CommitGas)
+
+	// the report only estimates the pending blocks, nothing is sealed.
+	assert.Empty(t, store.chunks)
+
+	// the third block would exceed the tx num limit.
+	maxTxNum := 2 * report.Blocks[0].TxNum
+	report, err = newTestChunkProposer(store, clock, nil, func(cfg *config.ChunkProposerConfig) {
+		cfg.MaxTxNumPerChunk = maxTxNum
+	}).GetChunkUtilizationReport(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, report.Blocks[1].ExceededLimits)
+	assert.Equal(t, []string{"max_tx_num_per_chunk"}, report.Blocks[2].ExceededLimits)
+}
+
+func TestChunkProposerMaxUncompressedPayloadSize(t *testing.T) {
+	store := newMemoryStore(t, 3)
+	clock := newMemoryClock(store)
+
+	report, err := newTestChunkProposer(store, clock, nil, nil).GetChunkUtilizationReport(context.Background())
+	assert.NoError(t, err)
+	blockPayloadSize := report.Blocks[0].UncompressedPayloadSize
+	assert.NotZero(t, blockPayloadSize)
+	assert.Equal(t, 3*blockPayloadSize, report.Blocks[2].CumulativeUncompressedPayload)
+
+	// the chunk is sealed before its payload exceeds two blocks.
+	cp := newTestChunkProposer(store, clock, nil, func(cfg *config.ChunkProposerConfig) {
+		cfg.MaxUncompressedPayloadSizePerChunk = 2 * blockPayloadSize
+	})
+	assert.True(t, cp.TryProposeChunk())
+	assert.Len(t, store.chunks, 1)
+	assert.Len(t, store.chunks[0].Blocks, 2)
+}
+
+func TestChunkProposerL1MessageChunkTimeout(t *testing.T) {
+	store := newMemoryStore(t, 3)
+	// the second block contains an L1 message.
+	store.blocks[1].Transactions[0].Type = gethTypes.L1MessageTxType
+	store.blocks[1].Transactions[0].Nonce = 0
+
+	clock := newMemoryClock(store)
+	cp := newTestChunkProposer(store, clock, nil, func(cfg *config.ChunkProposerConfig) {
+		cfg.L1MessageChunkTimeoutSec = 30
+	})
+
+	// neither timeout is reached yet.
+	clock.now = clock.now.Add(30 * time.Second)
+	assert.False(t, cp.TryProposeChunk())
+	assert.Empty(t, store.chunks)
+
+	// the L1 message timeout is reached long before the chunk timeout.
+	clock.now = clock.now.Add(time.Second)
+	assert.True(t, cp.TryProposeChunk())
+	assert.Len(t, store.chunks, 1)
+	assert.Len(t, store.chunks[0].Blocks, 3)
+}
+
+func TestChunkProposerProfiles(t *testing.T) {
+	store := newMemoryStore(t, 3)
+	clock := newMemoryClock(store)
+	cp := newTestChunkProposer(store, clock, nil, func(cfg *config.ChunkProposerConfig) {
+		cfg.MaxBlockNumPerChunk = 8
+		cfg.Profile = "cost-optimized"
+	})
+
+	state := cp.Profile()
+	assert.Equal(t, "cost-optimized", state.Name)
+	assert.Equal(t, uint64(600), state.Settings.ChunkTimeoutSec)
+	assert.Equal(t, uint64(4), state.Settings.MinBlockNumPerChunk)
+	assert.Equal(t, []string{"balanced", "cost-optimized", "low-latency"}, state.Available)
+
+	// the chunk timeout doesn't seal fewer blocks than the min block number.
+	clock.now = clock.now.Add(601 * time.Second)
+	assert.False(t, cp.TryProposeChunk())
+	assert.Empty(t, store.chunks)
+
+	// low-latency seals the pending blocks on the shorter chunk timeout.
+	assert.NoError(t, cp.SetProfile("low-latency"))
+	state = cp.Profile()
+	assert.Equal(t, uint64(75), state.Settings.ChunkTimeoutSec)
+	assert.Equal(t, uint64(4), state.Settings.MaxBlockNumPerChunk)
+	assert.Zero(t, state.Settings.MinBlockNumPerChunk)
+	assert.True(t, cp.TryProposeChunk())
+	assert.Len(t, store.chunks, 1)
+	assert.Len(t, store.chunks[0].Blocks, 3)
+
+	assert.Error(t, cp.SetProfile("unknown"))
+	assert.Equal(t, "low-latency", cp.Profile().Name)
+}
+
+func TestChunkProposerMaxChunkDelay(t *testing.T) {
+	store := newMemoryStore(t, 3)
+	clock := newMemoryClock(store)
+	cp := newTestChunkProposer(store, clock, nil, func(cfg *config.ChunkProposerConfig) {
+		cfg.MaxBlockNumPerChunk = 8
+		cfg.Profile = "cost-optimized"
+	})
+
+	// the chunk below the min block number waits past the chunk timeout.
+	clock.now = clock.now.Add(1200 * time.Second)
+	assert.False(t, cp.TryProposeChunk())
+	assert.Empty(t, store.chunks)
+
+	// and is sealed once its first block waited for twice the chunk timeout.
+	clock.now = clock.now.Add(2 * time.Second)
+	assert.True(t, cp.TryProposeChunk())
+	assert.Len(t, store.chunks, 1)
+	assert.Len(t, store.chunks[0].Blocks, 3)
+}
+
+func TestChunkProposerReleaseStaleChunks(t *testing.T) {
+	store := newMemoryStore(t, 4)
+	clock := newMemoryClock(store)
+	newChunkProposer := func(chainCfg *params.ChainConfig) *ChunkProposer {
+		return newTestChunkProposer(store, clock, chainCfg, func(cfg *config.ChunkProposerConfig) {
+			cfg.MaxBlockNumPerChunk = 2
+		})
+	}
+	proposeAll := func(cp *ChunkProposer) {
+		_, err := cp.ForceSealChunks(context.Background())
+		assert.NoError(t, err)
+	}
+
+	cp := newChunkProposer(&params.ChainConfig{})
+	proposeAll(cp)
+	assert.Len(t, store.chunks, 2)
+
+	// nothing is stale under the fork heights the chunks are proposed with.
+	result, err := cp.ReleaseStaleChunks(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), result.NumChunks)
+
+	// the Bernoulli fork is scheduled at a height inside the first chunk.
+	cp = newChunkProposer(&params.ChainConfig{BernoulliBlock: big.NewInt(2)})
+	result, err = cp.ReleaseStaleChunks(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, &StaleChunkGCResult{NumChunks: 2, StartChunkIndex: 1, EndChunkIndex: 2, StartBlockNumber: 1, Reason: "fork_boundary"}, result)
+	assert.Empty(t, store.chunks)
+	assert.Equal(t, []string{"fork_boundary", "fork_boundary"}, store.staleReasons)
+
+	// the released blocks are chunked again at the fork height.
+	proposeAll(cp)
+	assert.Len(t, store.chunks, 3)
+	assert.Len(t, store.chunks[0].Blocks, 1)
+
+	// the Bernoulli fork is postponed, the chunks proposed with codecv1 before it are stale.
+	cp = newChunkProposer(&params.ChainConfig{BernoulliBlock: big.NewInt(5)})
+	result, err = cp.ReleaseStaleChunks(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "codec_mismatch", result.Reason)
+	assert.Equal(t, uint64(2), result.StartChunkIndex)
+	assert.Len(t, store.chunks, 1)
+
+	// a batched chunk is not released.
+	store.batches = []*encoding.Batch{{Chunks: store.chunks}}
+	cp = newChunkProposer(&params.ChainConfig{BernoulliBlock: big.NewInt(1)})
+	result, err = cp.ReleaseStaleChunks(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), result.NumChunks)
+	assert.Len(t, store.chunks, 1)
+}
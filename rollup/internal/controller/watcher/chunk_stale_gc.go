@@ -0,0 +1,100 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/common/forks"
+	"scroll-tech/common/types/encoding"
+
+	"scroll-tech/rollup/internal/orm"
+)
+
+// Reasons of the release of a stale chunk.
+const (
+	staleChunkReasonForkBoundary  = "fork_boundary"
+	staleChunkReasonCodecMismatch = "codec_mismatch"
+)
+
+// StaleChunkGCResult is the outcome of a stale chunk garbage collection.
+type StaleChunkGCResult struct {
+	NumChunks       uint64 `json:"num_chunks"`
+	StartChunkIndex uint64 `json:"start_chunk_index,omitempty"`
+	EndChunkIndex   uint64 `json:"end_chunk_index,omitempty"`
+	// StartBlockNumber is the first block chunked again by the chunk proposer.
+	StartBlockNumber uint64 `json:"start_block_number,omitempty"`
+	Reason           string `json:"reason,omitempty"`
+}
+
+// CollectStaleChunks releases the stale unbatched chunks, logging the failures.
+func (p *ChunkProposer) CollectStaleChunks() {
+	if _, err := p.ReleaseStaleChunks(p.ctx); err != nil {
+		log.Error("failed to collect stale chunks", "err", err)
+	}
+}
+
+// ReleaseStaleChunks releases the unbatched chunks invalidated by the current fork heights, i.e. crossing a fork
+// height or proposed with another codec than the one of their fork, e.g. after a fork height is moved.
+// The chunks after the first stale chunk are released too, since each chunk builds on its parent, and their
+// blocks are chunked again by the next proposals. The released chunks are recorded as stale.
+func (p *ChunkProposer) ReleaseStaleChunks(ctx context.Context) (*StaleChunkGCResult, error) {
+	staleStore, ok := p.chunkStore.(StaleChunkStore)
+	if !ok {
+		return nil, errors.New("the chunk store doesn't support releasing chunks")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	chunks, err := staleStore.GetUnbatchedChunks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unbatched chunks: %w", err)
+	}
+
+	result := &StaleChunkGCResult{}
+	for i, chunk := range chunks {
+		reason := p.staleChunkReason(chunk)
+		if reason == "" {
+			continue
+		}
+
+		released := chunks[i:]
+		if err := staleStore.ReleaseChunks(ctx, released, reason); err != nil {
+			return nil, fmt.Errorf("failed to release stale chunks, start index: %v, reason: %v: %w", chunk.Index, reason, err)
+		}
+		p.staleChunkReleasedTotal.WithLabelValues(reason).Add(float64(len(released)))
+
+		result.NumChunks = uint64(len(released))
+		result.StartChunkIndex = chunk.Index
+		result.EndChunkIndex = released[len(released)-1].Index
+		result.StartBlockNumber = chunk.StartBlockNumber
+		result.Reason = reason
+		log.Warn("released stale chunks", "chunks", result.NumChunks, "start index", result.StartChunkIndex, "end index", result.EndChunkIndex, "start block number", result.StartBlockNumber, "reason", reason)
+		break
+	}
+	return result, nil
+}
+
+// staleChunkReason returns why the chunk is invalidated by the current fork heights, empty if it's not.
+func (p *ChunkProposer) staleChunkReason(chunk *orm.Chunk) string {
+	if _, nextForkHeight := forks.BlockRange(chunk.StartBlockNumber, p.forkHeights); chunk.EndBlockNumber >= nextForkHeight {
+		return staleChunkReasonForkBoundary
+	}
+
+	// the codec version of the chunks proposed before it's recorded is unknown.
+	if chunk.CodecVersion < 0 {
+		return ""
+	}
+	codecVersion := encoding.CodecV0
+	if p.chainCfg.IsBernoulli(new(big.Int).SetUint64(chunk.StartBlockNumber)) {
+		codecVersion = encoding.CodecV1
+	}
+	if encoding.CodecVersion(chunk.CodecVersion) != codecVersion {
+		return staleChunkReasonCodecMismatch
+	}
+	return ""
+}
@@ -0,0 +1,160 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+
+	"scroll-tech/common/forks"
+	"scroll-tech/common/types/encoding"
+
+	"scroll-tech/rollup/internal/utils"
+)
+
+// Names of the chunk limits reported by ChunkProposer.exceededLimits.
+const (
+	chunkLimitTxNum                = "max_tx_num_per_chunk"
+	chunkLimitL1CommitCalldataSize = "max_l1_commit_calldata_size_per_chunk"
+	chunkLimitL1CommitGas          = "max_l1_commit_gas_per_chunk"
+	chunkLimitRowConsumption       = "max_row_consumption_per_chunk"
+	chunkLimitBlobSize             = "max_blob_size"
+	chunkLimitUncompressedPayload  = "max_uncompressed_payload_size_per_chunk"
+)
+
+// ChunkUtilizationReport shows how the pending (unchunked) blocks use up the limits of the next chunk,
+// i.e. why the next chunk is or isn't sealed yet.
+type ChunkUtilizationReport struct {
+	CodecVersion             encoding.CodecVersion `json:"codec_version"`
+	CurrentTime              uint64                `json:"current_time"`
+	Profile                  string                `json:"profile"`
+	ChunkTimeoutSec          uint64                `json:"chunk_timeout_sec"`
+	L1MessageChunkTimeoutSec uint64                `json:"l1_message_chunk_timeout_sec"`
+	MaxBlockNumPerChunk      uint64                `json:"max_block_num_per_chunk"`
+	Limits                   map[string]uint64     `json:"limits"`
+	Blocks                   []*BlockUtilization   `json:"blocks"`
+}
+
+// BlockUtilization is the estimated cost of a pending block, along with the cumulative cost of the
+// chunk made of the pending blocks up to and including it.
+type BlockUtilization struct {
+	Number    uint64 `json:"number"`
+	Timestamp uint64 `json:"timestamp"`
+
+	TxNum                   uint64 `json:"tx_num"`
+	RowConsumption          uint64 `json:"row_consumption"`
+	L1CommitGas             uint64 `json:"l1_commit_gas"`
+	L1CommitCalldataSize    uint64 `json:"l1_commit_calldata_size"`
+	UncompressedPayloadSize uint64 `json:"uncompressed_payload_size"`
+
+	CumulativeTxNum                uint64 `json:"cumulative_tx_num"`
+	CumulativeRowConsumption       uint64 `json:"cumulative_row_consumption"`
+	CumulativeL1CommitGas          uint64 `json:"cumulative_l1_commit_gas"`
+	CumulativeL1CommitCalldataSize uint64 `json:"cumulative_l1_commit_calldata_size"`
+	CumulativeL1CommitBlobSize     uint64 `json:"cumulative_l1_commit_blob_size"`
+	CumulativeUncompressedPayload  uint64 `json:"cumulative_uncompressed_payload_size"`
+
+	// ExceededLimits lists the chunk limits exceeded by the cumulative cost, the block doesn't fit in the next chunk if not empty.
+	ExceededLimits []string `json:"exceeded_limits,omitempty"`
+}
+
+// GetChunkUtilizationReport estimates the constraint utilization of the pending blocks that may go into the next chunk.
+func (p *ChunkProposer) GetChunkUtilizationReport(ctx context.Context) (*ChunkUtilizationReport, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	unchunkedBlockHeight, err := p.chunkStore.GetUnchunkedBlockHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	maxBlocksThisChunk := p.maxBlockNumPerChunk
+	blocksUntilFork := forks.BlocksUntilFork(unchunkedBlockHeight, p.forkHeights)
+	if blocksUntilFork != 0 && blocksUntilFork < maxBlocksThisChunk {
+		maxBlocksThisChunk = blocksUntilFork
+	}
+
+	report := &ChunkUtilizationReport{
+		CurrentTime:              uint64(p.clock.Now().Unix()),
+		Profile:                  p.profile,
+		ChunkTimeoutSec:          p.chunkTimeoutSec,
+		L1MessageChunkTimeoutSec: p.l1MessageChunkTimeoutSec,
+		MaxBlockNumPerChunk:      maxBlocksThisChunk,
+		Limits: map[string]uint64{
+			chunkLimitTxNum:                p.maxTxNumPerChunk,
+			chunkLimitL1CommitCalldataSize: p.maxL1CommitCalldataSizePerChunk,
+			chunkLimitL1CommitGas:          p.maxL1CommitGasPerChunk,
+			chunkLimitRowConsumption:       p.maxRowConsumptionPerChunk,
+			chunkLimitBlobSize:             maxBlobSize,
+			chunkLimitUncompressedPayload:  p.maxUncompressedPayloadSize,
+		},
+		Blocks: []*BlockUtilization{},
+	}
+
+	blocks, err := p.blockSource.GetL2BlocksGEHeight(ctx, unchunkedBlockHeight, int(maxBlocksThisChunk))
+	if err != nil {
+		return nil, err
+	}
+	if len(blocks) == 0 {
+		return report, nil
+	}
+
+	report.CodecVersion = encoding.CodecV0
+	if p.chainCfg.IsBernoulli(blocks[0].Header.Number) {
+		report.CodecVersion = encoding.CodecV1
+	}
+
+	var chunk encoding.Chunk
+	for _, block := range blocks {
+		blockMetrics, err := p.estimationCache.CalculateChunkMetrics(&encoding.Chunk{Blocks: []*encoding.Block{block}}, report.CodecVersion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate block metrics: %w", err)
+		}
+
+		chunk.Blocks = append(chunk.Blocks, block)
+		chunkMetrics, err := p.estimationCache.CalculateChunkMetrics(&chunk, report.CodecVersion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate chunk metrics: %w", err)
+		}
+
+		report.Blocks = append(report.Blocks, &BlockUtilization{
+			Number:                         block.Header.Number.Uint64(),
+			Timestamp:                      block.Header.Time,
+			TxNum:                          blockMetrics.TxNum,
+			RowConsumption:                 blockMetrics.CrcMax,
+			L1CommitGas:                    blockMetrics.L1CommitGas,
+			L1CommitCalldataSize:           blockMetrics.L1CommitCalldataSize,
+			UncompressedPayloadSize:        blockMetrics.UncompressedPayloadSize,
+			CumulativeTxNum:                chunkMetrics.TxNum,
+			CumulativeRowConsumption:       chunkMetrics.CrcMax,
+			CumulativeL1CommitGas:          chunkMetrics.L1CommitGas,
+			CumulativeL1CommitCalldataSize: chunkMetrics.L1CommitCalldataSize,
+			CumulativeL1CommitBlobSize:     chunkMetrics.L1CommitBlobSize,
+			CumulativeUncompressedPayload:  chunkMetrics.UncompressedPayloadSize,
+			ExceededLimits:                 p.exceededLimits(chunkMetrics),
+		})
+	}
+	return report, nil
+}
+
+// exceededLimits returns the names of the chunk limits exceeded by the chunk metrics.
+func (p *ChunkProposer) exceededLimits(metrics *utils.ChunkMetrics) []string {
+	var exceeded []string
+	if metrics.TxNum > p.maxTxNumPerChunk {
+		exceeded = append(exceeded, chunkLimitTxNum)
+	}
+	if metrics.L1CommitCalldataSize > p.maxL1CommitCalldataSizePerChunk {
+		exceeded = append(exceeded, chunkLimitL1CommitCalldataSize)
+	}
+	if uint64(p.gasCostIncreaseMultiplier*float64(metrics.L1CommitGas)) > p.maxL1CommitGasPerChunk {
+		exceeded = append(exceeded, chunkLimitL1CommitGas)
+	}
+	if metrics.CrcMax > p.maxRowConsumptionPerChunk {
+		exceeded = append(exceeded, chunkLimitRowConsumption)
+	}
+	if metrics.L1CommitBlobSize > maxBlobSize {
+		exceeded = append(exceeded, chunkLimitBlobSize)
+	}
+	if p.maxUncompressedPayloadSize > 0 && metrics.UncompressedPayloadSize > p.maxUncompressedPayloadSize {
+		exceeded = append(exceeded, chunkLimitUncompressedPayload)
+	}
+	return exceeded
+}
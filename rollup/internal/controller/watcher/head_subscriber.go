@@ -0,0 +1,147 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/ethclient"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+const defaultHeadReconnectInterval = 30 * time.Second
+
+// headSource is the part of the websocket client used by the HeadSubscriber.
+type headSource interface {
+	SubscribeNewHead(ctx context.Context, ch chan<- *gethTypes.Header) (ethereum.Subscription, error)
+	Close()
+}
+
+// HeadSubscriber runs a fetch function on every new head of the l1 or l2 chain received over an
+// eth_subscribe("newHeads") subscription, and falls back to running it periodically while the subscription is unavailable.
+type HeadSubscriber struct {
+	ctx               context.Context
+	layer             string
+	wsEndpoint        string
+	pollPeriod        time.Duration
+	reconnectInterval time.Duration
+	dial              func(ctx context.Context, endpoint string) (headSource, error)
+
+	headSubscriptionHeadsTotal     prometheus.Counter
+	headSubscriptionFallbacksTotal prometheus.Counter
+	headSubscriptionActive         prometheus.Gauge
+}
+
+// NewHeadSubscriber creates a new HeadSubscriber of the heads of the given layer (l1 or l2), pollPeriod is the period
+// of the polling fallback. The name distinguishes the metrics of the subscribers of different services.
+func NewHeadSubscriber(ctx context.Context, cfg *config.HeadSubscriptionConfig, pollPeriod time.Duration, layer string, name string, reg prometheus.Registerer) *HeadSubscriber {
+	reconnectInterval := defaultHeadReconnectInterval
+	if cfg.ReconnectIntervalSec > 0 {
+		reconnectInterval = time.Duration(cfg.ReconnectIntervalSec) * time.Second
+	}
+	labels := prometheus.Labels{"subscriber": name}
+	return &HeadSubscriber{
+		ctx:               ctx,
+		layer:             layer,
+		wsEndpoint:        cfg.WSEndpoint,
+		pollPeriod:        pollPeriod,
+		reconnectInterval: reconnectInterval,
+		dial: func(ctx context.Context, endpoint string) (headSource, error) {
+			return ethclient.DialContext(ctx, endpoint)
+		},
+		headSubscriptionHeadsTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name:        fmt.Sprintf("rollup_%s_head_subscription_heads_total", layer),
+			Help:        fmt.Sprintf("The total number of %s heads received over the head subscription", layer),
+			ConstLabels: labels,
+		}),
+		headSubscriptionFallbacksTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name:        fmt.Sprintf("rollup_%s_head_subscription_fallbacks_total", layer),
+			Help:        fmt.Sprintf("The total number of times the %s head subscription fell back to polling", layer),
+			ConstLabels: labels,
+		}),
+		headSubscriptionActive: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name:        fmt.Sprintf("rollup_%s_head_subscription_active", layer),
+			Help:        fmt.Sprintf("Whether the %s head subscription is active (1) or polling is used (0)", layer),
+			ConstLabels: labels,
+		}),
+	}
+}
+
+// Run runs f on every new head until the context is done. While the subscription is unavailable
+// f is run every poll period, and the subscription is retried every reconnect interval.
+func (s *HeadSubscriber) Run(f func(ctx context.Context)) {
+	for {
+		err := s.subscribe(f)
+		s.headSubscriptionActive.Set(0)
+		if s.ctx.Err() != nil {
+			return
+		}
+		log.Warn("Head subscription unavailable, falling back to polling", "layer", s.layer, "endpoint", s.wsEndpoint, "retry in", s.reconnectInterval, "err", err)
+		s.headSubscriptionFallbacksTotal.Inc()
+		if !s.poll(f) {
+			return
+		}
+	}
+}
+
+// subscribe runs f on every new head of the subscription, it returns when the subscription fails.
+func (s *HeadSubscriber) subscribe(f func(ctx context.Context)) error {
+	client, err := s.dial(s.ctx, s.wsEndpoint)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	heads := make(chan *gethTypes.Header, 16)
+	sub, err := client.SubscribeNewHead(s.ctx, heads)
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	log.Info("Head subscription established", "layer", s.layer, "endpoint", s.wsEndpoint)
+	s.headSubscriptionActive.Set(1)
+
+	// Catch up on what happened while the subscription was unavailable.
+	f(s.ctx)
+	for {
+		select {
+		case <-s.ctx.Done():
+			return s.ctx.Err()
+		case err = <-sub.Err():
+			return err
+		case <-heads:
+			s.headSubscriptionHeadsTotal.Inc()
+			// f always fetches up to the latest head, so the heads queued meanwhile are skipped.
+			for len(heads) > 0 {
+				<-heads
+				s.headSubscriptionHeadsTotal.Inc()
+			}
+			f(s.ctx)
+		}
+	}
+}
+
+// poll runs f every poll period until the reconnect interval elapses, it returns false if the context is done.
+func (s *HeadSubscriber) poll(f func(ctx context.Context)) bool {
+	reconnect := time.NewTimer(s.reconnectInterval)
+	defer reconnect.Stop()
+	tick := time.NewTicker(s.pollPeriod)
+	defer tick.Stop()
+	for ; ; <-tick.C {
+		select {
+		case <-s.ctx.Done():
+			return false
+		case <-reconnect.C:
+			return true
+		default:
+			f(s.ctx)
+		}
+	}
+}
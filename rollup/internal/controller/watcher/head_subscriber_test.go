@@ -0,0 +1,86 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scroll-tech/go-ethereum"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/event"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+// mockHeadSource serves the heads pushed to its channel until the subscription is failed.
+type mockHeadSource struct {
+	heads chan *gethTypes.Header
+	fail  chan error
+}
+
+func (s *mockHeadSource) SubscribeNewHead(_ context.Context, ch chan<- *gethTypes.Header) (ethereum.Subscription, error) {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		for {
+			select {
+			case <-quit:
+				return nil
+			case err := <-s.fail:
+				return err
+			case head := <-s.heads:
+				ch <- head
+			}
+		}
+	}), nil
+}
+
+func (s *mockHeadSource) Close() {}
+
+func TestHeadSubscriber(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	subscriber := NewHeadSubscriber(ctx, &config.HeadSubscriptionConfig{WSEndpoint: "ws://l1"}, 10*time.Millisecond, "l1", "test", prometheus.NewRegistry())
+	subscriber.reconnectInterval = 100 * time.Millisecond
+
+	source := &mockHeadSource{heads: make(chan *gethTypes.Header), fail: make(chan error)}
+	var dials atomic.Int64
+	subscriber.dial = func(context.Context, string) (headSource, error) {
+		// The second dial fails, so the subscriber keeps polling until the third one.
+		if dials.Add(1) == 2 {
+			return nil, errors.New("connection refused")
+		}
+		return source, nil
+	}
+
+	var runs atomic.Int64
+	done := make(chan struct{})
+	go func() {
+		subscriber.Run(func(context.Context) { runs.Add(1) })
+		close(done)
+	}()
+
+	// The catch-up run after the subscription is established.
+	assert.Eventually(t, func() bool { return runs.Load() == 1 }, time.Second, time.Millisecond)
+
+	// Every new head triggers a run, without polling in between.
+	source.heads <- &gethTypes.Header{}
+	assert.Eventually(t, func() bool { return runs.Load() == 2 }, time.Second, time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int64(2), runs.Load())
+
+	// A disconnect falls back to polling until the subscription is re-established.
+	source.fail <- errors.New("connection reset")
+	assert.Eventually(t, func() bool { return dials.Load() == 3 }, time.Second, time.Millisecond)
+	assert.Greater(t, runs.Load(), int64(10))
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("head subscriber did not stop")
+	}
+}
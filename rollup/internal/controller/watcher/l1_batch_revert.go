@@ -0,0 +1,37 @@
+package watcher
+
+import (
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+
+	"scroll-tech/rollup/internal/orm"
+)
+
+// revertBatch moves a batch reverted on L1 back to pending along with its chunks, so that the node proves
+// and commits it again instead of keeping a commitment that no longer exists on L1.
+func (w *L1WatcherClient) revertBatch(batchHash string, status types.RollupStatus) error {
+	switch status {
+	case types.RollupPending:
+		return nil
+	case types.RollupFinalized:
+		// finalized batches cannot be reverted by the rollup contract.
+		log.Error("Ignoring revert of a finalized batch", "batchHash", batchHash)
+		return nil
+	}
+
+	err := w.db.Transaction(func(dbTX *gorm.DB) error {
+		if err := orm.NewBatch(dbTX).ResetRevertedBatch(w.ctx, batchHash); err != nil {
+			return err
+		}
+		return orm.NewChunk(dbTX).UpdateProvingStatusByBatchHash(w.ctx, batchHash, types.ProvingTaskUnassigned)
+	})
+	if err != nil {
+		return err
+	}
+
+	w.metrics.l1WatcherRevertedBatchesTotal.Inc()
+	log.Warn("Batch reverted on L1, moved back to pending", "batchHash", batchHash, "previousStatus", status.String())
+	return nil
+}
@@ -0,0 +1,63 @@
+package watcher
+
+import (
+	"fmt"
+
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rpc"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/utils"
+)
+
+// SetBeaconCheckpoints makes the configured event types confirmed by "safe" or "finalized" take the safe and
+// finalized heads from the justified and finalized checkpoints of the beacon chain instead of the l1 node.
+func (w *L1WatcherClient) SetBeaconCheckpoints(cfg *config.BeaconCheckpointsConfig) {
+	w.beacon = nil
+	w.beaconEventTypes = make(map[l1EventType]bool)
+	if cfg == nil {
+		return
+	}
+	w.beacon = utils.NewBeaconClient(cfg.BeaconEndpoint, 0)
+	for _, eventType := range l1EventTypes {
+		w.beaconEventTypes[eventType] = len(cfg.EventFilters) == 0
+	}
+	for _, eventFilter := range cfg.EventFilters {
+		known := false
+		for _, eventType := range l1EventTypes {
+			if eventType.String() == eventFilter {
+				w.beaconEventTypes[eventType] = true
+				known = true
+			}
+		}
+		if !known {
+			log.Warn("Ignoring the beacon checkpoints of an unknown event filter", "eventFilter", eventFilter)
+		}
+	}
+}
+
+// usesBeaconCheckpoints tells whether the confirmed height of an event type is taken from the beacon checkpoints.
+func (w *L1WatcherClient) usesBeaconCheckpoints(eventType l1EventType) bool {
+	if w.beacon == nil || !w.beaconEventTypes[eventType] {
+		return false
+	}
+	confirmations := w.eventTypeConfirmations(eventType)
+	return confirmations == rpc.SafeBlockNumber || confirmations == rpc.FinalizedBlockNumber
+}
+
+// confirmedHeight returns the height up to which the events of a group are actionable.
+func (w *L1WatcherClient) confirmedHeight(group *l1EventGroup) (uint64, error) {
+	if !group.beacon {
+		return utils.GetLatestConfirmedBlockNumber(w.ctx, w.client, group.confirmations)
+	}
+	checkpoints, err := w.beacon.Checkpoints(w.ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get beacon checkpoints: %w", err)
+	}
+	w.metrics.l1WatcherBeaconCheckpointHeight.WithLabelValues("safe").Set(float64(checkpoints.Safe))
+	w.metrics.l1WatcherBeaconCheckpointHeight.WithLabelValues("finalized").Set(float64(checkpoints.Finalized))
+	if group.confirmations == rpc.FinalizedBlockNumber {
+		return checkpoints.Finalized, nil
+	}
+	return checkpoints.Safe, nil
+}
@@ -0,0 +1,65 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+func TestL1WatcherBeaconCheckpoints(t *testing.T) {
+	justifiedRoot := common.HexToHash("0x01")
+	finalizedRoot := common.HexToHash("0x02")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/eth/v1/beacon/states/head/finality_checkpoints":
+			_, _ = fmt.Fprintf(w, `{"data":{"current_justified":{"epoch":"9","root":"%s"},"finalized":{"epoch":"8","root":"%s"}}}`, justifiedRoot.Hex(), finalizedRoot.Hex())
+		case "/eth/v2/beacon/blocks/" + justifiedRoot.Hex():
+			_, _ = fmt.Fprint(w, `{"data":{"message":{"body":{"execution_payload":{"block_number":"1064"}}}}}`)
+		case "/eth/v2/beacon/blocks/" + finalizedRoot.Hex():
+			_, _ = fmt.Fprint(w, `{"data":{"message":{"body":{"execution_payload":{"block_number":"1032"}}}}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	w := &L1WatcherClient{
+		ctx:           context.Background(),
+		confirmations: rpc.BlockNumber(6),
+		metrics:       initL1WatcherMetrics(prometheus.NewRegistry()),
+	}
+	safe, finalized := rpc.SafeBlockNumber, rpc.FinalizedBlockNumber
+	w.SetEventConfirmations(&config.EventConfirmationsConfig{L1Message: &safe, CommitBatch: &safe, FinalizeBatch: &finalized})
+	w.SetBeaconCheckpoints(&config.BeaconCheckpointsConfig{BeaconEndpoint: server.URL, EventFilters: []string{"commit_batch", "finalize_batch"}})
+
+	// The l1 messages keep the safe head of the l1 node, so they are not grouped with the batch commits.
+	groups := w.eventGroups()
+	assert.Len(t, groups, 3)
+	assert.Equal(t, []l1EventType{l1EventL1Message}, groups[0].eventTypes)
+	assert.False(t, groups[0].beacon)
+	assert.Equal(t, []l1EventType{l1EventCommitBatch}, groups[1].eventTypes)
+	assert.True(t, groups[1].beacon)
+	assert.True(t, groups[2].beacon)
+
+	height, err := w.confirmedHeight(groups[1])
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1064), height)
+	height, err = w.confirmedHeight(groups[2])
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1032), height)
+
+	// The event types confirmed by a number of blocks ignore the beacon checkpoints.
+	w.SetEventConfirmations(nil)
+	groups = w.eventGroups()
+	assert.Len(t, groups, 1)
+	assert.False(t, groups[0].beacon)
+}
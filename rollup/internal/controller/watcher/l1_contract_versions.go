@@ -0,0 +1,169 @@
+package watcher
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/scroll-tech/go-ethereum/accounts/abi"
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	bridgeAbi "scroll-tech/rollup/abi"
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/utils"
+)
+
+// l1EventNames are the contract events imported for each event type.
+var l1EventNames = map[l1EventType][]string{
+	l1EventL1Message: {"QueueTransaction"},
+	// the reverts undo the commits, they are imported along with them to be applied in order.
+	l1EventCommitBatch:   {"CommitBatch", "RevertBatch"},
+	l1EventFinalizeBatch: {"FinalizeBatch"},
+}
+
+// l1ContractVersion is a deployed version of the rollup contracts, with the ABIs decoding its events.
+// Its events are imported from the L1 blocks in [startHeight, endHeight], endHeight zero meaning open-ended,
+// so that the versions before and after an upgrade are watched simultaneously around the cutover.
+type l1ContractVersion struct {
+	name        string
+	startHeight uint64
+	endHeight   uint64
+
+	messageQueueAddress common.Address
+	messageQueueABI     *abi.ABI
+	scrollChainAddress  common.Address
+	scrollChainABI      *abi.ABI
+
+	// The L2 alias of the L1ScrollMessenger of the version, the one of the watcher if zero.
+	l1MessengerAliasAddress common.Address
+}
+
+// SetContractVersions sets the deployed versions of the rollup contracts to watch, the contracts the watcher
+// is created with are watched at all heights if none.
+func (w *L1WatcherClient) SetContractVersions(cfgs []*config.ContractVersionConfig) error {
+	versions := make([]*l1ContractVersion, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		messageQueueABI, err := loadContractABI(cfg.L1MessageQueueABIPath, bridgeAbi.L1MessageQueueABI)
+		if err != nil {
+			return fmt.Errorf("failed to load L1MessageQueue abi of contract version %v: %w", cfg.Name, err)
+		}
+		scrollChainABI, err := loadContractABI(cfg.ScrollChainABIPath, bridgeAbi.ScrollChainABI)
+		if err != nil {
+			return fmt.Errorf("failed to load ScrollChain abi of contract version %v: %w", cfg.Name, err)
+		}
+		version := &l1ContractVersion{
+			name:                cfg.Name,
+			startHeight:         cfg.StartHeight,
+			endHeight:           cfg.EndHeight,
+			messageQueueAddress: cfg.L1MessageQueueAddress,
+			messageQueueABI:     messageQueueABI,
+			scrollChainAddress:  cfg.ScrollChainContractAddress,
+			scrollChainABI:      scrollChainABI,
+		}
+		if cfg.L1ScrollMessengerAddress != (common.Address{}) {
+			version.l1MessengerAliasAddress = utils.ApplyL1ToL2Alias(cfg.L1ScrollMessengerAddress)
+		}
+		for _, name := range []string{"CommitBatch", "FinalizeBatch"} {
+			if _, ok := scrollChainABI.Events[name]; !ok {
+				return fmt.Errorf("ScrollChain abi of contract version %v has no %v event", cfg.Name, name)
+			}
+		}
+		if _, ok := messageQueueABI.Events["QueueTransaction"]; !ok {
+			return fmt.Errorf("L1MessageQueue abi of contract version %v has no QueueTransaction event", cfg.Name)
+		}
+		log.Info("Watching L1 contract version", "name", version.name, "startHeight", version.startHeight, "endHeight", version.endHeight,
+			"messageQueue", version.messageQueueAddress, "scrollChain", version.scrollChainAddress)
+		versions = append(versions, version)
+	}
+	w.contractVersions = versions
+	return nil
+}
+
+// loadContractABI loads a JSON ABI file, the builtin ABI if the path is empty.
+func loadContractABI(path string, builtin *abi.ABI) (*abi.ABI, error) {
+	if path == "" {
+		return builtin, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+	contractABI, err := abi.JSON(file)
+	if err != nil {
+		return nil, err
+	}
+	return &contractABI, nil
+}
+
+// versions returns the watched contract versions, the contracts the watcher is created with if none is set.
+func (w *L1WatcherClient) versions() []*l1ContractVersion {
+	if len(w.contractVersions) > 0 {
+		return w.contractVersions
+	}
+	return []*l1ContractVersion{{
+		name:                "default",
+		messageQueueAddress: w.messageQueueAddress,
+		messageQueueABI:     bridgeAbi.L1MessageQueueABI,
+		scrollChainAddress:  w.scrollChainAddress,
+		scrollChainABI:      bridgeAbi.ScrollChainABI,
+	}}
+}
+
+// contract returns the address and the ABI of the contract emitting the events of an event type.
+func (v *l1ContractVersion) contract(eventType l1EventType) (common.Address, *abi.ABI) {
+	if eventType == l1EventL1Message {
+		return v.messageQueueAddress, v.messageQueueABI
+	}
+	return v.scrollChainAddress, v.scrollChainABI
+}
+
+// active tells whether the version is deployed at the given L1 height.
+func (v *l1ContractVersion) active(height uint64) bool {
+	return height >= v.startHeight && (v.endHeight == 0 || height <= v.endHeight)
+}
+
+// event returns the event type and the name of the event of the version with the given signature.
+func (v *l1ContractVersion) event(signature common.Hash) (l1EventType, string, bool) {
+	for _, eventType := range l1EventTypes {
+		_, contractABI := v.contract(eventType)
+		for _, name := range l1EventNames[eventType] {
+			if event, ok := contractABI.Events[name]; ok && event.ID == signature {
+				return eventType, name, true
+			}
+		}
+	}
+	return 0, "", false
+}
+
+// logEventType returns the event type of a log of any of the watched versions, l1EventL1Message if unknown.
+func (w *L1WatcherClient) logEventType(vLog gethTypes.Log) l1EventType {
+	for _, version := range w.versions() {
+		if eventType, _, ok := version.event(vLog.Topics[0]); ok {
+			return eventType
+		}
+	}
+	return l1EventL1Message
+}
+
+// logVersion returns the version which emitted a log, the first one whose contract emitted it at the log height.
+// The logs of a contract address outside the L1 blocks of its versions are not imported, the logs of
+// an unknown contract address are decoded by the latest version.
+func (w *L1WatcherClient) logVersion(vLog gethTypes.Log) (*l1ContractVersion, bool) {
+	versions := w.versions()
+	knownAddress := false
+	for _, version := range versions {
+		if vLog.Address != version.messageQueueAddress && vLog.Address != version.scrollChainAddress {
+			continue
+		}
+		knownAddress = true
+		if version.active(vLog.BlockNumber) {
+			return version, true
+		}
+	}
+	if knownAddress {
+		return nil, false
+	}
+	return versions[len(versions)-1], true
+}
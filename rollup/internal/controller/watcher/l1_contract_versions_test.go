@@ -0,0 +1,72 @@
+package watcher
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+
+	commonTypes "scroll-tech/common/types"
+
+	bridgeAbi "scroll-tech/rollup/abi"
+	"scroll-tech/rollup/internal/config"
+)
+
+// scrollChainV0ABI is a ScrollChain ABI without the RevertBatch event.
+const scrollChainV0ABI = `[
+	{"anonymous":false,"inputs":[{"indexed":true,"name":"batchIndex","type":"uint256"},{"indexed":true,"name":"batchHash","type":"bytes32"}],"name":"CommitBatch","type":"event"},
+	{"anonymous":false,"inputs":[{"indexed":true,"name":"batchIndex","type":"uint256"},{"indexed":true,"name":"batchHash","type":"bytes32"},{"indexed":false,"name":"stateRoot","type":"bytes32"},{"indexed":false,"name":"withdrawRoot","type":"bytes32"}],"name":"FinalizeBatch","type":"event"}
+]`
+
+func TestL1WatcherContractVersions(t *testing.T) {
+	abiPath := filepath.Join(t.TempDir(), "scroll_chain_v0.json")
+	assert.NoError(t, os.WriteFile(abiPath, []byte(scrollChainV0ABI), 0600))
+
+	messageQueue := common.HexToAddress("0x01")
+	scrollChainV0 := common.HexToAddress("0x02")
+	scrollChainV1 := common.HexToAddress("0x03")
+	w := &L1WatcherClient{}
+	assert.NoError(t, w.SetContractVersions([]*config.ContractVersionConfig{
+		{Name: "v0", StartHeight: 1, EndHeight: 100, L1MessageQueueAddress: messageQueue, ScrollChainContractAddress: scrollChainV0, ScrollChainABIPath: abiPath},
+		{Name: "v1", StartHeight: 90, L1MessageQueueAddress: messageQueue, ScrollChainContractAddress: scrollChainV1},
+	}))
+
+	// Both versions are watched by the same filters.
+	addresses, topics := w.filter(l1EventTypes)
+	assert.Equal(t, []common.Address{messageQueue, scrollChainV0, scrollChainV1}, addresses)
+	assert.Len(t, topics, 4)
+
+	revertLog := func(address common.Address, height uint64) gethTypes.Log {
+		return gethTypes.Log{
+			Address:     address,
+			Topics:      []common.Hash{bridgeAbi.L1RevertBatchEventSignature, common.BigToHash(big.NewInt(7)), common.HexToHash("0x07")},
+			BlockNumber: height,
+		}
+	}
+
+	version, ok := w.logVersion(revertLog(scrollChainV0, 95))
+	assert.True(t, ok)
+	assert.Equal(t, "v0", version.name)
+	version, ok = w.logVersion(revertLog(scrollChainV1, 95))
+	assert.True(t, ok)
+	assert.Equal(t, "v1", version.name)
+	_, ok = w.logVersion(revertLog(scrollChainV0, 101))
+	assert.False(t, ok)
+
+	// The reverts are only decoded for the version emitting them.
+	_, rollupEvents, err := w.parseBridgeEventLogs([]gethTypes.Log{revertLog(scrollChainV0, 95), revertLog(scrollChainV0, 101), revertLog(scrollChainV1, 95)})
+	assert.NoError(t, err)
+	assert.Len(t, rollupEvents, 1)
+	assert.Equal(t, common.HexToHash("0x07"), rollupEvents[0].batchHash)
+	assert.Equal(t, commonTypes.RollupPending, rollupEvents[0].status)
+
+	// An ABI without the imported events is rejected.
+	assert.NoError(t, os.WriteFile(abiPath, []byte(`[]`), 0600))
+	assert.Error(t, w.SetContractVersions([]*config.ContractVersionConfig{
+		{Name: "v0", StartHeight: 1, L1MessageQueueAddress: messageQueue, ScrollChainContractAddress: scrollChainV0, ScrollChainABIPath: abiPath},
+	}))
+}
@@ -0,0 +1,141 @@
+package watcher
+
+import (
+	"fmt"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/rpc"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+// l1EventType is a type of the L1 events imported by the L1WatcherClient.
+type l1EventType int
+
+const (
+	l1EventL1Message l1EventType = iota
+	l1EventCommitBatch
+	l1EventFinalizeBatch
+)
+
+var l1EventTypes = []l1EventType{l1EventL1Message, l1EventCommitBatch, l1EventFinalizeBatch}
+
+// String returns the name of the event type, also the name of its event filter checkpoint.
+func (t l1EventType) String() string {
+	switch t {
+	case l1EventL1Message:
+		return "l1_message"
+	case l1EventCommitBatch:
+		return "commit_batch"
+	case l1EventFinalizeBatch:
+		return "finalize_batch"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(t))
+	}
+}
+
+// l1EventGroup is a set of event types with the same confirmations, imported by the same log filter.
+type l1EventGroup struct {
+	confirmations rpc.BlockNumber
+	// beacon tells whether the safe and finalized heads are taken from the beacon checkpoints.
+	beacon     bool
+	eventTypes []l1EventType
+}
+
+// SetEventConfirmations overrides the confirmations of the configured event types.
+func (w *L1WatcherClient) SetEventConfirmations(cfg *config.EventConfirmationsConfig) {
+	w.eventConfirmations = make(map[l1EventType]rpc.BlockNumber)
+	if cfg == nil {
+		return
+	}
+	if cfg.L1Message != nil {
+		w.eventConfirmations[l1EventL1Message] = *cfg.L1Message
+	}
+	if cfg.CommitBatch != nil {
+		w.eventConfirmations[l1EventCommitBatch] = *cfg.CommitBatch
+	}
+	if cfg.FinalizeBatch != nil {
+		w.eventConfirmations[l1EventFinalizeBatch] = *cfg.FinalizeBatch
+	}
+}
+
+func (w *L1WatcherClient) eventTypeConfirmations(eventType l1EventType) rpc.BlockNumber {
+	if confirmations, ok := w.eventConfirmations[eventType]; ok {
+		return confirmations
+	}
+	return w.confirmations
+}
+
+// eventGroups groups the event types by confirmations (and source of the safe and finalized heads), so that the event types with the same confirmations
+// (all of them without overrides) are imported with a single log filter.
+func (w *L1WatcherClient) eventGroups() []*l1EventGroup {
+	var groups []*l1EventGroup
+	for _, eventType := range l1EventTypes {
+		confirmations := w.eventTypeConfirmations(eventType)
+		beacon := w.usesBeaconCheckpoints(eventType)
+		var group *l1EventGroup
+		for _, g := range groups {
+			if g.confirmations == confirmations && g.beacon == beacon {
+				group = g
+				break
+			}
+		}
+		if group == nil {
+			group = &l1EventGroup{confirmations: confirmations, beacon: beacon}
+			groups = append(groups, group)
+		}
+		group.eventTypes = append(group.eventTypes, eventType)
+	}
+	return groups
+}
+
+// filter returns the contract addresses and the event signatures of the event types of a group, of all the contract versions.
+func (w *L1WatcherClient) filter(eventTypes []l1EventType) ([]common.Address, []common.Hash) {
+	var addresses []common.Address
+	var topics []common.Hash
+	addAddress := func(address common.Address) {
+		for _, a := range addresses {
+			if a == address {
+				return
+			}
+		}
+		addresses = append(addresses, address)
+	}
+	addTopic := func(topic common.Hash) {
+		for _, t := range topics {
+			if t == topic {
+				return
+			}
+		}
+		topics = append(topics, topic)
+	}
+	for _, version := range w.versions() {
+		for _, eventType := range eventTypes {
+			address, contractABI := version.contract(eventType)
+			addAddress(address)
+			for _, name := range l1EventNames[eventType] {
+				if event, ok := contractABI.Events[name]; ok {
+					addTopic(event.ID)
+				}
+			}
+		}
+	}
+	return addresses, topics
+}
+
+// groupProcessedHeight returns the height up to which all the event types of a group are processed.
+func (w *L1WatcherClient) groupProcessedHeight(eventTypes []l1EventType) uint64 {
+	height := w.processedEventHeights[eventTypes[0]]
+	for _, eventType := range eventTypes[1:] {
+		height = min(height, w.processedEventHeights[eventType])
+	}
+	return height
+}
+
+// setProcessedHeight updates the processed height of the given event types, processedMsgHeight is the lowest of all.
+func (w *L1WatcherClient) setProcessedHeight(eventTypes []l1EventType, height uint64) {
+	for _, eventType := range eventTypes {
+		w.processedEventHeights[eventType] = height
+	}
+	w.processedMsgHeight = w.groupProcessedHeight(l1EventTypes)
+}
@@ -0,0 +1,68 @@
+package watcher
+
+import (
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+
+	bridgeAbi "scroll-tech/rollup/abi"
+	"scroll-tech/rollup/internal/config"
+)
+
+func TestL1WatcherEventGroups(t *testing.T) {
+	messageQueue := common.HexToAddress("0x01")
+	scrollChain := common.HexToAddress("0x02")
+	w := &L1WatcherClient{
+		confirmations:         rpc.BlockNumber(6),
+		messageQueueAddress:   messageQueue,
+		scrollChainAddress:    scrollChain,
+		processedEventHeights: map[l1EventType]uint64{},
+	}
+
+	// Without overrides all the event types are imported by a single filter.
+	w.SetEventConfirmations(nil)
+	groups := w.eventGroups()
+	assert.Len(t, groups, 1)
+	assert.Equal(t, rpc.BlockNumber(6), groups[0].confirmations)
+	addresses, topics := w.filter(groups[0].eventTypes)
+	assert.Equal(t, []common.Address{messageQueue, scrollChain}, addresses)
+	assert.Len(t, topics, 4)
+
+	finalized := rpc.FinalizedBlockNumber
+	w.SetEventConfirmations(&config.EventConfirmationsConfig{FinalizeBatch: &finalized})
+	groups = w.eventGroups()
+	assert.Len(t, groups, 2)
+	assert.Equal(t, []l1EventType{l1EventL1Message, l1EventCommitBatch}, groups[0].eventTypes)
+	assert.Equal(t, rpc.FinalizedBlockNumber, groups[1].confirmations)
+	addresses, topics = w.filter(groups[1].eventTypes)
+	assert.Equal(t, []common.Address{scrollChain}, addresses)
+	assert.Equal(t, []common.Hash{bridgeAbi.L1FinalizeBatchEventSignature}, topics)
+
+	// The processed height of the watcher is the lowest of the event types.
+	w.setProcessedHeight(groups[0].eventTypes, 100)
+	w.setProcessedHeight(groups[1].eventTypes, 80)
+	assert.Equal(t, uint64(80), w.processedMsgHeight)
+	assert.Equal(t, uint64(100), w.groupProcessedHeight(groups[0].eventTypes))
+}
+
+func TestL1WatcherSkipProcessedLogs(t *testing.T) {
+	w := &L1WatcherClient{processedEventHeights: map[l1EventType]uint64{
+		l1EventL1Message:     100,
+		l1EventCommitBatch:   90,
+		l1EventFinalizeBatch: 90,
+	}}
+
+	logs := []types.Log{
+		{BlockNumber: 95, Topics: []common.Hash{bridgeAbi.L1QueueTransactionEventSignature}},
+		{BlockNumber: 95, Topics: []common.Hash{bridgeAbi.L1CommitBatchEventSignature}},
+		{BlockNumber: 101, Topics: []common.Hash{bridgeAbi.L1QueueTransactionEventSignature}},
+		{BlockNumber: 90, Topics: []common.Hash{bridgeAbi.L1FinalizeBatchEventSignature}},
+	}
+	unprocessed := w.skipProcessedLogs(logs)
+	assert.Len(t, unprocessed, 2)
+	assert.Equal(t, bridgeAbi.L1CommitBatchEventSignature, unprocessed[0].Topics[0])
+	assert.Equal(t, uint64(101), unprocessed[1].BlockNumber)
+}
@@ -0,0 +1,45 @@
+package watcher
+
+import (
+	"fmt"
+	"math/big"
+
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+)
+
+// SetForcedTxQueueConfig sets the max inclusion delay of the enforced transactions, the enforced transactions
+// are stored without deadline if not set.
+func (w *L1WatcherClient) SetForcedTxQueueConfig(cfg *config.ForcedTxQueueConfig) {
+	w.maxInclusionDelaySec = 0
+	if cfg != nil {
+		w.maxInclusionDelaySec = cfg.MaxInclusionDelaySec
+	}
+}
+
+// setEnforcedTxTimes sets the enqueue time of the enforced transactions among the messages, the timestamp of the
+// L1 block queueing them, and their inclusion deadline, the enqueue time plus the max inclusion delay if set.
+func (w *L1WatcherClient) setEnforcedTxTimes(messages []*orm.L1Message) error {
+	headers := make(map[uint64]*gethTypes.Header)
+	for _, msg := range messages {
+		if !msg.IsEnforced {
+			continue
+		}
+		header, ok := headers[msg.Height]
+		if !ok {
+			var err error
+			header, err = w.client.HeaderByNumber(w.ctx, new(big.Int).SetUint64(msg.Height))
+			if err != nil {
+				return fmt.Errorf("failed to get header of L1 block %v: %w", msg.Height, err)
+			}
+			headers[msg.Height] = header
+		}
+		msg.EnqueueTime = header.Time
+		if w.maxInclusionDelaySec != 0 {
+			msg.InclusionDeadline = header.Time + w.maxInclusionDelaySec
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package watcher
+
+import (
+	"math/big"
+	"strings"
+
+	geth "github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+const (
+	defaultLogFetchMinBlockRange     = int64(1)
+	defaultLogFetchMaxBlockRange     = int64(1000)
+	defaultLogFetchSmallResponseLogs = 100
+)
+
+// logRangeTooLargeErrors are the error messages of the rpc providers rejecting a log query
+// because of its block range or the number of its results.
+var logRangeTooLargeErrors = []string{
+	"query returned more than",
+	"too many results",
+	"response size exceeded",
+	"response size is larger",
+	"block range",
+	"range is too large",
+	"range too large",
+	"limit exceeded",
+}
+
+// logFetchWindow is the adaptive block range of the log queries: it is halved when the provider rejects
+// a query with too many results and doubled while the responses stay small.
+type logFetchWindow struct {
+	size              int64
+	minSize           int64
+	maxSize           int64
+	smallResponseLogs int
+}
+
+func newLogFetchWindow(cfg *config.LogFetchConfig, endpoints []string) *logFetchWindow {
+	window := &logFetchWindow{
+		size:              contractEventsBlocksFetchLimit,
+		minSize:           defaultLogFetchMinBlockRange,
+		maxSize:           defaultLogFetchMaxBlockRange,
+		smallResponseLogs: defaultLogFetchSmallResponseLogs,
+	}
+	if cfg == nil {
+		return window
+	}
+	if cfg.MinBlockRange != 0 {
+		window.minSize = int64(cfg.MinBlockRange)
+	}
+	if cfg.MaxBlockRange != 0 {
+		window.maxSize = int64(cfg.MaxBlockRange)
+	}
+	for _, endpoint := range endpoints {
+		if providerMax, ok := cfg.ProviderMaxBlockRanges[endpoint]; ok && providerMax != 0 {
+			window.maxSize = min(window.maxSize, int64(providerMax))
+		}
+	}
+	window.minSize = min(window.minSize, window.maxSize)
+	if cfg.InitialBlockRange != 0 {
+		window.size = int64(cfg.InitialBlockRange)
+	}
+	window.size = max(min(window.size, window.maxSize), window.minSize)
+	if cfg.SmallResponseLogs != 0 {
+		window.smallResponseLogs = int(cfg.SmallResponseLogs)
+	}
+	return window
+}
+
+// shrink halves the block range, it returns false if it is already the minimum.
+func (w *logFetchWindow) shrink() bool {
+	if w.size <= w.minSize {
+		return false
+	}
+	w.size = max(w.size/2, w.minSize)
+	return true
+}
+
+// observe grows the block range after a response with few logs.
+func (w *logFetchWindow) observe(numLogs int) {
+	if numLogs < w.smallResponseLogs {
+		w.size = min(w.size*2, w.maxSize)
+	}
+}
+
+func isLogRangeTooLargeError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, tooLarge := range logRangeTooLargeErrors {
+		if strings.Contains(msg, tooLarge) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetLogFetchConfig configures the adaptive block range of the log queries, capped by the providers of the given endpoints.
+func (w *L1WatcherClient) SetLogFetchConfig(cfg *config.LogFetchConfig, endpoints []string) {
+	w.logFetchWindow = newLogFetchWindow(cfg, endpoints)
+}
+
+// filterLogs queries the logs from the given block on, in a range of the window size at most, shrinking the window
+// until the provider accepts the query. It returns the logs and the last block of the queried range.
+func (w *L1WatcherClient) filterLogs(from, toBlock int64, addresses []common.Address, topics []common.Hash) ([]gethTypes.Log, int64, error) {
+	for {
+		to := min(from+w.logFetchWindow.size-1, toBlock)
+		query := geth.FilterQuery{
+			FromBlock: big.NewInt(from), // inclusive
+			ToBlock:   big.NewInt(to),   // inclusive
+			Addresses: addresses,
+			Topics:    [][]common.Hash{topics},
+		}
+
+		logs, err := w.client.FilterLogs(w.ctx, query)
+		if err == nil {
+			w.logFetchWindow.observe(len(logs))
+			w.metrics.l1WatcherLogFetchBlockRange.Set(float64(w.logFetchWindow.size))
+			return logs, to, nil
+		}
+		if !isLogRangeTooLargeError(err) || !w.logFetchWindow.shrink() {
+			return nil, to, err
+		}
+		w.metrics.l1WatcherLogFetchShrinkTotal.Inc()
+		w.metrics.l1WatcherLogFetchBlockRange.Set(float64(w.logFetchWindow.size))
+		log.Warn("Log query rejected by the provider, shrinking the block range", "fromBlock", from, "toBlock", to, "blockRange", w.logFetchWindow.size, "err", err)
+	}
+}
@@ -0,0 +1,41 @@
+package watcher
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+func TestLogFetchWindow(t *testing.T) {
+	window := newLogFetchWindow(nil, nil)
+	assert.Equal(t, contractEventsBlocksFetchLimit, window.size)
+
+	// The lowest provider cap of the endpoints in use bounds the window.
+	cfg := &config.LogFetchConfig{
+		InitialBlockRange:      400,
+		MaxBlockRange:          1000,
+		SmallResponseLogs:      10,
+		ProviderMaxBlockRanges: map[string]uint64{"http://a": 500, "http://b": 800, "http://unused": 100},
+	}
+	window = newLogFetchWindow(cfg, []string{"http://a", "http://b"})
+	assert.Equal(t, int64(400), window.size)
+	assert.Equal(t, int64(500), window.maxSize)
+
+	window.observe(10)
+	assert.Equal(t, int64(400), window.size)
+	window.observe(9)
+	assert.Equal(t, int64(500), window.size)
+
+	for window.shrink() {
+	}
+	assert.Equal(t, int64(1), window.size)
+	window.observe(0)
+	assert.Equal(t, int64(2), window.size)
+
+	assert.True(t, isLogRangeTooLargeError(errors.New("query returned more than 10000 results")))
+	assert.True(t, isLogRangeTooLargeError(errors.New("Log response size exceeded. You can make eth_getLogs requests with up to a 2K block range")))
+	assert.False(t, isLogRangeTooLargeError(errors.New("connection refused")))
+}
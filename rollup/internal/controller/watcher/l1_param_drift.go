@@ -0,0 +1,215 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/accounts/abi"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/common/types/encoding/codecv1"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+// l1ParamsABI holds the getters of the on-chain rollup parameters compared by the L1ParamDriftDetector.
+const l1ParamsABI = `[
+	{"type":"function","name":"maxNumTxInChunk","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"verifier","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address"}]},
+	{"type":"function","name":"messageQueue","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address"}]},
+	{"type":"function","name":"isSequencer","stateMutability":"view","inputs":[{"name":"","type":"address"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"isProver","stateMutability":"view","inputs":[{"name":"","type":"address"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"scrollChain","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address"}]},
+	{"type":"function","name":"maxGasLimit","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]}
+]`
+
+// L1ParamDrift describes an on-chain rollup parameter that drifted from the local configuration.
+type L1ParamDrift struct {
+	Param    string
+	OnChain  string
+	Expected string
+}
+
+// L1ParamDriftDetector periodically reads the on-chain rollup parameters and compares them to the
+// relayer and proposer configuration, so that a drift is alerted before it causes commit reverts.
+type L1ParamDriftDetector struct {
+	ctx    context.Context
+	client ethereum.ContractCaller
+	abi    abi.ABI
+
+	scrollChainAddress    common.Address
+	l1MessageQueueAddress common.Address
+	verifierAddress       common.Address
+	commitSenderAddress   common.Address
+	finalizeSenderAddress common.Address
+	maxTxNumPerChunk      uint64
+	maxChunkNumPerBatch   uint64
+
+	paramDriftCheckTotal   prometheus.Counter
+	paramDriftFailureTotal prometheus.Counter
+	paramDrift             *prometheus.GaugeVec
+	l1MessageQueueMaxGas   prometheus.Gauge
+}
+
+// NewL1ParamDriftDetector creates a new L1ParamDriftDetector instance.
+func NewL1ParamDriftDetector(ctx context.Context, client ethereum.ContractCaller, cfg *config.Config, reg prometheus.Registerer) (*L1ParamDriftDetector, error) {
+	parsed, err := abi.JSON(strings.NewReader(l1ParamsABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse l1 params abi: %w", err)
+	}
+
+	d := &L1ParamDriftDetector{
+		ctx:                   ctx,
+		client:                client,
+		abi:                   parsed,
+		scrollChainAddress:    cfg.L2Config.RelayerConfig.RollupContractAddress,
+		l1MessageQueueAddress: cfg.L1Config.L1MessageQueueAddress,
+		maxTxNumPerChunk:      cfg.L2Config.ChunkProposerConfig.MaxTxNumPerChunk,
+		maxChunkNumPerBatch:   cfg.L2Config.BatchProposerConfig.MaxChunkNumPerBatch,
+
+		paramDriftCheckTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_l1_param_drift_check_total",
+			Help: "Total number of on-chain rollup parameter drift checks.",
+		}),
+		paramDriftFailureTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_l1_param_drift_check_failure_total",
+			Help: "Total number of failed on-chain rollup parameter drift checks.",
+		}),
+		paramDrift: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Name: "rollup_l1_param_drift",
+			Help: "Whether an on-chain rollup parameter drifted from the configuration (1) or not (0).",
+		}, []string{"param"}),
+		l1MessageQueueMaxGas: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_l1_message_queue_max_gas_limit",
+			Help: "The max gas limit of an L1 message read from the L1MessageQueue contract.",
+		}),
+	}
+	if driftCfg := cfg.L1Config.ParamDriftConfig; driftCfg != nil {
+		d.verifierAddress = driftCfg.VerifierAddress
+	}
+	if key := cfg.L2Config.RelayerConfig.CommitSenderPrivateKey; key != nil {
+		d.commitSenderAddress = crypto.PubkeyToAddress(key.PublicKey)
+	}
+	if key := cfg.L2Config.RelayerConfig.FinalizeSenderPrivateKey; key != nil {
+		d.finalizeSenderAddress = crypto.PubkeyToAddress(key.PublicKey)
+	}
+	return d, nil
+}
+
+// CheckDrift compares the on-chain rollup parameters to the configuration and alerts on every drift.
+func (d *L1ParamDriftDetector) CheckDrift() {
+	d.paramDriftCheckTotal.Inc()
+	drifts, err := d.detectDrifts()
+	if err != nil {
+		d.paramDriftFailureTotal.Inc()
+		log.Error("failed to check on-chain rollup parameter drift", "err", err)
+		return
+	}
+
+	drifted := make(map[string]bool)
+	for _, drift := range drifts {
+		drifted[drift.Param] = true
+		log.Error("on-chain rollup parameter drifted from configuration", "param", drift.Param, "on-chain", drift.OnChain, "expected", drift.Expected)
+	}
+	for _, param := range []string{"max_tx_num_per_chunk", "max_chunk_num_per_batch", "verifier", "message_queue", "scroll_chain", "sequencer", "prover"} {
+		if drifted[param] {
+			d.paramDrift.WithLabelValues(param).Set(1)
+		} else {
+			d.paramDrift.WithLabelValues(param).Set(0)
+		}
+	}
+}
+
+func (d *L1ParamDriftDetector) detectDrifts() ([]*L1ParamDrift, error) {
+	var drifts []*L1ParamDrift
+
+	var maxNumTxInChunk *big.Int
+	if err := d.call(d.scrollChainAddress, &maxNumTxInChunk, "maxNumTxInChunk"); err != nil {
+		return nil, err
+	}
+	// a chunk with more transactions than the on-chain limit reverts the commit transaction.
+	if !maxNumTxInChunk.IsUint64() || d.maxTxNumPerChunk > maxNumTxInChunk.Uint64() {
+		drifts = append(drifts, &L1ParamDrift{Param: "max_tx_num_per_chunk", OnChain: maxNumTxInChunk.String(), Expected: fmt.Sprintf("<= %d", d.maxTxNumPerChunk)})
+	}
+
+	// the number of chunks per batch is bounded by the blob layout verified on-chain.
+	if d.maxChunkNumPerBatch > uint64(codecv1.MaxNumChunks) {
+		drifts = append(drifts, &L1ParamDrift{Param: "max_chunk_num_per_batch", OnChain: fmt.Sprintf("%d", codecv1.MaxNumChunks), Expected: fmt.Sprintf("<= %d", d.maxChunkNumPerBatch)})
+	}
+
+	var messageQueue common.Address
+	if err := d.call(d.scrollChainAddress, &messageQueue, "messageQueue"); err != nil {
+		return nil, err
+	}
+	if messageQueue != d.l1MessageQueueAddress {
+		drifts = append(drifts, &L1ParamDrift{Param: "message_queue", OnChain: messageQueue.Hex(), Expected: d.l1MessageQueueAddress.Hex()})
+	}
+
+	var scrollChain common.Address
+	if err := d.call(d.l1MessageQueueAddress, &scrollChain, "scrollChain"); err != nil {
+		return nil, err
+	}
+	if scrollChain != d.scrollChainAddress {
+		drifts = append(drifts, &L1ParamDrift{Param: "scroll_chain", OnChain: scrollChain.Hex(), Expected: d.scrollChainAddress.Hex()})
+	}
+
+	var maxGasLimit *big.Int
+	if err := d.call(d.l1MessageQueueAddress, &maxGasLimit, "maxGasLimit"); err != nil {
+		return nil, err
+	}
+	d.l1MessageQueueMaxGas.Set(float64(maxGasLimit.Uint64()))
+
+	if d.verifierAddress != (common.Address{}) {
+		var verifier common.Address
+		if err := d.call(d.scrollChainAddress, &verifier, "verifier"); err != nil {
+			return nil, err
+		}
+		if verifier != d.verifierAddress {
+			drifts = append(drifts, &L1ParamDrift{Param: "verifier", OnChain: verifier.Hex(), Expected: d.verifierAddress.Hex()})
+		}
+	}
+
+	if d.commitSenderAddress != (common.Address{}) {
+		var isSequencer bool
+		if err := d.call(d.scrollChainAddress, &isSequencer, "isSequencer", d.commitSenderAddress); err != nil {
+			return nil, err
+		}
+		if !isSequencer {
+			drifts = append(drifts, &L1ParamDrift{Param: "sequencer", OnChain: "false", Expected: fmt.Sprintf("commit sender %s is sequencer", d.commitSenderAddress.Hex())})
+		}
+	}
+
+	if d.finalizeSenderAddress != (common.Address{}) {
+		var isProver bool
+		if err := d.call(d.scrollChainAddress, &isProver, "isProver", d.finalizeSenderAddress); err != nil {
+			return nil, err
+		}
+		if !isProver {
+			drifts = append(drifts, &L1ParamDrift{Param: "prover", OnChain: "false", Expected: fmt.Sprintf("finalize sender %s is prover", d.finalizeSenderAddress.Hex())})
+		}
+	}
+
+	return drifts, nil
+}
+
+func (d *L1ParamDriftDetector) call(contract common.Address, result interface{}, method string, args ...interface{}) error {
+	input, err := d.abi.Pack(method, args...)
+	if err != nil {
+		return fmt.Errorf("failed to pack %s: %w", method, err)
+	}
+	output, err := d.client.CallContract(d.ctx, ethereum.CallMsg{To: &contract, Data: input}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to call %s of %s: %w", method, contract.Hex(), err)
+	}
+	if err := d.abi.UnpackIntoInterface(result, method, output); err != nil {
+		return fmt.Errorf("failed to unpack %s of %s: %w", method, contract.Hex(), err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package watcher
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/accounts/abi"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+// mockL1ParamsCaller serves the on-chain rollup parameters from memory.
+type mockL1ParamsCaller struct {
+	abi     abi.ABI
+	outputs map[string][]interface{}
+}
+
+func (c *mockL1ParamsCaller) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	method, err := c.abi.MethodById(msg.Data[:4])
+	if err != nil {
+		return nil, err
+	}
+	return method.Outputs.Pack(c.outputs[method.Name]...)
+}
+
+func TestL1ParamDriftDetector(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(l1ParamsABI))
+	assert.NoError(t, err)
+
+	commitKey, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	scrollChain := common.HexToAddress("0x01")
+	messageQueue := common.HexToAddress("0x02")
+	verifier := common.HexToAddress("0x03")
+
+	cfg := &config.Config{
+		L1Config: &config.L1Config{
+			L1MessageQueueAddress: messageQueue,
+			ParamDriftConfig:      &config.ParamDriftConfig{VerifierAddress: verifier},
+		},
+		L2Config: &config.L2Config{
+			RelayerConfig:       &config.RelayerConfig{RollupContractAddress: scrollChain, CommitSenderPrivateKey: commitKey},
+			ChunkProposerConfig: &config.ChunkProposerConfig{MaxTxNumPerChunk: 100},
+			BatchProposerConfig: &config.BatchProposerConfig{MaxChunkNumPerBatch: 15},
+		},
+	}
+
+	caller := &mockL1ParamsCaller{abi: parsed, outputs: map[string][]interface{}{
+		"maxNumTxInChunk": {big.NewInt(100)},
+		"messageQueue":    {messageQueue},
+		"scrollChain":     {scrollChain},
+		"maxGasLimit":     {big.NewInt(10000000)},
+		"verifier":        {verifier},
+		"isSequencer":     {true},
+	}}
+	d, err := NewL1ParamDriftDetector(context.Background(), caller, cfg, nil)
+	assert.NoError(t, err)
+
+	drifts, err := d.detectDrifts()
+	assert.NoError(t, err)
+	assert.Empty(t, drifts)
+
+	caller.outputs["maxNumTxInChunk"] = []interface{}{big.NewInt(50)}
+	caller.outputs["verifier"] = []interface{}{common.HexToAddress("0x04")}
+	caller.outputs["isSequencer"] = []interface{}{false}
+	drifts, err = d.detectDrifts()
+	assert.NoError(t, err)
+	assert.Len(t, drifts, 3)
+	assert.Equal(t, "max_tx_num_per_chunk", drifts[0].Param)
+	assert.Equal(t, "50", drifts[0].OnChain)
+	assert.Equal(t, "verifier", drifts[1].Param)
+	assert.Equal(t, "sequencer", drifts[2].Param)
+}
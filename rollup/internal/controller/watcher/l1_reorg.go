@@ -0,0 +1,106 @@
+package watcher
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+)
+
+// maxImportedL1Blocks is the number of imported ranges tracked for the reorg detection,
+// at least contractEventsBlocksFetchLimit times deeper than any realistic reorg.
+const maxImportedL1Blocks = 128
+
+// importedL1Block is the tip of an imported event log range.
+type importedL1Block struct {
+	number uint64
+	hash   common.Hash
+}
+
+// recordImportedBlock tracks the tip of an imported event log range, the tracked blocks are kept ordered by number
+// since the event types with different confirmations are imported up to different heights.
+func (w *L1WatcherClient) recordImportedBlock(header *gethTypes.Header) {
+	imported := importedL1Block{number: header.Number.Uint64(), hash: header.Hash()}
+	i := sort.Search(len(w.importedBlocks), func(i int) bool { return w.importedBlocks[i].number >= imported.number })
+	if i < len(w.importedBlocks) && w.importedBlocks[i].number == imported.number {
+		w.importedBlocks[i] = imported
+	} else {
+		w.importedBlocks = append(w.importedBlocks, importedL1Block{})
+		copy(w.importedBlocks[i+1:], w.importedBlocks[i:])
+		w.importedBlocks[i] = imported
+	}
+	if len(w.importedBlocks) > maxImportedL1Blocks {
+		w.importedBlocks = w.importedBlocks[len(w.importedBlocks)-maxImportedL1Blocks:]
+	}
+}
+
+// checkL1Reorg compares the tracked imported blocks with the canonical chain. The imported blocks are confirmed,
+// so any mismatch is a reorg deeper than the confirmations: the data imported from the fork height on is rolled
+// back and the watcher rewinds, so that the canonical chain is re-imported by the next fetch.
+// Only the blocks imported since the start are tracked, a reorg happening while the watcher is down is not detected.
+func (w *L1WatcherClient) checkL1Reorg() error {
+	if len(w.importedBlocks) == 0 {
+		return nil
+	}
+
+	// Find the latest tracked block still canonical, the fork happened after it.
+	forkHeight := w.importedBlocks[0].number
+	kept := 0
+	for i := len(w.importedBlocks) - 1; i >= 0; i-- {
+		imported := w.importedBlocks[i]
+		header, err := w.client.HeaderByNumber(w.ctx, new(big.Int).SetUint64(imported.number))
+		if err != nil {
+			return err
+		}
+		if header.Hash() == imported.hash {
+			forkHeight = imported.number + 1
+			kept = i + 1
+			break
+		}
+	}
+	if kept == len(w.importedBlocks) {
+		return nil
+	}
+	if kept == 0 {
+		log.Error("L1 reorg deeper than the tracked imported blocks, rolling back from the oldest one", "height", forkHeight)
+	}
+
+	depth := w.importedBlocks[len(w.importedBlocks)-1].number + 1 - forkHeight
+	log.Warn("L1 reorg detected, rolling back the imported data", "forkHeight", forkHeight, "importedHeight", w.importedBlocks[len(w.importedBlocks)-1].number, "depth", depth)
+
+	var deletedMessages, revertedBatches int64
+	err := w.db.Transaction(func(dbTX *gorm.DB) error {
+		var err error
+		if deletedMessages, err = w.l1MessageOrm.DeleteL1MessagesGeHeight(w.ctx, forkHeight, dbTX); err != nil {
+			return err
+		}
+		if err = w.l1BlockOrm.DeleteL1BlocksGeHeight(w.ctx, forkHeight, dbTX); err != nil {
+			return err
+		}
+		if revertedBatches, err = w.batchOrm.RollbackL1RollupStatus(w.ctx, forkHeight, dbTX); err != nil {
+			return err
+		}
+		return w.checkpointOrm.RewindL1WatcherCheckpoints(w.ctx, forkHeight-1, dbTX)
+	})
+	if err != nil {
+		return err
+	}
+
+	w.importedBlocks = w.importedBlocks[:kept]
+	for _, eventType := range l1EventTypes {
+		if w.processedEventHeights[eventType] >= forkHeight {
+			w.setProcessedHeight([]l1EventType{eventType}, forkHeight-1)
+		}
+	}
+	if w.processedBlockHeight >= forkHeight {
+		w.processedBlockHeight = forkHeight - 1
+	}
+	w.metrics.l1WatcherReorgTotal.Inc()
+	w.metrics.l1WatcherReorgDepth.Set(float64(depth))
+	w.metrics.l1WatcherFetchContractEventProcessedBlockHeight.Set(float64(w.processedMsgHeight))
+	log.Info("L1 reorg rolled back", "forkHeight", forkHeight, "deletedMessages", deletedMessages, "revertedBatches", revertedBatches)
+	return nil
+}
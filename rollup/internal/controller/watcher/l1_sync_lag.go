@@ -0,0 +1,64 @@
+package watcher
+
+import (
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+// SetSyncLagAlertConfig sets the alert thresholds of the sync lag of the event filters.
+func (w *L1WatcherClient) SetSyncLagAlertConfig(cfg *config.SyncLagAlertConfig) {
+	w.syncLagThresholds = make(map[l1EventType]uint64)
+	w.syncLagAlerts = make(map[l1EventType]bool)
+	if cfg == nil {
+		return
+	}
+	for _, eventType := range l1EventTypes {
+		if cfg.MaxLagBlocks != 0 {
+			w.syncLagThresholds[eventType] = cfg.MaxLagBlocks
+		}
+	}
+	for eventFilter, maxLag := range cfg.EventFilterMaxLagBlocks {
+		known := false
+		for _, eventType := range l1EventTypes {
+			if eventType.String() == eventFilter {
+				w.syncLagThresholds[eventType] = maxLag
+				known = true
+			}
+		}
+		if !known {
+			log.Warn("Ignoring the sync lag alert threshold of an unknown event filter", "eventFilter", eventFilter)
+		}
+	}
+}
+
+// updateSyncLag exports the gap between the L1 head and the processed height of every event filter,
+// and raises or clears the alerts of the event filters with a threshold.
+func (w *L1WatcherClient) updateSyncLag(headHeight uint64) {
+	for _, eventType := range l1EventTypes {
+		var lag uint64
+		if processed := w.processedEventHeights[eventType]; headHeight > processed {
+			lag = headHeight - processed
+		}
+		w.metrics.l1WatcherSyncLagBlocks.WithLabelValues(eventType.String()).Set(float64(lag))
+
+		threshold, ok := w.syncLagThresholds[eventType]
+		if !ok || threshold == 0 {
+			continue
+		}
+		alert := lag > threshold
+		if alert {
+			w.metrics.l1WatcherSyncLagAlert.WithLabelValues(eventType.String()).Set(1)
+		} else {
+			w.metrics.l1WatcherSyncLagAlert.WithLabelValues(eventType.String()).Set(0)
+		}
+		if alert != w.syncLagAlerts[eventType] {
+			if alert {
+				log.Error("L1 watcher is falling behind the L1 head", "eventFilter", eventType.String(), "lagBlocks", lag, "maxLagBlocks", threshold, "headHeight", headHeight)
+			} else {
+				log.Info("L1 watcher caught up with the L1 head", "eventFilter", eventType.String(), "lagBlocks", lag, "maxLagBlocks", threshold)
+			}
+			w.syncLagAlerts[eventType] = alert
+		}
+	}
+}
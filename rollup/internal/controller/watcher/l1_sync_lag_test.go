@@ -0,0 +1,41 @@
+package watcher
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+func TestL1WatcherSyncLag(t *testing.T) {
+	w := &L1WatcherClient{
+		processedEventHeights: map[l1EventType]uint64{
+			l1EventL1Message:     100,
+			l1EventCommitBatch:   80,
+			l1EventFinalizeBatch: 120,
+		},
+		metrics: initL1WatcherMetrics(prometheus.NewRegistry()),
+	}
+	w.SetSyncLagAlertConfig(&config.SyncLagAlertConfig{
+		MaxLagBlocks:            30,
+		EventFilterMaxLagBlocks: map[string]uint64{"finalize_batch": 5, "unknown": 1},
+	})
+
+	w.updateSyncLag(125)
+	assert.Equal(t, float64(25), testutil.ToFloat64(w.metrics.l1WatcherSyncLagBlocks.WithLabelValues("l1_message")))
+	assert.Equal(t, float64(45), testutil.ToFloat64(w.metrics.l1WatcherSyncLagBlocks.WithLabelValues("commit_batch")))
+	assert.Equal(t, float64(5), testutil.ToFloat64(w.metrics.l1WatcherSyncLagBlocks.WithLabelValues("finalize_batch")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(w.metrics.l1WatcherSyncLagAlert.WithLabelValues("l1_message")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(w.metrics.l1WatcherSyncLagAlert.WithLabelValues("commit_batch")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(w.metrics.l1WatcherSyncLagAlert.WithLabelValues("finalize_batch")))
+	assert.Equal(t, map[l1EventType]bool{l1EventCommitBatch: true}, w.syncLagAlerts)
+
+	// The alert is cleared once the watcher catches up.
+	w.processedEventHeights[l1EventCommitBatch] = 125
+	w.updateSyncLag(125)
+	assert.Equal(t, float64(0), testutil.ToFloat64(w.metrics.l1WatcherSyncLagAlert.WithLabelValues("commit_batch")))
+	assert.False(t, w.syncLagAlerts[l1EventCommitBatch])
+}
@@ -0,0 +1,188 @@
+package watcher
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	geth "github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rpc"
+
+	bridgeAbi "scroll-tech/rollup/abi"
+	"scroll-tech/rollup/internal/utils"
+)
+
+// systemConfigBlocksFetchLimit is the block range of the log queries of the SystemConfigWatcher,
+// the configuration updates are rare so that wide ranges are cheap.
+const systemConfigBlocksFetchLimit = uint64(1000)
+
+// systemConfigL1Client is the part of the l1 client used by the SystemConfigWatcher.
+type systemConfigL1Client interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*gethTypes.Header, error)
+	FilterLogs(ctx context.Context, q geth.FilterQuery) ([]gethTypes.Log, error)
+}
+
+// SystemConfigHandlers are the callbacks applying the on-chain configuration updates to the running components,
+// the updates without a handler are only exported as metrics.
+type SystemConfigHandlers struct {
+	// OnSequencerUpdate is called when an account is added to or removed from the sequencers of the ScrollChain contract.
+	OnSequencerUpdate func(account common.Address, allowed bool)
+	// OnMaxNumTxInChunkUpdate is called when the max number of transactions in a chunk of the ScrollChain contract changes.
+	OnMaxNumTxInChunkUpdate func(maxNumTxInChunk uint64)
+	// OnL2BaseFeeUpdate is called when the L2 base fee of the L2GasPriceOracle contract changes.
+	OnL2BaseFeeUpdate func(l2BaseFee uint64)
+}
+
+// SystemConfigAddresses are the addresses of the L1 contracts whose configuration updates are watched.
+type SystemConfigAddresses struct {
+	ScrollChain    common.Address
+	L1MessageQueue common.Address
+	L2GasOracle    common.Address
+}
+
+// SystemConfigWatcher follows the configuration update events of the L1 rollup contracts (sequencer allowlist,
+// max transactions per chunk, max L1 message gas limit, L2 base fee) and applies them to the running components,
+// instead of requiring configuration file edits. The updates are replayed from the start height at startup.
+type SystemConfigWatcher struct {
+	ctx           context.Context
+	client        systemConfigL1Client
+	confirmations rpc.BlockNumber
+	addresses     SystemConfigAddresses
+	handlers      SystemConfigHandlers
+
+	// The height of the block that the watcher has retrieved the configuration updates of
+	processedHeight uint64
+
+	systemConfigUpdateTotal     *prometheus.CounterVec
+	systemConfigMaxNumTxInChunk prometheus.Gauge
+	systemConfigMaxGasLimit     prometheus.Gauge
+	systemConfigL2BaseFee       prometheus.Gauge
+}
+
+// NewSystemConfigWatcher creates a new SystemConfigWatcher, replaying the configuration updates from startHeight on.
+func NewSystemConfigWatcher(ctx context.Context, client systemConfigL1Client, startHeight uint64, confirmations rpc.BlockNumber, addresses SystemConfigAddresses, handlers SystemConfigHandlers, reg prometheus.Registerer) *SystemConfigWatcher {
+	processedHeight := uint64(0)
+	if startHeight > 0 {
+		processedHeight = startHeight - 1
+	}
+	return &SystemConfigWatcher{
+		ctx:             ctx,
+		client:          client,
+		confirmations:   confirmations,
+		addresses:       addresses,
+		handlers:        handlers,
+		processedHeight: processedHeight,
+
+		systemConfigUpdateTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "rollup_l1_system_config_update_total",
+			Help: "The total number of on-chain configuration updates applied, by event.",
+		}, []string{"event"}),
+		systemConfigMaxNumTxInChunk: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_l1_system_config_max_num_tx_in_chunk",
+			Help: "The max number of transactions in a chunk set in the ScrollChain contract.",
+		}),
+		systemConfigMaxGasLimit: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_l1_system_config_max_gas_limit",
+			Help: "The max gas limit of the L1 messages set in the L1MessageQueue contract.",
+		}),
+		systemConfigL2BaseFee: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_l1_system_config_l2_base_fee",
+			Help: "The L2 base fee set in the L2GasPriceOracle contract.",
+		}),
+	}
+}
+
+// FetchUpdates applies the configuration updates of the confirmed blocks since the last call, in order.
+func (w *SystemConfigWatcher) FetchUpdates() {
+	blockHeight, err := utils.GetLatestConfirmedBlockNumber(w.ctx, w.client, w.confirmations)
+	if err != nil {
+		log.Error("failed to get block number", "err", err)
+		return
+	}
+
+	var addresses []common.Address
+	for _, address := range []common.Address{w.addresses.ScrollChain, w.addresses.L1MessageQueue, w.addresses.L2GasOracle} {
+		if address != (common.Address{}) {
+			addresses = append(addresses, address)
+		}
+	}
+	topics := []common.Hash{
+		bridgeAbi.L1UpdateSequencerEventSignature,
+		bridgeAbi.L1UpdateMaxNumTxInChunkEventSignature,
+		bridgeAbi.L1UpdateMaxGasLimitEventSignature,
+		bridgeAbi.L1L2BaseFeeUpdatedEventSignature,
+	}
+
+	for from := w.processedHeight + 1; from <= blockHeight; from += systemConfigBlocksFetchLimit {
+		to := min(from+systemConfigBlocksFetchLimit-1, blockHeight)
+		query := geth.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(from), // inclusive
+			ToBlock:   new(big.Int).SetUint64(to),   // inclusive
+			Addresses: addresses,
+			Topics:    [][]common.Hash{topics},
+		}
+		logs, err := w.client.FilterLogs(w.ctx, query)
+		if err != nil {
+			log.Warn("Failed to get system config update logs", "fromBlock", from, "toBlock", to, "err", err)
+			return
+		}
+		for _, vLog := range logs {
+			if err := w.applyUpdate(vLog); err != nil {
+				log.Error("Failed to apply system config update", "txHash", vLog.TxHash, "err", err)
+				return
+			}
+		}
+		w.processedHeight = to
+	}
+}
+
+func (w *SystemConfigWatcher) applyUpdate(vLog gethTypes.Log) error {
+	switch {
+	case vLog.Address == w.addresses.ScrollChain && vLog.Topics[0] == bridgeAbi.L1UpdateSequencerEventSignature:
+		event := bridgeAbi.L1UpdateSequencerEvent{}
+		if err := utils.UnpackLog(bridgeAbi.ScrollChainABI, &event, "UpdateSequencer", vLog); err != nil {
+			return err
+		}
+		log.Info("Sequencer allowlist updated on L1", "account", event.Account, "allowed", event.Status, "l1BlockNumber", vLog.BlockNumber)
+		if w.handlers.OnSequencerUpdate != nil {
+			w.handlers.OnSequencerUpdate(event.Account, event.Status)
+		}
+		w.systemConfigUpdateTotal.WithLabelValues("update_sequencer").Inc()
+	case vLog.Address == w.addresses.ScrollChain && vLog.Topics[0] == bridgeAbi.L1UpdateMaxNumTxInChunkEventSignature:
+		event := bridgeAbi.L1UpdateMaxNumTxInChunkEvent{}
+		if err := utils.UnpackLog(bridgeAbi.ScrollChainABI, &event, "UpdateMaxNumTxInChunk", vLog); err != nil {
+			return err
+		}
+		log.Info("Max number of transactions in chunk updated on L1", "old", event.OldMaxNumTxInChunk, "new", event.NewMaxNumTxInChunk, "l1BlockNumber", vLog.BlockNumber)
+		if w.handlers.OnMaxNumTxInChunkUpdate != nil {
+			w.handlers.OnMaxNumTxInChunkUpdate(event.NewMaxNumTxInChunk.Uint64())
+		}
+		w.systemConfigMaxNumTxInChunk.Set(float64(event.NewMaxNumTxInChunk.Uint64()))
+		w.systemConfigUpdateTotal.WithLabelValues("update_max_num_tx_in_chunk").Inc()
+	case vLog.Address == w.addresses.L1MessageQueue && vLog.Topics[0] == bridgeAbi.L1UpdateMaxGasLimitEventSignature:
+		event := bridgeAbi.L1UpdateMaxGasLimitEvent{}
+		if err := utils.UnpackLog(bridgeAbi.L1MessageQueueABI, &event, "UpdateMaxGasLimit", vLog); err != nil {
+			return err
+		}
+		log.Info("Max gas limit of L1 messages updated on L1", "old", event.OldMaxGasLimit, "new", event.NewMaxGasLimit, "l1BlockNumber", vLog.BlockNumber)
+		w.systemConfigMaxGasLimit.Set(float64(event.NewMaxGasLimit.Uint64()))
+		w.systemConfigUpdateTotal.WithLabelValues("update_max_gas_limit").Inc()
+	case vLog.Address == w.addresses.L2GasOracle && vLog.Topics[0] == bridgeAbi.L1L2BaseFeeUpdatedEventSignature:
+		event := bridgeAbi.L1L2BaseFeeUpdatedEvent{}
+		if err := utils.UnpackLog(bridgeAbi.L2GasPriceOracleABI, &event, "L2BaseFeeUpdated", vLog); err != nil {
+			return err
+		}
+		log.Debug("L2 base fee updated on L1", "old", event.OldL2BaseFee, "new", event.NewL2BaseFee, "l1BlockNumber", vLog.BlockNumber)
+		if w.handlers.OnL2BaseFeeUpdate != nil {
+			w.handlers.OnL2BaseFeeUpdate(event.NewL2BaseFee.Uint64())
+		}
+		w.systemConfigL2BaseFee.Set(float64(event.NewL2BaseFee.Uint64()))
+		w.systemConfigUpdateTotal.WithLabelValues("l2_base_fee_updated").Inc()
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package watcher
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	geth "github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/accounts/abi"
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+
+	bridgeAbi "scroll-tech/rollup/abi"
+)
+
+type mockSystemConfigL1Client struct {
+	blockNumber uint64
+	logs        []gethTypes.Log
+	queries     []geth.FilterQuery
+}
+
+func (c *mockSystemConfigL1Client) BlockNumber(context.Context) (uint64, error) {
+	return c.blockNumber, nil
+}
+
+func (c *mockSystemConfigL1Client) HeaderByNumber(context.Context, *big.Int) (*gethTypes.Header, error) {
+	return &gethTypes.Header{Number: new(big.Int).SetUint64(c.blockNumber)}, nil
+}
+
+func (c *mockSystemConfigL1Client) FilterLogs(_ context.Context, q geth.FilterQuery) ([]gethTypes.Log, error) {
+	c.queries = append(c.queries, q)
+	var logs []gethTypes.Log
+	for _, vLog := range c.logs {
+		if vLog.BlockNumber >= q.FromBlock.Uint64() && vLog.BlockNumber <= q.ToBlock.Uint64() {
+			logs = append(logs, vLog)
+		}
+	}
+	return logs, nil
+}
+
+func systemConfigLog(t *testing.T, contractABI *abi.ABI, event string, address common.Address, blockNumber uint64, topics []common.Hash, args ...interface{}) gethTypes.Log {
+	data, err := contractABI.Events[event].Inputs.NonIndexed().Pack(args...)
+	assert.NoError(t, err)
+	return gethTypes.Log{
+		Address:     address,
+		Topics:      append([]common.Hash{contractABI.Events[event].ID}, topics...),
+		Data:        data,
+		BlockNumber: blockNumber,
+	}
+}
+
+func TestSystemConfigWatcher(t *testing.T) {
+	addresses := SystemConfigAddresses{
+		ScrollChain:    common.HexToAddress("0x01"),
+		L1MessageQueue: common.HexToAddress("0x02"),
+		L2GasOracle:    common.HexToAddress("0x03"),
+	}
+	sequencer := common.HexToAddress("0x10")
+	client := &mockSystemConfigL1Client{
+		blockNumber: 2500,
+		logs: []gethTypes.Log{
+			systemConfigLog(t, bridgeAbi.ScrollChainABI, "UpdateSequencer", addresses.ScrollChain, 100, []common.Hash{common.BytesToHash(sequencer.Bytes())}, true),
+			systemConfigLog(t, bridgeAbi.ScrollChainABI, "UpdateMaxNumTxInChunk", addresses.ScrollChain, 1200, nil, big.NewInt(100), big.NewInt(50)),
+			systemConfigLog(t, bridgeAbi.L1MessageQueueABI, "UpdateMaxGasLimit", addresses.L1MessageQueue, 1300, nil, big.NewInt(1000000), big.NewInt(2000000)),
+			systemConfigLog(t, bridgeAbi.L2GasPriceOracleABI, "L2BaseFeeUpdated", addresses.L2GasOracle, 2000, nil, big.NewInt(1), big.NewInt(7)),
+			systemConfigLog(t, bridgeAbi.ScrollChainABI, "UpdateSequencer", addresses.ScrollChain, 2400, []common.Hash{common.BytesToHash(sequencer.Bytes())}, false),
+		},
+	}
+
+	sequencers := make(map[common.Address]bool)
+	var maxNumTxInChunk, l2BaseFee uint64
+	handlers := SystemConfigHandlers{
+		OnSequencerUpdate:       func(account common.Address, allowed bool) { sequencers[account] = allowed },
+		OnMaxNumTxInChunkUpdate: func(n uint64) { maxNumTxInChunk = n },
+		OnL2BaseFeeUpdate:       func(fee uint64) { l2BaseFee = fee },
+	}
+	w := NewSystemConfigWatcher(context.Background(), client, 1, rpc.LatestBlockNumber, addresses, handlers, prometheus.NewRegistry())
+
+	w.FetchUpdates()
+	assert.Len(t, client.queries, 3)
+	assert.Equal(t, uint64(2500), w.processedHeight)
+	assert.Equal(t, map[common.Address]bool{sequencer: false}, sequencers)
+	assert.Equal(t, uint64(50), maxNumTxInChunk)
+	assert.Equal(t, uint64(7), l2BaseFee)
+
+	// Nothing new to fetch.
+	w.FetchUpdates()
+	assert.Len(t, client.queries, 3)
+}
@@ -6,8 +6,6 @@ import (
 	"math/big"
 
 	"github.com/prometheus/client_golang/prometheus"
-	geth "github.com/scroll-tech/go-ethereum"
-	"github.com/scroll-tech/go-ethereum/accounts/abi"
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/consensus/misc"
 	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
@@ -25,38 +23,67 @@ import (
 )
 
 type rollupEvent struct {
-	batchHash common.Hash
-	txHash    common.Hash
-	status    types.RollupStatus
+	batchHash     common.Hash
+	txHash        common.Hash
+	l1BlockNumber uint64
+	// status is the rollup status of the batch after the event, RollupPending for a revert.
+	status types.RollupStatus
 }
 
 // L1WatcherClient will listen for smart contract events from Eth L1.
 type L1WatcherClient struct {
-	ctx          context.Context
-	client       *ethclient.Client
-	l1MessageOrm *orm.L1Message
-	l1BlockOrm   *orm.L1Block
-	batchOrm     *orm.Batch
+	ctx           context.Context
+	client        *ethclient.Client
+	db            *gorm.DB
+	l1MessageOrm  *orm.L1Message
+	l1BlockOrm    *orm.L1Block
+	batchOrm      *orm.Batch
+	checkpointOrm *orm.L1WatcherCheckpoint
 
 	// The number of new blocks to wait for a block to be confirmed
 	confirmations rpc.BlockNumber
+	// The confirmations overriding confirmations for some event types
+	eventConfirmations map[l1EventType]rpc.BlockNumber
 
 	messageQueueAddress common.Address
-	messageQueueABI     *abi.ABI
+	scrollChainAddress  common.Address
+	// The deployed versions of the rollup contracts, the contracts above are watched at all heights if none
+	contractVersions []*l1ContractVersion
 
-	scrollChainAddress common.Address
-	scrollChainABI     *abi.ABI
+	// The L2 alias of L1ScrollMessenger, queued transactions from other senders are enforced transactions.
+	// Zero address means enforced transaction tracking is disabled.
+	l1MessengerAliasAddress common.Address
 
-	// The height of the block that the watcher has retrieved event logs
+	// The height of the block that the watcher has retrieved event logs of all types
 	processedMsgHeight uint64
+	// The height of the block that the watcher has retrieved event logs, per event type
+	processedEventHeights map[l1EventType]uint64
 	// The height of the block that the watcher has retrieved header rlp
 	processedBlockHeight uint64
+	// The hashes of the recently imported event log ranges, used to detect L1 reorgs deeper than the confirmations
+	importedBlocks []importedL1Block
+	// The adaptive block range of the log queries
+	logFetchWindow *logFetchWindow
+	// The sync lag alert thresholds and the raised alerts, per event type
+	syncLagThresholds map[l1EventType]uint64
+	syncLagAlerts     map[l1EventType]bool
+	// The max delay between queueing and including an enforced transaction, no deadline if zero
+	maxInclusionDelaySec uint64
+	// The height to sync events from, the processed heights never go below it
+	startHeight uint64
+	// The leader election of the hot-standby mode, the events are imported unconditionally if nil
+	standby *L1WatcherStandby
+	// Whether the watcher followed another instance since its last import
+	following bool
+	// The beacon node of the safe and finalized heads, and the event types using them, the l1 node's if nil
+	beacon           *utils.BeaconClient
+	beaconEventTypes map[l1EventType]bool
 
 	metrics *l1WatcherMetrics
 }
 
 // NewL1WatcherClient returns a new instance of L1WatcherClient.
-func NewL1WatcherClient(ctx context.Context, client *ethclient.Client, startHeight uint64, confirmations rpc.BlockNumber, messageQueueAddress, scrollChainAddress common.Address, db *gorm.DB, reg prometheus.Registerer) *L1WatcherClient {
+func NewL1WatcherClient(ctx context.Context, client *ethclient.Client, startHeight uint64, confirmations rpc.BlockNumber, messageQueueAddress, scrollChainAddress, l1MessengerAddress common.Address, db *gorm.DB, reg prometheus.Registerer) *L1WatcherClient {
 	l1MessageOrm := orm.NewL1Message(db)
 	savedHeight, err := l1MessageOrm.GetLayer1LatestWatchedHeight()
 	if err != nil {
@@ -77,24 +104,40 @@ func NewL1WatcherClient(ctx context.Context, client *ethclient.Client, startHeig
 		savedL1BlockHeight = startHeight
 	}
 
-	return &L1WatcherClient{
+	var l1MessengerAliasAddress common.Address
+	if l1MessengerAddress != (common.Address{}) {
+		l1MessengerAliasAddress = utils.ApplyL1ToL2Alias(l1MessengerAddress)
+	}
+
+	checkpointOrm := orm.NewL1WatcherCheckpoint(db)
+	processedEventHeights, importedBlocks := loadL1WatcherCheckpoints(ctx, checkpointOrm, uint64(savedHeight), startHeight)
+
+	w := &L1WatcherClient{
 		ctx:           ctx,
 		client:        client,
+		db:            db,
 		l1MessageOrm:  l1MessageOrm,
 		l1BlockOrm:    l1BlockOrm,
 		batchOrm:      orm.NewBatch(db),
+		checkpointOrm: checkpointOrm,
 		confirmations: confirmations,
 
 		messageQueueAddress: messageQueueAddress,
-		messageQueueABI:     bridgeAbi.L1MessageQueueABI,
-
-		scrollChainAddress: scrollChainAddress,
-		scrollChainABI:     bridgeAbi.ScrollChainABI,
-
-		processedMsgHeight:   uint64(savedHeight),
-		processedBlockHeight: savedL1BlockHeight,
-		metrics:              initL1WatcherMetrics(reg),
+		scrollChainAddress:  scrollChainAddress,
+
+		l1MessengerAliasAddress: l1MessengerAliasAddress,
+
+		startHeight:           startHeight,
+		processedEventHeights: processedEventHeights,
+		importedBlocks:        importedBlocks,
+		processedBlockHeight:  savedL1BlockHeight,
+		logFetchWindow:        newLogFetchWindow(nil, nil),
+		syncLagThresholds:     make(map[l1EventType]uint64),
+		syncLagAlerts:         make(map[l1EventType]bool),
+		metrics:               initL1WatcherMetrics(reg),
 	}
+	w.processedMsgHeight = w.groupProcessedHeight(l1EventTypes)
+	return w
 }
 
 // ProcessedBlockHeight get processedBlockHeight
@@ -166,46 +209,78 @@ func (w *L1WatcherClient) FetchContractEvent() error {
 	defer func() {
 		log.Info("l1 watcher fetchContractEvent", "w.processedMsgHeight", w.processedMsgHeight)
 	}()
-	blockHeight, err := utils.GetLatestConfirmedBlockNumber(w.ctx, w.client, w.confirmations)
+	// The sync lag is measured from the head before the fetch, also when the fetch fails.
+	headHeight, err := w.client.BlockNumber(w.ctx)
+	if err != nil {
+		log.Warn("failed to get l1 head height", "err", err)
+	} else {
+		defer w.updateSyncLag(headHeight)
+	}
+
+	if w.isStandby() {
+		w.following = true
+		return w.followLeader()
+	}
+	if w.following {
+		w.resumeFromLeader()
+	}
+
+	if err = w.checkL1Reorg(); err != nil {
+		log.Error("failed to check l1 reorg", "err", err)
+		return err
+	}
+
+	for _, group := range w.eventGroups() {
+		if err := w.fetchContractEvent(group); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchContractEvent pull the event logs of a group of event types up to their confirmed height and save in DB
+func (w *L1WatcherClient) fetchContractEvent(group *l1EventGroup) error {
+	blockHeight, err := w.confirmedHeight(group)
 	if err != nil {
 		log.Error("failed to get block number", "err", err)
 		return err
 	}
 
-	fromBlock := int64(w.processedMsgHeight) + 1
+	fromBlock := int64(w.groupProcessedHeight(group.eventTypes)) + 1
 	toBlock := int64(blockHeight)
+	if fromBlock > toBlock {
+		return nil
+	}
 
-	for from := fromBlock; from <= toBlock; from += contractEventsBlocksFetchLimit {
-		w.metrics.l1WatcherFetchContractEventTotal.Inc()
-		to := from + contractEventsBlocksFetchLimit - 1
-
-		if to > toBlock {
-			to = toBlock
-		}
+	// The tip header is fetched before the logs, so that a reorg racing with the import is detected on the next fetch.
+	tipHeader, err := w.client.HeaderByNumber(w.ctx, big.NewInt(toBlock))
+	if err != nil {
+		log.Error("failed to get l1 tip header", "height", toBlock, "err", err)
+		return err
+	}
 
-		// warning: uint int conversion...
-		query := geth.FilterQuery{
-			FromBlock: big.NewInt(from), // inclusive
-			ToBlock:   big.NewInt(to),   // inclusive
-			Addresses: []common.Address{
-				w.scrollChainAddress,
-				w.messageQueueAddress,
-			},
-			Topics: make([][]common.Hash, 1),
+	addresses, topics := w.filter(group.eventTypes)
+	var to int64
+	for from := fromBlock; from <= toBlock; from = to + 1 {
+		if w.isStandby() {
+			log.Warn("Stopping l1 events import, the leader lease expired", "fromBlock", from)
+			return nil
 		}
-		query.Topics[0] = make([]common.Hash, 3)
-		query.Topics[0][0] = bridgeAbi.L1QueueTransactionEventSignature
-		query.Topics[0][1] = bridgeAbi.L1CommitBatchEventSignature
-		query.Topics[0][2] = bridgeAbi.L1FinalizeBatchEventSignature
+		w.metrics.l1WatcherFetchContractEventTotal.Inc()
 
-		logs, err := w.client.FilterLogs(w.ctx, query)
+		var logs []gethTypes.Log
+		logs, to, err = w.filterLogs(from, toBlock, addresses, topics)
 		if err != nil {
 			log.Warn("Failed to get event logs", "err", err)
 			return err
 		}
+		logs = w.skipProcessedLogs(logs)
 		if len(logs) == 0 {
-			w.processedMsgHeight = uint64(to)
-			w.metrics.l1WatcherFetchContractEventProcessedBlockHeight.Set(float64(to))
+			if err = w.saveCheckpoints(group.eventTypes, uint64(to), tipHeader); err != nil {
+				return err
+			}
+			w.setProcessedHeight(group.eventTypes, uint64(to))
+			w.metrics.l1WatcherFetchContractEventProcessedBlockHeight.Set(float64(w.processedMsgHeight))
 			continue
 		}
 
@@ -216,11 +291,23 @@ func (w *L1WatcherClient) FetchContractEvent() error {
 			log.Error("Failed to parse emitted events log", "err", err)
 			return err
 		}
+		if err = w.setEnforcedTxTimes(sentMessageEvents); err != nil {
+			log.Error("Failed to set enqueue times of enforced transactions", "err", err)
+			return err
+		}
 		sentMessageCount := int64(len(sentMessageEvents))
 		rollupEventCount := int64(len(rollupEvents))
+		var enforcedTxCount int64
+		for _, msg := range sentMessageEvents {
+			if msg.IsEnforced {
+				enforcedTxCount++
+				log.Info("Received enforced transaction", "queueIndex", msg.QueueIndex, "sender", msg.Sender, "l1TxHash", msg.Layer1Hash, "height", msg.Height, "enqueueTime", msg.EnqueueTime, "inclusionDeadline", msg.InclusionDeadline)
+			}
+		}
 		w.metrics.l1WatcherFetchContractEventSentEventsTotal.Add(float64(sentMessageCount))
 		w.metrics.l1WatcherFetchContractEventRollupEventsTotal.Add(float64(rollupEventCount))
-		log.Info("L1 events types", "SentMessageCount", sentMessageCount, "RollupEventCount", rollupEventCount)
+		w.metrics.l1WatcherFetchContractEventEnforcedTxsTotal.Add(float64(enforcedTxCount))
+		log.Info("L1 events types", "SentMessageCount", sentMessageCount, "RollupEventCount", rollupEventCount, "EnforcedTxCount", enforcedTxCount)
 
 		// use rollup event to update rollup results db status
 		var batchHashes []string
@@ -237,20 +324,47 @@ func (w *L1WatcherClient) FetchContractEvent() error {
 			return nil
 		}
 
-		for index, event := range rollupEvents {
+		// the statuses are tracked across the events, a batch may be committed and reverted in the same range.
+		latestStatuses := make(map[string]types.RollupStatus, len(statuses))
+		for index, batchHash := range batchHashes {
+			latestStatuses[batchHash] = statuses[index]
+		}
+
+		for _, event := range rollupEvents {
 			batchHash := event.batchHash.String()
-			status := statuses[index]
+			status := latestStatuses[batchHash]
+			if event.status == types.RollupPending {
+				if err = w.revertBatch(batchHash, status); err != nil {
+					log.Error("Failed to revert batch", "batchHash", batchHash, "err", err)
+					return err
+				}
+				latestStatuses[batchHash] = types.RollupPending
+				continue
+			}
 			// only update when db status is before event status
 			if event.status > status {
 				if event.status == types.RollupFinalized {
-					err = w.batchOrm.UpdateFinalizeTxHashAndRollupStatus(w.ctx, batchHash, event.txHash.String(), event.status)
+					err = w.batchOrm.UpdateFinalizedBatch(w.ctx, batchHash, event.txHash.String(), event.l1BlockNumber)
 				} else if event.status == types.RollupCommitted {
-					err = w.batchOrm.UpdateCommitTxHashAndRollupStatus(w.ctx, batchHash, event.txHash.String(), event.status)
+					err = w.batchOrm.UpdateCommittedBatch(w.ctx, batchHash, event.txHash.String(), event.l1BlockNumber)
 				}
 				if err != nil {
 					log.Error("Failed to update Rollup/Finalize TxHash and Status", "err", err)
 					return err
 				}
+				latestStatuses[batchHash] = event.status
+				continue
+			}
+			// the status may already be set by the confirmation of the relayer, which doesn't know the L1 block
+			// number if the batch was committed or finalized by another operator.
+			if event.status == types.RollupFinalized {
+				err = w.batchOrm.FillFinalizeL1BlockNumber(w.ctx, batchHash, event.l1BlockNumber)
+			} else if event.status == types.RollupCommitted {
+				err = w.batchOrm.FillCommitL1BlockNumber(w.ctx, batchHash, event.l1BlockNumber)
+			}
+			if err != nil {
+				log.Error("Failed to fill the L1 block number of the batch", "batchHash", batchHash, "err", err)
+				return err
 			}
 		}
 
@@ -258,11 +372,15 @@ func (w *L1WatcherClient) FetchContractEvent() error {
 			return err
 		}
 
-		w.processedMsgHeight = uint64(to)
+		if err = w.saveCheckpoints(group.eventTypes, uint64(to), tipHeader); err != nil {
+			return err
+		}
+		w.setProcessedHeight(group.eventTypes, uint64(to))
 		w.metrics.l1WatcherFetchContractEventSuccessTotal.Inc()
 		w.metrics.l1WatcherFetchContractEventProcessedBlockHeight.Set(float64(w.processedMsgHeight))
 	}
 
+	w.recordImportedBlock(tipHeader)
 	return nil
 }
 
@@ -272,10 +390,16 @@ func (w *L1WatcherClient) parseBridgeEventLogs(logs []gethTypes.Log) ([]*orm.L1M
 	var l1Messages []*orm.L1Message
 	var rollupEvents []rollupEvent
 	for _, vLog := range logs {
-		switch vLog.Topics[0] {
-		case bridgeAbi.L1QueueTransactionEventSignature:
+		version, ok := w.logVersion(vLog)
+		if !ok {
+			log.Warn("Skipping event emitted outside the L1 blocks of its contract versions", "address", vLog.Address, "height", vLog.BlockNumber, "txHash", vLog.TxHash)
+			continue
+		}
+		_, eventName, _ := version.event(vLog.Topics[0])
+		switch eventName {
+		case "QueueTransaction":
 			event := bridgeAbi.L1QueueTransactionEvent{}
-			err := utils.UnpackLog(w.messageQueueABI, &event, "QueueTransaction", vLog)
+			err := utils.UnpackLog(version.messageQueueABI, &event, "QueueTransaction", vLog)
 			if err != nil {
 				log.Warn("Failed to unpack layer1 QueueTransaction event", "err", err)
 				return l1Messages, rollupEvents, err
@@ -293,37 +417,67 @@ func (w *L1WatcherClient) parseBridgeEventLogs(logs []gethTypes.Log) ([]*orm.L1M
 				Calldata:   common.Bytes2Hex(event.Data),
 				GasLimit:   event.GasLimit.Uint64(),
 				Layer1Hash: vLog.TxHash.Hex(),
+				IsEnforced: w.isEnforcedTransaction(version, event.Sender),
 			})
-		case bridgeAbi.L1CommitBatchEventSignature:
+		case "CommitBatch":
 			event := bridgeAbi.L1CommitBatchEvent{}
-			err := utils.UnpackLog(w.scrollChainABI, &event, "CommitBatch", vLog)
+			err := utils.UnpackLog(version.scrollChainABI, &event, "CommitBatch", vLog)
 			if err != nil {
 				log.Warn("Failed to unpack layer1 CommitBatch event", "err", err)
 				return l1Messages, rollupEvents, err
 			}
 
 			rollupEvents = append(rollupEvents, rollupEvent{
-				batchHash: event.BatchHash,
-				txHash:    vLog.TxHash,
-				status:    types.RollupCommitted,
+				batchHash:     event.BatchHash,
+				txHash:        vLog.TxHash,
+				l1BlockNumber: vLog.BlockNumber,
+				status:        types.RollupCommitted,
 			})
-		case bridgeAbi.L1FinalizeBatchEventSignature:
+		case "FinalizeBatch":
 			event := bridgeAbi.L1FinalizeBatchEvent{}
-			err := utils.UnpackLog(w.scrollChainABI, &event, "FinalizeBatch", vLog)
+			err := utils.UnpackLog(version.scrollChainABI, &event, "FinalizeBatch", vLog)
 			if err != nil {
 				log.Warn("Failed to unpack layer1 FinalizeBatch event", "err", err)
 				return l1Messages, rollupEvents, err
 			}
 
 			rollupEvents = append(rollupEvents, rollupEvent{
-				batchHash: event.BatchHash,
-				txHash:    vLog.TxHash,
-				status:    types.RollupFinalized,
+				batchHash:     event.BatchHash,
+				txHash:        vLog.TxHash,
+				l1BlockNumber: vLog.BlockNumber,
+				status:        types.RollupFinalized,
+			})
+		case "RevertBatch":
+			event := bridgeAbi.L1RevertBatchEvent{}
+			err := utils.UnpackLog(version.scrollChainABI, &event, "RevertBatch", vLog)
+			if err != nil {
+				log.Warn("Failed to unpack layer1 RevertBatch event", "err", err)
+				return l1Messages, rollupEvents, err
+			}
+
+			rollupEvents = append(rollupEvents, rollupEvent{
+				batchHash:     event.BatchHash,
+				txHash:        vLog.TxHash,
+				l1BlockNumber: vLog.BlockNumber,
+				status:        types.RollupPending,
 			})
 		default:
-			log.Error("Unknown event", "topic", vLog.Topics[0], "txHash", vLog.TxHash)
+			log.Error("Unknown event", "topic", vLog.Topics[0], "txHash", vLog.TxHash, "contractVersion", version.name)
 		}
 	}
 
 	return l1Messages, rollupEvents, nil
 }
+
+// isEnforcedTransaction tells whether a queued transaction was submitted through the enforced (censorship-resistance) path,
+// i.e. it was not appended by the L1ScrollMessenger of the contract version.
+func (w *L1WatcherClient) isEnforcedTransaction(version *l1ContractVersion, sender common.Address) bool {
+	l1MessengerAliasAddress := version.l1MessengerAliasAddress
+	if l1MessengerAliasAddress == (common.Address{}) {
+		l1MessengerAliasAddress = w.l1MessengerAliasAddress
+	}
+	if l1MessengerAliasAddress == (common.Address{}) {
+		return false
+	}
+	return sender != l1MessengerAliasAddress
+}
@@ -0,0 +1,80 @@
+package watcher
+
+import (
+	"context"
+	"sort"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/rollup/internal/orm"
+)
+
+// loadL1WatcherCheckpoints returns the processed height of every event type and the imported blocks to resume from.
+// The event types without a checkpoint resume from the latest L1 message height, as before the checkpoints.
+func loadL1WatcherCheckpoints(ctx context.Context, checkpointOrm *orm.L1WatcherCheckpoint, savedMessageHeight, startHeight uint64) (map[l1EventType]uint64, []importedL1Block) {
+	processedEventHeights := make(map[l1EventType]uint64)
+	for _, eventType := range l1EventTypes {
+		processedEventHeights[eventType] = savedMessageHeight
+	}
+
+	checkpoints, err := checkpointOrm.GetL1WatcherCheckpoints(ctx)
+	if err != nil {
+		log.Warn("Failed to fetch l1 watcher checkpoints from db", "err", err)
+		return processedEventHeights, nil
+	}
+
+	var importedBlocks []importedL1Block
+	for _, checkpoint := range checkpoints {
+		for _, eventType := range l1EventTypes {
+			if checkpoint.EventFilter != eventType.String() {
+				continue
+			}
+			height := max(checkpoint.ProcessedHeight, startHeight)
+			// The messages are saved before the checkpoint, they may be ahead of it after a crash.
+			if eventType == l1EventL1Message {
+				height = max(height, savedMessageHeight)
+			}
+			processedEventHeights[eventType] = height
+			if checkpoint.BlockHash != "" && height == checkpoint.ProcessedHeight {
+				importedBlocks = append(importedBlocks, importedL1Block{number: height, hash: common.HexToHash(checkpoint.BlockHash)})
+			}
+			log.Info("Resuming l1 watcher from checkpoint", "eventFilter", checkpoint.EventFilter, "height", height)
+		}
+	}
+	sort.Slice(importedBlocks, func(i, j int) bool { return importedBlocks[i].number < importedBlocks[j].number })
+	return processedEventHeights, importedBlocks
+}
+
+// saveCheckpoints persists the processed height of the event types, with the block hash if it is the tip.
+func (w *L1WatcherClient) saveCheckpoints(eventTypes []l1EventType, height uint64, tipHeader *gethTypes.Header) error {
+	var blockHash string
+	if tipHeader.Number.Uint64() == height {
+		blockHash = tipHeader.Hash().String()
+	}
+	for _, eventType := range eventTypes {
+		if err := w.checkpointOrm.UpsertL1WatcherCheckpoint(w.ctx, eventType.String(), height, blockHash); err != nil {
+			log.Error("Failed to save l1 watcher checkpoint", "eventFilter", eventType.String(), "height", height, "err", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// skipProcessedLogs drops the logs of the event types already processed up to their block, which happens when
+// the event types of a filter resume from different checkpoints.
+func (w *L1WatcherClient) skipProcessedLogs(logs []gethTypes.Log) []gethTypes.Log {
+	unprocessed := logs[:0]
+	for _, vLog := range logs {
+		if len(vLog.Topics) == 0 {
+			unprocessed = append(unprocessed, vLog)
+			continue
+		}
+		if w.processedEventHeights[w.logEventType(vLog)] >= vLog.BlockNumber {
+			continue
+		}
+		unprocessed = append(unprocessed, vLog)
+	}
+	return unprocessed
+}
@@ -15,6 +15,15 @@ type l1WatcherMetrics struct {
 	l1WatcherFetchContractEventProcessedBlockHeight prometheus.Gauge
 	l1WatcherFetchContractEventSentEventsTotal      prometheus.Counter
 	l1WatcherFetchContractEventRollupEventsTotal    prometheus.Counter
+	l1WatcherFetchContractEventEnforcedTxsTotal     prometheus.Counter
+	l1WatcherReorgTotal                             prometheus.Counter
+	l1WatcherReorgDepth                             prometheus.Gauge
+	l1WatcherRevertedBatchesTotal                   prometheus.Counter
+	l1WatcherLogFetchBlockRange                     prometheus.Gauge
+	l1WatcherLogFetchShrinkTotal                    prometheus.Counter
+	l1WatcherSyncLagBlocks                          *prometheus.GaugeVec
+	l1WatcherSyncLagAlert                           *prometheus.GaugeVec
+	l1WatcherBeaconCheckpointHeight                 *prometheus.GaugeVec
 }
 
 var (
@@ -53,6 +62,42 @@ func initL1WatcherMetrics(reg prometheus.Registerer) *l1WatcherMetrics {
 				Name: "rollup_l1_watcher_fetch_block_contract_event_rollup_event_total",
 				Help: "The current processed block height of l1 watcher fetch contract rollup event",
 			}),
+			l1WatcherFetchContractEventEnforcedTxsTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_l1_watcher_fetch_block_contract_event_enforced_tx_total",
+				Help: "The total number of enforced transactions submitted on l1",
+			}),
+			l1WatcherReorgTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_l1_watcher_reorg_total",
+				Help: "The total number of l1 reorgs deeper than the confirmations rolled back by the l1 watcher",
+			}),
+			l1WatcherReorgDepth: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+				Name: "rollup_l1_watcher_reorg_depth",
+				Help: "The number of imported l1 blocks rolled back by the latest l1 reorg",
+			}),
+			l1WatcherRevertedBatchesTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_l1_watcher_reverted_batches_total",
+				Help: "The total number of batches reverted on l1 and moved back to pending",
+			}),
+			l1WatcherLogFetchBlockRange: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+				Name: "rollup_l1_watcher_log_fetch_block_range",
+				Help: "The current block range of the l1 watcher log queries",
+			}),
+			l1WatcherLogFetchShrinkTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_l1_watcher_log_fetch_shrink_total",
+				Help: "The total number of l1 watcher log queries rejected by the provider for their range",
+			}),
+			l1WatcherSyncLagBlocks: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+				Name: "rollup_l1_watcher_sync_lag_blocks",
+				Help: "The number of blocks between the l1 head and the height processed by the l1 watcher, per event filter",
+			}, []string{"event_filter"}),
+			l1WatcherSyncLagAlert: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+				Name: "rollup_l1_watcher_sync_lag_alert",
+				Help: "Whether the l1 watcher sync lag of an event filter is above its alert threshold (1) or not (0)",
+			}, []string{"event_filter"}),
+			l1WatcherBeaconCheckpointHeight: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+				Name: "rollup_l1_watcher_beacon_checkpoint_height",
+				Help: "The l1 block number of the safe (justified) and finalized checkpoints of the beacon chain",
+			}, []string{"checkpoint"}),
 		}
 	})
 	return l1WatcherMetric
@@ -0,0 +1,168 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+)
+
+// defaultL1WatcherLeaseName is the name of the leader lease of the l1 watchers if not configured.
+const defaultL1WatcherLeaseName = "l1_watcher"
+
+// L1WatcherStandby elects the l1 watcher importing the events among the instances sharing the database,
+// through a lease in the database renewed by heartbeats. The leader role is only trusted until the lease
+// renewed last expires by the local clock, so that a leader cut off from the database stops importing
+// before a standby instance can take over.
+type L1WatcherStandby struct {
+	ctx           context.Context
+	leaseOrm      *orm.LeaderLease
+	leaseName     string
+	instanceID    string
+	leaseDuration time.Duration
+
+	mu          sync.Mutex
+	leaderUntil time.Time
+
+	standbyLeader        prometheus.Gauge
+	standbyTakeoverTotal prometheus.Counter
+}
+
+// NewL1WatcherStandby creates a new L1WatcherStandby, which is a standby until its first heartbeat acquires the lease.
+func NewL1WatcherStandby(ctx context.Context, db *gorm.DB, cfg *config.L1WatcherStandbyConfig, reg prometheus.Registerer) *L1WatcherStandby {
+	leaseName := cfg.LeaseName
+	if leaseName == "" {
+		leaseName = defaultL1WatcherLeaseName
+	}
+	instanceID := cfg.InstanceID
+	if instanceID == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			hostname = "unknown"
+		}
+		instanceID = fmt.Sprintf("%s-%d", hostname, os.Getpid())
+	}
+	log.Info("L1 watcher hot-standby enabled", "lease", leaseName, "instance", instanceID, "leaseDurationSec", cfg.LeaseDurationSec)
+
+	return &L1WatcherStandby{
+		ctx:           ctx,
+		leaseOrm:      orm.NewLeaderLease(db),
+		leaseName:     leaseName,
+		instanceID:    instanceID,
+		leaseDuration: time.Duration(cfg.LeaseDurationSec) * time.Second,
+
+		standbyLeader: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_l1_watcher_standby_leader",
+			Help: "Whether the l1 watcher holds the leader lease (1) or follows in standby (0).",
+		}),
+		standbyTakeoverTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_l1_watcher_standby_takeover_total",
+			Help: "The total number of times the l1 watcher acquired the leader lease.",
+		}),
+	}
+}
+
+// Heartbeat acquires or renews the leader lease. A failed renewal leaves the leader role to lapse at the expiry.
+func (s *L1WatcherStandby) Heartbeat() {
+	start := time.Now()
+	acquired, err := s.leaseOrm.AcquireLeaderLease(s.ctx, s.leaseName, s.instanceID, s.leaseDuration)
+	if err != nil {
+		log.Error("failed to renew l1 watcher leader lease", "lease", s.leaseName, "instance", s.instanceID, "err", err)
+		return
+	}
+
+	wasLeader := s.IsLeader()
+	s.mu.Lock()
+	if acquired {
+		// the lease expires in the database no earlier than leaseDuration after the request.
+		s.leaderUntil = start.Add(s.leaseDuration)
+	} else {
+		s.leaderUntil = time.Time{}
+	}
+	s.mu.Unlock()
+
+	switch {
+	case acquired && !wasLeader:
+		log.Info("acquired l1 watcher leader lease, importing l1 events", "lease", s.leaseName, "instance", s.instanceID)
+		s.standbyTakeoverTotal.Inc()
+		s.standbyLeader.Set(1)
+	case !acquired && wasLeader:
+		log.Warn("lost l1 watcher leader lease, following in standby", "lease", s.leaseName, "instance", s.instanceID)
+		s.standbyLeader.Set(0)
+	}
+}
+
+// IsLeader tells whether the instance holds the leader lease.
+func (s *L1WatcherStandby) IsLeader() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return time.Now().Before(s.leaderUntil)
+}
+
+// Release gives up the leader lease at shutdown, so that a standby instance takes over without waiting for the expiry.
+func (s *L1WatcherStandby) Release() {
+	if !s.IsLeader() {
+		return
+	}
+	s.mu.Lock()
+	s.leaderUntil = time.Time{}
+	s.mu.Unlock()
+	s.standbyLeader.Set(0)
+
+	// the context of the standby is canceled at shutdown.
+	if err := s.leaseOrm.ReleaseLeaderLease(context.Background(), s.leaseName, s.instanceID); err != nil {
+		log.Error("failed to release l1 watcher leader lease", "lease", s.leaseName, "instance", s.instanceID, "err", err)
+	}
+}
+
+// SetStandby makes the watcher import the events only while it holds the leader lease of the standby,
+// and follow the checkpoints saved by the leader otherwise.
+func (w *L1WatcherClient) SetStandby(standby *L1WatcherStandby) {
+	w.standby = standby
+	w.following = true
+}
+
+// isStandby tells whether the watcher follows another instance instead of importing the events.
+func (w *L1WatcherClient) isStandby() bool {
+	return w.standby != nil && !w.standby.IsLeader()
+}
+
+// followLeader catches up with the checkpoints saved by the leader, without importing anything.
+func (w *L1WatcherClient) followLeader() error {
+	checkpoints, err := w.checkpointOrm.GetL1WatcherCheckpoints(w.ctx)
+	if err != nil {
+		log.Warn("Failed to fetch l1 watcher checkpoints of the leader", "err", err)
+		return err
+	}
+	for _, checkpoint := range checkpoints {
+		for _, eventType := range l1EventTypes {
+			if checkpoint.EventFilter == eventType.String() {
+				w.processedEventHeights[eventType] = max(checkpoint.ProcessedHeight, w.startHeight)
+			}
+		}
+	}
+	w.processedMsgHeight = w.groupProcessedHeight(l1EventTypes)
+	w.metrics.l1WatcherFetchContractEventProcessedBlockHeight.Set(float64(w.processedMsgHeight))
+	return nil
+}
+
+// resumeFromLeader resumes the import from the progress of the previous leader after a takeover, as a restart would.
+func (w *L1WatcherClient) resumeFromLeader() {
+	savedHeight, err := w.l1MessageOrm.GetLayer1LatestWatchedHeight()
+	if err != nil {
+		log.Warn("Failed to fetch height from db", "err", err)
+		savedHeight = 0
+	}
+	w.processedEventHeights, w.importedBlocks = loadL1WatcherCheckpoints(w.ctx, w.checkpointOrm, max(uint64(savedHeight), w.startHeight), w.startHeight)
+	w.processedMsgHeight = w.groupProcessedHeight(l1EventTypes)
+	w.following = false
+}
@@ -30,7 +30,7 @@ func setupL1Watcher(t *testing.T) (*L1WatcherClient, *gorm.DB) {
 	client, err := testApps.GetPoSL1Client()
 	assert.NoError(t, err)
 	l1Cfg := cfg.L1Config
-	watcher := NewL1WatcherClient(context.Background(), client, l1Cfg.StartHeight, l1Cfg.Confirmations, l1Cfg.L1MessageQueueAddress, l1Cfg.RelayerConfig.RollupContractAddress, db, nil)
+	watcher := NewL1WatcherClient(context.Background(), client, l1Cfg.StartHeight, l1Cfg.Confirmations, l1Cfg.L1MessageQueueAddress, l1Cfg.RelayerConfig.RollupContractAddress, l1Cfg.L1ScrollMessengerAddress, db, nil)
 	assert.NoError(t, watcher.FetchContractEvent())
 	return watcher, db
 }
@@ -213,28 +213,63 @@ func testL1WatcherClientFetchContractEvent(t *testing.T) {
 	})
 
 	convey.Convey("db update RollupFinalized status failure", t, func() {
-		targetErr := errors.New("UpdateFinalizeTxHashAndRollupStatus RollupFinalized failure")
-		patchGuard.ApplyMethodFunc(batchOrm, "UpdateFinalizeTxHashAndRollupStatus", func(context.Context, string, string, commonTypes.RollupStatus) error {
+		targetErr := errors.New("UpdateFinalizedBatch failure")
+		patchGuard.ApplyMethodFunc(batchOrm, "UpdateFinalizedBatch", func(context.Context, string, string, uint64) error {
 			return targetErr
 		})
 		err := watcher.FetchContractEvent()
 		assert.Equal(t, targetErr.Error(), err.Error())
 	})
 
-	patchGuard.ApplyMethodFunc(batchOrm, "UpdateFinalizeTxHashAndRollupStatus", func(context.Context, string, string, commonTypes.RollupStatus) error {
+	patchGuard.ApplyMethodFunc(batchOrm, "UpdateFinalizedBatch", func(context.Context, string, string, uint64) error {
 		return nil
 	})
 
 	convey.Convey("db update RollupCommitted status failure", t, func() {
-		targetErr := errors.New("UpdateCommitTxHashAndRollupStatus RollupCommitted failure")
-		patchGuard.ApplyMethodFunc(batchOrm, "UpdateCommitTxHashAndRollupStatus", func(context.Context, string, string, commonTypes.RollupStatus) error {
+		targetErr := errors.New("UpdateCommittedBatch failure")
+		patchGuard.ApplyMethodFunc(batchOrm, "UpdateCommittedBatch", func(context.Context, string, string, uint64) error {
 			return targetErr
 		})
 		err := watcher.FetchContractEvent()
 		assert.Equal(t, targetErr.Error(), err.Error())
 	})
 
-	patchGuard.ApplyMethodFunc(batchOrm, "UpdateCommitTxHashAndRollupStatus", func(context.Context, string, string, commonTypes.RollupStatus) error {
+	patchGuard.ApplyMethodFunc(batchOrm, "UpdateCommittedBatch", func(context.Context, string, string, uint64) error {
+		return nil
+	})
+
+	// the statuses are already set, e.g. by the confirmations of the relayer.
+	patchGuard.ApplyMethodFunc(batchOrm, "GetRollupStatusByHashList", func(context.Context, []string) ([]commonTypes.RollupStatus, error) {
+		s := []commonTypes.RollupStatus{
+			commonTypes.RollupFinalized,
+			commonTypes.RollupCommitted,
+		}
+		return s, nil
+	})
+
+	convey.Convey("db fill finalize l1 block number failure", t, func() {
+		targetErr := errors.New("FillFinalizeL1BlockNumber failure")
+		patchGuard.ApplyMethodFunc(batchOrm, "FillFinalizeL1BlockNumber", func(context.Context, string, uint64) error {
+			return targetErr
+		})
+		err := watcher.FetchContractEvent()
+		assert.Equal(t, targetErr.Error(), err.Error())
+	})
+
+	patchGuard.ApplyMethodFunc(batchOrm, "FillFinalizeL1BlockNumber", func(context.Context, string, uint64) error {
+		return nil
+	})
+
+	convey.Convey("db fill commit l1 block number failure", t, func() {
+		targetErr := errors.New("FillCommitL1BlockNumber failure")
+		patchGuard.ApplyMethodFunc(batchOrm, "FillCommitL1BlockNumber", func(context.Context, string, uint64) error {
+			return targetErr
+		})
+		err := watcher.FetchContractEvent()
+		assert.Equal(t, targetErr.Error(), err.Error())
+	})
+
+	patchGuard.ApplyMethodFunc(batchOrm, "FillCommitL1BlockNumber", func(context.Context, string, uint64) error {
 		return nil
 	})
 
@@ -258,6 +293,45 @@ func testL1WatcherClientFetchContractEvent(t *testing.T) {
 	})
 }
 
+func testL1WatcherClientReorg(t *testing.T) {
+	watcher, db := setupL1Watcher(t)
+	defer database.CloseDB(db)
+
+	l1MessageOrm := orm.NewL1Message(db)
+	assert.NoError(t, l1MessageOrm.SaveL1Messages(context.Background(), []*orm.L1Message{
+		{QueueIndex: 0, MsgHash: "0x01", Height: 90, Value: "0", Layer1Hash: "0x11"},
+		{QueueIndex: 1, MsgHash: "0x02", Height: 95, Value: "0", Layer1Hash: "0x12"},
+	}))
+
+	// Block 90 is still canonical, the imported block 100 was reorged out.
+	canonicalHeader := func(height *big.Int) *types.Header {
+		return &types.Header{Number: new(big.Int).Set(height)}
+	}
+	watcher.importedBlocks = []importedL1Block{
+		{number: 90, hash: canonicalHeader(big.NewInt(90)).Hash()},
+		{number: 100, hash: common.HexToHash("0xdead")},
+	}
+	watcher.setProcessedHeight(l1EventTypes, 100)
+
+	var c *ethclient.Client
+	patchGuard := gomonkey.ApplyMethodFunc(c, "HeaderByNumber", func(ctx context.Context, height *big.Int) (*types.Header, error) {
+		return canonicalHeader(height), nil
+	})
+	defer patchGuard.Reset()
+
+	assert.NoError(t, watcher.checkL1Reorg())
+	assert.Equal(t, uint64(90), watcher.processedMsgHeight)
+	assert.Len(t, watcher.importedBlocks, 1)
+
+	height, err := l1MessageOrm.GetLayer1LatestWatchedHeight()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(90), height)
+
+	// The canonical chain is unchanged, nothing is rolled back.
+	assert.NoError(t, watcher.checkL1Reorg())
+	assert.Equal(t, uint64(90), watcher.processedMsgHeight)
+}
+
 func testParseBridgeEventLogsL1QueueTransactionEventSignature(t *testing.T) {
 	watcher, db := setupL1Watcher(t)
 	defer database.CloseDB(db)
@@ -301,6 +375,38 @@ func testParseBridgeEventLogsL1QueueTransactionEventSignature(t *testing.T) {
 		assert.Empty(t, rollupEvents)
 		assert.Len(t, l2Messages, 1)
 		assert.Equal(t, l2Messages[0].Value, big.NewInt(1000).String())
+		assert.False(t, l2Messages[0].IsEnforced)
+	})
+
+	convey.Convey("L1QueueTransactionEventSignature enforced transaction", t, func() {
+		messengerAlias := utils.ApplyL1ToL2Alias(common.HexToAddress("0x6774Bcbd5ceCef1336b5300fb5186a12DDD8b367"))
+		watcher.l1MessengerAliasAddress = messengerAlias
+		defer func() { watcher.l1MessengerAliasAddress = common.Address{} }()
+
+		var sender common.Address
+		patchGuard := gomonkey.ApplyFunc(utils.UnpackLog, func(c *abi.ABI, out interface{}, event string, log types.Log) error {
+			tmpOut := out.(*bridgeAbi.L1QueueTransactionEvent)
+			tmpOut.QueueIndex = 100
+			tmpOut.Data = []byte("test data")
+			tmpOut.Sender = sender
+			tmpOut.Value = big.NewInt(1000)
+			tmpOut.Target = common.HexToAddress("0xad3228b676f7d3cd4284a5443f17f1962b36e491b30a40b2405849e597ba5fb5")
+			tmpOut.GasLimit = big.NewInt(10)
+			return nil
+		})
+		defer patchGuard.Reset()
+
+		sender = messengerAlias
+		l2Messages, _, err := watcher.parseBridgeEventLogs(logs)
+		assert.NoError(t, err)
+		assert.Len(t, l2Messages, 1)
+		assert.False(t, l2Messages[0].IsEnforced)
+
+		sender = common.HexToAddress("0xb4c11951957c6f8f642c4af61cd6b24640fec6dc")
+		l2Messages, _, err = watcher.parseBridgeEventLogs(logs)
+		assert.NoError(t, err)
+		assert.Len(t, l2Messages, 1)
+		assert.True(t, l2Messages[0].IsEnforced)
 	})
 }
 
@@ -387,3 +493,45 @@ func testParseBridgeEventLogsL1FinalizeBatchEventSignature(t *testing.T) {
 		assert.Equal(t, rollupEvents[0].status, commonTypes.RollupFinalized)
 	})
 }
+
+func testParseBridgeEventLogsL1RevertBatchEventSignature(t *testing.T) {
+	watcher, db := setupL1Watcher(t)
+	defer database.CloseDB(db)
+	logs := []types.Log{
+		{
+			Topics:      []common.Hash{bridgeAbi.L1RevertBatchEventSignature},
+			BlockNumber: 100,
+			TxHash:      common.HexToHash("0x1dcc4de8dec75d7aab85b567b6ccd41ad312451b948a7413f0a142fd40d49347"),
+		},
+	}
+
+	convey.Convey("unpack RevertBatch log failure", t, func() {
+		targetErr := errors.New("UnpackLog RevertBatch failure")
+		patchGuard := gomonkey.ApplyFunc(utils.UnpackLog, func(c *abi.ABI, out interface{}, event string, log types.Log) error {
+			return targetErr
+		})
+		defer patchGuard.Reset()
+
+		l2Messages, rollupEvents, err := watcher.parseBridgeEventLogs(logs)
+		assert.EqualError(t, err, targetErr.Error())
+		assert.Empty(t, l2Messages)
+		assert.Empty(t, rollupEvents)
+	})
+
+	convey.Convey("L1RevertBatchEventSignature success", t, func() {
+		msgHash := common.HexToHash("0xad3228b676f7d3cd4284a5443f17f1962b36e491b30a40b2405849e597ba5fb5")
+		patchGuard := gomonkey.ApplyFunc(utils.UnpackLog, func(c *abi.ABI, out interface{}, event string, log types.Log) error {
+			tmpOut := out.(*bridgeAbi.L1RevertBatchEvent)
+			tmpOut.BatchHash = msgHash
+			return nil
+		})
+		defer patchGuard.Reset()
+
+		l2Messages, rollupEvents, err := watcher.parseBridgeEventLogs(logs)
+		assert.NoError(t, err)
+		assert.Empty(t, l2Messages)
+		assert.Len(t, rollupEvents, 1)
+		assert.Equal(t, rollupEvents[0].batchHash, msgHash)
+		assert.Equal(t, rollupEvents[0].status, commonTypes.RollupPending)
+	})
+}
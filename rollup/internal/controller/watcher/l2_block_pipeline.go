@@ -0,0 +1,58 @@
+package watcher
+
+import (
+	"context"
+
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/common/types/encoding"
+)
+
+// fetchedBlockRange is a range of blocks fetched ahead of its insertion.
+type fetchedBlockRange struct {
+	from, to uint64
+	blocks   []*encoding.Block
+	// err is the error of the first block of the range which failed to be fetched, the blocks before it are fetched
+	err error
+}
+
+// fetchAndStoreBlocksPipelined fetches and stores the blocks in [from, blockHeight] like the sequential loop of
+// TryFetchRunningMissingBlocks, but the ranges are fetched by a separate goroutine up to prefetchDepth ranges ahead
+// of the insertion, so that fetching the next ranges overlaps with inserting the current one. It is only used while
+// no block is quarantined, as the insertion releases the quarantine the fetching reads. The prefetched
+// ranges are dropped on the first failure, and fetched again in the next attempt.
+func (w *L2WatcherClient) fetchAndStoreBlocksPipelined(from, blockHeight uint64) {
+	ctx, cancel := context.WithCancel(w.ctx)
+	defer cancel()
+
+	ranges := make(chan *fetchedBlockRange, w.prefetchDepth)
+	go func() {
+		defer close(ranges)
+		for start := from; start <= blockHeight; start += w.blocksFetchLimit {
+			end := start + w.blocksFetchLimit - 1
+			if end > blockHeight {
+				end = blockHeight
+			}
+
+			blocks, err := w.getBlocks(ctx, start, end)
+			select {
+			case ranges <- &fetchedBlockRange{from: start, to: end, blocks: blocks, err: err}:
+			case <-ctx.Done():
+				return
+			}
+			// the blocks after a failed one are fetched again by the next attempt
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	for fetched := range ranges {
+		if err := w.storeBlocks(ctx, fetched.from, fetched.blocks, fetched.err); err != nil {
+			log.Error("fail to getAndStoreBlockTraces", "from", fetched.from, "to", fetched.to, "err", err)
+			return
+		}
+		w.metrics.fetchRunningMissingBlocksHeight.Set(float64(fetched.to))
+		w.metrics.rollupL2BlocksFetchedGap.Set(float64(blockHeight - fetched.to))
+	}
+}
@@ -0,0 +1,98 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+)
+
+// defaultL2BlockPruneBatchSize is the number of blocks pruned per database statement if not configured.
+const defaultL2BlockPruneBatchSize = 1000
+
+// L2BlockPruner implements the light storage mode: it discards the transactions of the L2 blocks whose batch
+// is finalized, keeping the header fields and the per-block metrics, to bound the database size of long-running nodes.
+type L2BlockPruner struct {
+	ctx       context.Context
+	batchSize int
+
+	batchOrm   *orm.Batch
+	chunkOrm   *orm.Chunk
+	l2BlockOrm *orm.L2Block
+
+	l2BlockPrunedTotal  prometheus.Counter
+	l2BlockPrunedHeight prometheus.Gauge
+}
+
+// NewL2BlockPruner creates a new L2BlockPruner instance.
+func NewL2BlockPruner(ctx context.Context, cfg *config.L2LightStorageConfig, db *gorm.DB, reg prometheus.Registerer) *L2BlockPruner {
+	batchSize := int(cfg.PruneBatchSize)
+	if batchSize == 0 {
+		batchSize = defaultL2BlockPruneBatchSize
+	}
+
+	return &L2BlockPruner{
+		ctx:        ctx,
+		batchSize:  batchSize,
+		batchOrm:   orm.NewBatch(db),
+		chunkOrm:   orm.NewChunk(db),
+		l2BlockOrm: orm.NewL2Block(db),
+
+		l2BlockPrunedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_l2_block_pruned_total",
+			Help: "Total number of L2 blocks whose transactions are pruned by the light storage mode.",
+		}),
+		l2BlockPrunedHeight: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_l2_block_pruned_height",
+			Help: "The height up to which the transactions of the L2 blocks are pruned.",
+		}),
+	}
+}
+
+// Prune discards the transactions of the blocks up to the last block of the latest finalized batch.
+func (p *L2BlockPruner) Prune() {
+	height, err := p.finalizedHeight()
+	if err != nil {
+		log.Error("failed to get the finalized l2 block height", "err", err)
+		return
+	}
+	if height == 0 {
+		return
+	}
+
+	for p.ctx.Err() == nil {
+		pruned, err := p.l2BlockOrm.PruneL2BlockTransactions(p.ctx, height, p.batchSize)
+		if err != nil {
+			log.Error("failed to prune l2 block transactions", "height", height, "err", err)
+			return
+		}
+		p.l2BlockPrunedTotal.Add(float64(pruned))
+		if pruned < int64(p.batchSize) {
+			break
+		}
+	}
+	p.l2BlockPrunedHeight.Set(float64(height))
+}
+
+// finalizedHeight returns the number of the last block of the latest finalized batch, 0 if no batch is finalized.
+func (p *L2BlockPruner) finalizedHeight() (uint64, error) {
+	batch, err := p.batchOrm.GetLatestFinalizedBatch(p.ctx)
+	if err != nil {
+		return 0, err
+	}
+	if batch == nil {
+		return 0, nil
+	}
+
+	chunks, err := p.chunkOrm.GetChunksInRange(p.ctx, batch.EndChunkIndex, batch.EndChunkIndex)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get the last chunk of batch %v: %w", batch.Index, err)
+	}
+	return chunks[0].EndBlockNumber, nil
+}
@@ -0,0 +1,100 @@
+package watcher
+
+import (
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+)
+
+// SetBlockQuarantineConfig enables the quarantine of the blocks which repeatedly fail to be fetched or validated,
+// so that a bad block is retried at a slower pace until an operator retries or resolves it through the admin api.
+func (w *L2WatcherClient) SetBlockQuarantineConfig(cfg *config.L2BlockQuarantineConfig) {
+	w.quarantineCfg = cfg
+}
+
+// checkQuarantine loads the quarantine of the next block to import, and returns false
+// if the block is quarantined and was last retried less than the retry interval ago.
+func (w *L2WatcherClient) checkQuarantine(number uint64) bool {
+	w.quarantinedBlock = nil
+	if w.quarantineCfg == nil {
+		return true
+	}
+
+	quarantine, err := w.l2BlockQuarantineOrm.GetL2BlockQuarantine(w.ctx, number)
+	if err != nil {
+		log.Error("failed to get the quarantine of the next L2 block", "number", number, "err", err)
+		return false
+	}
+	if quarantine == nil {
+		w.metrics.l2WatcherQuarantinedBlock.Set(0)
+		return true
+	}
+	w.quarantinedBlock = quarantine
+	w.metrics.l2WatcherQuarantinedBlock.Set(float64(number))
+
+	retryInterval := time.Duration(w.quarantineCfg.RetryIntervalSec) * time.Second
+	if quarantine.Status == orm.L2BlockQuarantineStatusQuarantined && w.failedBlockNumber == number && time.Since(w.failedBlockAt) < retryInterval {
+		return false
+	}
+	return true
+}
+
+// recordBlockFailure counts the consecutive failures of the block, and quarantines it after the max attempts.
+// A retry requested by an operator failing again puts the block back in quarantine.
+func (w *L2WatcherClient) recordBlockFailure(number uint64, err error) {
+	w.metrics.l2WatcherBlockImportFailureTotal.Inc()
+	if w.quarantineCfg == nil {
+		return
+	}
+
+	if w.failedBlockNumber != number {
+		w.failedBlockNumber = number
+		w.failedBlockAttempts = 0
+	}
+	w.failedBlockAttempts++
+	w.failedBlockAt = time.Now()
+	if w.failedBlockAttempts < w.quarantineCfg.MaxAttempts {
+		return
+	}
+
+	quarantined := w.quarantinedBlock != nil && w.quarantinedBlock.Number == number
+	status := orm.L2BlockQuarantineStatusQuarantined
+	if quarantined && w.quarantinedBlock.Status == orm.L2BlockQuarantineStatusResolved {
+		status = orm.L2BlockQuarantineStatusResolved
+	}
+	if upsertErr := w.l2BlockQuarantineOrm.UpsertL2BlockQuarantine(w.ctx, number, status, w.failedBlockAttempts, err.Error()); upsertErr != nil {
+		log.Error("failed to quarantine L2 block", "number", number, "err", upsertErr)
+		return
+	}
+	if !quarantined {
+		w.metrics.l2WatcherQuarantinedBlockTotal.Inc()
+		log.Error("L2 block quarantined", "number", number, "attempts", w.failedBlockAttempts, "err", err)
+	}
+}
+
+// releaseQuarantine releases the quarantined block and resets the failures once the block is imported.
+func (w *L2WatcherClient) releaseQuarantine(from, to uint64) {
+	if w.failedBlockNumber >= from && w.failedBlockNumber <= to {
+		w.failedBlockNumber = 0
+		w.failedBlockAttempts = 0
+	}
+	if w.quarantinedBlock == nil || w.quarantinedBlock.Number < from || w.quarantinedBlock.Number > to {
+		return
+	}
+	if err := w.l2BlockQuarantineOrm.DeleteL2BlockQuarantine(w.ctx, w.quarantinedBlock.Number); err != nil {
+		log.Error("failed to release L2 block from quarantine", "number", w.quarantinedBlock.Number, "err", err)
+		return
+	}
+	log.Info("L2 block released from quarantine", "number", w.quarantinedBlock.Number, "status", w.quarantinedBlock.Status)
+	w.quarantinedBlock = nil
+	w.metrics.l2WatcherQuarantinedBlock.Set(0)
+}
+
+// importWithoutRowConsumption returns whether the block can be imported without its row consumption,
+// i.e. an operator resolved its quarantine. The row consumption is filled in later by the row consumption backfill.
+func (w *L2WatcherClient) importWithoutRowConsumption(number uint64) bool {
+	return w.quarantinedBlock != nil && w.quarantinedBlock.Number == number && w.quarantinedBlock.Status == orm.L2BlockQuarantineStatusResolved
+}
@@ -0,0 +1,65 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/common/types/encoding"
+)
+
+// l2CrossCheckClient is the l2geth node the fetched blocks are checked against.
+type l2CrossCheckClient interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*gethTypes.Header, error)
+	StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error)
+}
+
+// SetCrossCheck sets the l2geth node the last block of every fetched range is checked against, and the
+// function called when the fetched block diverges, e.g. to fail over to another endpoint. It's disabled if client is nil.
+func (w *L2WatcherClient) SetCrossCheck(client l2CrossCheckClient, onDivergence func()) {
+	w.crossCheckClient = client
+	w.onCrossCheckDivergence = onDivergence
+}
+
+// crossCheck compares the hash and the withdraw root of the block against the cross-check node. An unavailable
+// cross-check node doesn't block the import, the check is skipped.
+func (w *L2WatcherClient) crossCheck(ctx context.Context, block *encoding.Block) error {
+	if w.crossCheckClient == nil {
+		return nil
+	}
+
+	number := block.Header.Number
+	header, err := w.crossCheckClient.HeaderByNumber(ctx, number)
+	if err != nil {
+		w.metrics.l2WatcherCrossCheckSkippedTotal.Inc()
+		log.Warn("failed to get the block from the cross-check node, skipping the check", "number", number, "err", err)
+		return nil
+	}
+	withdrawRoot, err := w.crossCheckClient.StorageAt(ctx, w.messageQueueAddress, w.withdrawTrieRootSlot, number)
+	if err != nil {
+		w.metrics.l2WatcherCrossCheckSkippedTotal.Inc()
+		log.Warn("failed to get the withdraw root from the cross-check node, skipping the check", "number", number, "err", err)
+		return nil
+	}
+
+	var divergence error
+	if header.Hash() != block.Header.Hash() {
+		divergence = fmt.Errorf("block %v diverges from the cross-check node, fetched hash: %v, cross-check hash: %v", number, block.Header.Hash(), header.Hash())
+	} else if common.BytesToHash(withdrawRoot) != block.WithdrawRoot {
+		divergence = fmt.Errorf("withdraw root of block %v diverges from the cross-check node, fetched: %v, cross-check: %v", number, block.WithdrawRoot, common.BytesToHash(withdrawRoot))
+	}
+	if divergence == nil {
+		return nil
+	}
+
+	w.metrics.l2WatcherCrossCheckDivergenceTotal.Inc()
+	log.Error("l2 block cross-check failed", "err", divergence)
+	if w.onCrossCheckDivergence != nil {
+		w.onCrossCheckDivergence()
+	}
+	return divergence
+}
@@ -0,0 +1,60 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/types/encoding"
+)
+
+type mockCrossCheckClient struct {
+	header       *gethTypes.Header
+	withdrawRoot common.Hash
+	err          error
+}
+
+func (c *mockCrossCheckClient) HeaderByNumber(context.Context, *big.Int) (*gethTypes.Header, error) {
+	return c.header, c.err
+}
+
+func (c *mockCrossCheckClient) StorageAt(context.Context, common.Address, common.Hash, *big.Int) ([]byte, error) {
+	return c.withdrawRoot.Bytes(), c.err
+}
+
+func TestL2WatcherCrossCheck(t *testing.T) {
+	block := &encoding.Block{
+		Header:       &gethTypes.Header{Number: big.NewInt(10), Difficulty: big.NewInt(0)},
+		WithdrawRoot: common.HexToHash("0x01"),
+	}
+	client := &mockCrossCheckClient{header: gethTypes.CopyHeader(block.Header), withdrawRoot: block.WithdrawRoot}
+	var divergences int
+	w := &L2WatcherClient{metrics: initL2WatcherMetrics(prometheus.NewRegistry())}
+	assert.NoError(t, w.crossCheck(context.Background(), block))
+
+	w.SetCrossCheck(client, func() { divergences++ })
+	assert.NoError(t, w.crossCheck(context.Background(), block))
+	assert.Equal(t, 0, divergences)
+
+	// A diverging withdraw root fails the import and fails over.
+	client.withdrawRoot = common.HexToHash("0x02")
+	assert.Error(t, w.crossCheck(context.Background(), block))
+	assert.Equal(t, 1, divergences)
+
+	// A diverging block hash too.
+	client.withdrawRoot = block.WithdrawRoot
+	client.header.GasUsed = 1
+	assert.Error(t, w.crossCheck(context.Background(), block))
+	assert.Equal(t, 2, divergences)
+
+	// An unavailable cross-check node doesn't block the import.
+	client.err = errors.New("connection refused")
+	assert.NoError(t, w.crossCheck(context.Background(), block))
+	assert.Equal(t, 2, divergences)
+}
@@ -0,0 +1,94 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+
+	geth "github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+
+	"scroll-tech/common/types/encoding"
+
+	bridgeAbi "scroll-tech/rollup/abi"
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+	"scroll-tech/rollup/internal/utils"
+)
+
+// SetMessageIndexConfig enables the indexing of the L2 to L1 messages sent by the imported blocks, so that the
+// withdraw roots can be verified and the message proofs built from the rollup database.
+func (w *L2WatcherClient) SetMessageIndexConfig(cfg *config.L2MessageIndexConfig) {
+	w.messageIndexCfg = cfg
+}
+
+// getL2Messages returns the L2 to L1 messages sent by the blocks, in index order. The logs must belong to the
+// fetched blocks, a log from another fork means that l2geth reorged since and the blocks are fetched again.
+func (w *L2WatcherClient) getL2Messages(ctx context.Context, blocks []*encoding.Block) ([]*orm.L2Message, error) {
+	if w.messageIndexCfg == nil || len(blocks) == 0 {
+		return nil, nil
+	}
+
+	blockHashes := make(map[uint64]common.Hash, len(blocks))
+	for _, block := range blocks {
+		blockHashes[block.Header.Number.Uint64()] = block.Header.Hash()
+	}
+	query := geth.FilterQuery{
+		FromBlock: blocks[0].Header.Number,
+		ToBlock:   blocks[len(blocks)-1].Header.Number,
+		Addresses: []common.Address{w.messageQueueAddress, w.messageIndexCfg.L2ScrollMessengerAddress},
+		Topics:    [][]common.Hash{{bridgeAbi.L2AppendMessageEventSignature, bridgeAbi.L2SentMessageEventSignature}},
+	}
+	logs, err := w.FilterLogs(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get L2 message logs: %w", err)
+	}
+
+	var messages []*orm.L2Message
+	sentMessages := make(map[uint64]*bridgeAbi.L2SentMessageEvent)
+	for _, vLog := range logs {
+		if hash, ok := blockHashes[vLog.BlockNumber]; !ok || hash != vLog.BlockHash {
+			return nil, fmt.Errorf("L2 message log of block %d (%s) doesn't match the fetched block", vLog.BlockNumber, vLog.BlockHash)
+		}
+		switch {
+		case vLog.Address == w.messageQueueAddress && vLog.Topics[0] == bridgeAbi.L2AppendMessageEventSignature:
+			event := bridgeAbi.L2AppendMessageEvent{}
+			if err := utils.UnpackLog(w.messageQueueABI, &event, "AppendMessage", vLog); err != nil {
+				return nil, fmt.Errorf("failed to unpack AppendMessage event: %w", err)
+			}
+			messages = append(messages, &orm.L2Message{
+				MessageIndex: event.Index.Uint64(),
+				MsgHash:      event.MessageHash.String(),
+				Height:       vLog.BlockNumber,
+				Layer2Hash:   vLog.TxHash.String(),
+			})
+		case vLog.Address == w.messageIndexCfg.L2ScrollMessengerAddress && vLog.Topics[0] == bridgeAbi.L2SentMessageEventSignature:
+			event, err := unpackL2SentMessageEvent(vLog)
+			if err != nil {
+				return nil, err
+			}
+			sentMessages[event.MessageNonce.Uint64()] = event
+		}
+	}
+
+	// The messenger uses the index of the message in the L2MessageQueue as message nonce.
+	for _, message := range messages {
+		if event, ok := sentMessages[message.MessageIndex]; ok {
+			message.Sender = event.Sender.String()
+			message.Target = event.Target.String()
+			message.Value = event.Value.String()
+			message.GasLimit = event.GasLimit.Uint64()
+			message.Calldata = hexutil.Encode(event.Message)
+		}
+	}
+	return messages, nil
+}
+
+func unpackL2SentMessageEvent(vLog gethTypes.Log) (*bridgeAbi.L2SentMessageEvent, error) {
+	event := bridgeAbi.L2SentMessageEvent{}
+	if err := utils.UnpackLog(bridgeAbi.L2ScrollMessengerABI, &event, "SentMessage", vLog); err != nil {
+		return nil, fmt.Errorf("failed to unpack SentMessage event: %w", err)
+	}
+	return &event, nil
+}
@@ -0,0 +1,83 @@
+package watcher
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/ethclient"
+	"github.com/scroll-tech/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/types/encoding"
+
+	bridgeAbi "scroll-tech/rollup/abi"
+	"scroll-tech/rollup/internal/config"
+)
+
+type mockL2LogsService struct {
+	logs []gethTypes.Log
+}
+
+func (s *mockL2LogsService) GetLogs(_ context.Context, _ map[string]interface{}) ([]gethTypes.Log, error) {
+	return s.logs, nil
+}
+
+func TestL2WatcherGetL2Messages(t *testing.T) {
+	messageQueue := common.HexToAddress("0x5300000000000000000000000000000000000000")
+	messenger := common.HexToAddress("0x4200000000000000000000000000000000000007")
+	block := &encoding.Block{Header: &gethTypes.Header{Number: big.NewInt(10), Difficulty: big.NewInt(0)}}
+
+	appendEvent := bridgeAbi.L2MessageQueueABI.Events["AppendMessage"]
+	appendData, err := appendEvent.Inputs.NonIndexed().Pack(big.NewInt(7), common.HexToHash("0x77"))
+	assert.NoError(t, err)
+	sentEvent := bridgeAbi.L2ScrollMessengerABI.Events["SentMessage"]
+	sentData, err := sentEvent.Inputs.NonIndexed().Pack(big.NewInt(1), big.NewInt(7), big.NewInt(100), []byte{0x12})
+	assert.NoError(t, err)
+	service := &mockL2LogsService{logs: []gethTypes.Log{
+		{
+			Address:     messenger,
+			Topics:      []common.Hash{sentEvent.ID, common.BytesToHash(common.HexToAddress("0x01").Bytes()), common.BytesToHash(common.HexToAddress("0x02").Bytes())},
+			Data:        sentData,
+			BlockNumber: 10,
+			BlockHash:   block.Header.Hash(),
+			TxHash:      common.HexToHash("0xaa"),
+		},
+		{
+			Address:     messageQueue,
+			Topics:      []common.Hash{appendEvent.ID},
+			Data:        appendData,
+			BlockNumber: 10,
+			BlockHash:   block.Header.Hash(),
+			TxHash:      common.HexToHash("0xaa"),
+		},
+	}}
+
+	server := rpc.NewServer()
+	assert.NoError(t, server.RegisterName("eth", service))
+	client := ethclient.NewClient(rpc.DialInProc(server))
+	defer client.Close()
+
+	w := &L2WatcherClient{Client: client, messageQueueAddress: messageQueue, messageQueueABI: bridgeAbi.L2MessageQueueABI}
+	messages, err := w.getL2Messages(context.Background(), []*encoding.Block{block})
+	assert.NoError(t, err)
+	assert.Nil(t, messages)
+
+	w.SetMessageIndexConfig(&config.L2MessageIndexConfig{L2ScrollMessengerAddress: messenger})
+	messages, err = w.getL2Messages(context.Background(), []*encoding.Block{block})
+	assert.NoError(t, err)
+	assert.Len(t, messages, 1)
+	assert.Equal(t, uint64(7), messages[0].MessageIndex)
+	assert.Equal(t, common.HexToHash("0x77").String(), messages[0].MsgHash)
+	assert.Equal(t, uint64(10), messages[0].Height)
+	assert.Equal(t, common.HexToAddress("0x02").String(), messages[0].Target)
+	assert.Equal(t, uint64(100), messages[0].GasLimit)
+	assert.Equal(t, "0x12", messages[0].Calldata)
+
+	// the logs of another fork are rejected.
+	service.logs[1].BlockHash = common.HexToHash("0xbb")
+	_, err = w.getL2Messages(context.Background(), []*encoding.Block{block})
+	assert.Error(t, err)
+}
@@ -0,0 +1,121 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types/encoding"
+)
+
+// maxL2ReorgDepth is the number of stored blocks compared with the canonical chain when looking for the fork height.
+const maxL2ReorgDepth = 256
+
+// staleChunkReasonL2Reorg is the reason of the release of the chunks containing blocks orphaned by an L2 reorg.
+const staleChunkReasonL2Reorg = "l2_reorg"
+
+// checkL2Reorg checks that the blocks fetched from height from on extend the stored chain. It returns the prefix of
+// the blocks linked by their parent hashes, e.g. the rest was fetched from another fork while l2geth reorged.
+// If the first block doesn't extend the stored chain, the stored blocks orphaned by the reorg are rolled back and an
+// error is returned, so that the canonical blocks are imported from the fork height by the next fetch.
+func (w *L2WatcherClient) checkL2Reorg(ctx context.Context, from uint64, blocks []*encoding.Block) ([]*encoding.Block, error) {
+	for i := 1; i < len(blocks); i++ {
+		if blocks[i].Header.ParentHash != blocks[i-1].Header.Hash() {
+			log.Warn("fetched L2 blocks are not linked, l2geth reorged during the fetch", "height", blocks[i].Header.Number)
+			blocks = blocks[:i]
+			break
+		}
+	}
+
+	if from == 0 {
+		return blocks, nil
+	}
+	parentHash, err := w.l2BlockOrm.GetL2BlockHashByNumber(ctx, from-1)
+	if err != nil {
+		return nil, err
+	}
+	if parentHash == "" || parentHash == blocks[0].Header.ParentHash.String() {
+		return blocks, nil
+	}
+
+	log.Warn("L2 reorg detected", "height", from, "storedParentHash", parentHash, "parentHash", blocks[0].Header.ParentHash)
+	forkHeight, err := w.findL2ForkHeight(ctx, from-1)
+	if err != nil {
+		return nil, err
+	}
+	deleted, err := w.rollbackL2Blocks(ctx, forkHeight, staleChunkReasonL2Reorg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to roll back the L2 reorg from height %d: %w", forkHeight, err)
+	}
+	w.metrics.l2WatcherReorgTotal.Inc()
+	w.metrics.l2WatcherReorgDepth.Set(float64(deleted))
+	log.Warn("L2 reorg rolled back", "forkHeight", forkHeight, "deletedBlocks", deleted)
+	return nil, fmt.Errorf("L2 reorg rolled back from height %d", forkHeight)
+}
+
+// findL2ForkHeight returns the height of the first stored block not in the canonical chain,
+// walking the stored blocks back from the given height.
+func (w *L2WatcherClient) findL2ForkHeight(ctx context.Context, height uint64) (uint64, error) {
+	for depth := uint64(0); depth < maxL2ReorgDepth && height > 0; depth++ {
+		storedHash, err := w.l2BlockOrm.GetL2BlockHashByNumber(ctx, height)
+		if err != nil {
+			return 0, err
+		}
+		header, err := w.HeaderByNumber(ctx, new(big.Int).SetUint64(height))
+		if err != nil {
+			return 0, fmt.Errorf("failed to get L2 header %d: %w", height, err)
+		}
+		if storedHash == "" || header.Hash().String() == storedHash {
+			return height + 1, nil
+		}
+		height--
+	}
+	return 0, fmt.Errorf("L2 reorg deeper than %d blocks, the fork height is below %d", maxL2ReorgDepth, height+1)
+}
+
+// rollbackL2Blocks deletes the stored blocks and their L2 messages from the given height on. The chunks containing those blocks, and the
+// chunks after them since each chunk builds on its parent, are released and recorded as stale with the reason, so
+// that the blocks imported again are chunked again. It fails if one of those chunks is already batched.
+// It returns the number of deleted blocks.
+func (w *L2WatcherClient) rollbackL2Blocks(ctx context.Context, height uint64, reason string) (int64, error) {
+	chunks, err := w.chunkOrm.GetChunksGEBlockNumber(ctx, height)
+	if err != nil {
+		return 0, err
+	}
+	for _, chunk := range chunks {
+		if chunk.BatchHash != "" {
+			return 0, fmt.Errorf("the blocks from height %d are in chunk %d already included in batch %s", height, chunk.Index, chunk.BatchHash)
+		}
+	}
+
+	var deleted int64
+	err = w.db.Transaction(func(dbTX *gorm.DB) error {
+		if len(chunks) > 0 {
+			if _, err := w.chunkOrm.DeleteUnbatchedChunksGEIndex(ctx, chunks[0].Index, dbTX); err != nil {
+				return err
+			}
+			if err := w.l2BlockOrm.ResetChunkHashGENumber(ctx, chunks[0].StartBlockNumber, dbTX); err != nil {
+				return err
+			}
+			if err := w.staleChunkOrm.InsertStaleChunks(ctx, chunks, reason, dbTX); err != nil {
+				return err
+			}
+		}
+		if err := w.l2MessageOrm.DeleteL2MessagesGEHeight(ctx, height, dbTX); err != nil {
+			return err
+		}
+		var err error
+		deleted, err = w.l2BlockOrm.DeleteL2BlocksGENumber(ctx, height, dbTX)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(chunks) > 0 {
+		log.Warn("released the chunks of the deleted L2 blocks", "start index", chunks[0].Index, "end index", chunks[len(chunks)-1].Index, "reason", reason)
+	}
+	return deleted, nil
+}
@@ -0,0 +1,159 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/ethclient"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rpc"
+	"gorm.io/gorm"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+)
+
+// RowConsumptionBackfiller backfills the row consumption of the L2 blocks stored without it, e.g. ingested
+// before the row consumption was available or from a fallback API. The chunk proposer can't seal a chunk
+// containing such a block, so the backfill unblocks the chunk proposal over those ranges.
+type RowConsumptionBackfiller struct {
+	ctx context.Context
+	cfg *config.RowConsumptionBackfillConfig
+
+	l2Client              *ethclient.Client
+	capacityCheckerClient *rpc.Client
+
+	l2BlockOrm *orm.L2Block
+
+	rowConsumptionBackfilledTotal      prometheus.Counter
+	rowConsumptionBackfillFailureTotal prometheus.Counter
+	rowConsumptionMissingBlocks        prometheus.Gauge
+}
+
+// NewRowConsumptionBackfiller creates a new RowConsumptionBackfiller instance.
+func NewRowConsumptionBackfiller(ctx context.Context, cfg *config.RowConsumptionBackfillConfig, l2Client *ethclient.Client, db *gorm.DB, reg prometheus.Registerer) (*RowConsumptionBackfiller, error) {
+	b := &RowConsumptionBackfiller{
+		ctx:        ctx,
+		cfg:        cfg,
+		l2Client:   l2Client,
+		l2BlockOrm: orm.NewL2Block(db),
+
+		rowConsumptionBackfilledTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_l2_row_consumption_backfilled_total",
+			Help: "Total number of L2 blocks whose row consumption is backfilled.",
+		}),
+		rowConsumptionBackfillFailureTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_l2_row_consumption_backfill_failure_total",
+			Help: "Total number of failures to backfill the row consumption of an L2 block.",
+		}),
+		rowConsumptionMissingBlocks: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_l2_row_consumption_missing_blocks",
+			Help: "The number of L2 blocks without row consumption found in the last check, capped by the backfill batch size.",
+		}),
+	}
+
+	if cfg.CapacityCheckerEndpoint != "" {
+		client, err := rpc.DialContext(ctx, cfg.CapacityCheckerEndpoint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to dial capacity checker endpoint: %w", err)
+		}
+		b.capacityCheckerClient = client
+	}
+	return b, nil
+}
+
+// Backfill backfills the row consumption of the oldest blocks stored without it, in ascending block number order.
+// It stops at the first block whose row consumption can't be fetched, the block is retried in the next check.
+func (b *RowConsumptionBackfiller) Backfill() {
+	if _, _, err := b.backfillNext(); err != nil {
+		log.Error("failed to backfill row consumption", "err", err)
+	}
+}
+
+// BackfillAll backfills the row consumption of all the blocks stored without it, a batch of blocks at a time,
+// e.g. to unblock the chunk proposer after a tracing outage. It returns the number of backfilled blocks and
+// stops at the first block whose row consumption can't be fetched.
+func (b *RowConsumptionBackfiller) BackfillAll() (uint64, error) {
+	var total uint64
+	for {
+		found, backfilled, err := b.backfillNext()
+		total += uint64(backfilled)
+		if err != nil {
+			return total, err
+		}
+		if found == 0 {
+			return total, nil
+		}
+	}
+}
+
+// backfillNext backfills the row consumption of the next batch of blocks stored without it,
+// it returns the number of blocks found without row consumption and the number of backfilled blocks.
+func (b *RowConsumptionBackfiller) backfillNext() (int, int, error) {
+	blocks, err := b.l2BlockOrm.GetL2BlocksWithoutRowConsumption(b.ctx, int(b.cfg.BatchSize))
+	if err != nil {
+		b.rowConsumptionBackfillFailureTotal.Inc()
+		return 0, 0, fmt.Errorf("failed to get L2 blocks without row consumption: %w", err)
+	}
+	b.rowConsumptionMissingBlocks.Set(float64(len(blocks)))
+	if len(blocks) == 0 {
+		return 0, 0, nil
+	}
+
+	var backfilled int
+	var backfillErr error
+	for _, block := range blocks {
+		rowConsumption, err := b.fetchRowConsumption(block.Number, block.Hash)
+		if err == nil {
+			err = b.l2BlockOrm.UpdateRowConsumption(b.ctx, block.Number, block.Hash, rowConsumption)
+		}
+		if err != nil {
+			b.rowConsumptionBackfillFailureTotal.Inc()
+			backfillErr = fmt.Errorf("failed to backfill row consumption of block %d (%s): %w", block.Number, block.Hash, err)
+			break
+		}
+		b.rowConsumptionBackfilledTotal.Inc()
+		backfilled++
+	}
+	b.rowConsumptionMissingBlocks.Set(float64(len(blocks) - backfilled))
+	if backfilled > 0 {
+		log.Info("backfilled row consumption", "blocks", backfilled, "start block number", blocks[0].Number, "end block number", blocks[backfilled-1].Number)
+	}
+	return len(blocks), backfilled, backfillErr
+}
+
+// fetchRowConsumption queries the row consumption of the block from l2geth, falling back to the capacity checker.
+// The block returned by l2geth must match the stored hash, so that the row consumption of a reorged block isn't stored.
+func (b *RowConsumptionBackfiller) fetchRowConsumption(number uint64, hash string) (*gethTypes.RowConsumption, error) {
+	block, err := b.l2Client.GetBlockByNumberOrHash(b.ctx, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(number)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to GetBlockByNumberOrHash: %w", err)
+	}
+	if blockHash := block.Hash().String(); blockHash != hash {
+		return nil, fmt.Errorf("block hash mismatch, stored: %v, l2geth: %v", hash, blockHash)
+	}
+	if block.RowConsumption != nil {
+		return block.RowConsumption, nil
+	}
+
+	if b.capacityCheckerClient == nil {
+		return nil, errors.New("l2geth returned no row consumption and no capacity checker is configured")
+	}
+	return queryCapacityChecker(b.ctx, b.capacityCheckerClient, b.cfg.CapacityCheckerMethod, number)
+}
+
+func queryCapacityChecker(ctx context.Context, client *rpc.Client, method string, number uint64) (*gethTypes.RowConsumption, error) {
+	var rowConsumption *gethTypes.RowConsumption
+	if err := client.CallContext(ctx, &rowConsumption, method, hexutil.Uint64(number)); err != nil {
+		return nil, fmt.Errorf("failed to query capacity checker: %w", err)
+	}
+	if rowConsumption == nil {
+		return nil, errors.New("capacity checker returned no row consumption")
+	}
+	return rowConsumption, nil
+}
@@ -0,0 +1,46 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockCapacityChecker struct{}
+
+func (mockCapacityChecker) GetRowConsumptionByNumber(number hexutil.Uint64) (*gethTypes.RowConsumption, error) {
+	if number == 0 {
+		return nil, errors.New("unknown block")
+	}
+	if number == 1 {
+		return nil, nil
+	}
+	return &gethTypes.RowConsumption{{Name: "evm", RowNumber: uint64(number) * 10}}, nil
+}
+
+func TestQueryCapacityChecker(t *testing.T) {
+	server := rpc.NewServer()
+	assert.NoError(t, server.RegisterName("ccc", mockCapacityChecker{}))
+	srv := httptest.NewServer(server)
+	defer srv.Close()
+
+	client, err := rpc.Dial(srv.URL)
+	assert.NoError(t, err)
+	defer client.Close()
+
+	rowConsumption, err := queryCapacityChecker(context.Background(), client, "ccc_getRowConsumptionByNumber", 5)
+	assert.NoError(t, err)
+	assert.Equal(t, &gethTypes.RowConsumption{{Name: "evm", RowNumber: 50}}, rowConsumption)
+
+	_, err = queryCapacityChecker(context.Background(), client, "ccc_getRowConsumptionByNumber", 1)
+	assert.Error(t, err)
+
+	_, err = queryCapacityChecker(context.Background(), client, "ccc_getRowConsumptionByNumber", 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,44 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/scroll-tech/go-ethereum/log"
+)
+
+// staleChunkReasonL2Resync is the reason of the release of the chunks containing blocks deleted for a re-sync.
+const staleChunkReasonL2Resync = "l2_resync"
+
+// SyncHeight returns the height of the latest stored block, the l2 watcher resumes the import after it.
+func (w *L2WatcherClient) SyncHeight(ctx context.Context) (uint64, error) {
+	return w.l2BlockOrm.GetL2BlocksLatestHeight(ctx)
+}
+
+// SetSyncHeight moves the sync cursor of the l2 watcher back to the given height, e.g. for a partial re-sync after
+// a targeted data fix: the stored blocks above it are deleted and imported again by the next fetches. Moving the
+// cursor forward is rejected since it would leave a gap, and so is deleting blocks already included in a batch.
+func (w *L2WatcherClient) SetSyncHeight(ctx context.Context, height uint64) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	latestHeight, err := w.l2BlockOrm.GetL2BlocksLatestHeight(ctx)
+	if err != nil {
+		return err
+	}
+	if height > latestHeight {
+		return fmt.Errorf("sync height %d is above the latest stored block %d, moving it forward would leave a gap", height, latestHeight)
+	}
+	if height == latestHeight {
+		return nil
+	}
+
+	deleted, err := w.rollbackL2Blocks(ctx, height+1, staleChunkReasonL2Resync)
+	if err != nil {
+		return fmt.Errorf("failed to move the sync height back to %d: %w", height, err)
+	}
+	w.quarantinedBlock = nil
+	w.metrics.fetchRunningMissingBlocksHeight.Set(float64(height))
+	log.Warn("L2 sync height moved back", "height", height, "previousHeight", latestHeight, "deletedBlocks", deleted)
+	return nil
+}
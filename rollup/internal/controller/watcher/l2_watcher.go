@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"sync"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/scroll-tech/go-ethereum/accounts/abi"
@@ -20,6 +22,7 @@ import (
 	"scroll-tech/common/types/encoding/codecv0"
 
 	bridgeAbi "scroll-tech/rollup/abi"
+	"scroll-tech/rollup/internal/config"
 	"scroll-tech/rollup/internal/orm"
 )
 
@@ -28,9 +31,16 @@ type L2WatcherClient struct {
 	ctx context.Context
 	event.Feed
 
+	// mu serializes the block imports and the sync height changes
+	mu sync.Mutex
+
 	*ethclient.Client
 
-	l2BlockOrm *orm.L2Block
+	db            *gorm.DB
+	l2BlockOrm    *orm.L2Block
+	chunkOrm      *orm.Chunk
+	staleChunkOrm *orm.StaleChunk
+	l2MessageOrm  *orm.L2Message
 
 	confirmations rpc.BlockNumber
 
@@ -38,6 +48,28 @@ type L2WatcherClient struct {
 	messageQueueABI      *abi.ABI
 	withdrawTrieRootSlot common.Hash
 
+	// The number of blocks inserted at once, and of the concurrent block requests
+	blocksFetchLimit uint64
+	fetchParallelism int
+	// The number of fetched ranges buffered ahead of their insertion, 0 to fetch and insert sequentially
+	prefetchDepth int
+
+	// The quarantine of the blocks which repeatedly fail to be imported, disabled if not set
+	quarantineCfg        *config.L2BlockQuarantineConfig
+	l2BlockQuarantineOrm *orm.L2BlockQuarantine
+	failedBlockNumber    uint64
+	failedBlockAttempts  uint64
+	failedBlockAt        time.Time
+	// The quarantine of the next block to import, if any
+	quarantinedBlock *orm.L2BlockQuarantine
+
+	// The indexing of the L2 to L1 messages, disabled if not set
+	messageIndexCfg *config.L2MessageIndexConfig
+
+	// The node the fetched blocks are cross-checked against, disabled if not set
+	crossCheckClient       l2CrossCheckClient
+	onCrossCheckDivergence func()
+
 	metrics *l2WatcherMetrics
 }
 
@@ -47,7 +79,13 @@ func NewL2WatcherClient(ctx context.Context, client *ethclient.Client, confirmat
 		ctx:    ctx,
 		Client: client,
 
-		l2BlockOrm: orm.NewL2Block(db),
+		db:            db,
+		l2BlockOrm:    orm.NewL2Block(db),
+		chunkOrm:      orm.NewChunk(db),
+		staleChunkOrm: orm.NewStaleChunk(db),
+		l2MessageOrm:  orm.NewL2Message(db),
+
+		l2BlockQuarantineOrm: orm.NewL2BlockQuarantine(db),
 
 		confirmations: confirmations,
 
@@ -55,24 +93,57 @@ func NewL2WatcherClient(ctx context.Context, client *ethclient.Client, confirmat
 		messageQueueABI:      bridgeAbi.L2MessageQueueABI,
 		withdrawTrieRootSlot: withdrawTrieRootSlot,
 
+		blocksFetchLimit: defaultBlocksFetchLimit,
+		fetchParallelism: 1,
+
 		metrics: initL2WatcherMetrics(reg),
 	}
 }
 
-const blocksFetchLimit = uint64(10)
+const defaultBlocksFetchLimit = uint64(10)
+
+// SetBlockFetchConfig sets the number of blocks inserted at once, the number of concurrent block requests and the
+// number of ranges prefetched ahead of their insertion. The blocks are fetched one at a time by 10 if not set.
+func (w *L2WatcherClient) SetBlockFetchConfig(cfg *config.L2BlockFetchConfig) {
+	w.blocksFetchLimit = defaultBlocksFetchLimit
+	w.fetchParallelism = 1
+	w.prefetchDepth = 0
+	if cfg == nil {
+		return
+	}
+	w.prefetchDepth = cfg.PrefetchDepth
+	if cfg.Parallelism > 1 {
+		w.fetchParallelism = cfg.Parallelism
+	}
+	if cfg.BlocksPerInsert > 0 {
+		w.blocksFetchLimit = cfg.BlocksPerInsert
+	}
+}
 
 // TryFetchRunningMissingBlocks attempts to fetch and store block traces for any missing blocks.
 func (w *L2WatcherClient) TryFetchRunningMissingBlocks(blockHeight uint64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
 	w.metrics.fetchRunningMissingBlocksTotal.Inc()
 	heightInDB, err := w.l2BlockOrm.GetL2BlocksLatestHeight(w.ctx)
 	if err != nil {
 		log.Error("failed to GetL2BlocksLatestHeight", "err", err)
 		return
 	}
+	if !w.checkQuarantine(heightInDB + 1) {
+		return
+	}
+
+	// the fetcher goroutine reads the quarantined block, which is released by the insertion
+	if w.prefetchDepth > 0 && w.quarantinedBlock == nil {
+		w.fetchAndStoreBlocksPipelined(heightInDB+1, blockHeight)
+		return
+	}
 
 	// Fetch and store block traces for missing blocks
-	for from := heightInDB + 1; from <= blockHeight; from += blocksFetchLimit {
-		to := from + blocksFetchLimit - 1
+	for from := heightInDB + 1; from <= blockHeight; from += w.blocksFetchLimit {
+		to := from + w.blocksFetchLimit - 1
 
 		if to > blockHeight {
 			to = blockHeight
@@ -124,31 +195,29 @@ func txsToTxsData(txs gethTypes.Transactions) []*gethTypes.TransactionData {
 }
 
 func (w *L2WatcherClient) getAndStoreBlocks(ctx context.Context, from, to uint64) error {
-	var blocks []*encoding.Block
-	for number := from; number <= to; number++ {
-		log.Debug("retrieving block", "height", number)
-		block, err := w.GetBlockByNumberOrHash(ctx, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(number)))
-		if err != nil {
-			return fmt.Errorf("failed to GetBlockByNumberOrHash: %v. number: %v", err, number)
-		}
-		if block.RowConsumption == nil {
-			return fmt.Errorf("fetched block does not contain RowConsumption. number: %v", number)
-		}
-
-		log.Info("retrieved block", "height", block.Header().Number, "hash", block.Header().Hash().String())
+	blocks, fetchErr := w.getBlocks(ctx, from, to)
+	return w.storeBlocks(ctx, from, blocks, fetchErr)
+}
 
-		withdrawRoot, err3 := w.StorageAt(ctx, w.messageQueueAddress, w.withdrawTrieRootSlot, big.NewInt(int64(number)))
-		if err3 != nil {
-			return fmt.Errorf("failed to get withdrawRoot: %v. number: %v", err3, number)
+// storeBlocks stores the blocks fetched from the given height, fetchErr is the error of the first block which
+// failed to be fetched after them, if any.
+func (w *L2WatcherClient) storeBlocks(ctx context.Context, from uint64, blocks []*encoding.Block, fetchErr error) error {
+	if fetchErr != nil {
+		w.recordBlockFailure(from+uint64(len(blocks)), fetchErr)
+	}
+	if len(blocks) > 0 {
+		var err error
+		if blocks, err = w.checkL2Reorg(ctx, from, blocks); err != nil {
+			return err
+		}
+		if len(blocks) > 0 {
+			if err = w.crossCheck(ctx, blocks[len(blocks)-1]); err != nil {
+				return err
+			}
 		}
-		blocks = append(blocks, &encoding.Block{
-			Header:         block.Header(),
-			Transactions:   txsToTxsData(block.Transactions()),
-			WithdrawRoot:   common.BytesToHash(withdrawRoot),
-			RowConsumption: block.RowConsumption,
-		})
 	}
 
+	// the blocks fetched before a failure are stored, so that the next attempt resumes after them.
 	if len(blocks) > 0 {
 		for _, block := range blocks {
 			blockL1CommitCalldataSize, err := codecv0.EstimateBlockL1CommitCalldataSize(block)
@@ -157,10 +226,73 @@ func (w *L2WatcherClient) getAndStoreBlocks(ctx context.Context, from, to uint64
 			}
 			w.metrics.rollupL2BlockL1CommitCalldataSize.Set(float64(blockL1CommitCalldataSize))
 		}
-		if err := w.l2BlockOrm.InsertL2Blocks(w.ctx, blocks); err != nil {
-			return fmt.Errorf("failed to batch insert BlockTraces: %v", err)
+		messages, err := w.getL2Messages(ctx, blocks)
+		if err != nil {
+			return err
 		}
+		err = w.db.Transaction(func(dbTX *gorm.DB) error {
+			if err := w.l2BlockOrm.InsertL2Blocks(w.ctx, blocks, dbTX); err != nil {
+				return fmt.Errorf("failed to batch insert BlockTraces: %v", err)
+			}
+			return w.l2MessageOrm.InsertL2Messages(w.ctx, messages, dbTX)
+		})
+		if err != nil {
+			return err
+		}
+		w.releaseQuarantine(from, from+uint64(len(blocks))-1)
 	}
 
-	return nil
+	return fetchErr
+}
+
+// getBlocks fetches the blocks in [from, to] with up to fetchParallelism concurrent requests. It returns the
+// blocks in height order up to the first one which failed, and the error of that one.
+func (w *L2WatcherClient) getBlocks(ctx context.Context, from, to uint64) ([]*encoding.Block, error) {
+	blocks := make([]*encoding.Block, to-from+1)
+	errs := make([]error, len(blocks))
+	sem := make(chan struct{}, w.fetchParallelism)
+	var wg sync.WaitGroup
+	for number := from; number <= to; number++ {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(number uint64) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			blocks[number-from], errs[number-from] = w.getBlock(ctx, number)
+		}(number)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return blocks[:i], err
+		}
+	}
+	return blocks, nil
+}
+
+func (w *L2WatcherClient) getBlock(ctx context.Context, number uint64) (*encoding.Block, error) {
+	log.Debug("retrieving block", "height", number)
+	block, err := w.GetBlockByNumberOrHash(ctx, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(number)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to GetBlockByNumberOrHash: %v. number: %v", err, number)
+	}
+	if block.RowConsumption == nil && !w.importWithoutRowConsumption(number) {
+		return nil, fmt.Errorf("fetched block does not contain RowConsumption. number: %v", number)
+	}
+
+	log.Info("retrieved block", "height", block.Header().Number, "hash", block.Header().Hash().String())
+
+	withdrawRoot, err3 := w.StorageAt(ctx, w.messageQueueAddress, w.withdrawTrieRootSlot, big.NewInt(int64(number)))
+	if err3 != nil {
+		return nil, fmt.Errorf("failed to get withdrawRoot: %v. number: %v", err3, number)
+	}
+	return &encoding.Block{
+		Header:         block.Header(),
+		Transactions:   txsToTxsData(block.Transactions()),
+		WithdrawRoot:   common.BytesToHash(withdrawRoot),
+		RowConsumption: block.RowConsumption,
+	}, nil
 }
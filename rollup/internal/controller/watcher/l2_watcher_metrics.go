@@ -8,10 +8,17 @@ import (
 )
 
 type l2WatcherMetrics struct {
-	fetchRunningMissingBlocksTotal    prometheus.Counter
-	fetchRunningMissingBlocksHeight   prometheus.Gauge
-	rollupL2BlocksFetchedGap          prometheus.Gauge
-	rollupL2BlockL1CommitCalldataSize prometheus.Gauge
+	fetchRunningMissingBlocksTotal     prometheus.Counter
+	fetchRunningMissingBlocksHeight    prometheus.Gauge
+	rollupL2BlocksFetchedGap           prometheus.Gauge
+	rollupL2BlockL1CommitCalldataSize  prometheus.Gauge
+	l2WatcherReorgTotal                prometheus.Counter
+	l2WatcherReorgDepth                prometheus.Gauge
+	l2WatcherBlockImportFailureTotal   prometheus.Counter
+	l2WatcherQuarantinedBlockTotal     prometheus.Counter
+	l2WatcherQuarantinedBlock          prometheus.Gauge
+	l2WatcherCrossCheckDivergenceTotal prometheus.Counter
+	l2WatcherCrossCheckSkippedTotal    prometheus.Counter
 }
 
 var (
@@ -38,6 +45,34 @@ func initL2WatcherMetrics(reg prometheus.Registerer) *l2WatcherMetrics {
 				Name: "rollup_l2_block_l1_commit_calldata_size",
 				Help: "The l1 commitBatch calldata size of the l2 block",
 			}),
+			l2WatcherReorgTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_l2_watcher_reorg_total",
+				Help: "The total number of l2 reorgs rolled back by the l2 watcher",
+			}),
+			l2WatcherReorgDepth: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+				Name: "rollup_l2_watcher_reorg_depth",
+				Help: "The number of orphaned blocks deleted by the last l2 reorg rolled back by the l2 watcher",
+			}),
+			l2WatcherBlockImportFailureTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_l2_watcher_block_import_failure_total",
+				Help: "The total number of failures to fetch or validate an l2 block",
+			}),
+			l2WatcherQuarantinedBlockTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_l2_watcher_quarantined_block_total",
+				Help: "The total number of l2 blocks quarantined after repeatedly failing to be imported",
+			}),
+			l2WatcherQuarantinedBlock: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+				Name: "rollup_l2_watcher_quarantined_block",
+				Help: "The number of the quarantined l2 block blocking the import, 0 if none",
+			}),
+			l2WatcherCrossCheckDivergenceTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_l2_watcher_cross_check_divergence_total",
+				Help: "The total number of fetched l2 blocks diverging from the cross-check node",
+			}),
+			l2WatcherCrossCheckSkippedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_l2_watcher_cross_check_skipped_total",
+				Help: "The total number of l2 block cross-checks skipped as the cross-check node is unavailable",
+			}),
 		}
 	})
 	return l2WatcherMetric
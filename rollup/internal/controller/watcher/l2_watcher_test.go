@@ -14,6 +14,7 @@ import (
 	"scroll-tech/common/database"
 	cutils "scroll-tech/common/utils"
 
+	"scroll-tech/rollup/internal/config"
 	"scroll-tech/rollup/internal/orm"
 )
 
@@ -42,6 +43,82 @@ func testFetchRunningMissingBlocks(t *testing.T) {
 	assert.True(t, ok)
 }
 
+func testFetchRunningMissingBlocksConcurrently(t *testing.T) {
+	_, db := setupL2Watcher(t)
+	defer database.CloseDB(db)
+
+	l2BlockOrm := orm.NewL2Block(db)
+	ok := cutils.TryTimes(10, func() bool {
+		latestHeight, err := l2Cli.BlockNumber(context.Background())
+		if err != nil {
+			return false
+		}
+		wc := prepareWatcherClient(l2Cli, db)
+		wc.SetBlockFetchConfig(&config.L2BlockFetchConfig{Parallelism: 4, BlocksPerInsert: 3})
+		wc.TryFetchRunningMissingBlocks(latestHeight)
+		fetchedHeight, err := l2BlockOrm.GetL2BlocksLatestHeight(context.Background())
+		if err != nil || fetchedHeight != latestHeight {
+			return false
+		}
+		// the blocks are inserted in height order, without gaps.
+		blocks, err := l2BlockOrm.GetL2BlocksInRange(context.Background(), 1, latestHeight)
+		return err == nil && uint64(len(blocks)) == latestHeight
+	})
+	assert.True(t, ok)
+}
+
+func testFetchRunningMissingBlocksPipelined(t *testing.T) {
+	_, db := setupL2Watcher(t)
+	defer database.CloseDB(db)
+
+	l2BlockOrm := orm.NewL2Block(db)
+	ok := cutils.TryTimes(10, func() bool {
+		latestHeight, err := l2Cli.BlockNumber(context.Background())
+		if err != nil {
+			return false
+		}
+		wc := prepareWatcherClient(l2Cli, db)
+		wc.SetBlockFetchConfig(&config.L2BlockFetchConfig{Parallelism: 2, BlocksPerInsert: 2, PrefetchDepth: 2})
+		wc.TryFetchRunningMissingBlocks(latestHeight)
+		fetchedHeight, err := l2BlockOrm.GetL2BlocksLatestHeight(context.Background())
+		if err != nil || fetchedHeight != latestHeight {
+			return false
+		}
+		// the prefetched ranges are inserted in height order, without gaps.
+		blocks, err := l2BlockOrm.GetL2BlocksInRange(context.Background(), 1, latestHeight)
+		return err == nil && uint64(len(blocks)) == latestHeight
+	})
+	assert.True(t, ok)
+}
+
+func testSetL2SyncHeight(t *testing.T) {
+	_, db := setupL2Watcher(t)
+	defer database.CloseDB(db)
+
+	l2BlockOrm := orm.NewL2Block(db)
+	wc := prepareWatcherClient(l2Cli, db)
+	latestHeight, err := l2Cli.BlockNumber(context.Background())
+	assert.NoError(t, err)
+	wc.TryFetchRunningMissingBlocks(latestHeight)
+	fetchedHeight, err := l2BlockOrm.GetL2BlocksLatestHeight(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, latestHeight, fetchedHeight)
+
+	// the sync height can't be moved forward.
+	assert.Error(t, wc.SetSyncHeight(context.Background(), fetchedHeight+1))
+
+	assert.NoError(t, wc.SetSyncHeight(context.Background(), 1))
+	syncHeight, err := wc.SyncHeight(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), syncHeight)
+
+	// the deleted blocks are imported again.
+	wc.TryFetchRunningMissingBlocks(latestHeight)
+	fetchedHeight, err = l2BlockOrm.GetL2BlocksLatestHeight(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, latestHeight, fetchedHeight)
+}
+
 func prepareWatcherClient(l2Cli *ethclient.Client, db *gorm.DB) *L2WatcherClient {
 	confirmations := rpc.LatestBlockNumber
 	return NewL2WatcherClient(context.Background(), l2Cli, confirmations, common.Address{}, common.Hash{}, db, nil)
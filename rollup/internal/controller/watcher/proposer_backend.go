@@ -0,0 +1,336 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types/encoding"
+
+	"scroll-tech/rollup/internal/orm"
+)
+
+// Clock provides the current time to the proposers.
+type Clock interface {
+	Now() time.Time
+}
+
+// L2BlockSource provides the L2 blocks packed by the proposers.
+type L2BlockSource interface {
+	// GetL2BlocksGEHeight returns at most limit blocks starting from height, in ascending order.
+	GetL2BlocksGEHeight(ctx context.Context, height uint64, limit int) ([]*encoding.Block, error)
+	// GetL2BlocksInRange returns the blocks in the range [startBlockNumber, endBlockNumber], in ascending order.
+	GetL2BlocksInRange(ctx context.Context, startBlockNumber uint64, endBlockNumber uint64) ([]*encoding.Block, error)
+}
+
+// ChunkStore persists the chunks proposed by the ChunkProposer.
+type ChunkStore interface {
+	// GetUnchunkedBlockHeight returns the height of the first block not included in any chunk.
+	GetUnchunkedBlockHeight(ctx context.Context) (uint64, error)
+	// GetEnforcedL1MessagesByQueueIndices returns the enforced L1 messages among the given queue indices.
+	GetEnforcedL1MessagesByQueueIndices(ctx context.Context, queueIndices []uint64) ([]*orm.L1Message, error)
+	// InsertChunk persists the chunk and links its blocks to it atomically.
+	InsertChunk(ctx context.Context, chunk *encoding.Chunk, codecVersion encoding.CodecVersion) error
+}
+
+// BatchStore persists the batches proposed by the BatchProposer.
+type BatchStore interface {
+	// GetFirstUnbatchedChunkIndex returns the index of the first chunk not included in any batch.
+	GetFirstUnbatchedChunkIndex(ctx context.Context) (uint64, error)
+	// GetChunksGEIndex returns at most limit chunks starting from index, in ascending order.
+	GetChunksGEIndex(ctx context.Context, index uint64, limit int) ([]*orm.Chunk, error)
+	// GetLatestBatch returns the latest persisted batch.
+	GetLatestBatch(ctx context.Context) (*orm.Batch, error)
+	// InsertBatch persists the batch and links its chunks to it atomically.
+	InsertBatch(ctx context.Context, batch *encoding.Batch, codecVersion encoding.CodecVersion) error
+}
+
+// EnforcedL1MessageSource provides the enforced L1 messages, i.e. the transactions submitted through the
+// censorship-resistance path of the L1 message queue. A BatchStore implementing it enables the forced inclusion
+// timeout of the batch proposer.
+type EnforcedL1MessageSource interface {
+	// GetEnforcedL1MessagesByQueueIndices returns the enforced L1 messages among the given queue indices.
+	GetEnforcedL1MessagesByQueueIndices(ctx context.Context, queueIndices []uint64) ([]*orm.L1Message, error)
+}
+
+// BlobBaseFeeSource provides the latest observed L1 blob base fee. A BatchStore implementing it enables
+// the dynamic max chunk number per batch.
+type BlobBaseFeeSource interface {
+	// GetLatestBlobBaseFee returns the blob base fee of the latest L1 block.
+	GetLatestBlobBaseFee(ctx context.Context) (uint64, error)
+}
+
+// BlobBaseFeeHistorySource provides the trailing average of the observed L1 blob base fee. A BatchStore
+// implementing it enables the blob fee spike detection of the DA mode selection.
+type BlobBaseFeeHistorySource interface {
+	// GetAverageBlobBaseFee returns the average blob base fee of the latest numBlocks L1 blocks.
+	GetAverageBlobBaseFee(ctx context.Context, numBlocks uint64) (uint64, error)
+}
+
+// BaseFeeSource provides the latest observed L1 base fee. A BatchStore implementing it enables
+// the congestion-aware batch timeout on the L1 base fee.
+type BaseFeeSource interface {
+	// GetLatestBaseFee returns the base fee of the latest L1 block.
+	GetLatestBaseFee(ctx context.Context) (uint64, error)
+}
+
+// StaleChunkStore releases the unbatched chunks. A ChunkStore implementing it enables the stale chunk garbage collection.
+type StaleChunkStore interface {
+	// GetUnbatchedChunks returns the chunks not included in any batch, in ascending order.
+	GetUnbatchedChunks(ctx context.Context) ([]*orm.Chunk, error)
+	// ReleaseChunks deletes the chunks starting from the first given chunk, unlinks their blocks and records
+	// them as stale with the reason atomically. It fails if any of those chunks is already batched.
+	ReleaseChunks(ctx context.Context, chunks []*orm.Chunk, reason string) error
+}
+
+// PauseStore persists the pause state of the proposers, so that a pause survives restarts.
+// A BatchStore implementing it enables pausing the batch proposal.
+type PauseStore interface {
+	// GetProposerPause returns the pause state of the proposer, nil if it was never paused.
+	GetProposerPause(ctx context.Context, proposer string) (*orm.ProposerPause, error)
+	// SetProposerPause persists the pause state of the proposer.
+	SetProposerPause(ctx context.Context, proposer string, paused bool, reason string) error
+}
+
+// StateStore persists the state snapshots of the proposers, so that their in-memory state survives restarts.
+// A ChunkStore or a BatchStore implementing it enables the state snapshots of the proposer.
+type StateStore interface {
+	// GetProposerState returns the state snapshot of the proposer, nil if it was never snapshotted.
+	GetProposerState(ctx context.Context, proposer string) (*orm.ProposerState, error)
+	// SetProposerState persists the state snapshot of the proposer, replacing the previous one.
+	SetProposerState(ctx context.Context, proposer string, state string) error
+}
+
+// systemClock is the Clock backed by the system time.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// DBStore is the storage backend of the proposers backed by the rollup database, serving the L2 blocks
+// and persisting both the chunks and the batches. The storage interfaces only cover the proposers, the
+// relayers and the watchers use the orm package directly.
+type DBStore struct {
+	*dbChunkStore
+	*dbBatchStore
+	l2BlockOrm *orm.L2Block
+}
+
+// NewDBStore creates a new DBStore instance.
+func NewDBStore(db *gorm.DB) *DBStore {
+	return &DBStore{
+		dbChunkStore: newDBChunkStore(db),
+		dbBatchStore: newDBBatchStore(db),
+		l2BlockOrm:   orm.NewL2Block(db),
+	}
+}
+
+// GetL2BlocksGEHeight returns at most limit blocks starting from height, in ascending order.
+func (s *DBStore) GetL2BlocksGEHeight(ctx context.Context, height uint64, limit int) ([]*encoding.Block, error) {
+	return s.l2BlockOrm.GetL2BlocksGEHeight(ctx, height, limit)
+}
+
+// GetL2BlocksInRange returns the blocks in the range [startBlockNumber, endBlockNumber], in ascending order.
+func (s *DBStore) GetL2BlocksInRange(ctx context.Context, startBlockNumber uint64, endBlockNumber uint64) ([]*encoding.Block, error) {
+	return s.l2BlockOrm.GetL2BlocksInRange(ctx, startBlockNumber, endBlockNumber)
+}
+
+// GetEnforcedL1MessagesByQueueIndices returns the enforced L1 messages among the given queue indices.
+func (s *DBStore) GetEnforcedL1MessagesByQueueIndices(ctx context.Context, queueIndices []uint64) ([]*orm.L1Message, error) {
+	return s.dbChunkStore.GetEnforcedL1MessagesByQueueIndices(ctx, queueIndices)
+}
+
+// GetProposerState returns the state snapshot of the proposer, nil if it was never snapshotted.
+func (s *DBStore) GetProposerState(ctx context.Context, proposer string) (*orm.ProposerState, error) {
+	return s.dbChunkStore.GetProposerState(ctx, proposer)
+}
+
+// SetProposerState persists the state snapshot of the proposer, replacing the previous one.
+func (s *DBStore) SetProposerState(ctx context.Context, proposer string, state string) error {
+	return s.dbChunkStore.SetProposerState(ctx, proposer, state)
+}
+
+// InsertL2Blocks persists the L2 blocks.
+func (s *DBStore) InsertL2Blocks(ctx context.Context, blocks []*encoding.Block) error {
+	return s.l2BlockOrm.InsertL2Blocks(ctx, blocks)
+}
+
+// dbChunkStore is the ChunkStore backed by the rollup database.
+type dbChunkStore struct {
+	db               *gorm.DB
+	chunkOrm         *orm.Chunk
+	l2BlockOrm       *orm.L2Block
+	l1MessageOrm     *orm.L1Message
+	staleChunkOrm    *orm.StaleChunk
+	proposerStateOrm *orm.ProposerState
+}
+
+func newDBChunkStore(db *gorm.DB) *dbChunkStore {
+	return &dbChunkStore{
+		db:               db,
+		chunkOrm:         orm.NewChunk(db),
+		l2BlockOrm:       orm.NewL2Block(db),
+		l1MessageOrm:     orm.NewL1Message(db),
+		staleChunkOrm:    orm.NewStaleChunk(db),
+		proposerStateOrm: orm.NewProposerState(db),
+	}
+}
+
+func (s *dbChunkStore) GetUnchunkedBlockHeight(ctx context.Context) (uint64, error) {
+	return s.chunkOrm.GetUnchunkedBlockHeight(ctx)
+}
+
+func (s *dbChunkStore) GetEnforcedL1MessagesByQueueIndices(ctx context.Context, queueIndices []uint64) ([]*orm.L1Message, error) {
+	return s.l1MessageOrm.GetEnforcedL1MessagesByQueueIndices(ctx, queueIndices)
+}
+
+func (s *dbChunkStore) GetProposerState(ctx context.Context, proposer string) (*orm.ProposerState, error) {
+	return s.proposerStateOrm.GetProposerState(ctx, proposer)
+}
+
+func (s *dbChunkStore) SetProposerState(ctx context.Context, proposer string, state string) error {
+	return s.proposerStateOrm.UpsertProposerState(ctx, proposer, state)
+}
+
+func (s *dbChunkStore) InsertChunk(ctx context.Context, chunk *encoding.Chunk, codecVersion encoding.CodecVersion) error {
+	return s.db.Transaction(func(dbTX *gorm.DB) error {
+		dbChunk, err := s.chunkOrm.InsertChunk(ctx, chunk, codecVersion, dbTX)
+		if err != nil {
+			log.Warn("ChunkProposer.InsertChunk failed", "err", err)
+			return err
+		}
+		if err := s.l2BlockOrm.UpdateChunkHashInRange(ctx, dbChunk.StartBlockNumber, dbChunk.EndBlockNumber, dbChunk.Hash, dbTX); err != nil {
+			log.Error("failed to update chunk_hash for l2_blocks", "chunk hash", dbChunk.Hash, "start block", dbChunk.StartBlockNumber, "end block", dbChunk.EndBlockNumber, "err", err)
+			return err
+		}
+		return nil
+	})
+}
+
+func (s *dbChunkStore) GetUnbatchedChunks(ctx context.Context) ([]*orm.Chunk, error) {
+	return s.chunkOrm.GetUnbatchedChunks(ctx)
+}
+
+func (s *dbChunkStore) ReleaseChunks(ctx context.Context, chunks []*orm.Chunk, reason string) error {
+	if len(chunks) == 0 {
+		return nil
+	}
+	return s.db.Transaction(func(dbTX *gorm.DB) error {
+		if _, err := s.chunkOrm.DeleteUnbatchedChunksGEIndex(ctx, chunks[0].Index, dbTX); err != nil {
+			return err
+		}
+		if err := s.l2BlockOrm.ResetChunkHashGENumber(ctx, chunks[0].StartBlockNumber, dbTX); err != nil {
+			return err
+		}
+		return s.staleChunkOrm.InsertStaleChunks(ctx, chunks, reason, dbTX)
+	})
+}
+
+// dbBatchStore is the BatchStore backed by the rollup database.
+type dbBatchStore struct {
+	db               *gorm.DB
+	batchOrm         *orm.Batch
+	chunkOrm         *orm.Chunk
+	l1BlockOrm       *orm.L1Block
+	l1MessageOrm     *orm.L1Message
+	proposerPauseOrm *orm.ProposerPause
+	proposerStateOrm *orm.ProposerState
+}
+
+func newDBBatchStore(db *gorm.DB) *dbBatchStore {
+	return &dbBatchStore{
+		db:               db,
+		batchOrm:         orm.NewBatch(db),
+		chunkOrm:         orm.NewChunk(db),
+		l1BlockOrm:       orm.NewL1Block(db),
+		l1MessageOrm:     orm.NewL1Message(db),
+		proposerPauseOrm: orm.NewProposerPause(db),
+		proposerStateOrm: orm.NewProposerState(db),
+	}
+}
+
+func (s *dbBatchStore) GetFirstUnbatchedChunkIndex(ctx context.Context) (uint64, error) {
+	return s.batchOrm.GetFirstUnbatchedChunkIndex(ctx)
+}
+
+func (s *dbBatchStore) GetChunksGEIndex(ctx context.Context, index uint64, limit int) ([]*orm.Chunk, error) {
+	return s.chunkOrm.GetChunksGEIndex(ctx, index, limit)
+}
+
+func (s *dbBatchStore) GetLatestBatch(ctx context.Context) (*orm.Batch, error) {
+	return s.batchOrm.GetLatestBatch(ctx)
+}
+
+func (s *dbBatchStore) InsertBatch(ctx context.Context, batch *encoding.Batch, codecVersion encoding.CodecVersion) error {
+	return s.db.Transaction(func(dbTX *gorm.DB) error {
+		dbBatch, dbErr := s.batchOrm.InsertBatch(ctx, batch, codecVersion, dbTX)
+		if dbErr != nil {
+			log.Warn("BatchProposer.updateBatchInfoInDB insert batch failure", "index", batch.Index, "parent hash", batch.ParentBatchHash.Hex(), "error", dbErr)
+			return dbErr
+		}
+		if dbErr = s.chunkOrm.UpdateBatchHashInRange(ctx, dbBatch.StartChunkIndex, dbBatch.EndChunkIndex, dbBatch.Hash, dbTX); dbErr != nil {
+			log.Warn("BatchProposer.UpdateBatchHashInRange update the chunk's batch hash failure", "hash", dbBatch.Hash, "error", dbErr)
+			return dbErr
+		}
+		return nil
+	})
+}
+
+func (s *dbBatchStore) GetProposerPause(ctx context.Context, proposer string) (*orm.ProposerPause, error) {
+	return s.proposerPauseOrm.GetProposerPause(ctx, proposer)
+}
+
+func (s *dbBatchStore) SetProposerPause(ctx context.Context, proposer string, paused bool, reason string) error {
+	return s.proposerPauseOrm.UpsertProposerPause(ctx, proposer, paused, reason)
+}
+
+func (s *dbBatchStore) GetProposerState(ctx context.Context, proposer string) (*orm.ProposerState, error) {
+	return s.proposerStateOrm.GetProposerState(ctx, proposer)
+}
+
+func (s *dbBatchStore) SetProposerState(ctx context.Context, proposer string, state string) error {
+	return s.proposerStateOrm.UpsertProposerState(ctx, proposer, state)
+}
+
+func (s *dbBatchStore) GetEnforcedL1MessagesByQueueIndices(ctx context.Context, queueIndices []uint64) ([]*orm.L1Message, error) {
+	return s.l1MessageOrm.GetEnforcedL1MessagesByQueueIndices(ctx, queueIndices)
+}
+
+func (s *dbBatchStore) GetLatestBlobBaseFee(ctx context.Context) (uint64, error) {
+	l1Block, err := s.getLatestL1Block(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return l1Block.BlobBaseFee, nil
+}
+
+func (s *dbBatchStore) GetAverageBlobBaseFee(ctx context.Context, numBlocks uint64) (uint64, error) {
+	return s.l1BlockOrm.GetAverageBlobBaseFee(ctx, numBlocks)
+}
+
+func (s *dbBatchStore) GetLatestBaseFee(ctx context.Context) (uint64, error) {
+	l1Block, err := s.getLatestL1Block(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return l1Block.BaseFee, nil
+}
+
+func (s *dbBatchStore) getLatestL1Block(ctx context.Context) (*orm.L1Block, error) {
+	latestL1Height, err := s.l1BlockOrm.GetLatestL1BlockHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+	l1Blocks, err := s.l1BlockOrm.GetL1Blocks(ctx, map[string]interface{}{"number": latestL1Height})
+	if err != nil {
+		return nil, err
+	}
+	if len(l1Blocks) == 0 {
+		return nil, fmt.Errorf("l1 block not found, number: %v", latestL1Height)
+	}
+	return &l1Blocks[0], nil
+}
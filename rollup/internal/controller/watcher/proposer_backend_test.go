@@ -0,0 +1,238 @@
+package watcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/types/encoding"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+)
+
+type memoryClock struct {
+	now time.Time
+}
+
+func (c *memoryClock) Now() time.Time {
+	return c.now
+}
+
+// memoryStore is an in-memory backend of the proposers, testing their behavior without a database.
+type memoryStore struct {
+	blocks  []*encoding.Block
+	chunks  []*encoding.Chunk
+	batches []*encoding.Batch
+
+	// the codec versions of the chunks inserted by the chunk proposer.
+	codecVersions map[*encoding.Chunk]encoding.CodecVersion
+	staleReasons  []string
+	// the codec versions of the batches inserted by the batch proposer.
+	batchCodecVersions []encoding.CodecVersion
+	// the queue indices of the enforced L1 messages.
+	enforcedQueueIndices map[uint64]bool
+	// the enqueue times of the enforced L1 messages, unknown if not set.
+	enqueueTimes map[uint64]uint64
+}
+
+func (s *memoryStore) GetL2BlocksGEHeight(_ context.Context, height uint64, limit int) ([]*encoding.Block, error) {
+	var blocks []*encoding.Block
+	for _, block := range s.blocks {
+		if block.Header.Number.Uint64() >= height && (limit <= 0 || len(blocks) < limit) {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks, nil
+}
+
+func (s *memoryStore) GetL2BlocksInRange(_ context.Context, startBlockNumber uint64, endBlockNumber uint64) ([]*encoding.Block, error) {
+	var blocks []*encoding.Block
+	for _, block := range s.blocks {
+		if number := block.Header.Number.Uint64(); number >= startBlockNumber && number <= endBlockNumber {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks, nil
+}
+
+func (s *memoryStore) GetUnchunkedBlockHeight(_ context.Context) (uint64, error) {
+	if len(s.chunks) == 0 {
+		return 1, nil
+	}
+	lastChunk := s.chunks[len(s.chunks)-1]
+	return lastChunk.Blocks[len(lastChunk.Blocks)-1].Header.Number.Uint64() + 1, nil
+}
+
+func (s *memoryStore) GetEnforcedL1MessagesByQueueIndices(_ context.Context, queueIndices []uint64) ([]*orm.L1Message, error) {
+	var messages []*orm.L1Message
+	for _, queueIndex := range queueIndices {
+		if s.enforcedQueueIndices[queueIndex] {
+			messages = append(messages, &orm.L1Message{QueueIndex: queueIndex, IsEnforced: true, EnqueueTime: s.enqueueTimes[queueIndex]})
+		}
+	}
+	return messages, nil
+}
+
+func (s *memoryStore) InsertChunk(_ context.Context, chunk *encoding.Chunk, codecVersion encoding.CodecVersion) error {
+	if s.codecVersions == nil {
+		s.codecVersions = make(map[*encoding.Chunk]encoding.CodecVersion)
+	}
+	s.codecVersions[chunk] = codecVersion
+	s.chunks = append(s.chunks, chunk)
+	return nil
+}
+
+func (s *memoryStore) GetUnbatchedChunks(ctx context.Context) ([]*orm.Chunk, error) {
+	index, err := s.GetFirstUnbatchedChunkIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetChunksGEIndex(ctx, index, 0)
+}
+
+func (s *memoryStore) ReleaseChunks(ctx context.Context, chunks []*orm.Chunk, reason string) error {
+	index, err := s.GetFirstUnbatchedChunkIndex(ctx)
+	if err != nil {
+		return err
+	}
+	if chunks[0].Index < index {
+		return fmt.Errorf("chunk %d is already batched", chunks[0].Index)
+	}
+	s.chunks = s.chunks[:chunks[0].Index-1]
+	for range chunks {
+		s.staleReasons = append(s.staleReasons, reason)
+	}
+	return nil
+}
+
+func (s *memoryStore) GetFirstUnbatchedChunkIndex(_ context.Context) (uint64, error) {
+	index := uint64(1)
+	for _, batch := range s.batches {
+		index += uint64(len(batch.Chunks))
+	}
+	return index, nil
+}
+
+func (s *memoryStore) GetChunksGEIndex(_ context.Context, index uint64, limit int) ([]*orm.Chunk, error) {
+	var chunks []*orm.Chunk
+	var totalL1MessagePoppedBefore uint64
+	for i, chunk := range s.chunks {
+		// index 0 is the genesis chunk
+		chunkIndex := uint64(i + 1)
+		numL1Messages := chunk.NumL1Messages(totalL1MessagePoppedBefore)
+		totalL1MessagePoppedBefore += numL1Messages
+		if chunkIndex < index || (limit > 0 && len(chunks) >= limit) {
+			continue
+		}
+		codecVersion := int16(-1)
+		if version, ok := s.codecVersions[chunk]; ok {
+			codecVersion = int16(version)
+		}
+		chunks = append(chunks, &orm.Chunk{
+			Index:            chunkIndex,
+			Hash:             fmt.Sprintf("chunk-%d", chunkIndex),
+			StartBlockNumber: chunk.Blocks[0].Header.Number.Uint64(),
+			StartBlockTime:   chunk.Blocks[0].Header.Time,
+			EndBlockNumber:   chunk.Blocks[len(chunk.Blocks)-1].Header.Number.Uint64(),
+			CodecVersion:     codecVersion,
+
+			TotalL1MessagesPoppedBefore:  totalL1MessagePoppedBefore - numL1Messages,
+			TotalL1MessagesPoppedInChunk: numL1Messages,
+		})
+	}
+	return chunks, nil
+}
+
+func (s *memoryStore) GetLatestBatch(_ context.Context) (*orm.Batch, error) {
+	// a zero batch header of the codec of the latest batch, codecv0 for the genesis batch
+	headerSize := 89
+	if n := len(s.batchCodecVersions); n > 0 && s.batchCodecVersions[n-1] == encoding.CodecV1 {
+		headerSize = 121
+	}
+	return &orm.Batch{Index: uint64(len(s.batches)), BatchHeader: make([]byte, headerSize)}, nil
+}
+
+func (s *memoryStore) InsertBatch(_ context.Context, batch *encoding.Batch, codecVersion encoding.CodecVersion) error {
+	s.batches = append(s.batches, batch)
+	s.batchCodecVersions = append(s.batchCodecVersions, codecVersion)
+	return nil
+}
+
+// newMemoryStore creates a memory store of numBlocks copies of the block trace, numbered from 1.
+func newMemoryStore(t *testing.T, numBlocks int64) *memoryStore {
+	data, err := os.ReadFile("../../../testdata/blockTrace_02.json")
+	assert.NoError(t, err)
+
+	store := &memoryStore{}
+	for i := int64(1); i <= numBlocks; i++ {
+		block := &encoding.Block{}
+		assert.NoError(t, json.Unmarshal(data, block))
+		block.Header.Number = big.NewInt(i)
+		store.blocks = append(store.blocks, block)
+	}
+	return store
+}
+
+// chunkEach puts every block of the store in its own chunk.
+func (s *memoryStore) chunkEach() {
+	for _, block := range s.blocks {
+		s.chunks = append(s.chunks, &encoding.Chunk{Blocks: []*encoding.Block{block}})
+	}
+}
+
+// newMemoryClock creates a clock at the time of the first block of the store.
+func newMemoryClock(store *memoryStore) *memoryClock {
+	return &memoryClock{now: time.Unix(int64(store.blocks[0].Header.Time), 0)}
+}
+
+// newTestChunkProposer creates a chunk proposer on the store only sealing the chunks of 10 blocks or on the chunk
+// timeout, override adjusts the configuration if not nil. A nil chainCfg schedules no fork.
+func newTestChunkProposer(store *memoryStore, clock Clock, chainCfg *params.ChainConfig, override func(cfg *config.ChunkProposerConfig)) *ChunkProposer {
+	cfg := &config.ChunkProposerConfig{
+		MaxBlockNumPerChunk:             10,
+		MaxTxNumPerChunk:                math.MaxUint64,
+		MaxL1CommitGasPerChunk:          math.MaxUint64,
+		MaxL1CommitCalldataSizePerChunk: math.MaxUint64,
+		MaxRowConsumptionPerChunk:       math.MaxUint64,
+		ChunkTimeoutSec:                 300,
+		GasCostIncreaseMultiplier:       1,
+	}
+	if override != nil {
+		override(cfg)
+	}
+	if chainCfg == nil {
+		chainCfg = &params.ChainConfig{}
+	}
+	return NewChunkProposerWithBackend(context.Background(), cfg, chainCfg, store, store, clock, nil)
+}
+
+// newTestBatchProposer creates a batch proposer on the store only sealing the batches of 10 chunks or on the batch
+// timeout, override adjusts the configuration if not nil. A nil chainCfg schedules no fork.
+func newTestBatchProposer(store interface {
+	L2BlockSource
+	BatchStore
+}, clock Clock, chainCfg *params.ChainConfig, override func(cfg *config.BatchProposerConfig)) *BatchProposer {
+	cfg := &config.BatchProposerConfig{
+		MaxChunkNumPerBatch:             10,
+		MaxL1CommitGasPerBatch:          math.MaxUint64,
+		MaxL1CommitCalldataSizePerBatch: math.MaxUint64,
+		BatchTimeoutSec:                 300,
+		GasCostIncreaseMultiplier:       1,
+	}
+	if override != nil {
+		override(cfg)
+	}
+	if chainCfg == nil {
+		chainCfg = &params.ChainConfig{}
+	}
+	return NewBatchProposerWithBackend(context.Background(), cfg, chainCfg, store, store, clock, nil)
+}
@@ -0,0 +1,91 @@
+package watcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/rollup/internal/utils"
+)
+
+// chunkProposerName identifies the chunk proposer in the StateStore.
+const chunkProposerName = "chunk_proposer"
+
+// chunkProposerStateVersion is the version of the chunk proposer state snapshot. It must be bumped whenever the
+// snapshot format or the meaning of its content changes, so that the snapshots of a previous release are discarded.
+const chunkProposerStateVersion = 1
+
+// chunkProposerState is the state snapshot of the chunk proposer. The pending blocks themselves are re-read from
+// the block source, only the work done on them is snapshotted.
+type chunkProposerState struct {
+	// Version is the chunkProposerStateVersion of the release taking the snapshot.
+	Version uint64 `json:"version"`
+	// Estimations are the cached L1 commit estimations of the most recently examined blocks.
+	Estimations []*utils.BlockEstimationSnapshot `json:"estimations"`
+}
+
+// SnapshotState persists the in-memory state of the chunk proposer, so that it can be restored by RestoreState
+// after a restart. The snapshot is taken between two proposal attempts and replaces the previous one atomically.
+// It does nothing if the chunk store doesn't persist proposer states.
+func (p *ChunkProposer) SnapshotState() {
+	if p.stateStore == nil {
+		return
+	}
+
+	p.mu.Lock()
+	// the estimations of the blocks examined by a single proposal attempt are enough to resume it.
+	limit := p.maxBlockNumPerChunk * max(p.maxChunksPerTick, 1)
+	state := &chunkProposerState{Version: chunkProposerStateVersion, Estimations: p.estimationCache.Snapshot(int(limit))}
+	p.mu.Unlock()
+
+	if err := saveProposerState(p.ctx, p.stateStore, chunkProposerName, state); err != nil {
+		log.Error("failed to snapshot chunk proposer state", "err", err)
+	}
+}
+
+// RestoreState restores the in-memory state of the chunk proposer from its last snapshot, if any.
+// A snapshot of another version is discarded. It must be called before the proposer is in use.
+func (p *ChunkProposer) RestoreState() error {
+	if p.stateStore == nil {
+		return nil
+	}
+
+	var state chunkProposerState
+	found, err := loadProposerState(p.ctx, p.stateStore, chunkProposerName, &state)
+	if err != nil || !found {
+		return err
+	}
+	if state.Version != chunkProposerStateVersion {
+		log.Warn("discarding chunk proposer state of another version", "version", state.Version, "expected", chunkProposerStateVersion)
+		return nil
+	}
+	p.estimationCache.Restore(state.Estimations)
+	log.Info("restored chunk proposer state", "estimations", len(state.Estimations))
+	return nil
+}
+
+// saveProposerState persists the JSON encoded state snapshot of the proposer.
+func saveProposerState(ctx context.Context, store StateStore, proposer string, state interface{}) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s state: %w", proposer, err)
+	}
+	return store.SetProposerState(ctx, proposer, string(data))
+}
+
+// loadProposerState decodes the last state snapshot of the proposer into state, it returns false if there is none.
+func loadProposerState(ctx context.Context, store StateStore, proposer string, state interface{}) (bool, error) {
+	proposerState, err := store.GetProposerState(ctx, proposer)
+	if err != nil {
+		return false, err
+	}
+	if proposerState == nil || proposerState.State == "" {
+		return false, nil
+	}
+	if err := json.Unmarshal([]byte(proposerState.State), state); err != nil {
+		return false, fmt.Errorf("failed to decode %s state: %w", proposer, err)
+	}
+	return true, nil
+}
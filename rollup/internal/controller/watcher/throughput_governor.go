@@ -0,0 +1,182 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rpc"
+	"gorm.io/gorm"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+)
+
+const defaultSequencerAdminMethod = "miner_setGasLimit"
+
+// ThroughputSignal is the feedback signal of the ThroughputGovernor, derived from the DA backlog and the blob fee.
+type ThroughputSignal struct {
+	UnchunkedBlocks    uint64 `json:"unchunked_blocks"`
+	UncommittedBatches uint64 `json:"uncommitted_batches"`
+	BlobBaseFee        uint64 `json:"blob_base_fee"`
+	// Pressure is the highest of the backlog and fee pressures, in [0, 1].
+	Pressure float64 `json:"pressure"`
+	// RecommendedGasLimit is the L2 block gas limit recommended to the sequencer.
+	RecommendedGasLimit uint64 `json:"recommended_gas_limit"`
+}
+
+// ThroughputGovernor derives a recommended L2 block gas limit from the chunk and batch backlog and the blob fee,
+// so that the L2 throughput can be modulated when the DA submission falls behind.
+type ThroughputGovernor struct {
+	ctx context.Context
+	cfg *config.ThroughputGovernorConfig
+
+	l2BlockOrm *orm.L2Block
+	chunkOrm   *orm.Chunk
+	batchOrm   *orm.Batch
+	l1BlockOrm *orm.L1Block
+
+	sequencerClient *rpc.Client
+
+	mu                 sync.RWMutex
+	signal             *ThroughputSignal
+	lastPushedGasLimit uint64
+
+	governorPressure            prometheus.Gauge
+	governorRecommendedGasLimit prometheus.Gauge
+	governorFailureTotal        prometheus.Counter
+}
+
+// NewThroughputGovernor creates a new ThroughputGovernor instance.
+func NewThroughputGovernor(ctx context.Context, cfg *config.ThroughputGovernorConfig, db *gorm.DB, reg prometheus.Registerer) (*ThroughputGovernor, error) {
+	g := &ThroughputGovernor{
+		ctx:        ctx,
+		cfg:        cfg,
+		l2BlockOrm: orm.NewL2Block(db),
+		chunkOrm:   orm.NewChunk(db),
+		batchOrm:   orm.NewBatch(db),
+		l1BlockOrm: orm.NewL1Block(db),
+
+		governorPressure: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_throughput_governor_pressure",
+			Help: "The DA pressure derived from the chunk and batch backlog and the blob fee, in [0, 1].",
+		}),
+		governorRecommendedGasLimit: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_throughput_governor_recommended_gas_limit",
+			Help: "The L2 block gas limit recommended to the sequencer.",
+		}),
+		governorFailureTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_throughput_governor_failure_total",
+			Help: "Total number of throughput governor update failures.",
+		}),
+	}
+
+	if cfg.SequencerAdminEndpoint != "" {
+		client, err := rpc.DialContext(ctx, cfg.SequencerAdminEndpoint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to dial sequencer admin endpoint: %w", err)
+		}
+		g.sequencerClient = client
+	}
+	return g, nil
+}
+
+// Signal returns the latest throughput signal, nil if it's not computed yet.
+func (g *ThroughputGovernor) Signal() *ThroughputSignal {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.signal
+}
+
+// Update recomputes the throughput signal and pushes the recommended gas limit to the sequencer if it changed.
+func (g *ThroughputGovernor) Update() {
+	signal, err := g.computeSignal()
+	if err != nil {
+		g.governorFailureTotal.Inc()
+		log.Error("failed to compute throughput signal", "err", err)
+		return
+	}
+
+	g.mu.Lock()
+	g.signal = signal
+	g.mu.Unlock()
+
+	g.governorPressure.Set(signal.Pressure)
+	g.governorRecommendedGasLimit.Set(float64(signal.RecommendedGasLimit))
+
+	if g.sequencerClient == nil || signal.RecommendedGasLimit == g.lastPushedGasLimit {
+		return
+	}
+
+	method := g.cfg.SequencerAdminMethod
+	if method == "" {
+		method = defaultSequencerAdminMethod
+	}
+	var result interface{}
+	if err := g.sequencerClient.CallContext(g.ctx, &result, method, hexutil.Uint64(signal.RecommendedGasLimit)); err != nil {
+		g.governorFailureTotal.Inc()
+		log.Error("failed to push recommended gas limit to sequencer", "method", method, "gas limit", signal.RecommendedGasLimit, "err", err)
+		return
+	}
+	g.lastPushedGasLimit = signal.RecommendedGasLimit
+	log.Info("pushed recommended gas limit to sequencer", "gas limit", signal.RecommendedGasLimit, "pressure", signal.Pressure,
+		"unchunked blocks", signal.UnchunkedBlocks, "uncommitted batches", signal.UncommittedBatches, "blob base fee", signal.BlobBaseFee)
+}
+
+func (g *ThroughputGovernor) computeSignal() (*ThroughputSignal, error) {
+	signal := &ThroughputSignal{}
+
+	latestHeight, err := g.l2BlockOrm.GetL2BlocksLatestHeight(g.ctx)
+	if err != nil {
+		return nil, err
+	}
+	unchunkedHeight, err := g.chunkOrm.GetUnchunkedBlockHeight(g.ctx)
+	if err != nil {
+		return nil, err
+	}
+	if latestHeight >= unchunkedHeight {
+		signal.UnchunkedBlocks = latestHeight - unchunkedHeight + 1
+	}
+
+	signal.UncommittedBatches, err = g.batchOrm.GetUncommittedBatchCount(g.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	latestL1Height, err := g.l1BlockOrm.GetLatestL1BlockHeight(g.ctx)
+	if err != nil {
+		return nil, err
+	}
+	l1Blocks, err := g.l1BlockOrm.GetL1Blocks(g.ctx, map[string]interface{}{"number": latestL1Height})
+	if err != nil {
+		return nil, err
+	}
+	if len(l1Blocks) > 0 {
+		signal.BlobBaseFee = l1Blocks[0].BlobBaseFee
+	}
+
+	signal.Pressure = math.Max(pressure(signal.UnchunkedBlocks, g.cfg.MaxUnchunkedBlocks),
+		math.Max(pressure(signal.UncommittedBatches, g.cfg.MaxUncommittedBatches), pressure(signal.BlobBaseFee, g.cfg.MaxBlobBaseFee)))
+	signal.RecommendedGasLimit = recommendGasLimit(g.cfg.BaseGasLimit, g.cfg.MinGasLimitRatio, signal.Pressure)
+	return signal, nil
+}
+
+// pressure returns value/limit capped to 1, or 0 if limit is not set.
+func pressure(value, limit uint64) float64 {
+	if limit == 0 {
+		return 0
+	}
+	return math.Min(float64(value)/float64(limit), 1)
+}
+
+// recommendGasLimit interpolates the gas limit linearly between baseGasLimit (no pressure)
+// and baseGasLimit*minRatio (full pressure).
+func recommendGasLimit(baseGasLimit uint64, minRatio float64, pressure float64) uint64 {
+	ratio := 1 - (1-minRatio)*pressure
+	return uint64(float64(baseGasLimit) * ratio)
+}
@@ -0,0 +1,17 @@
+package watcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThroughputGovernorRecommendGasLimit(t *testing.T) {
+	assert.Equal(t, float64(0), pressure(100, 0))
+	assert.Equal(t, 0.5, pressure(50, 100))
+	assert.Equal(t, float64(1), pressure(500, 100))
+
+	assert.Equal(t, uint64(10000000), recommendGasLimit(10000000, 0.25, 0))
+	assert.Equal(t, uint64(6250000), recommendGasLimit(10000000, 0.25, 0.5))
+	assert.Equal(t, uint64(2500000), recommendGasLimit(10000000, 0.25, 1))
+}
@@ -97,12 +97,17 @@ func TestFunction(t *testing.T) {
 	t.Run("TestStartWatcher", testFetchContractEvent)
 	t.Run("TestL1WatcherClientFetchBlockHeader", testL1WatcherClientFetchBlockHeader)
 	t.Run("TestL1WatcherClientFetchContractEvent", testL1WatcherClientFetchContractEvent)
+	t.Run("TestL1WatcherClientReorg", testL1WatcherClientReorg)
 	t.Run("TestParseBridgeEventLogsL1QueueTransactionEventSignature", testParseBridgeEventLogsL1QueueTransactionEventSignature)
 	t.Run("TestParseBridgeEventLogsL1CommitBatchEventSignature", testParseBridgeEventLogsL1CommitBatchEventSignature)
 	t.Run("TestParseBridgeEventLogsL1FinalizeBatchEventSignature", testParseBridgeEventLogsL1FinalizeBatchEventSignature)
+	t.Run("TestParseBridgeEventLogsL1RevertBatchEventSignature", testParseBridgeEventLogsL1RevertBatchEventSignature)
 
 	// Run l2 watcher test cases.
 	t.Run("TestFetchRunningMissingBlocks", testFetchRunningMissingBlocks)
+	t.Run("TestFetchRunningMissingBlocksConcurrently", testFetchRunningMissingBlocksConcurrently)
+	t.Run("TestFetchRunningMissingBlocksPipelined", testFetchRunningMissingBlocksPipelined)
+	t.Run("TestSetL2SyncHeight", testSetL2SyncHeight)
 
 	// Run chunk proposer test cases.
 	t.Run("TestChunkProposerCodecv0Limits", testChunkProposerCodecv0Limits)
@@ -0,0 +1,175 @@
+package featureflag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+)
+
+// Sources of the state of a feature flag.
+const (
+	SourceConfig = "config"
+	SourceDB     = "db"
+)
+
+const fullRollout = 100
+
+// Names of the feature flags gating the behaviors of the deployment.
+const (
+	// SecondaryBroadcast gates the broadcast of the sender transactions to the secondary endpoint, the subject is
+	// the name of the sender.
+	SecondaryBroadcast = "secondary_broadcast"
+)
+
+// FlagState is the effective state of a feature flag.
+type FlagState struct {
+	Name              string `json:"name"`
+	Enabled           bool   `json:"enabled"`
+	RolloutPercentage uint64 `json:"rollout_percentage"`
+	Source            string `json:"source"`
+}
+
+// Flags gates the experimental behaviors of the deployment. The configured flags are overridden by the
+// database rows, so that a risky feature can ship disabled and be enabled gradually without a restart.
+// Unknown flags are disabled, and a nil *Flags disables every flag.
+type Flags struct {
+	ctx context.Context
+
+	configured     map[string]*config.FeatureFlagConfig
+	featureFlagOrm *orm.FeatureFlag
+
+	mu     sync.RWMutex
+	states map[string]*FlagState
+
+	featureFlagRefreshFailureTotal prometheus.Counter
+}
+
+// NewFlags creates a new Flags instance and loads the database overrides.
+func NewFlags(ctx context.Context, configured map[string]*config.FeatureFlagConfig, db *gorm.DB, reg prometheus.Registerer) (*Flags, error) {
+	f := &Flags{
+		ctx:            ctx,
+		configured:     configured,
+		featureFlagOrm: orm.NewFeatureFlag(db),
+
+		featureFlagRefreshFailureTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_feature_flag_refresh_failure_total",
+			Help: "Total number of failures to load the feature flag overrides from the database.",
+		}),
+	}
+	if err := f.load(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Refresh reloads the database overrides, keeping the current states on failure.
+func (f *Flags) Refresh() {
+	if err := f.load(); err != nil {
+		f.featureFlagRefreshFailureTotal.Inc()
+		log.Error("failed to refresh feature flags", "err", err)
+	}
+}
+
+// Enabled returns whether the flag is enabled for the subject. The subject is the unit of the
+// percentage rollout (e.g. a batch hash), a given subject always gets the same result for a given percentage.
+func (f *Flags) Enabled(name string, subject string) bool {
+	if f == nil {
+		return false
+	}
+
+	f.mu.RLock()
+	state, ok := f.states[name]
+	f.mu.RUnlock()
+	if !ok || !state.Enabled {
+		return false
+	}
+	if state.RolloutPercentage >= fullRollout {
+		return true
+	}
+	return rolloutBucket(name, subject) < state.RolloutPercentage
+}
+
+// States returns the effective states of all the flags, ordered by name.
+func (f *Flags) States() []*FlagState {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	states := make([]*FlagState, 0, len(f.states))
+	for _, state := range f.states {
+		stateCopy := *state
+		states = append(states, &stateCopy)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Name < states[j].Name
+	})
+	return states
+}
+
+// Set overrides the state of the flag in the database.
+func (f *Flags) Set(ctx context.Context, name string, enabled bool, rolloutPercentage uint64) error {
+	if name == "" {
+		return errors.New("empty feature flag name")
+	}
+	if rolloutPercentage > fullRollout {
+		return fmt.Errorf("invalid rollout percentage: %v", rolloutPercentage)
+	}
+	if err := f.featureFlagOrm.UpsertFeatureFlag(ctx, name, enabled, rolloutPercentage); err != nil {
+		return err
+	}
+	log.Info("feature flag overridden", "name", name, "enabled", enabled, "rollout percentage", rolloutPercentage)
+	return f.load()
+}
+
+// Reset removes the database override of the flag, falling back to the configured state.
+func (f *Flags) Reset(ctx context.Context, name string) error {
+	if err := f.featureFlagOrm.DeleteFeatureFlag(ctx, name); err != nil {
+		return err
+	}
+	log.Info("feature flag override removed", "name", name)
+	return f.load()
+}
+
+func (f *Flags) load() error {
+	overrides, err := f.featureFlagOrm.GetFeatureFlags(f.ctx)
+	if err != nil {
+		return err
+	}
+
+	states := make(map[string]*FlagState, len(f.configured)+len(overrides))
+	for name, flagCfg := range f.configured {
+		rolloutPercentage := uint64(fullRollout)
+		if flagCfg.RolloutPercentage != nil {
+			rolloutPercentage = *flagCfg.RolloutPercentage
+		}
+		states[name] = &FlagState{Name: name, Enabled: flagCfg.Enabled, RolloutPercentage: rolloutPercentage, Source: SourceConfig}
+	}
+	for _, override := range overrides {
+		states[override.Name] = &FlagState{Name: override.Name, Enabled: override.Enabled, RolloutPercentage: override.RolloutPercentage, Source: SourceDB}
+	}
+
+	f.mu.Lock()
+	f.states = states
+	f.mu.Unlock()
+	return nil
+}
+
+// rolloutBucket maps the subject to a stable bucket in [0, 100), salted by the flag name
+// so that the rollouts of different flags are independent.
+func rolloutBucket(name string, subject string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(name))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(subject))
+	return h.Sum64() % fullRollout
+}
@@ -0,0 +1,41 @@
+package featureflag
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlagsEnabled(t *testing.T) {
+	var nilFlags *Flags
+	assert.False(t, nilFlags.Enabled("flag", "subject"))
+
+	f := &Flags{states: map[string]*FlagState{
+		"enabled":  {Name: "enabled", Enabled: true, RolloutPercentage: 100, Source: SourceConfig},
+		"disabled": {Name: "disabled", Enabled: false, RolloutPercentage: 100, Source: SourceDB},
+		"rollout":  {Name: "rollout", Enabled: true, RolloutPercentage: 30, Source: SourceDB},
+		"none":     {Name: "none", Enabled: true, RolloutPercentage: 0, Source: SourceDB},
+	}}
+	assert.True(t, f.Enabled("enabled", ""))
+	assert.False(t, f.Enabled("disabled", ""))
+	assert.False(t, f.Enabled("unknown", ""))
+	assert.False(t, f.Enabled("none", "subject"))
+
+	var enabledCount int
+	for i := 0; i < 10000; i++ {
+		subject := fmt.Sprintf("subject-%d", i)
+		enabled := f.Enabled("rollout", subject)
+		// a subject always gets the same result.
+		assert.Equal(t, enabled, f.Enabled("rollout", subject))
+		if enabled {
+			enabledCount++
+		}
+	}
+	assert.InDelta(t, 3000, enabledCount, 300)
+
+	states := f.States()
+	assert.Len(t, states, 4)
+	assert.Equal(t, "disabled", states[0].Name)
+	assert.Equal(t, "rollout", states[3].Name)
+}
@@ -7,12 +7,14 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/log"
 	"gorm.io/gorm"
 
 	"scroll-tech/common/types"
 	"scroll-tech/common/types/encoding"
 	"scroll-tech/common/types/message"
+	"scroll-tech/common/types/schema"
 	"scroll-tech/common/utils"
 
 	rutils "scroll-tech/rollup/internal/utils"
@@ -49,6 +51,10 @@ type Batch struct {
 	CommittedAt    *time.Time `json:"committed_at" gorm:"column:committed_at;default:NULL"`
 	FinalizeTxHash string     `json:"finalize_tx_hash" gorm:"column:finalize_tx_hash;default:NULL"`
 	FinalizedAt    *time.Time `json:"finalized_at" gorm:"column:finalized_at;default:NULL"`
+	// CommitL1BlockNumber is the number of the L1 block including the commit transaction, 0 if unknown.
+	CommitL1BlockNumber uint64 `json:"commit_l1_block_number" gorm:"column:commit_l1_block_number;default:0"`
+	// FinalizeL1BlockNumber is the number of the L1 block including the finalize transaction, 0 if unknown.
+	FinalizeL1BlockNumber uint64 `json:"finalize_l1_block_number" gorm:"column:finalize_l1_block_number;default:0"`
 
 	// gas oracle
 	OracleStatus int16  `json:"oracle_status" gorm:"column:oracle_status;default:1"`
@@ -57,6 +63,8 @@ type Batch struct {
 	// blob
 	BlobDataProof []byte `json:"blob_data_proof" gorm:"column:blob_data_proof"`
 	BlobSize      uint64 `json:"blob_size" gorm:"column:blob_size"`
+	// BlobVersionedHashes is the json array of the versioned hashes of the blobs of the commit transaction.
+	BlobVersionedHashes string `json:"blob_versioned_hashes" gorm:"column:blob_versioned_hashes;default:NULL"`
 
 	// metadata
 	TotalL1CommitGas          uint64         `json:"total_l1_commit_gas" gorm:"column:total_l1_commit_gas;default:0"`
@@ -76,6 +84,33 @@ func (*Batch) TableName() string {
 	return "batch"
 }
 
+// ToSchema converts the batch to its canonical schema.
+func (o *Batch) ToSchema() *schema.Batch {
+	return &schema.Batch{
+		SchemaVersion:             schema.Version,
+		Index:                     o.Index,
+		Hash:                      o.Hash,
+		DataHash:                  o.DataHash,
+		StartChunkIndex:           o.StartChunkIndex,
+		StartChunkHash:            o.StartChunkHash,
+		EndChunkIndex:             o.EndChunkIndex,
+		EndChunkHash:              o.EndChunkHash,
+		StateRoot:                 o.StateRoot,
+		WithdrawRoot:              o.WithdrawRoot,
+		ParentBatchHash:           o.ParentBatchHash,
+		BatchHeader:               o.BatchHeader,
+		TotalL1CommitCalldataSize: o.TotalL1CommitCalldataSize,
+		TotalL1CommitGas:          o.TotalL1CommitGas,
+		BlobSize:                  o.BlobSize,
+		ChunkProofsStatus:         int32(o.ChunkProofsStatus),
+		ProvingStatus:             int32(o.ProvingStatus),
+		RollupStatus:              int32(o.RollupStatus),
+		CommitTxHash:              o.CommitTxHash,
+		FinalizeTxHash:            o.FinalizeTxHash,
+		CreatedAt:                 o.CreatedAt.Unix(),
+	}
+}
+
 // GetBatches retrieves selected batches from the database.
 // The returned batches are sorted in ascending order by their index.
 func (o *Batch) GetBatches(ctx context.Context, fields map[string]interface{}, orderByList []string, limit int) ([]*Batch, error) {
@@ -115,6 +150,32 @@ func (o *Batch) GetBatchCount(ctx context.Context) (uint64, error) {
 	return uint64(count), nil
 }
 
+// GetUncommittedBatchCount retrieves the number of batches not committed to L1 yet.
+func (o *Batch) GetUncommittedBatchCount(ctx context.Context) (uint64, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("rollup_status IN ?", []types.RollupStatus{types.RollupPending, types.RollupCommitting, types.RollupCommitFailed})
+
+	var count int64
+	if err := db.Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("Batch.GetUncommittedBatchCount error: %w", err)
+	}
+	return uint64(count), nil
+}
+
+// GetCommittingBatchCount retrieves the number of batches whose commit transaction is sent but not confirmed yet.
+func (o *Batch) GetCommittingBatchCount(ctx context.Context) (uint64, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("rollup_status = ?", types.RollupCommitting)
+
+	var count int64
+	if err := db.Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("Batch.GetCommittingBatchCount error: %w", err)
+	}
+	return uint64(count), nil
+}
+
 // GetVerifiedProofByHash retrieves the verified aggregate proof for a batch with the given hash.
 func (o *Batch) GetVerifiedProofByHash(ctx context.Context, hash string) (*message.BatchProof, error) {
 	db := o.db.WithContext(ctx)
@@ -147,6 +208,23 @@ func (o *Batch) GetLatestBatch(ctx context.Context) (*Batch, error) {
 	return &latestBatch, nil
 }
 
+// GetLatestFinalizedBatch retrieves the finalized batch with the highest index, nil if no batch is finalized.
+func (o *Batch) GetLatestFinalizedBatch(ctx context.Context) (*Batch, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("rollup_status = ?", int(types.RollupFinalized))
+	db = db.Order("index desc")
+
+	var batch Batch
+	if err := db.First(&batch).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Batch.GetLatestFinalizedBatch error: %w", err)
+	}
+	return &batch, nil
+}
+
 // GetFirstUnbatchedChunkIndex retrieves the first unbatched chunk index.
 func (o *Batch) GetFirstUnbatchedChunkIndex(ctx context.Context) (uint64, error) {
 	// Get the latest batch
@@ -223,6 +301,34 @@ func (o *Batch) GetBatchByIndex(ctx context.Context, index uint64) (*Batch, erro
 	return &batch, nil
 }
 
+// GetBatchByHash retrieves the batch by the given hash.
+func (o *Batch) GetBatchByHash(ctx context.Context, hash string) (*Batch, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("hash = ?", hash)
+
+	var batch Batch
+	if err := db.First(&batch).Error; err != nil {
+		return nil, fmt.Errorf("Batch.GetBatchByHash error: %w, batch hash: %v", err, hash)
+	}
+	return &batch, nil
+}
+
+// GetDeletedBatchCountByHash returns the number of deleted batches with the given hash,
+// i.e. how many times a batch with the same content was dissolved.
+func (o *Batch) GetDeletedBatchCountByHash(ctx context.Context, hash string) (uint64, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Unscoped()
+	db = db.Model(&Batch{})
+	db = db.Where("hash = ? AND deleted_at IS NOT NULL", hash)
+
+	var count int64
+	if err := db.Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("Batch.GetDeletedBatchCountByHash error: %w, batch hash: %v", err, hash)
+	}
+	return uint64(count), nil
+}
+
 // InsertBatch inserts a new batch into the database.
 func (o *Batch) InsertBatch(ctx context.Context, batch *encoding.Batch, codecVersion encoding.CodecVersion, dbTX ...*gorm.DB) (*Batch, error) {
 	if batch == nil {
@@ -234,6 +340,44 @@ func (o *Batch) InsertBatch(ctx context.Context, batch *encoding.Batch, codecVer
 		return nil, errors.New("invalid args: batch contains 0 chunk")
 	}
 
+	var parentBatch *Batch
+	if batch.Index > 0 {
+		var getErr error
+		parentBatch, getErr = o.GetBatchByIndex(ctx, batch.Index-1)
+		if getErr != nil {
+			log.Error("failed to get batch by index", "index", batch.Index, "total l1 message popped before", batch.TotalL1MessagePoppedBefore,
+				"parent hash", batch.ParentBatchHash, "number of chunks", numChunks, "err", getErr)
+			return nil, fmt.Errorf("Batch.InsertBatch error: %w", getErr)
+		}
+	}
+
+	newBatch, err := NewBatchRecord(batch, codecVersion, parentBatch)
+	if err != nil {
+		return nil, fmt.Errorf("Batch.InsertBatch error: %w", err)
+	}
+
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db.WithContext(ctx)
+	db = db.Model(&Batch{})
+
+	if err := db.Create(newBatch).Error; err != nil {
+		log.Error("failed to insert batch", "batch", newBatch, "err", err)
+		return nil, fmt.Errorf("Batch.InsertBatch error: %w", err)
+	}
+	return newBatch, nil
+}
+
+// NewBatchRecord builds the record of a batch following parentBatch, nil if it is the genesis batch.
+// It is shared by the storage backends so that they derive the same batch fields.
+func NewBatchRecord(batch *encoding.Batch, codecVersion encoding.CodecVersion, parentBatch *Batch) (*Batch, error) {
+	numChunks := uint64(len(batch.Chunks))
+	if numChunks == 0 {
+		return nil, errors.New("invalid args: batch contains 0 chunk")
+	}
+
 	metrics, err := rutils.CalculateBatchMetrics(batch, codecVersion)
 	if err != nil {
 		log.Error("failed to calculate batch metrics",
@@ -242,13 +386,7 @@ func (o *Batch) InsertBatch(ctx context.Context, batch *encoding.Batch, codecVer
 	}
 
 	var startChunkIndex uint64
-	if batch.Index > 0 {
-		parentBatch, getErr := o.GetBatchByIndex(ctx, batch.Index-1)
-		if getErr != nil {
-			log.Error("failed to get batch by index", "index", batch.Index, "total l1 message popped before", batch.TotalL1MessagePoppedBefore,
-				"parent hash", batch.ParentBatchHash, "number of chunks", numChunks, "err", getErr)
-			return nil, fmt.Errorf("Batch.InsertBatch error: %w", getErr)
-		}
+	if parentBatch != nil {
 		startChunkIndex = parentBatch.EndChunkIndex + 1
 	}
 
@@ -256,10 +394,10 @@ func (o *Batch) InsertBatch(ctx context.Context, batch *encoding.Batch, codecVer
 	if err != nil {
 		log.Error("failed to get batch metadata", "index", batch.Index, "total l1 message popped before", batch.TotalL1MessagePoppedBefore,
 			"parent hash", batch.ParentBatchHash, "number of chunks", numChunks, "err", err)
-		return nil, fmt.Errorf("Batch.InsertBatch error: %w", err)
+		return nil, err
 	}
 
-	newBatch := Batch{
+	return &Batch{
 		Index:                     batch.Index,
 		Hash:                      batchMeta.BatchHash.Hex(),
 		DataHash:                  batchMeta.BatchDataHash.Hex(),
@@ -279,20 +417,7 @@ func (o *Batch) InsertBatch(ctx context.Context, batch *encoding.Batch, codecVer
 		TotalL1CommitCalldataSize: metrics.L1CommitCalldataSize,
 		BlobDataProof:             batchMeta.BatchBlobDataProof,
 		BlobSize:                  metrics.L1CommitBlobSize,
-	}
-
-	db := o.db
-	if len(dbTX) > 0 && dbTX[0] != nil {
-		db = dbTX[0]
-	}
-	db.WithContext(ctx)
-	db = db.Model(&Batch{})
-
-	if err := db.Create(&newBatch).Error; err != nil {
-		log.Error("failed to insert batch", "batch", newBatch, "err", err)
-		return nil, fmt.Errorf("Batch.InsertBatch error: %w", err)
-	}
-	return &newBatch, nil
+	}, nil
 }
 
 // UpdateL2GasOracleStatusAndOracleTxHash updates the L2 gas oracle status and transaction hash for a batch.
@@ -365,6 +490,36 @@ func (o *Batch) UpdateRollupStatus(ctx context.Context, hash string, status type
 	return nil
 }
 
+// UpdateBlobVersionedHashes records the versioned hashes of the blobs carried by the commit transaction of a batch.
+func (o *Batch) UpdateBlobVersionedHashes(ctx context.Context, hash string, versionedHashes []common.Hash) error {
+	encoded, err := json.Marshal(versionedHashes)
+	if err != nil {
+		return fmt.Errorf("Batch.UpdateBlobVersionedHashes error: %w, batch hash: %v", err, hash)
+	}
+
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("hash", hash)
+
+	if err := db.Update("blob_versioned_hashes", string(encoded)).Error; err != nil {
+		return fmt.Errorf("Batch.UpdateBlobVersionedHashes error: %w, batch hash: %v", err, hash)
+	}
+	return nil
+}
+
+// GetBlobVersionedHashes returns the recorded versioned hashes of the blobs of the commit transaction,
+// nil if the batch is committed without blobs or before the hashes are recorded.
+func (o *Batch) GetBlobVersionedHashes() ([]common.Hash, error) {
+	if o.BlobVersionedHashes == "" {
+		return nil, nil
+	}
+	var versionedHashes []common.Hash
+	if err := json.Unmarshal([]byte(o.BlobVersionedHashes), &versionedHashes); err != nil {
+		return nil, fmt.Errorf("Batch.GetBlobVersionedHashes error: %w, batch hash: %v", err, o.Hash)
+	}
+	return versionedHashes, nil
+}
+
 // UpdateCommitTxHashAndRollupStatus updates the commit transaction hash and rollup status for a batch.
 func (o *Batch) UpdateCommitTxHashAndRollupStatus(ctx context.Context, hash string, commitTxHash string, status types.RollupStatus) error {
 	updateFields := make(map[string]interface{})
@@ -384,6 +539,55 @@ func (o *Batch) UpdateCommitTxHashAndRollupStatus(ctx context.Context, hash stri
 	return nil
 }
 
+// UpdateCommittedBatch marks a batch as committed by the given transaction, included in the given L1 block.
+func (o *Batch) UpdateCommittedBatch(ctx context.Context, hash string, commitTxHash string, l1BlockNumber uint64) error {
+	updateFields := map[string]interface{}{
+		"commit_tx_hash":         commitTxHash,
+		"rollup_status":          int(types.RollupCommitted),
+		"committed_at":           utils.NowUTC(),
+		"commit_l1_block_number": l1BlockNumber,
+	}
+
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("hash", hash)
+
+	if err := db.Updates(updateFields).Error; err != nil {
+		return fmt.Errorf("Batch.UpdateCommittedBatch error: %w, batch hash: %v, commitTxHash: %v, l1 block number: %v", err, hash, commitTxHash, l1BlockNumber)
+	}
+	return nil
+}
+
+// FillCommitL1BlockNumber sets the number of the L1 block including the commit transaction of a batch, if it isn't
+// known yet.
+func (o *Batch) FillCommitL1BlockNumber(ctx context.Context, hash string, l1BlockNumber uint64) error {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("hash", hash)
+	db = db.Where("commit_l1_block_number = 0")
+
+	if err := db.Update("commit_l1_block_number", l1BlockNumber).Error; err != nil {
+		return fmt.Errorf("Batch.FillCommitL1BlockNumber error: %w, batch hash: %v, l1 block number: %v", err, hash, l1BlockNumber)
+	}
+	return nil
+}
+
+// UpdateCommitL1BlockNumber updates the number of the L1 block including the commit transaction of a batch.
+func (o *Batch) UpdateCommitL1BlockNumber(ctx context.Context, hash string, l1BlockNumber uint64, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("hash", hash)
+
+	if err := db.Update("commit_l1_block_number", l1BlockNumber).Error; err != nil {
+		return fmt.Errorf("Batch.UpdateCommitL1BlockNumber error: %w, batch hash: %v, l1 block number: %v", err, hash, l1BlockNumber)
+	}
+	return nil
+}
+
 // UpdateFinalizeTxHashAndRollupStatus updates the finalize transaction hash and rollup status for a batch.
 func (o *Batch) UpdateFinalizeTxHashAndRollupStatus(ctx context.Context, hash string, finalizeTxHash string, status types.RollupStatus) error {
 	updateFields := make(map[string]interface{})
@@ -403,6 +607,129 @@ func (o *Batch) UpdateFinalizeTxHashAndRollupStatus(ctx context.Context, hash st
 	return nil
 }
 
+// UpdateFinalizedBatch marks a batch as finalized by the given transaction, included in the given L1 block.
+func (o *Batch) UpdateFinalizedBatch(ctx context.Context, hash string, finalizeTxHash string, l1BlockNumber uint64) error {
+	updateFields := map[string]interface{}{
+		"finalize_tx_hash":         finalizeTxHash,
+		"rollup_status":            int(types.RollupFinalized),
+		"finalized_at":             time.Now(),
+		"finalize_l1_block_number": l1BlockNumber,
+	}
+
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("hash", hash)
+
+	if err := db.Updates(updateFields).Error; err != nil {
+		return fmt.Errorf("Batch.UpdateFinalizedBatch error: %w, batch hash: %v, finalizeTxHash: %v, l1 block number: %v", err, hash, finalizeTxHash, l1BlockNumber)
+	}
+	return nil
+}
+
+// FillFinalizeL1BlockNumber sets the number of the L1 block including the finalize transaction of a batch, if it
+// isn't known yet.
+func (o *Batch) FillFinalizeL1BlockNumber(ctx context.Context, hash string, l1BlockNumber uint64) error {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("hash", hash)
+	db = db.Where("finalize_l1_block_number = 0")
+
+	if err := db.Update("finalize_l1_block_number", l1BlockNumber).Error; err != nil {
+		return fmt.Errorf("Batch.FillFinalizeL1BlockNumber error: %w, batch hash: %v, l1 block number: %v", err, hash, l1BlockNumber)
+	}
+	return nil
+}
+
+// RollbackL1RollupStatus reverts the rollup statuses derived from the L1 blocks from the given height on, after an L1 reorg.
+// The commit and the finalization are rolled back independently. A batch whose commit event is reorged goes back to
+// commit failed whatever its later status, along with its finalization, and a batch whose only finalize event is
+// reorged goes back to committed. The transactions already confirmed aren't tracked by the senders anymore, so the
+// batches are left to the relayer to resend them, and a transaction re-included on L1 reverts as already done.
+// It returns the number of the reverted batches.
+func (o *Batch) RollbackL1RollupStatus(ctx context.Context, fromL1BlockNumber uint64, dbTX ...*gorm.DB) (int64, error) {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+
+	committed := db.Model(&Batch{}).
+		Where("rollup_status IN ?", []int{int(types.RollupCommitted), int(types.RollupFinalizing), int(types.RollupFinalized), int(types.RollupFinalizeFailed)}).
+		Where("commit_l1_block_number >= ?", fromL1BlockNumber).
+		Updates(map[string]interface{}{
+			"rollup_status":            int(types.RollupCommitFailed),
+			"committed_at":             nil,
+			"commit_l1_block_number":   0,
+			"finalized_at":             nil,
+			"finalize_l1_block_number": 0,
+		})
+	if committed.Error != nil {
+		return 0, fmt.Errorf("Batch.RollbackL1RollupStatus error: %w, from l1 block number: %v", committed.Error, fromL1BlockNumber)
+	}
+
+	// the batches committed before the reorg keep their commit.
+	finalized := db.Model(&Batch{}).
+		Where("rollup_status = ?", int(types.RollupFinalized)).
+		Where("finalize_l1_block_number >= ?", fromL1BlockNumber).
+		Where("commit_l1_block_number < ?", fromL1BlockNumber).
+		Updates(map[string]interface{}{
+			"rollup_status":            int(types.RollupCommitted),
+			"finalized_at":             nil,
+			"finalize_l1_block_number": 0,
+		})
+	if finalized.Error != nil {
+		return 0, fmt.Errorf("Batch.RollbackL1RollupStatus error: %w, from l1 block number: %v", finalized.Error, fromL1BlockNumber)
+	}
+	return committed.RowsAffected + finalized.RowsAffected, nil
+}
+
+// ResetRevertedBatch moves a batch reverted on L1 back to pending, so that it is proven and committed again.
+func (o *Batch) ResetRevertedBatch(ctx context.Context, hash string, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("hash", hash)
+
+	updateFields := map[string]interface{}{
+		"rollup_status":          int(types.RollupPending),
+		"commit_tx_hash":         nil,
+		"committed_at":           nil,
+		"commit_l1_block_number": 0,
+		"proving_status":         int(types.ProvingTaskUnassigned),
+		"prover_assigned_at":     nil,
+	}
+	if err := db.Updates(updateFields).Error; err != nil {
+		return fmt.Errorf("Batch.ResetRevertedBatch error: %w, batch hash: %v", err, hash)
+	}
+	return nil
+}
+
+// ResetBatchProof drops the verified proof of a batch and moves it back to unassigned along with fresh proving
+// attempts, so that the coordinator assigns it to a prover again. It returns false if the batch isn't verified.
+func (o *Batch) ResetBatchProof(ctx context.Context, hash string) (bool, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("hash = ?", hash)
+	db = db.Where("proving_status = ?", int(types.ProvingTaskVerified))
+
+	updateFields := map[string]interface{}{
+		"proving_status":     int(types.ProvingTaskUnassigned),
+		"proof":              nil,
+		"proved_at":          nil,
+		"prover_assigned_at": nil,
+		"total_attempts":     0,
+		"active_attempts":    0,
+	}
+	result := db.Updates(updateFields)
+	if result.Error != nil {
+		return false, fmt.Errorf("Batch.ResetBatchProof error: %w, batch hash: %v", result.Error, hash)
+	}
+	return result.RowsAffected > 0, nil
+}
+
 // UpdateProofByHash updates the batch proof by hash.
 // for unit test.
 func (o *Batch) UpdateProofByHash(ctx context.Context, hash string, proof *message.BatchProof, proofTimeSec uint64) error {
@@ -424,3 +751,19 @@ func (o *Batch) UpdateProofByHash(ctx context.Context, hash string, proof *messa
 	}
 	return nil
 }
+
+// DeleteBatchesGtIndex deletes the batches with an index greater than the given index.
+func (o *Batch) DeleteBatchesGtIndex(ctx context.Context, index uint64, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("index > ?", index)
+
+	if err := db.Delete(&Batch{}).Error; err != nil {
+		return fmt.Errorf("Batch.DeleteBatchesGtIndex error: %w, index: %v", err, index)
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 
 	"scroll-tech/common/types"
 	"scroll-tech/common/types/encoding"
+	"scroll-tech/common/types/schema"
 
 	"scroll-tech/rollup/internal/utils"
 
@@ -33,6 +34,8 @@ type Chunk struct {
 	StateRoot                    string `json:"state_root" gorm:"column:state_root"`
 	ParentChunkStateRoot         string `json:"parent_chunk_state_root" gorm:"column:parent_chunk_state_root"`
 	WithdrawRoot                 string `json:"withdraw_root" gorm:"column:withdraw_root"`
+	// CodecVersion is -1 for the chunks proposed before the codec version is recorded.
+	CodecVersion int16 `json:"codec_version" gorm:"column:codec_version"`
 
 	// proof
 	ProvingStatus    int16      `json:"proving_status" gorm:"column:proving_status;default:1"`
@@ -68,6 +71,35 @@ func (*Chunk) TableName() string {
 	return "chunk"
 }
 
+// ToSchema converts the chunk to its canonical schema.
+func (o *Chunk) ToSchema() *schema.Chunk {
+	return &schema.Chunk{
+		SchemaVersion:                schema.Version,
+		Index:                        o.Index,
+		Hash:                         o.Hash,
+		StartBlockNumber:             o.StartBlockNumber,
+		StartBlockHash:               o.StartBlockHash,
+		EndBlockNumber:               o.EndBlockNumber,
+		EndBlockHash:                 o.EndBlockHash,
+		StartBlockTime:               o.StartBlockTime,
+		TotalL1MessagesPoppedBefore:  o.TotalL1MessagesPoppedBefore,
+		TotalL1MessagesPoppedInChunk: o.TotalL1MessagesPoppedInChunk,
+		ParentChunkHash:              o.ParentChunkHash,
+		StateRoot:                    o.StateRoot,
+		ParentChunkStateRoot:         o.ParentChunkStateRoot,
+		WithdrawRoot:                 o.WithdrawRoot,
+		TotalL2TxGas:                 o.TotalL2TxGas,
+		TotalL2TxNum:                 o.TotalL2TxNum,
+		TotalL1CommitCalldataSize:    o.TotalL1CommitCalldataSize,
+		TotalL1CommitGas:             o.TotalL1CommitGas,
+		CrcMax:                       o.CrcMax,
+		BlobSize:                     o.BlobSize,
+		ProvingStatus:                int32(o.ProvingStatus),
+		BatchHash:                    o.BatchHash,
+		CreatedAt:                    o.CreatedAt.Unix(),
+	}
+}
+
 // GetChunksInRange retrieves chunks within a given range (inclusive) from the database.
 // The range is closed, i.e., it includes both start and end indices.
 // The returned chunks are sorted in ascending order by their index.
@@ -125,6 +157,18 @@ func (o *Chunk) GetUnchunkedBlockHeight(ctx context.Context) (uint64, error) {
 	return latestChunk.EndBlockNumber + 1, nil
 }
 
+// GetUnchunkedL1MessageQueueIndex retrieves the queue index of the first L1 message not included in any chunk.
+func (o *Chunk) GetUnchunkedL1MessageQueueIndex(ctx context.Context) (uint64, error) {
+	latestChunk, err := o.getLatestChunk(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("Chunk.GetUnchunkedL1MessageQueueIndex error: %w", err)
+	}
+	if latestChunk == nil {
+		return 0, nil
+	}
+	return latestChunk.TotalL1MessagesPoppedBefore + latestChunk.TotalL1MessagesPoppedInChunk, nil
+}
+
 // GetChunksGEIndex retrieves chunks that have a chunk index greater than the or equal to the given index.
 // The returned chunks are sorted in ascending order by their index.
 func (o *Chunk) GetChunksGEIndex(ctx context.Context, index uint64, limit int) ([]*Chunk, error) {
@@ -144,6 +188,54 @@ func (o *Chunk) GetChunksGEIndex(ctx context.Context, index uint64, limit int) (
 	return chunks, nil
 }
 
+// GetChunksGEBlockNumber retrieves the chunks containing a block with a number greater than or equal to the given number.
+// The returned chunks are sorted in ascending order by their index.
+func (o *Chunk) GetChunksGEBlockNumber(ctx context.Context, number uint64) ([]*Chunk, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Chunk{})
+	db = db.Where("end_block_number >= ?", number)
+	db = db.Order("index ASC")
+
+	var chunks []*Chunk
+	if err := db.Find(&chunks).Error; err != nil {
+		return nil, fmt.Errorf("Chunk.GetChunksGEBlockNumber error: %w, number: %v", err, number)
+	}
+	return chunks, nil
+}
+
+// GetUnbatchedChunks retrieves the chunks not included in any batch.
+// The returned chunks are sorted in ascending order by their index.
+func (o *Chunk) GetUnbatchedChunks(ctx context.Context) ([]*Chunk, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Chunk{})
+	db = db.Where("batch_hash IS NULL")
+	db = db.Order("index ASC")
+
+	var chunks []*Chunk
+	if err := db.Find(&chunks).Error; err != nil {
+		return nil, fmt.Errorf("Chunk.GetUnbatchedChunks error: %w", err)
+	}
+	return chunks, nil
+}
+
+// GetChunkByL1MessageQueueIndex retrieves the chunk which pops the L1 message with the given queue index.
+// It returns nil if the message has not been included in any chunk yet.
+func (o *Chunk) GetChunkByL1MessageQueueIndex(ctx context.Context, queueIndex uint64) (*Chunk, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Chunk{})
+	db = db.Where("total_l1_messages_popped_before <= ?", queueIndex)
+	db = db.Where("total_l1_messages_popped_before + total_l1_messages_popped_in_chunk > ?", queueIndex)
+
+	var chunk Chunk
+	if err := db.First(&chunk).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Chunk.GetChunkByL1MessageQueueIndex error: %w, queue index: %v", err, queueIndex)
+	}
+	return &chunk, nil
+}
+
 // GetChunksByBatchHash retrieves chunks by batch hash
 // for test
 func (o *Chunk) GetChunksByBatchHash(ctx context.Context, batchHash string) ([]*Chunk, error) {
@@ -164,19 +256,45 @@ func (o *Chunk) InsertChunk(ctx context.Context, chunk *encoding.Chunk, codecVer
 		return nil, errors.New("invalid args")
 	}
 
-	var chunkIndex uint64
-	var totalL1MessagePoppedBefore uint64
-	var parentChunkHash string
-	var parentChunkStateRoot string
 	parentChunk, err := o.getLatestChunk(ctx)
 	if err != nil {
 		log.Error("failed to get latest chunk", "err", err)
 		return nil, fmt.Errorf("Chunk.InsertChunk error: %w", err)
 	}
 
-	// if parentChunk==nil then err==gorm.ErrRecordNotFound, which means there's
-	// no chunk record in the db, we then use default empty values for the creating chunk;
-	// if parentChunk!=nil then err==nil, then we fill the parentChunk-related data into the creating chunk
+	// parentChunk is nil if there's no chunk record in the db yet
+	newChunk, err := NewChunkRecord(chunk, codecVersion, parentChunk)
+	if err != nil {
+		log.Error("failed to build chunk record", "err", err)
+		return nil, fmt.Errorf("Chunk.InsertChunk error: %w", err)
+	}
+
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&Chunk{})
+
+	if err := db.Create(newChunk).Error; err != nil {
+		return nil, fmt.Errorf("Chunk.InsertChunk error: %w, chunk hash: %v", err, newChunk.Hash)
+	}
+
+	return newChunk, nil
+}
+
+// NewChunkRecord builds the record of a chunk following parentChunk, nil if it is the first chunk.
+// It is shared by the storage backends so that they derive the same chunk fields.
+func NewChunkRecord(chunk *encoding.Chunk, codecVersion encoding.CodecVersion, parentChunk *Chunk) (*Chunk, error) {
+	if chunk == nil || len(chunk.Blocks) == 0 {
+		return nil, errors.New("invalid args")
+	}
+
+	var chunkIndex uint64
+	var totalL1MessagePoppedBefore uint64
+	var parentChunkHash string
+	var parentChunkStateRoot string
+	// fill the parentChunk-related data into the creating chunk
 	if parentChunk != nil {
 		chunkIndex = parentChunk.Index + 1
 		totalL1MessagePoppedBefore = parentChunk.TotalL1MessagesPoppedBefore + parentChunk.TotalL1MessagesPoppedInChunk
@@ -186,18 +304,16 @@ func (o *Chunk) InsertChunk(ctx context.Context, chunk *encoding.Chunk, codecVer
 
 	metrics, err := utils.CalculateChunkMetrics(chunk, codecVersion)
 	if err != nil {
-		log.Error("failed to calculate chunk metrics", "err", err)
-		return nil, fmt.Errorf("Chunk.InsertChunk error: %w", err)
+		return nil, fmt.Errorf("failed to calculate chunk metrics: %w", err)
 	}
 
 	chunkHash, err := utils.GetChunkHash(chunk, totalL1MessagePoppedBefore, codecVersion)
 	if err != nil {
-		log.Error("failed to get chunk hash", "err", err)
-		return nil, fmt.Errorf("Chunk.InsertChunk error: %w", err)
+		return nil, fmt.Errorf("failed to get chunk hash: %w", err)
 	}
 
 	numBlocks := len(chunk.Blocks)
-	newChunk := Chunk{
+	return &Chunk{
 		Index:                        chunkIndex,
 		Hash:                         chunkHash.Hex(),
 		StartBlockNumber:             chunk.Blocks[0].Header.Number.Uint64(),
@@ -215,23 +331,11 @@ func (o *Chunk) InsertChunk(ctx context.Context, chunk *encoding.Chunk, codecVer
 		StateRoot:                    chunk.Blocks[numBlocks-1].Header.Root.Hex(),
 		ParentChunkStateRoot:         parentChunkStateRoot,
 		WithdrawRoot:                 chunk.Blocks[numBlocks-1].WithdrawRoot.Hex(),
+		CodecVersion:                 int16(codecVersion),
 		ProvingStatus:                int16(types.ProvingTaskUnassigned),
 		CrcMax:                       metrics.CrcMax,
 		BlobSize:                     metrics.L1CommitBlobSize,
-	}
-
-	db := o.db
-	if len(dbTX) > 0 && dbTX[0] != nil {
-		db = dbTX[0]
-	}
-	db = db.WithContext(ctx)
-	db = db.Model(&Chunk{})
-
-	if err := db.Create(&newChunk).Error; err != nil {
-		return nil, fmt.Errorf("Chunk.InsertChunk error: %w, chunk hash: %v", err, newChunk.Hash)
-	}
-
-	return &newChunk, nil
+	}, nil
 }
 
 // UpdateProvingStatus updates the proving status of a chunk.
@@ -306,3 +410,44 @@ func (o *Chunk) UpdateBatchHashInRange(ctx context.Context, startIndex uint64, e
 	}
 	return nil
 }
+
+// DeleteUnbatchedChunksGEIndex deletes the chunks with an index greater than or equal to the given index.
+// It fails without deleting anything if any of those chunks is already included in a batch.
+// The rows are deleted permanently, so that the blocks can be grouped into chunks with the same hashes again.
+func (o *Chunk) DeleteUnbatchedChunksGEIndex(ctx context.Context, index uint64, dbTX ...*gorm.DB) (int64, error) {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+
+	var numBatched int64
+	if err := db.Model(&Chunk{}).Where("index >= ? AND batch_hash IS NOT NULL", index).Count(&numBatched).Error; err != nil {
+		return 0, fmt.Errorf("Chunk.DeleteUnbatchedChunksGEIndex error: %w, index: %v", err, index)
+	}
+	if numBatched > 0 {
+		return 0, fmt.Errorf("Chunk.DeleteUnbatchedChunksGEIndex error: %v chunks are already batched, index: %v", numBatched, index)
+	}
+
+	result := db.Unscoped().Model(&Chunk{}).Where("index >= ?", index).Delete(&Chunk{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("Chunk.DeleteUnbatchedChunksGEIndex error: %w, index: %v", result.Error, index)
+	}
+	return result.RowsAffected, nil
+}
+
+// ResetBatchHashGtIndex unlinks the chunks with an index greater than the given index from their batch.
+func (o *Chunk) ResetBatchHashGtIndex(ctx context.Context, index uint64, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&Chunk{})
+	db = db.Where("index > ?", index)
+
+	if err := db.Update("batch_hash", nil).Error; err != nil {
+		return fmt.Errorf("Chunk.ResetBatchHashGtIndex error: %w, index: %v", err, index)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FeatureFlag is the database override of a feature flag of the deployment.
+type FeatureFlag struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	Name              string `json:"name" gorm:"column:name"`
+	Enabled           bool   `json:"enabled" gorm:"column:enabled"`
+	RolloutPercentage uint64 `json:"rollout_percentage" gorm:"column:rollout_percentage"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewFeatureFlag creates a new FeatureFlag instance.
+func NewFeatureFlag(db *gorm.DB) *FeatureFlag {
+	return &FeatureFlag{db: db}
+}
+
+// TableName returns the name of the "feature_flag" table.
+func (*FeatureFlag) TableName() string {
+	return "feature_flag"
+}
+
+// GetFeatureFlags returns all the feature flag overrides, ordered by name.
+func (o *FeatureFlag) GetFeatureFlags(ctx context.Context) ([]*FeatureFlag, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&FeatureFlag{})
+	db = db.Order("name ASC")
+
+	var flags []*FeatureFlag
+	if err := db.Find(&flags).Error; err != nil {
+		return nil, fmt.Errorf("FeatureFlag.GetFeatureFlags error: %w", err)
+	}
+	return flags, nil
+}
+
+// UpsertFeatureFlag inserts or updates the override of a feature flag.
+func (o *FeatureFlag) UpsertFeatureFlag(ctx context.Context, name string, enabled bool, rolloutPercentage uint64) error {
+	flag := FeatureFlag{
+		Name:              name,
+		Enabled:           enabled,
+		RolloutPercentage: rolloutPercentage,
+	}
+
+	db := o.db.WithContext(ctx)
+	db = db.Model(&FeatureFlag{})
+	db = db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		Where:     clause.Where{Exprs: []clause.Expression{clause.Eq{Column: "deleted_at", Value: nil}}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"enabled": enabled, "rollout_percentage": rolloutPercentage, "updated_at": gorm.Expr("CURRENT_TIMESTAMP")}),
+	})
+	if err := db.Create(&flag).Error; err != nil {
+		return fmt.Errorf("FeatureFlag.UpsertFeatureFlag error: %w, name: %v", err, name)
+	}
+	return nil
+}
+
+// DeleteFeatureFlag removes the override of a feature flag, falling back to the configured value.
+func (o *FeatureFlag) DeleteFeatureFlag(ctx context.Context, name string) error {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&FeatureFlag{})
+	db = db.Where("name = ?", name)
+	if err := db.Delete(&FeatureFlag{}).Error; err != nil {
+		return fmt.Errorf("FeatureFlag.DeleteFeatureFlag error: %w, name: %v", err, name)
+	}
+	return nil
+}
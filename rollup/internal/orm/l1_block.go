@@ -53,6 +53,24 @@ func (o *L1Block) GetLatestL1BlockHeight(ctx context.Context) (uint64, error) {
 	return maxNumber, nil
 }
 
+// GetAverageBlobBaseFee returns the average blob base fee of the latest numBlocks l1 blocks, 0 if there is no block.
+func (o *L1Block) GetAverageBlobBaseFee(ctx context.Context, numBlocks uint64) (uint64, error) {
+	latest := o.db.WithContext(ctx).Model(&L1Block{})
+	latest = latest.Select("blob_base_fee")
+	latest = latest.Order("number DESC")
+	latest = latest.Limit(int(numBlocks))
+
+	db := o.db.WithContext(ctx)
+	db = db.Table("(?) AS latest", latest)
+	db = db.Select("COALESCE(AVG(blob_base_fee), 0)::BIGINT")
+
+	var average uint64
+	if err := db.Row().Scan(&average); err != nil {
+		return 0, fmt.Errorf("L1Block.GetAverageBlobBaseFee error: %w, num blocks: %v", err, numBlocks)
+	}
+	return average, nil
+}
+
 // GetL1Blocks get the l1 blocks
 func (o *L1Block) GetL1Blocks(ctx context.Context, fields map[string]interface{}) ([]L1Block, error) {
 	db := o.db.WithContext(ctx)
@@ -124,3 +142,19 @@ func (o *L1Block) UpdateL1GasOracleStatusAndOracleTxHash(ctx context.Context, bl
 	}
 	return nil
 }
+
+// DeleteL1BlocksGeHeight soft deletes the l1 blocks from the given height on.
+func (o *L1Block) DeleteL1BlocksGeHeight(ctx context.Context, height uint64, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&L1Block{})
+	db = db.Where("number >= ?", height)
+
+	if err := db.Delete(&L1Block{}).Error; err != nil {
+		return fmt.Errorf("L1Block.DeleteL1BlocksGeHeight error: %w, height: %v", err, height)
+	}
+	return nil
+}
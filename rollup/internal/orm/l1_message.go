@@ -3,6 +3,7 @@ package orm
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"time"
 
 	"github.com/scroll-tech/go-ethereum/log"
@@ -24,6 +25,11 @@ type L1Message struct {
 	Layer1Hash string `json:"layer1_hash" gorm:"column:layer1_hash"`
 	Layer2Hash string `json:"layer2_hash" gorm:"column:layer2_hash;default:NULL"`
 	Status     int    `json:"status" gorm:"column:status;default:1"`
+	IsEnforced bool   `json:"is_enforced" gorm:"column:is_enforced"`
+	// InclusionDeadline is the unix time before which an enforced transaction must be included, 0 if none.
+	InclusionDeadline uint64 `json:"inclusion_deadline" gorm:"column:inclusion_deadline"`
+	// EnqueueTime is the unix time of the L1 block queueing an enforced transaction, 0 if unknown.
+	EnqueueTime uint64 `json:"enqueue_time" gorm:"column:enqueue_time"`
 
 	// metadata
 	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
@@ -74,3 +80,92 @@ func (m *L1Message) SaveL1Messages(ctx context.Context, messages []*L1Message) e
 	}
 	return err
 }
+
+// GetL1MessagesByQueueIndices returns the messages of the given queue indices, in ascending order of queue index.
+func (m *L1Message) GetL1MessagesByQueueIndices(ctx context.Context, queueIndices []uint64) ([]*L1Message, error) {
+	if len(queueIndices) == 0 {
+		return nil, nil
+	}
+
+	db := m.db.WithContext(ctx)
+	db = db.Model(&L1Message{})
+	db = db.Where("queue_index IN ?", queueIndices)
+	db = db.Order("queue_index ASC")
+
+	var messages []*L1Message
+	if err := db.Find(&messages).Error; err != nil {
+		return nil, fmt.Errorf("L1Message.GetL1MessagesByQueueIndices error: %w, queue indices: %v", err, queueIndices)
+	}
+	return messages, nil
+}
+
+// GetEnforcedL1MessagesByQueueIndices returns the enforced (forced-inclusion) messages among the given queue indices.
+func (m *L1Message) GetEnforcedL1MessagesByQueueIndices(ctx context.Context, queueIndices []uint64) ([]*L1Message, error) {
+	if len(queueIndices) == 0 {
+		return nil, nil
+	}
+
+	db := m.db.WithContext(ctx)
+	db = db.Model(&L1Message{})
+	db = db.Where("is_enforced = ?", true)
+	db = db.Where("queue_index IN ?", queueIndices)
+	db = db.Order("queue_index ASC")
+
+	var messages []*L1Message
+	if err := db.Find(&messages).Error; err != nil {
+		return nil, fmt.Errorf("L1Message.GetEnforcedL1MessagesByQueueIndices error: %w, queue indices: %v", err, queueIndices)
+	}
+	return messages, nil
+}
+
+// GetEnforcedL1MessagesByLayer1Hash returns the enforced (forced-inclusion) messages submitted by the given L1 transaction.
+func (m *L1Message) GetEnforcedL1MessagesByLayer1Hash(ctx context.Context, layer1Hash string) ([]*L1Message, error) {
+	db := m.db.WithContext(ctx)
+	db = db.Model(&L1Message{})
+	db = db.Where("is_enforced = ?", true)
+	db = db.Where("layer1_hash = ?", layer1Hash)
+	db = db.Order("queue_index ASC")
+
+	var messages []*L1Message
+	if err := db.Find(&messages).Error; err != nil {
+		return nil, fmt.Errorf("L1Message.GetEnforcedL1MessagesByLayer1Hash error: %w, layer1 hash: %v", err, layer1Hash)
+	}
+	return messages, nil
+}
+
+// GetEnforcedL1MessagesGEQueueIndex returns the enforced (forced-inclusion) messages from the given queue index on,
+// i.e. the forced transaction queue when the index is the next one to include, in ascending order of queue index.
+func (m *L1Message) GetEnforcedL1MessagesGEQueueIndex(ctx context.Context, queueIndex uint64, limit int) ([]*L1Message, error) {
+	db := m.db.WithContext(ctx)
+	db = db.Model(&L1Message{})
+	db = db.Where("is_enforced = ?", true)
+	db = db.Where("queue_index >= ?", queueIndex)
+	db = db.Order("queue_index ASC")
+	if limit > 0 {
+		db = db.Limit(limit)
+	}
+
+	var messages []*L1Message
+	if err := db.Find(&messages).Error; err != nil {
+		return nil, fmt.Errorf("L1Message.GetEnforcedL1MessagesGEQueueIndex error: %w, queue index: %v", err, queueIndex)
+	}
+	return messages, nil
+}
+
+// DeleteL1MessagesGeHeight soft deletes the messages emitted in the L1 blocks from the given height on.
+// It returns the number of the deleted messages.
+func (m *L1Message) DeleteL1MessagesGeHeight(ctx context.Context, height uint64, dbTX ...*gorm.DB) (int64, error) {
+	db := m.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&L1Message{})
+	db = db.Where("height >= ?", height)
+
+	result := db.Delete(&L1Message{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("L1Message.DeleteL1MessagesGeHeight error: %w, height: %v", result.Error, height)
+	}
+	return result.RowsAffected, nil
+}
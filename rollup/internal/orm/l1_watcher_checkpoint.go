@@ -0,0 +1,93 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// L1WatcherCheckpoint is the sync checkpoint of an event filter of the L1 watcher, resumed after a restart.
+type L1WatcherCheckpoint struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	EventFilter     string `json:"event_filter" gorm:"column:event_filter"`
+	ProcessedHeight uint64 `json:"processed_height" gorm:"column:processed_height"`
+	// BlockHash is the hash of the block at ProcessedHeight, empty if unknown.
+	BlockHash string `json:"block_hash" gorm:"column:block_hash"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewL1WatcherCheckpoint creates a new L1WatcherCheckpoint instance.
+func NewL1WatcherCheckpoint(db *gorm.DB) *L1WatcherCheckpoint {
+	return &L1WatcherCheckpoint{db: db}
+}
+
+// TableName returns the name of the "l1_watcher_checkpoint" table.
+func (*L1WatcherCheckpoint) TableName() string {
+	return "l1_watcher_checkpoint"
+}
+
+// GetL1WatcherCheckpoints returns the checkpoints of all the event filters.
+func (o *L1WatcherCheckpoint) GetL1WatcherCheckpoints(ctx context.Context) ([]*L1WatcherCheckpoint, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&L1WatcherCheckpoint{})
+	db = db.Order("event_filter ASC")
+
+	var checkpoints []*L1WatcherCheckpoint
+	if err := db.Find(&checkpoints).Error; err != nil {
+		return nil, fmt.Errorf("L1WatcherCheckpoint.GetL1WatcherCheckpoints error: %w", err)
+	}
+	return checkpoints, nil
+}
+
+// UpsertL1WatcherCheckpoint inserts or replaces the checkpoint of an event filter.
+func (o *L1WatcherCheckpoint) UpsertL1WatcherCheckpoint(ctx context.Context, eventFilter string, processedHeight uint64, blockHash string, dbTX ...*gorm.DB) error {
+	checkpoint := L1WatcherCheckpoint{
+		EventFilter:     eventFilter,
+		ProcessedHeight: processedHeight,
+		BlockHash:       blockHash,
+	}
+
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&L1WatcherCheckpoint{})
+	db = db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "event_filter"}},
+		Where:   clause.Where{Exprs: []clause.Expression{clause.Eq{Column: "deleted_at", Value: nil}}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"processed_height": processedHeight,
+			"block_hash":       blockHash,
+			"updated_at":       gorm.Expr("CURRENT_TIMESTAMP"),
+		}),
+	})
+	if err := db.Create(&checkpoint).Error; err != nil {
+		return fmt.Errorf("L1WatcherCheckpoint.UpsertL1WatcherCheckpoint error: %w, event filter: %v, processed height: %v", err, eventFilter, processedHeight)
+	}
+	return nil
+}
+
+// RewindL1WatcherCheckpoints moves the checkpoints beyond the given height back to it, e.g. after an L1 reorg.
+func (o *L1WatcherCheckpoint) RewindL1WatcherCheckpoints(ctx context.Context, height uint64, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&L1WatcherCheckpoint{})
+	db = db.Where("processed_height > ?", height)
+
+	if err := db.Updates(map[string]interface{}{"processed_height": height, "block_hash": ""}).Error; err != nil {
+		return fmt.Errorf("L1WatcherCheckpoint.RewindL1WatcherCheckpoints error: %w, height: %v", err, height)
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package orm
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -14,6 +15,10 @@ import (
 	"scroll-tech/common/types/encoding"
 )
 
+// maxL2BlocksPerInsertStatement is the max number of rows of an l2_block insert statement,
+// far below the 65535 bind parameters allowed by postgres per statement.
+const maxL2BlocksPerInsertStatement = 1000
+
 // L2Block represents a l2 block in the database.
 type L2Block struct {
 	db *gorm.DB `gorm:"column:-"`
@@ -31,6 +36,9 @@ type L2Block struct {
 	BlockTimestamp uint64 `json:"block_timestamp" gorm:"block_timestamp"`
 	RowConsumption string `json:"row_consumption" gorm:"row_consumption"`
 
+	// TransactionsPruned is set once the transactions are discarded by the light storage mode.
+	TransactionsPruned bool `json:"transactions_pruned" gorm:"transactions_pruned"`
+
 	// chunk
 	ChunkHash string `json:"chunk_hash" gorm:"chunk_hash;default:NULL"`
 
@@ -64,13 +72,49 @@ func (o *L2Block) GetL2BlocksLatestHeight(ctx context.Context) (uint64, error) {
 	return maxNumber, nil
 }
 
+// GetL2BlockHashByNumber retrieves the hash of the L2 block with the given number.
+// It returns an empty string if the block is not stored.
+func (o *L2Block) GetL2BlockHashByNumber(ctx context.Context, number uint64) (string, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&L2Block{})
+	db = db.Select("hash")
+	db = db.Where("number = ?", number)
+
+	var l2Block L2Block
+	if err := db.First(&l2Block).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil
+		}
+		return "", fmt.Errorf("L2Block.GetL2BlockHashByNumber error: %w, number: %v", err, number)
+	}
+	return l2Block.Hash, nil
+}
+
+// GetL2BlockImportedAt retrieves the time the L2 block with the given number was imported by the l2 watcher.
+// It returns nil if the block is not stored.
+func (o *L2Block) GetL2BlockImportedAt(ctx context.Context, number uint64) (*time.Time, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&L2Block{})
+	db = db.Select("created_at")
+	db = db.Where("number = ?", number)
+
+	var l2Block L2Block
+	if err := db.First(&l2Block).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("L2Block.GetL2BlockImportedAt error: %w, number: %v", err, number)
+	}
+	return &l2Block.CreatedAt, nil
+}
+
 // GetL2BlocksGEHeight retrieves L2 blocks that have a block number greater than or equal to the given height.
 // The blocks are converted into encoding.Block format for output.
 // The returned blocks are sorted in ascending order by their block number.
 func (o *L2Block) GetL2BlocksGEHeight(ctx context.Context, height uint64, limit int) ([]*encoding.Block, error) {
 	db := o.db.WithContext(ctx)
 	db = db.Model(&L2Block{})
-	db = db.Select("header, transactions, withdraw_root, row_consumption")
+	db = db.Select("number, header, transactions, withdraw_root, row_consumption, transactions_pruned")
 	db = db.Where("number >= ?", height)
 	db = db.Order("number ASC")
 
@@ -85,6 +129,10 @@ func (o *L2Block) GetL2BlocksGEHeight(ctx context.Context, height uint64, limit
 
 	var blocks []*encoding.Block
 	for _, v := range l2Blocks {
+		if v.TransactionsPruned {
+			return nil, fmt.Errorf("L2Block.GetL2BlocksGEHeight: transactions of block %v are pruned by the light storage mode", v.Number)
+		}
+
 		var block encoding.Block
 
 		if err := json.Unmarshal([]byte(v.Transactions), &block.Transactions); err != nil {
@@ -144,7 +192,7 @@ func (o *L2Block) GetL2BlocksInRange(ctx context.Context, startBlockNumber uint6
 
 	db := o.db.WithContext(ctx)
 	db = db.Model(&L2Block{})
-	db = db.Select("header, transactions, withdraw_root, row_consumption")
+	db = db.Select("number, header, transactions, withdraw_root, row_consumption, transactions_pruned")
 	db = db.Where("number >= ? AND number <= ?", startBlockNumber, endBlockNumber)
 	db = db.Order("number ASC")
 
@@ -160,6 +208,10 @@ func (o *L2Block) GetL2BlocksInRange(ctx context.Context, startBlockNumber uint6
 
 	var blocks []*encoding.Block
 	for _, v := range l2Blocks {
+		if v.TransactionsPruned {
+			return nil, fmt.Errorf("L2Block.GetL2BlocksInRange: transactions of block %v are pruned by the light storage mode", v.Number)
+		}
+
 		var block encoding.Block
 
 		if err := json.Unmarshal([]byte(v.Transactions), &block.Transactions); err != nil {
@@ -183,8 +235,8 @@ func (o *L2Block) GetL2BlocksInRange(ctx context.Context, startBlockNumber uint6
 	return blocks, nil
 }
 
-// InsertL2Blocks inserts l2 blocks into the "l2_block" table.
-func (o *L2Block) InsertL2Blocks(ctx context.Context, blocks []*encoding.Block) error {
+// InsertL2Blocks inserts l2 blocks into the "l2_block" table, either all of them or none.
+func (o *L2Block) InsertL2Blocks(ctx context.Context, blocks []*encoding.Block, dbTX ...*gorm.DB) error {
 	var l2Blocks []L2Block
 	for _, block := range blocks {
 		header, err := json.Marshal(block.Header)
@@ -221,15 +273,121 @@ func (o *L2Block) InsertL2Blocks(ctx context.Context, blocks []*encoding.Block)
 		l2Blocks = append(l2Blocks, l2Block)
 	}
 
-	db := o.db.WithContext(ctx)
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
 	db = db.Model(&L2Block{})
 
-	if err := db.Create(&l2Blocks).Error; err != nil {
+	// The blocks are inserted in a single transaction, by multi-row statements small enough for the bind parameter limit.
+	if err := db.CreateInBatches(&l2Blocks, maxL2BlocksPerInsertStatement).Error; err != nil {
 		return fmt.Errorf("L2Block.InsertL2Blocks error: %w", err)
 	}
 	return nil
 }
 
+// GetL2BlocksWithoutRowConsumption retrieves the blocks stored without row consumption, e.g. ingested before the
+// row consumption was available or from a fallback API. Only the number and hash of the blocks are selected.
+// The returned blocks are sorted in ascending order by their block number.
+func (o *L2Block) GetL2BlocksWithoutRowConsumption(ctx context.Context, limit int) ([]*L2Block, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&L2Block{})
+	db = db.Select("number, hash")
+	db = db.Where("row_consumption IN ?", []string{"", "null"})
+	db = db.Order("number ASC")
+
+	if limit > 0 {
+		db = db.Limit(limit)
+	}
+
+	var l2Blocks []*L2Block
+	if err := db.Find(&l2Blocks).Error; err != nil {
+		return nil, fmt.Errorf("L2Block.GetL2BlocksWithoutRowConsumption error: %w, limit: %v", err, limit)
+	}
+	return l2Blocks, nil
+}
+
+// UpdateRowConsumption updates the row consumption of the block with the given number and hash.
+func (o *L2Block) UpdateRowConsumption(ctx context.Context, number uint64, hash string, rowConsumption *gethTypes.RowConsumption) error {
+	if rowConsumption == nil {
+		return fmt.Errorf("L2Block.UpdateRowConsumption: nil row consumption, number: %v, hash: %v", number, hash)
+	}
+
+	rc, err := json.Marshal(rowConsumption)
+	if err != nil {
+		return fmt.Errorf("L2Block.UpdateRowConsumption error: %w, number: %v, hash: %v", err, number, hash)
+	}
+
+	db := o.db.WithContext(ctx)
+	db = db.Model(&L2Block{})
+	db = db.Where("number = ? AND hash = ?", number, hash)
+
+	tx := db.Update("row_consumption", string(rc))
+	if tx.Error != nil {
+		return fmt.Errorf("L2Block.UpdateRowConsumption error: %w, number: %v, hash: %v", tx.Error, number, hash)
+	}
+	if tx.RowsAffected != 1 {
+		return fmt.Errorf("L2Block.UpdateRowConsumption: block not found, number: %v, hash: %v", number, hash)
+	}
+	return nil
+}
+
+// PruneL2BlockTransactions discards the transactions of at most limit blocks with a number less than or equal to
+// the given height, in ascending block number order. The header, the withdraw root, the row consumption and the
+// per-block metrics are kept. It returns the number of pruned blocks.
+func (o *L2Block) PruneL2BlockTransactions(ctx context.Context, height uint64, limit int) (int64, error) {
+	subQuery := o.db.WithContext(ctx).Model(&L2Block{})
+	subQuery = subQuery.Select("number")
+	subQuery = subQuery.Where("number <= ? AND transactions_pruned = FALSE", height)
+	subQuery = subQuery.Order("number ASC")
+	subQuery = subQuery.Limit(limit)
+
+	db := o.db.WithContext(ctx)
+	db = db.Model(&L2Block{})
+	db = db.Where("number IN (?)", subQuery)
+
+	tx := db.Updates(map[string]interface{}{"transactions": "", "transactions_pruned": true})
+	if tx.Error != nil {
+		return 0, fmt.Errorf("L2Block.PruneL2BlockTransactions error: %w, height: %v, limit: %v", tx.Error, height, limit)
+	}
+	return tx.RowsAffected, nil
+}
+
+// ResetChunkHashGENumber unlinks the L2 blocks with a number greater than or equal to the given number from their chunk.
+func (o *L2Block) ResetChunkHashGENumber(ctx context.Context, number uint64, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&L2Block{})
+	db = db.Where("number >= ?", number)
+
+	if err := db.Update("chunk_hash", nil).Error; err != nil {
+		return fmt.Errorf("L2Block.ResetChunkHashGENumber error: %w, number: %v", err, number)
+	}
+	return nil
+}
+
+// DeleteL2BlocksGENumber deletes the L2 blocks with a number greater than or equal to the given number,
+// e.g. the blocks orphaned by an L2 reorg. It returns the number of deleted blocks.
+func (o *L2Block) DeleteL2BlocksGENumber(ctx context.Context, number uint64, dbTX ...*gorm.DB) (int64, error) {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&L2Block{})
+	db = db.Where("number >= ?", number)
+
+	result := db.Delete(&L2Block{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("L2Block.DeleteL2BlocksGENumber error: %w, number: %v", result.Error, number)
+	}
+	return result.RowsAffected, nil
+}
+
 // UpdateChunkHashInRange updates the chunk_hash of block tx within the specified range (inclusive).
 // The range is closed, i.e., it includes both start and end indices.
 // This function ensures the number of rows updated must equal to (endIndex - startIndex + 1).
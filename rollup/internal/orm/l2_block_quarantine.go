@@ -0,0 +1,128 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Status of a quarantined L2 block.
+const (
+	// L2BlockQuarantineStatusQuarantined indicates the block repeatedly failed to be fetched or validated,
+	// the l2 watcher retries it at the quarantine retry interval only
+	L2BlockQuarantineStatusQuarantined = "quarantined"
+	// L2BlockQuarantineStatusRetry indicates an operator requested the block to be retried by the next fetch
+	L2BlockQuarantineStatusRetry = "retry"
+	// L2BlockQuarantineStatusResolved indicates an operator allowed the block to be imported without its row consumption,
+	// which is filled in later by the row consumption backfill
+	L2BlockQuarantineStatusResolved = "resolved"
+)
+
+// L2BlockQuarantine is an L2 block whose import repeatedly failed.
+type L2BlockQuarantine struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	Number    uint64 `json:"number" gorm:"column:number"`
+	Status    string `json:"status" gorm:"column:status"`
+	Attempts  uint64 `json:"attempts" gorm:"column:attempts"`
+	LastError string `json:"last_error" gorm:"column:last_error"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewL2BlockQuarantine creates a new L2BlockQuarantine instance.
+func NewL2BlockQuarantine(db *gorm.DB) *L2BlockQuarantine {
+	return &L2BlockQuarantine{db: db}
+}
+
+// TableName returns the name of the "l2_block_quarantine" table.
+func (*L2BlockQuarantine) TableName() string {
+	return "l2_block_quarantine"
+}
+
+// GetL2BlockQuarantine returns the quarantine of the block with the given number, nil if the block is not quarantined.
+func (o *L2BlockQuarantine) GetL2BlockQuarantine(ctx context.Context, number uint64) (*L2BlockQuarantine, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&L2BlockQuarantine{})
+	db = db.Where("number = ?", number)
+
+	var quarantine L2BlockQuarantine
+	if err := db.First(&quarantine).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("L2BlockQuarantine.GetL2BlockQuarantine error: %w, number: %v", err, number)
+	}
+	return &quarantine, nil
+}
+
+// GetL2BlockQuarantines returns the quarantined blocks.
+// The returned blocks are sorted in ascending order by their number.
+func (o *L2BlockQuarantine) GetL2BlockQuarantines(ctx context.Context) ([]*L2BlockQuarantine, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&L2BlockQuarantine{})
+	db = db.Order("number ASC")
+
+	var quarantines []*L2BlockQuarantine
+	if err := db.Find(&quarantines).Error; err != nil {
+		return nil, fmt.Errorf("L2BlockQuarantine.GetL2BlockQuarantines error: %w", err)
+	}
+	return quarantines, nil
+}
+
+// UpsertL2BlockQuarantine quarantines the block, or updates the quarantine of an already quarantined block.
+func (o *L2BlockQuarantine) UpsertL2BlockQuarantine(ctx context.Context, number uint64, status string, attempts uint64, lastError string) error {
+	quarantine := L2BlockQuarantine{
+		Number:    number,
+		Status:    status,
+		Attempts:  attempts,
+		LastError: lastError,
+	}
+
+	db := o.db.WithContext(ctx)
+	db = db.Model(&L2BlockQuarantine{})
+	db = db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "number"}},
+		Where:     clause.Where{Exprs: []clause.Expression{clause.Eq{Column: "deleted_at", Value: nil}}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"status": status, "attempts": attempts, "last_error": lastError, "updated_at": gorm.Expr("CURRENT_TIMESTAMP")}),
+	})
+	if err := db.Create(&quarantine).Error; err != nil {
+		return fmt.Errorf("L2BlockQuarantine.UpsertL2BlockQuarantine error: %w, number: %v, status: %v", err, number, status)
+	}
+	return nil
+}
+
+// UpdateL2BlockQuarantineStatus updates the status of a quarantined block.
+func (o *L2BlockQuarantine) UpdateL2BlockQuarantineStatus(ctx context.Context, number uint64, status string) error {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&L2BlockQuarantine{})
+	db = db.Where("number = ?", number)
+
+	tx := db.Updates(map[string]interface{}{"status": status, "updated_at": gorm.Expr("CURRENT_TIMESTAMP")})
+	if tx.Error != nil {
+		return fmt.Errorf("L2BlockQuarantine.UpdateL2BlockQuarantineStatus error: %w, number: %v, status: %v", tx.Error, number, status)
+	}
+	if tx.RowsAffected == 0 {
+		return fmt.Errorf("L2BlockQuarantine.UpdateL2BlockQuarantineStatus: block %v is not quarantined", number)
+	}
+	return nil
+}
+
+// DeleteL2BlockQuarantine releases the block from quarantine, e.g. once it is imported.
+func (o *L2BlockQuarantine) DeleteL2BlockQuarantine(ctx context.Context, number uint64) error {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&L2BlockQuarantine{})
+	db = db.Where("number = ?", number)
+
+	if err := db.Delete(&L2BlockQuarantine{}).Error; err != nil {
+		return fmt.Errorf("L2BlockQuarantine.DeleteL2BlockQuarantine error: %w, number: %v", err, number)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// L2Message is an L2 to L1 message (e.g. a withdrawal) sent by an imported L2 block.
+type L2Message struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	MessageIndex uint64 `json:"message_index" gorm:"column:message_index"`
+	MsgHash      string `json:"msg_hash" gorm:"column:msg_hash"`
+	Height       uint64 `json:"height" gorm:"column:height"`
+	Layer2Hash   string `json:"layer2_hash" gorm:"column:layer2_hash"`
+	Sender       string `json:"sender" gorm:"column:sender"`
+	Target       string `json:"target" gorm:"column:target"`
+	Value        string `json:"value" gorm:"column:value"`
+	GasLimit     uint64 `json:"gas_limit" gorm:"column:gas_limit"`
+	Calldata     string `json:"calldata" gorm:"column:calldata"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewL2Message creates a new L2Message instance.
+func NewL2Message(db *gorm.DB) *L2Message {
+	return &L2Message{db: db}
+}
+
+// TableName returns the name of the "l2_message" table.
+func (*L2Message) TableName() string {
+	return "l2_message"
+}
+
+// GetL2MessageByHash retrieves the L2 message with the given hash, nil if it is not indexed.
+func (o *L2Message) GetL2MessageByHash(ctx context.Context, msgHash string) (*L2Message, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&L2Message{})
+	db = db.Where("msg_hash = ?", msgHash)
+
+	var message L2Message
+	if err := db.First(&message).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("L2Message.GetL2MessageByHash error: %w, msg hash: %v", err, msgHash)
+	}
+	return &message, nil
+}
+
+// GetL2MessagesLEHeight retrieves the L2 messages sent by the blocks with a number less than or equal to the given
+// height, e.g. to rebuild the withdraw trie root of that block. The returned messages are sorted by their index.
+func (o *L2Message) GetL2MessagesLEHeight(ctx context.Context, height uint64) ([]*L2Message, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&L2Message{})
+	db = db.Where("height <= ?", height)
+	db = db.Order("message_index ASC")
+
+	var messages []*L2Message
+	if err := db.Find(&messages).Error; err != nil {
+		return nil, fmt.Errorf("L2Message.GetL2MessagesLEHeight error: %w, height: %v", err, height)
+	}
+	return messages, nil
+}
+
+// InsertL2Messages inserts the L2 messages into the "l2_message" table.
+func (o *L2Message) InsertL2Messages(ctx context.Context, messages []*L2Message, dbTX ...*gorm.DB) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&L2Message{})
+
+	if err := db.Create(&messages).Error; err != nil {
+		return fmt.Errorf("L2Message.InsertL2Messages error: %w, first index: %v, last index: %v", err, messages[0].MessageIndex, messages[len(messages)-1].MessageIndex)
+	}
+	return nil
+}
+
+// DeleteL2MessagesGEHeight deletes the L2 messages sent by the blocks with a number greater than or equal to the given height.
+func (o *L2Message) DeleteL2MessagesGEHeight(ctx context.Context, height uint64, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&L2Message{})
+	db = db.Where("height >= ?", height)
+
+	if err := db.Delete(&L2Message{}).Error; err != nil {
+		return fmt.Errorf("L2Message.DeleteL2MessagesGEHeight error: %w, height: %v", err, height)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LeaderLease is a lease on a role which a single instance may hold at a time, renewed by its holder.
+type LeaderLease struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	Name      string    `json:"name" gorm:"column:name"`
+	Holder    string    `json:"holder" gorm:"column:holder"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"column:expires_at"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewLeaderLease creates a new LeaderLease instance.
+func NewLeaderLease(db *gorm.DB) *LeaderLease {
+	return &LeaderLease{db: db}
+}
+
+// TableName returns the name of the "leader_lease" table.
+func (*LeaderLease) TableName() string {
+	return "leader_lease"
+}
+
+// GetLeaderLease returns the lease of the given name, nil if it was never acquired.
+func (o *LeaderLease) GetLeaderLease(ctx context.Context, name string) (*LeaderLease, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&LeaderLease{})
+	db = db.Where("name = ?", name)
+
+	var lease LeaderLease
+	if err := db.First(&lease).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("LeaderLease.GetLeaderLease error: %w, name: %v", err, name)
+	}
+	return &lease, nil
+}
+
+// AcquireLeaderLease acquires or renews the lease of the given name for the holder, for the given duration
+// from the current database time. It returns false if the lease is held by another holder and not expired.
+func (o *LeaderLease) AcquireLeaderLease(ctx context.Context, name string, holder string, duration time.Duration) (bool, error) {
+	expiresAt := gorm.Expr("CURRENT_TIMESTAMP + ? * INTERVAL '1 second'", int64(duration.Seconds()))
+
+	db := o.db.WithContext(ctx)
+	db = db.Model(&LeaderLease{})
+	db = db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "name"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Eq{Column: "deleted_at", Value: nil}}},
+		DoUpdates:   clause.Assignments(map[string]interface{}{"holder": holder, "expires_at": expiresAt, "updated_at": gorm.Expr("CURRENT_TIMESTAMP")}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "leader_lease.holder = ? OR leader_lease.expires_at < CURRENT_TIMESTAMP", Vars: []interface{}{holder}},
+		}},
+	})
+	result := db.Create(map[string]interface{}{"name": name, "holder": holder, "expires_at": expiresAt})
+	if result.Error != nil {
+		return false, fmt.Errorf("LeaderLease.AcquireLeaderLease error: %w, name: %v, holder: %v", result.Error, name, holder)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ReleaseLeaderLease expires the lease of the given name if it is held by the holder, so that another instance
+// can take over without waiting for the expiry.
+func (o *LeaderLease) ReleaseLeaderLease(ctx context.Context, name string, holder string) error {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&LeaderLease{})
+	db = db.Where("name = ? AND holder = ?", name, holder)
+
+	// the expiry is moved to the past, the timestamps are rounded to seconds.
+	if err := db.Update("expires_at", gorm.Expr("CURRENT_TIMESTAMP - INTERVAL '1 second'")).Error; err != nil {
+		return fmt.Errorf("LeaderLease.ReleaseLeaderLease error: %w, name: %v, holder: %v", err, name, holder)
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"math/big"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/scroll-tech/go-ethereum/common"
 	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
@@ -126,6 +127,52 @@ func TestL1BlockOrm(t *testing.T) {
 	assert.Len(t, updatedBlocks, 2)
 	assert.Equal(t, types.GasOracleImported, types.GasOracleStatus(updatedBlocks[0].GasOracleStatus))
 	assert.Equal(t, "txhash1", updatedBlocks[0].OracleTxHash)
+
+	err = l1BlockOrm.InsertL1Blocks(context.Background(), []L1Block{{Number: 3, Hash: "hash3", BlobBaseFee: 6}})
+	assert.NoError(t, err)
+
+	average, err := l1BlockOrm.GetAverageBlobBaseFee(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(6), average)
+	average, err = l1BlockOrm.GetAverageBlobBaseFee(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), average)
+}
+
+func TestL1MessageOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	l1MessageOrm := NewL1Message(db)
+
+	messages := []*L1Message{
+		{QueueIndex: 0, MsgHash: "msg0", Height: 1, Sender: "sender0", Target: "target0", Value: "0", Layer1Hash: "l1hash0"},
+		{QueueIndex: 1, MsgHash: "msg1", Height: 1, Sender: "sender1", Target: "target1", Value: "0", Layer1Hash: "l1hash1", IsEnforced: true},
+		{QueueIndex: 2, MsgHash: "msg2", Height: 2, Sender: "sender1", Target: "target2", Value: "0", Layer1Hash: "l1hash2", IsEnforced: true, InclusionDeadline: 1000, EnqueueTime: 400},
+	}
+	assert.NoError(t, l1MessageOrm.SaveL1Messages(context.Background(), messages))
+
+	enforcedMessages, err := l1MessageOrm.GetEnforcedL1MessagesByQueueIndices(context.Background(), []uint64{0, 1, 2, 3})
+	assert.NoError(t, err)
+	assert.Len(t, enforcedMessages, 2)
+	assert.Equal(t, uint64(1), enforcedMessages[0].QueueIndex)
+	assert.Equal(t, uint64(2), enforcedMessages[1].QueueIndex)
+
+	enforcedMessages, err = l1MessageOrm.GetEnforcedL1MessagesByLayer1Hash(context.Background(), "l1hash2")
+	assert.NoError(t, err)
+	assert.Len(t, enforcedMessages, 1)
+	assert.Equal(t, "target2", enforcedMessages[0].Target)
+
+	enforcedMessages, err = l1MessageOrm.GetEnforcedL1MessagesByLayer1Hash(context.Background(), "l1hash0")
+	assert.NoError(t, err)
+	assert.Empty(t, enforcedMessages)
+
+	enforcedMessages, err = l1MessageOrm.GetEnforcedL1MessagesGEQueueIndex(context.Background(), 2, 10)
+	assert.NoError(t, err)
+	assert.Len(t, enforcedMessages, 1)
+	assert.Equal(t, uint64(1000), enforcedMessages[0].InclusionDeadline)
+	assert.Equal(t, uint64(400), enforcedMessages[0].EnqueueTime)
 }
 
 func TestL2BlockOrm(t *testing.T) {
@@ -160,6 +207,78 @@ func TestL2BlockOrm(t *testing.T) {
 	assert.Len(t, chunkHashes, 2)
 	assert.Equal(t, "test hash", chunkHashes[0])
 	assert.Equal(t, "", chunkHashes[1])
+
+	missing, err := l2BlockOrm.GetL2BlocksWithoutRowConsumption(context.Background(), 0)
+	assert.NoError(t, err)
+	assert.Len(t, missing, 0)
+
+	block3 := *block2
+	block3.Header = gethTypes.CopyHeader(block2.Header)
+	block3.Header.Number = big.NewInt(4)
+	block3.RowConsumption = nil
+	err = l2BlockOrm.InsertL2Blocks(context.Background(), []*encoding.Block{&block3})
+	assert.NoError(t, err)
+
+	missing, err = l2BlockOrm.GetL2BlocksWithoutRowConsumption(context.Background(), 0)
+	assert.NoError(t, err)
+	assert.Len(t, missing, 1)
+	assert.Equal(t, uint64(4), missing[0].Number)
+	assert.Equal(t, block3.Header.Hash().String(), missing[0].Hash)
+
+	assert.Error(t, l2BlockOrm.UpdateRowConsumption(context.Background(), 4, block2.Header.Hash().String(), block2.RowConsumption))
+	assert.NoError(t, l2BlockOrm.UpdateRowConsumption(context.Background(), 4, missing[0].Hash, block2.RowConsumption))
+
+	missing, err = l2BlockOrm.GetL2BlocksWithoutRowConsumption(context.Background(), 0)
+	assert.NoError(t, err)
+	assert.Len(t, missing, 0)
+
+	blocks, err = l2BlockOrm.GetL2BlocksInRange(context.Background(), 4, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, block2.RowConsumption, blocks[0].RowConsumption)
+
+	hash, err := l2BlockOrm.GetL2BlockHashByNumber(context.Background(), 3)
+	assert.NoError(t, err)
+	assert.Equal(t, block2.Header.Hash().String(), hash)
+
+	importedAt, err := l2BlockOrm.GetL2BlockImportedAt(context.Background(), 3)
+	assert.NoError(t, err)
+	assert.NotNil(t, importedAt)
+
+	deleted, err := l2BlockOrm.DeleteL2BlocksGENumber(context.Background(), 3)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+
+	hash, err = l2BlockOrm.GetL2BlockHashByNumber(context.Background(), 3)
+	assert.NoError(t, err)
+	assert.Equal(t, "", hash)
+
+	importedAt, err = l2BlockOrm.GetL2BlockImportedAt(context.Background(), 3)
+	assert.NoError(t, err)
+	assert.Nil(t, importedAt)
+
+	height, err = l2BlockOrm.GetL2BlocksLatestHeight(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), height)
+
+	// the orphaned blocks can be imported again.
+	assert.NoError(t, l2BlockOrm.InsertL2Blocks(context.Background(), []*encoding.Block{block2}))
+
+	pruned, err := l2BlockOrm.PruneL2BlockTransactions(context.Background(), 2, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), pruned)
+
+	pruned, err = l2BlockOrm.PruneL2BlockTransactions(context.Background(), 3, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), pruned)
+
+	_, err = l2BlockOrm.GetL2BlocksInRange(context.Background(), 2, 3)
+	assert.Error(t, err)
+	_, err = l2BlockOrm.GetL2BlocksGEHeight(context.Background(), 2, 0)
+	assert.Error(t, err)
+
+	height, err = l2BlockOrm.GetL2BlocksLatestHeight(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), height)
 }
 
 func TestChunkOrm(t *testing.T) {
@@ -210,6 +329,11 @@ func TestChunkOrm(t *testing.T) {
 		assert.Equal(t, "", chunks[0].BatchHash)
 		assert.Equal(t, "", chunks[1].BatchHash)
 
+		chunks, err = chunkOrm.GetChunksGEBlockNumber(context.Background(), block2.Header.Number.Uint64())
+		assert.NoError(t, err)
+		assert.Len(t, chunks, 1)
+		assert.Equal(t, chunkHash2.Hex(), chunks[0].Hash)
+
 		err = chunkOrm.UpdateProvingStatus(context.Background(), chunkHash1.Hex(), types.ProvingTaskVerified)
 		assert.NoError(t, err)
 		err = chunkOrm.UpdateProvingStatus(context.Background(), chunkHash2.Hex(), types.ProvingTaskAssigned)
@@ -232,7 +356,62 @@ func TestChunkOrm(t *testing.T) {
 		assert.Equal(t, chunkHash2.Hex(), chunks[1].Hash)
 		assert.Equal(t, "test hash", chunks[0].BatchHash)
 		assert.Equal(t, "", chunks[1].BatchHash)
+
+		err = chunkOrm.UpdateBatchHashInRange(context.Background(), 1, 1, "test hash 2")
+		assert.NoError(t, err)
+		err = chunkOrm.ResetBatchHashGtIndex(context.Background(), 0)
+		assert.NoError(t, err)
+		chunks, err = chunkOrm.GetChunksGEIndex(context.Background(), 0, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, "test hash", chunks[0].BatchHash)
+		assert.Equal(t, "", chunks[1].BatchHash)
+		assert.Equal(t, int16(codecVersion), chunks[1].CodecVersion)
+
+		chunks, err = chunkOrm.GetUnbatchedChunks(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, chunks, 1)
+		assert.Equal(t, chunkHash2.Hex(), chunks[0].Hash)
+
+		// a batched chunk can't be deleted
+		_, err = chunkOrm.DeleteUnbatchedChunksGEIndex(context.Background(), 0)
+		assert.Error(t, err)
+
+		numDeleted, err := chunkOrm.DeleteUnbatchedChunksGEIndex(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), numDeleted)
+		chunks, err = chunkOrm.GetChunksGEIndex(context.Background(), 0, 0)
+		assert.NoError(t, err)
+		assert.Len(t, chunks, 1)
+
+		// the deleted chunk can be proposed again
+		dbChunk2, err = chunkOrm.InsertChunk(context.Background(), chunk2, codecVersion)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), dbChunk2.Index)
+		assert.Equal(t, chunkHash2.Hex(), dbChunk2.Hash)
+	}
+}
+
+func TestStaleChunkOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	staleChunkOrm := NewStaleChunk(db)
+
+	chunks := []*Chunk{
+		{Index: 1, Hash: "0x1", StartBlockNumber: 1, EndBlockNumber: 2},
+		{Index: 2, Hash: "0x2", StartBlockNumber: 3, EndBlockNumber: 5},
 	}
+	err = staleChunkOrm.InsertStaleChunks(context.Background(), chunks, "fork_boundary")
+	assert.NoError(t, err)
+
+	staleChunks, err := staleChunkOrm.GetStaleChunks(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Len(t, staleChunks, 1)
+	assert.Equal(t, "0x2", staleChunks[0].ChunkHash)
+	assert.Equal(t, uint64(3), staleChunks[0].StartBlockNumber)
+	assert.Equal(t, uint64(5), staleChunks[0].EndBlockNumber)
+	assert.Equal(t, "fork_boundary", staleChunks[0].Reason)
 }
 
 func TestBatchOrm(t *testing.T) {
@@ -305,6 +484,14 @@ func TestBatchOrm(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, 2, len(pendingBatches))
 
+		uncommittedCount, err := batchOrm.GetUncommittedBatchCount(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(2), uncommittedCount)
+
+		committingCount, err := batchOrm.GetCommittingBatchCount(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(0), committingCount)
+
 		rollupStatus, err := batchOrm.GetRollupStatusByHashList(context.Background(), []string{batchHash1, batchHash2})
 		assert.NoError(t, err)
 		assert.Equal(t, 2, len(rollupStatus))
@@ -333,6 +520,22 @@ func TestBatchOrm(t *testing.T) {
 		assert.Equal(t, types.GasOracleImported, types.GasOracleStatus(updatedBatch.OracleStatus))
 		assert.Equal(t, "oracleTxHash", updatedBatch.OracleTxHash)
 
+		versionedHashes, err := updatedBatch.GetBlobVersionedHashes()
+		assert.NoError(t, err)
+		assert.Nil(t, versionedHashes)
+		err = batchOrm.UpdateBlobVersionedHashes(context.Background(), batchHash2, []common.Hash{common.HexToHash("0x01")})
+		assert.NoError(t, err)
+		updatedBatch, err = batchOrm.GetLatestBatch(context.Background())
+		assert.NoError(t, err)
+		versionedHashes, err = updatedBatch.GetBlobVersionedHashes()
+		assert.NoError(t, err)
+		assert.Equal(t, []common.Hash{common.HexToHash("0x01")}, versionedHashes)
+
+		finalizedBatch, err := batchOrm.GetLatestFinalizedBatch(context.Background())
+		assert.NoError(t, err)
+		assert.NotNil(t, finalizedBatch)
+		assert.Equal(t, batchHash2, finalizedBatch.Hash)
+
 		err = batchOrm.UpdateCommitTxHashAndRollupStatus(context.Background(), batchHash2, "commitTxHash", types.RollupCommitted)
 		assert.NoError(t, err)
 		updatedBatch, err = batchOrm.GetLatestBatch(context.Background())
@@ -340,6 +543,74 @@ func TestBatchOrm(t *testing.T) {
 		assert.NotNil(t, updatedBatch)
 		assert.Equal(t, "commitTxHash", updatedBatch.CommitTxHash)
 		assert.Equal(t, types.RollupCommitted, types.RollupStatus(updatedBatch.RollupStatus))
+		assert.Equal(t, uint64(0), updatedBatch.CommitL1BlockNumber)
+
+		err = batchOrm.UpdateCommitL1BlockNumber(context.Background(), batchHash2, 100)
+		assert.NoError(t, err)
+		updatedBatch, err = batchOrm.GetLatestBatch(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(100), updatedBatch.CommitL1BlockNumber)
+
+		rolledBack, err := batchOrm.RollbackL1RollupStatus(context.Background(), 101)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), rolledBack)
+		rolledBack, err = batchOrm.RollbackL1RollupStatus(context.Background(), 100)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), rolledBack)
+		updatedBatch, err = batchOrm.GetLatestBatch(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, types.RollupCommitFailed, types.RollupStatus(updatedBatch.RollupStatus))
+		assert.Equal(t, uint64(0), updatedBatch.CommitL1BlockNumber)
+
+		// the L1 block numbers are set along with the statuses, and only filled if unknown.
+		err = batchOrm.UpdateCommittedBatch(context.Background(), batchHash2, "commitTxHash", 100)
+		assert.NoError(t, err)
+		err = batchOrm.UpdateFinalizedBatch(context.Background(), batchHash2, "finalizeTxHash", 105)
+		assert.NoError(t, err)
+		err = batchOrm.FillCommitL1BlockNumber(context.Background(), batchHash2, 101)
+		assert.NoError(t, err)
+		err = batchOrm.FillFinalizeL1BlockNumber(context.Background(), batchHash2, 106)
+		assert.NoError(t, err)
+		updatedBatch, err = batchOrm.GetLatestBatch(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, types.RollupFinalized, types.RollupStatus(updatedBatch.RollupStatus))
+		assert.Equal(t, uint64(100), updatedBatch.CommitL1BlockNumber)
+		assert.Equal(t, uint64(105), updatedBatch.FinalizeL1BlockNumber)
+
+		// a reorg of the finalize event only keeps the commit.
+		rolledBack, err = batchOrm.RollbackL1RollupStatus(context.Background(), 103)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), rolledBack)
+		updatedBatch, err = batchOrm.GetLatestBatch(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, types.RollupCommitted, types.RollupStatus(updatedBatch.RollupStatus))
+		assert.Equal(t, uint64(100), updatedBatch.CommitL1BlockNumber)
+		assert.Equal(t, uint64(0), updatedBatch.FinalizeL1BlockNumber)
+
+		// a reorg of the commit event rolls back a finalizing batch too.
+		err = batchOrm.UpdateFinalizeTxHashAndRollupStatus(context.Background(), batchHash2, "finalizeTxHash", types.RollupFinalizing)
+		assert.NoError(t, err)
+		rolledBack, err = batchOrm.RollbackL1RollupStatus(context.Background(), 100)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), rolledBack)
+		updatedBatch, err = batchOrm.GetLatestBatch(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, types.RollupCommitFailed, types.RollupStatus(updatedBatch.RollupStatus))
+		assert.Equal(t, uint64(0), updatedBatch.CommitL1BlockNumber)
+
+		// a reorg of both events rolls back a finalized batch to commit failed.
+		err = batchOrm.UpdateCommittedBatch(context.Background(), batchHash2, "commitTxHash", 100)
+		assert.NoError(t, err)
+		err = batchOrm.UpdateFinalizedBatch(context.Background(), batchHash2, "finalizeTxHash", 105)
+		assert.NoError(t, err)
+		rolledBack, err = batchOrm.RollbackL1RollupStatus(context.Background(), 100)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), rolledBack)
+		updatedBatch, err = batchOrm.GetLatestBatch(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, types.RollupCommitFailed, types.RollupStatus(updatedBatch.RollupStatus))
+		assert.Equal(t, uint64(0), updatedBatch.CommitL1BlockNumber)
+		assert.Equal(t, uint64(0), updatedBatch.FinalizeL1BlockNumber)
 
 		err = batchOrm.UpdateFinalizeTxHashAndRollupStatus(context.Background(), batchHash2, "finalizeTxHash", types.RollupFinalizeFailed)
 		assert.NoError(t, err)
@@ -349,6 +620,36 @@ func TestBatchOrm(t *testing.T) {
 		assert.NotNil(t, updatedBatch)
 		assert.Equal(t, "finalizeTxHash", updatedBatch.FinalizeTxHash)
 		assert.Equal(t, types.RollupFinalizeFailed, types.RollupStatus(updatedBatch.RollupStatus))
+
+		err = batchOrm.ResetRevertedBatch(context.Background(), batchHash2)
+		assert.NoError(t, err)
+		updatedBatch, err = batchOrm.GetLatestBatch(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, types.RollupPending, types.RollupStatus(updatedBatch.RollupStatus))
+		assert.Equal(t, types.ProvingTaskUnassigned, types.ProvingStatus(updatedBatch.ProvingStatus))
+		assert.Equal(t, "", updatedBatch.CommitTxHash)
+		assert.Nil(t, updatedBatch.CommittedAt)
+
+		reset, err := batchOrm.ResetBatchProof(context.Background(), batchHash2)
+		assert.NoError(t, err)
+		assert.False(t, reset)
+		assert.NoError(t, batchOrm.UpdateProvingStatus(context.Background(), batchHash2, types.ProvingTaskVerified))
+		reset, err = batchOrm.ResetBatchProof(context.Background(), batchHash2)
+		assert.NoError(t, err)
+		assert.True(t, reset)
+		updatedBatch, err = batchOrm.GetLatestBatch(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, types.ProvingTaskUnassigned, types.ProvingStatus(updatedBatch.ProvingStatus))
+		assert.Nil(t, updatedBatch.ProvedAt)
+
+		err = batchOrm.DeleteBatchesGtIndex(context.Background(), 0)
+		assert.NoError(t, err)
+		count, err = batchOrm.GetBatchCount(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), count)
+		updatedBatch, err = batchOrm.GetLatestBatch(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(0), updatedBatch.Index)
 	}
 }
 
@@ -433,3 +734,381 @@ func TestPendingTransactionOrm(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, types.TxStatusConfirmedFailed, status)
 }
+
+func TestFeatureFlagOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	featureFlagOrm := NewFeatureFlag(db)
+
+	err = featureFlagOrm.UpsertFeatureFlag(context.Background(), "flag_b", true, 100)
+	assert.NoError(t, err)
+	err = featureFlagOrm.UpsertFeatureFlag(context.Background(), "flag_a", false, 100)
+	assert.NoError(t, err)
+
+	flags, err := featureFlagOrm.GetFeatureFlags(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, flags, 2)
+	assert.Equal(t, "flag_a", flags[0].Name)
+	assert.False(t, flags[0].Enabled)
+	assert.Equal(t, "flag_b", flags[1].Name)
+
+	// update an existing override
+	err = featureFlagOrm.UpsertFeatureFlag(context.Background(), "flag_a", true, 25)
+	assert.NoError(t, err)
+
+	flags, err = featureFlagOrm.GetFeatureFlags(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, flags, 2)
+	assert.True(t, flags[0].Enabled)
+	assert.Equal(t, uint64(25), flags[0].RolloutPercentage)
+
+	err = featureFlagOrm.DeleteFeatureFlag(context.Background(), "flag_a")
+	assert.NoError(t, err)
+
+	flags, err = featureFlagOrm.GetFeatureFlags(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, flags, 1)
+	assert.Equal(t, "flag_b", flags[0].Name)
+
+	// re-create a deleted override
+	err = featureFlagOrm.UpsertFeatureFlag(context.Background(), "flag_a", true, 50)
+	assert.NoError(t, err)
+
+	flags, err = featureFlagOrm.GetFeatureFlags(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, flags, 2)
+	assert.Equal(t, uint64(50), flags[0].RolloutPercentage)
+}
+
+func TestProposerPauseOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	proposerPauseOrm := NewProposerPause(db)
+
+	// a proposer never paused has no pause state
+	pause, err := proposerPauseOrm.GetProposerPause(context.Background(), "batch_proposer")
+	assert.NoError(t, err)
+	assert.Nil(t, pause)
+
+	err = proposerPauseOrm.UpsertProposerPause(context.Background(), "batch_proposer", true, "contract upgrade")
+	assert.NoError(t, err)
+
+	pause, err = proposerPauseOrm.GetProposerPause(context.Background(), "batch_proposer")
+	assert.NoError(t, err)
+	assert.True(t, pause.Paused)
+	assert.Equal(t, "contract upgrade", pause.Reason)
+
+	pause, err = proposerPauseOrm.GetProposerPause(context.Background(), "chunk_proposer")
+	assert.NoError(t, err)
+	assert.Nil(t, pause)
+
+	// update an existing pause state
+	err = proposerPauseOrm.UpsertProposerPause(context.Background(), "batch_proposer", false, "")
+	assert.NoError(t, err)
+
+	pause, err = proposerPauseOrm.GetProposerPause(context.Background(), "batch_proposer")
+	assert.NoError(t, err)
+	assert.False(t, pause.Paused)
+	assert.Empty(t, pause.Reason)
+}
+
+func TestProposerStateOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	proposerStateOrm := NewProposerState(db)
+
+	// a proposer never snapshotted has no state
+	state, err := proposerStateOrm.GetProposerState(context.Background(), "chunk_proposer")
+	assert.NoError(t, err)
+	assert.Nil(t, state)
+
+	err = proposerStateOrm.UpsertProposerState(context.Background(), "chunk_proposer", `{"estimations":[]}`)
+	assert.NoError(t, err)
+
+	state, err = proposerStateOrm.GetProposerState(context.Background(), "chunk_proposer")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"estimations":[]}`, state.State)
+
+	// replace an existing state
+	err = proposerStateOrm.UpsertProposerState(context.Background(), "chunk_proposer", `{}`)
+	assert.NoError(t, err)
+
+	state, err = proposerStateOrm.GetProposerState(context.Background(), "chunk_proposer")
+	assert.NoError(t, err)
+	assert.Equal(t, `{}`, state.State)
+}
+
+func TestL1WatcherCheckpointOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	checkpointOrm := NewL1WatcherCheckpoint(db)
+
+	checkpoints, err := checkpointOrm.GetL1WatcherCheckpoints(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, checkpoints)
+
+	assert.NoError(t, checkpointOrm.UpsertL1WatcherCheckpoint(context.Background(), "l1_message", 100, "0x01"))
+	assert.NoError(t, checkpointOrm.UpsertL1WatcherCheckpoint(context.Background(), "finalize_batch", 80, ""))
+	// replace an existing checkpoint
+	assert.NoError(t, checkpointOrm.UpsertL1WatcherCheckpoint(context.Background(), "l1_message", 110, "0x02"))
+
+	checkpoints, err = checkpointOrm.GetL1WatcherCheckpoints(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, checkpoints, 2)
+	assert.Equal(t, "finalize_batch", checkpoints[0].EventFilter)
+	assert.Equal(t, uint64(80), checkpoints[0].ProcessedHeight)
+	assert.Equal(t, "l1_message", checkpoints[1].EventFilter)
+	assert.Equal(t, uint64(110), checkpoints[1].ProcessedHeight)
+	assert.Equal(t, "0x02", checkpoints[1].BlockHash)
+
+	// only the checkpoints beyond the height are rewound
+	assert.NoError(t, checkpointOrm.RewindL1WatcherCheckpoints(context.Background(), 90))
+	checkpoints, err = checkpointOrm.GetL1WatcherCheckpoints(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(80), checkpoints[0].ProcessedHeight)
+	assert.Equal(t, uint64(90), checkpoints[1].ProcessedHeight)
+	assert.Equal(t, "", checkpoints[1].BlockHash)
+}
+
+func TestLeaderLeaseOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	leaseOrm := NewLeaderLease(db)
+
+	lease, err := leaseOrm.GetLeaderLease(context.Background(), "l1_watcher")
+	assert.NoError(t, err)
+	assert.Nil(t, lease)
+
+	acquired, err := leaseOrm.AcquireLeaderLease(context.Background(), "l1_watcher", "instance-a", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+
+	// the lease is held by instance-a until it expires.
+	acquired, err = leaseOrm.AcquireLeaderLease(context.Background(), "l1_watcher", "instance-b", time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, acquired)
+	acquired, err = leaseOrm.AcquireLeaderLease(context.Background(), "l1_watcher", "instance-a", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+
+	lease, err = leaseOrm.GetLeaderLease(context.Background(), "l1_watcher")
+	assert.NoError(t, err)
+	assert.Equal(t, "instance-a", lease.Holder)
+
+	// a released lease is taken over right away.
+	assert.NoError(t, leaseOrm.ReleaseLeaderLease(context.Background(), "l1_watcher", "instance-b"))
+	acquired, err = leaseOrm.AcquireLeaderLease(context.Background(), "l1_watcher", "instance-b", time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, acquired)
+	assert.NoError(t, leaseOrm.ReleaseLeaderLease(context.Background(), "l1_watcher", "instance-a"))
+	acquired, err = leaseOrm.AcquireLeaderLease(context.Background(), "l1_watcher", "instance-b", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+}
+
+func TestL2BlockQuarantineOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	quarantineOrm := NewL2BlockQuarantine(db)
+
+	quarantine, err := quarantineOrm.GetL2BlockQuarantine(context.Background(), 100)
+	assert.NoError(t, err)
+	assert.Nil(t, quarantine)
+	assert.Error(t, quarantineOrm.UpdateL2BlockQuarantineStatus(context.Background(), 100, L2BlockQuarantineStatusRetry))
+
+	assert.NoError(t, quarantineOrm.UpsertL2BlockQuarantine(context.Background(), 100, L2BlockQuarantineStatusQuarantined, 5, "missing row consumption"))
+	assert.NoError(t, quarantineOrm.UpsertL2BlockQuarantine(context.Background(), 100, L2BlockQuarantineStatusQuarantined, 6, "timeout"))
+	assert.NoError(t, quarantineOrm.UpdateL2BlockQuarantineStatus(context.Background(), 100, L2BlockQuarantineStatusResolved))
+
+	quarantines, err := quarantineOrm.GetL2BlockQuarantines(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, quarantines, 1)
+	assert.Equal(t, uint64(100), quarantines[0].Number)
+	assert.Equal(t, L2BlockQuarantineStatusResolved, quarantines[0].Status)
+	assert.Equal(t, uint64(6), quarantines[0].Attempts)
+	assert.Equal(t, "timeout", quarantines[0].LastError)
+
+	assert.NoError(t, quarantineOrm.DeleteL2BlockQuarantine(context.Background(), 100))
+	quarantine, err = quarantineOrm.GetL2BlockQuarantine(context.Background(), 100)
+	assert.NoError(t, err)
+	assert.Nil(t, quarantine)
+}
+
+func TestL2MessageOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	l2MessageOrm := NewL2Message(db)
+	assert.NoError(t, l2MessageOrm.InsertL2Messages(context.Background(), nil))
+	assert.NoError(t, l2MessageOrm.InsertL2Messages(context.Background(), []*L2Message{
+		{MessageIndex: 0, MsgHash: "0x01", Height: 10, Layer2Hash: "0x0a"},
+		{MessageIndex: 1, MsgHash: "0x02", Height: 12, Layer2Hash: "0x0b", Sender: "0x1111", Target: "0x2222", Value: "1", GasLimit: 100, Calldata: "0x"},
+	}))
+
+	message, err := l2MessageOrm.GetL2MessageByHash(context.Background(), "0x02")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), message.MessageIndex)
+	assert.Equal(t, uint64(12), message.Height)
+	assert.Equal(t, "0x2222", message.Target)
+
+	messages, err := l2MessageOrm.GetL2MessagesLEHeight(context.Background(), 11)
+	assert.NoError(t, err)
+	assert.Len(t, messages, 1)
+	assert.Equal(t, "0x01", messages[0].MsgHash)
+
+	assert.NoError(t, l2MessageOrm.DeleteL2MessagesGEHeight(context.Background(), 11))
+	message, err = l2MessageOrm.GetL2MessageByHash(context.Background(), "0x02")
+	assert.NoError(t, err)
+	assert.Nil(t, message)
+
+	// the messages of the deleted blocks can be indexed again.
+	assert.NoError(t, l2MessageOrm.InsertL2Messages(context.Background(), []*L2Message{{MessageIndex: 1, MsgHash: "0x03", Height: 11, Layer2Hash: "0x0c"}}))
+}
+
+func TestPreparedTransactionOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	preparedTxOrm := NewPreparedTransaction(db)
+	assert.NoError(t, preparedTxOrm.UpsertPreparedTransaction(context.Background(), "0x02", 2, "commit_batch", `{"v":1}`))
+	assert.NoError(t, preparedTxOrm.UpsertPreparedTransaction(context.Background(), "0x01", 1, "commit_batch", `{"v":1}`))
+	assert.NoError(t, preparedTxOrm.UpsertPreparedTransaction(context.Background(), "0x01", 1, "finalize_batch", `{"v":1}`))
+	assert.NoError(t, preparedTxOrm.UpdatePreparedTransactionExecuted(context.Background(), "0x01", "commit_batch", "0xaa"))
+
+	txs, err := preparedTxOrm.GetPreparedTransactions(context.Background(), PreparedTransactionStatusPrepared, 0)
+	assert.NoError(t, err)
+	assert.Len(t, txs, 2)
+	assert.Equal(t, "finalize_batch", txs[0].Kind)
+	assert.Equal(t, uint64(2), txs[1].BatchIndex)
+
+	// preparing a transaction again replaces it.
+	assert.NoError(t, preparedTxOrm.UpsertPreparedTransaction(context.Background(), "0x01", 1, "commit_batch", `{"v":2}`))
+	txs, err = preparedTxOrm.GetPreparedTransactions(context.Background(), "", 1)
+	assert.NoError(t, err)
+	assert.Len(t, txs, 1)
+	assert.Equal(t, PreparedTransactionStatusPrepared, txs[0].Status)
+	assert.Equal(t, `{"v":2}`, txs[0].Payload)
+	assert.Empty(t, txs[0].TxHash)
+}
+
+func TestSkippedL1MessageOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	skippedL1MessageOrm := NewSkippedL1Message(db)
+	assert.NoError(t, skippedL1MessageOrm.UpsertSkippedL1Messages(context.Background(), []*SkippedL1Message{
+		{QueueIndex: 3, MsgHash: "0x03", BatchIndex: 1, BatchHash: "0x01"},
+		{QueueIndex: 1, MsgHash: "0x01", BatchIndex: 1, BatchHash: "0x01", SkipReason: "row consumption overflow", SkipBlockNumber: 10},
+	}))
+
+	messages, err := skippedL1MessageOrm.GetSkippedL1Messages(context.Background(), 0, 0)
+	assert.NoError(t, err)
+	assert.Len(t, messages, 2)
+	assert.Equal(t, uint64(1), messages[0].QueueIndex)
+	assert.Equal(t, "row consumption overflow", messages[0].SkipReason)
+
+	// recording a message again, e.g. when its batch is committed again, replaces it.
+	assert.NoError(t, skippedL1MessageOrm.UpsertSkippedL1Messages(context.Background(), []*SkippedL1Message{
+		{QueueIndex: 3, MsgHash: "0x03", BatchIndex: 2, BatchHash: "0x02", SkipReason: "unknown"},
+	}))
+	messages, err = skippedL1MessageOrm.GetSkippedL1Messages(context.Background(), 2, 1)
+	assert.NoError(t, err)
+	assert.Len(t, messages, 1)
+	assert.Equal(t, "0x02", messages[0].BatchHash)
+	assert.Equal(t, "unknown", messages[0].SkipReason)
+
+	message, err := skippedL1MessageOrm.GetSkippedL1MessageByQueueIndex(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Nil(t, message)
+
+	l1MessageOrm := NewL1Message(db)
+	assert.NoError(t, l1MessageOrm.SaveL1Messages(context.Background(), []*L1Message{{QueueIndex: 3, MsgHash: "0x03", Layer1Hash: "0x0a"}}))
+	l1Messages, err := l1MessageOrm.GetL1MessagesByQueueIndices(context.Background(), []uint64{1, 3})
+	assert.NoError(t, err)
+	assert.Len(t, l1Messages, 1)
+	assert.Equal(t, "0x03", l1Messages[0].MsgHash)
+}
+
+func TestTransactionCostOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	dbBatch, err := batchOrm.InsertBatch(context.Background(), &encoding.Batch{
+		Index:  0,
+		Chunks: []*encoding.Chunk{{Blocks: []*encoding.Block{block1}}},
+	}, encoding.CodecV0)
+	assert.NoError(t, err)
+
+	commitMeta := &SenderMeta{Name: "testName", Service: "testService", Address: common.HexToAddress("0x1"), Type: types.SenderTypeCommitBatch}
+	finalizeMeta := &SenderMeta{Name: "testName", Service: "testService", Address: common.HexToAddress("0x2"), Type: types.SenderTypeFinalizeBatch}
+	day := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	transactionCostOrm := NewTransactionCost(db)
+	commitReceipt := &gethTypes.Receipt{
+		Status:            gethTypes.ReceiptStatusSuccessful,
+		TxHash:            common.HexToHash("0x0a"),
+		BlockNumber:       big.NewInt(10),
+		GasUsed:           100,
+		EffectiveGasPrice: big.NewInt(3),
+		BlobGasUsed:       131072,
+		BlobGasPrice:      big.NewInt(1),
+	}
+	assert.NoError(t, transactionCostOrm.InsertTransactionCost(context.Background(), dbBatch.Hash, commitMeta, commitReceipt, uint64(day.Unix())))
+	// a confirmation recorded again is ignored.
+	assert.NoError(t, transactionCostOrm.InsertTransactionCost(context.Background(), dbBatch.Hash, commitMeta, commitReceipt, uint64(day.Unix())))
+	assert.NoError(t, transactionCostOrm.InsertTransactionCost(context.Background(), dbBatch.Hash, finalizeMeta, &gethTypes.Receipt{
+		Status:            gethTypes.ReceiptStatusFailed,
+		TxHash:            common.HexToHash("0x0b"),
+		BlockNumber:       big.NewInt(11),
+		GasUsed:           50,
+		EffectiveGasPrice: big.NewInt(4),
+	}, uint64(day.Unix())))
+	assert.NoError(t, transactionCostOrm.InsertTransactionCost(context.Background(), dbBatch.Hash, finalizeMeta, &gethTypes.Receipt{
+		Status:            gethTypes.ReceiptStatusSuccessful,
+		TxHash:            common.HexToHash("0x0c"),
+		BlockNumber:       big.NewInt(20),
+		GasUsed:           60,
+		EffectiveGasPrice: big.NewInt(5),
+	}, uint64(day.AddDate(0, 0, 1).Unix())))
+
+	costs, err := transactionCostOrm.GetTransactionCostsByContextID(context.Background(), dbBatch.Hash)
+	assert.NoError(t, err)
+	assert.Len(t, costs, 3)
+	assert.Equal(t, "131372", costs[0].Fee)
+	assert.False(t, costs[1].IsSuccessful)
+
+	summaries, err := transactionCostOrm.GetBatchTransactionCosts(context.Background(), 0, 1)
+	assert.NoError(t, err)
+	assert.Len(t, summaries, 2)
+	assert.Equal(t, dbBatch.Hash, summaries[0].BatchHash)
+	assert.Equal(t, types.SenderTypeCommitBatch, summaries[0].SenderType)
+	assert.Equal(t, uint64(1), summaries[0].TxCount)
+	assert.Equal(t, uint64(131072), summaries[0].BlobGasUsed)
+	assert.Equal(t, types.SenderTypeFinalizeBatch, summaries[1].SenderType)
+	assert.Equal(t, uint64(2), summaries[1].TxCount)
+	assert.Equal(t, uint64(1), summaries[1].FailedCount)
+	assert.Equal(t, uint64(110), summaries[1].GasUsed)
+	assert.Equal(t, "500", summaries[1].Fee)
+
+	summaries, err = transactionCostOrm.GetDailyTransactionCosts(context.Background(), day.AddDate(0, 0, 1).Truncate(24*time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, summaries, 1)
+	assert.Equal(t, "2024-03-02", summaries[0].Day)
+	assert.Equal(t, "300", summaries[0].Fee)
+}
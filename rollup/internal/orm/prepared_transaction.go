@@ -0,0 +1,101 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Status of a prepared transaction.
+const (
+	// PreparedTransactionStatusPrepared indicates the transaction is waiting to be executed by the multisig
+	PreparedTransactionStatusPrepared = "prepared"
+	// PreparedTransactionStatusExecuted indicates the call of the transaction was observed on the rollup contract
+	PreparedTransactionStatusExecuted = "executed"
+)
+
+// PreparedTransaction is a commit or finalize transaction prepared for a multisig instead of being sent by the relayer.
+type PreparedTransaction struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	BatchHash  string `json:"batch_hash" gorm:"column:batch_hash"`
+	BatchIndex uint64 `json:"batch_index" gorm:"column:batch_index"`
+	Kind       string `json:"kind" gorm:"column:kind"`
+	Status     string `json:"status" gorm:"column:status"`
+	Payload    string `json:"payload" gorm:"column:payload"`
+	TxHash     string `json:"tx_hash" gorm:"column:tx_hash"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewPreparedTransaction creates a new PreparedTransaction instance.
+func NewPreparedTransaction(db *gorm.DB) *PreparedTransaction {
+	return &PreparedTransaction{db: db}
+}
+
+// TableName returns the name of the "prepared_transaction" table.
+func (*PreparedTransaction) TableName() string {
+	return "prepared_transaction"
+}
+
+// GetPreparedTransactions returns the prepared transactions with the given status, all of them if status is empty.
+// The returned transactions are sorted in ascending order by their batch index, the commit before the finalization.
+func (o *PreparedTransaction) GetPreparedTransactions(ctx context.Context, status string, limit int) ([]*PreparedTransaction, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&PreparedTransaction{})
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
+	db = db.Order("batch_index ASC, kind ASC")
+	if limit > 0 {
+		db = db.Limit(limit)
+	}
+
+	var txs []*PreparedTransaction
+	if err := db.Find(&txs).Error; err != nil {
+		return nil, fmt.Errorf("PreparedTransaction.GetPreparedTransactions error: %w, status: %v", err, status)
+	}
+	return txs, nil
+}
+
+// UpsertPreparedTransaction stores the prepared transaction of the batch, replacing the one of the same kind
+// prepared before, e.g. if the batch is committed again.
+func (o *PreparedTransaction) UpsertPreparedTransaction(ctx context.Context, batchHash string, batchIndex uint64, kind string, payload string) error {
+	preparedTx := PreparedTransaction{
+		BatchHash:  batchHash,
+		BatchIndex: batchIndex,
+		Kind:       kind,
+		Status:     PreparedTransactionStatusPrepared,
+		Payload:    payload,
+	}
+
+	db := o.db.WithContext(ctx)
+	db = db.Model(&PreparedTransaction{})
+	db = db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "batch_hash"}, {Name: "kind"}},
+		Where:     clause.Where{Exprs: []clause.Expression{clause.Eq{Column: "deleted_at", Value: nil}}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"status": PreparedTransactionStatusPrepared, "payload": payload, "tx_hash": "", "updated_at": gorm.Expr("CURRENT_TIMESTAMP")}),
+	})
+	if err := db.Create(&preparedTx).Error; err != nil {
+		return fmt.Errorf("PreparedTransaction.UpsertPreparedTransaction error: %w, batch hash: %v, kind: %v", err, batchHash, kind)
+	}
+	return nil
+}
+
+// UpdatePreparedTransactionExecuted marks the prepared transaction of the batch as executed by the L1 transaction.
+func (o *PreparedTransaction) UpdatePreparedTransactionExecuted(ctx context.Context, batchHash string, kind string, txHash string) error {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&PreparedTransaction{})
+	db = db.Where("batch_hash = ? AND kind = ?", batchHash, kind)
+
+	if err := db.Updates(map[string]interface{}{"status": PreparedTransactionStatusExecuted, "tx_hash": txHash, "updated_at": gorm.Expr("CURRENT_TIMESTAMP")}).Error; err != nil {
+		return fmt.Errorf("PreparedTransaction.UpdatePreparedTransactionExecuted error: %w, batch hash: %v, kind: %v", err, batchHash, kind)
+	}
+	return nil
+}
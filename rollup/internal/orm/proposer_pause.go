@@ -0,0 +1,72 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ProposerPause is the pause state of a proposer set by the operators.
+type ProposerPause struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	Proposer string `json:"proposer" gorm:"column:proposer"`
+	Paused   bool   `json:"paused" gorm:"column:paused"`
+	Reason   string `json:"reason" gorm:"column:reason"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewProposerPause creates a new ProposerPause instance.
+func NewProposerPause(db *gorm.DB) *ProposerPause {
+	return &ProposerPause{db: db}
+}
+
+// TableName returns the name of the "proposer_pause" table.
+func (*ProposerPause) TableName() string {
+	return "proposer_pause"
+}
+
+// GetProposerPause returns the pause state of the proposer, nil if it was never paused.
+func (o *ProposerPause) GetProposerPause(ctx context.Context, proposer string) (*ProposerPause, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&ProposerPause{})
+	db = db.Where("proposer = ?", proposer)
+
+	var pause ProposerPause
+	if err := db.First(&pause).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("ProposerPause.GetProposerPause error: %w, proposer: %v", err, proposer)
+	}
+	return &pause, nil
+}
+
+// UpsertProposerPause inserts or updates the pause state of the proposer.
+func (o *ProposerPause) UpsertProposerPause(ctx context.Context, proposer string, paused bool, reason string) error {
+	pause := ProposerPause{
+		Proposer: proposer,
+		Paused:   paused,
+		Reason:   reason,
+	}
+
+	db := o.db.WithContext(ctx)
+	db = db.Model(&ProposerPause{})
+	db = db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "proposer"}},
+		Where:     clause.Where{Exprs: []clause.Expression{clause.Eq{Column: "deleted_at", Value: nil}}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"paused": paused, "reason": reason, "updated_at": gorm.Expr("CURRENT_TIMESTAMP")}),
+	})
+	if err := db.Create(&pause).Error; err != nil {
+		return fmt.Errorf("ProposerPause.UpsertProposerPause error: %w, proposer: %v, paused: %v", err, proposer, paused)
+	}
+	return nil
+}
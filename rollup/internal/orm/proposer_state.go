@@ -0,0 +1,71 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ProposerState is the snapshot of the in-memory state of a proposer, restored after a restart.
+type ProposerState struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	Proposer string `json:"proposer" gorm:"column:proposer"`
+	// State is the JSON encoded snapshot, its layout is owned by the proposer.
+	State string `json:"state" gorm:"column:state"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewProposerState creates a new ProposerState instance.
+func NewProposerState(db *gorm.DB) *ProposerState {
+	return &ProposerState{db: db}
+}
+
+// TableName returns the name of the "proposer_state" table.
+func (*ProposerState) TableName() string {
+	return "proposer_state"
+}
+
+// GetProposerState returns the state snapshot of the proposer, nil if it was never snapshotted.
+func (o *ProposerState) GetProposerState(ctx context.Context, proposer string) (*ProposerState, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&ProposerState{})
+	db = db.Where("proposer = ?", proposer)
+
+	var state ProposerState
+	if err := db.First(&state).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("ProposerState.GetProposerState error: %w, proposer: %v", err, proposer)
+	}
+	return &state, nil
+}
+
+// UpsertProposerState inserts or replaces the state snapshot of the proposer.
+func (o *ProposerState) UpsertProposerState(ctx context.Context, proposer string, state string) error {
+	proposerState := ProposerState{
+		Proposer: proposer,
+		State:    state,
+	}
+
+	db := o.db.WithContext(ctx)
+	db = db.Model(&ProposerState{})
+	db = db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "proposer"}},
+		Where:     clause.Where{Exprs: []clause.Expression{clause.Eq{Column: "deleted_at", Value: nil}}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"state": state, "updated_at": gorm.Expr("CURRENT_TIMESTAMP")}),
+	})
+	if err := db.Create(&proposerState).Error; err != nil {
+		return fmt.Errorf("ProposerState.UpsertProposerState error: %w, proposer: %v", err, proposer)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SkippedL1Message is an L1 message marked as skipped in the skipped bitmap of a committed batch.
+type SkippedL1Message struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	QueueIndex      uint64 `json:"queue_index" gorm:"column:queue_index"`
+	MsgHash         string `json:"msg_hash" gorm:"column:msg_hash"`
+	BatchIndex      uint64 `json:"batch_index" gorm:"column:batch_index"`
+	BatchHash       string `json:"batch_hash" gorm:"column:batch_hash"`
+	SkipReason      string `json:"skip_reason" gorm:"column:skip_reason"`
+	SkipBlockNumber uint64 `json:"skip_block_number" gorm:"column:skip_block_number"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewSkippedL1Message creates a new SkippedL1Message instance.
+func NewSkippedL1Message(db *gorm.DB) *SkippedL1Message {
+	return &SkippedL1Message{db: db}
+}
+
+// TableName returns the name of the "skipped_l1_message" table.
+func (*SkippedL1Message) TableName() string {
+	return "skipped_l1_message"
+}
+
+// GetSkippedL1Messages returns the skipped messages from the given queue index on, in ascending order of queue index.
+func (o *SkippedL1Message) GetSkippedL1Messages(ctx context.Context, startQueueIndex uint64, limit int) ([]*SkippedL1Message, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&SkippedL1Message{})
+	db = db.Where("queue_index >= ?", startQueueIndex)
+	db = db.Order("queue_index ASC")
+	if limit > 0 {
+		db = db.Limit(limit)
+	}
+
+	var messages []*SkippedL1Message
+	if err := db.Find(&messages).Error; err != nil {
+		return nil, fmt.Errorf("SkippedL1Message.GetSkippedL1Messages error: %w, start queue index: %v", err, startQueueIndex)
+	}
+	return messages, nil
+}
+
+// GetSkippedL1MessageByQueueIndex returns the skipped message of the given queue index, nil if the message isn't skipped.
+func (o *SkippedL1Message) GetSkippedL1MessageByQueueIndex(ctx context.Context, queueIndex uint64) (*SkippedL1Message, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&SkippedL1Message{})
+	db = db.Where("queue_index = ?", queueIndex)
+
+	var message SkippedL1Message
+	if err := db.First(&message).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("SkippedL1Message.GetSkippedL1MessageByQueueIndex error: %w, queue index: %v", err, queueIndex)
+	}
+	return &message, nil
+}
+
+// UpsertSkippedL1Messages stores the skipped messages, replacing the batch and the reason of the ones stored
+// before, e.g. if the batch is committed again.
+func (o *SkippedL1Message) UpsertSkippedL1Messages(ctx context.Context, messages []*SkippedL1Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	db := o.db.WithContext(ctx)
+	db = db.Model(&SkippedL1Message{})
+	db = db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "queue_index"}},
+		Where:     clause.Where{Exprs: []clause.Expression{clause.Eq{Column: "deleted_at", Value: nil}}},
+		DoUpdates: clause.AssignmentColumns([]string{"msg_hash", "batch_index", "batch_hash", "skip_reason", "skip_block_number", "updated_at"}),
+	})
+	if err := db.Create(&messages).Error; err != nil {
+		return fmt.Errorf("SkippedL1Message.UpsertSkippedL1Messages error: %w, messages: %v", err, len(messages))
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// StaleChunk is an unbatched chunk released by the chunk proposer, e.g. because it's invalidated by a fork.
+type StaleChunk struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	ChunkIndex       uint64 `json:"chunk_index" gorm:"column:chunk_index"`
+	ChunkHash        string `json:"chunk_hash" gorm:"column:chunk_hash"`
+	StartBlockNumber uint64 `json:"start_block_number" gorm:"column:start_block_number"`
+	EndBlockNumber   uint64 `json:"end_block_number" gorm:"column:end_block_number"`
+	Reason           string `json:"reason" gorm:"column:reason"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewStaleChunk creates a new StaleChunk instance.
+func NewStaleChunk(db *gorm.DB) *StaleChunk {
+	return &StaleChunk{db: db}
+}
+
+// TableName returns the name of the "stale_chunk" table.
+func (*StaleChunk) TableName() string {
+	return "stale_chunk"
+}
+
+// GetStaleChunks returns the stale chunks with an index greater than or equal to the given index, ordered by index.
+func (o *StaleChunk) GetStaleChunks(ctx context.Context, index uint64) ([]*StaleChunk, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&StaleChunk{})
+	db = db.Where("chunk_index >= ?", index)
+	db = db.Order("chunk_index ASC, id ASC")
+
+	var staleChunks []*StaleChunk
+	if err := db.Find(&staleChunks).Error; err != nil {
+		return nil, fmt.Errorf("StaleChunk.GetStaleChunks error: %w, index: %v", err, index)
+	}
+	return staleChunks, nil
+}
+
+// InsertStaleChunks records the released chunks with the reason.
+func (o *StaleChunk) InsertStaleChunks(ctx context.Context, chunks []*Chunk, reason string, dbTX ...*gorm.DB) error {
+	if len(chunks) == 0 {
+		return nil
+	}
+
+	staleChunks := make([]*StaleChunk, 0, len(chunks))
+	for _, chunk := range chunks {
+		staleChunks = append(staleChunks, &StaleChunk{
+			ChunkIndex:       chunk.Index,
+			ChunkHash:        chunk.Hash,
+			StartBlockNumber: chunk.StartBlockNumber,
+			EndBlockNumber:   chunk.EndBlockNumber,
+			Reason:           reason,
+		})
+	}
+
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&StaleChunk{})
+
+	if err := db.Create(&staleChunks).Error; err != nil {
+		return fmt.Errorf("StaleChunk.InsertStaleChunks error: %w, start index: %v", err, chunks[0].Index)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"scroll-tech/common/types"
+)
+
+// TransactionCost is the cost paid by a sender for a confirmed transaction, the wei amounts are decimal strings.
+type TransactionCost struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	TxHash            string           `json:"tx_hash" gorm:"column:tx_hash"`
+	ContextID         string           `json:"context_id" gorm:"column:context_id"`
+	SenderName        string           `json:"sender_name" gorm:"column:sender_name"`
+	SenderService     string           `json:"sender_service" gorm:"column:sender_service"`
+	SenderAddress     string           `json:"sender_address" gorm:"column:sender_address"`
+	SenderType        types.SenderType `json:"sender_type" gorm:"column:sender_type"`
+	IsSuccessful      bool             `json:"is_successful" gorm:"column:is_successful"`
+	BlockNumber       uint64           `json:"block_number" gorm:"column:block_number"`
+	BlockTimestamp    uint64           `json:"block_timestamp" gorm:"column:block_timestamp"`
+	GasUsed           uint64           `json:"gas_used" gorm:"column:gas_used"`
+	EffectiveGasPrice string           `json:"effective_gas_price" gorm:"column:effective_gas_price;type:decimal(78)"`
+	BlobGasUsed       uint64           `json:"blob_gas_used" gorm:"column:blob_gas_used"`
+	BlobGasPrice      string           `json:"blob_gas_price" gorm:"column:blob_gas_price;type:decimal(78)"`
+	L1Fee             string           `json:"l1_fee" gorm:"column:l1_fee;type:decimal(78)"`
+	Fee               string           `json:"fee" gorm:"column:fee;type:decimal(78)"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// TransactionCostSummary is the cost of the confirmed transactions of a sender type, aggregated per batch or per day.
+type TransactionCostSummary struct {
+	// BatchIndex and BatchHash are set by the aggregation per batch.
+	BatchIndex uint64 `json:"batch_index" gorm:"column:batch_index"`
+	BatchHash  string `json:"batch_hash" gorm:"column:batch_hash"`
+	// Day is the UTC day of the L1 blocks, "YYYY-MM-DD", set by the aggregation per day.
+	Day string `json:"day" gorm:"column:day"`
+
+	SenderType  types.SenderType `json:"sender_type" gorm:"column:sender_type"`
+	TxCount     uint64           `json:"tx_count" gorm:"column:tx_count"`
+	FailedCount uint64           `json:"failed_count" gorm:"column:failed_count"`
+	GasUsed     uint64           `json:"gas_used" gorm:"column:gas_used"`
+	BlobGasUsed uint64           `json:"blob_gas_used" gorm:"column:blob_gas_used"`
+	Fee         string           `json:"fee" gorm:"column:fee"`
+}
+
+// transactionCostSummaryColumns are the aggregated columns of a TransactionCostSummary.
+const transactionCostSummaryColumns = "transaction_cost.sender_type, COUNT(*) AS tx_count, " +
+	"COUNT(*) FILTER (WHERE NOT transaction_cost.is_successful) AS failed_count, " +
+	"SUM(transaction_cost.gas_used) AS gas_used, SUM(transaction_cost.blob_gas_used) AS blob_gas_used, " +
+	"SUM(transaction_cost.fee) AS fee"
+
+// NewTransactionCost creates a new TransactionCost instance.
+func NewTransactionCost(db *gorm.DB) *TransactionCost {
+	return &TransactionCost{db: db}
+}
+
+// TableName returns the name of the "transaction_cost" table.
+func (*TransactionCost) TableName() string {
+	return "transaction_cost"
+}
+
+// GetTransactionCostsByContextID returns the costs of the confirmed transactions of a context, in block number order.
+func (o *TransactionCost) GetTransactionCostsByContextID(ctx context.Context, contextID string) ([]*TransactionCost, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&TransactionCost{})
+	db = db.Where("context_id = ?", contextID)
+	db = db.Order("block_number ASC")
+
+	var costs []*TransactionCost
+	if err := db.Find(&costs).Error; err != nil {
+		return nil, fmt.Errorf("TransactionCost.GetTransactionCostsByContextID error: %w, context id: %v", err, contextID)
+	}
+	return costs, nil
+}
+
+// GetBatchTransactionCosts returns the costs of the commit, finalize and l2 gas oracle transactions of the batches
+// from the given index on, aggregated per batch and sender type, in batch index order.
+func (o *TransactionCost) GetBatchTransactionCosts(ctx context.Context, startIndex uint64, limit int) ([]*TransactionCostSummary, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Table("transaction_cost")
+	db = db.Select("batch.index AS batch_index, batch.hash AS batch_hash, " + transactionCostSummaryColumns)
+	db = db.Joins("JOIN batch ON batch.hash = transaction_cost.context_id AND batch.deleted_at IS NULL")
+	db = db.Where("transaction_cost.deleted_at IS NULL")
+	db = db.Where("batch.index >= ?", startIndex)
+	if limit > 0 {
+		db = db.Where("batch.index < ?", startIndex+uint64(limit))
+	}
+	db = db.Group("batch.index, batch.hash, transaction_cost.sender_type")
+	db = db.Order("batch.index ASC, transaction_cost.sender_type ASC")
+
+	var summaries []*TransactionCostSummary
+	if err := db.Scan(&summaries).Error; err != nil {
+		return nil, fmt.Errorf("TransactionCost.GetBatchTransactionCosts error: %w, start index: %v, limit: %v", err, startIndex, limit)
+	}
+	return summaries, nil
+}
+
+// GetDailyTransactionCosts returns the costs of the transactions confirmed in the L1 blocks from the given time on,
+// aggregated per UTC day and sender type, in day order.
+func (o *TransactionCost) GetDailyTransactionCosts(ctx context.Context, since time.Time) ([]*TransactionCostSummary, error) {
+	const day = "to_char(to_timestamp(transaction_cost.block_timestamp) AT TIME ZONE 'UTC', 'YYYY-MM-DD')"
+
+	db := o.db.WithContext(ctx)
+	db = db.Table("transaction_cost")
+	db = db.Select(day + " AS day, " + transactionCostSummaryColumns)
+	db = db.Where("transaction_cost.deleted_at IS NULL")
+	db = db.Where("transaction_cost.block_timestamp >= ?", since.Unix())
+	db = db.Group("day, transaction_cost.sender_type")
+	db = db.Order("day ASC, transaction_cost.sender_type ASC")
+
+	var summaries []*TransactionCostSummary
+	if err := db.Scan(&summaries).Error; err != nil {
+		return nil, fmt.Errorf("TransactionCost.GetDailyTransactionCosts error: %w, since: %v", err, since)
+	}
+	return summaries, nil
+}
+
+// InsertTransactionCost records the cost of a confirmed transaction from its receipt, a transaction recorded before
+// is left untouched.
+func (o *TransactionCost) InsertTransactionCost(ctx context.Context, contextID string, senderMeta *SenderMeta, receipt *gethTypes.Receipt, blockTimestamp uint64, dbTX ...*gorm.DB) error {
+	cost := &TransactionCost{
+		TxHash:            receipt.TxHash.String(),
+		ContextID:         contextID,
+		SenderName:        senderMeta.Name,
+		SenderService:     senderMeta.Service,
+		SenderAddress:     senderMeta.Address.String(),
+		SenderType:        senderMeta.Type,
+		IsSuccessful:      receipt.Status == gethTypes.ReceiptStatusSuccessful,
+		BlockNumber:       receipt.BlockNumber.Uint64(),
+		BlockTimestamp:    blockTimestamp,
+		GasUsed:           receipt.GasUsed,
+		EffectiveGasPrice: bigOrZero(receipt.EffectiveGasPrice).String(),
+		BlobGasUsed:       receipt.BlobGasUsed,
+		BlobGasPrice:      bigOrZero(receipt.BlobGasPrice).String(),
+		L1Fee:             bigOrZero(receipt.L1Fee).String(),
+		Fee:               TransactionFee(receipt).String(),
+	}
+
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&TransactionCost{})
+	db = db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tx_hash"}},
+		Where:     clause.Where{Exprs: []clause.Expression{clause.Eq{Column: "deleted_at", Value: nil}}},
+		DoNothing: true,
+	})
+	if err := db.Create(cost).Error; err != nil {
+		return fmt.Errorf("TransactionCost.InsertTransactionCost error: %w, tx hash: %v, context id: %v", err, cost.TxHash, contextID)
+	}
+	return nil
+}
+
+// TransactionFee returns the wei paid for a transaction: its execution fee, its blob fee and its L1 data fee if it's
+// an L2 transaction.
+func TransactionFee(receipt *gethTypes.Receipt) *big.Int {
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), bigOrZero(receipt.EffectiveGasPrice))
+	fee.Add(fee, new(big.Int).Mul(new(big.Int).SetUint64(receipt.BlobGasUsed), bigOrZero(receipt.BlobGasPrice)))
+	return fee.Add(fee, bigOrZero(receipt.L1Fee))
+}
+
+func bigOrZero(v *big.Int) *big.Int {
+	if v == nil {
+		return new(big.Int)
+	}
+	return v
+}
@@ -0,0 +1,61 @@
+package route
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"scroll-tech/common/observability"
+
+	"scroll-tech/rollup/internal/controller/api"
+)
+
+// Route register route for rollup relayer
+func Route(router *gin.Engine, reg prometheus.Registerer) {
+	router.Use(gin.Recovery())
+
+	observability.Use(router, "rollup_relayer", reg)
+
+	r := router.Group("api")
+
+	v1(r)
+}
+
+func v1(router *gin.RouterGroup) {
+	r := router.Group("/v1")
+
+	r.GET("/forced_inclusion", api.ForcedInclusion.GetForcedInclusionStatus)
+	r.GET("/forced_tx_queue", api.ForcedInclusion.GetForcedTxQueue)
+	r.GET("/l2_message", api.L2Message.GetL2Message)
+	r.GET("/skipped_l1_messages", api.SkippedL1Message.GetSkippedL1Messages)
+	r.GET("/skipped_l1_message_tx", api.SkippedL1Message.GetSkippedL1MessageTx)
+
+	if api.Throughput != nil {
+		r.GET("/throughput_signal", api.Throughput.GetThroughputSignal)
+	}
+
+	if api.Admin != nil {
+		admin := r.Group("/admin", api.Admin.Authorize)
+		admin.POST("/force_seal_chunk", api.Admin.ForceSealChunk)
+		admin.POST("/force_seal_batch", api.Admin.ForceSealBatch)
+		admin.GET("/batch_proposer_pause", api.Admin.GetBatchProposerPause)
+		admin.POST("/batch_proposer_pause", api.Admin.SetBatchProposerPause)
+		admin.GET("/relayer_circuit_breaker", api.Admin.GetRelayerCircuitBreaker)
+		admin.POST("/relayer_circuit_breaker", api.Admin.SetRelayerCircuitBreaker)
+		admin.GET("/batch_revert", api.Admin.GetBatchRevert)
+		admin.POST("/batch_revert", api.Admin.RevertBatches)
+		admin.GET("/prepared_transactions", api.Admin.GetPreparedTransactions)
+		admin.GET("/batch_transaction_costs", api.Admin.GetBatchTransactionCosts)
+		admin.GET("/daily_transaction_costs", api.Admin.GetDailyTransactionCosts)
+		admin.GET("/chunk_utilization", api.Admin.GetChunkUtilization)
+		admin.GET("/chunk_profile", api.Admin.GetChunkProfile)
+		admin.POST("/chunk_profile", api.Admin.SetChunkProfile)
+		admin.POST("/simulate_propose_batch", api.Admin.SimulateProposeBatch)
+		admin.GET("/feature_flags", api.Admin.GetFeatureFlags)
+		admin.POST("/feature_flags", api.Admin.SetFeatureFlag)
+		admin.DELETE("/feature_flags/:name", api.Admin.ResetFeatureFlag)
+		admin.GET("/l2_block_quarantine", api.Admin.GetL2BlockQuarantine)
+		admin.POST("/l2_block_quarantine", api.Admin.UpdateL2BlockQuarantine)
+		admin.GET("/l2_sync_height", api.Admin.GetL2SyncHeight)
+		admin.POST("/l2_sync_height", api.Admin.SetL2SyncHeight)
+	}
+}
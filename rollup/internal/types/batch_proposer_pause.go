@@ -0,0 +1,8 @@
+package types
+
+// SetBatchProposerPauseParameter for pausing or resuming the batch proposer request parameter
+type SetBatchProposerPauseParameter struct {
+	Paused *bool `json:"paused" binding:"required"`
+	// Reason is recorded with the pause, ignored on resume
+	Reason string `json:"reason"`
+}
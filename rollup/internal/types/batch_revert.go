@@ -0,0 +1,9 @@
+package types
+
+// RevertBatchesParameter for reverting the last committed batches request parameter
+type RevertBatchesParameter struct {
+	StartIndex uint64 `json:"start_index" binding:"required"`
+	EndIndex   uint64 `json:"end_index" binding:"required"`
+	// Confirm sends the revertBatch transaction, else it's only checked and returned
+	Confirm bool `json:"confirm"`
+}
@@ -0,0 +1,6 @@
+package types
+
+// SetChunkProfileParameter for switching the chunk proposer profile request parameter
+type SetChunkProfileParameter struct {
+	Name string `json:"name" binding:"required"`
+}
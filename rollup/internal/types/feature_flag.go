@@ -0,0 +1,14 @@
+package types
+
+// SetFeatureFlagParameter for overriding a feature flag request parameter
+type SetFeatureFlagParameter struct {
+	Name    string `json:"name" binding:"required"`
+	Enabled bool   `json:"enabled"`
+	// RolloutPercentage is 100 if not set
+	RolloutPercentage *uint64 `json:"rollout_percentage"`
+}
+
+// FeatureFlagNameParameter for the feature flag name path parameter
+type FeatureFlagNameParameter struct {
+	Name string `uri:"name" binding:"required"`
+}
@@ -0,0 +1,51 @@
+package types
+
+// Status of an enforced (forced-inclusion) transaction in the rollup pipeline.
+const (
+	// ForcedInclusionStatusQueued indicates the transaction is queued on L1 but not included in any chunk yet
+	ForcedInclusionStatusQueued = "queued"
+	// ForcedInclusionStatusChunked indicates the transaction is included in a chunk
+	ForcedInclusionStatusChunked = "chunked"
+	// ForcedInclusionStatusBatched indicates the transaction is included in a batch which is not committed yet
+	ForcedInclusionStatusBatched = "batched"
+	// ForcedInclusionStatusCommitted indicates the batch including the transaction is committed on L1
+	ForcedInclusionStatusCommitted = "committed"
+	// ForcedInclusionStatusFinalized indicates the batch including the transaction is finalized on L1
+	ForcedInclusionStatusFinalized = "finalized"
+)
+
+// ForcedInclusionStatusParameter for forced inclusion status request parameter
+type ForcedInclusionStatusParameter struct {
+	L1TxHash string `form:"l1_tx_hash" json:"l1_tx_hash" binding:"required"`
+}
+
+// ForcedInclusionStatusSchema the schema data of an enforced transaction's status
+type ForcedInclusionStatusSchema struct {
+	QueueIndex        uint64 `json:"queue_index"`
+	L1TxHash          string `json:"l1_tx_hash"`
+	L1BlockNumber     uint64 `json:"l1_block_number"`
+	Sender            string `json:"sender"`
+	Target            string `json:"target"`
+	Status            string `json:"status"`
+	ChunkHash         string `json:"chunk_hash,omitempty"`
+	BatchHash         string `json:"batch_hash,omitempty"`
+	BatchIndex        uint64 `json:"batch_index,omitempty"`
+	CommitTxHash      string `json:"commit_tx_hash,omitempty"`
+	FinalizeTxHash    string `json:"finalize_tx_hash,omitempty"`
+	InclusionDeadline uint64 `json:"inclusion_deadline,omitempty"`
+}
+
+// ForcedTxQueueParameter for forced transaction queue request parameter
+type ForcedTxQueueParameter struct {
+	Limit int `form:"limit" json:"limit" binding:"omitempty,min=1,max=1000"`
+}
+
+// ForcedTxQueueSchema the schema data of an enforced transaction waiting for inclusion in a chunk
+type ForcedTxQueueSchema struct {
+	QueueIndex        uint64 `json:"queue_index"`
+	L1TxHash          string `json:"l1_tx_hash"`
+	L1BlockNumber     uint64 `json:"l1_block_number"`
+	Sender            string `json:"sender"`
+	Target            string `json:"target"`
+	InclusionDeadline uint64 `json:"inclusion_deadline"`
+}
@@ -0,0 +1,24 @@
+package types
+
+// Operator actions on a quarantined l2 block.
+const (
+	// L2BlockQuarantineActionRetry retries the block on the next fetch of the l2 watcher
+	L2BlockQuarantineActionRetry = "retry"
+	// L2BlockQuarantineActionResolve imports the block without its row consumption, which is backfilled later
+	L2BlockQuarantineActionResolve = "resolve"
+)
+
+// UpdateL2BlockQuarantineParameter for retrying or resolving a quarantined l2 block request parameter
+type UpdateL2BlockQuarantineParameter struct {
+	Number uint64 `json:"number" binding:"required"`
+	Action string `json:"action" binding:"required,oneof=retry resolve"`
+}
+
+// L2BlockQuarantineSchema the schema data of a quarantined l2 block
+type L2BlockQuarantineSchema struct {
+	Number    uint64 `json:"number"`
+	Status    string `json:"status"`
+	Attempts  uint64 `json:"attempts"`
+	LastError string `json:"last_error"`
+	UpdatedAt int64  `json:"updated_at"`
+}
@@ -0,0 +1,19 @@
+package types
+
+// L2MessageParameter for l2 message request parameter
+type L2MessageParameter struct {
+	MsgHash string `form:"msg_hash" json:"msg_hash" binding:"required"`
+}
+
+// L2MessageSchema the schema data of an l2 to l1 message indexed by the l2 watcher
+type L2MessageSchema struct {
+	MessageIndex  uint64 `json:"message_index"`
+	MsgHash       string `json:"msg_hash"`
+	L2BlockNumber uint64 `json:"l2_block_number"`
+	L2TxHash      string `json:"l2_tx_hash"`
+	Sender        string `json:"sender,omitempty"`
+	Target        string `json:"target,omitempty"`
+	Value         string `json:"value,omitempty"`
+	GasLimit      uint64 `json:"gas_limit,omitempty"`
+	Calldata      string `json:"calldata,omitempty"`
+}
@@ -0,0 +1,11 @@
+package types
+
+// SetL2SyncHeightParameter for moving the l2 watcher sync cursor back request parameter
+type SetL2SyncHeightParameter struct {
+	Height *uint64 `json:"height" binding:"required"`
+}
+
+// L2SyncHeightState the height of the latest l2 block stored by the l2 watcher
+type L2SyncHeightState struct {
+	Height uint64 `json:"height"`
+}
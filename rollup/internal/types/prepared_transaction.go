@@ -0,0 +1,21 @@
+package types
+
+import "encoding/json"
+
+// PreparedTransactionsParameter for prepared multisig transactions request parameter
+type PreparedTransactionsParameter struct {
+	// Status is "prepared" or "executed", all the transactions if empty
+	Status string `form:"status" json:"status" binding:"omitempty,oneof=prepared executed"`
+	Limit  int    `form:"limit" json:"limit" binding:"omitempty,min=1,max=1000"`
+}
+
+// PreparedTransactionSchema the schema data of a commit or finalize transaction prepared for the multisig
+type PreparedTransactionSchema struct {
+	BatchIndex uint64          `json:"batch_index"`
+	BatchHash  string          `json:"batch_hash"`
+	Kind       string          `json:"kind"`
+	Status     string          `json:"status"`
+	Payload    json.RawMessage `json:"payload"`
+	TxHash     string          `json:"tx_hash,omitempty"`
+	CreatedAt  int64           `json:"created_at"`
+}
@@ -0,0 +1,18 @@
+package types
+
+// SetRelayerCircuitBreakerParameter for pausing or resuming a relayer pipeline request parameter
+type SetRelayerCircuitBreakerParameter struct {
+	Pipeline string `json:"pipeline" binding:"required,oneof=commit_relayer finalize_relayer"`
+	Paused   *bool  `json:"paused" binding:"required"`
+	// Reason is recorded with the pause, ignored on resume
+	Reason string `json:"reason"`
+}
+
+// RelayerCircuitBreakerSchema the schema data of the circuit breaker state of a relayer pipeline
+type RelayerCircuitBreakerSchema struct {
+	Pipeline string `json:"pipeline"`
+	Paused   bool   `json:"paused"`
+	Reason   string `json:"reason,omitempty"`
+	// UpdatedAt is the unix time of the last pause or resume, 0 if the pipeline was never paused.
+	UpdatedAt int64 `json:"updated_at,omitempty"`
+}
@@ -0,0 +1,40 @@
+package types
+
+// SkippedL1MessagesParameter for skipped l1 messages request parameter
+type SkippedL1MessagesParameter struct {
+	StartQueueIndex uint64 `form:"start_queue_index" json:"start_queue_index"`
+	Limit           int    `form:"limit" json:"limit" binding:"omitempty,min=1,max=1000"`
+}
+
+// SkippedL1MessageSchema the schema data of an l1 message skipped by a committed batch
+type SkippedL1MessageSchema struct {
+	QueueIndex      uint64 `json:"queue_index"`
+	MsgHash         string `json:"msg_hash,omitempty"`
+	BatchIndex      uint64 `json:"batch_index"`
+	BatchHash       string `json:"batch_hash"`
+	SkipReason      string `json:"skip_reason,omitempty"`
+	SkipBlockNumber uint64 `json:"skip_block_number,omitempty"`
+	CreatedAt       int64  `json:"created_at"`
+}
+
+// SkippedL1MessageTxParameter for skipped l1 message replay or drop transaction request parameter
+type SkippedL1MessageTxParameter struct {
+	QueueIndex *uint64 `form:"queue_index" json:"queue_index" binding:"required"`
+	// Action is "replay" to relay the message again with a new gas limit, or "drop" to refund its value
+	Action string `form:"action" json:"action" binding:"required,oneof=replay drop"`
+	// NewGasLimit is the gas limit of the replayed message, required to replay it
+	NewGasLimit uint32 `form:"new_gas_limit" json:"new_gas_limit"`
+	// RefundAddress receives the excess of the replay fee, the sender of the message if empty
+	RefundAddress string `form:"refund_address" json:"refund_address"`
+}
+
+// SkippedL1MessageTxSchema the schema data of an L1ScrollMessenger transaction replaying or dropping a skipped l1
+// message, a drop refunds the value of the message to its sender. The replay transaction pays the fee of the new
+// gas limit as its value.
+type SkippedL1MessageTxSchema struct {
+	QueueIndex uint64 `json:"queue_index"`
+	Action     string `json:"action"`
+	Sender     string `json:"sender"`
+	To         string `json:"to"`
+	Data       string `json:"data"`
+}
@@ -0,0 +1,39 @@
+package types
+
+// BatchTransactionCostsParameter for the transaction costs per batch request parameter
+type BatchTransactionCostsParameter struct {
+	StartIndex uint64 `form:"start_index" json:"start_index"`
+	Limit      int    `form:"limit" json:"limit" binding:"omitempty,min=1,max=1000"`
+}
+
+// DailyTransactionCostsParameter for the transaction costs per day request parameter
+type DailyTransactionCostsParameter struct {
+	// Days is the number of UTC days returned, today included
+	Days int `form:"days" json:"days" binding:"omitempty,min=1,max=366"`
+}
+
+// BatchTransactionCostSchema the schema data of the commit, finalize and l2 gas oracle transaction costs of a batch
+type BatchTransactionCostSchema struct {
+	BatchIndex uint64                   `json:"batch_index"`
+	BatchHash  string                   `json:"batch_hash"`
+	TotalFee   string                   `json:"total_fee"`
+	Costs      []*TransactionCostSchema `json:"costs"`
+}
+
+// DailyTransactionCostSchema the schema data of the transaction costs of a UTC day
+type DailyTransactionCostSchema struct {
+	Day      string                   `json:"day"`
+	TotalFee string                   `json:"total_fee"`
+	Costs    []*TransactionCostSchema `json:"costs"`
+}
+
+// TransactionCostSchema the schema data of the costs of the confirmed transactions of a sender type, the fees are
+// decimal wei amounts
+type TransactionCostSchema struct {
+	SenderType  string `json:"sender_type"`
+	TxCount     uint64 `json:"tx_count"`
+	FailedCount uint64 `json:"failed_count"`
+	GasUsed     uint64 `json:"gas_used"`
+	BlobGasUsed uint64 `json:"blob_gas_used"`
+	Fee         string `json:"fee"`
+}
@@ -0,0 +1,174 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/crypto/kzg4844"
+)
+
+// BlobSidecar is a blob sidecar served by the beacon node.
+type BlobSidecar struct {
+	Index         uint64
+	Blob          *kzg4844.Blob
+	KZGCommitment kzg4844.Commitment
+}
+
+// BeaconCheckpoints are the L1 block numbers of the safe (justified) and finalized checkpoints of the beacon chain.
+type BeaconCheckpoints struct {
+	Safe      uint64
+	Finalized uint64
+}
+
+// BeaconClient is a minimal client of the beacon node api, fetching the blob sidecars and the finality checkpoints of L1 blocks.
+type BeaconClient struct {
+	endpoint       string
+	client         *http.Client
+	secondsPerSlot uint64
+
+	genesisTime uint64
+}
+
+// NewBeaconClient creates a new BeaconClient, secondsPerSlot is 12 if zero.
+func NewBeaconClient(endpoint string, secondsPerSlot uint64) *BeaconClient {
+	if secondsPerSlot == 0 {
+		secondsPerSlot = 12
+	}
+	return &BeaconClient{
+		endpoint:       strings.TrimSuffix(endpoint, "/"),
+		client:         &http.Client{Timeout: 30 * time.Second},
+		secondsPerSlot: secondsPerSlot,
+	}
+}
+
+func (c *BeaconClient) get(ctx context.Context, path string, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("beacon api %v responded %v", path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// Slot returns the beacon slot of the L1 block with the given timestamp.
+func (c *BeaconClient) Slot(ctx context.Context, timestamp uint64) (uint64, error) {
+	if c.genesisTime == 0 {
+		var genesis struct {
+			Data struct {
+				GenesisTime string `json:"genesis_time"`
+			} `json:"data"`
+		}
+		if err := c.get(ctx, "/eth/v1/beacon/genesis", &genesis); err != nil {
+			return 0, err
+		}
+		genesisTime, err := strconv.ParseUint(genesis.Data.GenesisTime, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid beacon genesis time %v: %w", genesis.Data.GenesisTime, err)
+		}
+		c.genesisTime = genesisTime
+	}
+	if timestamp < c.genesisTime {
+		return 0, fmt.Errorf("timestamp %v before beacon genesis %v", timestamp, c.genesisTime)
+	}
+	return (timestamp - c.genesisTime) / c.secondsPerSlot, nil
+}
+
+// BlobSidecars returns the blob sidecars of the beacon block at the given slot.
+func (c *BeaconClient) BlobSidecars(ctx context.Context, slot uint64) ([]*BlobSidecar, error) {
+	var response struct {
+		Data []struct {
+			Index         string        `json:"index"`
+			Blob          hexutil.Bytes `json:"blob"`
+			KZGCommitment hexutil.Bytes `json:"kzg_commitment"`
+		} `json:"data"`
+	}
+	if err := c.get(ctx, fmt.Sprintf("/eth/v1/beacon/blob_sidecars/%d", slot), &response); err != nil {
+		return nil, err
+	}
+
+	sidecars := make([]*BlobSidecar, 0, len(response.Data))
+	for _, data := range response.Data {
+		index, err := strconv.ParseUint(data.Index, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid blob sidecar index %v: %w", data.Index, err)
+		}
+		if len(data.Blob) != len(kzg4844.Blob{}) || len(data.KZGCommitment) != len(kzg4844.Commitment{}) {
+			return nil, fmt.Errorf("invalid blob sidecar %v, blob size: %v, commitment size: %v", index, len(data.Blob), len(data.KZGCommitment))
+		}
+		sidecar := &BlobSidecar{Index: index, Blob: new(kzg4844.Blob)}
+		copy(sidecar.Blob[:], data.Blob)
+		copy(sidecar.KZGCommitment[:], data.KZGCommitment)
+		sidecars = append(sidecars, sidecar)
+	}
+	return sidecars, nil
+}
+
+// Checkpoints returns the L1 block numbers of the current justified and finalized checkpoints of the beacon chain.
+func (c *BeaconClient) Checkpoints(ctx context.Context) (*BeaconCheckpoints, error) {
+	var response struct {
+		Data struct {
+			CurrentJustified struct {
+				Root common.Hash `json:"root"`
+			} `json:"current_justified"`
+			Finalized struct {
+				Root common.Hash `json:"root"`
+			} `json:"finalized"`
+		} `json:"data"`
+	}
+	if err := c.get(ctx, "/eth/v1/beacon/states/head/finality_checkpoints", &response); err != nil {
+		return nil, err
+	}
+
+	safe, err := c.executionBlockNumber(ctx, response.Data.CurrentJustified.Root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block number of the justified checkpoint: %w", err)
+	}
+	finalized, err := c.executionBlockNumber(ctx, response.Data.Finalized.Root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block number of the finalized checkpoint: %w", err)
+	}
+	return &BeaconCheckpoints{Safe: safe, Finalized: finalized}, nil
+}
+
+// executionBlockNumber returns the L1 block number of the execution payload of a beacon block, 0 for the zero
+// root of the checkpoints before the first justification.
+func (c *BeaconClient) executionBlockNumber(ctx context.Context, blockRoot common.Hash) (uint64, error) {
+	if blockRoot == (common.Hash{}) {
+		return 0, nil
+	}
+	var response struct {
+		Data struct {
+			Message struct {
+				Body struct {
+					ExecutionPayload struct {
+						BlockNumber string `json:"block_number"`
+					} `json:"execution_payload"`
+				} `json:"body"`
+			} `json:"message"`
+		} `json:"data"`
+	}
+	if err := c.get(ctx, "/eth/v2/beacon/blocks/"+blockRoot.Hex(), &response); err != nil {
+		return 0, err
+	}
+	blockNumber := response.Data.Message.Body.ExecutionPayload.BlockNumber
+	number, err := strconv.ParseUint(blockNumber, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid execution payload block number %v of beacon block %v: %w", blockNumber, blockRoot.Hex(), err)
+	}
+	return number, nil
+}
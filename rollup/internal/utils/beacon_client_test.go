@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/crypto/kzg4844"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBeaconClient(t *testing.T) {
+	var blob kzg4844.Blob
+	blob[0] = 0x01
+	var commitment kzg4844.Commitment
+	commitment[0] = 0x02
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/eth/v1/beacon/genesis":
+			_, _ = fmt.Fprint(w, `{"data":{"genesis_time":"1000"}}`)
+		case "/eth/v1/beacon/blob_sidecars/10":
+			_, _ = fmt.Fprintf(w, `{"data":[{"index":"3","blob":"%s","kzg_commitment":"%s"}]}`, hexutil.Encode(blob[:]), hexutil.Encode(commitment[:]))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewBeaconClient(server.URL+"/", 0)
+	slot, err := client.Slot(context.Background(), 1000+10*12+5)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), slot)
+	_, err = client.Slot(context.Background(), 999)
+	assert.Error(t, err)
+
+	sidecars, err := client.BlobSidecars(context.Background(), slot)
+	assert.NoError(t, err)
+	assert.Len(t, sidecars, 1)
+	assert.Equal(t, uint64(3), sidecars[0].Index)
+	assert.Equal(t, blob, *sidecars[0].Blob)
+	assert.Equal(t, commitment, sidecars[0].KZGCommitment)
+
+	_, err = client.BlobSidecars(context.Background(), 11)
+	assert.Error(t, err)
+}
+
+func TestBeaconClientCheckpoints(t *testing.T) {
+	justifiedRoot := common.HexToHash("0x01")
+	finalizedRoot := common.HexToHash("0x02")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/eth/v1/beacon/states/head/finality_checkpoints":
+			_, _ = fmt.Fprintf(w, `{"data":{"previous_justified":{"epoch":"8","root":"%s"},"current_justified":{"epoch":"9","root":"%s"},"finalized":{"epoch":"8","root":"%s"}}}`,
+				finalizedRoot.Hex(), justifiedRoot.Hex(), finalizedRoot.Hex())
+		case "/eth/v2/beacon/blocks/" + justifiedRoot.Hex():
+			_, _ = fmt.Fprint(w, `{"version":"deneb","data":{"message":{"slot":"288","body":{"execution_payload":{"block_number":"1064"}}}}}`)
+		case "/eth/v2/beacon/blocks/" + finalizedRoot.Hex():
+			_, _ = fmt.Fprint(w, `{"version":"deneb","data":{"message":{"slot":"256","body":{"execution_payload":{"block_number":"1032"}}}}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewBeaconClient(server.URL, 0)
+	checkpoints, err := client.Checkpoints(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, &BeaconCheckpoints{Safe: 1064, Finalized: 1032}, checkpoints)
+}
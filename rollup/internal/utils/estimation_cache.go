@@ -0,0 +1,170 @@
+package utils
+
+import (
+	"fmt"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/scroll-tech/go-ethereum/common"
+
+	"scroll-tech/common/types/encoding"
+	"scroll-tech/common/types/encoding/codecv0"
+	"scroll-tech/common/types/encoding/codecv1"
+)
+
+// DefaultEstimationCacheSize is the default number of blocks held by an EstimationCache.
+const DefaultEstimationCacheSize = 10000
+
+type estimationCacheKey struct {
+	blockHash    common.Hash
+	codecVersion encoding.CodecVersion
+}
+
+// blockEstimation is the per-block part of the L1 commit estimations of a chunk.
+type blockEstimation struct {
+	l1CommitCalldataSize    uint64
+	l1CommitGas             uint64
+	uncompressedPayloadSize uint64
+	l1CommitBlobDataSize    uint64 // codecv1 only
+}
+
+// EstimationCache caches the per-block L1 commit estimations keyed by block hash and codec version,
+// so that repeated proposal attempts over the same pending blocks don't redo the codec work.
+type EstimationCache struct {
+	cache *lru.Cache
+}
+
+// NewEstimationCache creates a new EstimationCache holding at most size blocks,
+// DefaultEstimationCacheSize is used if size is not positive.
+func NewEstimationCache(size int) *EstimationCache {
+	if size <= 0 {
+		size = DefaultEstimationCacheSize
+	}
+	// lru.New only fails on a non-positive size.
+	cache, _ := lru.New(size)
+	return &EstimationCache{cache: cache}
+}
+
+// CalculateChunkMetrics calculates chunk metrics like CalculateChunkMetrics, reusing the cached per-block estimations.
+func (c *EstimationCache) CalculateChunkMetrics(chunk *encoding.Chunk, codecVersion encoding.CodecVersion) (*ChunkMetrics, error) {
+	var err error
+	metrics := &ChunkMetrics{
+		TxNum:               chunk.NumTransactions(),
+		NumBlocks:           uint64(len(chunk.Blocks)),
+		FirstBlockTimestamp: chunk.Blocks[0].Header.Time,
+	}
+	metrics.CrcMax, err = chunk.CrcMax()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get crc max: %w", err)
+	}
+
+	var l1CommitBlobDataSize uint64
+	for _, block := range chunk.Blocks {
+		estimation, err := c.getBlockEstimation(block, codecVersion)
+		if err != nil {
+			return nil, err
+		}
+		metrics.L1CommitCalldataSize += estimation.l1CommitCalldataSize
+		metrics.L1CommitGas += estimation.l1CommitGas
+		metrics.UncompressedPayloadSize += estimation.uncompressedPayloadSize
+		l1CommitBlobDataSize += estimation.l1CommitBlobDataSize
+	}
+
+	switch codecVersion {
+	case encoding.CodecV0:
+		metrics.L1CommitGas += codecv0.EstimateChunkL1CommitGasOverhead(metrics.NumBlocks, metrics.TxNum)
+	case encoding.CodecV1:
+		var totalNonSkippedL1Messages uint64
+		for _, block := range chunk.Blocks {
+			totalNonSkippedL1Messages += uint64(len(block.Transactions)) - block.NumL2Transactions()
+		}
+		metrics.L1CommitGas += codecv1.EstimateChunkL1CommitGasOverhead(metrics.NumBlocks, totalNonSkippedL1Messages)
+		metrics.L1CommitBlobSize = codecv1.EstimateL1CommitBlobSize(l1CommitBlobDataSize)
+	}
+	return metrics, nil
+}
+
+func (c *EstimationCache) getBlockEstimation(block *encoding.Block, codecVersion encoding.CodecVersion) (*blockEstimation, error) {
+	key := estimationCacheKey{blockHash: block.Header.Hash(), codecVersion: codecVersion}
+	if value, ok := c.cache.Get(key); ok {
+		return value.(*blockEstimation), nil
+	}
+
+	var err error
+	estimation := &blockEstimation{}
+	estimation.uncompressedPayloadSize, err = codecv1.EstimateBlockL1CommitBlobDataSize(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate block uncompressed payload size: %w", err)
+	}
+	switch codecVersion {
+	case encoding.CodecV0:
+		estimation.l1CommitCalldataSize, err = codecv0.EstimateBlockL1CommitCalldataSize(block)
+		if err != nil {
+			return nil, fmt.Errorf("failed to estimate block L1 commit calldata size: %w", err)
+		}
+		estimation.l1CommitGas, err = codecv0.EstimateBlockL1CommitGas(block)
+		if err != nil {
+			return nil, fmt.Errorf("failed to estimate block L1 commit gas: %w", err)
+		}
+	case encoding.CodecV1:
+		estimation.l1CommitCalldataSize = codecv1.EstimateChunkL1CommitCalldataSize(&encoding.Chunk{Blocks: []*encoding.Block{block}})
+		estimation.l1CommitGas = codecv1.EstimateBlockL1CommitGas(block)
+		// the blob data of codecv1 is the uncompressed payload.
+		estimation.l1CommitBlobDataSize = estimation.uncompressedPayloadSize
+	default:
+		return nil, fmt.Errorf("unsupported codec version: %v", codecVersion)
+	}
+
+	c.cache.Add(key, estimation)
+	return estimation, nil
+}
+
+// BlockEstimationSnapshot is a cached per-block estimation, as persisted in the proposer state snapshots.
+type BlockEstimationSnapshot struct {
+	BlockHash               common.Hash           `json:"block_hash"`
+	CodecVersion            encoding.CodecVersion `json:"codec_version"`
+	L1CommitCalldataSize    uint64                `json:"l1_commit_calldata_size"`
+	L1CommitGas             uint64                `json:"l1_commit_gas"`
+	UncompressedPayloadSize uint64                `json:"uncompressed_payload_size"`
+	L1CommitBlobDataSize    uint64                `json:"l1_commit_blob_data_size,omitempty"`
+}
+
+// Snapshot returns at most limit of the most recently used estimations, from the oldest to the newest,
+// all of them if limit is not positive.
+func (c *EstimationCache) Snapshot(limit int) []*BlockEstimationSnapshot {
+	keys := c.cache.Keys()
+	if limit > 0 && len(keys) > limit {
+		keys = keys[len(keys)-limit:]
+	}
+
+	snapshots := make([]*BlockEstimationSnapshot, 0, len(keys))
+	for _, k := range keys {
+		value, ok := c.cache.Peek(k)
+		if !ok {
+			continue
+		}
+		key, estimation := k.(estimationCacheKey), value.(*blockEstimation)
+		snapshots = append(snapshots, &BlockEstimationSnapshot{
+			BlockHash:               key.blockHash,
+			CodecVersion:            key.codecVersion,
+			L1CommitCalldataSize:    estimation.l1CommitCalldataSize,
+			L1CommitGas:             estimation.l1CommitGas,
+			UncompressedPayloadSize: estimation.uncompressedPayloadSize,
+			L1CommitBlobDataSize:    estimation.l1CommitBlobDataSize,
+		})
+	}
+	return snapshots
+}
+
+// Restore adds the snapshotted estimations to the cache, the ones of blocks which are no longer canonical are
+// never hit since the estimations are keyed by block hash.
+func (c *EstimationCache) Restore(snapshots []*BlockEstimationSnapshot) {
+	for _, snapshot := range snapshots {
+		key := estimationCacheKey{blockHash: snapshot.BlockHash, codecVersion: snapshot.CodecVersion}
+		c.cache.Add(key, &blockEstimation{
+			l1CommitCalldataSize:    snapshot.L1CommitCalldataSize,
+			l1CommitGas:             snapshot.L1CommitGas,
+			uncompressedPayloadSize: snapshot.UncompressedPayloadSize,
+			l1CommitBlobDataSize:    snapshot.L1CommitBlobDataSize,
+		})
+	}
+}
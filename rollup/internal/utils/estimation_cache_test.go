@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/types/encoding"
+)
+
+func readBlockFromJSON(t *testing.T, filename string) *encoding.Block {
+	data, err := os.ReadFile(filename)
+	assert.NoError(t, err)
+
+	block := &encoding.Block{}
+	assert.NoError(t, json.Unmarshal(data, block))
+	return block
+}
+
+func TestEstimationCache(t *testing.T) {
+	var chunk encoding.Chunk
+	for i, trace := range []string{"blockTrace_02.json", "blockTrace_03.json", "blockTrace_02.json"} {
+		block := readBlockFromJSON(t, "../../testdata/"+trace)
+		block.Header.Number = big.NewInt(int64(i + 1))
+		chunk.Blocks = append(chunk.Blocks, block)
+	}
+
+	cache := NewEstimationCache(2)
+	for _, codecVersion := range []encoding.CodecVersion{encoding.CodecV0, encoding.CodecV1} {
+		for numBlocks := 1; numBlocks <= len(chunk.Blocks); numBlocks++ {
+			subChunk := &encoding.Chunk{Blocks: chunk.Blocks[:numBlocks]}
+			expected, err := CalculateChunkMetrics(subChunk, codecVersion)
+			assert.NoError(t, err)
+
+			// the first call fills the cache and the second call reuses it.
+			for i := 0; i < 2; i++ {
+				metrics, err := cache.CalculateChunkMetrics(subChunk, codecVersion)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, metrics)
+			}
+		}
+	}
+	assert.Equal(t, 2, cache.cache.Len())
+
+	_, err := cache.CalculateChunkMetrics(&chunk, encoding.CodecVersion(255))
+	assert.Error(t, err)
+}
+
+func TestEstimationCacheSnapshot(t *testing.T) {
+	var chunk encoding.Chunk
+	for i, trace := range []string{"blockTrace_02.json", "blockTrace_03.json"} {
+		block := readBlockFromJSON(t, "../../testdata/"+trace)
+		block.Header.Number = big.NewInt(int64(i + 1))
+		chunk.Blocks = append(chunk.Blocks, block)
+	}
+
+	cache := NewEstimationCache(0)
+	expected, err := cache.CalculateChunkMetrics(&chunk, encoding.CodecV1)
+	assert.NoError(t, err)
+
+	snapshots := cache.Snapshot(0)
+	assert.Len(t, snapshots, 2)
+	assert.Equal(t, chunk.Blocks[0].Header.Hash(), snapshots[0].BlockHash)
+	assert.Equal(t, chunk.Blocks[1].Header.Hash(), snapshots[1].BlockHash)
+
+	// only the most recently used estimations are kept within the limit.
+	limited := cache.Snapshot(1)
+	assert.Len(t, limited, 1)
+	assert.Equal(t, chunk.Blocks[1].Header.Hash(), limited[0].BlockHash)
+
+	data, err := json.Marshal(snapshots)
+	assert.NoError(t, err)
+	var decoded []*BlockEstimationSnapshot
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+
+	restored := NewEstimationCache(0)
+	restored.Restore(decoded)
+	assert.Equal(t, 2, restored.cache.Len())
+	metrics, err := restored.CalculateChunkMetrics(&chunk, encoding.CodecV1)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, metrics)
+	assert.Equal(t, 2, restored.cache.Len())
+}
@@ -0,0 +1,297 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/ethclient"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rpc"
+)
+
+const (
+	// rpcLatencyDecay is the weight of the latest sample in the moving averages of an endpoint.
+	rpcLatencyDecay = 0.2
+	// rpcErrorRatePenalty is the latency an error rate of 1 weighs as in the endpoint score.
+	rpcErrorRatePenalty = 10 * time.Second
+	// rpcFailureCooldown is how long an endpoint is tried last after a failed request.
+	rpcFailureCooldown = 30 * time.Second
+
+	defaultRPCProbeInterval = 30 * time.Second
+)
+
+// rpcEndpoint holds the health of an rpc endpoint.
+type rpcEndpoint struct {
+	url *url.URL
+	// sampled is whether a request was sent to the endpoint, its score is meaningless until then.
+	sampled bool
+	// latency and errorRate are exponential moving averages of the request samples.
+	latency       time.Duration
+	errorRate     float64
+	cooldownUntil time.Time
+}
+
+// score is the expected cost of a request to the endpoint, the lower the better.
+func (e *rpcEndpoint) score() time.Duration {
+	return e.latency + time.Duration(e.errorRate*float64(rpcErrorRatePenalty))
+}
+
+func (e *rpcEndpoint) record(latency time.Duration, failed bool, now time.Time) {
+	e.sampled = true
+	var errorSample float64
+	if failed {
+		errorSample = 1
+		e.cooldownUntil = now.Add(rpcFailureCooldown)
+	} else {
+		e.latency = time.Duration((1-rpcLatencyDecay)*float64(e.latency) + rpcLatencyDecay*float64(latency))
+	}
+	e.errorRate = (1-rpcLatencyDecay)*e.errorRate + rpcLatencyDecay*errorSample
+}
+
+// RPCFailoverTransport is a http.RoundTripper sending every json-rpc request to the healthiest of a list of
+// endpoints, scored by their latency and error rate, and failing over to the next one on a transport error.
+type RPCFailoverTransport struct {
+	transport http.RoundTripper
+	// layer is the layer of the eth nodes, l1 or l2, in the logs and the metrics
+	layer string
+
+	mu        sync.Mutex
+	endpoints []*rpcEndpoint
+	// lastServed is the endpoint which answered the last request
+	lastServed *rpcEndpoint
+
+	rpcFailoverTotal   prometheus.Counter
+	rpcEndpointScore   *prometheus.GaugeVec
+	rpcEndpointHealthy *prometheus.GaugeVec
+}
+
+// NewRPCFailoverTransport creates a new RPCFailoverTransport for the eth nodes of the given layer (l1 or l2),
+// the endpoints are initially tried in the given order.
+func NewRPCFailoverTransport(layer string, endpoints []string, reg prometheus.Registerer) (*RPCFailoverTransport, error) {
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("no rpc endpoint")
+	}
+	t := &RPCFailoverTransport{
+		transport: http.DefaultTransport,
+		layer:     layer,
+		rpcFailoverTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("rollup_%s_rpc_failover_total", layer),
+			Help: fmt.Sprintf("The total number of %s rpc requests failed over to another endpoint", layer),
+		}),
+		rpcEndpointScore: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Name: fmt.Sprintf("rollup_%s_rpc_endpoint_score_ms", layer),
+			Help: fmt.Sprintf("The health score of the %s rpc endpoints, the expected request cost in milliseconds", layer),
+		}, []string{"endpoint"}),
+		rpcEndpointHealthy: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Name: fmt.Sprintf("rollup_%s_rpc_endpoint_healthy", layer),
+			Help: fmt.Sprintf("Whether the %s rpc endpoint is healthy (1) or cooling down after a failure (0)", layer),
+		}, []string{"endpoint"}),
+	}
+	for _, endpoint := range endpoints {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rpc endpoint %v: %w", endpoint, err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return nil, fmt.Errorf("unsupported rpc endpoint %v: only http(s) endpoints can fail over", endpoint)
+		}
+		t.endpoints = append(t.endpoints, &rpcEndpoint{url: u})
+	}
+	return t, nil
+}
+
+// ranked returns the endpoints ordered by health: the ones cooling down last, then by score. An endpoint not
+// sampled yet keeps its configured position, the sampled ones are ordered by score among the other positions.
+func (t *RPCFailoverTransport) ranked(now time.Time) []*rpcEndpoint {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ranked := make([]*rpcEndpoint, len(t.endpoints))
+	copy(ranked, t.endpoints)
+
+	var positions []int
+	var sampled []*rpcEndpoint
+	for i, endpoint := range ranked {
+		if endpoint.sampled {
+			positions = append(positions, i)
+			sampled = append(sampled, endpoint)
+		}
+	}
+	sort.SliceStable(sampled, func(i, j int) bool {
+		return sampled[i].score() < sampled[j].score()
+	})
+	for i, position := range positions {
+		ranked[position] = sampled[i]
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return !now.Before(ranked[i].cooldownUntil) && now.Before(ranked[j].cooldownUntil)
+	})
+	return ranked
+}
+
+func (t *RPCFailoverTransport) record(endpoint *rpcEndpoint, latency time.Duration, failed bool) {
+	now := time.Now()
+	t.mu.Lock()
+	endpoint.record(latency, failed, now)
+	score := endpoint.score()
+	t.mu.Unlock()
+
+	label := redactEndpoint(endpoint.url)
+	t.rpcEndpointScore.WithLabelValues(label).Set(float64(score.Milliseconds()))
+	if failed {
+		t.rpcEndpointHealthy.WithLabelValues(label).Set(0)
+	} else {
+		t.rpcEndpointHealthy.WithLabelValues(label).Set(1)
+	}
+}
+
+// RoundTrip sends the request to the endpoints in the order of their health until one answers.
+func (t *RPCFailoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+	}
+
+	var lastErr error
+	for i, endpoint := range t.ranked(time.Now()) {
+		if i > 0 {
+			t.rpcFailoverTotal.Inc()
+			log.Warn("rpc request failed, failing over", "layer", t.layer, "to", redactEndpoint(endpoint.url), "err", lastErr)
+		}
+		resp, err := t.send(req, endpoint, body)
+		if err == nil {
+			t.mu.Lock()
+			t.lastServed = endpoint
+			t.mu.Unlock()
+			return resp, nil
+		}
+		lastErr = err
+		if req.Context().Err() != nil {
+			break
+		}
+	}
+	return nil, lastErr
+}
+
+// send sends the request to an endpoint, the server errors are returned as errors so that they fail over.
+func (t *RPCFailoverTransport) send(req *http.Request, endpoint *rpcEndpoint, body []byte) (*http.Response, error) {
+	endpointReq := req.Clone(req.Context())
+	endpointReq.URL = endpoint.url
+	endpointReq.Host = endpoint.url.Host
+	endpointReq.Body = io.NopCloser(bytes.NewReader(body))
+	endpointReq.ContentLength = int64(len(body))
+
+	start := time.Now()
+	resp, err := t.transport.RoundTrip(endpointReq)
+	if err == nil && (resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests) {
+		_ = resp.Body.Close()
+		err = fmt.Errorf("rpc endpoint %v responded %v", redactEndpoint(endpoint.url), resp.Status)
+	}
+	// A cancelled request tells nothing about the endpoint.
+	if req.Context().Err() == nil {
+		t.record(endpoint, time.Since(start), err != nil)
+	}
+	return resp, err
+}
+
+// FailOver puts the endpoint which answered the last request in cooldown, so that the next requests are sent
+// to another endpoint, e.g. when the data it served diverged from another node.
+func (t *RPCFailoverTransport) FailOver() {
+	t.mu.Lock()
+	endpoint := t.lastServed
+	t.mu.Unlock()
+	if endpoint == nil {
+		return
+	}
+	t.rpcFailoverTotal.Inc()
+	log.Warn("failing over from rpc endpoint", "layer", t.layer, "endpoint", redactEndpoint(endpoint.url))
+	t.record(endpoint, 0, true)
+}
+
+// Probe sends an eth_blockNumber request to every endpoint to refresh their health.
+func (t *RPCFailoverTransport) Probe(ctx context.Context) {
+	const probeBody = `{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber","params":[]}`
+	t.mu.Lock()
+	endpoints := make([]*rpcEndpoint, len(t.endpoints))
+	copy(endpoints, t.endpoints)
+	t.mu.Unlock()
+
+	for _, endpoint := range endpoints {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.url.String(), strings.NewReader(probeBody))
+		if err != nil {
+			continue
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := t.send(req, endpoint, []byte(probeBody))
+		if err != nil {
+			log.Warn("rpc endpoint probe failed", "layer", t.layer, "endpoint", redactEndpoint(endpoint.url), "err", err)
+			continue
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}
+}
+
+// redactEndpoint strips the credentials and the path (often an api key) of an endpoint for logs and metrics.
+func redactEndpoint(u *url.URL) string {
+	return u.Scheme + "://" + u.Host
+}
+
+// DialWithFailover connects to a list of l1 rpc endpoints with failover, the health of the endpoints is
+// re-probed every probeInterval until the context is done. A single endpoint is dialed directly.
+func DialWithFailover(ctx context.Context, endpoints []string, probeInterval time.Duration, reg prometheus.Registerer) (*ethclient.Client, error) {
+	client, _, err := dialWithFailover(ctx, "l1", endpoints, probeInterval, reg)
+	return client, err
+}
+
+// DialL2WithFailover connects to a list of l2 rpc endpoints with failover like DialWithFailover. It also returns
+// the failover transport, nil for a single endpoint, so that the caller can fail over on diverging data.
+func DialL2WithFailover(ctx context.Context, endpoints []string, probeInterval time.Duration, reg prometheus.Registerer) (*ethclient.Client, *RPCFailoverTransport, error) {
+	return dialWithFailover(ctx, "l2", endpoints, probeInterval, reg)
+}
+
+func dialWithFailover(ctx context.Context, layer string, endpoints []string, probeInterval time.Duration, reg prometheus.Registerer) (*ethclient.Client, *RPCFailoverTransport, error) {
+	if len(endpoints) == 1 {
+		client, err := ethclient.Dial(endpoints[0])
+		return client, nil, err
+	}
+	transport, err := NewRPCFailoverTransport(layer, endpoints, reg)
+	if err != nil {
+		return nil, nil, err
+	}
+	rpcClient, err := rpc.DialHTTPWithClient(endpoints[0], &http.Client{Transport: transport})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if probeInterval == 0 {
+		probeInterval = defaultRPCProbeInterval
+	}
+	go func() {
+		ticker := time.NewTicker(probeInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				transport.Probe(ctx)
+			}
+		}
+	}()
+	return ethclient.NewClient(rpcClient), transport, nil
+}
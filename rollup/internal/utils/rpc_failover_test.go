@@ -0,0 +1,135 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func newBlockNumberServer(blockNumber uint64, healthy *atomic.Bool, calls *atomic.Int64) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":"0x%x"}`, blockNumber)
+	}))
+}
+
+func TestDialWithFailover(t *testing.T) {
+	var primaryHealthy, backupHealthy atomic.Bool
+	var primaryCalls, backupCalls atomic.Int64
+	primaryHealthy.Store(true)
+	backupHealthy.Store(true)
+	primary := newBlockNumberServer(100, &primaryHealthy, &primaryCalls)
+	defer primary.Close()
+	backup := newBlockNumberServer(200, &backupHealthy, &backupCalls)
+	defer backup.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client, err := DialWithFailover(ctx, []string{primary.URL, backup.URL}, 0, prometheus.NewRegistry())
+	assert.NoError(t, err)
+
+	// The endpoints are tried in the given order initially.
+	number, err := client.BlockNumber(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(100), number)
+
+	// A failing endpoint is failed over and then tried last.
+	primaryHealthy.Store(false)
+	number, err = client.BlockNumber(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(200), number)
+	primaryHealthy.Store(true)
+	calls := primaryCalls.Load()
+	number, err = client.BlockNumber(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(200), number)
+	assert.Equal(t, calls, primaryCalls.Load())
+
+	// All endpoints failing is an error.
+	primaryHealthy.Store(false)
+	backupHealthy.Store(false)
+	_, err = client.BlockNumber(ctx)
+	assert.Error(t, err)
+
+	_, err = DialWithFailover(ctx, []string{primary.URL, "ws://localhost:8546"}, 0, prometheus.NewRegistry())
+	assert.Error(t, err)
+}
+
+func TestRPCFailoverTransportFailOver(t *testing.T) {
+	var primaryHealthy, backupHealthy atomic.Bool
+	var primaryCalls, backupCalls atomic.Int64
+	primaryHealthy.Store(true)
+	backupHealthy.Store(true)
+	primary := newBlockNumberServer(100, &primaryHealthy, &primaryCalls)
+	defer primary.Close()
+	backup := newBlockNumberServer(200, &backupHealthy, &backupCalls)
+	defer backup.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client, transport, err := DialL2WithFailover(ctx, []string{primary.URL, backup.URL}, 0, prometheus.NewRegistry())
+	assert.NoError(t, err)
+	assert.NotNil(t, transport)
+
+	number, err := client.BlockNumber(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(100), number)
+
+	// A healthy endpoint is left on request, e.g. after serving diverging data.
+	transport.FailOver()
+	number, err = client.BlockNumber(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(200), number)
+
+	// A single endpoint is dialed directly.
+	_, transport, err = DialL2WithFailover(ctx, []string{primary.URL}, 0, prometheus.NewRegistry())
+	assert.NoError(t, err)
+	assert.Nil(t, transport)
+}
+
+func TestRPCFailoverTransportRanked(t *testing.T) {
+	transport, err := NewRPCFailoverTransport("l1", []string{"http://a", "http://b", "http://c"}, prometheus.NewRegistry())
+	assert.NoError(t, err)
+	a, b, c := transport.endpoints[0], transport.endpoints[1], transport.endpoints[2]
+	now := time.Now()
+
+	// The endpoints are tried in the given order initially.
+	assert.Equal(t, []*rpcEndpoint{a, b, c}, transport.ranked(now))
+
+	// A sampled endpoint doesn't overtake an unsampled one, the sampled ones are ordered by score.
+	a.record(100*time.Millisecond, false, now)
+	assert.Equal(t, []*rpcEndpoint{a, b, c}, transport.ranked(now))
+	c.record(10*time.Millisecond, false, now)
+	assert.Equal(t, []*rpcEndpoint{c, b, a}, transport.ranked(now))
+
+	// An endpoint cooling down is tried last until the cooldown ends, whatever its score.
+	c.cooldownUntil = now.Add(time.Minute)
+	assert.Equal(t, []*rpcEndpoint{b, a, c}, transport.ranked(now))
+	assert.Equal(t, []*rpcEndpoint{c, b, a}, transport.ranked(now.Add(time.Minute)))
+}
+
+func TestRPCEndpointScore(t *testing.T) {
+	fast := &rpcEndpoint{}
+	slow := &rpcEndpoint{}
+	for i := 0; i < 10; i++ {
+		fast.record(10, false, time.Time{})
+		slow.record(100, false, time.Time{})
+	}
+	assert.Less(t, fast.score(), slow.score())
+
+	// A single error outweighs the latency difference.
+	fast.record(0, true, time.Time{})
+	assert.Greater(t, fast.score(), slow.score())
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/core/types"
 	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/params"
 
 	"scroll-tech/common/types/encoding"
 	"scroll-tech/common/types/encoding/codecv0"
@@ -48,6 +49,16 @@ func BufferToUint256Le(buffer []byte) []*big.Int {
 	return buffer256
 }
 
+// l1ToL2AliasOffset is the offset added to an L1 contract address when it sends a message to L2.
+var l1ToL2AliasOffset = new(big.Int).SetBytes(common.FromHex("0x1111000000000000000000000000000000001111"))
+
+// ApplyL1ToL2Alias computes the L2 alias of an L1 address, same as AddressAliasHelper.applyL1ToL2Alias.
+func ApplyL1ToL2Alias(l1Address common.Address) common.Address {
+	aliased := new(big.Int).Add(new(big.Int).SetBytes(l1Address.Bytes()), l1ToL2AliasOffset)
+	// addition overflow is intended, only the lower 20 bytes are kept.
+	return common.BytesToAddress(aliased.Bytes())
+}
+
 // UnpackLog unpacks a retrieved log into the provided output structure.
 // @todo: add unit test.
 func UnpackLog(c *abi.ABI, out interface{}, event string, log types.Log) error {
@@ -79,6 +90,9 @@ type ChunkMetrics struct {
 	L1CommitCalldataSize uint64
 	L1CommitGas          uint64
 
+	// UncompressedPayloadSize is the total size of the L2 transaction payloads, before any DA encoding or compression.
+	UncompressedPayloadSize uint64
+
 	// codecv1 metrics, default 0 for codecv0
 	L1CommitBlobSize uint64
 }
@@ -95,6 +109,13 @@ func CalculateChunkMetrics(chunk *encoding.Chunk, codecVersion encoding.CodecVer
 	if err != nil {
 		return nil, fmt.Errorf("failed to get crc max: %w", err)
 	}
+	for _, block := range chunk.Blocks {
+		payloadSize, err := codecv1.EstimateBlockL1CommitBlobDataSize(block)
+		if err != nil {
+			return nil, fmt.Errorf("failed to estimate block uncompressed payload size: %w", err)
+		}
+		metrics.UncompressedPayloadSize += payloadSize
+	}
 	switch codecVersion {
 	case encoding.CodecV0:
 		metrics.L1CommitCalldataSize, err = codecv0.EstimateChunkL1CommitCalldataSize(chunk)
@@ -286,6 +307,16 @@ func GetBatchMetadata(batch *encoding.Batch, codecVersion encoding.CodecVersion)
 	}
 }
 
+// GetBatchCodecVersion returns the codec version of a stored batch: codecv0 for the genesis batch, and the codec of
+// the fork of blockNumber otherwise. The recorded codec version of the batch isn't trusted, since the coordinator,
+// the provers and the verifier only accept the codec of the fork.
+func GetBatchCodecVersion(chainCfg *params.ChainConfig, batchIndex uint64, blockNumber uint64) encoding.CodecVersion {
+	if batchIndex > 0 && chainCfg.IsBernoulli(new(big.Int).SetUint64(blockNumber)) {
+		return encoding.CodecV1
+	}
+	return encoding.CodecV0
+}
+
 // GetTotalL1MessagePoppedBeforeBatch retrieves the total L1 messages popped before the batch.
 func GetTotalL1MessagePoppedBeforeBatch(parentBatchBytes []byte, codecVersion encoding.CodecVersion) (uint64, error) {
 	switch codecVersion {
@@ -5,7 +5,10 @@ import (
 	"testing"
 
 	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/params"
 	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/types/encoding"
 )
 
 func TestKeccak2(t *testing.T) {
@@ -47,3 +50,19 @@ func TestBufferToUint256Le(t *testing.T) {
 	result := BufferToUint256Le(input)
 	assert.Equal(t, expectedOutput, result)
 }
+
+func TestApplyL1ToL2Alias(t *testing.T) {
+	assert.Equal(t, common.HexToAddress("0x1111000000000000000000000000000000001111"), ApplyL1ToL2Alias(common.Address{}))
+	assert.Equal(t, common.HexToAddress("0x7885BcBd5CeCEf1336b5300fb5186A12DDD8c478"), ApplyL1ToL2Alias(common.HexToAddress("0x6774Bcbd5ceCef1336b5300fb5186a12DDD8b367")))
+	// overflow wraps around
+	assert.Equal(t, common.HexToAddress("0x1111000000000000000000000000000000001110"), ApplyL1ToL2Alias(common.HexToAddress("0xffffffffffffffffffffffffffffffffffffffff")))
+}
+
+func TestGetBatchCodecVersion(t *testing.T) {
+	chainCfg := &params.ChainConfig{BernoulliBlock: big.NewInt(10)}
+
+	// the batches follow the fork, except the genesis batch.
+	assert.Equal(t, encoding.CodecV0, GetBatchCodecVersion(chainCfg, 5, 9))
+	assert.Equal(t, encoding.CodecV1, GetBatchCodecVersion(chainCfg, 5, 10))
+	assert.Equal(t, encoding.CodecV0, GetBatchCodecVersion(chainCfg, 0, 10))
+}
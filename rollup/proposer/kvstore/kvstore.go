@@ -0,0 +1,262 @@
+// Package kvstore is a storage backend of the proposers on top of a key-value database, e.g. LevelDB,
+// for the deployments without the rollup database such as light verifier nodes. It only serves the proposers,
+// the relayers and the watchers are bound to the rollup database.
+package kvstore
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/scroll-tech/go-ethereum/ethdb"
+
+	"scroll-tech/common/types/encoding"
+
+	"scroll-tech/rollup/internal/orm"
+)
+
+var (
+	blockPrefix = []byte("b") // blockPrefix + number (uint64 big endian) -> JSON encoded block
+	chunkPrefix = []byte("c") // chunkPrefix + index (uint64 big endian) -> JSON encoded chunk record
+	batchPrefix = []byte("t") // batchPrefix + index (uint64 big endian) -> JSON encoded batch record
+
+	latestChunkKey = []byte("LatestChunk") // index of the latest chunk
+	latestBatchKey = []byte("LatestBatch") // index of the latest batch
+)
+
+// ErrNotFound is returned when the requested record doesn't exist.
+var ErrNotFound = errors.New("not found")
+
+// Store is the storage backend of the proposers backed by a key-value database.
+// The L1 messages are not tracked, so the forced inclusion timeout never seals a chunk.
+type Store struct {
+	db ethdb.KeyValueStore
+
+	// serializes the inserts, which derive the new records from the latest ones.
+	mu sync.Mutex
+}
+
+// New creates a new Store instance.
+func New(db ethdb.KeyValueStore) *Store {
+	return &Store{db: db}
+}
+
+// InsertL2Blocks persists the L2 blocks.
+func (s *Store) InsertL2Blocks(_ context.Context, blocks []*encoding.Block) error {
+	batch := s.db.NewBatch()
+	for _, block := range blocks {
+		value, err := json.Marshal(block)
+		if err != nil {
+			return fmt.Errorf("failed to marshal block: %w, number: %v", err, block.Header.Number)
+		}
+		if err := batch.Put(indexKey(blockPrefix, block.Header.Number.Uint64()), value); err != nil {
+			return err
+		}
+	}
+	return batch.Write()
+}
+
+// GetL2BlocksGEHeight returns at most limit blocks starting from height, in ascending order.
+func (s *Store) GetL2BlocksGEHeight(_ context.Context, height uint64, limit int) ([]*encoding.Block, error) {
+	it := s.db.NewIterator(blockPrefix, encodeIndex(height))
+	defer it.Release()
+
+	var blocks []*encoding.Block
+	for it.Next() && (limit <= 0 || len(blocks) < limit) {
+		var block encoding.Block
+		if err := json.Unmarshal(it.Value(), &block); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal block: %w", err)
+		}
+		blocks = append(blocks, &block)
+	}
+	return blocks, it.Error()
+}
+
+// GetL2BlocksInRange returns the blocks in the range [startBlockNumber, endBlockNumber], in ascending order.
+func (s *Store) GetL2BlocksInRange(_ context.Context, startBlockNumber uint64, endBlockNumber uint64) ([]*encoding.Block, error) {
+	if startBlockNumber > endBlockNumber {
+		return nil, fmt.Errorf("start block number should be less than or equal to end block number, start block: %v, end block: %v", startBlockNumber, endBlockNumber)
+	}
+
+	blocks := make([]*encoding.Block, 0, endBlockNumber-startBlockNumber+1)
+	for number := startBlockNumber; number <= endBlockNumber; number++ {
+		var block encoding.Block
+		if err := s.get(indexKey(blockPrefix, number), &block); err != nil {
+			return nil, fmt.Errorf("failed to get block: %w, number: %v", err, number)
+		}
+		blocks = append(blocks, &block)
+	}
+	return blocks, nil
+}
+
+// GetUnchunkedBlockHeight returns the height of the first block not included in any chunk.
+func (s *Store) GetUnchunkedBlockHeight(_ context.Context) (uint64, error) {
+	latestChunk, err := s.getLatestChunk()
+	if err != nil {
+		return 0, err
+	}
+	if latestChunk == nil {
+		// the genesis block is never chunked.
+		return 1, nil
+	}
+	return latestChunk.EndBlockNumber + 1, nil
+}
+
+// GetEnforcedL1MessagesByQueueIndices returns no message, the L1 messages are not tracked.
+func (s *Store) GetEnforcedL1MessagesByQueueIndices(_ context.Context, _ []uint64) ([]*orm.L1Message, error) {
+	return nil, nil
+}
+
+// InsertChunk persists the chunk following the latest one.
+func (s *Store) InsertChunk(_ context.Context, chunk *encoding.Chunk, codecVersion encoding.CodecVersion) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	parentChunk, err := s.getLatestChunk()
+	if err != nil {
+		return err
+	}
+	newChunk, err := orm.NewChunkRecord(chunk, codecVersion, parentChunk)
+	if err != nil {
+		return err
+	}
+
+	batch := s.db.NewBatch()
+	if err := s.put(batch, indexKey(chunkPrefix, newChunk.Index), newChunk); err != nil {
+		return err
+	}
+	if err := batch.Put(latestChunkKey, encodeIndex(newChunk.Index)); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// GetFirstUnbatchedChunkIndex returns the index of the first chunk not included in any batch.
+func (s *Store) GetFirstUnbatchedChunkIndex(ctx context.Context) (uint64, error) {
+	latestBatch, err := s.GetLatestBatch(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return latestBatch.EndChunkIndex + 1, nil
+}
+
+// GetChunksGEIndex returns at most limit chunks starting from index, in ascending order.
+func (s *Store) GetChunksGEIndex(_ context.Context, index uint64, limit int) ([]*orm.Chunk, error) {
+	it := s.db.NewIterator(chunkPrefix, encodeIndex(index))
+	defer it.Release()
+
+	var chunks []*orm.Chunk
+	for it.Next() && (limit <= 0 || len(chunks) < limit) {
+		var chunk orm.Chunk
+		if err := json.Unmarshal(it.Value(), &chunk); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal chunk: %w", err)
+		}
+		chunks = append(chunks, &chunk)
+	}
+	return chunks, it.Error()
+}
+
+// GetLatestBatch returns the latest persisted batch, or ErrNotFound if there is none.
+func (s *Store) GetLatestBatch(_ context.Context) (*orm.Batch, error) {
+	if ok, err := s.db.Has(latestBatchKey); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, fmt.Errorf("failed to get latest batch: %w", ErrNotFound)
+	}
+	index, err := s.db.Get(latestBatchKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var batch orm.Batch
+	if err := s.get(indexKey(batchPrefix, binary.BigEndian.Uint64(index)), &batch); err != nil {
+		return nil, fmt.Errorf("failed to get latest batch: %w", err)
+	}
+	return &batch, nil
+}
+
+// InsertBatch persists the batch and links its chunks to it atomically.
+func (s *Store) InsertBatch(ctx context.Context, batch *encoding.Batch, codecVersion encoding.CodecVersion) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var parentBatch *orm.Batch
+	if batch.Index > 0 {
+		parentBatch = &orm.Batch{}
+		if err := s.get(indexKey(batchPrefix, batch.Index-1), parentBatch); err != nil {
+			return fmt.Errorf("failed to get parent batch: %w, index: %v", err, batch.Index-1)
+		}
+	}
+	newBatch, err := orm.NewBatchRecord(batch, codecVersion, parentBatch)
+	if err != nil {
+		return err
+	}
+
+	dbBatch := s.db.NewBatch()
+	for index := newBatch.StartChunkIndex; index <= newBatch.EndChunkIndex; index++ {
+		var chunk orm.Chunk
+		if err := s.get(indexKey(chunkPrefix, index), &chunk); err != nil {
+			return fmt.Errorf("failed to get chunk: %w, index: %v", err, index)
+		}
+		chunk.BatchHash = newBatch.Hash
+		if err := s.put(dbBatch, indexKey(chunkPrefix, index), &chunk); err != nil {
+			return err
+		}
+	}
+	if err := s.put(dbBatch, indexKey(batchPrefix, newBatch.Index), newBatch); err != nil {
+		return err
+	}
+	if err := dbBatch.Put(latestBatchKey, encodeIndex(newBatch.Index)); err != nil {
+		return err
+	}
+	return dbBatch.Write()
+}
+
+// getLatestChunk returns the latest chunk, or nil if there is none.
+func (s *Store) getLatestChunk() (*orm.Chunk, error) {
+	if ok, err := s.db.Has(latestChunkKey); err != nil || !ok {
+		return nil, err
+	}
+	index, err := s.db.Get(latestChunkKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var chunk orm.Chunk
+	if err := s.get(indexKey(chunkPrefix, binary.BigEndian.Uint64(index)), &chunk); err != nil {
+		return nil, fmt.Errorf("failed to get latest chunk: %w", err)
+	}
+	return &chunk, nil
+}
+
+func (s *Store) get(key []byte, value interface{}) error {
+	if ok, err := s.db.Has(key); err != nil {
+		return err
+	} else if !ok {
+		return ErrNotFound
+	}
+	data, err := s.db.Get(key)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, value)
+}
+
+func (s *Store) put(batch ethdb.Batch, key []byte, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return batch.Put(key, data)
+}
+
+func encodeIndex(index uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, index)
+}
+
+func indexKey(prefix []byte, index uint64) []byte {
+	return append(append([]byte{}, prefix...), encodeIndex(index)...)
+}
@@ -0,0 +1,17 @@
+package kvstore_test
+
+import (
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/ethdb/memorydb"
+
+	"scroll-tech/rollup/proposer"
+	"scroll-tech/rollup/proposer/kvstore"
+	"scroll-tech/rollup/proposer/storetest"
+)
+
+func TestConformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) proposer.Store {
+		return kvstore.New(memorydb.New())
+	})
+}
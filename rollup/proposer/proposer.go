@@ -0,0 +1,117 @@
+// Package proposer exposes the chunk and batch proposers of the rollup-relayer as a library,
+// so that external programs (e.g. research tools, alternative node implementations) can reuse
+// the exact production packing logic with their own block source, persistence and clock.
+package proposer
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scroll-tech/go-ethereum/params"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types/encoding"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/controller/watcher"
+	"scroll-tech/rollup/internal/orm"
+)
+
+type (
+	// ChunkProposer proposes chunks based on available unchunked blocks.
+	ChunkProposer = watcher.ChunkProposer
+	// BatchProposer proposes batches based on available unbatched chunks.
+	BatchProposer = watcher.BatchProposer
+
+	// ChunkProposerConfig is the configuration of the ChunkProposer.
+	ChunkProposerConfig = config.ChunkProposerConfig
+	// BatchProposerConfig is the configuration of the BatchProposer.
+	BatchProposerConfig = config.BatchProposerConfig
+	// DAModeSelectionConfig is the configuration of the DA mode cost comparison of the BatchProposer.
+	DAModeSelectionConfig = config.DAModeSelectionConfig
+
+	// Clock provides the current time to the proposers.
+	Clock = watcher.Clock
+	// L2BlockSource provides the L2 blocks packed by the proposers.
+	L2BlockSource = watcher.L2BlockSource
+	// ChunkStore persists the chunks proposed by the ChunkProposer.
+	ChunkStore = watcher.ChunkStore
+	// BatchStore persists the batches proposed by the BatchProposer.
+	BatchStore = watcher.BatchStore
+	// BlobBaseFeeSource provides the latest L1 blob base fee, implemented by a BatchStore to enable the dynamic max chunk number.
+	BlobBaseFeeSource = watcher.BlobBaseFeeSource
+	// BlobBaseFeeHistorySource provides the trailing average L1 blob base fee, implemented by a BatchStore to enable
+	// the blob fee spike detection of the DA mode selection.
+	BlobBaseFeeHistorySource = watcher.BlobBaseFeeHistorySource
+	// EnforcedL1MessageSource provides the enforced L1 messages, implemented by a BatchStore to enable the batch forced inclusion timeout.
+	EnforcedL1MessageSource = watcher.EnforcedL1MessageSource
+	// StaleChunkStore is implemented by a ChunkStore able to release the stale chunks.
+	StaleChunkStore = watcher.StaleChunkStore
+	// PauseStore is implemented by a BatchStore able to persist the pause state of the BatchProposer.
+	PauseStore = watcher.PauseStore
+	// StateStore is implemented by a ChunkStore or a BatchStore able to persist the state snapshots of the proposers.
+	StateStore = watcher.StateStore
+
+	// ForceSealResult is the outcome of ChunkProposer.ForceSealChunks.
+	ForceSealResult = watcher.ForceSealResult
+	// ForceSealBatchResult is the outcome of BatchProposer.ForceSealBatches.
+	ForceSealBatchResult = watcher.ForceSealBatchResult
+	// ChunkUtilizationReport is the outcome of ChunkProposer.GetChunkUtilizationReport.
+	ChunkUtilizationReport = watcher.ChunkUtilizationReport
+	// BlockUtilization is the constraint utilization of a pending block.
+	BlockUtilization = watcher.BlockUtilization
+	// ChunkProfileState is the outcome of ChunkProposer.Profile.
+	ChunkProfileState = watcher.ChunkProfileState
+	// StaleChunkGCResult is the outcome of ChunkProposer.ReleaseStaleChunks.
+	StaleChunkGCResult = watcher.StaleChunkGCResult
+	// BatchSimulationResult is the outcome of BatchProposer.SimulateProposeBatch.
+	BatchSimulationResult = watcher.BatchSimulationResult
+	// ProposerPauseState is the outcome of BatchProposer.PauseState.
+	ProposerPauseState = watcher.ProposerPauseState
+
+	// L1Message is an L1 message as returned by ChunkStore.
+	L1Message = orm.L1Message
+	// Chunk is a persisted chunk as returned by BatchStore.
+	Chunk = orm.Chunk
+	// Batch is a persisted batch as returned by BatchStore.
+	Batch = orm.Batch
+	// ProposerPause is a persisted pause state as returned by PauseStore.
+	ProposerPause = orm.ProposerPause
+	// ProposerState is a persisted state snapshot as returned by StateStore.
+	ProposerState = orm.ProposerState
+)
+
+// ErrChunkCrossesForkBoundary is returned when building a batch from a chunk containing blocks of different fork regimes.
+var ErrChunkCrossesForkBoundary = watcher.ErrChunkCrossesForkBoundary
+
+// ErrBatchProposerPaused is returned when force sealing batches while the BatchProposer is paused.
+var ErrBatchProposerPaused = watcher.ErrBatchProposerPaused
+
+// ErrL1MessageQueueGap is returned when building a batch from chunks not continuing the L1 message queue.
+var ErrL1MessageQueueGap = watcher.ErrL1MessageQueueGap
+
+// Store is a storage backend of the proposers, serving the L2 blocks and persisting both the chunks and the batches.
+// The rollup database backend is created by NewDBStore, a key-value backend is provided by the kvstore package,
+// and the storetest package is the conformance suite of the backends.
+type Store interface {
+	L2BlockSource
+	ChunkStore
+	BatchStore
+	// InsertL2Blocks persists the L2 blocks.
+	InsertL2Blocks(ctx context.Context, blocks []*encoding.Block) error
+}
+
+// NewDBStore creates a new Store backed by the rollup database.
+func NewDBStore(db *gorm.DB) Store {
+	return watcher.NewDBStore(db)
+}
+
+// NewChunkProposer creates a new ChunkProposer instance. A nil reg leaves the metrics unregistered.
+func NewChunkProposer(ctx context.Context, cfg *ChunkProposerConfig, chainCfg *params.ChainConfig, blockSource L2BlockSource, chunkStore ChunkStore, clock Clock, reg prometheus.Registerer) *ChunkProposer {
+	return watcher.NewChunkProposerWithBackend(ctx, cfg, chainCfg, blockSource, chunkStore, clock, reg)
+}
+
+// NewBatchProposer creates a new BatchProposer instance. A nil reg leaves the metrics unregistered.
+func NewBatchProposer(ctx context.Context, cfg *BatchProposerConfig, chainCfg *params.ChainConfig, blockSource L2BlockSource, batchStore BatchStore, clock Clock, reg prometheus.Registerer) *BatchProposer {
+	return watcher.NewBatchProposerWithBackend(ctx, cfg, chainCfg, blockSource, batchStore, clock, reg)
+}
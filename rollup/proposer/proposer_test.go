@@ -0,0 +1,353 @@
+package proposer_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/types/encoding"
+
+	"scroll-tech/rollup/proposer"
+)
+
+type memoryClock struct {
+	now time.Time
+}
+
+func (c *memoryClock) Now() time.Time {
+	return c.now
+}
+
+type memoryStore struct {
+	blocks  []*encoding.Block
+	chunks  []*encoding.Chunk
+	batches []*encoding.Batch
+
+	// the codec versions of the chunks inserted by the chunk proposer.
+	codecVersions map[*encoding.Chunk]encoding.CodecVersion
+	// the codec versions of the batches inserted by the batch proposer.
+	batchCodecVersions []encoding.CodecVersion
+	pauses             map[string]*proposer.ProposerPause
+	states             map[string]*proposer.ProposerState
+}
+
+func (s *memoryStore) GetL2BlocksGEHeight(_ context.Context, height uint64, limit int) ([]*encoding.Block, error) {
+	var blocks []*encoding.Block
+	for _, block := range s.blocks {
+		if block.Header.Number.Uint64() >= height && (limit <= 0 || len(blocks) < limit) {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks, nil
+}
+
+func (s *memoryStore) GetL2BlocksInRange(_ context.Context, startBlockNumber uint64, endBlockNumber uint64) ([]*encoding.Block, error) {
+	var blocks []*encoding.Block
+	for _, block := range s.blocks {
+		if number := block.Header.Number.Uint64(); number >= startBlockNumber && number <= endBlockNumber {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks, nil
+}
+
+func (s *memoryStore) GetUnchunkedBlockHeight(_ context.Context) (uint64, error) {
+	if len(s.chunks) == 0 {
+		return 1, nil
+	}
+	lastChunk := s.chunks[len(s.chunks)-1]
+	return lastChunk.Blocks[len(lastChunk.Blocks)-1].Header.Number.Uint64() + 1, nil
+}
+
+func (s *memoryStore) GetEnforcedL1MessagesByQueueIndices(_ context.Context, _ []uint64) ([]*proposer.L1Message, error) {
+	return nil, nil
+}
+
+func (s *memoryStore) InsertChunk(_ context.Context, chunk *encoding.Chunk, codecVersion encoding.CodecVersion) error {
+	if s.codecVersions == nil {
+		s.codecVersions = make(map[*encoding.Chunk]encoding.CodecVersion)
+	}
+	s.codecVersions[chunk] = codecVersion
+	s.chunks = append(s.chunks, chunk)
+	return nil
+}
+
+func (s *memoryStore) GetUnbatchedChunks(ctx context.Context) ([]*proposer.Chunk, error) {
+	index, err := s.GetFirstUnbatchedChunkIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetChunksGEIndex(ctx, index, 0)
+}
+
+func (s *memoryStore) GetFirstUnbatchedChunkIndex(_ context.Context) (uint64, error) {
+	index := uint64(1)
+	for _, batch := range s.batches {
+		index += uint64(len(batch.Chunks))
+	}
+	return index, nil
+}
+
+func (s *memoryStore) GetChunksGEIndex(_ context.Context, index uint64, limit int) ([]*proposer.Chunk, error) {
+	var chunks []*proposer.Chunk
+	var totalL1MessagePoppedBefore uint64
+	for i, chunk := range s.chunks {
+		// index 0 is the genesis chunk
+		chunkIndex := uint64(i + 1)
+		numL1Messages := chunk.NumL1Messages(totalL1MessagePoppedBefore)
+		totalL1MessagePoppedBefore += numL1Messages
+		if chunkIndex < index || (limit > 0 && len(chunks) >= limit) {
+			continue
+		}
+		codecVersion := int16(-1)
+		if version, ok := s.codecVersions[chunk]; ok {
+			codecVersion = int16(version)
+		}
+		chunks = append(chunks, &proposer.Chunk{
+			Index:            chunkIndex,
+			Hash:             fmt.Sprintf("chunk-%d", chunkIndex),
+			StartBlockNumber: chunk.Blocks[0].Header.Number.Uint64(),
+			StartBlockTime:   chunk.Blocks[0].Header.Time,
+			EndBlockNumber:   chunk.Blocks[len(chunk.Blocks)-1].Header.Number.Uint64(),
+			CodecVersion:     codecVersion,
+
+			TotalL1MessagesPoppedBefore:  totalL1MessagePoppedBefore - numL1Messages,
+			TotalL1MessagesPoppedInChunk: numL1Messages,
+		})
+	}
+	return chunks, nil
+}
+
+func (s *memoryStore) GetLatestBatch(_ context.Context) (*proposer.Batch, error) {
+	// a zero batch header of the codec of the latest batch, codecv0 for the genesis batch
+	headerSize := 89
+	if n := len(s.batchCodecVersions); n > 0 && s.batchCodecVersions[n-1] == encoding.CodecV1 {
+		headerSize = 121
+	}
+	return &proposer.Batch{Index: uint64(len(s.batches)), BatchHeader: make([]byte, headerSize)}, nil
+}
+
+func (s *memoryStore) InsertBatch(_ context.Context, batch *encoding.Batch, codecVersion encoding.CodecVersion) error {
+	s.batches = append(s.batches, batch)
+	s.batchCodecVersions = append(s.batchCodecVersions, codecVersion)
+	return nil
+}
+
+func (s *memoryStore) GetProposerPause(_ context.Context, name string) (*proposer.ProposerPause, error) {
+	return s.pauses[name], nil
+}
+
+func (s *memoryStore) SetProposerPause(_ context.Context, name string, paused bool, reason string) error {
+	if s.pauses == nil {
+		s.pauses = make(map[string]*proposer.ProposerPause)
+	}
+	s.pauses[name] = &proposer.ProposerPause{Proposer: name, Paused: paused, Reason: reason, UpdatedAt: time.Now()}
+	return nil
+}
+
+func (s *memoryStore) GetProposerState(_ context.Context, name string) (*proposer.ProposerState, error) {
+	return s.states[name], nil
+}
+
+func (s *memoryStore) SetProposerState(_ context.Context, name string, state string) error {
+	if s.states == nil {
+		s.states = make(map[string]*proposer.ProposerState)
+	}
+	s.states[name] = &proposer.ProposerState{Proposer: name, State: state, UpdatedAt: time.Now()}
+	return nil
+}
+
+func newMemoryStore(t *testing.T, numBlocks int64) *memoryStore {
+	return newMemoryStoreFromTrace(t, "../testdata/blockTrace_02.json", numBlocks)
+}
+
+func newMemoryStoreFromTrace(t *testing.T, traceFile string, numBlocks int64) *memoryStore {
+	data, err := os.ReadFile(traceFile)
+	assert.NoError(t, err)
+
+	store := &memoryStore{}
+	for i := int64(1); i <= numBlocks; i++ {
+		block := &encoding.Block{}
+		assert.NoError(t, json.Unmarshal(data, block))
+		block.Header.Number = big.NewInt(i)
+		store.blocks = append(store.blocks, block)
+	}
+	return store
+}
+
+// newTestChunkProposer creates a chunk proposer on the store only sealing the chunks of 10 blocks or on the chunk
+// timeout, override adjusts the configuration if not nil.
+func newTestChunkProposer(store *memoryStore, clock proposer.Clock, chainCfg *params.ChainConfig, override func(cfg *proposer.ChunkProposerConfig)) *proposer.ChunkProposer {
+	cfg := &proposer.ChunkProposerConfig{
+		MaxBlockNumPerChunk:             10,
+		MaxTxNumPerChunk:                math.MaxUint64,
+		MaxL1CommitGasPerChunk:          math.MaxUint64,
+		MaxL1CommitCalldataSizePerChunk: math.MaxUint64,
+		MaxRowConsumptionPerChunk:       math.MaxUint64,
+		ChunkTimeoutSec:                 300,
+		GasCostIncreaseMultiplier:       1,
+	}
+	if override != nil {
+		override(cfg)
+	}
+	return proposer.NewChunkProposer(context.Background(), cfg, chainCfg, store, store, clock, nil)
+}
+
+// newTestBatchProposer creates a batch proposer reading the blocks of the store and persisting the batches in
+// batchStore, which only seals the batches of 10 chunks or on the batch timeout. override adjusts the configuration
+// if not nil.
+func newTestBatchProposer(store *memoryStore, batchStore proposer.BatchStore, clock proposer.Clock, override func(cfg *proposer.BatchProposerConfig)) *proposer.BatchProposer {
+	cfg := &proposer.BatchProposerConfig{
+		MaxChunkNumPerBatch:             10,
+		MaxL1CommitGasPerBatch:          math.MaxUint64,
+		MaxL1CommitCalldataSizePerBatch: math.MaxUint64,
+		BatchTimeoutSec:                 300,
+		GasCostIncreaseMultiplier:       1,
+	}
+	if override != nil {
+		override(cfg)
+	}
+	return proposer.NewBatchProposer(context.Background(), cfg, &params.ChainConfig{}, store, batchStore, clock, nil)
+}
+
+func TestChunkProposerWithMemoryBackend(t *testing.T) {
+	store := newMemoryStore(t, 3)
+
+	clock := &memoryClock{now: time.Unix(int64(store.blocks[0].Header.Time), 0)}
+	cp := newTestChunkProposer(store, clock, &params.ChainConfig{}, func(cfg *proposer.ChunkProposerConfig) {
+		cfg.MaxBlockNumPerChunk = 2
+	})
+
+	// the first chunk reaches the max number of blocks.
+	assert.True(t, cp.TryProposeChunk())
+	assert.Len(t, store.chunks, 1)
+	assert.Len(t, store.chunks[0].Blocks, 2)
+
+	// the remaining block does not reach any constraint yet.
+	assert.False(t, cp.TryProposeChunk())
+	assert.Len(t, store.chunks, 1)
+
+	// the remaining block reaches the chunk timeout once the clock advances.
+	clock.now = clock.now.Add(301 * time.Second)
+	assert.True(t, cp.TryProposeChunk())
+	assert.Len(t, store.chunks, 2)
+	assert.Equal(t, uint64(3), store.chunks[1].Blocks[0].Header.Number.Uint64())
+
+	assert.False(t, cp.TryProposeChunk())
+}
+
+// failingBatchStore is a memoryStore failing to insert the batches.
+type failingBatchStore struct {
+	*memoryStore
+}
+
+func (s *failingBatchStore) InsertBatch(_ context.Context, _ *encoding.Batch, _ encoding.CodecVersion) error {
+	return errors.New("insert batch failed")
+}
+
+func TestBatchProposerInsertBatchFailure(t *testing.T) {
+	store := newMemoryStore(t, 2)
+	store.chunks = []*encoding.Chunk{{Blocks: store.blocks}}
+
+	clock := &memoryClock{now: time.Unix(int64(store.blocks[0].Header.Time), 0)}
+	bp := newTestBatchProposer(store, &failingBatchStore{memoryStore: store}, clock, func(cfg *proposer.BatchProposerConfig) {
+		cfg.MaxChunkNumPerBatch = 1
+	})
+
+	// a batch which fails to be persisted isn't reported as proposed.
+	assert.False(t, bp.TryProposeBatch())
+	assert.Empty(t, store.batches)
+}
+
+func TestBatchProposerPause(t *testing.T) {
+	store := newMemoryStore(t, 6)
+	clock := &memoryClock{now: time.Unix(int64(store.blocks[0].Header.Time), 0)}
+	cp := newTestChunkProposer(store, clock, &params.ChainConfig{}, func(cfg *proposer.ChunkProposerConfig) {
+		cfg.MaxBlockNumPerChunk = 2
+	})
+	newBatchProposer := func() *proposer.BatchProposer {
+		return newTestBatchProposer(store, store, clock, func(cfg *proposer.BatchProposerConfig) {
+			cfg.MaxChunkNumPerBatch = 1
+		})
+	}
+	bp := newBatchProposer()
+
+	state, err := bp.PauseState(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, state.Paused)
+
+	assert.NoError(t, bp.Pause(context.Background(), "contract upgrade"))
+	state, err = bp.PauseState(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, state.Paused)
+	assert.Equal(t, "contract upgrade", state.Reason)
+
+	// the chunks are still proposed while the batches aren't.
+	assert.True(t, cp.TryProposeChunk())
+	assert.False(t, bp.TryProposeBatch())
+	_, err = bp.ForceSealBatches(context.Background())
+	assert.ErrorIs(t, err, proposer.ErrBatchProposerPaused)
+	assert.Empty(t, store.batches)
+
+	// the pause survives a restart.
+	bp = newBatchProposer()
+	assert.False(t, bp.TryProposeBatch())
+	assert.Empty(t, store.batches)
+
+	assert.NoError(t, bp.Resume(context.Background()))
+	assert.True(t, bp.TryProposeBatch())
+	assert.Len(t, store.batches, 1)
+}
+
+func TestProposerStateSnapshot(t *testing.T) {
+	store := newMemoryStoreFromTrace(t, "../testdata/blockTrace_03.json", 30)
+	clock := &memoryClock{now: time.Unix(int64(store.blocks[0].Header.Time), 0)}
+	newChunkProposer := func() *proposer.ChunkProposer {
+		return newTestChunkProposer(store, clock, &params.ChainConfig{BernoulliBlock: big.NewInt(0)}, func(cfg *proposer.ChunkProposerConfig) {
+			cfg.MaxChunksPerTick = 10
+		})
+	}
+
+	// nothing is restored before the first snapshot.
+	cp := newChunkProposer()
+	assert.NoError(t, cp.RestoreState())
+	for cp.TryProposeChunk() {
+	}
+	assert.Len(t, store.chunks, 3)
+
+	cp.SnapshotState()
+	var chunkState struct {
+		Version     uint64            `json:"version"`
+		Estimations []json.RawMessage `json:"estimations"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(store.states["chunk_proposer"].State), &chunkState))
+	assert.Equal(t, uint64(1), chunkState.Version)
+	assert.Len(t, chunkState.Estimations, 30)
+
+	restoredCp := newChunkProposer()
+	assert.NoError(t, restoredCp.RestoreState())
+	snapshot := store.states["chunk_proposer"].State
+	restoredCp.SnapshotState()
+	assert.Equal(t, snapshot, store.states["chunk_proposer"].State)
+
+	// a snapshot of another version is discarded.
+	chunkState.Version = 0
+	staleSnapshot, err := json.Marshal(&chunkState)
+	assert.NoError(t, err)
+	store.states["chunk_proposer"].State = string(staleSnapshot)
+	discardedCp := newChunkProposer()
+	assert.NoError(t, discardedCp.RestoreState())
+	discardedCp.SnapshotState()
+	assert.NoError(t, json.Unmarshal([]byte(store.states["chunk_proposer"].State), &chunkState))
+	assert.Equal(t, uint64(1), chunkState.Version)
+	assert.Empty(t, chunkState.Estimations)
+}
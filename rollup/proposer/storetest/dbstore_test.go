@@ -0,0 +1,32 @@
+package storetest_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"scroll-tech/common/testcontainers"
+	"scroll-tech/database/migrate"
+
+	"scroll-tech/rollup/proposer"
+	"scroll-tech/rollup/proposer/storetest"
+)
+
+func TestDBStoreConformance(t *testing.T) {
+	testApps := testcontainers.NewTestcontainerApps()
+	defer testApps.Free()
+	require.NoError(t, testApps.StartPostgresContainer())
+
+	db, err := testApps.GetGormDBClient()
+	require.NoError(t, err)
+	require.NotNil(t, db)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	require.NotNil(t, sqlDB)
+
+	storetest.Run(t, func(t *testing.T) proposer.Store {
+		assert.NoError(t, migrate.ResetDB(sqlDB))
+		return proposer.NewDBStore(db)
+	})
+}
@@ -0,0 +1,235 @@
+// Package storetest is the conformance suite of the proposer storage backends, run by the tests of every
+// backend so that the proposers behave the same whichever database a deployment uses.
+package storetest
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"scroll-tech/common/types/encoding"
+
+	"scroll-tech/rollup/proposer"
+)
+
+const genesisTime = 1700000000
+
+type fixedClock struct {
+	now time.Time
+}
+
+func (c *fixedClock) Now() time.Time {
+	return c.now
+}
+
+// Run runs the conformance suite against the stores created by newStore, every case gets an empty store.
+func Run(t *testing.T, newStore func(t *testing.T) proposer.Store) {
+	t.Run("L2Blocks", func(t *testing.T) { testL2Blocks(t, newStore(t)) })
+	t.Run("Chunks", func(t *testing.T) { testChunks(t, newStore(t)) })
+	t.Run("Batches", func(t *testing.T) { testBatches(t, newStore(t)) })
+	t.Run("Proposers", func(t *testing.T) { testProposers(t, newStore(t)) })
+}
+
+func newBlock(number uint64) *encoding.Block {
+	return &encoding.Block{
+		Header: &gethTypes.Header{
+			Number:     new(big.Int).SetUint64(number),
+			Difficulty: big.NewInt(0),
+			GasLimit:   10000000,
+			Time:       genesisTime + 3*number,
+			Root:       common.BigToHash(new(big.Int).SetUint64(number + 1)),
+		},
+		WithdrawRoot:   common.BigToHash(new(big.Int).SetUint64(number + 2)),
+		RowConsumption: &gethTypes.RowConsumption{},
+	}
+}
+
+func newBlocks(from, to uint64) []*encoding.Block {
+	var blocks []*encoding.Block
+	for number := from; number <= to; number++ {
+		blocks = append(blocks, newBlock(number))
+	}
+	return blocks
+}
+
+func blockNumbers(blocks []*encoding.Block) []uint64 {
+	numbers := make([]uint64, len(blocks))
+	for i, block := range blocks {
+		numbers[i] = block.Header.Number.Uint64()
+	}
+	return numbers
+}
+
+// insertGenesis persists the genesis block, chunk and batch as the rollup-relayer does on startup.
+func insertGenesis(t *testing.T, store proposer.Store) {
+	ctx := context.Background()
+	genesis := newBlock(0)
+	require.NoError(t, store.InsertL2Blocks(ctx, []*encoding.Block{genesis}))
+	chunk := &encoding.Chunk{Blocks: []*encoding.Block{genesis}}
+	require.NoError(t, store.InsertChunk(ctx, chunk, encoding.CodecV0))
+	require.NoError(t, store.InsertBatch(ctx, &encoding.Batch{Index: 0, Chunks: []*encoding.Chunk{chunk}}, encoding.CodecV0))
+}
+
+func testL2Blocks(t *testing.T, store proposer.Store) {
+	ctx := context.Background()
+	blocks := newBlocks(1, 5)
+	require.NoError(t, store.InsertL2Blocks(ctx, blocks))
+
+	got, err := store.GetL2BlocksGEHeight(ctx, 2, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{2, 3}, blockNumbers(got))
+
+	got, err = store.GetL2BlocksGEHeight(ctx, 4, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{4, 5}, blockNumbers(got))
+
+	got, err = store.GetL2BlocksGEHeight(ctx, 6, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+
+	got, err = store.GetL2BlocksInRange(ctx, 2, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{2, 3, 4}, blockNumbers(got))
+	for i, block := range got {
+		assert.Equal(t, blocks[i+1].Header.Hash(), block.Header.Hash())
+		assert.Equal(t, blocks[i+1].WithdrawRoot, block.WithdrawRoot)
+	}
+
+	// missing blocks and invalid ranges are errors.
+	_, err = store.GetL2BlocksInRange(ctx, 4, 6)
+	assert.Error(t, err)
+	_, err = store.GetL2BlocksInRange(ctx, 3, 2)
+	assert.Error(t, err)
+}
+
+func testChunks(t *testing.T, store proposer.Store) {
+	ctx := context.Background()
+	blocks := newBlocks(1, 3)
+	require.NoError(t, store.InsertL2Blocks(ctx, blocks))
+
+	height, err := store.GetUnchunkedBlockHeight(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), height)
+
+	require.NoError(t, store.InsertChunk(ctx, &encoding.Chunk{Blocks: blocks[0:2]}, encoding.CodecV0))
+	require.NoError(t, store.InsertChunk(ctx, &encoding.Chunk{Blocks: blocks[2:3]}, encoding.CodecV0))
+
+	height, err = store.GetUnchunkedBlockHeight(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4), height)
+
+	chunks, err := store.GetChunksGEIndex(ctx, 0, 0)
+	assert.NoError(t, err)
+	require.Len(t, chunks, 2)
+	assert.Equal(t, uint64(0), chunks[0].Index)
+	assert.Equal(t, uint64(1), chunks[0].StartBlockNumber)
+	assert.Equal(t, uint64(2), chunks[0].EndBlockNumber)
+	assert.Equal(t, blocks[0].Header.Time, chunks[0].StartBlockTime)
+	assert.Equal(t, uint64(1), chunks[1].Index)
+	assert.Equal(t, uint64(3), chunks[1].StartBlockNumber)
+	assert.Equal(t, uint64(3), chunks[1].EndBlockNumber)
+	assert.NotEmpty(t, chunks[0].Hash)
+	assert.Equal(t, chunks[0].Hash, chunks[1].ParentChunkHash)
+
+	chunks, err = store.GetChunksGEIndex(ctx, 1, 1)
+	assert.NoError(t, err)
+	require.Len(t, chunks, 1)
+	assert.Equal(t, uint64(1), chunks[0].Index)
+
+	messages, err := store.GetEnforcedL1MessagesByQueueIndices(ctx, []uint64{0, 1})
+	assert.NoError(t, err)
+	assert.Empty(t, messages)
+}
+
+func testBatches(t *testing.T, store proposer.Store) {
+	ctx := context.Background()
+
+	// there is no batch before the genesis.
+	_, err := store.GetLatestBatch(ctx)
+	assert.Error(t, err)
+
+	insertGenesis(t, store)
+	genesisBatch, err := store.GetLatestBatch(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), genesisBatch.Index)
+	assert.NotEmpty(t, genesisBatch.BatchHeader)
+
+	index, err := store.GetFirstUnbatchedChunkIndex(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), index)
+
+	blocks := newBlocks(1, 3)
+	require.NoError(t, store.InsertL2Blocks(ctx, blocks))
+	chunk1 := &encoding.Chunk{Blocks: blocks[0:2]}
+	require.NoError(t, store.InsertChunk(ctx, chunk1, encoding.CodecV0))
+	require.NoError(t, store.InsertChunk(ctx, &encoding.Chunk{Blocks: blocks[2:3]}, encoding.CodecV0))
+
+	batch := &encoding.Batch{Index: 1, ParentBatchHash: common.HexToHash(genesisBatch.Hash), Chunks: []*encoding.Chunk{chunk1}}
+	require.NoError(t, store.InsertBatch(ctx, batch, encoding.CodecV0))
+
+	latestBatch, err := store.GetLatestBatch(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), latestBatch.Index)
+	assert.Equal(t, genesisBatch.Hash, latestBatch.ParentBatchHash)
+	assert.Equal(t, uint64(1), latestBatch.StartChunkIndex)
+	assert.Equal(t, uint64(1), latestBatch.EndChunkIndex)
+
+	index, err = store.GetFirstUnbatchedChunkIndex(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), index)
+
+	// the chunks of the batch are linked to it.
+	chunks, err := store.GetChunksGEIndex(ctx, 1, 0)
+	assert.NoError(t, err)
+	require.Len(t, chunks, 2)
+	assert.Equal(t, latestBatch.Hash, chunks[0].BatchHash)
+	assert.Empty(t, chunks[1].BatchHash)
+}
+
+func testProposers(t *testing.T, store proposer.Store) {
+	ctx := context.Background()
+	insertGenesis(t, store)
+	require.NoError(t, store.InsertL2Blocks(ctx, newBlocks(1, 5)))
+
+	clock := &fixedClock{now: time.Unix(genesisTime, 0)}
+	cp := proposer.NewChunkProposer(ctx, &proposer.ChunkProposerConfig{
+		MaxBlockNumPerChunk:             2,
+		MaxTxNumPerChunk:                math.MaxUint64,
+		MaxL1CommitGasPerChunk:          math.MaxUint64,
+		MaxL1CommitCalldataSizePerChunk: math.MaxUint64,
+		MaxRowConsumptionPerChunk:       math.MaxUint64,
+		ChunkTimeoutSec:                 math.MaxUint32,
+		GasCostIncreaseMultiplier:       1,
+	}, &params.ChainConfig{}, store, store, clock, nil)
+	bp := proposer.NewBatchProposer(ctx, &proposer.BatchProposerConfig{
+		MaxChunkNumPerBatch:             2,
+		MaxL1CommitGasPerBatch:          math.MaxUint64,
+		MaxL1CommitCalldataSizePerBatch: math.MaxUint64,
+		BatchTimeoutSec:                 math.MaxUint32,
+		GasCostIncreaseMultiplier:       1,
+	}, &params.ChainConfig{}, store, store, clock, nil)
+
+	// the last block doesn't fill a chunk.
+	for cp.TryProposeChunk() {
+	}
+	height, err := store.GetUnchunkedBlockHeight(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), height)
+
+	assert.True(t, bp.TryProposeBatch())
+	assert.False(t, bp.TryProposeBatch())
+
+	latestBatch, err := store.GetLatestBatch(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), latestBatch.Index)
+	assert.Equal(t, uint64(1), latestBatch.StartChunkIndex)
+	assert.Equal(t, uint64(2), latestBatch.EndChunkIndex)
+}
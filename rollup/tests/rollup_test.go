@@ -65,7 +65,7 @@ func testCommitBatchAndFinalizeBatch(t *testing.T) {
 
 	// Create L1Watcher
 	l1Cfg := rollupApp.Config.L1Config
-	l1Watcher := watcher.NewL1WatcherClient(context.Background(), l1Client, 0, l1Cfg.Confirmations, l1Cfg.L1MessageQueueAddress, l1Cfg.ScrollChainContractAddress, db, nil)
+	l1Watcher := watcher.NewL1WatcherClient(context.Background(), l1Client, 0, l1Cfg.Confirmations, l1Cfg.L1MessageQueueAddress, l1Cfg.ScrollChainContractAddress, l1Cfg.L1ScrollMessengerAddress, db, nil)
 
 	// add some blocks to db
 	var blocks []*encoding.Block
@@ -194,7 +194,7 @@ func testCommitBatchAndFinalizeBatch4844(t *testing.T) {
 
 	// Create L1Watcher
 	l1Cfg := rollupApp.Config.L1Config
-	l1Watcher := watcher.NewL1WatcherClient(context.Background(), l1Client, 0, l1Cfg.Confirmations, l1Cfg.L1MessageQueueAddress, l1Cfg.ScrollChainContractAddress, db, nil)
+	l1Watcher := watcher.NewL1WatcherClient(context.Background(), l1Client, 0, l1Cfg.Confirmations, l1Cfg.L1MessageQueueAddress, l1Cfg.ScrollChainContractAddress, l1Cfg.L1ScrollMessengerAddress, db, nil)
 
 	// add some blocks to db
 	var blocks []*encoding.Block
@@ -323,7 +323,7 @@ func testCommitBatchAndFinalizeBatchBeforeAndPost4844(t *testing.T) {
 
 	// Create L1Watcher
 	l1Cfg := rollupApp.Config.L1Config
-	l1Watcher := watcher.NewL1WatcherClient(context.Background(), l1Client, 0, l1Cfg.Confirmations, l1Cfg.L1MessageQueueAddress, l1Cfg.ScrollChainContractAddress, db, nil)
+	l1Watcher := watcher.NewL1WatcherClient(context.Background(), l1Client, 0, l1Cfg.Confirmations, l1Cfg.L1MessageQueueAddress, l1Cfg.ScrollChainContractAddress, l1Cfg.L1ScrollMessengerAddress, db, nil)
 
 	// add some blocks to db
 	var blocks []*encoding.Block